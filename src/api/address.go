@@ -2,9 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
 )
 
 // VerifyAddressRequest is the request data for POST /api/v2/address/verify
@@ -60,3 +62,147 @@ func addressVerifyHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 }
+
+// MultisigAddressRequest is the request data for POST /api/v2/address/multisig
+type MultisigAddressRequest struct {
+	Required int      `json:"required"`
+	PubKeys  []string `json:"public_keys"`
+}
+
+// MultisigAddressResponse is returned by POST /api/v2/address/multisig
+type MultisigAddressResponse struct {
+	Address string `json:"address"`
+}
+
+// addressMultisigHandler creates an m-of-n multisig address from public keys
+// Method: POST
+// URI: /api/v2/address/multisig
+func addressMultisigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	var req MultisigAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if len(req.PubKeys) == 0 {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "public_keys is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	pubKeys, err := parsePubKeys(req.PubKeys)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	addr, err := cipher.MultisigAddressFromPubKeys(req.Required, pubKeys)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: MultisigAddressResponse{
+			Address: addr.String(),
+		},
+	})
+}
+
+// TimeLockAddressRequest is the request data for POST /api/v2/address/timelock
+type TimeLockAddressRequest struct {
+	Address string `json:"address"`
+	Kind    string `json:"kind"`
+	Value   uint64 `json:"value"`
+}
+
+// TimeLockAddressResponse is returned by POST /api/v2/address/timelock
+type TimeLockAddressResponse struct {
+	Address string `json:"address"`
+}
+
+// addressTimeLockHandler creates a time-locked address owned by an address, which can't be spent
+// before a block height or unix time
+// Method: POST
+// URI: /api/v2/address/timelock
+func addressTimeLockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	var req TimeLockAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if req.Address == "" {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "address is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	owner, err := cipher.DecodeBase58Address(req.Address)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("address is invalid: %v", err))
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	kind, err := wallet.ParseTimeLockKind(req.Kind)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	addr, err := cipher.TimeLockedAddress(owner, kind, req.Value)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: TimeLockAddressResponse{
+			Address: addr.String(),
+		},
+	})
+}
+
+// parsePubKeys parses hex encoded public keys
+func parsePubKeys(pks []string) ([]cipher.PubKey, error) {
+	pubKeys := make([]cipher.PubKey, len(pks))
+	for i, pk := range pks {
+		p, err := cipher.PubKeyFromHex(pk)
+		if err != nil {
+			return nil, fmt.Errorf("public key %q is invalid: %v", pk, err)
+		}
+		pubKeys[i] = p
+	}
+	return pubKeys, nil
+}
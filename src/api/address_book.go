@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletAddressLabelRequest is the request data for POST /api/v2/wallet/address/label
+type WalletAddressLabelRequest struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Label   string `json:"label"`
+}
+
+// WalletAddressBookEntryRequest is the request data for POST /api/v2/wallet/address-book/add
+// and POST /api/v2/wallet/address-book/remove
+type WalletAddressBookEntryRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Address and Note are ignored when removing an entry
+	Address string `json:"address,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// decodeWalletJSONRequest decodes the JSON body of a POST request to req.
+// Writes the error response and returns false if the request is invalid.
+func decodeWalletJSONRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return false
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return false
+	}
+
+	return true
+}
+
+// writeWalletResponse writes the WalletResponse of a wallet that was updated
+func writeWalletResponse(w http.ResponseWriter, wlt *wallet.Wallet, err error) {
+	if err != nil {
+		writeHTTPResponse(w, outputReservationsErrorResponse(err))
+		return
+	}
+
+	rlt, err := NewWalletResponse(wlt)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: rlt,
+	})
+}
+
+// URI: /api/v2/wallet/address/label
+// Method: POST
+// Args:
+//	id: wallet id
+//	address: an address of the wallet
+//	label: the label of the address, an empty label removes it
+// Sets the label of an address of a wallet
+func walletAddressLabelHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletAddressLabelRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Address == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "address is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wlt, err := gateway.UpdateWalletAddressLabel(req.ID, req.Address, req.Label)
+		writeWalletResponse(w, wlt, err)
+	}
+}
+
+// URI: /api/v2/wallet/address-book/add
+// Method: POST
+// Args:
+//	id: wallet id
+//	name: name of the recipient, must not be an address
+//	address: address of the recipient
+//	note: note about the recipient [optional]
+// Adds a recipient to the address book of a wallet, or replaces the recipient with the same name
+func walletAddressBookAddHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletAddressBookEntryRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Name == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "name is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Address == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "address is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wlt, err := gateway.AddWalletAddressBookEntry(req.ID, req.Name, req.Address, req.Note)
+		writeWalletResponse(w, wlt, err)
+	}
+}
+
+// URI: /api/v2/wallet/address-book/remove
+// Method: POST
+// Args:
+//	id: wallet id
+//	name: name of the recipient
+// Removes a recipient from the address book of a wallet
+func walletAddressBookRemoveHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletAddressBookEntryRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Name == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "name is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wlt, err := gateway.RemoveWalletAddressBookEntry(req.ID, req.Name)
+		writeWalletResponse(w, wlt, err)
+	}
+}
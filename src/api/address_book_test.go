@@ -0,0 +1,341 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletAddressLabel(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:      "foo",
+		Label:     "foo",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	addr := wlt.Entries[0].Address.String()
+	require.NoError(t, wlt.SetAddressLabel(wlt.Entries[0].Address, "savings"))
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletAddressLabelRequest
+		rawBody    string
+		wlt        *wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing id",
+			body:   &WalletAddressLabelRequest{},
+			status: http.StatusBadRequest,
+			err:    "id is required",
+		},
+		{
+			name: "400 - missing address",
+			body: &WalletAddressLabelRequest{
+				ID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "address is required",
+		},
+		{
+			name: "400 - unknown address",
+			body: &WalletAddressLabelRequest{
+				ID:      "foo.wlt",
+				Address: addr,
+				Label:   "savings",
+			},
+			gatewayErr: wallet.ErrUnknownAddress,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrUnknownAddress.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletAddressLabelRequest{
+				ID:      "foo.wlt",
+				Address: addr,
+				Label:   "savings",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletAddressLabelRequest{
+				ID:      "foo.wlt",
+				Address: addr,
+				Label:   "savings",
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletAddressLabelRequest{
+				ID:      "foo.wlt",
+				Address: addr,
+				Label:   "savings",
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200",
+			body: &WalletAddressLabelRequest{
+				ID:      "foo.wlt",
+				Address: addr,
+				Label:   "savings",
+			},
+			wlt:    wlt,
+			status: http.StatusOK,
+			data:   wr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("UpdateWalletAddressLabel", "foo.wlt", addr, "savings").Return(tc.wlt, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/address/label", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+			require.Equal(t, "savings", data.Entries[0].Label)
+		})
+	}
+}
+
+func TestWalletAddressBookAdd(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:      "foo",
+		Label:     "foo",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	alice := testutil.MakeAddress()
+	require.NoError(t, wlt.SetAddressBookEntry(wallet.AddressBookEntry{
+		Name:    "alice",
+		Address: alice,
+		Note:    "rent",
+	}))
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletAddressBookEntryRequest
+		rawBody    string
+		wlt        *wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing id",
+			body:   &WalletAddressBookEntryRequest{},
+			status: http.StatusBadRequest,
+			err:    "id is required",
+		},
+		{
+			name: "400 - missing name",
+			body: &WalletAddressBookEntryRequest{
+				ID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "name is required",
+		},
+		{
+			name: "400 - missing address",
+			body: &WalletAddressBookEntryRequest{
+				ID:   "foo.wlt",
+				Name: "alice",
+			},
+			status: http.StatusBadRequest,
+			err:    "address is required",
+		},
+		{
+			name: "400 - invalid name",
+			body: &WalletAddressBookEntryRequest{
+				ID:      "foo.wlt",
+				Name:    "alice",
+				Address: alice.String(),
+				Note:    "rent",
+			},
+			gatewayErr: wallet.ErrInvalidAddressBookName,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrInvalidAddressBookName.Error(),
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletAddressBookEntryRequest{
+				ID:      "foo.wlt",
+				Name:    "alice",
+				Address: alice.String(),
+				Note:    "rent",
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "200",
+			body: &WalletAddressBookEntryRequest{
+				ID:      "foo.wlt",
+				Name:    "alice",
+				Address: alice.String(),
+				Note:    "rent",
+			},
+			wlt:    wlt,
+			status: http.StatusOK,
+			data:   wr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("AddWalletAddressBookEntry", "foo.wlt", "alice", alice.String(), "rent").Return(tc.wlt, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/address-book/add", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+			require.Equal(t, []readable.AddressBookEntry{{
+				Name:    "alice",
+				Address: alice.String(),
+				Note:    "rent",
+			}}, data.AddressBook)
+		})
+	}
+}
+
+func TestWalletAddressBookRemove(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:      "foo",
+		Label:     "foo",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletAddressBookEntryRequest
+		wlt        *wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletResponse
+	}{
+		{
+			name: "400 - missing name",
+			body: &WalletAddressBookEntryRequest{
+				ID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "name is required",
+		},
+		{
+			name: "400 - entry not found",
+			body: &WalletAddressBookEntryRequest{
+				ID:   "foo.wlt",
+				Name: "alice",
+			},
+			gatewayErr: wallet.ErrAddressBookEntryNotExist,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrAddressBookEntryNotExist.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletAddressBookEntryRequest{
+				ID:   "foo.wlt",
+				Name: "alice",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "200",
+			body: &WalletAddressBookEntryRequest{
+				ID:   "foo.wlt",
+				Name: "alice",
+			},
+			wlt:    wlt,
+			status: http.StatusOK,
+			data:   wr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("RemoveWalletAddressBookEntry", "foo.wlt", "alice").Return(tc.wlt, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/address-book/remove", tc.body, "")
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+			require.Empty(t, data.AddressBook)
+		})
+	}
+}
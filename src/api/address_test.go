@@ -8,6 +8,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
 )
 
 func toJSON(t *testing.T, r interface{}) string {
@@ -146,3 +150,262 @@ func TestVerifyAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestMultisigAddress(t *testing.T) {
+	p1, _ := cipher.GenerateKeyPair()
+	p2, _ := cipher.GenerateKeyPair()
+	addr, err := cipher.MultisigAddressFromPubKeys(2, []cipher.PubKey{p1, p2})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		contentType  string
+		httpBody     string
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+
+		{
+			name:         "415 - Unsupported Media Type",
+			method:       http.MethodPost,
+			contentType:  ContentTypeForm,
+			status:       http.StatusUnsupportedMediaType,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnsupportedMediaType, ""),
+		},
+
+		{
+			name:         "400 - EOF",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "EOF"),
+		},
+
+		{
+			name:         "400 - Missing public keys",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "{}",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "public_keys is required"),
+		},
+
+		{
+			name:   "400 - Invalid public key",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, MultisigAddressRequest{
+				Required: 1,
+				PubKeys:  []string{p1.Hex(), "abcd"},
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, `public key "abcd" is invalid: Invalid public key length`),
+		},
+
+		{
+			name:   "400 - Required out of range",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, MultisigAddressRequest{
+				Required: 3,
+				PubKeys:  []string{p1.Hex(), p2.Hex()},
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, cipher.ErrMultisigInvalidRequired.Error()),
+		},
+
+		{
+			name:   "200",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			httpBody: toJSON(t, MultisigAddressRequest{
+				Required: 2,
+				PubKeys:  []string{p1.Hex(), p2.Hex()},
+			}),
+			httpResponse: HTTPResponse{
+				Data: MultisigAddressResponse{
+					Address: addr.String(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := "/api/v2/address/multisig"
+			gateway := &MockGatewayer{}
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = ContentTypeJSON
+			}
+
+			req.Header.Set("Content-Type", contentType)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.NewDecoder(rr.Body).Decode(&rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var addrRsp MultisigAddressResponse
+				err := json.Unmarshal(rsp.Data, &addrRsp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.httpResponse.Data.(MultisigAddressResponse), addrRsp)
+			}
+		})
+	}
+}
+
+func TestTimeLockAddress(t *testing.T) {
+	owner := testutil.MakeAddress()
+	addr, err := cipher.TimeLockedAddress(owner, cipher.TimeLockHeight, 1000)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		method       string
+		status       int
+		contentType  string
+		httpBody     string
+		httpResponse HTTPResponse
+	}{
+		{
+			name:         "405",
+			method:       http.MethodGet,
+			status:       http.StatusMethodNotAllowed,
+			httpResponse: NewHTTPErrorResponse(http.StatusMethodNotAllowed, ""),
+		},
+
+		{
+			name:         "415 - Unsupported Media Type",
+			method:       http.MethodPost,
+			contentType:  ContentTypeForm,
+			status:       http.StatusUnsupportedMediaType,
+			httpResponse: NewHTTPErrorResponse(http.StatusUnsupportedMediaType, ""),
+		},
+
+		{
+			name:         "400 - Missing address",
+			method:       http.MethodPost,
+			status:       http.StatusBadRequest,
+			httpBody:     "{}",
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address is required"),
+		},
+
+		{
+			name:   "400 - Invalid address",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, TimeLockAddressRequest{
+				Address: "foo",
+				Kind:    wallet.TimeLockKindHeight,
+				Value:   1000,
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "address is invalid: Invalid address length"),
+		},
+
+		{
+			name:   "400 - Invalid kind",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, TimeLockAddressRequest{
+				Address: owner.String(),
+				Kind:    "block",
+				Value:   1000,
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, `invalid time-lock kind "block", must be "height" or "time"`),
+		},
+
+		{
+			name:   "400 - Time-locked owner",
+			method: http.MethodPost,
+			status: http.StatusBadRequest,
+			httpBody: toJSON(t, TimeLockAddressRequest{
+				Address: addr.String(),
+				Kind:    wallet.TimeLockKindHeight,
+				Value:   1000,
+			}),
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, cipher.ErrTimeLockInvalidOwner.Error()),
+		},
+
+		{
+			name:   "200",
+			method: http.MethodPost,
+			status: http.StatusOK,
+			httpBody: toJSON(t, TimeLockAddressRequest{
+				Address: owner.String(),
+				Kind:    wallet.TimeLockKindHeight,
+				Value:   1000,
+			}),
+			httpResponse: HTTPResponse{
+				Data: TimeLockAddressResponse{
+					Address: addr.String(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := "/api/v2/address/timelock"
+			gateway := &MockGatewayer{}
+
+			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(tc.httpBody))
+			require.NoError(t, err)
+
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = ContentTypeJSON
+			}
+
+			req.Header.Set("Content-Type", contentType)
+			setCSRFParameters(t, tokenValid, req)
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			status := rr.Code
+			require.Equal(t, tc.status, status, "got `%v` want `%v`", status, tc.status)
+
+			var rsp ReceivedHTTPResponse
+			err = json.NewDecoder(rr.Body).Decode(&rsp)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.httpResponse.Error, rsp.Error)
+
+			if rsp.Data == nil {
+				require.Nil(t, tc.httpResponse.Data)
+			} else {
+				require.NotNil(t, tc.httpResponse.Data)
+
+				var addrRsp TimeLockAddressResponse
+				err := json.Unmarshal(rsp.Data, &addrRsp)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.httpResponse.Data.(TimeLockAddressResponse), addrRsp)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletsExportRequest is the request data for POST /api/v2/wallets/export
+type WalletsExportRequest struct {
+	IDs      []string `json:"ids,omitempty"`
+	Password string   `json:"password"`
+}
+
+// WalletsExportResponse is the response data for POST /api/v2/wallets/export
+type WalletsExportResponse struct {
+	Backup string `json:"backup"`
+}
+
+// WalletsImportRequest is the request data for POST /api/v2/wallets/import
+type WalletsImportRequest struct {
+	Backup   string `json:"backup"`
+	Password string `json:"password"`
+}
+
+// walletsBackupErrorResponse returns the error response of the wallet backup endpoints
+func walletsBackupErrorResponse(err error) HTTPResponse {
+	switch err {
+	case wallet.ErrSeedAPIDisabled:
+		return NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+	default:
+		return outputReservationsErrorResponse(err)
+	}
+}
+
+// URI: /api/v2/wallets/export
+// Method: POST
+// Args:
+//	ids: wallet ids [optional, all wallets are exported by default]
+//	password: password of the backup
+// Packages wallets into a single backup, authenticated and encrypted with the password.
+// Exporting unencrypted wallets requires the seed API, because their seeds can be read from the backup.
+func walletsExportHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletsExportRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		defer func() {
+			req.Password = ""
+		}()
+
+		if req.Password == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "password is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		backup, err := gateway.ExportWallets(req.IDs, []byte(req.Password))
+		if err != nil {
+			writeHTTPResponse(w, walletsBackupErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: WalletsExportResponse{
+				Backup: string(backup),
+			},
+		})
+	}
+}
+
+// URI: /api/v2/wallets/import
+// Method: POST
+// Args:
+//	backup: backup created by /api/v2/wallets/export
+//	password: password of the backup
+// Restores the wallets of a backup. Wallets whose filenames are in use are renamed.
+// No wallet is restored if the backup contains a wallet that is already loaded.
+func walletsImportHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletsImportRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		defer func() {
+			req.Password = ""
+		}()
+
+		if req.Backup == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "backup is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Password == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "password is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wlts, err := gateway.ImportWallets([]byte(req.Backup), []byte(req.Password))
+		if err != nil {
+			writeHTTPResponse(w, walletsBackupErrorResponse(err))
+			return
+		}
+
+		wrs := make([]WalletResponse, len(wlts))
+		for i, wlt := range wlts {
+			wr, err := NewWalletResponse(wlt)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			wrs[i] = *wr
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: wrs,
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletsExport(t *testing.T) {
+	tt := []struct {
+		name       string
+		body       *WalletsExportRequest
+		rawBody    string
+		ids        []string
+		backup     []byte
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletsExportResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing password",
+			body:   &WalletsExportRequest{},
+			status: http.StatusBadRequest,
+			err:    "password is required",
+		},
+		{
+			name: "403 - seed api disabled",
+			body: &WalletsExportRequest{
+				Password: "pwd",
+			},
+			gatewayErr: wallet.ErrSeedAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrSeedAPIDisabled.Error(),
+		},
+		{
+			name: "403 - wallet api disabled",
+			body: &WalletsExportRequest{
+				Password: "pwd",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletsExportRequest{
+				IDs:      []string{"foo.wlt"},
+				Password: "pwd",
+			},
+			ids:        []string{"foo.wlt"},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletsExportRequest{
+				Password: "pwd",
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200",
+			body: &WalletsExportRequest{
+				IDs:      []string{"foo.wlt"},
+				Password: "pwd",
+			},
+			ids:    []string{"foo.wlt"},
+			backup: []byte(`{"version":"0.1"}`),
+			status: http.StatusOK,
+			data: &WalletsExportResponse{
+				Backup: `{"version":"0.1"}`,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("ExportWallets", tc.ids, []byte("pwd")).Return(tc.backup, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallets/export", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletsExportResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
+
+func TestWalletsImport(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:      "foo",
+		Label:     "foo",
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, wlt.SetAddressLabel(wlt.Entries[1].Address, "savings"))
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletsImportRequest
+		rawBody    string
+		wlts       []*wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       []WalletResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing backup",
+			body:   &WalletsImportRequest{},
+			status: http.StatusBadRequest,
+			err:    "backup is required",
+		},
+		{
+			name: "400 - missing password",
+			body: &WalletsImportRequest{
+				Backup: "backup",
+			},
+			status: http.StatusBadRequest,
+			err:    "password is required",
+		},
+		{
+			name: "400 - invalid password",
+			body: &WalletsImportRequest{
+				Backup:   "backup",
+				Password: "pwd",
+			},
+			gatewayErr: wallet.ErrInvalidPassword,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrInvalidPassword.Error(),
+		},
+		{
+			name: "400 - invalid backup",
+			body: &WalletsImportRequest{
+				Backup:   "backup",
+				Password: "pwd",
+			},
+			gatewayErr: wallet.ErrInvalidBackup,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrInvalidBackup.Error(),
+		},
+		{
+			name: "403 - wallet api disabled",
+			body: &WalletsImportRequest{
+				Backup:   "backup",
+				Password: "pwd",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "200",
+			body: &WalletsImportRequest{
+				Backup:   "backup",
+				Password: "pwd",
+			},
+			wlts:   []*wallet.Wallet{wlt},
+			status: http.StatusOK,
+			data:   []WalletResponse{*wr},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("ImportWallets", []byte("backup"), []byte("pwd")).Return(tc.wlts, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallets/import", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+			require.Equal(t, "savings", data[0].Entries[1].Label)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletBatchTransactionResponse is returned by POST /api/v2/wallet/transaction/batch
+type WalletBatchTransactionResponse struct {
+	Transactions []CreateTransactionResponse `json:"transactions"`
+	// Receivers are the outputs paying each receiver of the request, in the same order
+	Receivers []BatchTransactionReceiver `json:"receivers"`
+}
+
+// BatchTransactionReceiver is the output paying a receiver of a batch, and the transaction that creates it
+type BatchTransactionReceiver struct {
+	TxID string `json:"txid"`
+	CreatedTransactionOutput
+}
+
+// NewWalletBatchTransactionResponse creates a WalletBatchTransactionResponse
+func NewWalletBatchTransactionResponse(b *visor.Batch) (*WalletBatchTransactionResponse, error) {
+	if len(b.Transactions) != len(b.Inputs) {
+		return nil, errors.New("len(b.Transactions) != len(b.Inputs)")
+	}
+
+	txns := make([]CreateTransactionResponse, len(b.Transactions))
+	for i := range b.Transactions {
+		txnResp, err := NewCreateTransactionResponse(&b.Transactions[i], b.Inputs[i])
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = *txnResp
+	}
+
+	// The receivers paid by a transaction are its first outputs
+	receivers := make([]BatchTransactionReceiver, len(b.Receivers))
+	var n int
+	for i, txnIdx := range b.Receivers {
+		if txnIdx < 0 || txnIdx >= len(b.Transactions) {
+			return nil, errors.New("batch receiver transaction index out of range")
+		}
+
+		if i > 0 && txnIdx != b.Receivers[i-1] {
+			n = 0
+		}
+
+		txn := &b.Transactions[txnIdx]
+		if n >= len(txn.Out) {
+			return nil, errors.New("batch transaction has fewer outputs than receivers")
+		}
+
+		txID := txn.Hash()
+		out, err := NewCreatedTransactionOutput(txn.Out[n], txID)
+		if err != nil {
+			return nil, err
+		}
+
+		receivers[i] = BatchTransactionReceiver{
+			TxID:                     txID.Hex(),
+			CreatedTransactionOutput: *out,
+		}
+		n++
+	}
+
+	return &WalletBatchTransactionResponse{
+		Transactions: txns,
+		Receivers:    receivers,
+	}, nil
+}
+
+// walletCreateBatchTransactionHandler creates transactions paying a list of receivers with a wallet.
+// The receivers are split into as few transactions as fit within the transaction size limit.
+// The transactions are not broadcast.
+// Method: POST
+// URI: /api/v2/wallet/transaction/batch
+// Args: JSON body, the same as POST /api/v1/wallet/transaction
+func walletCreateBatchTransactionHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req walletCreateTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var b *visor.Batch
+		var err error
+		if req.Unsigned {
+			b, err = gateway.WalletCreateBatchTransaction(req.WalletID, req.TransactionParams(), req.VisorParams())
+		} else {
+			b, err = gateway.WalletCreateBatchTransactionSigned(req.WalletID, []byte(req.Password), req.TransactionParams(), req.VisorParams())
+		}
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			case blockdb.ErrUnspentNotExist,
+				transaction.Error,
+				visor.UserError,
+				visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				switch err {
+				case fee.ErrTxnNoFee,
+					fee.ErrTxnInsufficientCoinHours:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				}
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		batchResp, err := NewWalletBatchTransactionResponse(b)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: batchResp,
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletCreateBatchTransaction(t *testing.T) {
+	txnAndInputs := prepareTxnAndInputs(t)
+	txn := txnAndInputs.txn
+
+	batch := &visor.Batch{
+		Transactions: []coin.Transaction{txn},
+		Inputs:       [][]visor.TransactionInput{txnAndInputs.inputs},
+		Receivers:    []int{0, 0},
+	}
+
+	batchResp, err := NewWalletBatchTransactionResponse(batch)
+	require.NoError(t, err)
+	require.Len(t, batchResp.Receivers, 2)
+	for i, r := range batchResp.Receivers {
+		require.Equal(t, txn.Hash().Hex(), r.TxID)
+		require.Equal(t, txn.Out[i].Address.String(), r.Address)
+	}
+
+	to := []coin.TransactionOutput{
+		{
+			Address: txn.Out[0].Address,
+			Coins:   txn.Out[0].Coins,
+			Hours:   txn.Out[0].Hours,
+		},
+		{
+			Address: txn.Out[1].Address,
+			Coins:   txn.Out[1].Coins,
+			Hours:   txn.Out[1].Hours,
+		},
+	}
+
+	receivers := []Receiver{
+		{
+			Address: to[0].Address.String(),
+			Coins:   "1",
+			Hours:   "50",
+		},
+		{
+			Address: to[1].Address.String(),
+			Coins:   "5",
+			Hours:   "50",
+		},
+	}
+
+	txnParams := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: to,
+	}
+
+	validBody := func(unsigned bool, password string) *WalletCreateTransactionRequest {
+		return &WalletCreateTransactionRequest{
+			WalletID: "foo.wlt",
+			Password: password,
+			Unsigned: unsigned,
+			CreateTransactionRequest: CreateTransactionRequest{
+				HoursSelection: HoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				},
+				To: receivers,
+			},
+		}
+	}
+
+	tt := []struct {
+		name          string
+		body          *WalletCreateTransactionRequest
+		rawBody       string
+		gatewayResult *visor.Batch
+		gatewayErr    error
+		status        int
+		err           string
+		data          *WalletBatchTransactionResponse
+	}{
+		{
+			name:    "400 - invalid json",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name: "400 - missing wallet_id",
+			body: &WalletCreateTransactionRequest{
+				CreateTransactionRequest: validBody(false, "").CreateTransactionRequest,
+			},
+			status: http.StatusBadRequest,
+			err:    "missing wallet_id",
+		},
+		{
+			name: "400 - to is empty",
+			body: &WalletCreateTransactionRequest{
+				WalletID: "foo.wlt",
+				CreateTransactionRequest: CreateTransactionRequest{
+					HoursSelection: HoursSelection{
+						Type: transaction.HoursSelectionTypeManual,
+					},
+				},
+			},
+			status: http.StatusBadRequest,
+			err:    "to is empty",
+		},
+		{
+			name: "400 - too many decimal places",
+			body: &WalletCreateTransactionRequest{
+				WalletID: "foo.wlt",
+				CreateTransactionRequest: CreateTransactionRequest{
+					HoursSelection: HoursSelection{
+						Type: transaction.HoursSelectionTypeManual,
+					},
+					To: []Receiver{
+						{
+							Address: testutil.MakeAddress().String(),
+							Coins:   "1.0001",
+							Hours:   "1",
+						},
+					},
+				},
+			},
+			status: http.StatusBadRequest,
+			err:    "to[0].coins has too many decimal places",
+		},
+		{
+			name:       "400 - insufficient balance",
+			body:       validBody(false, "pwd"),
+			gatewayErr: transaction.ErrInsufficientBalance,
+			status:     http.StatusBadRequest,
+			err:        transaction.ErrInsufficientBalance.Error(),
+		},
+		{
+			name:       "403 - wallet API disabled",
+			body:       validBody(false, "pwd"),
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        "wallet api is disabled",
+		},
+		{
+			name:       "404 - wallet not found",
+			body:       validBody(false, "pwd"),
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        "wallet doesn't exist",
+		},
+		{
+			name:       "500 - other error",
+			body:       validBody(false, "pwd"),
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name:          "200 - signed",
+			body:          validBody(false, "pwd"),
+			gatewayResult: batch,
+			status:        http.StatusOK,
+			data:          batchResp,
+		},
+		{
+			name:          "200 - unsigned",
+			body:          validBody(true, ""),
+			gatewayResult: batch,
+			status:        http.StatusOK,
+			data:          batchResp,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.body != nil {
+				if tc.body.Unsigned {
+					gateway.On("WalletCreateBatchTransaction", tc.body.WalletID, txnParams, visor.CreateTransactionParams{}).Return(tc.gatewayResult, tc.gatewayErr)
+				} else {
+					gateway.On("WalletCreateBatchTransactionSigned", tc.body.WalletID, []byte(tc.body.Password), txnParams, visor.CreateTransactionParams{}).Return(tc.gatewayResult, tc.gatewayErr)
+				}
+			}
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/transaction/batch", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletBatchTransactionResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
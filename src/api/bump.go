@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/coin"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// walletBumpTransactionRequest is sent to POST /api/v2/wallet/transaction/bump
+type walletBumpTransactionRequest struct {
+	WalletID string    `json:"wallet_id"`
+	Password string    `json:"password"`
+	Unsigned bool      `json:"unsigned"`
+	TxID     wh.SHA256 `json:"txid"`
+	Fee      wh.Hours  `json:"fee"`
+}
+
+// Validate validates walletBumpTransactionRequest data
+func (r walletBumpTransactionRequest) Validate() error {
+	if r.WalletID == "" {
+		return errors.New("missing wallet_id")
+	}
+
+	if r.Unsigned && len(r.Password) != 0 {
+		return errors.New("password must not be used for unsigned transactions")
+	}
+
+	if r.TxID.Null() {
+		return errors.New("txid is empty")
+	}
+
+	if r.Fee == 0 {
+		return errors.New("fee must be greater than 0")
+	}
+
+	return nil
+}
+
+// walletBumpTransactionHandler creates a transaction that replaces an unconfirmed transaction of a wallet.
+// The transaction spends the same inputs to the same outputs but burns more coin hours, which are taken from
+// the change outputs of the unconfirmed transaction. Once injected, it replaces the unconfirmed transaction.
+// The transaction is not broadcast.
+// Method: POST
+// URI: /api/v2/wallet/transaction/bump
+// Args: JSON body
+func walletBumpTransactionHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req walletBumpTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var txn *coin.Transaction
+		var inputs []visor.TransactionInput
+		var err error
+		if req.Unsigned {
+			txn, inputs, err = gateway.WalletBumpTransaction(req.WalletID, req.TxID.SHA256, uint64(req.Fee))
+		} else {
+			txn, inputs, err = gateway.WalletBumpTransactionSigned(req.WalletID, []byte(req.Password), req.TxID.SHA256, uint64(req.Fee))
+		}
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			case blockdb.ErrUnspentNotExist,
+				visor.UserError,
+				visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txnResp, err := NewCreateTransactionResponse(txn, inputs)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: txnResp,
+		})
+	}
+}
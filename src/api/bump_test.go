@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletBumpTransaction(t *testing.T) {
+	txnAndInputs := prepareTxnAndInputs(t)
+	txnResp, err := NewCreateTransactionResponse(&txnAndInputs.txn, txnAndInputs.inputs)
+	require.NoError(t, err)
+
+	txid := testutil.RandSHA256(t)
+
+	tt := []struct {
+		name       string
+		body       *WalletBumpTransactionRequest
+		rawBody    string
+		txid       cipher.SHA256
+		fee        uint64
+		gatewayErr error
+		status     int
+		err        string
+		data       *CreateTransactionResponse
+	}{
+		{
+			name:    "400 - invalid json",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:    "400 - invalid txid",
+			rawBody: `{"wallet_id": "foo.wlt", "txid": "foo", "fee": "10"}`,
+			status:  http.StatusBadRequest,
+			err:     "invalid SHA256 hash: encoding/hex: invalid byte: U+006F 'o'",
+		},
+		{
+			name:    "400 - invalid fee",
+			rawBody: `{"wallet_id": "foo.wlt", "txid": "` + txid.Hex() + `", "fee": "-1"}`,
+			status:  http.StatusBadRequest,
+			err:     `invalid hours value: strconv.ParseUint: parsing "-1": invalid syntax`,
+		},
+		{
+			name: "400 - missing wallet_id",
+			body: &WalletBumpTransactionRequest{
+				TxID: txid.Hex(),
+				Fee:  "10",
+			},
+			status: http.StatusBadRequest,
+			err:    "missing wallet_id",
+		},
+		{
+			name: "400 - password with unsigned",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				Password: "pwd",
+				Unsigned: true,
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			status: http.StatusBadRequest,
+			err:    "password must not be used for unsigned transactions",
+		},
+		{
+			name: "400 - empty txid",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     cipher.SHA256{}.Hex(),
+				Fee:      "10",
+			},
+			status: http.StatusBadRequest,
+			err:    "txid is empty",
+		},
+		{
+			name: "400 - zero fee",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "0",
+			},
+			status: http.StatusBadRequest,
+			err:    "fee must be greater than 0",
+		},
+		{
+			name: "400 - fee not increased",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			txid:       txid,
+			fee:        10,
+			gatewayErr: visor.NewUserError(errors.New("fee must be greater than the 20 coin hours burned by the transaction")),
+			status:     http.StatusBadRequest,
+			err:        "fee must be greater than the 20 coin hours burned by the transaction",
+		},
+		{
+			name: "400 - unknown uxout",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			txid:       txid,
+			fee:        10,
+			gatewayErr: wallet.ErrUnknownUxOut,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrUnknownUxOut.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			txid:       txid,
+			fee:        10,
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        "wallet api is disabled",
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			txid:       txid,
+			fee:        10,
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        "wallet doesn't exist",
+		},
+		{
+			name: "500 - other error",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				TxID:     txid.Hex(),
+				Fee:      "10",
+			},
+			txid:       txid,
+			fee:        10,
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200 - signed",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				Password: "pwd",
+				TxID:     txid.Hex(),
+				Fee:      "30",
+			},
+			txid:   txid,
+			fee:    30,
+			status: http.StatusOK,
+			data:   txnResp,
+		},
+		{
+			name: "200 - unsigned",
+			body: &WalletBumpTransactionRequest{
+				WalletID: "foo.wlt",
+				Unsigned: true,
+				TxID:     txid.Hex(),
+				Fee:      "30",
+			},
+			txid:   txid,
+			fee:    30,
+			status: http.StatusOK,
+			data:   txnResp,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.body != nil {
+				if tc.gatewayErr != nil {
+					if tc.body.Unsigned {
+						gateway.On("WalletBumpTransaction", tc.body.WalletID, tc.txid, tc.fee).Return(nil, nil, tc.gatewayErr)
+					} else {
+						gateway.On("WalletBumpTransactionSigned", tc.body.WalletID, []byte(tc.body.Password), tc.txid, tc.fee).Return(nil, nil, tc.gatewayErr)
+					}
+				} else {
+					if tc.body.Unsigned {
+						gateway.On("WalletBumpTransaction", tc.body.WalletID, tc.txid, tc.fee).Return(&txnAndInputs.txn, txnAndInputs.inputs, nil)
+					} else {
+						gateway.On("WalletBumpTransactionSigned", tc.body.WalletID, []byte(tc.body.Password), tc.txid, tc.fee).Return(&txnAndInputs.txn, txnAndInputs.inputs, nil)
+					}
+				}
+			}
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/transaction/bump", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data CreateTransactionResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletChangeAddressPolicyRequest is the request data for POST /api/v2/wallet/change-address-policy
+type WalletChangeAddressPolicyRequest struct {
+	ID     string `json:"id"`
+	Policy string `json:"policy"`
+}
+
+// URI: /api/v2/wallet/change-address-policy
+// Method: POST
+// Args:
+//	id: wallet id
+//	policy: "reuse" or "fresh"
+// Sets the change address policy of a wallet.
+// With the "fresh" policy, the change of the transactions created from the wallet
+// without a change address is sent to a new address generated from the wallet.
+// With the "reuse" policy, the change is sent back to one of the input addresses.
+func walletChangeAddressPolicyHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletChangeAddressPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Policy == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "policy is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wlt, err := gateway.UpdateWalletChangeAddressPolicy(req.ID, req.Policy)
+		if err != nil {
+			var resp HTTPResponse
+			switch err {
+			case wallet.ErrInvalidChangeAddressPolicy, wallet.ErrCannotGenerateAddresses:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			case wallet.ErrWalletNotExist:
+				resp = NewHTTPErrorResponse(http.StatusNotFound, "")
+			case wallet.ErrWalletAPIDisabled:
+				resp = NewHTTPErrorResponse(http.StatusForbidden, "")
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		rlt, err := NewWalletResponse(wlt)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: rlt,
+		})
+	}
+}
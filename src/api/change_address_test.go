@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletChangeAddressPolicy(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:                "foo",
+		Label:               "foo",
+		GenerateN:           1,
+		ChangeAddressPolicy: wallet.ChangeAddressPolicyFresh,
+	})
+	require.NoError(t, err)
+	_, err = wlt.GenerateChangeAddress()
+	require.NoError(t, err)
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletChangeAddressPolicyRequest
+		rawBody    string
+		wlt        *wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing id",
+			body:   &WalletChangeAddressPolicyRequest{},
+			status: http.StatusBadRequest,
+			err:    "id is required",
+		},
+		{
+			name: "400 - missing policy",
+			body: &WalletChangeAddressPolicyRequest{
+				ID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "policy is required",
+		},
+		{
+			name: "400 - invalid policy",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			gatewayErr: wallet.ErrInvalidChangeAddressPolicy,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrInvalidChangeAddressPolicy.Error(),
+		},
+		{
+			name: "400 - address list wallet",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			gatewayErr: wallet.ErrCannotGenerateAddresses,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrCannotGenerateAddresses.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        "Forbidden",
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        "Not Found",
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200",
+			body: &WalletChangeAddressPolicyRequest{
+				ID:     "foo.wlt",
+				Policy: "fresh",
+			},
+			wlt:    wlt,
+			status: http.StatusOK,
+			data:   wr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("UpdateWalletChangeAddressPolicy", "foo.wlt", "fresh").Return(tc.wlt, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/change-address-policy", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+			require.Equal(t, wallet.ChangeAddressPolicyFresh, data.Meta.ChangeAddressPolicy)
+
+			// The change address of the deterministic wallet is marked
+			require.Len(t, data.Entries, 2)
+			require.Nil(t, data.Entries[0].Change)
+			require.NotNil(t, data.Entries[1].Change)
+			require.Equal(t, uint32(1), *data.Entries[1].Change)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletChangePasswordRequest is the request data for POST /api/v2/wallet/password
+type WalletChangePasswordRequest struct {
+	ID          string `json:"id"`
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+	CryptoType  string `json:"crypto_type,omitempty"`
+}
+
+// URI: /api/v2/wallet/password
+// Method: POST
+// Args:
+//	id: wallet id
+//	password: current wallet password
+//	new_password: new wallet password
+//	crypto_type: new crypto type [optional, the crypto type of the wallet is kept by default]
+// Changes the password of an encrypted wallet, and optionally its crypto type.
+// The secrets are re-encrypted in memory, the wallet is never decrypted on disk.
+// The wallet file is replaced atomically, and the previous file is kept as a backup.
+func walletChangePasswordHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WalletChangePasswordRequest
+		if !decodeWalletJSONRequest(w, r, &req) {
+			return
+		}
+
+		defer func() {
+			req.Password = ""
+			req.NewPassword = ""
+		}()
+
+		if req.ID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.Password == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "password is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.NewPassword == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "new_password is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var cryptoType wallet.CryptoType
+		if req.CryptoType != "" {
+			var err error
+			cryptoType, err = wallet.CryptoTypeFromString(req.CryptoType)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "invalid crypto_type")
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		wlt, err := gateway.ChangeWalletPassword(req.ID, []byte(req.Password), []byte(req.NewPassword), cryptoType)
+		writeWalletResponse(w, wlt, err)
+	}
+}
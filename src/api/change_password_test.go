@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletChangePassword(t *testing.T) {
+	wlt, err := wallet.NewWallet("foo.wlt", wallet.Options{
+		Seed:       "foo",
+		Label:      "foo",
+		GenerateN:  1,
+		Encrypt:    true,
+		Password:   []byte("new pwd"),
+		CryptoType: wallet.CryptoTypeScryptChacha20poly1305Insecure,
+	})
+	require.NoError(t, err)
+
+	wr, err := NewWalletResponse(wlt)
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		body       *WalletChangePasswordRequest
+		rawBody    string
+		cryptoType wallet.CryptoType
+		wlt        *wallet.Wallet
+		gatewayErr error
+		status     int
+		err        string
+		data       *WalletResponse
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing id",
+			body:   &WalletChangePasswordRequest{},
+			status: http.StatusBadRequest,
+			err:    "id is required",
+		},
+		{
+			name: "400 - missing password",
+			body: &WalletChangePasswordRequest{
+				ID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "password is required",
+		},
+		{
+			name: "400 - missing new password",
+			body: &WalletChangePasswordRequest{
+				ID:       "foo.wlt",
+				Password: "pwd",
+			},
+			status: http.StatusBadRequest,
+			err:    "new_password is required",
+		},
+		{
+			name: "400 - invalid crypto type",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+				CryptoType:  "foo",
+			},
+			status: http.StatusBadRequest,
+			err:    "invalid crypto_type",
+		},
+		{
+			name: "400 - invalid password",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			gatewayErr: wallet.ErrInvalidPassword,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrInvalidPassword.Error(),
+		},
+		{
+			name: "400 - wallet not encrypted",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			gatewayErr: wallet.ErrWalletNotEncrypted,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrWalletNotEncrypted.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+			},
+			wlt:    wlt,
+			status: http.StatusOK,
+			data:   wr,
+		},
+		{
+			name: "200 - new crypto type",
+			body: &WalletChangePasswordRequest{
+				ID:          "foo.wlt",
+				Password:    "pwd",
+				NewPassword: "new pwd",
+				CryptoType:  string(wallet.CryptoTypeScryptChacha20poly1305Insecure),
+			},
+			cryptoType: wallet.CryptoTypeScryptChacha20poly1305Insecure,
+			wlt:        wlt,
+			status:     http.StatusOK,
+			data:       wr,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("ChangeWalletPassword", "foo.wlt", []byte("pwd"), []byte("new pwd"), tc.cryptoType).Return(tc.wlt, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/password", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+			require.True(t, data.Meta.Encrypted)
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/daemon"
 	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/wallet"
 )
 
 const (
@@ -234,6 +235,29 @@ func (c *Client) PostJSONV2(endpoint string, reqObj, respObj interface{}) (bool,
 	req.Header.Set("Content-Type", ContentTypeJSON)
 	req.Header.Set("Accept", ContentTypeJSON)
 
+	return c.doV2(req, respObj)
+}
+
+// GetV2 makes a GET request to an endpoint and parses the standard JSON response.
+func (c *Client) GetV2(endpoint string, respObj interface{}) (bool, error) {
+	endpoint = strings.TrimLeft(endpoint, "/")
+	endpoint = c.Addr + endpoint
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	c.applyAuth(req)
+
+	req.Header.Set("Accept", ContentTypeJSON)
+
+	return c.doV2(req, respObj)
+}
+
+// doV2 makes a request and parses the standard JSON response into respObj.
+// Returns true if respObj was populated.
+func (c *Client) doV2(req *http.Request, respObj interface{}) (bool, error) {
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return false, err
@@ -256,7 +280,7 @@ func (c *Client) PostJSONV2(endpoint string, reqObj, respObj interface{}) (bool,
 		// occurs in the go HTTP stack, outside of the application's control.
 		// If this happens, treat the entire response body as the error message.
 		if resp.StatusCode != http.StatusOK {
-			return false, NewClientError(resp.Status, resp.StatusCode, string(body))
+			return false, NewClientError(resp.Status, resp.StatusCode, string(respBody))
 		}
 
 		return false, err
@@ -643,6 +667,68 @@ func (c *Client) CreateEncryptedWallet(seed, label, password string, scanN int)
 	return &w, nil
 }
 
+// CreateWalletOptions are the options for creating a wallet
+type CreateWalletOptions struct {
+	Type      string
+	Seed      string
+	XPub      string
+	Addresses []string
+	Label     string
+	Password  string
+	ScanN     int
+
+	ChangeAddressPolicy string
+	SeedPassphrase      string
+}
+
+// CreateWallet makes a request to POST /api/v1/wallet/create and creates
+// a wallet of the given type. The wallet is encrypted if a password is provided.
+// Watch-only wallets are created from XPub or Addresses instead of Seed.
+// If ScanN is <= 0, the scan number defaults to 1
+func (c *Client) CreateWallet(o CreateWalletOptions) (*WalletResponse, error) {
+	v := url.Values{}
+	v.Add("label", o.Label)
+
+	if o.Seed != "" {
+		v.Add("seed", o.Seed)
+	}
+
+	if o.XPub != "" {
+		v.Add("xpub", o.XPub)
+	}
+
+	if len(o.Addresses) != 0 {
+		v.Add("addresses", strings.Join(o.Addresses, ","))
+	}
+
+	if o.Type != "" {
+		v.Add("type", o.Type)
+	}
+
+	if o.Password != "" {
+		v.Add("encrypt", "true")
+		v.Add("password", o.Password)
+	}
+
+	if o.ScanN > 0 {
+		v.Add("scan", fmt.Sprint(o.ScanN))
+	}
+
+	if o.ChangeAddressPolicy != "" {
+		v.Add("change-address-policy", o.ChangeAddressPolicy)
+	}
+
+	if o.SeedPassphrase != "" {
+		v.Add("seed-passphrase", o.SeedPassphrase)
+	}
+
+	var w WalletResponse
+	if err := c.PostForm("/api/v1/wallet/create", strings.NewReader(v.Encode()), &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
 // NewWalletAddress makes a request to POST /api/v1/wallet/newAddress
 // if n is <= 0, defaults to 1
 func (c *Client) NewWalletAddress(id string, n int, password string) ([]string, error) {
@@ -684,6 +770,7 @@ type CreateTransactionRequest struct {
 	To                []Receiver     `json:"to"`
 	UxOuts            []string       `json:"unspents,omitempty"`
 	Addresses         []string       `json:"addresses,omitempty"`
+	CoinSelection     string         `json:"coin_selection,omitempty"`
 }
 
 // HoursSelection defines options for hours distribution
@@ -705,6 +792,7 @@ type WalletCreateTransactionRequest struct {
 	Unsigned bool   `json:"unsigned"`
 	WalletID string `json:"wallet_id"`
 	Password string `json:"password"`
+	Reserve  bool   `json:"reserve"`
 	CreateTransactionRequest
 }
 
@@ -719,6 +807,149 @@ func (c *Client) WalletCreateTransaction(req WalletCreateTransactionRequest) (*C
 	return &r, nil
 }
 
+// WalletConsolidateRequest is sent to /api/v2/wallet/consolidate
+type WalletConsolidateRequest struct {
+	WalletID          string   `json:"wallet_id"`
+	Password          string   `json:"password"`
+	Unsigned          bool     `json:"unsigned"`
+	IgnoreUnconfirmed bool     `json:"ignore_unconfirmed"`
+	Reserve           bool     `json:"reserve"`
+	To                string   `json:"to"`
+	MaxInputs         int      `json:"max_inputs,omitempty"`
+	UxOuts            []string `json:"unspents,omitempty"`
+	Addresses         []string `json:"addresses,omitempty"`
+}
+
+// WalletConsolidate makes a request to POST /api/v2/wallet/consolidate
+func (c *Client) WalletConsolidate(req WalletConsolidateRequest) (*WalletConsolidateResponse, error) {
+	var r WalletConsolidateResponse
+	endpoint := "/api/v2/wallet/consolidate"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// WalletOutputReservations makes a request to GET /api/v2/wallet/reservations
+func (c *Client) WalletOutputReservations(id string) ([]OutputReservation, error) {
+	v := url.Values{}
+	v.Add("id", id)
+	endpoint := "/api/v2/wallet/reservations?" + v.Encode()
+
+	var r []OutputReservation
+	ok, err := c.GetV2(endpoint, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// WalletReleaseOutputReservations makes a request to POST /api/v2/wallet/reservations/release.
+// If uxOuts is empty, all the reservations of the wallet are released.
+func (c *Client) WalletReleaseOutputReservations(id string, uxOuts []string) ([]OutputReservation, error) {
+	req := struct {
+		WalletID string   `json:"wallet_id"`
+		UxOuts   []string `json:"unspents,omitempty"`
+	}{
+		WalletID: id,
+		UxOuts:   uxOuts,
+	}
+
+	var r []OutputReservation
+	endpoint := "/api/v2/wallet/reservations/release"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// WalletFrozenOutputs makes a request to GET /api/v2/wallet/frozen
+func (c *Client) WalletFrozenOutputs(id string) ([]FrozenOutput, error) {
+	v := url.Values{}
+	v.Add("id", id)
+	endpoint := "/api/v2/wallet/frozen?" + v.Encode()
+
+	var r []FrozenOutput
+	ok, err := c.GetV2(endpoint, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// WalletFreezeOutputs makes a request to POST /api/v2/wallet/frozen/freeze
+func (c *Client) WalletFreezeOutputs(id string, uxOuts []string, reason string) ([]FrozenOutput, error) {
+	req := struct {
+		WalletID string   `json:"wallet_id"`
+		UxOuts   []string `json:"unspents"`
+		Reason   string   `json:"reason,omitempty"`
+	}{
+		WalletID: id,
+		UxOuts:   uxOuts,
+		Reason:   reason,
+	}
+
+	var r []FrozenOutput
+	endpoint := "/api/v2/wallet/frozen/freeze"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// WalletUnfreezeOutputs makes a request to POST /api/v2/wallet/frozen/unfreeze
+func (c *Client) WalletUnfreezeOutputs(id string, uxOuts []string) ([]FrozenOutput, error) {
+	req := struct {
+		WalletID string   `json:"wallet_id"`
+		UxOuts   []string `json:"unspents"`
+	}{
+		WalletID: id,
+		UxOuts:   uxOuts,
+	}
+
+	var r []FrozenOutput
+	endpoint := "/api/v2/wallet/frozen/unfreeze"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// WalletCreateBatchTransaction makes a request to POST /api/v2/wallet/transaction/batch
+func (c *Client) WalletCreateBatchTransaction(req WalletCreateTransactionRequest) (*WalletBatchTransactionResponse, error) {
+	var r WalletBatchTransactionResponse
+	endpoint := "/api/v2/wallet/transaction/batch"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// WalletBumpTransactionRequest is sent to /api/v2/wallet/transaction/bump
+type WalletBumpTransactionRequest struct {
+	WalletID string `json:"wallet_id"`
+	Password string `json:"password"`
+	Unsigned bool   `json:"unsigned"`
+	TxID     string `json:"txid"`
+	Fee      string `json:"fee"`
+}
+
+// WalletBumpTransaction makes a request to POST /api/v2/wallet/transaction/bump
+func (c *Client) WalletBumpTransaction(req WalletBumpTransactionRequest) (*CreateTransactionResponse, error) {
+	var r CreateTransactionResponse
+	endpoint := "/api/v2/wallet/transaction/bump"
+	ok, err := c.PostJSONV2(endpoint, req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
 // WalletSignTransaction makes a request to POST /api/v2/wallet/transaction/sign
 func (c *Client) WalletSignTransaction(req WalletSignTransactionRequest) (*CreateTransactionResponse, error) {
 	var r CreateTransactionResponse
@@ -919,6 +1150,64 @@ func (c *Client) PendingTransactionsVerbose() ([]readable.UnconfirmedTransaction
 	return v, nil
 }
 
+// PendingTransactionsEvicted makes a request to GET /api/v1/pendingTxs?evicted=1
+func (c *Client) PendingTransactionsEvicted() ([]readable.EvictedTransaction, error) {
+	var v []readable.EvictedTransaction
+	if err := c.Get("/api/v1/pendingTxs?evicted=1", &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// PendingTransactionsExpired makes a request to GET /api/v1/pendingTxs?expired=1
+func (c *Client) PendingTransactionsExpired() ([]readable.ExpiredTransaction, error) {
+	var v []readable.ExpiredTransaction
+	if err := c.Get("/api/v1/pendingTxs?expired=1", &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RebroadcastTransactions makes a request to GET /api/v2/transaction/rebroadcast
+func (c *Client) RebroadcastTransactions() ([]RebroadcastTransaction, error) {
+	var r []RebroadcastTransaction
+	ok, err := c.GetV2("/api/v2/transaction/rebroadcast", &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
+// TransactionLifecycle makes a request to GET /api/v2/transaction/lifecycle
+func (c *Client) TransactionLifecycle(txid string) (*TransactionLifecycle, error) {
+	v := url.Values{}
+	v.Add("txid", txid)
+	endpoint := "/api/v2/transaction/lifecycle?" + v.Encode()
+
+	var r TransactionLifecycle
+	ok, err := c.GetV2(endpoint, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// TransactionLifecycles makes a request to GET /api/v2/transaction/lifecycles
+func (c *Client) TransactionLifecycles(walletID string) ([]TransactionLifecycle, error) {
+	v := url.Values{}
+	if walletID != "" {
+		v.Add("wallet_id", walletID)
+	}
+	endpoint := "/api/v2/transaction/lifecycles?" + v.Encode()
+
+	var r []TransactionLifecycle
+	ok, err := c.GetV2(endpoint, &r)
+	if ok {
+		return r, err
+	}
+	return nil, err
+}
+
 // Transaction makes a request to GET /api/v1/transaction
 func (c *Client) Transaction(txid string) (*readable.TransactionWithStatus, error) {
 	v := url.Values{}
@@ -1104,6 +1393,46 @@ func (c *Client) VerifyTransaction(req VerifyTransactionRequest) (*VerifyTransac
 	return nil, err
 }
 
+// CreatePartialTransaction makes a request to POST /api/v2/transaction/partial
+func (c *Client) CreatePartialTransaction(req CreatePartialTransactionRequest) (*wallet.ReadablePartiallySignedTransaction, error) {
+	var r wallet.ReadablePartiallySignedTransaction
+	ok, err := c.PostJSONV2("/api/v2/transaction/partial", req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// WalletSignPartialTransaction makes a request to POST /api/v2/wallet/transaction/partial/sign
+func (c *Client) WalletSignPartialTransaction(req WalletSignPartialTransactionRequest) (*wallet.ReadablePartiallySignedTransaction, error) {
+	var r wallet.ReadablePartiallySignedTransaction
+	ok, err := c.PostJSONV2("/api/v2/wallet/transaction/partial/sign", req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// CombinePartialTransactions makes a request to POST /api/v2/transaction/partial/combine
+func (c *Client) CombinePartialTransactions(req CombinePartialTransactionsRequest) (*wallet.ReadablePartiallySignedTransaction, error) {
+	var r wallet.ReadablePartiallySignedTransaction
+	ok, err := c.PostJSONV2("/api/v2/transaction/partial/combine", req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
+// FinalizePartialTransaction makes a request to POST /api/v2/transaction/partial/finalize
+func (c *Client) FinalizePartialTransaction(req FinalizePartialTransactionRequest) (*FinalizePartialTransactionResponse, error) {
+	var r FinalizePartialTransactionResponse
+	ok, err := c.PostJSONV2("/api/v2/transaction/partial/finalize", req, &r)
+	if ok {
+		return &r, err
+	}
+	return nil, err
+}
+
 // VerifyAddress makes a request to POST /api/v2/address/verify
 // The API may respond with an error but include data useful for processing,
 // so both return values may be non-nil.
@@ -1121,6 +1450,28 @@ func (c *Client) VerifyAddress(addr string) (*VerifyAddressResponse, error) {
 	return nil, err
 }
 
+// MultisigAddress makes a request to POST /api/v2/address/multisig
+func (c *Client) MultisigAddress(req MultisigAddressRequest) (*MultisigAddressResponse, error) {
+	var rsp MultisigAddressResponse
+	ok, err := c.PostJSONV2("/api/v2/address/multisig", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// TimeLockAddress makes a request to POST /api/v2/address/timelock
+func (c *Client) TimeLockAddress(req TimeLockAddressRequest) (*TimeLockAddressResponse, error) {
+	var rsp TimeLockAddressResponse
+	ok, err := c.PostJSONV2("/api/v2/address/timelock", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
 // RichlistParams are arguments to the /richlist endpoint
 type RichlistParams struct {
 	N                   int
@@ -1201,13 +1552,15 @@ func (c *Client) DecryptWallet(id, password string) (*WalletResponse, error) {
 }
 
 // RecoverWallet makes a request to POST /api/v2/ wallet/recover to recover an encrypted wallet by seed.
+// The seedPassphrase argument is the optional bip39 passphrase of the seed of a bip44 wallet.
 // The password argument is optional, if provided, the recovered wallet will be encrypted with this password,
 // otherwise the recovered wallet will be unencrypted.
-func (c *Client) RecoverWallet(id, seed, password string) (*WalletResponse, error) {
+func (c *Client) RecoverWallet(id, seed, seedPassphrase, password string) (*WalletResponse, error) {
 	req := WalletRecoverRequest{
-		ID:       id,
-		Seed:     seed,
-		Password: password,
+		ID:             id,
+		Seed:           seed,
+		SeedPassphrase: seedPassphrase,
+		Password:       password,
 	}
 
 	var rsp WalletResponse
@@ -1219,6 +1572,125 @@ func (c *Client) RecoverWallet(id, seed, password string) (*WalletResponse, erro
 	return nil, err
 }
 
+// WalletChangeAddressPolicy makes a request to POST /api/v2/wallet/change-address-policy
+func (c *Client) WalletChangeAddressPolicy(id, policy string) (*WalletResponse, error) {
+	req := WalletChangeAddressPolicyRequest{
+		ID:     id,
+		Policy: policy,
+	}
+
+	var rsp WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/change-address-policy", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletChangePassword makes a request to POST /api/v2/wallet/password.
+// The crypto type of the wallet is kept if cryptoType is empty.
+func (c *Client) WalletChangePassword(id, password, newPassword, cryptoType string) (*WalletResponse, error) {
+	req := WalletChangePasswordRequest{
+		ID:          id,
+		Password:    password,
+		NewPassword: newPassword,
+		CryptoType:  cryptoType,
+	}
+
+	var rsp WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/password", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletsExport makes a request to POST /api/v2/wallets/export.
+// All wallets are exported if no ids are given.
+func (c *Client) WalletsExport(ids []string, password string) (*WalletsExportResponse, error) {
+	req := WalletsExportRequest{
+		IDs:      ids,
+		Password: password,
+	}
+
+	var rsp WalletsExportResponse
+	ok, err := c.PostJSONV2("/api/v2/wallets/export", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletsImport makes a request to POST /api/v2/wallets/import
+func (c *Client) WalletsImport(backup, password string) ([]WalletResponse, error) {
+	req := WalletsImportRequest{
+		Backup:   backup,
+		Password: password,
+	}
+
+	var rsp []WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallets/import", req, &rsp)
+	if ok {
+		return rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletAddressLabel makes a request to POST /api/v2/wallet/address/label
+func (c *Client) WalletAddressLabel(id, addr, label string) (*WalletResponse, error) {
+	req := WalletAddressLabelRequest{
+		ID:      id,
+		Address: addr,
+		Label:   label,
+	}
+
+	var rsp WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/address/label", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletAddressBookAdd makes a request to POST /api/v2/wallet/address-book/add
+func (c *Client) WalletAddressBookAdd(id, name, addr, note string) (*WalletResponse, error) {
+	req := WalletAddressBookEntryRequest{
+		ID:      id,
+		Name:    name,
+		Address: addr,
+		Note:    note,
+	}
+
+	var rsp WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/address-book/add", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
+// WalletAddressBookRemove makes a request to POST /api/v2/wallet/address-book/remove
+func (c *Client) WalletAddressBookRemove(id, name string) (*WalletResponse, error) {
+	req := WalletAddressBookEntryRequest{
+		ID:   id,
+		Name: name,
+	}
+
+	var rsp WalletResponse
+	ok, err := c.PostJSONV2("/api/v2/wallet/address-book/remove", req, &rsp)
+	if ok {
+		return &rsp, err
+	}
+
+	return nil, err
+}
+
 // Disconnect disconnect a connections by ID
 func (c *Client) Disconnect(id uint64) error {
 	v := url.Values{}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/util/fee"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// walletConsolidateRequest is sent to POST /api/v2/wallet/consolidate
+type walletConsolidateRequest struct {
+	WalletID          string       `json:"wallet_id"`
+	Password          string       `json:"password"`
+	Unsigned          bool         `json:"unsigned"`
+	IgnoreUnconfirmed bool         `json:"ignore_unconfirmed"`
+	Reserve           bool         `json:"reserve"`
+	To                wh.Address   `json:"to"`
+	MaxInputs         int          `json:"max_inputs,omitempty"`
+	UxOuts            []wh.SHA256  `json:"unspents,omitempty"`
+	Addresses         []wh.Address `json:"addresses,omitempty"`
+}
+
+// Validate validates walletConsolidateRequest data
+func (r walletConsolidateRequest) Validate() error {
+	if r.WalletID == "" {
+		return errors.New("missing wallet_id")
+	}
+
+	if r.Unsigned && len(r.Password) != 0 {
+		return errors.New("password must not be used for unsigned transactions")
+	}
+
+	if r.To.Null() {
+		return errors.New("to is empty")
+	}
+
+	if r.MaxInputs < 0 || r.MaxInputs == 1 {
+		return errors.New("max_inputs must be 0 or at least 2")
+	}
+
+	if len(r.UxOuts) != 0 && len(r.Addresses) != 0 {
+		return errors.New("unspents and addresses cannot be combined")
+	}
+
+	addressMap := make(map[cipher.Address]struct{}, len(r.Addresses))
+	for i, a := range r.Addresses {
+		if a.Null() {
+			return fmt.Errorf("addresses[%d] is empty", i)
+		}
+
+		if _, ok := addressMap[a.Address]; ok {
+			return errors.New("addresses contains duplicate values")
+		}
+
+		addressMap[a.Address] = struct{}{}
+	}
+
+	uxouts := make(map[cipher.SHA256]struct{}, len(r.UxOuts))
+	for _, o := range r.UxOuts {
+		if _, ok := uxouts[o.SHA256]; ok {
+			return errors.New("unspents contains duplicate values")
+		}
+
+		uxouts[o.SHA256] = struct{}{}
+	}
+
+	return nil
+}
+
+// ConsolidateParams converts walletConsolidateRequest to transaction.ConsolidateParams
+func (r walletConsolidateRequest) ConsolidateParams() transaction.ConsolidateParams {
+	return transaction.ConsolidateParams{
+		To:        r.To.Address,
+		MaxInputs: r.MaxInputs,
+	}
+}
+
+// VisorParams converts walletConsolidateRequest to visor.CreateTransactionParams
+func (r walletConsolidateRequest) VisorParams() visor.CreateTransactionParams {
+	var addresses []cipher.Address
+	if len(r.Addresses) != 0 {
+		addresses = make([]cipher.Address, len(r.Addresses))
+		for i, a := range r.Addresses {
+			addresses[i] = a.Address
+		}
+	}
+
+	var uxouts []cipher.SHA256
+	if len(r.UxOuts) != 0 {
+		uxouts = make([]cipher.SHA256, len(r.UxOuts))
+		for i, o := range r.UxOuts {
+			uxouts[i] = o.SHA256
+		}
+	}
+
+	return visor.CreateTransactionParams{
+		IgnoreUnconfirmed: r.IgnoreUnconfirmed,
+		Addresses:         addresses,
+		UxOuts:            uxouts,
+		Reserve:           r.Reserve,
+	}
+}
+
+// WalletConsolidateResponse is returned by POST /api/v2/wallet/consolidate
+type WalletConsolidateResponse struct {
+	Transactions []CreateTransactionResponse `json:"transactions"`
+	// Coins is the total of coins consolidated
+	Coins string `json:"coins"`
+	// Fee is the total of coin hours burned by the transactions
+	Fee string `json:"fee"`
+	// Skipped are the unspent outputs without coin hours that could not be consolidated
+	Skipped []CreatedTransactionInput `json:"skipped"`
+}
+
+// NewWalletConsolidateResponse creates a WalletConsolidateResponse
+func NewWalletConsolidateResponse(c *visor.Consolidation) (*WalletConsolidateResponse, error) {
+	if len(c.Transactions) != len(c.Inputs) {
+		return nil, errors.New("len(c.Transactions) != len(c.Inputs)")
+	}
+
+	txns := make([]CreateTransactionResponse, len(c.Transactions))
+	for i := range c.Transactions {
+		txnResp, err := NewCreateTransactionResponse(&c.Transactions[i], c.Inputs[i])
+		if err != nil {
+			return nil, err
+		}
+		txns[i] = *txnResp
+	}
+
+	coins, err := droplet.ToString(c.Coins)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make([]CreatedTransactionInput, len(c.Skipped))
+	for i, in := range c.Skipped {
+		s, err := NewCreatedTransactionInput(in)
+		if err != nil {
+			return nil, err
+		}
+		skipped[i] = *s
+	}
+
+	return &WalletConsolidateResponse{
+		Transactions: txns,
+		Coins:        coins,
+		Fee:          fmt.Sprint(c.Fee),
+		Skipped:      skipped,
+	}, nil
+}
+
+// walletConsolidateHandler creates transactions that merge the unspent outputs of a wallet into one address.
+// The transactions are not broadcast; the total coin hours burned by them is reported in the response.
+// Method: POST
+// URI: /api/v2/wallet/consolidate
+// Args: JSON body
+func walletConsolidateHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req walletConsolidateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var c *visor.Consolidation
+		var err error
+		if req.Unsigned {
+			c, err = gateway.WalletCreateConsolidation(req.WalletID, req.ConsolidateParams(), req.VisorParams())
+		} else {
+			c, err = gateway.WalletCreateConsolidationSigned(req.WalletID, []byte(req.Password), req.ConsolidateParams(), req.VisorParams())
+		}
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			case blockdb.ErrUnspentNotExist,
+				transaction.Error,
+				visor.UserError,
+				visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				switch err {
+				case fee.ErrTxnNoFee,
+					fee.ErrTxnInsufficientCoinHours:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+				}
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		consolidateResp, err := NewWalletConsolidateResponse(c)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: consolidateResp,
+		})
+	}
+}
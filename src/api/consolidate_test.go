@@ -0,0 +1,262 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletConsolidate(t *testing.T) {
+	txnAndInputs := prepareTxnAndInputs(t)
+	skippedUx, _ := makeUxOutWithSecret(t)
+	skippedUx.Body.Hours = 0
+	skipped, err := visor.NewTransactionInput(skippedUx, skippedUx.Head.Time)
+	require.NoError(t, err)
+
+	consolidation := &visor.Consolidation{
+		Transactions: []coin.Transaction{txnAndInputs.txn},
+		Inputs:       [][]visor.TransactionInput{txnAndInputs.inputs},
+		Coins:        6e6,
+		Fee:          txnAndInputs.inputs[0].CalculatedHours - 100,
+		Skipped:      []visor.TransactionInput{skipped},
+	}
+
+	consolidateResp, err := NewWalletConsolidateResponse(consolidation)
+	require.NoError(t, err)
+
+	to := testutil.MakeAddress()
+	addr := testutil.MakeAddress()
+	ux := testutil.RandSHA256(t)
+
+	tt := []struct {
+		name              string
+		body              *WalletConsolidateRequest
+		rawBody           string
+		consolidateParams transaction.ConsolidateParams
+		visorParams       visor.CreateTransactionParams
+		gatewayResult     *visor.Consolidation
+		gatewayErr        error
+		status            int
+		err               string
+		data              *WalletConsolidateResponse
+	}{
+		{
+			name:    "400 - invalid json",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name: "400 - missing wallet_id",
+			body: &WalletConsolidateRequest{
+				To: to.String(),
+			},
+			status: http.StatusBadRequest,
+			err:    "missing wallet_id",
+		},
+		{
+			name: "400 - password with unsigned",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				Password: "pwd",
+				Unsigned: true,
+				To:       to.String(),
+			},
+			status: http.StatusBadRequest,
+			err:    "password must not be used for unsigned transactions",
+		},
+		{
+			name: "400 - empty to",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "invalid address: Invalid base58 string",
+		},
+		{
+			name: "400 - invalid to",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       "foo",
+			},
+			status: http.StatusBadRequest,
+			err:    "invalid address: Invalid address length",
+		},
+		{
+			name: "400 - invalid max_inputs",
+			body: &WalletConsolidateRequest{
+				WalletID:  "foo.wlt",
+				To:        to.String(),
+				MaxInputs: 1,
+			},
+			status: http.StatusBadRequest,
+			err:    "max_inputs must be 0 or at least 2",
+		},
+		{
+			name: "400 - unspents and addresses",
+			body: &WalletConsolidateRequest{
+				WalletID:  "foo.wlt",
+				To:        to.String(),
+				UxOuts:    []string{ux.Hex()},
+				Addresses: []string{addr.String()},
+			},
+			status: http.StatusBadRequest,
+			err:    "unspents and addresses cannot be combined",
+		},
+		{
+			name: "400 - duplicate addresses",
+			body: &WalletConsolidateRequest{
+				WalletID:  "foo.wlt",
+				To:        to.String(),
+				Addresses: []string{addr.String(), addr.String()},
+			},
+			status: http.StatusBadRequest,
+			err:    "addresses contains duplicate values",
+		},
+		{
+			name: "400 - nothing to consolidate",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       to.String(),
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			gatewayErr: transaction.ErrNothingToConsolidate,
+			status:     http.StatusBadRequest,
+			err:        "no unspents to consolidate",
+		},
+		{
+			name: "400 - no fee",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       to.String(),
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			gatewayErr: fee.ErrTxnNoFee,
+			status:     http.StatusBadRequest,
+			err:        fee.ErrTxnNoFee.Error(),
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       to.String(),
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        "wallet api is disabled",
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       to.String(),
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        "wallet doesn't exist",
+		},
+		{
+			name: "500 - other error",
+			body: &WalletConsolidateRequest{
+				WalletID: "foo.wlt",
+				To:       to.String(),
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200 - signed",
+			body: &WalletConsolidateRequest{
+				WalletID:  "foo.wlt",
+				Password:  "pwd",
+				To:        to.String(),
+				MaxInputs: 10,
+				Addresses: []string{addr.String()},
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To:        to,
+				MaxInputs: 10,
+			},
+			visorParams: visor.CreateTransactionParams{
+				Addresses: []cipher.Address{addr},
+			},
+			gatewayResult: consolidation,
+			status:        http.StatusOK,
+			data:          consolidateResp,
+		},
+		{
+			name: "200 - unsigned",
+			body: &WalletConsolidateRequest{
+				WalletID:          "foo.wlt",
+				Unsigned:          true,
+				IgnoreUnconfirmed: true,
+				Reserve:           true,
+				To:                to.String(),
+				UxOuts:            []string{ux.Hex()},
+			},
+			consolidateParams: transaction.ConsolidateParams{
+				To: to,
+			},
+			visorParams: visor.CreateTransactionParams{
+				IgnoreUnconfirmed: true,
+				UxOuts:            []cipher.SHA256{ux},
+				Reserve:           true,
+			},
+			gatewayResult: consolidation,
+			status:        http.StatusOK,
+			data:          consolidateResp,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.body != nil {
+				if tc.body.Unsigned {
+					gateway.On("WalletCreateConsolidation", tc.body.WalletID, tc.consolidateParams, tc.visorParams).Return(tc.gatewayResult, tc.gatewayErr)
+				} else {
+					gateway.On("WalletCreateConsolidationSigned", tc.body.WalletID, []byte(tc.body.Password), tc.consolidateParams, tc.visorParams).Return(tc.gatewayResult, tc.gatewayErr)
+				}
+			}
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/consolidate", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data WalletConsolidateResponse
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// FrozenOutput is an unspent output of a wallet that is never chosen to spend
+type FrozenOutput struct {
+	UxID   string    `json:"uxid"`
+	Reason string    `json:"reason"`
+	Time   time.Time `json:"time"`
+}
+
+// NewFrozenOutputs creates []FrozenOutput from []wallet.FrozenOutput
+func NewFrozenOutputs(fos []wallet.FrozenOutput) []FrozenOutput {
+	frozen := make([]FrozenOutput, len(fos))
+	for i, fo := range fos {
+		frozen[i] = FrozenOutput{
+			UxID:   fo.UxOut.Hex(),
+			Reason: fo.Reason,
+			Time:   fo.Time,
+		}
+	}
+	return frozen
+}
+
+// frozenOutputsErrorResponse creates the response of a frozen output request that failed
+func frozenOutputsErrorResponse(err error) HTTPResponse {
+	switch err.(type) {
+	case blockdb.ErrUnspentNotExist:
+		return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+	default:
+		return outputReservationsErrorResponse(err)
+	}
+}
+
+// walletFrozenOutputsHandler returns the frozen outputs of a wallet, ordered by the time they were frozen
+// Method: GET
+// URI: /api/v2/wallet/frozen
+// Args:
+//	id: wallet id
+func walletFrozenOutputsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wltID := r.FormValue("id")
+		if wltID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "missing wallet id")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		fos, err := gateway.GetFrozenOutputs(wltID)
+		if err != nil {
+			writeHTTPResponse(w, frozenOutputsErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewFrozenOutputs(fos),
+		})
+	}
+}
+
+// WalletFreezeOutputsRequest is the request body object for /api/v2/wallet/frozen/freeze
+// and /api/v2/wallet/frozen/unfreeze
+type WalletFreezeOutputsRequest struct {
+	WalletID string      `json:"wallet_id"`
+	UxOuts   []wh.SHA256 `json:"unspents"`
+	// Reason is recorded with the frozen outputs, it is ignored when unfreezing
+	Reason string `json:"reason,omitempty"`
+}
+
+// parseWalletFreezeOutputsRequest decodes and validates a WalletFreezeOutputsRequest.
+// Writes the error response and returns false if the request is invalid.
+func parseWalletFreezeOutputsRequest(w http.ResponseWriter, r *http.Request) (*WalletFreezeOutputsRequest, []cipher.SHA256, bool) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return nil, nil, false
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return nil, nil, false
+	}
+
+	var req WalletFreezeOutputsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return nil, nil, false
+	}
+
+	if req.WalletID == "" {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "wallet_id is required")
+		writeHTTPResponse(w, resp)
+		return nil, nil, false
+	}
+
+	if len(req.UxOuts) == 0 {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "unspents is required")
+		writeHTTPResponse(w, resp)
+		return nil, nil, false
+	}
+
+	uxOuts := make([]cipher.SHA256, len(req.UxOuts))
+	for i, o := range req.UxOuts {
+		uxOuts[i] = o.SHA256
+	}
+
+	return &req, uxOuts, true
+}
+
+// walletFreezeOutputsHandler freezes unspent outputs of a wallet, so that they are never chosen to spend
+// by the transactions created by the wallet. The outputs must be unspent and owned by the wallet.
+// Returns the outputs that were frozen; outputs that were already frozen are not returned.
+// Method: POST
+// URI: /api/v2/wallet/frozen/freeze
+// Args: JSON body
+func walletFreezeOutputsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, uxOuts, ok := parseWalletFreezeOutputsRequest(w, r)
+		if !ok {
+			return
+		}
+
+		fos, err := gateway.FreezeOutputs(req.WalletID, uxOuts, req.Reason)
+		if err != nil {
+			writeHTTPResponse(w, frozenOutputsErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewFrozenOutputs(fos),
+		})
+	}
+}
+
+// walletUnfreezeOutputsHandler unfreezes unspent outputs of a wallet.
+// Returns the outputs that were unfrozen; outputs that were not frozen are ignored.
+// Method: POST
+// URI: /api/v2/wallet/frozen/unfreeze
+// Args: JSON body
+func walletUnfreezeOutputsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, uxOuts, ok := parseWalletFreezeOutputsRequest(w, r)
+		if !ok {
+			return
+		}
+
+		fos, err := gateway.UnfreezeOutputs(req.WalletID, uxOuts)
+		if err != nil {
+			writeHTTPResponse(w, frozenOutputsErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewFrozenOutputs(fos),
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func makeFrozenOutput(t *testing.T, reason string) (wallet.FrozenOutput, FrozenOutput) {
+	now := time.Unix(1540000000, 0).UTC()
+	fo := wallet.FrozenOutput{
+		UxOut:  testutil.RandSHA256(t),
+		Reason: reason,
+		Time:   now,
+	}
+
+	return fo, FrozenOutput{
+		UxID:   fo.UxOut.Hex(),
+		Reason: reason,
+		Time:   now,
+	}
+}
+
+func TestWalletFrozenOutputs(t *testing.T) {
+	fo1, rfo1 := makeFrozenOutput(t, "tainted")
+	fo2, rfo2 := makeFrozenOutput(t, "")
+
+	tt := []struct {
+		name       string
+		method     string
+		walletID   string
+		frozen     []wallet.FrozenOutput
+		gatewayErr error
+		status     int
+		err        string
+		data       []FrozenOutput
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - missing wallet id",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "missing wallet id",
+		},
+		{
+			name:       "403 - wallet API disabled",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name:       "404 - wallet not found",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name:     "200 - none",
+			method:   http.MethodGet,
+			walletID: "foo.wlt",
+			status:   http.StatusOK,
+			data:     []FrozenOutput{},
+		},
+		{
+			name:     "200",
+			method:   http.MethodGet,
+			walletID: "foo.wlt",
+			frozen:   []wallet.FrozenOutput{fo1, fo2},
+			status:   http.StatusOK,
+			data:     []FrozenOutput{rfo1, rfo2},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetFrozenOutputs", tc.walletID).Return(tc.frozen, tc.gatewayErr)
+
+			v := url.Values{}
+			if tc.walletID != "" {
+				v.Add("id", tc.walletID)
+			}
+
+			status, rsp := doGetV2Request(t, gateway, tc.method, "/api/v2/wallet/frozen", v)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []FrozenOutput
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
+
+func TestWalletFreezeOutputs(t *testing.T) {
+	fo1, rfo1 := makeFrozenOutput(t, "tainted")
+	fo2, rfo2 := makeFrozenOutput(t, "tainted")
+
+	tt := []struct {
+		name       string
+		body       *WalletFreezeOutputsRequest
+		rawBody    string
+		uxOuts     []cipher.SHA256
+		frozen     []wallet.FrozenOutput
+		gatewayErr error
+		status     int
+		err        string
+		data       []FrozenOutput
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing wallet_id",
+			body:   &WalletFreezeOutputsRequest{},
+			status: http.StatusBadRequest,
+			err:    "wallet_id is required",
+		},
+		{
+			name: "400 - missing unspents",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "unspents is required",
+		},
+		{
+			name: "400 - unspent not found",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+				Reason:   "tainted",
+			},
+			uxOuts:     []cipher.SHA256{fo1.UxOut},
+			gatewayErr: blockdb.NewErrUnspentNotExist(fo1.UxOut.Hex()),
+			status:     http.StatusBadRequest,
+			err:        blockdb.NewErrUnspentNotExist(fo1.UxOut.Hex()).Error(),
+		},
+		{
+			name: "400 - unspent not in wallet",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+				Reason:   "tainted",
+			},
+			uxOuts:     []cipher.SHA256{fo1.UxOut},
+			gatewayErr: wallet.ErrUnknownUxOut,
+			status:     http.StatusBadRequest,
+			err:        wallet.ErrUnknownUxOut.Error(),
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+				Reason:   "tainted",
+			},
+			uxOuts:     []cipher.SHA256{fo1.UxOut},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "200",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}, {SHA256: fo2.UxOut}},
+				Reason:   "tainted",
+			},
+			uxOuts: []cipher.SHA256{fo1.UxOut, fo2.UxOut},
+			frozen: []wallet.FrozenOutput{fo1, fo2},
+			status: http.StatusOK,
+			data:   []FrozenOutput{rfo1, rfo2},
+		},
+		{
+			name: "200 - already frozen",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+				Reason:   "tainted",
+			},
+			uxOuts: []cipher.SHA256{fo1.UxOut},
+			status: http.StatusOK,
+			data:   []FrozenOutput{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("FreezeOutputs", "foo.wlt", tc.uxOuts, "tainted").Return(tc.frozen, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/frozen/freeze", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []FrozenOutput
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
+
+func TestWalletUnfreezeOutputs(t *testing.T) {
+	fo1, rfo1 := makeFrozenOutput(t, "tainted")
+
+	tt := []struct {
+		name       string
+		body       *WalletFreezeOutputsRequest
+		rawBody    string
+		uxOuts     []cipher.SHA256
+		unfrozen   []wallet.FrozenOutput
+		gatewayErr error
+		status     int
+		err        string
+		data       []FrozenOutput
+	}{
+		{
+			name:    "400 - invalid unspents",
+			rawBody: `{"wallet_id":"foo.wlt","unspents":["foo"]}`,
+			status:  http.StatusBadRequest,
+			err:     "invalid SHA256 hash: encoding/hex: invalid byte: U+006F 'o'",
+		},
+		{
+			name: "400 - missing unspents",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "unspents is required",
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+			},
+			uxOuts:     []cipher.SHA256{fo1.UxOut},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+			},
+			uxOuts:     []cipher.SHA256{fo1.UxOut},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200",
+			body: &WalletFreezeOutputsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: fo1.UxOut}},
+			},
+			uxOuts:   []cipher.SHA256{fo1.UxOut},
+			unfrozen: []wallet.FrozenOutput{fo1},
+			status:   http.StatusOK,
+			data:     []FrozenOutput{rfo1},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("UnfreezeOutputs", "foo.wlt", tc.uxOuts).Return(tc.unfrozen, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/frozen/unfreeze", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []FrozenOutput
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
@@ -17,19 +17,33 @@ type Gatewayer interface {
 	CreateTransaction(p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletCreateTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletCreateTransactionSigned(wltID string, password []byte, p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error)
+	WalletCreateBatchTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error)
+	WalletCreateBatchTransactionSigned(wltID string, password []byte, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error)
+	WalletCreateConsolidation(wltID string, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error)
+	WalletCreateConsolidationSigned(wltID string, password []byte, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error)
+	WalletBumpTransaction(wltID string, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error)
+	WalletBumpTransactionSigned(wltID string, password []byte, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error)
 	WalletSignTransaction(wltID string, password []byte, txn *coin.Transaction, signIndexes []int) (*coin.Transaction, []visor.TransactionInput, error)
+	WalletSignPartiallySignedTransaction(wltID string, password []byte, pst *wallet.PartiallySignedTransaction, signIndexes []int) (*wallet.PartiallySignedTransaction, error)
 	GetWalletBalance(wltID string) (wallet.BalancePair, wallet.AddressBalances, error)
 	GetWallet(wltID string) (*wallet.Wallet, error)
 	GetWallets() (wallet.Wallets, error)
 	UpdateWalletLabel(wltID, label string) error
+	UpdateWalletChangeAddressPolicy(wltID, policy string) (*wallet.Wallet, error)
+	UpdateWalletAddressLabel(wltID, addr, label string) (*wallet.Wallet, error)
+	AddWalletAddressBookEntry(wltID, name, addr, note string) (*wallet.Wallet, error)
+	RemoveWalletAddressBookEntry(wltID, name string) (*wallet.Wallet, error)
 	GetWalletUnconfirmedTransactions(wltID string) ([]visor.UnconfirmedTransaction, error)
 	GetWalletUnconfirmedTransactionsVerbose(wltID string) ([]visor.UnconfirmedTransaction, [][]visor.TransactionInput, error)
 	CreateWallet(wltName string, options wallet.Options) (*wallet.Wallet, error)
-	RecoverWallet(wltID, seed string, password []byte) (*wallet.Wallet, error)
+	RecoverWallet(wltID, seed, seedPassphrase string, password []byte) (*wallet.Wallet, error)
 	NewAddresses(wltID string, password []byte, n uint64) ([]cipher.Address, error)
 	GetWalletDir() (string, error)
 	EncryptWallet(wltID string, password []byte) (*wallet.Wallet, error)
 	DecryptWallet(wltID string, password []byte) (*wallet.Wallet, error)
+	ChangeWalletPassword(wltID string, password, newPassword []byte, cryptoType wallet.CryptoType) (*wallet.Wallet, error)
+	ExportWallets(wltIDs []string, password []byte) ([]byte, error)
+	ImportWallets(backup, password []byte) ([]*wallet.Wallet, error)
 	GetWalletSeed(wltID string, password []byte) (string, error)
 	GetSignedBlockByHash(hash cipher.SHA256) (*coin.SignedBlock, error)
 	GetSignedBlockByHashVerbose(hash cipher.SHA256) (*coin.SignedBlock, [][]visor.TransactionInput, error)
@@ -53,12 +67,24 @@ type Gatewayer interface {
 	GetExchgConnection() []string
 	GetAllUnconfirmedTransactions() ([]visor.UnconfirmedTransaction, error)
 	GetAllUnconfirmedTransactionsVerbose() ([]visor.UnconfirmedTransaction, [][]visor.TransactionInput, error)
+	GetEvictedUnconfirmedTransactions() []visor.EvictedTransaction
+	GetExpiredUnconfirmedTransactions() ([]visor.ExpiredTransaction, error)
 	GetTransaction(txid cipher.SHA256) (*visor.Transaction, error)
 	GetTransactionVerbose(txid cipher.SHA256) (*visor.Transaction, []visor.TransactionInput, error)
 	GetTransactions(flts []visor.TxFilter) ([]visor.Transaction, error)
 	GetTransactionsVerbose(flts []visor.TxFilter) ([]visor.Transaction, [][]visor.TransactionInput, error)
 	InjectBroadcastTransaction(txn coin.Transaction) error
 	ResendUnconfirmedTxns() ([]cipher.SHA256, error)
+	GetRebroadcastTransactions() []daemon.RebroadcastTransaction
+	GetTransactionLifecycle(txid cipher.SHA256) (*visor.TransactionLifecycle, error)
+	GetTransactionLifecycles(wltID string) ([]visor.TransactionLifecycle, error)
+	GetIdempotentResponse(wltID, key string) (*visor.IdempotentResponse, error)
+	SaveIdempotentResponse(wltID, key string, r visor.IdempotentResponse) error
+	GetOutputReservations(wltID string) ([]visor.OutputReservation, error)
+	ReleaseOutputReservations(wltID string, uxOuts []cipher.SHA256) ([]visor.OutputReservation, error)
+	GetFrozenOutputs(wltID string) ([]wallet.FrozenOutput, error)
+	FreezeOutputs(wltID string, uxOuts []cipher.SHA256, reason string) ([]wallet.FrozenOutput, error)
+	UnfreezeOutputs(wltID string, uxOuts []cipher.SHA256) ([]wallet.FrozenOutput, error)
 	GetUxOutByID(id cipher.SHA256) (*historydb.UxOut, error)
 	GetSpentOutputsForAddresses(addr []cipher.Address) ([][]historydb.UxOut, error)
 	GetVerboseTransactionsForAddress(a cipher.Address) ([]visor.Transaction, [][]visor.TransactionInput, error)
@@ -322,7 +322,7 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		AllowedOrigins:     allowedOrigins,
 		Debug:              false,
 		AllowedMethods:     []string{http.MethodGet, http.MethodPost},
-		AllowedHeaders:     []string{"Origin", "Accept", "Content-Type", "X-Requested-With", CSRFHeaderName},
+		AllowedHeaders:     []string{"Origin", "Accept", "Content-Type", "X-Requested-With", CSRFHeaderName, IdempotencyKeyHeader},
 		AllowCredentials:   false, // credentials are not used, but it would be safe to enable if necessary
 		OptionsPassthrough: false,
 	})
@@ -412,6 +412,8 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 		webHandler(apiVersion2, "/api/v2"+endpoint, handler, methodAPISets)
 	}
 
+	idempotencyLocks := newIdempotencyLocks()
+
 	indexHandler := newIndexHandler(c.appLoc, c.enableGUI)
 	if !c.disableCSP {
 		indexHandler = CSPHandler(indexHandler)
@@ -464,21 +466,69 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV1("/wallet/balance", walletBalanceHandler(gateway), map[string][]string{
 		http.MethodGet: []string{EndpointsWallet},
 	})
-	webHandlerV1("/wallet/transaction", walletCreateTransactionHandler(gateway), map[string][]string{
+	webHandlerV1("/wallet/transaction", idempotentHandler(gateway, idempotencyLocks, walletIDFromBody, walletCreateTransactionHandler(gateway)), map[string][]string{
 		http.MethodPost: []string{EndpointsWallet},
 	})
 	webHandlerV2("/wallet/transaction/sign", walletSignTransactionHandler(gateway), map[string][]string{
 		http.MethodPost: []string{EndpointsWallet},
 	})
+	webHandlerV2("/wallet/transaction/batch", walletCreateBatchTransactionHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/transaction/bump", walletBumpTransactionHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/transaction/partial/sign", walletSignPartialTxnHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/consolidate", walletConsolidateHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/reservations", walletOutputReservationsHandler(gateway), map[string][]string{
+		http.MethodGet: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/reservations/release", walletReleaseOutputReservationsHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/frozen", walletFrozenOutputsHandler(gateway), map[string][]string{
+		http.MethodGet: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/frozen/freeze", walletFreezeOutputsHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/frozen/unfreeze", walletUnfreezeOutputsHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
 	webHandlerV1("/wallet/transactions", walletTransactionsHandler(gateway), map[string][]string{
 		http.MethodGet: []string{EndpointsWallet},
 	})
 	webHandlerV1("/wallet/update", walletUpdateHandler(gateway), map[string][]string{
 		http.MethodPost: []string{EndpointsWallet},
 	})
+	webHandlerV2("/wallet/change-address-policy", walletChangeAddressPolicyHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/password", walletChangePasswordHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/address/label", walletAddressLabelHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/address-book/add", walletAddressBookAddHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallet/address-book/remove", walletAddressBookRemoveHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
 	webHandlerV1("/wallets", walletsHandler(gateway), map[string][]string{
 		http.MethodGet: []string{EndpointsWallet},
 	})
+	webHandlerV2("/wallets/export", walletsExportHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
+	webHandlerV2("/wallets/import", walletsImportHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsWallet},
+	})
 	webHandlerV1("/wallets/folderName", walletFolderHandler(gateway), map[string][]string{
 		http.MethodGet: []string{EndpointsWallet},
 	})
@@ -559,16 +609,34 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV2("/transaction/verify", verifyTxnHandler(gateway), map[string][]string{
 		http.MethodPost: []string{EndpointsRead},
 	})
+	webHandlerV2("/transaction/partial", createPartialTxnHandler(gateway), map[string][]string{
+		http.MethodPost: []string{EndpointsRead},
+	})
+	webHandlerV2("/transaction/partial/combine", http.HandlerFunc(combinePartialTxnsHandler), map[string][]string{
+		http.MethodPost: []string{EndpointsRead},
+	})
+	webHandlerV2("/transaction/partial/finalize", http.HandlerFunc(finalizePartialTxnHandler), map[string][]string{
+		http.MethodPost: []string{EndpointsRead},
+	})
 	webHandlerV1("/transactions", transactionsHandler(gateway), map[string][]string{
 		http.MethodGet:  []string{EndpointsRead},
 		http.MethodPost: []string{EndpointsRead},
 	})
-	webHandlerV1("/injectTransaction", injectTransactionHandler(gateway), map[string][]string{
+	webHandlerV1("/injectTransaction", idempotentHandler(gateway, idempotencyLocks, noWalletID, injectTransactionHandler(gateway)), map[string][]string{
 		http.MethodPost: []string{EndpointsTransaction, EndpointsWallet},
 	})
 	webHandlerV1("/resendUnconfirmedTxns", resendUnconfirmedTxnsHandler(gateway), map[string][]string{
 		http.MethodPost: []string{EndpointsTransaction, EndpointsWallet},
 	})
+	webHandlerV2("/transaction/rebroadcast", transactionRebroadcastHandler(gateway), map[string][]string{
+		http.MethodGet: []string{EndpointsTransaction, EndpointsWallet},
+	})
+	webHandlerV2("/transaction/lifecycle", transactionLifecycleHandler(gateway), map[string][]string{
+		http.MethodGet: []string{EndpointsTransaction, EndpointsWallet},
+	})
+	webHandlerV2("/transaction/lifecycles", transactionLifecyclesHandler(gateway), map[string][]string{
+		http.MethodGet: []string{EndpointsTransaction, EndpointsWallet},
+	})
 	webHandlerV1("/rawtx", rawTxnHandler(gateway), map[string][]string{
 		http.MethodGet: []string{EndpointsRead},
 	})
@@ -598,6 +666,12 @@ func newServerMux(c muxConfig, gateway Gatewayer) *http.ServeMux {
 	webHandlerV2("/address/verify", http.HandlerFunc(addressVerifyHandler), map[string][]string{
 		http.MethodPost: []string{EndpointsRead},
 	})
+	webHandlerV2("/address/multisig", http.HandlerFunc(addressMultisigHandler), map[string][]string{
+		http.MethodPost: []string{EndpointsRead},
+	})
+	webHandlerV2("/address/timelock", http.HandlerFunc(addressTimeLockHandler), map[string][]string{
+		http.MethodPost: []string{EndpointsRead},
+	})
 
 	// Explorer endpoints
 	webHandlerV1("/coinSupply", coinSupplyHandler(gateway), map[string][]string{
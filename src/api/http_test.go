@@ -160,12 +160,24 @@ var endpointsMethods = map[string][]string{
 	"/api/v2/address/verify": []string{
 		http.MethodPost,
 	},
+	"/api/v2/address/multisig": []string{
+		http.MethodPost,
+	},
+	"/api/v2/address/timelock": []string{
+		http.MethodPost,
+	},
+	"/api/v2/wallet/consolidate": []string{
+		http.MethodPost,
+	},
 	"/api/v2/wallet/recover": []string{
 		http.MethodPost,
 	},
 	"/api/v2/wallet/seed/verify": []string{
 		http.MethodPost,
 	},
+	"/api/v2/wallet/transaction/batch": []string{
+		http.MethodPost,
+	},
 	"/api/v2/wallet/transaction/sign": []string{
 		http.MethodPost,
 	},
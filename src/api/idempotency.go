@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+const (
+	// IdempotencyKeyHeader is the header of the client-supplied idempotency key of a request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on a response returned from a previous request with the same idempotency key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencyLocks tracks the idempotency keys of the requests in progress
+type idempotencyLocks struct {
+	sync.Mutex
+	keys map[string]struct{}
+}
+
+func newIdempotencyLocks() *idempotencyLocks {
+	return &idempotencyLocks{
+		keys: make(map[string]struct{}),
+	}
+}
+
+// lock returns false if a request with the same key is in progress
+func (l *idempotencyLocks) lock(key string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if _, ok := l.keys[key]; ok {
+		return false
+	}
+
+	l.keys[key] = struct{}{}
+	return true
+}
+
+func (l *idempotencyLocks) unlock(key string) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.keys, key)
+}
+
+// responseRecorder writes a response and records its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// walletIDFromBody returns the "wallet_id" field of a JSON request body.
+// Returns false if the body can't be parsed.
+func walletIDFromBody(body []byte) (string, bool) {
+	var v struct {
+		WalletID string `json:"wallet_id"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", false
+	}
+
+	return v.WalletID, true
+}
+
+// idempotentHandler makes a v1 POST endpoint idempotent for requests with an Idempotency-Key header.
+// The successful response of a request is saved for the key and the wallet of the request, and returned
+// to retries with the same key and wallet within the retention period, without handling them again.
+// A key reused for a different request is rejected with 422, and a retry made while the request with the
+// same key is in progress is rejected with 409.
+// Error responses are not saved, so a request that failed can be retried with the same key.
+// walletID returns the wallet of a request body, or false if the body can't be parsed.
+// Requests without a wallet have an empty wallet ID.
+func idempotentHandler(gateway Gatewayer, locks *idempotencyLocks, walletID func(body []byte) (string, bool), handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || r.Method != http.MethodPost {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			wh.Error400(w, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			wh.Error400(w, err.Error())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		wltID, ok := walletID(body)
+		if !ok {
+			// Let the handler report the invalid body
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		requestHash := cipher.SumSHA256(append([]byte(r.URL.Path+"\n"), body...)).Hex()
+
+		lockKey := wltID + "\x00" + key
+		if !locks.lock(lockKey) {
+			wh.ErrorXXX(w, http.StatusConflict, "a request with this Idempotency-Key is in progress")
+			return
+		}
+		defer locks.unlock(lockKey)
+
+		saved, err := gateway.GetIdempotentResponse(wltID, key)
+		if err != nil {
+			wh.Error500(w, err.Error())
+			return
+		}
+
+		if saved != nil {
+			if saved.RequestHash != requestHash {
+				wh.ErrorXXX(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				return
+			}
+
+			if saved.ContentType != "" {
+				w.Header().Set("Content-Type", saved.ContentType)
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(saved.Status)
+			if _, err := w.Write(saved.Body); err != nil {
+				logger.WithError(err).Error("http Write failed")
+			}
+			return
+		}
+
+		rec := &responseRecorder{
+			ResponseWriter: w,
+		}
+		handler.ServeHTTP(rec, r)
+
+		if rec.status < http.StatusOK || rec.status >= http.StatusMultipleChoices {
+			return
+		}
+
+		if err := gateway.SaveIdempotentResponse(wltID, key, visor.IdempotentResponse{
+			RequestHash: requestHash,
+			Status:      rec.status,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+			Created:     time.Now().UTC(),
+		}); err != nil {
+			logger.WithError(err).Error("gateway.SaveIdempotentResponse failed")
+		}
+	}
+}
+
+// noWalletID is the walletID function of idempotentHandler for requests that are not made for a wallet
+func noWalletID([]byte) (string, bool) {
+	return "", true
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestIdempotentInjectTransaction(t *testing.T) {
+	txn := makeTransaction(t)
+	body := `{"rawtx":"` + txn.MustSerializeHex() + `"}`
+	endpoint := "/api/v1/injectTransaction"
+	requestHash := cipher.SumSHA256([]byte(endpoint + "\n" + body)).Hex()
+
+	txidJSON, err := json.MarshalIndent(txn.Hash().Hex(), "", "    ")
+	require.NoError(t, err)
+
+	saved := &visor.IdempotentResponse{
+		RequestHash: requestHash,
+		Status:      http.StatusOK,
+		ContentType: ContentTypeJSON,
+		Body:        txidJSON,
+	}
+
+	tt := []struct {
+		name       string
+		key        string
+		body       string
+		saved      *visor.IdempotentResponse
+		getErr     error
+		injectErr  error
+		injected   bool
+		save       bool
+		status     int
+		response   string
+		replayed   bool
+		gatewayKey string
+	}{
+		{
+			name:     "200 - no key",
+			body:     body,
+			injected: true,
+			status:   http.StatusOK,
+			response: string(txidJSON),
+		},
+		{
+			name:   "400 - key too long",
+			key:    strings.Repeat("a", 256),
+			body:   body,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:     "200 - first request is saved",
+			key:      "payout-1",
+			body:     body,
+			injected: true,
+			save:     true,
+			status:   http.StatusOK,
+			response: string(txidJSON),
+		},
+		{
+			name:     "200 - retry is replayed",
+			key:      "payout-1",
+			body:     body,
+			saved:    saved,
+			status:   http.StatusOK,
+			response: string(txidJSON),
+			replayed: true,
+		},
+		{
+			name:   "422 - key reused for a different request",
+			key:    "payout-1",
+			body:   `{"rawtx": "` + txn.MustSerializeHex() + `"}`,
+			saved:  saved,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "500 - lookup failed",
+			key:    "payout-1",
+			body:   body,
+			getErr: errors.New("failed"),
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:      "503 - error response is not saved",
+			key:       "payout-1",
+			body:      body,
+			injected:  true,
+			injectErr: visor.ErrUnconfirmedPoolFull,
+			status:    http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetIdempotentResponse", "", tc.key).Return(tc.saved, tc.getErr)
+			gateway.On("InjectBroadcastTransaction", txn).Return(tc.injectErr)
+			gateway.On("SaveIdempotentResponse", "", tc.key, mock.MatchedBy(func(r visor.IdempotentResponse) bool {
+				return r.RequestHash == requestHash &&
+					r.Status == http.StatusOK &&
+					r.ContentType == ContentTypeJSON &&
+					string(r.Body) == string(txidJSON) &&
+					!r.Created.IsZero()
+			})).Return(nil)
+
+			req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			setCSRFParameters(t, tokenValid, req)
+			if tc.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tc.key)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := newServerMux(defaultMuxConfig(), gateway)
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.status, rr.Code, rr.Body.String())
+			if tc.response != "" {
+				require.Equal(t, tc.response, rr.Body.String())
+			}
+
+			if tc.replayed {
+				require.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+				require.Equal(t, ContentTypeJSON, rr.Header().Get("Content-Type"))
+			} else {
+				require.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+			}
+
+			if tc.injected {
+				gateway.AssertCalled(t, "InjectBroadcastTransaction", txn)
+			} else {
+				gateway.AssertNotCalled(t, "InjectBroadcastTransaction", txn)
+			}
+
+			if tc.save {
+				gateway.AssertNumberOfCalls(t, "SaveIdempotentResponse", 1)
+			} else {
+				gateway.AssertNotCalled(t, "SaveIdempotentResponse", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestIdempotentWalletCreateTransaction(t *testing.T) {
+	// The idempotency key is scoped by the wallet of the request
+	gateway := &MockGatewayer{}
+	gateway.On("GetIdempotentResponse", "foo.wlt", "payout-1").Return(nil, nil)
+	gateway.On("WalletCreateTransactionSigned", "foo.wlt", []byte("pwd"), mock.Anything, mock.Anything).Return(nil, nil, wallet.ErrWalletNotExist)
+
+	body := `{"wallet_id": "foo.wlt", "password": "pwd", "hours_selection": {"type": "manual"}, "to": [{"address": "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "coins": "1", "hours": "1"}]}`
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet/transaction", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set(IdempotencyKeyHeader, "payout-1")
+	setCSRFParameters(t, tokenValid, req)
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(defaultMuxConfig(), gateway)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	gateway.AssertCalled(t, "GetIdempotentResponse", "foo.wlt", "payout-1")
+	gateway.AssertNotCalled(t, "SaveIdempotentResponse", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyLocks(t *testing.T) {
+	l := newIdempotencyLocks()
+	require.True(t, l.lock("foo.wlt\x00a"))
+	require.False(t, l.lock("foo.wlt\x00a"))
+	require.True(t, l.lock("bar.wlt\x00a"))
+
+	l.unlock("foo.wlt\x00a")
+	require.True(t, l.lock("foo.wlt\x00a"))
+}
@@ -513,18 +513,18 @@ func TestRecoverWallet(t *testing.T) {
 	require.NoError(t, err)
 
 	// Recover fails if the wallet is not encrypted
-	_, err = c.RecoverWallet(w.Meta.Filename, "fooseed", "")
+	_, err = c.RecoverWallet(w.Meta.Filename, "fooseed", "", "")
 	assertResponseError(t, err, http.StatusBadRequest, "wallet is not encrypted")
 
 	_, err = c.EncryptWallet(w.Meta.Filename, "pwd")
 	require.NoError(t, err)
 
 	// Recovery fails if the seed doesn't match
-	_, err = c.RecoverWallet(w.Meta.Filename, "wrongseed", "")
+	_, err = c.RecoverWallet(w.Meta.Filename, "wrongseed", "", "")
 	assertResponseError(t, err, http.StatusBadRequest, "wallet recovery seed is wrong")
 
 	// Successful recovery with no new password
-	w2, err := c.RecoverWallet(w.Meta.Filename, "fooseed", "")
+	w2, err := c.RecoverWallet(w.Meta.Filename, "fooseed", "", "")
 	require.NoError(t, err)
 	require.False(t, w2.Meta.Encrypted)
 	checkWalletOnDisk(w2)
@@ -534,7 +534,7 @@ func TestRecoverWallet(t *testing.T) {
 	require.NoError(t, err)
 
 	// Successful recovery with a new password
-	w3, err := c.RecoverWallet(w.Meta.Filename, "fooseed", "pwd3")
+	w3, err := c.RecoverWallet(w.Meta.Filename, "fooseed", "", "pwd3")
 	require.NoError(t, err)
 	require.True(t, w3.Meta.Encrypted)
 	require.Equal(t, w3.Meta.CryptoType, "scrypt-chacha20poly1305")
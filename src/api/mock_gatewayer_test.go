@@ -16,6 +16,52 @@ type MockGatewayer struct {
 	mock.Mock
 }
 
+// AddWalletAddressBookEntry provides a mock function with given fields: wltID, name, addr, note
+func (_m *MockGatewayer) AddWalletAddressBookEntry(wltID string, name string, addr string, note string) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, name, addr, note)
+
+	var r0 *wallet.Wallet
+	if rf, ok := ret.Get(0).(func(string, string, string, string) *wallet.Wallet); ok {
+		r0 = rf(wltID, name, addr, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(wltID, name, addr, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeWalletPassword provides a mock function with given fields: wltID, password, newPassword, cryptoType
+func (_m *MockGatewayer) ChangeWalletPassword(wltID string, password []byte, newPassword []byte, cryptoType wallet.CryptoType) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, password, newPassword, cryptoType)
+
+	var r0 *wallet.Wallet
+	if rf, ok := ret.Get(0).(func(string, []byte, []byte, wallet.CryptoType) *wallet.Wallet); ok {
+		r0 = rf(wltID, password, newPassword, cryptoType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, []byte, wallet.CryptoType) error); ok {
+		r1 = rf(wltID, password, newPassword, cryptoType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTransaction provides a mock function with given fields: p, wp
 func (_m *MockGatewayer) CreateTransaction(p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(p, wp)
@@ -131,6 +177,52 @@ func (_m *MockGatewayer) EncryptWallet(wltID string, password []byte) (*wallet.W
 	return r0, r1
 }
 
+// ExportWallets provides a mock function with given fields: wltIDs, password
+func (_m *MockGatewayer) ExportWallets(wltIDs []string, password []byte) ([]byte, error) {
+	ret := _m.Called(wltIDs, password)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func([]string, []byte) []byte); ok {
+		r0 = rf(wltIDs, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, []byte) error); ok {
+		r1 = rf(wltIDs, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FreezeOutputs provides a mock function with given fields: wltID, uxOuts, reason
+func (_m *MockGatewayer) FreezeOutputs(wltID string, uxOuts []cipher.SHA256, reason string) ([]wallet.FrozenOutput, error) {
+	ret := _m.Called(wltID, uxOuts, reason)
+
+	var r0 []wallet.FrozenOutput
+	if rf, ok := ret.Get(0).(func(string, []cipher.SHA256, string) []wallet.FrozenOutput); ok {
+		r0 = rf(wltID, uxOuts, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wallet.FrozenOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []cipher.SHA256, string) error); ok {
+		r1 = rf(wltID, uxOuts, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAddressCount provides a mock function with given fields:
 func (_m *MockGatewayer) GetAddressCount() (uint64, error) {
 	ret := _m.Called()
@@ -448,6 +540,22 @@ func (_m *MockGatewayer) GetDefaultConnections() []string {
 	return r0
 }
 
+// GetEvictedUnconfirmedTransactions provides a mock function with given fields:
+func (_m *MockGatewayer) GetEvictedUnconfirmedTransactions() []visor.EvictedTransaction {
+	ret := _m.Called()
+
+	var r0 []visor.EvictedTransaction
+	if rf, ok := ret.Get(0).(func() []visor.EvictedTransaction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]visor.EvictedTransaction)
+		}
+	}
+
+	return r0
+}
+
 // GetExchgConnection provides a mock function with given fields:
 func (_m *MockGatewayer) GetExchgConnection() []string {
 	ret := _m.Called()
@@ -464,6 +572,52 @@ func (_m *MockGatewayer) GetExchgConnection() []string {
 	return r0
 }
 
+// GetExpiredUnconfirmedTransactions provides a mock function with given fields:
+func (_m *MockGatewayer) GetExpiredUnconfirmedTransactions() ([]visor.ExpiredTransaction, error) {
+	ret := _m.Called()
+
+	var r0 []visor.ExpiredTransaction
+	if rf, ok := ret.Get(0).(func() []visor.ExpiredTransaction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]visor.ExpiredTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFrozenOutputs provides a mock function with given fields: wltID
+func (_m *MockGatewayer) GetFrozenOutputs(wltID string) ([]wallet.FrozenOutput, error) {
+	ret := _m.Called(wltID)
+
+	var r0 []wallet.FrozenOutput
+	if rf, ok := ret.Get(0).(func(string) []wallet.FrozenOutput); ok {
+		r0 = rf(wltID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wallet.FrozenOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(wltID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetHealth provides a mock function with given fields:
 func (_m *MockGatewayer) GetHealth() (*daemon.Health, error) {
 	ret := _m.Called()
@@ -487,6 +641,29 @@ func (_m *MockGatewayer) GetHealth() (*daemon.Health, error) {
 	return r0, r1
 }
 
+// GetIdempotentResponse provides a mock function with given fields: wltID, key
+func (_m *MockGatewayer) GetIdempotentResponse(wltID string, key string) (*visor.IdempotentResponse, error) {
+	ret := _m.Called(wltID, key)
+
+	var r0 *visor.IdempotentResponse
+	if rf, ok := ret.Get(0).(func(string, string) *visor.IdempotentResponse); ok {
+		r0 = rf(wltID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.IdempotentResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(wltID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBlocks provides a mock function with given fields: num
 func (_m *MockGatewayer) GetLastBlocks(num uint64) ([]coin.SignedBlock, error) {
 	ret := _m.Called(num)
@@ -542,6 +719,45 @@ func (_m *MockGatewayer) GetLastBlocksVerbose(num uint64) ([]coin.SignedBlock, [
 	return r0, r1, r2
 }
 
+// GetOutputReservations provides a mock function with given fields: wltID
+func (_m *MockGatewayer) GetOutputReservations(wltID string) ([]visor.OutputReservation, error) {
+	ret := _m.Called(wltID)
+
+	var r0 []visor.OutputReservation
+	if rf, ok := ret.Get(0).(func(string) []visor.OutputReservation); ok {
+		r0 = rf(wltID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]visor.OutputReservation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(wltID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRebroadcastTransactions provides a mock function with given fields:
+func (_m *MockGatewayer) GetRebroadcastTransactions() []daemon.RebroadcastTransaction {
+	ret := _m.Called()
+
+	var r0 []daemon.RebroadcastTransaction
+	if rf, ok := ret.Get(0).(func() []daemon.RebroadcastTransaction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]daemon.RebroadcastTransaction)
+		}
+	}
+
+	return r0
+}
+
 // GetRichlist provides a mock function with given fields: includeDistribution
 func (_m *MockGatewayer) GetRichlist(includeDistribution bool) (visor.Richlist, error) {
 	ret := _m.Called(includeDistribution)
@@ -721,6 +937,52 @@ func (_m *MockGatewayer) GetTransaction(txid cipher.SHA256) (*visor.Transaction,
 	return r0, r1
 }
 
+// GetTransactionLifecycle provides a mock function with given fields: txid
+func (_m *MockGatewayer) GetTransactionLifecycle(txid cipher.SHA256) (*visor.TransactionLifecycle, error) {
+	ret := _m.Called(txid)
+
+	var r0 *visor.TransactionLifecycle
+	if rf, ok := ret.Get(0).(func(cipher.SHA256) *visor.TransactionLifecycle); ok {
+		r0 = rf(txid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.TransactionLifecycle)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(cipher.SHA256) error); ok {
+		r1 = rf(txid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionLifecycles provides a mock function with given fields: wltID
+func (_m *MockGatewayer) GetTransactionLifecycles(wltID string) ([]visor.TransactionLifecycle, error) {
+	ret := _m.Called(wltID)
+
+	var r0 []visor.TransactionLifecycle
+	if rf, ok := ret.Get(0).(func(string) []visor.TransactionLifecycle); ok {
+		r0 = rf(wltID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]visor.TransactionLifecycle)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(wltID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionVerbose provides a mock function with given fields: txid
 func (_m *MockGatewayer) GetTransactionVerbose(txid cipher.SHA256) (*visor.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(txid)
@@ -1075,6 +1337,29 @@ func (_m *MockGatewayer) GetWallets() (wallet.Wallets, error) {
 	return r0, r1
 }
 
+// ImportWallets provides a mock function with given fields: backup, password
+func (_m *MockGatewayer) ImportWallets(backup []byte, password []byte) ([]*wallet.Wallet, error) {
+	ret := _m.Called(backup, password)
+
+	var r0 []*wallet.Wallet
+	if rf, ok := ret.Get(0).(func([]byte, []byte) []*wallet.Wallet); ok {
+		r0 = rf(backup, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte, []byte) error); ok {
+		r1 = rf(backup, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // InjectBroadcastTransaction provides a mock function with given fields: txn
 func (_m *MockGatewayer) InjectBroadcastTransaction(txn coin.Transaction) error {
 	ret := _m.Called(txn)
@@ -1112,13 +1397,13 @@ func (_m *MockGatewayer) NewAddresses(wltID string, password []byte, n uint64) (
 	return r0, r1
 }
 
-// RecoverWallet provides a mock function with given fields: wltID, seed, password
-func (_m *MockGatewayer) RecoverWallet(wltID string, seed string, password []byte) (*wallet.Wallet, error) {
-	ret := _m.Called(wltID, seed, password)
+// RecoverWallet provides a mock function with given fields: wltID, seed, seedPassphrase, password
+func (_m *MockGatewayer) RecoverWallet(wltID string, seed string, seedPassphrase string, password []byte) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, seed, seedPassphrase, password)
 
 	var r0 *wallet.Wallet
-	if rf, ok := ret.Get(0).(func(string, string, []byte) *wallet.Wallet); ok {
-		r0 = rf(wltID, seed, password)
+	if rf, ok := ret.Get(0).(func(string, string, string, []byte) *wallet.Wallet); ok {
+		r0 = rf(wltID, seed, seedPassphrase, password)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.Wallet)
@@ -1126,8 +1411,54 @@ func (_m *MockGatewayer) RecoverWallet(wltID string, seed string, password []byt
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, []byte) error); ok {
-		r1 = rf(wltID, seed, password)
+	if rf, ok := ret.Get(1).(func(string, string, string, []byte) error); ok {
+		r1 = rf(wltID, seed, seedPassphrase, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseOutputReservations provides a mock function with given fields: wltID, uxOuts
+func (_m *MockGatewayer) ReleaseOutputReservations(wltID string, uxOuts []cipher.SHA256) ([]visor.OutputReservation, error) {
+	ret := _m.Called(wltID, uxOuts)
+
+	var r0 []visor.OutputReservation
+	if rf, ok := ret.Get(0).(func(string, []cipher.SHA256) []visor.OutputReservation); ok {
+		r0 = rf(wltID, uxOuts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]visor.OutputReservation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []cipher.SHA256) error); ok {
+		r1 = rf(wltID, uxOuts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWalletAddressBookEntry provides a mock function with given fields: wltID, name
+func (_m *MockGatewayer) RemoveWalletAddressBookEntry(wltID string, name string) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, name)
+
+	var r0 *wallet.Wallet
+	if rf, ok := ret.Get(0).(func(string, string) *wallet.Wallet); ok {
+		r0 = rf(wltID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(wltID, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1158,6 +1489,43 @@ func (_m *MockGatewayer) ResendUnconfirmedTxns() ([]cipher.SHA256, error) {
 	return r0, r1
 }
 
+// SaveIdempotentResponse provides a mock function with given fields: wltID, key, r
+func (_m *MockGatewayer) SaveIdempotentResponse(wltID string, key string, r visor.IdempotentResponse) error {
+	ret := _m.Called(wltID, key, r)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, visor.IdempotentResponse) error); ok {
+		r0 = rf(wltID, key, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnfreezeOutputs provides a mock function with given fields: wltID, uxOuts
+func (_m *MockGatewayer) UnfreezeOutputs(wltID string, uxOuts []cipher.SHA256) ([]wallet.FrozenOutput, error) {
+	ret := _m.Called(wltID, uxOuts)
+
+	var r0 []wallet.FrozenOutput
+	if rf, ok := ret.Get(0).(func(string, []cipher.SHA256) []wallet.FrozenOutput); ok {
+		r0 = rf(wltID, uxOuts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]wallet.FrozenOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []cipher.SHA256) error); ok {
+		r1 = rf(wltID, uxOuts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnloadWallet provides a mock function with given fields: id
 func (_m *MockGatewayer) UnloadWallet(id string) error {
 	ret := _m.Called(id)
@@ -1172,6 +1540,52 @@ func (_m *MockGatewayer) UnloadWallet(id string) error {
 	return r0
 }
 
+// UpdateWalletAddressLabel provides a mock function with given fields: wltID, addr, label
+func (_m *MockGatewayer) UpdateWalletAddressLabel(wltID string, addr string, label string) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, addr, label)
+
+	var r0 *wallet.Wallet
+	if rf, ok := ret.Get(0).(func(string, string, string) *wallet.Wallet); ok {
+		r0 = rf(wltID, addr, label)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(wltID, addr, label)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWalletChangeAddressPolicy provides a mock function with given fields: wltID, policy
+func (_m *MockGatewayer) UpdateWalletChangeAddressPolicy(wltID string, policy string) (*wallet.Wallet, error) {
+	ret := _m.Called(wltID, policy)
+
+	var r0 *wallet.Wallet
+	if rf, ok := ret.Get(0).(func(string, string) *wallet.Wallet); ok {
+		r0 = rf(wltID, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(wltID, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateWalletLabel provides a mock function with given fields: wltID, label
 func (_m *MockGatewayer) UpdateWalletLabel(wltID string, label string) error {
 	ret := _m.Called(wltID, label)
@@ -1216,6 +1630,162 @@ func (_m *MockGatewayer) VerifyTxnVerbose(txn *coin.Transaction, signed visor.Tx
 	return r0, r1, r2
 }
 
+// WalletBumpTransaction provides a mock function with given fields: wltID, txid, fee
+func (_m *MockGatewayer) WalletBumpTransaction(wltID string, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error) {
+	ret := _m.Called(wltID, txid, fee)
+
+	var r0 *coin.Transaction
+	if rf, ok := ret.Get(0).(func(string, cipher.SHA256, uint64) *coin.Transaction); ok {
+		r0 = rf(wltID, txid, fee)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coin.Transaction)
+		}
+	}
+
+	var r1 []visor.TransactionInput
+	if rf, ok := ret.Get(1).(func(string, cipher.SHA256, uint64) []visor.TransactionInput); ok {
+		r1 = rf(wltID, txid, fee)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]visor.TransactionInput)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, cipher.SHA256, uint64) error); ok {
+		r2 = rf(wltID, txid, fee)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// WalletBumpTransactionSigned provides a mock function with given fields: wltID, password, txid, fee
+func (_m *MockGatewayer) WalletBumpTransactionSigned(wltID string, password []byte, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error) {
+	ret := _m.Called(wltID, password, txid, fee)
+
+	var r0 *coin.Transaction
+	if rf, ok := ret.Get(0).(func(string, []byte, cipher.SHA256, uint64) *coin.Transaction); ok {
+		r0 = rf(wltID, password, txid, fee)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coin.Transaction)
+		}
+	}
+
+	var r1 []visor.TransactionInput
+	if rf, ok := ret.Get(1).(func(string, []byte, cipher.SHA256, uint64) []visor.TransactionInput); ok {
+		r1 = rf(wltID, password, txid, fee)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]visor.TransactionInput)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, []byte, cipher.SHA256, uint64) error); ok {
+		r2 = rf(wltID, password, txid, fee)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// WalletCreateBatchTransaction provides a mock function with given fields: wltID, p, wp
+func (_m *MockGatewayer) WalletCreateBatchTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error) {
+	ret := _m.Called(wltID, p, wp)
+
+	var r0 *visor.Batch
+	if rf, ok := ret.Get(0).(func(string, transaction.Params, visor.CreateTransactionParams) *visor.Batch); ok {
+		r0 = rf(wltID, p, wp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, transaction.Params, visor.CreateTransactionParams) error); ok {
+		r1 = rf(wltID, p, wp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletCreateBatchTransactionSigned provides a mock function with given fields: wltID, password, p, wp
+func (_m *MockGatewayer) WalletCreateBatchTransactionSigned(wltID string, password []byte, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error) {
+	ret := _m.Called(wltID, password, p, wp)
+
+	var r0 *visor.Batch
+	if rf, ok := ret.Get(0).(func(string, []byte, transaction.Params, visor.CreateTransactionParams) *visor.Batch); ok {
+		r0 = rf(wltID, password, p, wp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.Batch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, transaction.Params, visor.CreateTransactionParams) error); ok {
+		r1 = rf(wltID, password, p, wp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletCreateConsolidation provides a mock function with given fields: wltID, p, wp
+func (_m *MockGatewayer) WalletCreateConsolidation(wltID string, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error) {
+	ret := _m.Called(wltID, p, wp)
+
+	var r0 *visor.Consolidation
+	if rf, ok := ret.Get(0).(func(string, transaction.ConsolidateParams, visor.CreateTransactionParams) *visor.Consolidation); ok {
+		r0 = rf(wltID, p, wp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.Consolidation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, transaction.ConsolidateParams, visor.CreateTransactionParams) error); ok {
+		r1 = rf(wltID, p, wp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletCreateConsolidationSigned provides a mock function with given fields: wltID, password, p, wp
+func (_m *MockGatewayer) WalletCreateConsolidationSigned(wltID string, password []byte, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error) {
+	ret := _m.Called(wltID, password, p, wp)
+
+	var r0 *visor.Consolidation
+	if rf, ok := ret.Get(0).(func(string, []byte, transaction.ConsolidateParams, visor.CreateTransactionParams) *visor.Consolidation); ok {
+		r0 = rf(wltID, password, p, wp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*visor.Consolidation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, transaction.ConsolidateParams, visor.CreateTransactionParams) error); ok {
+		r1 = rf(wltID, password, p, wp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WalletCreateTransaction provides a mock function with given fields: wltID, p, wp
 func (_m *MockGatewayer) WalletCreateTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*coin.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(wltID, p, wp)
@@ -1280,6 +1850,29 @@ func (_m *MockGatewayer) WalletCreateTransactionSigned(wltID string, password []
 	return r0, r1, r2
 }
 
+// WalletSignPartiallySignedTransaction provides a mock function with given fields: wltID, password, pst, signIndexes
+func (_m *MockGatewayer) WalletSignPartiallySignedTransaction(wltID string, password []byte, pst *wallet.PartiallySignedTransaction, signIndexes []int) (*wallet.PartiallySignedTransaction, error) {
+	ret := _m.Called(wltID, password, pst, signIndexes)
+
+	var r0 *wallet.PartiallySignedTransaction
+	if rf, ok := ret.Get(0).(func(string, []byte, *wallet.PartiallySignedTransaction, []int) *wallet.PartiallySignedTransaction); ok {
+		r0 = rf(wltID, password, pst, signIndexes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.PartiallySignedTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []byte, *wallet.PartiallySignedTransaction, []int) error); ok {
+		r1 = rf(wltID, password, pst, signIndexes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WalletSignTransaction provides a mock function with given fields: wltID, password, txn, signIndexes
 func (_m *MockGatewayer) WalletSignTransaction(wltID string, password []byte, txn *coin.Transaction, signIndexes []int) (*coin.Transaction, []visor.TransactionInput, error) {
 	ret := _m.Called(wltID, password, txn, signIndexes)
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// OutputReservation is an unspent output reserved by a transaction created by a wallet
+type OutputReservation struct {
+	UxID         string    `json:"uxid"`
+	WalletID     string    `json:"wallet_id"`
+	TxnInnerHash string    `json:"txn_inner_hash"`
+	Created      time.Time `json:"created"`
+	Expires      time.Time `json:"expires"`
+}
+
+// NewOutputReservations creates []OutputReservation from []visor.OutputReservation
+func NewOutputReservations(rs []visor.OutputReservation) []OutputReservation {
+	reservations := make([]OutputReservation, len(rs))
+	for i, r := range rs {
+		reservations[i] = OutputReservation{
+			UxID:         r.UxOut.Hex(),
+			WalletID:     r.WalletID,
+			TxnInnerHash: r.TxnInnerHash.Hex(),
+			Created:      r.Created,
+			Expires:      r.Expires,
+		}
+	}
+	return reservations
+}
+
+// outputReservationsErrorResponse creates the response of an output reservation request that failed
+func outputReservationsErrorResponse(err error) HTTPResponse {
+	switch err.(type) {
+	case wallet.Error:
+		switch err {
+		case wallet.ErrWalletNotExist:
+			return NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+		case wallet.ErrWalletAPIDisabled:
+			return NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+		default:
+			return NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		}
+	default:
+		return NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// walletOutputReservationsHandler returns the unspent outputs reserved by the transactions created by a wallet
+// with the reserve option, ordered by creation time
+// Method: GET
+// URI: /api/v2/wallet/reservations
+// Args:
+//	id: wallet id
+func walletOutputReservationsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		wltID := r.FormValue("id")
+		if wltID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "missing wallet id")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		rs, err := gateway.GetOutputReservations(wltID)
+		if err != nil {
+			writeHTTPResponse(w, outputReservationsErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewOutputReservations(rs),
+		})
+	}
+}
+
+// WalletReleaseOutputReservationsRequest is the request body object for /api/v2/wallet/reservations/release
+type WalletReleaseOutputReservationsRequest struct {
+	WalletID string      `json:"wallet_id"`
+	UxOuts   []wh.SHA256 `json:"unspents,omitempty"`
+}
+
+// walletReleaseOutputReservationsHandler releases the reservations of a wallet for a list of unspent outputs,
+// or all the reservations of the wallet if no unspent outputs are specified.
+// Returns the released reservations.
+// Method: POST
+// URI: /api/v2/wallet/reservations/release
+// Args: JSON body
+func walletReleaseOutputReservationsHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletReleaseOutputReservationsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.WalletID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "wallet_id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var uxOuts []cipher.SHA256
+		if len(req.UxOuts) != 0 {
+			uxOuts = make([]cipher.SHA256, len(req.UxOuts))
+			for i, o := range req.UxOuts {
+				uxOuts[i] = o.SHA256
+			}
+		}
+
+		rs, err := gateway.ReleaseOutputReservations(req.WalletID, uxOuts)
+		if err != nil {
+			writeHTTPResponse(w, outputReservationsErrorResponse(err))
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewOutputReservations(rs),
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	wh "github.com/skycoin/skycoin/src/util/http"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func makeOutputReservation(t *testing.T, wltID string) (visor.OutputReservation, OutputReservation) {
+	now := time.Unix(1540000000, 0).UTC()
+	r := visor.OutputReservation{
+		UxOut:        testutil.RandSHA256(t),
+		WalletID:     wltID,
+		TxnInnerHash: testutil.RandSHA256(t),
+		Created:      now,
+		Expires:      now.Add(10 * time.Minute),
+	}
+
+	return r, OutputReservation{
+		UxID:         r.UxOut.Hex(),
+		WalletID:     wltID,
+		TxnInnerHash: r.TxnInnerHash.Hex(),
+		Created:      now,
+		Expires:      now.Add(10 * time.Minute),
+	}
+}
+
+func TestWalletOutputReservations(t *testing.T) {
+	r1, rr1 := makeOutputReservation(t, "foo.wlt")
+	r2, rr2 := makeOutputReservation(t, "foo.wlt")
+
+	tt := []struct {
+		name         string
+		method       string
+		walletID     string
+		reservations []visor.OutputReservation
+		gatewayErr   error
+		status       int
+		err          string
+		data         []OutputReservation
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - missing wallet id",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "missing wallet id",
+		},
+		{
+			name:       "403 - wallet API disabled",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        wallet.ErrWalletAPIDisabled.Error(),
+		},
+		{
+			name:       "404 - wallet not found",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name:     "200 - none",
+			method:   http.MethodGet,
+			walletID: "foo.wlt",
+			status:   http.StatusOK,
+			data:     []OutputReservation{},
+		},
+		{
+			name:         "200",
+			method:       http.MethodGet,
+			walletID:     "foo.wlt",
+			reservations: []visor.OutputReservation{r1, r2},
+			status:       http.StatusOK,
+			data:         []OutputReservation{rr1, rr2},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetOutputReservations", tc.walletID).Return(tc.reservations, tc.gatewayErr)
+
+			v := url.Values{}
+			if tc.walletID != "" {
+				v.Add("id", tc.walletID)
+			}
+
+			status, rsp := doGetV2Request(t, gateway, tc.method, "/api/v2/wallet/reservations", v)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []OutputReservation
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
+
+func TestWalletReleaseOutputReservations(t *testing.T) {
+	r1, rr1 := makeOutputReservation(t, "foo.wlt")
+	r2, rr2 := makeOutputReservation(t, "foo.wlt")
+
+	tt := []struct {
+		name         string
+		body         *WalletReleaseOutputReservationsRequest
+		rawBody      string
+		uxOuts       []cipher.SHA256
+		reservations []visor.OutputReservation
+		gatewayErr   error
+		status       int
+		err          string
+		data         []OutputReservation
+	}{
+		{
+			name:    "400 - invalid body",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing wallet_id",
+			body:   &WalletReleaseOutputReservationsRequest{},
+			status: http.StatusBadRequest,
+			err:    "wallet_id is required",
+		},
+		{
+			name:    "400 - invalid unspents",
+			rawBody: `{"wallet_id":"foo.wlt","unspents":["foo"]}`,
+			status:  http.StatusBadRequest,
+			err:     "invalid SHA256 hash: encoding/hex: invalid byte: U+006F 'o'",
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletReleaseOutputReservationsRequest{
+				WalletID: "foo.wlt",
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        wallet.ErrWalletNotExist.Error(),
+		},
+		{
+			name: "500 - gateway error",
+			body: &WalletReleaseOutputReservationsRequest{
+				WalletID: "foo.wlt",
+			},
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name: "200 - all",
+			body: &WalletReleaseOutputReservationsRequest{
+				WalletID: "foo.wlt",
+			},
+			reservations: []visor.OutputReservation{r1, r2},
+			status:       http.StatusOK,
+			data:         []OutputReservation{rr1, rr2},
+		},
+		{
+			name: "200 - unspents",
+			body: &WalletReleaseOutputReservationsRequest{
+				WalletID: "foo.wlt",
+				UxOuts:   []wh.SHA256{{SHA256: r2.UxOut}},
+			},
+			uxOuts:       []cipher.SHA256{r2.UxOut},
+			reservations: []visor.OutputReservation{r2},
+			status:       http.StatusOK,
+			data:         []OutputReservation{rr2},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("ReleaseOutputReservations", "foo.wlt", tc.uxOuts).Return(tc.reservations, tc.gatewayErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/reservations/release", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []OutputReservation
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// CreatePartialTransactionRequest is the request body object for /api/v2/transaction/partial
+type CreatePartialTransactionRequest struct {
+	EncodedTransaction string                            `json:"encoded_transaction"`
+	MultisigInputs     []PartialTransactionMultisigInput `json:"multisig_inputs,omitempty"`
+	TimeLockInputs     []PartialTransactionTimeLockInput `json:"time_lock_inputs,omitempty"`
+}
+
+// PartialTransactionTimeLockInput describes the time-locked address that owns an input of a partially signed transaction
+type PartialTransactionTimeLockInput struct {
+	Index int    `json:"index"`
+	Owner string `json:"owner"`
+	Kind  string `json:"kind"`
+	Value uint64 `json:"value"`
+}
+
+// PartialTransactionMultisigInput describes the multisig address that owns an input of a partially signed transaction
+type PartialTransactionMultisigInput struct {
+	Index    int      `json:"index"`
+	Required int      `json:"required"`
+	PubKeys  []string `json:"public_keys"`
+}
+
+// createPartialTxnHandler creates a partially signed transaction from an unsigned or partially signed transaction.
+// The outputs spent by the transaction are looked up and included, so that it can be signed offline.
+// Method: POST
+// URI: /api/v2/transaction/partial
+// Args: JSON body
+func createPartialTxnHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req CreatePartialTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.EncodedTransaction == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "encoded_transaction is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txn, err := decodeTxn(req.EncodedTransaction)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("decode transaction failed: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		inputs, isTxnConfirmed, err := gateway.VerifyTxnVerbose(txn, visor.TxnUnsigned)
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case visor.ErrTxnViolatesSoftConstraint,
+				visor.ErrTxnViolatesHardConstraint,
+				visor.ErrTxnViolatesUserConstraint:
+				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if isTxnConfirmed {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "transaction has been spent")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if len(inputs) != len(txn.In) {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, "transaction inputs length mismatch")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		uxOuts := make([]coin.UxOut, len(inputs))
+		for i, in := range inputs {
+			uxOuts[i] = in.UxOut
+		}
+
+		pst, err := wallet.NewPartiallySignedTransaction(txn, uxOuts)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		// Time-lock scripts are set first, multisig inputs may refer to the owner of a time-locked input
+		for _, in := range req.TimeLockInputs {
+			owner, err := cipher.DecodeBase58Address(in.Owner)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("owner %q is invalid: %v", in.Owner, err))
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			kind, err := wallet.ParseTimeLockKind(in.Kind)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			if err := pst.SetTimeLockInput(in.Index, kind, in.Value, owner); err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		for _, in := range req.MultisigInputs {
+			pubKeys, err := parsePubKeys(in.PubKeys)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+
+			if err := pst.SetMultisigInput(in.Index, in.Required, pubKeys); err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		writePartiallySignedTransaction(w, pst)
+	}
+}
+
+// WalletSignPartialTransactionRequest is the request body object for /api/v2/wallet/transaction/partial/sign
+type WalletSignPartialTransactionRequest struct {
+	WalletID           string                                     `json:"wallet_id"`
+	Password           string                                     `json:"password"`
+	PartialTransaction *wallet.ReadablePartiallySignedTransaction `json:"partial_transaction"`
+	SignIndexes        []int                                      `json:"sign_indexes"`
+}
+
+// walletSignPartialTxnHandler signs the inputs of a partially signed transaction owned by a wallet
+// Method: POST
+// URI: /api/v2/wallet/transaction/partial/sign
+// Args: JSON body
+func walletSignPartialTxnHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != ContentTypeJSON {
+			resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var req WalletSignPartialTransactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.WalletID == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "wallet_id is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if req.PartialTransaction == nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "partial_transaction is required")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		pst, err := req.PartialTransaction.ToPartiallySignedTransaction()
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid partial_transaction: %v", err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		// Check that number of sign_indexes does not exceed number of inputs
+		if len(req.SignIndexes) > len(pst.Inputs) {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "Too many values in sign_indexes")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		// Check that values in sign_indexes are in the range of txn inputs
+		for _, i := range req.SignIndexes {
+			if i < 0 || i >= len(pst.Inputs) {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "Value in sign_indexes exceeds range of transaction inputs array")
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		// Check for duplicate values in sign_indexes
+		signIndexesMap := make(map[int]struct{}, len(req.SignIndexes))
+		for _, i := range req.SignIndexes {
+			if _, ok := signIndexesMap[i]; ok {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "Duplicate value in sign_indexes")
+				writeHTTPResponse(w, resp)
+				return
+			}
+			signIndexesMap[i] = struct{}{}
+		}
+
+		signedPst, err := gateway.WalletSignPartiallySignedTransaction(req.WalletID, []byte(req.Password), pst, req.SignIndexes)
+		if err != nil {
+			var resp HTTPResponse
+			switch err.(type) {
+			case wallet.Error:
+				switch err {
+				case wallet.ErrWalletNotExist:
+					resp = NewHTTPErrorResponse(http.StatusNotFound, err.Error())
+				case wallet.ErrWalletAPIDisabled:
+					resp = NewHTTPErrorResponse(http.StatusForbidden, err.Error())
+				default:
+					resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+				}
+			default:
+				resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			}
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writePartiallySignedTransaction(w, signedPst)
+	}
+}
+
+// CombinePartialTransactionsRequest is the request body object for /api/v2/transaction/partial/combine
+type CombinePartialTransactionsRequest struct {
+	PartialTransactions []wallet.ReadablePartiallySignedTransaction `json:"partial_transactions"`
+}
+
+// combinePartialTxnsHandler merges the signatures of partially signed transactions of the same transaction
+// Method: POST
+// URI: /api/v2/transaction/partial/combine
+// Args: JSON body
+func combinePartialTxnsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	var req CombinePartialTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if len(req.PartialTransactions) == 0 {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "partial_transactions is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	psts := make([]*wallet.PartiallySignedTransaction, len(req.PartialTransactions))
+	for i, rpst := range req.PartialTransactions {
+		pst, err := rpst.ToPartiallySignedTransaction()
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid partial_transactions[%d]: %v", i, err))
+			writeHTTPResponse(w, resp)
+			return
+		}
+		psts[i] = pst
+	}
+
+	pst, err := wallet.CombinePartiallySignedTransactions(psts...)
+	if err != nil {
+		var resp HTTPResponse
+		switch err.(type) {
+		case wallet.Error:
+			resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		default:
+			resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writePartiallySignedTransaction(w, pst)
+}
+
+// FinalizePartialTransactionRequest is the request body object for /api/v2/transaction/partial/finalize
+type FinalizePartialTransactionRequest struct {
+	PartialTransaction *wallet.ReadablePartiallySignedTransaction `json:"partial_transaction"`
+}
+
+// FinalizePartialTransactionResponse is the response data for /api/v2/transaction/partial/finalize
+type FinalizePartialTransactionResponse struct {
+	TxID               string `json:"txid"`
+	EncodedTransaction string `json:"encoded_transaction"`
+}
+
+// finalizePartialTxnHandler extracts the fully signed transaction from a partially signed transaction.
+// The returned encoded transaction can be broadcast with /api/v1/injectTransaction.
+// Method: POST
+// URI: /api/v2/transaction/partial/finalize
+// Args: JSON body
+func finalizePartialTxnHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != ContentTypeJSON {
+		resp := NewHTTPErrorResponse(http.StatusUnsupportedMediaType, "")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	var req FinalizePartialTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	if req.PartialTransaction == nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, "partial_transaction is required")
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	pst, err := req.PartialTransaction.ToPartiallySignedTransaction()
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid partial_transaction: %v", err))
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	txn, err := pst.Finalize()
+	if err != nil {
+		var resp HTTPResponse
+		switch err.(type) {
+		case wallet.Error:
+			resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
+		default:
+			resp = NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	txnHex, err := txn.SerializeHex()
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: FinalizePartialTransactionResponse{
+			TxID:               txn.Hash().Hex(),
+			EncodedTransaction: txnHex,
+		},
+	})
+}
+
+func writePartiallySignedTransaction(w http.ResponseWriter, pst *wallet.PartiallySignedTransaction) {
+	rpst, err := wallet.NewReadablePartiallySignedTransaction(pst)
+	if err != nil {
+		resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+		writeHTTPResponse(w, resp)
+		return
+	}
+
+	writeHTTPResponse(w, HTTPResponse{
+		Data: rpst,
+	})
+}
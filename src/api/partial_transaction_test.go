@@ -0,0 +1,454 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// makePartiallySignedTransactions creates a transaction spending one output of each wallet,
+// returning it unsigned, signed by the first wallet and signed by both wallets
+func makePartiallySignedTransactions(t *testing.T) (*coin.Transaction, []visor.TransactionInput, []*wallet.PartiallySignedTransaction) {
+	var wlts []*wallet.Wallet
+	for _, seed := range []string{"seed1", "seed2"} {
+		w, err := wallet.NewWallet(seed+".wlt", wallet.Options{
+			Seed: seed,
+		})
+		require.NoError(t, err)
+		wlts = append(wlts, w)
+	}
+
+	var txn coin.Transaction
+	var inputs []visor.TransactionInput
+	var uxOuts []coin.UxOut
+	for _, w := range wlts {
+		ux := coin.UxOut{
+			Head: coin.UxHead{
+				Time:  uint64(time.Now().UTC().Unix()),
+				BkSeq: 9999,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        w.Entries[0].SkycoinAddress(),
+				Coins:          1e6,
+				Hours:          100,
+			},
+		}
+		uxOuts = append(uxOuts, ux)
+		inputs = append(inputs, visor.TransactionInput{
+			UxOut:           ux,
+			CalculatedHours: 200,
+		})
+		require.NoError(t, txn.PushInput(ux.Hash()))
+	}
+
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), 2e6, 100))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+
+	pst, err := wallet.NewPartiallySignedTransaction(&txn, uxOuts)
+	require.NoError(t, err)
+	pst1, err := wlts[0].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	pst2, err := wlts[1].SignPartiallySignedTransaction(pst1, nil)
+	require.NoError(t, err)
+
+	return &txn, inputs, []*wallet.PartiallySignedTransaction{pst, pst1, pst2}
+}
+
+func mustNewReadablePartiallySignedTransaction(t *testing.T, pst *wallet.PartiallySignedTransaction) *wallet.ReadablePartiallySignedTransaction {
+	rpst, err := wallet.NewReadablePartiallySignedTransaction(pst)
+	require.NoError(t, err)
+	return rpst
+}
+
+func doPartialTxnRequest(t *testing.T, gateway *MockGatewayer, endpoint string, body interface{}, rawBody string) (int, ReceivedHTTPResponse) {
+	bodyText := []byte(rawBody)
+	if len(bodyText) == 0 {
+		var err error
+		bodyText, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(bodyText))
+	require.NoError(t, err)
+	req.Header.Add("Content-Type", ContentTypeJSON)
+	setCSRFParameters(t, tokenValid, req)
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(defaultMuxConfig(), gateway)
+	handler.ServeHTTP(rr, req)
+
+	var rsp ReceivedHTTPResponse
+	err = json.NewDecoder(rr.Body).Decode(&rsp)
+	require.NoError(t, err)
+
+	return rr.Code, rsp
+}
+
+func TestCreatePartialTransaction(t *testing.T) {
+	txn, inputs, psts := makePartiallySignedTransactions(t)
+	rpst := mustNewReadablePartiallySignedTransaction(t, psts[0])
+
+	tt := []struct {
+		name            string
+		body            *CreatePartialTransactionRequest
+		rawBody         string
+		verifyInputs    []visor.TransactionInput
+		verifyConfirmed bool
+		verifyErr       error
+		status          int
+		err             string
+		data            *wallet.ReadablePartiallySignedTransaction
+	}{
+		{
+			name:    "400 - invalid json",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name:   "400 - missing encoded_transaction",
+			body:   &CreatePartialTransactionRequest{},
+			status: http.StatusBadRequest,
+			err:    "encoded_transaction is required",
+		},
+		{
+			name: "400 - invalid transaction",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: "abcd",
+			},
+			status: http.StatusBadRequest,
+			err:    "decode transaction failed: Invalid transaction: Not enough buffer data to deserialize",
+		},
+		{
+			name: "400 - transaction violates constraints",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+			},
+			verifyErr: visor.NewErrTxnViolatesHardConstraint(errors.New("bad txn")),
+			status:    http.StatusBadRequest,
+			err:       "Transaction violates hard constraint: bad txn",
+		},
+		{
+			name: "400 - transaction has been spent",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+			},
+			verifyInputs:    inputs,
+			verifyConfirmed: true,
+			status:          http.StatusBadRequest,
+			err:             "transaction has been spent",
+		},
+		{
+			name: "500 - verify failed",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+			},
+			verifyErr: errors.New("db error"),
+			status:    http.StatusInternalServerError,
+			err:       "db error",
+		},
+		{
+			name: "400 - invalid multisig public key",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+				MultisigInputs: []PartialTransactionMultisigInput{
+					{
+						Index:    0,
+						Required: 1,
+						PubKeys:  []string{"abcd"},
+					},
+				},
+			},
+			verifyInputs: inputs,
+			status:       http.StatusBadRequest,
+			err:          `public key "abcd" is invalid: Invalid public key length`,
+		},
+		{
+			name: "400 - input is not multisig",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+				MultisigInputs: []PartialTransactionMultisigInput{
+					{
+						Index:    0,
+						Required: 1,
+						PubKeys:  []string{testutil.MakePubKey().Hex()},
+					},
+				},
+			},
+			verifyInputs: inputs,
+			status:       http.StatusBadRequest,
+			err:          "input 0 is not owned by a multisig address",
+		},
+		{
+			name: "400 - invalid time-lock kind",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+				TimeLockInputs: []PartialTransactionTimeLockInput{
+					{
+						Index: 0,
+						Owner: testutil.MakeAddress().String(),
+						Kind:  "block",
+						Value: 10,
+					},
+				},
+			},
+			verifyInputs: inputs,
+			status:       http.StatusBadRequest,
+			err:          `invalid time-lock kind "block", must be "height" or "time"`,
+		},
+		{
+			name: "400 - input is not time-locked",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+				TimeLockInputs: []PartialTransactionTimeLockInput{
+					{
+						Index: 0,
+						Owner: testutil.MakeAddress().String(),
+						Kind:  wallet.TimeLockKindHeight,
+						Value: 10,
+					},
+				},
+			},
+			verifyInputs: inputs,
+			status:       http.StatusBadRequest,
+			err:          "input 0 is not owned by a time-locked address",
+		},
+		{
+			name: "200",
+			body: &CreatePartialTransactionRequest{
+				EncodedTransaction: txn.MustSerializeHex(),
+			},
+			verifyInputs: inputs,
+			status:       http.StatusOK,
+			data:         rpst,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("VerifyTxnVerbose", txn, visor.TxnUnsigned).Return(tc.verifyInputs, tc.verifyConfirmed, tc.verifyErr)
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/transaction/partial", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data wallet.ReadablePartiallySignedTransaction
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
+
+func TestWalletSignPartialTransaction(t *testing.T) {
+	_, _, psts := makePartiallySignedTransactions(t)
+	rpst := mustNewReadablePartiallySignedTransaction(t, psts[0])
+	rpst1 := mustNewReadablePartiallySignedTransaction(t, psts[1])
+
+	tt := []struct {
+		name       string
+		body       *WalletSignPartialTransactionRequest
+		rawBody    string
+		gatewayPst *wallet.PartiallySignedTransaction
+		gatewayErr error
+		status     int
+		err        string
+		data       *wallet.ReadablePartiallySignedTransaction
+	}{
+		{
+			name:    "400 - invalid json",
+			rawBody: "{",
+			status:  http.StatusBadRequest,
+			err:     "unexpected EOF",
+		},
+		{
+			name: "400 - missing wallet_id",
+			body: &WalletSignPartialTransactionRequest{
+				PartialTransaction: rpst,
+			},
+			status: http.StatusBadRequest,
+			err:    "wallet_id is required",
+		},
+		{
+			name: "400 - missing partial_transaction",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID: "foo.wlt",
+			},
+			status: http.StatusBadRequest,
+			err:    "partial_transaction is required",
+		},
+		{
+			name: "400 - invalid partial_transaction",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID: "foo.wlt",
+				PartialTransaction: &wallet.ReadablePartiallySignedTransaction{
+					Version: 2,
+				},
+			},
+			status: http.StatusBadRequest,
+			err:    "invalid partial_transaction: unsupported partially signed transaction version",
+		},
+		{
+			name: "400 - sign_indexes out of range",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				PartialTransaction: rpst,
+				SignIndexes:        []int{2},
+			},
+			status: http.StatusBadRequest,
+			err:    "Value in sign_indexes exceeds range of transaction inputs array",
+		},
+		{
+			name: "400 - duplicate sign_indexes",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				PartialTransaction: rpst,
+				SignIndexes:        []int{1, 1},
+			},
+			status: http.StatusBadRequest,
+			err:    "Duplicate value in sign_indexes",
+		},
+		{
+			name: "400 - no inputs to sign",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				PartialTransaction: rpst,
+			},
+			gatewayErr: wallet.ErrNoInputsToSign,
+			status:     http.StatusBadRequest,
+			err:        "wallet does not own any unsigned inputs",
+		},
+		{
+			name: "403 - wallet API disabled",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				PartialTransaction: rpst,
+			},
+			gatewayErr: wallet.ErrWalletAPIDisabled,
+			status:     http.StatusForbidden,
+			err:        "wallet api is disabled",
+		},
+		{
+			name: "404 - wallet not found",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				PartialTransaction: rpst,
+			},
+			gatewayErr: wallet.ErrWalletNotExist,
+			status:     http.StatusNotFound,
+			err:        "wallet doesn't exist",
+		},
+		{
+			name: "200",
+			body: &WalletSignPartialTransactionRequest{
+				WalletID:           "foo.wlt",
+				Password:           "pwd",
+				PartialTransaction: rpst,
+				SignIndexes:        []int{0},
+			},
+			gatewayPst: psts[1],
+			status:     http.StatusOK,
+			data:       rpst1,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			if tc.body != nil {
+				gateway.On("WalletSignPartiallySignedTransaction", tc.body.WalletID, []byte(tc.body.Password), psts[0], tc.body.SignIndexes).Return(tc.gatewayPst, tc.gatewayErr)
+			}
+
+			status, rsp := doPartialTxnRequest(t, gateway, "/api/v2/wallet/transaction/partial/sign", tc.body, tc.rawBody)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data wallet.ReadablePartiallySignedTransaction
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
+
+func TestCombineAndFinalizePartialTransactions(t *testing.T) {
+	_, _, psts := makePartiallySignedTransactions(t)
+	_, _, otherPsts := makePartiallySignedTransactions(t)
+
+	// Split the signatures of the fully signed transaction, to be combined
+	pst2Only := *psts[2]
+	pst2Only.Transaction.Sigs = []cipher.Sig{{}, psts[2].Transaction.Sigs[1]}
+	pst2Only.Inputs = []wallet.PartiallySignedInput{psts[0].Inputs[0], psts[2].Inputs[1]}
+
+	rpst := mustNewReadablePartiallySignedTransaction(t, psts[0])
+	rpst1 := mustNewReadablePartiallySignedTransaction(t, psts[1])
+	rpst2 := mustNewReadablePartiallySignedTransaction(t, psts[2])
+	rpst2Only := mustNewReadablePartiallySignedTransaction(t, &pst2Only)
+	rOther := mustNewReadablePartiallySignedTransaction(t, otherPsts[0])
+
+	// Combine
+	status, rsp := doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/combine", CombinePartialTransactionsRequest{}, "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "partial_transactions is required", rsp.Error.Message)
+
+	status, rsp = doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/combine", CombinePartialTransactionsRequest{
+		PartialTransactions: []wallet.ReadablePartiallySignedTransaction{*rpst1, *rOther},
+	}, "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "partially signed transactions do not spend the same transaction", rsp.Error.Message)
+
+	status, rsp = doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/combine", CombinePartialTransactionsRequest{
+		PartialTransactions: []wallet.ReadablePartiallySignedTransaction{*rpst1, *rpst2Only},
+	}, "")
+	require.Equal(t, http.StatusOK, status)
+	require.Nil(t, rsp.Error)
+	var combined wallet.ReadablePartiallySignedTransaction
+	require.NoError(t, json.Unmarshal(rsp.Data, &combined))
+	require.Equal(t, *rpst2, combined)
+
+	// Finalize
+	status, rsp = doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/finalize", FinalizePartialTransactionRequest{}, "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "partial_transaction is required", rsp.Error.Message)
+
+	status, rsp = doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/finalize", FinalizePartialTransactionRequest{
+		PartialTransaction: rpst,
+	}, "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "partially signed transaction is not fully signed", rsp.Error.Message)
+
+	status, rsp = doPartialTxnRequest(t, &MockGatewayer{}, "/api/v2/transaction/partial/finalize", FinalizePartialTransactionRequest{
+		PartialTransaction: &combined,
+	}, "")
+	require.Equal(t, http.StatusOK, status)
+	require.Nil(t, rsp.Error)
+	var finalized FinalizePartialTransactionResponse
+	require.NoError(t, json.Unmarshal(rsp.Data, &finalized))
+	require.Equal(t, psts[2].Transaction.Hash().Hex(), finalized.TxID)
+	require.Equal(t, psts[2].Transaction.MustSerializeHex(), finalized.EncodedTransaction)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/daemon"
+)
+
+// RebroadcastAttempt is an attempt to broadcast a transaction injected by this node
+type RebroadcastAttempt struct {
+	Time  time.Time `json:"time"`
+	Peers int       `json:"peers"`
+	Error string    `json:"error,omitempty"`
+}
+
+// RebroadcastTransaction is a transaction injected by this node with its broadcast attempts
+type RebroadcastTransaction struct {
+	TxID        string               `json:"txid"`
+	Injected    time.Time            `json:"injected"`
+	Status      string               `json:"status"`
+	Finished    *time.Time           `json:"finished,omitempty"`
+	NextAttempt *time.Time           `json:"next_attempt,omitempty"`
+	Attempts    []RebroadcastAttempt `json:"attempts"`
+}
+
+// NewRebroadcastTransaction creates a RebroadcastTransaction from daemon.RebroadcastTransaction
+func NewRebroadcastTransaction(txn daemon.RebroadcastTransaction) RebroadcastTransaction {
+	r := RebroadcastTransaction{
+		TxID:     txn.Hash.Hex(),
+		Injected: txn.Injected,
+		Status:   txn.Status,
+		Attempts: make([]RebroadcastAttempt, len(txn.Attempts)),
+	}
+
+	if !txn.Finished.IsZero() {
+		finished := txn.Finished
+		r.Finished = &finished
+	}
+
+	if !txn.NextAttempt.IsZero() {
+		nextAttempt := txn.NextAttempt
+		r.NextAttempt = &nextAttempt
+	}
+
+	for i, a := range txn.Attempts {
+		r.Attempts[i] = RebroadcastAttempt{
+			Time:  a.Time,
+			Peers: a.Peers,
+			Error: a.Error,
+		}
+	}
+
+	return r
+}
+
+// transactionRebroadcastHandler returns the transactions injected by this node and their broadcast attempts.
+// Transactions injected with POST /api/v1/injectTransaction are rebroadcast with exponential backoff
+// until they are confirmed or leave the unconfirmed pool.
+// Method: GET
+// URI: /api/v2/transaction/rebroadcast
+// Args:
+//	txid: [optional] return only this transaction
+func transactionRebroadcastHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		var txid cipher.SHA256
+		if txidStr := r.FormValue("txid"); txidStr != "" {
+			var err error
+			txid, err = cipher.SHA256FromHex(txidStr)
+			if err != nil {
+				resp := NewHTTPErrorResponse(http.StatusBadRequest, "invalid txid")
+				writeHTTPResponse(w, resp)
+				return
+			}
+		}
+
+		txns := []RebroadcastTransaction{}
+		for _, txn := range gateway.GetRebroadcastTransactions() {
+			if !txid.Null() && txn.Hash != txid {
+				continue
+			}
+			txns = append(txns, NewRebroadcastTransaction(txn))
+		}
+
+		if !txid.Null() && len(txns) == 0 {
+			resp := NewHTTPErrorResponse(http.StatusNotFound, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: txns,
+		})
+	}
+}
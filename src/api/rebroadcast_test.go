@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/daemon"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestTransactionRebroadcast(t *testing.T) {
+	now := time.Unix(1540000000, 0).UTC()
+	txns := []daemon.RebroadcastTransaction{
+		{
+			Hash:        testutil.RandSHA256(t),
+			Injected:    now,
+			Status:      daemon.RebroadcastStatusPending,
+			NextAttempt: now.Add(2 * time.Minute),
+			Attempts: []daemon.RebroadcastAttempt{
+				{
+					Time:  now,
+					Peers: 3,
+				},
+				{
+					Time:  now.Add(time.Minute),
+					Error: "transaction is invalid and was not broadcast",
+				},
+			},
+		},
+		{
+			Hash:     testutil.RandSHA256(t),
+			Injected: now.Add(time.Second),
+			Status:   daemon.RebroadcastStatusConfirmed,
+			Finished: now.Add(time.Hour),
+			Attempts: []daemon.RebroadcastAttempt{
+				{
+					Time:  now.Add(time.Second),
+					Peers: 1,
+				},
+			},
+		},
+	}
+
+	nextAttempt := now.Add(2 * time.Minute)
+	finished := now.Add(time.Hour)
+	pending := RebroadcastTransaction{
+		TxID:        txns[0].Hash.Hex(),
+		Injected:    now,
+		Status:      "pending",
+		NextAttempt: &nextAttempt,
+		Attempts: []RebroadcastAttempt{
+			{
+				Time:  now,
+				Peers: 3,
+			},
+			{
+				Time:  now.Add(time.Minute),
+				Error: "transaction is invalid and was not broadcast",
+			},
+		},
+	}
+	confirmed := RebroadcastTransaction{
+		TxID:     txns[1].Hash.Hex(),
+		Injected: now.Add(time.Second),
+		Status:   "confirmed",
+		Finished: &finished,
+		Attempts: []RebroadcastAttempt{
+			{
+				Time:  now.Add(time.Second),
+				Peers: 1,
+			},
+		},
+	}
+
+	tt := []struct {
+		name   string
+		method string
+		txid   string
+		status int
+		err    string
+		data   []RebroadcastTransaction
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - invalid txid",
+			method: http.MethodGet,
+			txid:   "foo",
+			status: http.StatusBadRequest,
+			err:    "invalid txid",
+		},
+		{
+			name:   "404 - txid not tracked",
+			method: http.MethodGet,
+			txid:   testutil.RandSHA256(t).Hex(),
+			status: http.StatusNotFound,
+			err:    "Not Found",
+		},
+		{
+			name:   "200 - all",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			data:   []RebroadcastTransaction{pending, confirmed},
+		},
+		{
+			name:   "200 - txid",
+			method: http.MethodGet,
+			txid:   txns[1].Hash.Hex(),
+			status: http.StatusOK,
+			data:   []RebroadcastTransaction{confirmed},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetRebroadcastTransactions").Return(txns)
+
+			v := url.Values{}
+			if tc.txid != "" {
+				v.Add("txid", tc.txid)
+			}
+
+			status, rsp := doGetV2Request(t, gateway, tc.method, "/api/v2/transaction/rebroadcast", v)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []RebroadcastTransaction
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
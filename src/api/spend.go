@@ -268,6 +268,7 @@ type createTransactionRequest struct {
 	To                []receiver     `json:"to"`
 	UxOuts            []wh.SHA256    `json:"unspents,omitempty"`
 	Addresses         []wh.Address   `json:"addresses,omitempty"`
+	CoinSelection     string         `json:"coin_selection,omitempty"`
 }
 
 // hoursSelection defines options for hours distribution
@@ -340,6 +341,10 @@ func (r createTransactionRequest) Validate() error {
 		}
 	}
 
+	if _, err := transaction.GetCoinSelector(r.CoinSelection); err != nil {
+		return errors.New("invalid coin_selection")
+	}
+
 	if len(r.UxOuts) != 0 && len(r.Addresses) != 0 {
 		return errors.New("unspents and addresses cannot be combined")
 	}
@@ -442,6 +447,7 @@ func (r createTransactionRequest) TransactionParams() transaction.Params {
 		},
 		ChangeAddress: changeAddress,
 		To:            to,
+		CoinSelection: r.CoinSelection,
 	}
 }
 
@@ -551,6 +557,7 @@ type walletCreateTransactionRequest struct {
 	Unsigned bool   `json:"unsigned"`
 	WalletID string `json:"wallet_id"`
 	Password string `json:"password"`
+	Reserve  bool   `json:"reserve"`
 	createTransactionRequest
 }
 
@@ -567,6 +574,13 @@ func (r walletCreateTransactionRequest) Validate() error {
 	return r.createTransactionRequest.Validate()
 }
 
+// VisorParams converts walletCreateTransactionRequest to visor.CreateTransactionParams
+func (r walletCreateTransactionRequest) VisorParams() visor.CreateTransactionParams {
+	p := r.createTransactionRequest.VisorParams()
+	p.Reserve = r.Reserve
+	return p
+}
+
 // walletCreateTransactionHandler creates a signed transaction
 // Method: POST
 // URI: /api/v1/wallet/transaction
@@ -42,6 +42,7 @@ type rawCreateTxnRequest struct {
 	ChangeAddress  string            `json:"change_address,omitempty"`
 	To             []rawReceiver     `json:"to"`
 	Password       string            `json:"password"`
+	CoinSelection  string            `json:"coin_selection,omitempty"`
 }
 
 func TestCreateTransaction(t *testing.T) {
@@ -698,6 +699,54 @@ func TestCreateTransaction(t *testing.T) {
 			},
 		},
 
+		{
+			name:   "400 - invalid coin selection",
+			method: http.MethodPost,
+			body: &rawCreateTxnRequest{
+				HoursSelection: rawHoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				},
+				To: []rawReceiver{
+					{
+						Address: destinationAddress.String(),
+						Coins:   "100",
+						Hours:   "10",
+					},
+				},
+				ChangeAddress: changeAddress.String(),
+				Addresses:     []string{changeAddress.String()},
+				CoinSelection: "foo",
+			},
+			status:       http.StatusBadRequest,
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, "invalid coin_selection"),
+		},
+
+		{
+			name:   "200 - manual type nonzero hours, exact match coin selection",
+			method: http.MethodPost,
+			body: &rawCreateTxnRequest{
+				HoursSelection: rawHoursSelection{
+					Type: transaction.HoursSelectionTypeManual,
+				},
+				To: []rawReceiver{
+					{
+						Address: destinationAddress.String(),
+						Coins:   "100",
+						Hours:   "10",
+					},
+				},
+				ChangeAddress: changeAddress.String(),
+				Addresses:     []string{changeAddress.String()},
+				CoinSelection: transaction.CoinSelectionExactMatch,
+			},
+			status:                         http.StatusOK,
+			gatewayCreateTransactionResult: txn,
+			gatewayCreateTransactionInputs: inputs,
+			httpResponse: HTTPResponse{
+				Data: createTxnResponse,
+			},
+		},
+
 		{
 			name:   "200 - manual type nonzero hours",
 			method: http.MethodPost,
@@ -24,6 +24,8 @@ import (
 // URI: /api/v1/pendingTxs
 // Args:
 //	verbose: [bool] include verbose transaction input data
+//	evicted: [bool] return the transactions recently evicted from the full pool instead
+//	expired: [bool] return the transactions expired from the pool instead
 func pendingTxnsHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -37,7 +39,51 @@ func pendingTxnsHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
-		if verbose {
+		evicted, err := parseBoolFlag(r.FormValue("evicted"))
+		if err != nil {
+			wh.Error400(w, "Invalid value for evicted")
+			return
+		}
+
+		expired, err := parseBoolFlag(r.FormValue("expired"))
+		if err != nil {
+			wh.Error400(w, "Invalid value for expired")
+			return
+		}
+
+		if verbose && evicted {
+			wh.Error400(w, "verbose and evicted cannot be combined")
+			return
+		}
+
+		if expired && (verbose || evicted) {
+			wh.Error400(w, "expired cannot be combined with verbose or evicted")
+			return
+		}
+
+		if expired {
+			expiredTxns, err := gateway.GetExpiredUnconfirmedTransactions()
+			if err != nil {
+				wh.Error500(w, err.Error())
+				return
+			}
+
+			ret, err := readable.NewExpiredTransactions(expiredTxns)
+			if err != nil {
+				wh.Error500(w, err.Error())
+				return
+			}
+
+			wh.SendJSONOr500(logger, w, ret)
+		} else if evicted {
+			ret, err := readable.NewEvictedTransactions(gateway.GetEvictedUnconfirmedTransactions())
+			if err != nil {
+				wh.Error500(w, err.Error())
+				return
+			}
+
+			wh.SendJSONOr500(logger, w, ret)
+		} else if verbose {
 			txns, inputs, err := gateway.GetAllUnconfirmedTransactionsVerbose()
 			if err != nil {
 				wh.Error500(w, err.Error())
@@ -333,9 +379,9 @@ func transactionsHandler(gateway Gatewayer) http.HandlerFunc {
 // Body: {"rawtx": "<hex encoded transaction>"}
 // Response:
 //      200 - ok, returns the transaction hash in hex as string
-//      400 - bad transaction
+//      400 - bad transaction, or the transaction was replaced by a transaction that burns more coin hours
 //		500 - other error
-//      503 - network unavailable for broadcasting transaction
+//      503 - network unavailable for broadcasting transaction, or the unconfirmed pool is full
 func injectTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -364,8 +410,10 @@ func injectTransactionHandler(gateway Gatewayer) http.HandlerFunc {
 		}
 
 		if err := gateway.InjectBroadcastTransaction(txn); err != nil {
-			if daemon.IsBroadcastFailure(err) {
+			if daemon.IsBroadcastFailure(err) || err == visor.ErrUnconfirmedPoolFull || err == visor.ErrUnconfirmedChainTooLong {
 				wh.Error503(w, err.Error())
+			} else if err == visor.ErrTxnReplaced {
+				wh.Error400(w, err.Error())
 			} else {
 				wh.Error500(w, err.Error())
 			}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+// TransactionLifecycleEvent is a change of state of a transaction lifecycle
+type TransactionLifecycleEvent struct {
+	State    string    `json:"state"`
+	Time     time.Time `json:"time"`
+	Peers    int       `json:"peers,omitempty"`
+	BlockSeq uint64    `json:"block_seq,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// TransactionLifecycle is the state of a transaction submitted through the API, with the history of its state changes
+type TransactionLifecycle struct {
+	TxID          string                      `json:"txid"`
+	WalletID      string                      `json:"wallet_id,omitempty"`
+	State         string                      `json:"state"`
+	Peers         int                         `json:"peers"`
+	BlockSeq      uint64                      `json:"block_seq"`
+	Confirmations uint64                      `json:"confirmations"`
+	Reason        string                      `json:"reason,omitempty"`
+	Created       time.Time                   `json:"created"`
+	Updated       time.Time                   `json:"updated"`
+	Events        []TransactionLifecycleEvent `json:"events"`
+}
+
+// NewTransactionLifecycle creates a TransactionLifecycle from visor.TransactionLifecycle
+func NewTransactionLifecycle(l visor.TransactionLifecycle) TransactionLifecycle {
+	events := make([]TransactionLifecycleEvent, len(l.Events))
+	for i, e := range l.Events {
+		events[i] = TransactionLifecycleEvent{
+			State:    e.State,
+			Time:     e.Time,
+			Peers:    e.Peers,
+			BlockSeq: e.BlockSeq,
+			Reason:   e.Reason,
+		}
+	}
+
+	return TransactionLifecycle{
+		TxID:          l.Hash.Hex(),
+		WalletID:      l.WalletID,
+		State:         l.State,
+		Peers:         l.Peers,
+		BlockSeq:      l.BlockSeq,
+		Confirmations: l.Confirmations,
+		Reason:        l.Reason,
+		Created:       l.Created,
+		Updated:       l.Updated,
+		Events:        events,
+	}
+}
+
+// transactionLifecycleHandler returns the lifecycle of a transaction submitted through the API
+// Method: GET
+// URI: /api/v2/transaction/lifecycle
+// Args:
+//	txid: transaction hash
+func transactionLifecycleHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txidStr := r.FormValue("txid")
+		if txidStr == "" {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "txid is empty")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		txid, err := cipher.SHA256FromHex(txidStr)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusBadRequest, "invalid txid")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		l, err := gateway.GetTransactionLifecycle(txid)
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		if l == nil {
+			resp := NewHTTPErrorResponse(http.StatusNotFound, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: NewTransactionLifecycle(*l),
+		})
+	}
+}
+
+// transactionLifecyclesHandler returns the lifecycles of the transactions submitted through the API, ordered by creation time
+// Method: GET
+// URI: /api/v2/transaction/lifecycles
+// Args:
+//	wallet_id: [optional] return only the transactions created by this wallet
+func transactionLifecyclesHandler(gateway Gatewayer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			resp := NewHTTPErrorResponse(http.StatusMethodNotAllowed, "")
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		ls, err := gateway.GetTransactionLifecycles(r.FormValue("wallet_id"))
+		if err != nil {
+			resp := NewHTTPErrorResponse(http.StatusInternalServerError, err.Error())
+			writeHTTPResponse(w, resp)
+			return
+		}
+
+		data := make([]TransactionLifecycle, len(ls))
+		for i, l := range ls {
+			data[i] = NewTransactionLifecycle(l)
+		}
+
+		writeHTTPResponse(w, HTTPResponse{
+			Data: data,
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+)
+
+func doGetV2Request(t *testing.T, gateway *MockGatewayer, method, endpoint string, v url.Values) (int, ReceivedHTTPResponse) {
+	if len(v) > 0 {
+		endpoint += "?" + v.Encode()
+	}
+
+	req, err := http.NewRequest(method, endpoint, nil)
+	require.NoError(t, err)
+	setCSRFParameters(t, tokenValid, req)
+
+	rr := httptest.NewRecorder()
+	handler := newServerMux(defaultMuxConfig(), gateway)
+	handler.ServeHTTP(rr, req)
+
+	var rsp ReceivedHTTPResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&rsp))
+
+	return rr.Code, rsp
+}
+
+func makeTransactionLifecycle(t *testing.T, wltID string) (visor.TransactionLifecycle, TransactionLifecycle) {
+	now := time.Unix(1540000000, 0).UTC()
+	l := visor.TransactionLifecycle{
+		Hash:          testutil.RandSHA256(t),
+		WalletID:      wltID,
+		State:         visor.TxnLifecycleInBlock,
+		Peers:         4,
+		BlockSeq:      10,
+		Confirmations: 1,
+		Created:       now,
+		Updated:       now.Add(time.Minute),
+		Events: []visor.TransactionLifecycleEvent{
+			{
+				State: visor.TxnLifecycleCreated,
+				Time:  now,
+			},
+			{
+				State: visor.TxnLifecycleInjected,
+				Time:  now.Add(time.Second),
+			},
+			{
+				State: visor.TxnLifecycleBroadcast,
+				Time:  now.Add(time.Second),
+				Peers: 4,
+			},
+			{
+				State:    visor.TxnLifecycleInBlock,
+				Time:     now.Add(time.Minute),
+				BlockSeq: 10,
+			},
+		},
+	}
+
+	return l, TransactionLifecycle{
+		TxID:          l.Hash.Hex(),
+		WalletID:      wltID,
+		State:         "in_block",
+		Peers:         4,
+		BlockSeq:      10,
+		Confirmations: 1,
+		Created:       now,
+		Updated:       now.Add(time.Minute),
+		Events: []TransactionLifecycleEvent{
+			{
+				State: "created",
+				Time:  now,
+			},
+			{
+				State: "injected",
+				Time:  now.Add(time.Second),
+			},
+			{
+				State: "broadcast",
+				Time:  now.Add(time.Second),
+				Peers: 4,
+			},
+			{
+				State:    "in_block",
+				Time:     now.Add(time.Minute),
+				BlockSeq: 10,
+			},
+		},
+	}
+}
+
+func TestTransactionLifecycle(t *testing.T) {
+	l, rl := makeTransactionLifecycle(t, "foo.wlt")
+
+	tt := []struct {
+		name       string
+		method     string
+		txid       string
+		hash       cipher.SHA256
+		lifecycle  *visor.TransactionLifecycle
+		gatewayErr error
+		status     int
+		err        string
+		data       *TransactionLifecycle
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:   "400 - missing txid",
+			method: http.MethodGet,
+			status: http.StatusBadRequest,
+			err:    "txid is empty",
+		},
+		{
+			name:   "400 - invalid txid",
+			method: http.MethodGet,
+			txid:   "foo",
+			status: http.StatusBadRequest,
+			err:    "invalid txid",
+		},
+		{
+			name:   "404 - not tracked",
+			method: http.MethodGet,
+			txid:   l.Hash.Hex(),
+			hash:   l.Hash,
+			status: http.StatusNotFound,
+			err:    "Not Found",
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodGet,
+			txid:       l.Hash.Hex(),
+			hash:       l.Hash,
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name:      "200",
+			method:    http.MethodGet,
+			txid:      l.Hash.Hex(),
+			hash:      l.Hash,
+			lifecycle: &l,
+			status:    http.StatusOK,
+			data:      &rl,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetTransactionLifecycle", tc.hash).Return(tc.lifecycle, tc.gatewayErr)
+
+			v := url.Values{}
+			if tc.txid != "" {
+				v.Add("txid", tc.txid)
+			}
+
+			status, rsp := doGetV2Request(t, gateway, tc.method, "/api/v2/transaction/lifecycle", v)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data TransactionLifecycle
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, *tc.data, data)
+		})
+	}
+}
+
+func TestTransactionLifecycles(t *testing.T) {
+	l1, rl1 := makeTransactionLifecycle(t, "foo.wlt")
+	l2, rl2 := makeTransactionLifecycle(t, "")
+
+	tt := []struct {
+		name       string
+		method     string
+		walletID   string
+		lifecycles []visor.TransactionLifecycle
+		gatewayErr error
+		status     int
+		err        string
+		data       []TransactionLifecycle
+	}{
+		{
+			name:   "405",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+			err:    "Method Not Allowed",
+		},
+		{
+			name:       "500 - gateway error",
+			method:     http.MethodGet,
+			gatewayErr: errors.New("failed"),
+			status:     http.StatusInternalServerError,
+			err:        "failed",
+		},
+		{
+			name:   "200 - none",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			data:   []TransactionLifecycle{},
+		},
+		{
+			name:       "200 - all",
+			method:     http.MethodGet,
+			lifecycles: []visor.TransactionLifecycle{l1, l2},
+			status:     http.StatusOK,
+			data:       []TransactionLifecycle{rl1, rl2},
+		},
+		{
+			name:       "200 - wallet",
+			method:     http.MethodGet,
+			walletID:   "foo.wlt",
+			lifecycles: []visor.TransactionLifecycle{l1},
+			status:     http.StatusOK,
+			data:       []TransactionLifecycle{rl1},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &MockGatewayer{}
+			gateway.On("GetTransactionLifecycles", tc.walletID).Return(tc.lifecycles, tc.gatewayErr)
+
+			v := url.Values{}
+			if tc.walletID != "" {
+				v.Add("wallet_id", tc.walletID)
+			}
+
+			status, rsp := doGetV2Request(t, gateway, tc.method, "/api/v2/transaction/lifecycles", v)
+			require.Equal(t, tc.status, status)
+
+			if tc.err != "" {
+				require.NotNil(t, rsp.Error)
+				require.Equal(t, tc.err, rsp.Error.Message)
+				return
+			}
+
+			require.Nil(t, rsp.Error)
+			var data []TransactionLifecycle
+			require.NoError(t, json.Unmarshal(rsp.Data, &data))
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
@@ -101,6 +101,13 @@ func TestGetPendingTxs(t *testing.T) {
 		getAllUnconfirmedTxnsErr             error
 		getAllUnconfirmedTxnsVerboseResponse verboseResult
 		getAllUnconfirmedTxnsVerboseErr      error
+		evicted                              bool
+		evictedStr                           string
+		getEvictedUnconfirmedTxnsResponse    []visor.EvictedTransaction
+		expired                              bool
+		expiredStr                           string
+		getExpiredUnconfirmedTxnsResponse    []visor.ExpiredTransaction
+		getExpiredUnconfirmedTxnsErr         error
 		httpResponse                         interface{}
 	}{
 		{
@@ -117,6 +124,68 @@ func TestGetPendingTxs(t *testing.T) {
 			err:        "400 Bad Request - Invalid value for verbose",
 			verboseStr: "foo",
 		},
+		{
+			name:       "400 - bad evicted",
+			method:     http.MethodGet,
+			status:     http.StatusBadRequest,
+			err:        "400 Bad Request - Invalid value for evicted",
+			evictedStr: "foo",
+		},
+		{
+			name:       "400 - verbose and evicted",
+			method:     http.MethodGet,
+			status:     http.StatusBadRequest,
+			err:        "400 Bad Request - verbose and evicted cannot be combined",
+			verboseStr: "1",
+			evictedStr: "1",
+		},
+		{
+			name:       "400 - bad expired",
+			method:     http.MethodGet,
+			status:     http.StatusBadRequest,
+			err:        "400 Bad Request - Invalid value for expired",
+			expiredStr: "foo",
+		},
+		{
+			name:       "400 - expired and evicted",
+			method:     http.MethodGet,
+			status:     http.StatusBadRequest,
+			err:        "400 Bad Request - expired cannot be combined with verbose or evicted",
+			evictedStr: "1",
+			expiredStr: "1",
+		},
+		{
+			name:                         "500 - get expired txns error",
+			method:                       http.MethodGet,
+			status:                       http.StatusInternalServerError,
+			err:                          "500 Internal Server Error - GetExpiredUnconfirmedTransactions failed",
+			expiredStr:                   "1",
+			getExpiredUnconfirmedTxnsErr: errors.New("GetExpiredUnconfirmedTransactions failed"),
+		},
+		{
+			name:       "500 - bad expired txn",
+			method:     http.MethodGet,
+			status:     http.StatusInternalServerError,
+			err:        "500 Internal Server Error - Droplet string conversion failed: Value is too large",
+			expiredStr: "1",
+			getExpiredUnconfirmedTxnsResponse: []visor.ExpiredTransaction{
+				{
+					Transaction: invalidTxn.Transaction,
+				},
+			},
+		},
+		{
+			name:       "500 - bad evicted txn",
+			method:     http.MethodGet,
+			status:     http.StatusInternalServerError,
+			err:        "500 Internal Server Error - Droplet string conversion failed: Value is too large",
+			evictedStr: "1",
+			getEvictedUnconfirmedTxnsResponse: []visor.EvictedTransaction{
+				{
+					Transaction: invalidTxn.Transaction,
+				},
+			},
+		},
 		{
 			name:   "500 - bad unconfirmedTxn",
 			method: http.MethodGet,
@@ -161,6 +230,24 @@ func TestGetPendingTxs(t *testing.T) {
 			},
 			httpResponse: []readable.UnconfirmedTransactionVerbose{},
 		},
+		{
+			name:                              "200 evicted",
+			method:                            http.MethodGet,
+			status:                            http.StatusOK,
+			evictedStr:                        "1",
+			evicted:                           true,
+			getEvictedUnconfirmedTxnsResponse: []visor.EvictedTransaction{},
+			httpResponse:                      []readable.EvictedTransaction{},
+		},
+		{
+			name:                              "200 expired",
+			method:                            http.MethodGet,
+			status:                            http.StatusOK,
+			expiredStr:                        "1",
+			expired:                           true,
+			getExpiredUnconfirmedTxnsResponse: []visor.ExpiredTransaction{},
+			httpResponse:                      []readable.ExpiredTransaction{},
+		},
 	}
 
 	for _, tc := range tt {
@@ -170,11 +257,19 @@ func TestGetPendingTxs(t *testing.T) {
 			gateway.On("GetAllUnconfirmedTransactions").Return(tc.getAllUnconfirmedTxnsResponse, tc.getAllUnconfirmedTxnsErr)
 			gateway.On("GetAllUnconfirmedTransactionsVerbose").Return(tc.getAllUnconfirmedTxnsVerboseResponse.Transactions,
 				tc.getAllUnconfirmedTxnsVerboseResponse.Inputs, tc.getAllUnconfirmedTxnsVerboseErr)
+			gateway.On("GetEvictedUnconfirmedTransactions").Return(tc.getEvictedUnconfirmedTxnsResponse)
+			gateway.On("GetExpiredUnconfirmedTransactions").Return(tc.getExpiredUnconfirmedTxnsResponse, tc.getExpiredUnconfirmedTxnsErr)
 
 			v := url.Values{}
 			if tc.verboseStr != "" {
 				v.Add("verbose", tc.verboseStr)
 			}
+			if tc.evictedStr != "" {
+				v.Add("evicted", tc.evictedStr)
+			}
+			if tc.expiredStr != "" {
+				v.Add("expired", tc.expiredStr)
+			}
 			if len(v) > 0 {
 				endpoint += "?" + v.Encode()
 			}
@@ -199,7 +294,17 @@ func TestGetPendingTxs(t *testing.T) {
 				require.Equal(t, tc.err, strings.TrimSpace(rr.Body.String()), "got `%v`| %d, want `%v`",
 					strings.TrimSpace(rr.Body.String()), status, tc.err)
 			} else {
-				if tc.verbose {
+				if tc.expired {
+					var msg []readable.ExpiredTransaction
+					err = json.Unmarshal(rr.Body.Bytes(), &msg)
+					require.NoError(t, err)
+					require.Equal(t, tc.httpResponse, msg, tc.name)
+				} else if tc.evicted {
+					var msg []readable.EvictedTransaction
+					err = json.Unmarshal(rr.Body.Bytes(), &msg)
+					require.NoError(t, err)
+					require.Equal(t, tc.httpResponse, msg, tc.name)
+				} else if tc.verbose {
 					var msg []readable.UnconfirmedTransactionVerbose
 					err = json.Unmarshal(rr.Body.Bytes(), &msg)
 					require.NoError(t, err)
@@ -716,6 +821,33 @@ func TestInjectTransaction(t *testing.T) {
 			injectTransactionArg:   validTransaction,
 			injectTransactionError: gnet.ErrPoolEmpty,
 		},
+		{
+			name:                   "503 - visor.ErrUnconfirmedPoolFull",
+			method:                 http.MethodPost,
+			status:                 http.StatusServiceUnavailable,
+			err:                    "503 Service Unavailable - unconfirmed transaction pool is full and the transaction fee per byte is too low to evict other transactions",
+			httpBody:               string(validTxnBodyJSON),
+			injectTransactionArg:   validTransaction,
+			injectTransactionError: visor.ErrUnconfirmedPoolFull,
+		},
+		{
+			name:                   "503 - visor.ErrUnconfirmedChainTooLong",
+			method:                 http.MethodPost,
+			status:                 http.StatusServiceUnavailable,
+			err:                    "503 Service Unavailable - transaction exceeds the limits of unconfirmed ancestors or descendants in the unconfirmed pool",
+			httpBody:               string(validTxnBodyJSON),
+			injectTransactionArg:   validTransaction,
+			injectTransactionError: visor.ErrUnconfirmedChainTooLong,
+		},
+		{
+			name:                   "400 - visor.ErrTxnReplaced",
+			method:                 http.MethodPost,
+			status:                 http.StatusBadRequest,
+			err:                    "400 Bad Request - transaction was replaced by an unconfirmed transaction that burns more coin hours",
+			httpBody:               string(validTxnBodyJSON),
+			injectTransactionArg:   validTransaction,
+			injectTransactionError: visor.ErrTxnReplaced,
+		},
 		{
 			name:                   "500 - other injectTransactionError",
 			method:                 http.MethodPost,
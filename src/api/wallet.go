@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/skycoin/skycoin/src/cipher"
 	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
 	"github.com/skycoin/skycoin/src/readable"
 	wh "github.com/skycoin/skycoin/src/util/http"
@@ -33,8 +34,9 @@ type BalanceResponse struct {
 
 // WalletResponse wallet response struct for http apis
 type WalletResponse struct {
-	Meta    readable.WalletMeta    `json:"meta"`
-	Entries []readable.WalletEntry `json:"entries"`
+	Meta        readable.WalletMeta         `json:"meta"`
+	Entries     []readable.WalletEntry      `json:"entries"`
+	AddressBook []readable.AddressBookEntry `json:"address_book,omitempty"`
 }
 
 // NewWalletResponse creates WalletResponse struct from *wallet.Wallet
@@ -66,13 +68,50 @@ func NewWalletResponse(w *wallet.Wallet) (*WalletResponse, error) {
 		wr.Meta.Timestamp = tm
 	}
 
+	wr.Meta.XPub = w.Meta["xpub"]
+	wr.Meta.ChangeAddressPolicy = w.Meta["changeAddressPolicy"]
+
+	hasChildNumbers := false
+	switch w.Type() {
+	case wallet.WalletTypeBip44:
+		hasChildNumbers = true
+		bip44Coin, err := strconv.ParseUint(w.Meta["bip44Coin"], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		c := uint32(bip44Coin)
+		wr.Meta.Bip44Coin = &c
+	case wallet.WalletTypeXPub:
+		hasChildNumbers = true
+	}
+
 	for _, e := range w.Entries {
-		wr.Entries = append(wr.Entries, readable.WalletEntry{
+		re := readable.WalletEntry{
 			Address: e.Address.String(),
-			Public:  e.Public.Hex(),
-		})
+			Label:   e.Label,
+		}
+
+		// Entries of address list wallets have no public key
+		if !e.Public.Null() {
+			re.Public = e.Public.Hex()
+		}
+
+		if hasChildNumbers {
+			childNumber := e.ChildNumber
+			change := e.Change
+			re.ChildNumber = &childNumber
+			re.Change = &change
+		} else if e.IsChangeAddress() {
+			// Change addresses of deterministic wallets
+			change := e.Change
+			re.Change = &change
+		}
+
+		wr.Entries = append(wr.Entries, re)
 	}
 
+	wr.AddressBook = readable.NewAddressBook(w.GetAddressBook())
+
 	return &wr, nil
 }
 
@@ -181,11 +220,16 @@ func balanceHandler(gateway Gatewayer) http.HandlerFunc {
 // URI: /api/v1/wallet/create
 // Method: POST
 // Args:
-//     seed: wallet seed [required]
+//     type: wallet type, "deterministic", "bip44", "xpub" or "addresses" [optional, default "deterministic"]
+//     seed: wallet seed [required for "deterministic" and "bip44" wallets]
+//     seed-passphrase: bip39 passphrase of the seed [optional, only for "bip44" wallets]
+//     xpub: account level extended public key [required for "xpub" wallets]
+//     addresses: comma-separated list of addresses [required for "addresses" wallets]
 //     label: wallet label [required]
 //     scan: the number of addresses to scan ahead for balances [optional, must be > 0]
 //     encrypt: bool value, whether encrypt the wallet [optional]
 //     password: password for encrypting wallet [optional, must be provided if "encrypt" is set]
+//     change-address-policy: "reuse" or "fresh", whether the change of created transactions is sent to a new address [optional, default "reuse"]
 func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -193,9 +237,45 @@ func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
+		walletType := r.FormValue("type")
+		if walletType == "" {
+			walletType = wallet.WalletTypeDeterministic
+		}
+
 		seed := r.FormValue("seed")
-		if seed == "" {
-			wh.Error400(w, "missing seed")
+		seedPassphrase := r.FormValue("seed-passphrase")
+		xpub := r.FormValue("xpub")
+		var addrs []cipher.Addresser
+
+		switch walletType {
+		case wallet.WalletTypeDeterministic, wallet.WalletTypeBip44:
+			if seed == "" {
+				wh.Error400(w, "missing seed")
+				return
+			}
+		case wallet.WalletTypeXPub:
+			if xpub == "" {
+				wh.Error400(w, "missing xpub")
+				return
+			}
+		case wallet.WalletTypeAddresses:
+			addrsStr := r.FormValue("addresses")
+			if addrsStr == "" {
+				wh.Error400(w, "missing addresses")
+				return
+			}
+
+			skyAddrs, err := parseAddressesFromStr(addrsStr)
+			if err != nil {
+				wh.Error400(w, fmt.Sprintf("invalid addresses: %v", err))
+				return
+			}
+
+			for _, a := range skyAddrs {
+				addrs = append(addrs, a)
+			}
+		default:
+			wh.Error400(w, "invalid wallet type")
 			return
 		}
 
@@ -208,6 +288,7 @@ func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 		password := r.FormValue("password")
 		defer func() {
 			password = ""
+			seedPassphrase = ""
 		}()
 
 		var encrypt bool
@@ -247,12 +328,25 @@ func walletCreateHandler(gateway Gatewayer) http.HandlerFunc {
 			return
 		}
 
+		changeAddressPolicy := r.FormValue("change-address-policy")
+		switch changeAddressPolicy {
+		case "", wallet.ChangeAddressPolicyReuse, wallet.ChangeAddressPolicyFresh:
+		default:
+			wh.Error400(w, "invalid change-address-policy value")
+			return
+		}
+
 		wlt, err := gateway.CreateWallet("", wallet.Options{
-			Seed:     seed,
-			Label:    label,
-			Encrypt:  encrypt,
-			Password: []byte(password),
-			ScanN:    scanN,
+			Seed:                seed,
+			SeedPassphrase:      seedPassphrase,
+			XPub:                xpub,
+			Addresses:           addrs,
+			Label:               label,
+			Type:                walletType,
+			Encrypt:             encrypt,
+			Password:            []byte(password),
+			ScanN:               scanN,
+			ChangeAddressPolicy: changeAddressPolicy,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -654,7 +748,8 @@ func walletSeedHandler(gateway Gatewayer) http.HandlerFunc {
 			switch err {
 			case wallet.ErrMissingPassword,
 				wallet.ErrWalletNotEncrypted,
-				wallet.ErrInvalidPassword:
+				wallet.ErrInvalidPassword,
+				wallet.ErrWatchOnlyWallet:
 				wh.Error400(w, err.Error())
 			case wallet.ErrWalletAPIDisabled, wallet.ErrSeedAPIDisabled:
 				wh.Error403(w, "")
@@ -778,7 +873,8 @@ func walletEncryptHandler(gateway Gatewayer) http.HandlerFunc {
 			switch err {
 			case wallet.ErrWalletEncrypted,
 				wallet.ErrMissingPassword,
-				wallet.ErrInvalidPassword:
+				wallet.ErrInvalidPassword,
+				wallet.ErrWatchOnlyWallet:
 				wh.Error400(w, err.Error())
 			case wallet.ErrWalletAPIDisabled:
 				wh.Error403(w, "")
@@ -852,9 +948,10 @@ func walletDecryptHandler(gateway Gatewayer) http.HandlerFunc {
 
 // WalletRecoverRequest is the request data for POST /api/v2/wallet/recover
 type WalletRecoverRequest struct {
-	ID       string `json:"id"`
-	Seed     string `json:"seed"`
-	Password string `json:"password"`
+	ID             string `json:"id"`
+	Seed           string `json:"seed"`
+	SeedPassphrase string `json:"seed_passphrase,omitempty"`
+	Password       string `json:"password"`
 }
 
 // URI: /api/v2/wallet/recover
@@ -862,6 +959,7 @@ type WalletRecoverRequest struct {
 // Args:
 //	id: wallet id
 //  seed: wallet seed
+//  seed_passphrase: [optional] bip39 passphrase of the seed of a bip44 wallet
 //  password: [optional] new password
 // Recovers an encrypted wallet by providing the seed.
 // The first address will be generated from seed and compared to the first address
@@ -908,15 +1006,16 @@ func walletRecoverHandler(gateway Gatewayer) http.HandlerFunc {
 
 		defer func() {
 			req.Seed = ""
+			req.SeedPassphrase = ""
 			req.Password = ""
 			password = nil
 		}()
 
-		wlt, err := gateway.RecoverWallet(req.ID, req.Seed, password)
+		wlt, err := gateway.RecoverWallet(req.ID, req.Seed, req.SeedPassphrase, password)
 		if err != nil {
 			var resp HTTPResponse
 			switch err {
-			case wallet.ErrWalletNotEncrypted, wallet.ErrWalletRecoverSeedWrong:
+			case wallet.ErrWalletNotEncrypted, wallet.ErrWalletRecoverSeedWrong, wallet.ErrSeedPassphraseNotSupported:
 				resp = NewHTTPErrorResponse(http.StatusBadRequest, err.Error())
 			case wallet.ErrWalletNotExist:
 				resp = NewHTTPErrorResponse(http.StatusNotFound, "")
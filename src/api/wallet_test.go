@@ -847,12 +847,34 @@ func TestWalletTransactionsHandler(t *testing.T) {
 
 func TestWalletCreateHandler(t *testing.T) {
 	entries, responseEntries := makeEntries([]byte("seed"), 5)
+
+	bip44Coin := uint32(8000)
+	bip44Entries := cloneEntries(entries[:2])
+	bip44Entries[1].ChildNumber = 0
+	bip44Entries[1].Change = 1
+	bip44ResponseEntries := make([]readable.WalletEntry, len(bip44Entries))
+	for i, e := range bip44Entries {
+		childNumber := e.ChildNumber
+		change := e.Change
+		bip44ResponseEntries[i] = readable.WalletEntry{
+			Address:     e.Address.String(),
+			Public:      e.Public.Hex(),
+			ChildNumber: &childNumber,
+			Change:      &change,
+		}
+	}
 	type httpBody struct {
 		Seed     string
-		Label    string
-		ScanN    string
+		Label     string
+		Type      string
+		XPub      string
+		Addresses string
+		ScanN     string
 		Encrypt  bool
 		Password string
+
+		ChangeAddressPolicy string
+		SeedPassphrase      string
 	}
 	tt := []struct {
 		name                      string
@@ -892,6 +914,52 @@ func TestWalletCreateHandler(t *testing.T) {
 			err:     "400 Bad Request - missing label",
 			wltName: "foo",
 		},
+		{
+			name:   "400 - invalid wallet type",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:  "foo",
+				Label: "bar",
+				Type:  "foo",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - invalid wallet type",
+			wltName: "foo",
+		},
+		{
+			name:   "400 - missing xpub",
+			method: http.MethodPost,
+			body: &httpBody{
+				Label: "bar",
+				Type:  wallet.WalletTypeXPub,
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - missing xpub",
+			wltName: "foo",
+		},
+		{
+			name:   "400 - missing addresses",
+			method: http.MethodPost,
+			body: &httpBody{
+				Label: "bar",
+				Type:  wallet.WalletTypeAddresses,
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - missing addresses",
+			wltName: "foo",
+		},
+		{
+			name:   "400 - invalid addresses",
+			method: http.MethodPost,
+			body: &httpBody{
+				Label:     "bar",
+				Type:      wallet.WalletTypeAddresses,
+				Addresses: "foo",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - invalid addresses: address \"foo\" is invalid: Invalid address length",
+			wltName: "foo",
+		},
 		{
 			name:   "400 - invalid scan value",
 			method: http.MethodPost,
@@ -916,6 +984,18 @@ func TestWalletCreateHandler(t *testing.T) {
 			err:     "400 Bad Request - scan must be > 0",
 			wltName: "foo",
 		},
+		{
+			name:   "400 - invalid change-address-policy value",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:                "foo",
+				Label:               "bar",
+				ChangeAddressPolicy: "new",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - invalid change-address-policy value",
+			wltName: "foo",
+		},
 		{
 			name:   "400 - seed in use",
 			method: http.MethodPost,
@@ -999,6 +1079,171 @@ func TestWalletCreateHandler(t *testing.T) {
 				Entries: responseEntries[:],
 			},
 		},
+		{
+			name:   "200 - OK - bip44",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:  "foo",
+				Label: "bar",
+				Type:  wallet.WalletTypeBip44,
+				ScanN: "2",
+			},
+			status:  http.StatusOK,
+			err:     "",
+			wltName: "filename",
+			options: wallet.Options{
+				Label:    "bar",
+				Seed:     "foo",
+				Type:     wallet.WalletTypeBip44,
+				Password: []byte{},
+				ScanN:    2,
+			},
+			gatewayCreateWalletResult: wallet.Wallet{
+				Meta: map[string]string{
+					"filename":  "filename",
+					"type":      wallet.WalletTypeBip44,
+					"bip44Coin": "8000",
+				},
+				Entries: bip44Entries,
+			},
+			responseBody: WalletResponse{
+				Meta: readable.WalletMeta{
+					Filename:  "filename",
+					Type:      wallet.WalletTypeBip44,
+					Bip44Coin: &bip44Coin,
+				},
+				Entries: bip44ResponseEntries,
+			},
+		},
+		{
+			name:   "200 - OK - bip44 seed passphrase",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:           "foo",
+				SeedPassphrase: "foobar",
+				Label:          "bar",
+				Type:           wallet.WalletTypeBip44,
+			},
+			status:  http.StatusOK,
+			err:     "",
+			wltName: "filename",
+			options: wallet.Options{
+				Label:          "bar",
+				Seed:           "foo",
+				SeedPassphrase: "foobar",
+				Type:           wallet.WalletTypeBip44,
+				Password:       []byte{},
+				ScanN:          1,
+			},
+			gatewayCreateWalletResult: wallet.Wallet{
+				Meta: map[string]string{
+					"filename":       "filename",
+					"type":           wallet.WalletTypeBip44,
+					"bip44Coin":      "8000",
+					"seedPassphrase": "foobar",
+				},
+				Entries: bip44Entries,
+			},
+			responseBody: WalletResponse{
+				Meta: readable.WalletMeta{
+					Filename:  "filename",
+					Type:      wallet.WalletTypeBip44,
+					Bip44Coin: &bip44Coin,
+				},
+				Entries: bip44ResponseEntries,
+			},
+		},
+		{
+			name:   "400 - seed passphrase not supported",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:           "foo",
+				SeedPassphrase: "foobar",
+				Label:          "bar",
+			},
+			status:  http.StatusBadRequest,
+			err:     "400 Bad Request - seed passphrase is only supported by bip44 wallets",
+			wltName: "filename",
+			options: wallet.Options{
+				Label:          "bar",
+				Seed:           "foo",
+				SeedPassphrase: "foobar",
+				Type:           wallet.WalletTypeDeterministic,
+				Password:       []byte{},
+				ScanN:          1,
+			},
+			gatewayCreateWalletErr: wallet.ErrSeedPassphraseNotSupported,
+		},
+		{
+			name:   "200 - OK - addresses",
+			method: http.MethodPost,
+			body: &httpBody{
+				Label:     "bar",
+				Type:      wallet.WalletTypeAddresses,
+				Addresses: entries[0].Address.String() + "," + entries[1].Address.String(),
+			},
+			status:  http.StatusOK,
+			err:     "",
+			wltName: "filename",
+			options: wallet.Options{
+				Label:     "bar",
+				Type:      wallet.WalletTypeAddresses,
+				Addresses: []cipher.Addresser{entries[0].Address, entries[1].Address},
+				Password:  []byte{},
+			},
+			gatewayCreateWalletResult: wallet.Wallet{
+				Meta: map[string]string{
+					"filename": "filename",
+					"type":     wallet.WalletTypeAddresses,
+				},
+				Entries: []wallet.Entry{
+					{Address: entries[0].Address},
+					{Address: entries[1].Address},
+				},
+			},
+			responseBody: WalletResponse{
+				Meta: readable.WalletMeta{
+					Filename: "filename",
+					Type:     wallet.WalletTypeAddresses,
+				},
+				Entries: []readable.WalletEntry{
+					{Address: entries[0].Address.String()},
+					{Address: entries[1].Address.String()},
+				},
+			},
+		},
+		{
+			name:   "200 - OK - fresh change addresses",
+			method: http.MethodPost,
+			body: &httpBody{
+				Seed:                "foo",
+				Label:               "bar",
+				ChangeAddressPolicy: wallet.ChangeAddressPolicyFresh,
+			},
+			status:  http.StatusOK,
+			err:     "",
+			wltName: "filename",
+			options: wallet.Options{
+				Label:               "bar",
+				Seed:                "foo",
+				Password:            []byte{},
+				ChangeAddressPolicy: wallet.ChangeAddressPolicyFresh,
+			},
+			gatewayCreateWalletResult: wallet.Wallet{
+				Meta: map[string]string{
+					"filename":            "filename",
+					"changeAddressPolicy": wallet.ChangeAddressPolicyFresh,
+				},
+				Entries: cloneEntries(entries[:1]),
+			},
+			responseBody: WalletResponse{
+				Meta: readable.WalletMeta{
+					Filename:            "filename",
+					ChangeAddressPolicy: wallet.ChangeAddressPolicyFresh,
+				},
+				Entries: responseEntries[:1],
+			},
+		},
 		// CSRF Tests
 		{
 			name:   "200 - OK - CSRF disabled",
@@ -1084,6 +1329,9 @@ func TestWalletCreateHandler(t *testing.T) {
 			if tc.options.ScanN == 0 {
 				tc.options.ScanN = 1
 			}
+			if tc.options.Type == "" {
+				tc.options.Type = wallet.WalletTypeDeterministic
+			}
 			gateway.On("CreateWallet", "", tc.options).Return(&tc.gatewayCreateWalletResult, tc.gatewayCreateWalletErr)
 
 			endpoint := "/api/v1/wallet/create"
@@ -1096,6 +1344,15 @@ func TestWalletCreateHandler(t *testing.T) {
 				if tc.body.Label != "" {
 					v.Add("label", tc.body.Label)
 				}
+				if tc.body.Type != "" {
+					v.Add("type", tc.body.Type)
+				}
+				if tc.body.XPub != "" {
+					v.Add("xpub", tc.body.XPub)
+				}
+				if tc.body.Addresses != "" {
+					v.Add("addresses", tc.body.Addresses)
+				}
 				if tc.body.ScanN != "" {
 					v.Add("scan", tc.body.ScanN)
 				}
@@ -1107,6 +1364,14 @@ func TestWalletCreateHandler(t *testing.T) {
 				if tc.body.Password != "" {
 					v.Add("password", tc.body.Password)
 				}
+
+				if tc.body.ChangeAddressPolicy != "" {
+					v.Add("change-address-policy", tc.body.ChangeAddressPolicy)
+				}
+
+				if tc.body.SeedPassphrase != "" {
+					v.Add("seed-passphrase", tc.body.SeedPassphrase)
+				}
 			}
 
 			req, err := http.NewRequest(tc.method, endpoint, strings.NewReader(v.Encode()))
@@ -1441,6 +1706,18 @@ func TestGetWalletSeed(t *testing.T) {
 			expectStatus: http.StatusBadRequest,
 			expectErr:    "400 Bad Request - missing wallet id",
 		},
+		{
+			name:     "400 - watch-only wallet",
+			method:   http.MethodPost,
+			wltID:    "wallet.wlt",
+			password: "pwd",
+			gatewayReturnArgs: []interface{}{
+				"",
+				wallet.ErrWatchOnlyWallet,
+			},
+			expectStatus: http.StatusBadRequest,
+			expectErr:    "400 Bad Request - wallet is watch-only",
+		},
 		{
 			name:     "400 - missing password",
 			method:   http.MethodPost,
@@ -2688,6 +2965,39 @@ func TestWalletRecover(t *testing.T) {
 				Data: *okWalletEncryptedResponse,
 			},
 		},
+		{
+			name:        "seed passphrase not supported",
+			method:      http.MethodPost,
+			status:      http.StatusBadRequest,
+			contentType: ContentTypeJSON,
+			req: &WalletRecoverRequest{
+				ID:             "foo",
+				Seed:           "fooseed",
+				SeedPassphrase: "foopassphrase",
+			},
+			gatewayReturn: gatewayReturnPair{
+				err: wallet.ErrSeedPassphraseNotSupported,
+			},
+			httpResponse: NewHTTPErrorResponse(http.StatusBadRequest, wallet.ErrSeedPassphraseNotSupported.Error()),
+		},
+		{
+			name:        "ok, seed passphrase",
+			method:      http.MethodPost,
+			status:      http.StatusOK,
+			contentType: ContentTypeJSON,
+			req: &WalletRecoverRequest{
+				ID:             "foo",
+				Seed:           "fooseed",
+				SeedPassphrase: "foopassphrase",
+				Password:       "foopassword",
+			},
+			gatewayReturn: gatewayReturnPair{
+				w: okWalletEncrypted,
+			},
+			httpResponse: HTTPResponse{
+				Data: *okWalletEncryptedResponse,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -2698,7 +3008,7 @@ func TestWalletRecover(t *testing.T) {
 				if tc.req.Password != "" {
 					password = []byte(tc.req.Password)
 				}
-				gateway.On("RecoverWallet", tc.req.ID, tc.req.Seed, password).Return(tc.gatewayReturn.w, tc.gatewayReturn.err)
+				gateway.On("RecoverWallet", tc.req.ID, tc.req.Seed, tc.req.SeedPassphrase, password).Return(tc.gatewayReturn.w, tc.gatewayReturn.err)
 			}
 
 			if tc.httpBody == "" && tc.req != nil {
@@ -123,7 +123,9 @@ func AddressFromBytes(b []byte) (Address, error) {
 		return Address{}, ErrAddressInvalidChecksum
 	}
 
-	if a.Version != 0 {
+	switch a.Version {
+	case 0, MultisigAddressVersion, TimeLockedAddressVersion:
+	default:
 		return Address{}, ErrAddressInvalidVersion
 	}
 
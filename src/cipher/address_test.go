@@ -118,7 +118,7 @@ func TestAddressFromBytes(t *testing.T) {
 	_, err = AddressFromBytes(b)
 	require.EqualError(t, err, "Invalid checksum")
 
-	a.Version = 2
+	a.Version = 3
 	b = a.Bytes()
 	_, err = AddressFromBytes(b)
 	require.EqualError(t, err, "Address version invalid")
@@ -142,7 +142,7 @@ func TestMustAddressFromBytes(t *testing.T) {
 		MustAddressFromBytes(b)
 	})
 
-	a.Version = 2
+	a.Version = 3
 	b = a.Bytes()
 	require.Panics(t, func() {
 		MustAddressFromBytes(b)
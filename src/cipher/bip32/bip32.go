@@ -0,0 +1,450 @@
+/*
+Package bip32 implements hierarchical deterministic wallet keys as defined in BIP32
+https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+*/
+package bip32
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/base58"
+	"github.com/skycoin/skycoin/src/cipher/ripemd160"
+	secp256k1go "github.com/skycoin/skycoin/src/cipher/secp256k1-go/secp256k1-go2"
+)
+
+const (
+	// FirstHardenedChild is the index of the first "hardened" child key as per the BIP32 spec
+	FirstHardenedChild = uint32(0x80000000)
+
+	// serializedKeyLen is the length of a serialized public or private extended key
+	serializedKeyLen = 78
+)
+
+var (
+	// PrivateWalletVersion is the version flag for serialized private keys ("xprv")
+	PrivateWalletVersion = []byte{0x04, 0x88, 0xAD, 0xE4}
+	// PublicWalletVersion is the version flag for serialized public keys ("xpub")
+	PublicWalletVersion = []byte{0x04, 0x88, 0xB2, 0x1E}
+
+	// masterKeySeed is the HMAC key used to generate the master key from a seed
+	masterKeySeed = []byte("Bitcoin seed")
+
+	// ErrInvalidSeedLength seed length must be between 128 and 512 bits
+	ErrInvalidSeedLength = errors.New("Invalid seed length, must be between 128 and 512 bits")
+	// ErrDeriveHardenedFromPublic cannot derive a hardened key from a public key
+	ErrDeriveHardenedFromPublic = errors.New("Can't derive a hardened key from a public key")
+	// ErrInvalidPrivateKey the derived private key is invalid
+	ErrInvalidPrivateKey = errors.New("Derived private key is invalid")
+	// ErrInvalidPublicKey the derived public key is invalid
+	ErrInvalidPublicKey = errors.New("Derived public key is invalid")
+	// ErrSerializedKeyWrongSize the serialized key is not the expected length
+	ErrSerializedKeyWrongSize = errors.New("Serialized keys should be exactly 82 bytes")
+	// ErrInvalidChecksum the checksum of a serialized key is invalid
+	ErrInvalidChecksum = errors.New("Checksum doesn't match")
+	// ErrInvalidKeyVersion the version of a serialized key is unknown
+	ErrInvalidKeyVersion = errors.New("Invalid key version")
+	// ErrInvalidPath a derivation path string is malformed
+	ErrInvalidPath = errors.New("Invalid derivation path")
+)
+
+// key holds the data common to private and public extended keys
+type key struct {
+	Version           []byte
+	Depth             byte
+	ParentFingerprint []byte
+	ChildNumber       uint32
+	ChainCode         []byte
+	Key               []byte
+}
+
+// PrivateKey is a bip32 extended private key
+type PrivateKey struct {
+	key
+}
+
+// PublicKey is a bip32 extended public key
+type PublicKey struct {
+	key
+}
+
+// NewMasterKey creates a new master extended private key from a seed
+func NewMasterKey(seed []byte) (*PrivateKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidSeedLength
+	}
+
+	h := hmac.New(sha512.New, masterKeySeed)
+	h.Write(seed) // nolint: errcheck
+	intermediary := h.Sum(nil)
+
+	keyBytes := intermediary[:32]
+	chainCode := intermediary[32:]
+
+	if err := validatePrivateKey(keyBytes); err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		key: key{
+			Version:           PrivateWalletVersion,
+			ChainCode:         chainCode,
+			Key:               keyBytes,
+			Depth:             0,
+			ChildNumber:       0,
+			ParentFingerprint: []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}, nil
+}
+
+// NewPrivateChildKey derives a private child key from a private parent key
+func (k *PrivateKey) NewPrivateChildKey(childIdx uint32) (*PrivateKey, error) {
+	pub, err := k.publicKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if childIdx >= FirstHardenedChild {
+		data = append([]byte{0x00}, k.Key...)
+	} else {
+		data = pub
+	}
+
+	il, ir := hmacChild(k.ChainCode, data, childIdx)
+
+	if err := validateChildTweak(il); err != nil {
+		return nil, err
+	}
+
+	childKey := addPrivateKeys(il, k.Key)
+	if err := validatePrivateKey(childKey); err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		key: key{
+			Version:           PrivateWalletVersion,
+			Depth:             k.Depth + 1,
+			ParentFingerprint: fingerprint(pub),
+			ChildNumber:       childIdx,
+			ChainCode:         ir,
+			Key:               childKey,
+		},
+	}, nil
+}
+
+// NewPublicChildKey derives a public child key from a private parent key
+func (k *PrivateKey) NewPublicChildKey(childIdx uint32) (*PublicKey, error) {
+	ck, err := k.NewPrivateChildKey(childIdx)
+	if err != nil {
+		return nil, err
+	}
+	return ck.PublicKey(), nil
+}
+
+// DeriveSubpath derives a private key by following each child index in the path
+func (k *PrivateKey) DeriveSubpath(path []uint32) (*PrivateKey, error) {
+	ck := k
+	for _, i := range path {
+		var err error
+		ck, err = ck.NewPrivateChildKey(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ck, nil
+}
+
+// PublicKey returns the extended public key of the private key
+func (k *PrivateKey) PublicKey() *PublicKey {
+	pub, err := k.publicKeyBytes()
+	if err != nil {
+		// The private key was validated when it was created
+		panic(err)
+	}
+
+	return &PublicKey{
+		key: key{
+			Version:           PublicWalletVersion,
+			Depth:             k.Depth,
+			ParentFingerprint: k.ParentFingerprint,
+			ChildNumber:       k.ChildNumber,
+			ChainCode:         k.ChainCode,
+			Key:               pub,
+		},
+	}
+}
+
+// SecKey returns the cipher.SecKey of the private key
+func (k *PrivateKey) SecKey() (cipher.SecKey, error) {
+	return cipher.NewSecKey(k.Key)
+}
+
+func (k *PrivateKey) publicKeyBytes() ([]byte, error) {
+	sk, err := cipher.NewSecKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := cipher.PubKeyFromSecKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return pk[:], nil
+}
+
+// NewPublicChildKey derives a public child key from a public parent key.
+// Hardened child keys cannot be derived from a public key.
+func (k *PublicKey) NewPublicChildKey(childIdx uint32) (*PublicKey, error) {
+	if childIdx >= FirstHardenedChild {
+		return nil, ErrDeriveHardenedFromPublic
+	}
+
+	il, ir := hmacChild(k.ChainCode, k.Key, childIdx)
+
+	if err := validateChildTweak(il); err != nil {
+		return nil, err
+	}
+
+	childKey := secp256k1go.BaseMultiplyAdd(k.Key, il)
+	if childKey == nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return &PublicKey{
+		key: key{
+			Version:           PublicWalletVersion,
+			Depth:             k.Depth + 1,
+			ParentFingerprint: fingerprint(k.Key),
+			ChildNumber:       childIdx,
+			ChainCode:         ir,
+			Key:               childKey,
+		},
+	}, nil
+}
+
+// DeriveSubpath derives a public key by following each child index in the path
+func (k *PublicKey) DeriveSubpath(path []uint32) (*PublicKey, error) {
+	ck := k
+	for _, i := range path {
+		var err error
+		ck, err = ck.NewPublicChildKey(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ck, nil
+}
+
+// PubKey returns the cipher.PubKey of the public key
+func (k *PublicKey) PubKey() (cipher.PubKey, error) {
+	return cipher.NewPubKey(k.Key)
+}
+
+// Serialize returns the serialized extended key, with checksum
+func (k *key) Serialize() []byte {
+	childNumberBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(childNumberBytes, k.ChildNumber)
+
+	keyBytes := k.Key
+	if len(keyBytes) == 32 {
+		// Private keys are prefixed with a zero byte
+		keyBytes = append([]byte{0x00}, keyBytes...)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(k.Version)
+	buf.WriteByte(k.Depth)
+	buf.Write(k.ParentFingerprint)
+	buf.Write(childNumberBytes)
+	buf.Write(k.ChainCode)
+	buf.Write(keyBytes)
+
+	b := buf.Bytes()
+	return append(b, checksum(b)...)
+}
+
+// String returns the base58 encoded serialized extended key
+func (k *key) String() string {
+	return base58.Encode(k.Serialize())
+}
+
+// DeserializePrivateKey deserializes a serialized private key
+func DeserializePrivateKey(data []byte) (*PrivateKey, error) {
+	k, err := deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(k.Version, PrivateWalletVersion) {
+		return nil, ErrInvalidKeyVersion
+	}
+
+	if k.Key[0] != 0x00 {
+		return nil, ErrInvalidPrivateKey
+	}
+	k.Key = k.Key[1:]
+
+	if err := validatePrivateKey(k.Key); err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{key: *k}, nil
+}
+
+// DeserializeEncodedPrivateKey deserializes a base58 encoded private key
+func DeserializeEncodedPrivateKey(s string) (*PrivateKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializePrivateKey(b)
+}
+
+// DeserializePublicKey deserializes a serialized public key
+func DeserializePublicKey(data []byte) (*PublicKey, error) {
+	k, err := deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(k.Version, PublicWalletVersion) {
+		return nil, ErrInvalidKeyVersion
+	}
+
+	if _, err := cipher.NewPubKey(k.Key); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return &PublicKey{key: *k}, nil
+}
+
+// DeserializeEncodedPublicKey deserializes a base58 encoded public key
+func DeserializeEncodedPublicKey(s string) (*PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializePublicKey(b)
+}
+
+func deserialize(data []byte) (*key, error) {
+	if len(data) != serializedKeyLen+4 {
+		return nil, ErrSerializedKeyWrongSize
+	}
+
+	payload := data[:serializedKeyLen]
+	if !bytes.Equal(checksum(payload), data[serializedKeyLen:]) {
+		return nil, ErrInvalidChecksum
+	}
+
+	k := &key{
+		Version:           append([]byte{}, payload[0:4]...),
+		Depth:             payload[4],
+		ParentFingerprint: append([]byte{}, payload[5:9]...),
+		ChildNumber:       binary.BigEndian.Uint32(payload[9:13]),
+		ChainCode:         append([]byte{}, payload[13:45]...),
+		Key:               append([]byte{}, payload[45:78]...),
+	}
+
+	return k, nil
+}
+
+// ParsePath parses a derivation path string such as "m/44'/8000'/0'/0/1" into child indexes.
+// Hardened indexes are marked with a trailing ' or h.
+func ParsePath(p string) ([]uint32, error) {
+	parts := strings.Split(p, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+
+	path := make([]uint32, 0, len(parts)-1)
+	for _, s := range parts[1:] {
+		hardened := strings.HasSuffix(s, "'") || strings.HasSuffix(s, "h")
+		if hardened {
+			s = s[:len(s)-1]
+		}
+
+		i, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidPath
+		}
+
+		if uint32(i) >= FirstHardenedChild {
+			return nil, fmt.Errorf("%v: child index %d out of range", ErrInvalidPath, i)
+		}
+
+		if hardened {
+			i += uint64(FirstHardenedChild)
+		}
+
+		path = append(path, uint32(i))
+	}
+
+	return path, nil
+}
+
+func hmacChild(chainCode, data []byte, childIdx uint32) ([]byte, []byte) {
+	childIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(childIndexBytes, childIdx)
+
+	h := hmac.New(sha512.New, chainCode)
+	h.Write(data)            // nolint: errcheck
+	h.Write(childIndexBytes) // nolint: errcheck
+	intermediary := h.Sum(nil)
+
+	return intermediary[:32], intermediary[32:]
+}
+
+// validateChildTweak checks that the left half of the child HMAC is less than the curve order
+func validateChildTweak(il []byte) error {
+	var n big.Int
+	n.SetBytes(il)
+	if n.Cmp(&secp256k1go.TheCurve.Order.Int) >= 0 {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
+func validatePrivateKey(k []byte) error {
+	if len(k) != 32 || secp256k1go.SeckeyIsValid(k) != 1 {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
+// addPrivateKeys returns (a + b) mod n, as a 32 byte array
+func addPrivateKeys(a, b []byte) []byte {
+	var x, y big.Int
+	x.SetBytes(a)
+	y.SetBytes(b)
+
+	x.Add(&x, &y)
+	x.Mod(&x, &secp256k1go.TheCurve.Order.Int)
+
+	out := make([]byte, 32)
+	xb := x.Bytes()
+	copy(out[32-len(xb):], xb)
+	return out
+}
+
+func fingerprint(pubKey []byte) []byte {
+	h := sha256.Sum256(pubKey)
+	r := ripemd160.New()
+	r.Write(h[:]) // nolint: errcheck
+	return r.Sum(nil)[:4]
+}
+
+func checksum(data []byte) []byte {
+	h := cipher.DoubleSHA256(data)
+	return h[:4]
+}
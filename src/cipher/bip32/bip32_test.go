@@ -0,0 +1,134 @@
+package bip32
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testChildKey struct {
+	path    []uint32
+	privKey string
+	pubKey  string
+}
+
+func TestBip32TestVector1(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	tests := []testChildKey{
+		{
+			path:    []uint32{},
+			privKey: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			pubKey:  "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			path:    []uint32{FirstHardenedChild},
+			privKey: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			pubKey:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		},
+		{
+			path:    []uint32{FirstHardenedChild, 1},
+			privKey: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			pubKey:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+	}
+
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	for _, tc := range tests {
+		k, err := master.DeriveSubpath(tc.path)
+		require.NoError(t, err)
+
+		require.Equal(t, tc.privKey, k.String())
+		require.Equal(t, tc.pubKey, k.PublicKey().String())
+
+		// Roundtrip the serialized keys
+		dk, err := DeserializeEncodedPrivateKey(tc.privKey)
+		require.NoError(t, err)
+		require.Equal(t, k, dk)
+
+		dpk, err := DeserializeEncodedPublicKey(tc.pubKey)
+		require.NoError(t, err)
+		require.Equal(t, k.PublicKey(), dpk)
+	}
+}
+
+func TestPublicChildDerivation(t *testing.T) {
+	seed, err := hex.DecodeString("fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542")
+	require.NoError(t, err)
+
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	// Non-hardened public derivation must match private derivation
+	for i := uint32(0); i < 10; i++ {
+		privChild, err := master.NewPrivateChildKey(i)
+		require.NoError(t, err)
+
+		pubChild, err := master.PublicKey().NewPublicChildKey(i)
+		require.NoError(t, err)
+
+		require.Equal(t, privChild.PublicKey(), pubChild)
+	}
+
+	_, err = master.PublicKey().NewPublicChildKey(FirstHardenedChild)
+	require.Equal(t, ErrDeriveHardenedFromPublic, err)
+}
+
+func TestNewMasterKeyInvalidSeed(t *testing.T) {
+	_, err := NewMasterKey(make([]byte, 15))
+	require.Equal(t, ErrInvalidSeedLength, err)
+
+	_, err = NewMasterKey(make([]byte, 65))
+	require.Equal(t, ErrInvalidSeedLength, err)
+}
+
+func TestDeserializeInvalid(t *testing.T) {
+	k := "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+
+	_, err := DeserializeEncodedPrivateKey(k)
+	require.Equal(t, ErrInvalidKeyVersion, err)
+
+	b, err := DeserializeEncodedPublicKey(k)
+	require.NoError(t, err)
+
+	data := b.Serialize()
+	data[len(data)-1]++
+	_, err = DeserializePublicKey(data)
+	require.Equal(t, ErrInvalidChecksum, err)
+
+	_, err = DeserializePublicKey(data[1:])
+	require.Equal(t, ErrSerializedKeyWrongSize, err)
+}
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		path string
+		out  []uint32
+		err  bool
+	}{
+		{"m", []uint32{}, false},
+		{"m/0", []uint32{0}, false},
+		{"m/44'/8000'/0'/0/1", []uint32{FirstHardenedChild + 44, FirstHardenedChild + 8000, FirstHardenedChild, 0, 1}, false},
+		{"m/0h/1", []uint32{FirstHardenedChild, 1}, false},
+		{"", nil, true},
+		{"0/1", nil, true},
+		{"m/a", nil, true},
+		{"m/2147483648", nil, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := ParsePath(tc.path)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.out, p)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Package bip44 implements the BIP44 hierarchy for deterministic wallets
+https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
+
+The derivation path is m / purpose' / coin_type' / account' / change / address_index
+*/
+package bip44
+
+import (
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+// CoinType is the coin_type part of the bip44 path
+type CoinType uint32
+
+const (
+	// CoinTypeBitcoin is the coin_type for Bitcoin, registered in SLIP-0044
+	CoinTypeBitcoin CoinType = 0
+	// CoinTypeSkycoin is the coin_type for Skycoin, registered in SLIP-0044
+	CoinTypeSkycoin CoinType = 8000
+
+	// ExternalChainIndex is the index of the external (receiving) chain
+	ExternalChainIndex = uint32(0)
+	// ChangeChainIndex is the index of the internal (change) chain
+	ChangeChainIndex = uint32(1)
+
+	// purpose is the purpose index for bip44, always 44'
+	purpose = bip32.FirstHardenedChild + 44
+)
+
+var (
+	// ErrHardenedChildNumber the account number must not be hardened, it is hardened internally
+	ErrHardenedChildNumber = errors.New("Account number must be less than 0x80000000")
+	// ErrInvalidChain the chain index must be 0 (external) or 1 (change)
+	ErrInvalidChain = errors.New("Chain index must be 0 (external) or 1 (change)")
+)
+
+// Coin is a bip32 node at the coin_type level of the bip44 path
+type Coin struct {
+	*bip32.PrivateKey
+}
+
+// NewCoin derives the coin_type node from a bip32 seed
+func NewCoin(seed []byte, coinType CoinType) (*Coin, error) {
+	mk, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	ck, err := mk.DeriveSubpath([]uint32{
+		purpose,
+		bip32.FirstHardenedChild + uint32(coinType),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coin{ck}, nil
+}
+
+// Account derives the account' node under the coin_type node
+func (c *Coin) Account(account uint32) (*Account, error) {
+	if account >= bip32.FirstHardenedChild {
+		return nil, ErrHardenedChildNumber
+	}
+
+	k, err := c.NewPrivateChildKey(bip32.FirstHardenedChild + account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{k}, nil
+}
+
+// Account is a bip32 node at the account level of the bip44 path
+type Account struct {
+	*bip32.PrivateKey
+}
+
+// External returns the external chain node of the account
+func (a *Account) External() (*bip32.PrivateKey, error) {
+	return a.NewPrivateChildKey(ExternalChainIndex)
+}
+
+// Change returns the change chain node of the account
+func (a *Account) Change() (*bip32.PrivateKey, error) {
+	return a.NewPrivateChildKey(ChangeChainIndex)
+}
+
+// Chain returns the chain node of the account for the given chain index
+func (a *Account) Chain(chain uint32) (*bip32.PrivateKey, error) {
+	switch chain {
+	case ExternalChainIndex, ChangeChainIndex:
+		return a.NewPrivateChildKey(chain)
+	default:
+		return nil, ErrInvalidChain
+	}
+}
@@ -0,0 +1,67 @@
+package bip44
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+)
+
+func TestNewCoin(t *testing.T) {
+	seed := bip39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+
+	// Compare against manual derivation along m/44'/8000'/0'/0/0
+	mk, err := bip32.NewMasterKey(seed)
+	require.NoError(t, err)
+	path, err := bip32.ParsePath("m/44'/8000'/0'/0/0")
+	require.NoError(t, err)
+	expected, err := mk.DeriveSubpath(path)
+	require.NoError(t, err)
+
+	c, err := NewCoin(seed, CoinTypeSkycoin)
+	require.NoError(t, err)
+
+	acct, err := c.Account(0)
+	require.NoError(t, err)
+
+	ext, err := acct.External()
+	require.NoError(t, err)
+
+	k, err := ext.NewPrivateChildKey(0)
+	require.NoError(t, err)
+	require.Equal(t, expected, k)
+
+	// External and change chains are different
+	change, err := acct.Change()
+	require.NoError(t, err)
+	require.NotEqual(t, ext.Key, change.Key)
+
+	chain, err := acct.Chain(ChangeChainIndex)
+	require.NoError(t, err)
+	require.Equal(t, change, chain)
+
+	_, err = acct.Chain(2)
+	require.Equal(t, ErrInvalidChain, err)
+
+	_, err = c.Account(bip32.FirstHardenedChild)
+	require.Equal(t, ErrHardenedChildNumber, err)
+
+	// Bitcoin and Skycoin coin types derive different keys
+	bc, err := NewCoin(seed, CoinTypeBitcoin)
+	require.NoError(t, err)
+	require.NotEqual(t, c.Key, bc.Key)
+
+	// Known bitcoin address for m/44'/0'/0'/0/0 of this mnemonic
+	bacct, err := bc.Account(0)
+	require.NoError(t, err)
+	bext, err := bacct.External()
+	require.NoError(t, err)
+	bk, err := bext.NewPublicChildKey(0)
+	require.NoError(t, err)
+	pk, err := bk.PubKey()
+	require.NoError(t, err)
+	require.Equal(t, "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", cipher.BitcoinAddressFromPubKey(pk).String())
+}
@@ -197,3 +197,64 @@ func XORKeyStream(out, in []byte, counter *[16]byte, key *[32]byte) {
 		}
 	}
 }
+
+// HChaCha20 uses the ChaCha20 core to derive a 32-byte subkey from a 32-byte
+// key and the first 16 bytes of a nonce, and puts the result into out. It
+// should only be used as part of the XChaCha20 construction, as specified in
+// https://tools.ietf.org/html/draft-irtf-cfrg-xchacha-01#section-2.2.
+func HChaCha20(out *[32]byte, nonce *[16]byte, key *[32]byte) {
+	x := [16]uint32{
+		0x61707865, 0x3320646e, 0x79622d32, 0x6b206574,
+		binary.LittleEndian.Uint32(key[0:4]),
+		binary.LittleEndian.Uint32(key[4:8]),
+		binary.LittleEndian.Uint32(key[8:12]),
+		binary.LittleEndian.Uint32(key[12:16]),
+		binary.LittleEndian.Uint32(key[16:20]),
+		binary.LittleEndian.Uint32(key[20:24]),
+		binary.LittleEndian.Uint32(key[24:28]),
+		binary.LittleEndian.Uint32(key[28:32]),
+		binary.LittleEndian.Uint32(nonce[0:4]),
+		binary.LittleEndian.Uint32(nonce[4:8]),
+		binary.LittleEndian.Uint32(nonce[8:12]),
+		binary.LittleEndian.Uint32(nonce[12:16]),
+	}
+
+	for i := 0; i < rounds; i += 2 {
+		// Column round
+		quarterRound(&x[0], &x[4], &x[8], &x[12])
+		quarterRound(&x[1], &x[5], &x[9], &x[13])
+		quarterRound(&x[2], &x[6], &x[10], &x[14])
+		quarterRound(&x[3], &x[7], &x[11], &x[15])
+
+		// Diagonal round
+		quarterRound(&x[0], &x[5], &x[10], &x[15])
+		quarterRound(&x[1], &x[6], &x[11], &x[12])
+		quarterRound(&x[2], &x[7], &x[8], &x[13])
+		quarterRound(&x[3], &x[4], &x[9], &x[14])
+	}
+
+	binary.LittleEndian.PutUint32(out[0:4], x[0])
+	binary.LittleEndian.PutUint32(out[4:8], x[1])
+	binary.LittleEndian.PutUint32(out[8:12], x[2])
+	binary.LittleEndian.PutUint32(out[12:16], x[3])
+	binary.LittleEndian.PutUint32(out[16:20], x[12])
+	binary.LittleEndian.PutUint32(out[20:24], x[13])
+	binary.LittleEndian.PutUint32(out[24:28], x[14])
+	binary.LittleEndian.PutUint32(out[28:32], x[15])
+}
+
+// quarterRound applies the ChaCha20 quarter round to a, b, c and d
+func quarterRound(a, b, c, d *uint32) {
+	*a += *b
+	*d ^= *a
+	*d = (*d << 16) | (*d >> 16)
+	*c += *d
+	*b ^= *c
+	*b = (*b << 12) | (*b >> 20)
+	*a += *b
+	*d ^= *a
+	*d = (*d << 8) | (*d >> 24)
+	*c += *d
+	*b ^= *c
+	*b = (*b << 7) | (*b >> 25)
+}
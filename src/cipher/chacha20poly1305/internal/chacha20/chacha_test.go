@@ -31,3 +31,22 @@ func TestCore(t *testing.T) {
 		t.Errorf("wanted %x but got %x", expected, result)
 	}
 }
+
+func TestHChaCha20(t *testing.T) {
+	// This checks the example from
+	// https://tools.ietf.org/html/draft-irtf-cfrg-xchacha-01#section-2.2.1
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	var nonce [16]byte
+	copy(nonce[:], []byte{0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x4a, 0x00, 0x00, 0x00, 0x00, 0x31, 0x41, 0x59, 0x27})
+
+	var out [32]byte
+	HChaCha20(&out, &nonce, &key)
+	const expected = "82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc"
+	if result := hex.EncodeToString(out[:]); result != expected {
+		t.Errorf("wanted %s but got %s", expected, result)
+	}
+}
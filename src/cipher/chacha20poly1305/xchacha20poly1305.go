@@ -0,0 +1,92 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package chacha20poly1305
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher/chacha20poly1305/internal/chacha20"
+)
+
+// NonceSizeX is the size of the nonce used with the XChaCha20-Poly1305 AEAD, in bytes.
+const NonceSizeX = 24
+
+type xchacha20poly1305 struct {
+	key [KeySize]byte
+}
+
+// NewX returns a XChaCha20-Poly1305 AEAD that uses the given 256-bit key.
+//
+// XChaCha20-Poly1305 is a ChaCha20-Poly1305 variant that takes a longer nonce,
+// suitable to be generated randomly without risk of collisions. It should be
+// preferred when nonce uniqueness cannot be trivially ensured, or whenever
+// nonces are randomly generated.
+func NewX(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("chacha20poly1305: bad key length")
+	}
+	ret := new(xchacha20poly1305)
+	copy(ret.key[:], key)
+	return ret, nil
+}
+
+func (*xchacha20poly1305) NonceSize() int {
+	return NonceSizeX
+}
+
+func (*xchacha20poly1305) Overhead() int {
+	return 16
+}
+
+func (x *xchacha20poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != NonceSizeX {
+		panic("chacha20poly1305: bad nonce length passed to Seal")
+	}
+
+	// XChaCha20-Poly1305 technically supports a 64-bit counter, so there is no
+	// size limit. However, since we reuse the ChaCha20-Poly1305 implementation,
+	// the second half of the counter is not available. This is unlikely to be
+	// an issue because the cipher.AEAD API requires the entire message to be in
+	// memory, and the counter overflows at 256 GB.
+	if uint64(len(plaintext)) > (1<<38)-64 {
+		panic("chacha20poly1305: plaintext too large")
+	}
+
+	c, cNonce := x.derive(nonce)
+	return c.seal(dst, cNonce[:], plaintext, additionalData)
+}
+
+func (x *xchacha20poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != NonceSizeX {
+		panic("chacha20poly1305: bad nonce length passed to Open")
+	}
+	if len(ciphertext) < 16 {
+		return nil, errOpen
+	}
+	if uint64(len(ciphertext)) > (1<<38)-48 {
+		panic("chacha20poly1305: ciphertext too large")
+	}
+
+	c, cNonce := x.derive(nonce)
+	return c.open(dst, cNonce[:], ciphertext, additionalData)
+}
+
+// derive returns the ChaCha20-Poly1305 AEAD keyed with the HChaCha20 subkey
+// of the first 16 bytes of the nonce, and the ChaCha20-Poly1305 nonce made of
+// the last 8 bytes of the nonce.
+func (x *xchacha20poly1305) derive(nonce []byte) (*chacha20poly1305, [NonceSize]byte) {
+	var hNonce [16]byte
+	copy(hNonce[:], nonce[0:16])
+
+	c := new(chacha20poly1305)
+	chacha20.HChaCha20(&c.key, &hNonce, &x.key)
+
+	// The first 4 bytes of the final nonce are unused counter space.
+	var cNonce [NonceSize]byte
+	copy(cNonce[4:12], nonce[16:24])
+
+	return c, cNonce
+}
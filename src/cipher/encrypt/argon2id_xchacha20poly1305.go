@@ -0,0 +1,192 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/argon2"
+	"github.com/skycoin/skycoin/src/cipher/chacha20poly1305"
+)
+
+const (
+	argon2idXChacha20MetaLengthSize = 2  // meta data length field size in bytes
+	argon2idXChacha20SaltSize       = 32 // salt bytes number
+)
+
+// Default argon2id parameters
+const (
+	// Argon2idTime: argon2id time parameter, the number of passes over the memory.
+	Argon2idTime = 3
+	// Argon2idMemory: argon2id memory parameter, the size of the memory in KiB. Time 3, memory 64 MiB and 4 threads is the second recommended option of RFC 9106.
+	Argon2idMemory = 64 * 1024
+	// Argon2idThreads: argon2id threads parameter, the number of threads the memory is processed with.
+	Argon2idThreads = 4
+	// Argon2idKeyLen: The length of returned byte slice that can be used as cryptographic key.
+	Argon2idKeyLen = chacha20poly1305.KeySize
+)
+
+// Maximum argon2id parameters. The parameters are read from the untrusted header of the ciphertext,
+// so they are bounded to keep a crafted header from exhausting the CPU and memory when decrypting.
+const (
+	// Argon2idMaxTime: maximum argon2id time parameter, 4 times the default.
+	Argon2idMaxTime = 4 * Argon2idTime
+	// Argon2idMaxMemory: maximum argon2id memory parameter in KiB, 4 times the default (256 MiB).
+	Argon2idMaxMemory = 4 * Argon2idMemory
+)
+
+// DefaultArgon2idXChacha20poly1305 default Argon2idXChacha20poly1305 encryptor
+var DefaultArgon2idXChacha20poly1305 = Argon2idXChacha20poly1305{
+	Time:    Argon2idTime,
+	Memory:  Argon2idMemory,
+	Threads: Argon2idThreads,
+	KeyLen:  Argon2idKeyLen,
+}
+
+// Argon2idXChacha20poly1305 provides methods for encryption/decryption with argon2id and xchacha20poly1305
+type Argon2idXChacha20poly1305 struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+}
+
+type argon2idMeta struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	KeyLen  uint32 `json:"keyLen"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+}
+
+// key derives the key from password with the argon2id parameters in metadata
+func (m argon2idMeta) key(password []byte) ([]byte, error) {
+	if m.Time < 1 || m.Time > Argon2idMaxTime {
+		return nil, errors.New("invalid argon2id time parameter")
+	}
+	if m.Memory > Argon2idMaxMemory {
+		return nil, errors.New("invalid argon2id memory parameter")
+	}
+	if m.Threads < 1 {
+		return nil, errors.New("invalid argon2id threads parameter")
+	}
+	if m.KeyLen != chacha20poly1305.KeySize {
+		return nil, errors.New("invalid argon2id key length")
+	}
+
+	return argon2.IDKey(password, m.Salt, m.Time, m.Memory, m.Threads, m.KeyLen), nil
+}
+
+// Encrypt encrypts data with password,
+// 1. Argon2id derives the key from password
+// 2. XChacha20poly1305 generates AEAD from the derived key
+// 3. Puts argon2id parameters, salt and nonce into metadata, json serialize it and get the serialized metadata length
+// 4. AEAD.Seal encrypts the data, and use [length][metadata] as additional data
+// 5. Final format: base64([[length][metadata]][ciphertext]), length is 2 bytes.
+func (a Argon2idXChacha20poly1305) Encrypt(data, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("missing password")
+	}
+
+	// Prepare metadata
+	m := argon2idMeta{
+		Time:    a.Time,
+		Memory:  a.Memory,
+		Threads: a.Threads,
+		KeyLen:  a.KeyLen,
+		Salt:    cipher.RandByte(argon2idXChacha20SaltSize),
+		Nonce:   cipher.RandByte(chacha20poly1305.NonceSizeX),
+	}
+
+	// Argon2id derives key from password
+	dk, err := m.key(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// json serialize the metadata
+	ms, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ms) > math.MaxUint16 {
+		return nil, errors.New("metadata length beyond the math.MaxUint16")
+	}
+
+	length := make([]byte, argon2idXChacha20MetaLengthSize)
+	binary.LittleEndian.PutUint16(length, uint16(len(ms)))
+
+	// Additional data for AEAD
+	ad := append(length, ms...)
+	aead, err := chacha20poly1305.NewX(dk)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := aead.Seal(nil, m.Nonce, data, ad)
+
+	// Base64 encode the [[length][metadata]][ciphertext]
+	rawData := append(ad, ciphertext...)
+	enc := base64.StdEncoding
+	buf := make([]byte, enc.EncodedLen(len(rawData)))
+	enc.Encode(buf, rawData)
+	return buf, nil
+}
+
+// Decrypt decrypts the data with password
+// 1. Base64 decodes the data
+// 2. Reads the first [metaLengthSize] bytes data to get the metadata length, and reads out the metadata.
+// 3. Argon2id derives key from password and parameters in metadata, which must not exceed Argon2idMaxTime and Argon2idMaxMemory
+// 4. XChacha20poly1305 generates AEAD
+// 5. AEAD decrypts ciphertext with nonce in metadata and [length][metadata] as additional data.
+func (a Argon2idXChacha20poly1305) Decrypt(data, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("missing password")
+	}
+
+	enc := base64.StdEncoding
+	encData := make([]byte, enc.DecodedLen(len(data)))
+	n, err := enc.Decode(encData, data)
+	if err != nil {
+		return nil, err
+	}
+	encData = encData[:n]
+
+	if len(encData) < argon2idXChacha20MetaLengthSize {
+		return nil, errors.New("invalid metadata length")
+	}
+
+	length := binary.LittleEndian.Uint16(encData[:argon2idXChacha20MetaLengthSize])
+	if argon2idXChacha20MetaLengthSize+int(length) > len(encData) {
+		return nil, errors.New("invalid metadata length")
+	}
+
+	var m argon2idMeta
+	if err := json.Unmarshal(encData[argon2idXChacha20MetaLengthSize:argon2idXChacha20MetaLengthSize+int(length)], &m); err != nil {
+		return nil, err
+	}
+
+	if len(m.Nonce) != chacha20poly1305.NonceSizeX {
+		return nil, errors.New("invalid nonce length")
+	}
+
+	ad := encData[:argon2idXChacha20MetaLengthSize+int(length)]
+	// Argon2id derives key
+	dk, err := m.key(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generates AEAD
+	aead, err := chacha20poly1305.NewX(dk)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, m.Nonce, encData[argon2idXChacha20MetaLengthSize+int(length):], ad)
+}
@@ -0,0 +1,188 @@
+package encrypt
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgon2idXChacha20poly1305Encrypt(t *testing.T) {
+	for _, memory := range []uint32{64, 256, 1024} {
+		for _, time := range []uint32{1, 2} {
+			name := fmt.Sprintf("time=%v memory=%v threads=%v keyLen=%v", time, memory, 2, 32)
+			t.Run(name, func(t *testing.T) {
+				crypto := Argon2idXChacha20poly1305{Time: time, Memory: memory, Threads: 2, KeyLen: 32}
+				encData, err := crypto.Encrypt([]byte("plaintext"), []byte("password"))
+				require.NoError(t, err)
+
+				data, err := base64.StdEncoding.DecodeString(string(encData))
+				require.NoError(t, err)
+				// Checks the prefix
+				ml := binary.LittleEndian.Uint16(data[:argon2idXChacha20MetaLengthSize])
+				require.True(t, int(argon2idXChacha20MetaLengthSize+ml) <= len(data))
+				var m argon2idMeta
+				require.NoError(t, json.Unmarshal(data[argon2idXChacha20MetaLengthSize:argon2idXChacha20MetaLengthSize+ml], &m))
+				require.Equal(t, time, m.Time)
+				require.Equal(t, memory, m.Memory)
+				require.Equal(t, uint8(2), m.Threads)
+				require.Equal(t, uint32(32), m.KeyLen)
+				require.Len(t, m.Salt, argon2idXChacha20SaltSize)
+				require.Len(t, m.Nonce, 24)
+
+				// The parameters of the ciphertext header are used to decrypt, not the parameters of the decryptor
+				plaintext, err := Argon2idXChacha20poly1305{}.Decrypt(encData, []byte("password"))
+				require.NoError(t, err)
+				require.Equal(t, []byte("plaintext"), plaintext)
+			})
+		}
+	}
+}
+
+func TestArgon2idXChacha20poly1305EncryptInvalidParameters(t *testing.T) {
+	tt := []struct {
+		name   string
+		crypto Argon2idXChacha20poly1305
+		err    error
+	}{
+		{
+			name:   "invalid time",
+			crypto: Argon2idXChacha20poly1305{Time: 0, Memory: 64, Threads: 1, KeyLen: 32},
+			err:    errors.New("invalid argon2id time parameter"),
+		},
+		{
+			name:   "invalid threads",
+			crypto: Argon2idXChacha20poly1305{Time: 1, Memory: 64, Threads: 0, KeyLen: 32},
+			err:    errors.New("invalid argon2id threads parameter"),
+		},
+		{
+			name:   "invalid key length",
+			crypto: Argon2idXChacha20poly1305{Time: 1, Memory: 64, Threads: 1, KeyLen: 16},
+			err:    errors.New("invalid argon2id key length"),
+		},
+		{
+			name:   "time above maximum",
+			crypto: Argon2idXChacha20poly1305{Time: Argon2idMaxTime + 1, Memory: 64, Threads: 1, KeyLen: 32},
+			err:    errors.New("invalid argon2id time parameter"),
+		},
+		{
+			name:   "memory above maximum",
+			crypto: Argon2idXChacha20poly1305{Time: 1, Memory: Argon2idMaxMemory + 1, Threads: 1, KeyLen: 32},
+			err:    errors.New("invalid argon2id memory parameter"),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.crypto.Encrypt([]byte("plaintext"), []byte("password"))
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+// makeArgon2idHeader returns a ciphertext with metadata m and no encrypted data
+func makeArgon2idHeader(t *testing.T, m argon2idMeta) []byte {
+	ms, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	data := make([]byte, argon2idXChacha20MetaLengthSize)
+	binary.LittleEndian.PutUint16(data, uint16(len(ms)))
+	data = append(data, ms...)
+
+	return []byte(base64.StdEncoding.EncodeToString(data))
+}
+
+func TestArgon2idXChacha20poly1305Decrypt(t *testing.T) {
+	encData := []byte("jwB7InRpbWUiOjEsIm1lbW9yeSI6NjQsInRocmVhZHMiOjEsImtleUxlbiI6MzIsInNhbHQiOiJwN3BKcUlKbGdCc2hmMGE5NlA5cXNsVzAzbGlldWtzdmVvRlpaMmx3amIwPSIsIm5vbmNlIjoiOERjbDg2M3JPa3FWc1E3ODU0M2hsOFlkNDVWMXo1UmsifTKWCl2sM4B0SjfOXF4c/ofDVK1sb4mcvNU=")
+
+	tt := []struct {
+		name    string
+		data    []byte
+		encData []byte
+		decPwd  []byte
+		err     error
+	}{
+		{
+			name:    "ok",
+			data:    []byte("plaintext"),
+			encData: encData,
+			decPwd:  []byte("pwd"),
+			err:     nil,
+		},
+		{
+			name:    "invalid password",
+			encData: encData,
+			decPwd:  []byte("wrong password"),
+			err:     errors.New("chacha20poly1305: message authentication failed"),
+		},
+		{
+			name:    "missing password",
+			encData: encData,
+			decPwd:  nil,
+			err:     errors.New("missing password"),
+		},
+		{
+			name:    "missing metadata",
+			encData: []byte("jw=="),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("invalid metadata length"),
+		},
+		{
+			name:    "invalid metadata length",
+			encData: []byte("jwB7fQ=="),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("invalid metadata length"),
+		},
+		{
+			name:    "invalid nonce length",
+			encData: []byte("AgB7fQ=="),
+			decPwd:  []byte("pwd"),
+			err:     errors.New("invalid nonce length"),
+		},
+		{
+			name: "header time above maximum",
+			encData: makeArgon2idHeader(t, argon2idMeta{
+				Time:    Argon2idMaxTime + 1,
+				Memory:  64,
+				Threads: 1,
+				KeyLen:  32,
+				Salt:    make([]byte, argon2idXChacha20SaltSize),
+				Nonce:   make([]byte, 24),
+			}),
+			decPwd: []byte("pwd"),
+			err:    errors.New("invalid argon2id time parameter"),
+		},
+		{
+			name: "header memory above maximum",
+			encData: makeArgon2idHeader(t, argon2idMeta{
+				Time:    1,
+				Memory:  math.MaxUint32,
+				Threads: 1,
+				KeyLen:  32,
+				Salt:    make([]byte, argon2idXChacha20SaltSize),
+				Nonce:   make([]byte, 24),
+			}),
+			decPwd: []byte("pwd"),
+			err:    errors.New("invalid argon2id memory parameter"),
+		},
+	}
+
+	for _, tc := range tt {
+		name := fmt.Sprintf("time=1 memory=64 threads=1 keyLen=32 %v", tc.name)
+		t.Run(name, func(t *testing.T) {
+			crypto := Argon2idXChacha20poly1305{}
+			data, err := crypto.Decrypt(tc.encData, tc.decPwd)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.data, data)
+		})
+	}
+}
@@ -4,7 +4,9 @@ Package bip39 implements mnemonic seeds as defined in BIP 39 https://github.com/
 package bip39
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/pbkdf2"
 )
 
 // Some bitwise operands for working with big.Ints
@@ -22,6 +25,9 @@ var (
 	BigTwo                  = big.NewInt(2)
 )
 
+// ErrChecksumIncorrect is returned if the checksum of a mnemonic does not match its entropy
+var ErrChecksumIncorrect = errors.New("Checksum incorrect")
+
 // DefaultMnemonicEntropyBitSize is the default bit size for NewDefaultMnemonic's entropy
 const DefaultMnemonicEntropyBitSize = 128
 
@@ -107,13 +113,8 @@ func NewMnemonic(entropy []byte) (string, error) {
 
 // MnemonicToByteArray takes a mnemonic string and turns it into a byte array
 // suitable for creating another mnemonic.
-// An error is returned if the mnemonic is invalid.
-// FIXME
-// This does not work for all values in
-// the test vectors.  Namely
-// Vectors 0, 4, and 8.
-// This is not really important because BIP39 doesnt really define a conversion
-// from string to bytes.
+// The byte array is the entropy followed by the checksum bits.
+// An error is returned if the mnemonic is invalid or its checksum is incorrect.
 func MnemonicToByteArray(mnemonic string) ([]byte, error) {
 	if !IsMnemonicValid(mnemonic) {
 		return nil, fmt.Errorf("Invalid mnemonic")
@@ -126,6 +127,9 @@ func MnemonicToByteArray(mnemonic string) ([]byte, error) {
 		return nil, err
 	}
 	checksumSize := bitSize % 32
+	entropySize := (bitSize - checksumSize) / 8
+	// The checksum is at most 8 bits, it fits in one extra byte
+	byteSize := entropySize + 1
 
 	b := big.NewInt(0)
 	modulo := big.NewInt(2048)
@@ -138,62 +142,41 @@ func MnemonicToByteArray(mnemonic string) ([]byte, error) {
 		b = b.Mul(b, modulo)
 		b = b.Add(b, add)
 	}
-	hex := b.Bytes()
 	checksumModulo := big.NewInt(0).Exp(big.NewInt(2), big.NewInt(int64(checksumSize)), nil)
 	entropy, _ := big.NewInt(0).DivMod(b, checksumModulo, big.NewInt(0))
 
-	entropyHex := entropy.Bytes()
-
-	byteSize := bitSize/8 + 1
-	if len(hex) != byteSize {
-		tmp := make([]byte, byteSize)
-		diff := byteSize - len(hex)
-		for i := 0; i < len(hex); i++ {
-			tmp[i+diff] = hex[i]
-		}
-		hex = tmp
-	}
+	// big.Int.Bytes() drops the leading zero bytes, which must be restored
+	// before computing the checksum of the entropy
+	hex := padByteSlice(b.Bytes(), byteSize)
+	entropyHex := padByteSlice(entropy.Bytes(), entropySize)
 
 	validationHex, err := addChecksum(entropyHex)
 	if err != nil {
 		return nil, err
 	}
+	validationHex = padByteSlice(validationHex, byteSize)
 
-	if len(validationHex) != byteSize {
-		tmp2 := make([]byte, byteSize)
-		diff2 := byteSize - len(validationHex)
-		for i := 0; i < len(validationHex); i++ {
-			tmp2[i+diff2] = validationHex[i]
-		}
-		validationHex = tmp2
-	}
-
-	if len(hex) != len(validationHex) {
-		panic("[]byte len mismatch - it shouldn't happen")
-	}
-	for i := range validationHex {
-		if hex[i] != validationHex[i] {
-			return nil, fmt.Errorf("Invalid byte at position %v", i)
-		}
+	if !bytes.Equal(hex, validationHex) {
+		return nil, ErrChecksumIncorrect
 	}
 	return hex, nil
 }
 
 // NewSeedWithErrorChecking creates a hashed seed output given the mnemonic string and a password.
 // An error is returned if the mnemonic is not convertible to a byte array.
-// func NewSeedWithErrorChecking(mnemonic string, password string) ([]byte, error) {
-// 	_, err := MnemonicToByteArray(mnemonic)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return NewSeed(mnemonic, password), nil
-// }
+func NewSeedWithErrorChecking(mnemonic string, password string) ([]byte, error) {
+	_, err := MnemonicToByteArray(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeed(mnemonic, password), nil
+}
 
 // NewSeed creates a hashed seed output given a provided string and password.
 // No checking is performed to validate that the string provided is a valid mnemonic.
-// func NewSeed(mnemonic string, password string) []byte {
-// 	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+password), 2048, 64, sha512.New)
-// }
+func NewSeed(mnemonic string, password string) []byte {
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+password), 2048, 64, sha512.New)
+}
 
 // Appends to data the first (len(data) / 32)bits of the result of sha256(data)
 // Currently only supports data up to 32 bytes
@@ -226,7 +209,7 @@ func addChecksum(data []byte) ([]byte, error) {
 	return dataBigInt.Bytes(), nil
 }
 
-func padByteSlice(slice []byte, length int) []byte { // nolint: unparam
+func padByteSlice(slice []byte, length int) []byte {
 	newSlice := make([]byte, length-len(slice))
 	return append(newSlice, slice...)
 }
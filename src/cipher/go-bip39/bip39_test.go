@@ -1,6 +1,7 @@
 package bip39
 
 import (
+	"encoding/hex"
 	"strings"
 	"testing"
 
@@ -60,3 +61,93 @@ func TestIsMnemonicValid(t *testing.T) {
 	m = strings.Join(ms[:len(ms)-1], " ")
 	require.False(t, IsMnemonicValid(m))
 }
+
+func TestNewSeed(t *testing.T) {
+	m := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	seed := NewSeed(m, "TREZOR")
+	require.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+
+	seed = NewSeed(m, "")
+	require.Equal(t, "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4", hex.EncodeToString(seed))
+
+	m, err := NewDefaultMnemonic()
+	require.NoError(t, err)
+
+	seed, err = NewSeedWithErrorChecking(m, "")
+	require.NoError(t, err)
+	require.Equal(t, NewSeed(m, ""), seed)
+
+	_, err = NewSeedWithErrorChecking(m+" abandon", "")
+	require.Error(t, err)
+}
+
+func TestMnemonicToByteArray(t *testing.T) {
+	cases := []struct {
+		entropy  string
+		mnemonic string
+		bytes    string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			bytes:    "0000000000000000000000000000000003",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			entropy:  "80808080808080808080808080808080",
+			mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		},
+		{
+			entropy:  "000000000000000000000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		},
+		{
+			entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			bytes:    "000000000000000000000000000000000000000000000000000000000000000066",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.mnemonic, func(t *testing.T) {
+			entropy, err := hex.DecodeString(tc.entropy)
+			require.NoError(t, err)
+
+			m, err := NewMnemonic(entropy)
+			require.NoError(t, err)
+			require.Equal(t, tc.mnemonic, m)
+
+			b, err := MnemonicToByteArray(tc.mnemonic)
+			require.NoError(t, err)
+			require.Len(t, b, len(entropy)+1)
+			if tc.bytes != "" {
+				require.Equal(t, tc.bytes, hex.EncodeToString(b))
+			}
+		})
+	}
+
+	// Wrong checksum
+	_, err := MnemonicToByteArray("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+	require.Equal(t, ErrChecksumIncorrect, err)
+
+	_, err = MnemonicToByteArray("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo")
+	require.Equal(t, ErrChecksumIncorrect, err)
+
+	_, err = MnemonicToByteArray("abandon abandon abandon")
+	require.Error(t, err)
+
+	for i := 0; i < 100; i++ {
+		m, err := NewDefaultMnemonic()
+		require.NoError(t, err)
+		_, err = MnemonicToByteArray(m)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,84 @@
+package cipher
+
+import (
+	"errors"
+)
+
+const (
+	// MultisigAddressVersion is the address version of m-of-n multisignature addresses
+	MultisigAddressVersion byte = 0x01
+	// MaxMultisigPubKeys is the maximum number of public keys of a multisignature address
+	MaxMultisigPubKeys = 16
+)
+
+var (
+	// ErrMultisigInvalidRequired Number of required signatures out of range
+	ErrMultisigInvalidRequired = errors.New("Number of required signatures must be between 1 and the number of public keys")
+	// ErrMultisigTooManyPubKeys Too many public keys for a multisignature address
+	ErrMultisigTooManyPubKeys = errors.New("Too many public keys for multisig address")
+	// ErrMultisigDuplicatePubKey Public key appears twice in a multisignature address
+	ErrMultisigDuplicatePubKey = errors.New("Duplicate public key in multisig address")
+)
+
+/*
+Multisignature addresses are the Ripemd160 of the double SHA256 of the redeem script,
+with version byte MultisigAddressVersion.
+
+The redeem script is
+- 1 byte, the number of required signatures m
+- 1 byte, the number of public keys n
+- n*33 bytes, the compressed public keys in order
+
+The order of the public keys is significant: the same keys in a different order
+produce a different address.
+*/
+
+// MultisigRedeemScript returns the serialized m-of-n redeem script of a multisignature address
+func MultisigRedeemScript(required int, pubKeys []PubKey) ([]byte, error) {
+	if len(pubKeys) > MaxMultisigPubKeys {
+		return nil, ErrMultisigTooManyPubKeys
+	}
+	if required < 1 || required > len(pubKeys) {
+		return nil, ErrMultisigInvalidRequired
+	}
+
+	seen := make(map[PubKey]struct{}, len(pubKeys))
+	for _, p := range pubKeys {
+		if err := p.Verify(); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[p]; ok {
+			return nil, ErrMultisigDuplicatePubKey
+		}
+		seen[p] = struct{}{}
+	}
+
+	b := make([]byte, 0, 2+len(pubKeys)*len(PubKey{}))
+	b = append(b, byte(required), byte(len(pubKeys)))
+	for _, p := range pubKeys {
+		b = append(b, p[:]...)
+	}
+
+	return b, nil
+}
+
+// MultisigAddressFromPubKeys creates an m-of-n multisignature Address which requires
+// signatures from required of the pubKeys to spend
+func MultisigAddressFromPubKeys(required int, pubKeys []PubKey) (Address, error) {
+	script, err := MultisigRedeemScript(required, pubKeys)
+	if err != nil {
+		return Address{}, err
+	}
+
+	r1 := SumSHA256(script)
+	r2 := SumSHA256(r1[:])
+	return Address{
+		Version: MultisigAddressVersion,
+		Key:     HashRipemd160(r2[:]),
+	}, nil
+}
+
+// IsMultisig returns true if the address is a multisignature address
+func (addr Address) IsMultisig() bool {
+	return addr.Version == MultisigAddressVersion
+}
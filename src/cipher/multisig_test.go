@@ -0,0 +1,88 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultisigAddressFromPubKeys(t *testing.T) {
+	p1, _ := GenerateKeyPair()
+	p2, _ := GenerateKeyPair()
+	p3, _ := GenerateKeyPair()
+
+	a, err := MultisigAddressFromPubKeys(2, []PubKey{p1, p2, p3})
+	require.NoError(t, err)
+	require.Equal(t, MultisigAddressVersion, a.Version)
+	require.True(t, a.IsMultisig())
+	require.False(t, AddressFromPubKey(p1).IsMultisig())
+
+	// Multisig addresses never verify against a single public key
+	require.Equal(t, ErrAddressInvalidVersion, a.Verify(p1))
+
+	// The address roundtrips through its base58 encoding
+	a2, err := DecodeBase58Address(a.String())
+	require.NoError(t, err)
+	require.Equal(t, a, a2)
+
+	// The address depends on m and on the order of the public keys
+	b, err := MultisigAddressFromPubKeys(2, []PubKey{p1, p2, p3})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+	b, err = MultisigAddressFromPubKeys(3, []PubKey{p1, p2, p3})
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+	b, err = MultisigAddressFromPubKeys(2, []PubKey{p2, p1, p3})
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+
+	cases := []struct {
+		name     string
+		required int
+		pubKeys  []PubKey
+		err      error
+	}{
+		{
+			name:     "no pubkeys",
+			required: 1,
+			err:      ErrMultisigInvalidRequired,
+		},
+		{
+			name:     "zero required",
+			required: 0,
+			pubKeys:  []PubKey{p1},
+			err:      ErrMultisigInvalidRequired,
+		},
+		{
+			name:     "required exceeds pubkeys",
+			required: 3,
+			pubKeys:  []PubKey{p1, p2},
+			err:      ErrMultisigInvalidRequired,
+		},
+		{
+			name:     "duplicate pubkey",
+			required: 1,
+			pubKeys:  []PubKey{p1, p2, p1},
+			err:      ErrMultisigDuplicatePubKey,
+		},
+		{
+			name:     "too many pubkeys",
+			required: 1,
+			pubKeys:  make([]PubKey, MaxMultisigPubKeys+1),
+			err:      ErrMultisigTooManyPubKeys,
+		},
+		{
+			name:     "invalid pubkey",
+			required: 1,
+			pubKeys:  []PubKey{p1, {}},
+			err:      ErrInvalidPubKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := MultisigAddressFromPubKeys(tc.required, tc.pubKeys)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
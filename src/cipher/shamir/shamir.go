@@ -0,0 +1,171 @@
+/*
+Package shamir implements Shamir's secret sharing over GF(2^8)
+https://en.wikipedia.org/wiki/Shamir%27s_Secret_Sharing
+
+A secret is split into n shares, any threshold of which recover the secret,
+while fewer shares reveal nothing about it.
+Each byte of the secret is the constant term of its own random polynomial of degree threshold-1,
+and a share is the evaluation of the polynomials at a distinct non-zero x coordinate.
+The field is GF(2^8) with the AES reduction polynomial x^8 + x^4 + x^3 + x + 1.
+*/
+package shamir // import "github.com/skycoin/skycoin/src/cipher/shamir"
+
+import (
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// MaxShares is the maximum number of shares of a secret, one for each non-zero element of GF(2^8)
+const MaxShares = 255
+
+var (
+	// ErrEmptySecret is returned when trying to split an empty secret
+	ErrEmptySecret = errors.New("secret is empty")
+	// ErrInvalidShareCount is returned if the number of shares is not in [2, MaxShares]
+	ErrInvalidShareCount = errors.New("number of shares must be between 2 and 255")
+	// ErrInvalidThreshold is returned if the threshold is less than 2 or more than the number of shares
+	ErrInvalidThreshold = errors.New("threshold must be at least 2 and at most the number of shares")
+	// ErrNotEnoughShares is returned when trying to combine less than 2 shares
+	ErrNotEnoughShares = errors.New("at least 2 shares are required")
+	// ErrShareLengthMismatch is returned if the shares to combine have different lengths
+	ErrShareLengthMismatch = errors.New("shares must have the same length")
+	// ErrInvalidShareX is returned if a share has the x coordinate 0, which is the secret
+	ErrInvalidShareX = errors.New("share x coordinate must not be 0")
+	// ErrDuplicateShare is returned if two shares to combine have the same x coordinate
+	ErrDuplicateShare = errors.New("duplicate share x coordinate")
+)
+
+// Share is a share of a secret, the evaluation at X of the polynomials of each byte of the secret
+type Share struct {
+	X byte
+	Y []byte
+}
+
+// Split splits a secret into n shares, any threshold of which recover the secret with Combine.
+// The shares have the x coordinates 1 to n.
+func Split(secret []byte, n, threshold int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	if n < 2 || n > MaxShares {
+		return nil, ErrInvalidShareCount
+	}
+
+	if threshold < 2 || threshold > n {
+		return nil, ErrInvalidThreshold
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{
+			X: byte(i + 1),
+			Y: make([]byte, len(secret)),
+		}
+	}
+
+	coeffs := make([]byte, threshold)
+	defer erase(coeffs)
+
+	for i, s := range secret {
+		coeffs[0] = s
+		copy(coeffs[1:], cipher.RandByte(threshold-1))
+
+		for j := range shares {
+			shares[j].Y[i] = evaluate(coeffs, shares[j].X)
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine recovers the secret from shares created by Split.
+// The number of shares can't be checked against the threshold, fewer shares
+// than the threshold return a wrong secret.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrNotEnoughShares
+	}
+
+	n := len(shares[0].Y)
+	for i, s := range shares {
+		if len(s.Y) != n {
+			return nil, ErrShareLengthMismatch
+		}
+
+		if s.X == 0 {
+			return nil, ErrInvalidShareX
+		}
+
+		for _, t := range shares[:i] {
+			if s.X == t.X {
+				return nil, ErrDuplicateShare
+			}
+		}
+	}
+
+	// Lagrange interpolation at x = 0. Addition and subtraction are both xor in GF(2^8).
+	secret := make([]byte, n)
+	for i, s := range shares {
+		basis := byte(1)
+		for j, t := range shares {
+			if i == j {
+				continue
+			}
+			basis = mul(basis, div(t.X, t.X^s.X))
+		}
+
+		for k, y := range s.Y {
+			secret[k] ^= mul(y, basis)
+		}
+	}
+
+	return secret, nil
+}
+
+// evaluate evaluates the polynomial with the coefficients coeffs, lowest degree first, at x
+func evaluate(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coeffs[i]
+	}
+	return y
+}
+
+// mul multiplies in GF(2^8), without branches or table lookups that depend on the operands
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// inverse returns the multiplicative inverse of a in GF(2^8), which is a^254. The inverse of 0 is 0.
+func inverse(a byte) byte {
+	b := mul(a, a) // a^2
+	c := mul(b, a) // a^3
+	b = mul(c, c)  // a^6
+	b = mul(b, b)  // a^12
+	c = mul(b, c)  // a^15
+	b = mul(b, b)  // a^24
+	b = mul(b, b)  // a^48
+	b = mul(b, c)  // a^63
+	b = mul(b, b)  // a^126
+	b = mul(b, a)  // a^127
+	return mul(b, b)
+}
+
+// div divides a by b in GF(2^8), b must not be 0
+func div(a, b byte) byte {
+	return mul(a, inverse(b))
+}
+
+func erase(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
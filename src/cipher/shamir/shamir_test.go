@@ -0,0 +1,160 @@
+package shamir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func TestFieldArithmetic(t *testing.T) {
+	// 0x53 * 0xca = 0x01 in the AES field
+	require.Equal(t, byte(0x01), mul(0x53, 0xca))
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+
+	for a := 0; a < 256; a++ {
+		require.Equal(t, byte(0), mul(byte(a), 0))
+		require.Equal(t, byte(a), mul(byte(a), 1))
+		if a == 0 {
+			require.Equal(t, byte(0), inverse(0))
+			continue
+		}
+		require.Equal(t, byte(1), mul(byte(a), inverse(byte(a))))
+		require.Equal(t, byte(a), div(mul(byte(a), 0x1d), 0x1d))
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+
+	cases := []struct {
+		n         int
+		threshold int
+	}{
+		{2, 2},
+		{3, 2},
+		{5, 3},
+		{7, 7},
+		{255, 4},
+	}
+
+	for _, tc := range cases {
+		shares, err := Split(secret, tc.n, tc.threshold)
+		require.NoError(t, err)
+		require.Len(t, shares, tc.n)
+
+		for i, s := range shares {
+			require.Equal(t, byte(i+1), s.X)
+			require.Len(t, s.Y, len(secret))
+		}
+
+		// Any threshold shares recover the secret, in any order
+		s, err := Combine(shares[:tc.threshold])
+		require.NoError(t, err)
+		require.Equal(t, secret, s)
+
+		s, err = Combine(shares[tc.n-tc.threshold:])
+		require.NoError(t, err)
+		require.Equal(t, secret, s)
+
+		reversed := make([]Share, tc.threshold)
+		for i := range reversed {
+			reversed[i] = shares[tc.n-1-i]
+		}
+		s, err = Combine(reversed)
+		require.NoError(t, err)
+		require.Equal(t, secret, s)
+
+		// More shares than the threshold recover the secret too
+		s, err = Combine(shares)
+		require.NoError(t, err)
+		require.Equal(t, secret, s)
+
+		// Fewer shares don't
+		if tc.threshold > 2 {
+			s, err = Combine(shares[:tc.threshold-1])
+			require.NoError(t, err)
+			require.NotEqual(t, secret, s)
+		}
+	}
+
+	// The shares are random
+	secret = cipher.RandByte(32)
+	shares1, err := Split(secret, 3, 2)
+	require.NoError(t, err)
+	shares2, err := Split(secret, 3, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, shares1, shares2)
+}
+
+func TestSplitSecrecy(t *testing.T) {
+	// All the coefficients are uniform in GF(2^8), zero included. Then the value interpolated
+	// at x = 0 from threshold-1 shares is uniform too, and takes every value, the secret included,
+	// so threshold-1 shares are consistent with every possible secret byte.
+	secret := make([]byte, 8192)
+	for i := range secret {
+		secret[i] = 0x5a
+	}
+
+	requireAllValues := func(values []byte) {
+		var seen [256]bool
+		for _, v := range values {
+			seen[v] = true
+		}
+		for v, ok := range seen {
+			require.True(t, ok, "value %d never occurs", v)
+		}
+	}
+
+	// With a threshold of 2, a single share is the only information
+	shares, err := Split(secret, 3, 2)
+	require.NoError(t, err)
+	for _, s := range shares {
+		requireAllValues(s.Y)
+	}
+
+	shares, err = Split(secret, 5, 3)
+	require.NoError(t, err)
+	for i := range shares {
+		for j := i + 1; j < len(shares); j++ {
+			s, err := Combine([]Share{shares[i], shares[j]})
+			require.NoError(t, err)
+			requireAllValues(s)
+		}
+	}
+}
+
+func TestSplitInvalid(t *testing.T) {
+	_, err := Split(nil, 3, 2)
+	require.Equal(t, ErrEmptySecret, err)
+
+	_, err = Split([]byte("secret"), 1, 1)
+	require.Equal(t, ErrInvalidShareCount, err)
+
+	_, err = Split([]byte("secret"), 256, 2)
+	require.Equal(t, ErrInvalidShareCount, err)
+
+	_, err = Split([]byte("secret"), 3, 1)
+	require.Equal(t, ErrInvalidThreshold, err)
+
+	_, err = Split([]byte("secret"), 3, 4)
+	require.Equal(t, ErrInvalidThreshold, err)
+}
+
+func TestCombineInvalid(t *testing.T) {
+	shares, err := Split([]byte("secret"), 3, 2)
+	require.NoError(t, err)
+
+	_, err = Combine(shares[:1])
+	require.Equal(t, ErrNotEnoughShares, err)
+
+	_, err = Combine([]Share{shares[0], shares[0]})
+	require.Equal(t, ErrDuplicateShare, err)
+
+	_, err = Combine([]Share{shares[0], {X: 0, Y: shares[1].Y}})
+	require.Equal(t, ErrInvalidShareX, err)
+
+	_, err = Combine([]Share{shares[0], {X: 2, Y: shares[1].Y[:5]}})
+	require.Equal(t, ErrShareLengthMismatch, err)
+}
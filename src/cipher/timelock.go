@@ -0,0 +1,82 @@
+package cipher
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// TimeLockedAddressVersion is the address version of time-locked addresses
+	TimeLockedAddressVersion byte = 0x02
+
+	// TimeLockHeight locks an output until a block height
+	TimeLockHeight byte = 0x01
+	// TimeLockTime locks an output until a unix timestamp
+	TimeLockTime byte = 0x02
+
+	// TimeLockScriptSize is the size of a time-lock script
+	TimeLockScriptSize = 1 + 8 + 1 + 20
+)
+
+var (
+	// ErrTimeLockInvalidKind Unknown time-lock kind
+	ErrTimeLockInvalidKind = errors.New("Invalid time-lock kind")
+	// ErrTimeLockInvalidOwner Time-lock owner is not a standard or multisig address
+	ErrTimeLockInvalidOwner = errors.New("Time-lock owner must be a standard or multisig address")
+)
+
+/*
+Time-locked addresses are the Ripemd160 of the double SHA256 of the time-lock script,
+with version byte TimeLockedAddressVersion.
+
+The time-lock script is
+- 1 byte, the lock kind, TimeLockHeight or TimeLockTime
+- 8 bytes, the unlock block height or unix time, little endian
+- 1+20 bytes, the version and key of the owner address
+
+The owner is a standard or multisig address; it authorizes the spend once the lock has expired.
+*/
+
+// TimeLockScript returns the serialized time-lock script of a time-locked address
+func TimeLockScript(owner Address, kind byte, value uint64) ([]byte, error) {
+	switch kind {
+	case TimeLockHeight, TimeLockTime:
+	default:
+		return nil, ErrTimeLockInvalidKind
+	}
+
+	switch owner.Version {
+	case 0, MultisigAddressVersion:
+	default:
+		return nil, ErrTimeLockInvalidOwner
+	}
+
+	b := make([]byte, TimeLockScriptSize)
+	b[0] = kind
+	binary.LittleEndian.PutUint64(b[1:9], value)
+	b[9] = owner.Version
+	copy(b[10:], owner.Key[:])
+
+	return b, nil
+}
+
+// TimeLockedAddress creates an Address owned by owner which can't be spent
+// before the block height or unix time value, depending on kind
+func TimeLockedAddress(owner Address, kind byte, value uint64) (Address, error) {
+	script, err := TimeLockScript(owner, kind, value)
+	if err != nil {
+		return Address{}, err
+	}
+
+	r1 := SumSHA256(script)
+	r2 := SumSHA256(r1[:])
+	return Address{
+		Version: TimeLockedAddressVersion,
+		Key:     HashRipemd160(r2[:]),
+	}, nil
+}
+
+// IsTimeLocked returns true if the address is a time-locked address
+func (addr Address) IsTimeLocked() bool {
+	return addr.Version == TimeLockedAddressVersion
+}
@@ -0,0 +1,52 @@
+package cipher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeLockedAddress(t *testing.T) {
+	p, _ := GenerateKeyPair()
+	owner := AddressFromPubKey(p)
+
+	a, err := TimeLockedAddress(owner, TimeLockHeight, 1000)
+	require.NoError(t, err)
+	require.Equal(t, TimeLockedAddressVersion, a.Version)
+	require.True(t, a.IsTimeLocked())
+	require.False(t, owner.IsTimeLocked())
+
+	// Time-locked addresses never verify against a single public key
+	require.Equal(t, ErrAddressInvalidVersion, a.Verify(p))
+
+	// The address roundtrips through its base58 encoding
+	a2, err := DecodeBase58Address(a.String())
+	require.NoError(t, err)
+	require.Equal(t, a, a2)
+
+	// The address depends on the lock kind and value
+	b, err := TimeLockedAddress(owner, TimeLockTime, 1000)
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+	b, err = TimeLockedAddress(owner, TimeLockHeight, 1001)
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+
+	// A multisig address can own a time-locked address
+	p2, _ := GenerateKeyPair()
+	ms, err := MultisigAddressFromPubKeys(1, []PubKey{p, p2})
+	require.NoError(t, err)
+	_, err = TimeLockedAddress(ms, TimeLockTime, 1000)
+	require.NoError(t, err)
+
+	// Time-locked addresses can't be nested
+	_, err = TimeLockedAddress(a, TimeLockHeight, 1000)
+	require.Equal(t, ErrTimeLockInvalidOwner, err)
+
+	_, err = TimeLockedAddress(owner, 3, 1000)
+	require.Equal(t, ErrTimeLockInvalidKind, err)
+
+	script, err := TimeLockScript(owner, TimeLockHeight, 1000)
+	require.NoError(t, err)
+	require.Len(t, script, TimeLockScriptSize)
+}
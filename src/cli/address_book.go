@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	gcli "github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// AddressBookResult the address book json format
+type AddressBookResult struct {
+	AddressBook []wallet.ReadableAddressBookEntry `json:"address_book"`
+}
+
+func walletAddressLabelCmd() *gcli.Command {
+	walletAddressLabelCmd := &gcli.Command{
+		Use:   "walletAddressLabel [address] [label]",
+		Short: "Set the label of an address of a wallet",
+		Long: fmt.Sprintf(`Set the label of an address of a wallet. An empty label removes the label.
+    The labels are shown by listAddresses.
+    The default wallet (%s) will be used if no wallet was specified.`, cliConfig.FullWalletPath()),
+		Args:         gcli.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			wlt, err := SetAddressLabelInFile(w, args[0], args[1])
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(newListAddressesResult(wlt))
+		},
+	}
+
+	walletAddressLabelCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+
+	return walletAddressLabelCmd
+}
+
+func walletAddressBookAddCmd() *gcli.Command {
+	walletAddressBookAddCmd := &gcli.Command{
+		Use:   "walletAddressBookAdd [name] [address]",
+		Short: "Add a recipient to the address book of a wallet",
+		Long: fmt.Sprintf(`Add a recipient to the address book of a wallet, or replace the recipient with the same name.
+    The name can be used instead of the address by send and createRawTransaction.
+    The name must not be an address.
+    The default wallet (%s) will be used if no wallet was specified.`, cliConfig.FullWalletPath()),
+		Args:         gcli.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			note, err := c.Flags().GetString("note")
+			if err != nil {
+				return err
+			}
+
+			book, err := AddAddressBookEntryInFile(w, args[0], args[1], note)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(AddressBookResult{
+				AddressBook: newReadableAddressBook(book),
+			})
+		},
+	}
+
+	walletAddressBookAddCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+	walletAddressBookAddCmd.Flags().StringP("note", "n", "", "Note about the recipient")
+
+	return walletAddressBookAddCmd
+}
+
+func walletAddressBookRemoveCmd() *gcli.Command {
+	walletAddressBookRemoveCmd := &gcli.Command{
+		Use:   "walletAddressBookRemove [name]",
+		Short: "Remove a recipient from the address book of a wallet",
+		Long: fmt.Sprintf(`Remove a recipient from the address book of a wallet.
+    The default wallet (%s) will be used if no wallet was specified.`, cliConfig.FullWalletPath()),
+		Args:         gcli.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			book, err := RemoveAddressBookEntryInFile(w, args[0])
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(AddressBookResult{
+				AddressBook: newReadableAddressBook(book),
+			})
+		},
+	}
+
+	walletAddressBookRemoveCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+
+	return walletAddressBookRemoveCmd
+}
+
+func walletAddressBookCmd() *gcli.Command {
+	return &gcli.Command{
+		Use:   "walletAddressBook [wallet file]",
+		Short: "List the address book of a wallet",
+		Long: fmt.Sprintf(`List the address book of a wallet, the default wallet (%s) will be
+    used if no wallet was specified, use ENV 'WALLET_NAME'
+    to update default wallet file name, and 'WALLET_DIR' to update
+    the default wallet directory`, cliConfig.FullWalletPath()),
+		Args:                  gcli.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(_ *gcli.Command, args []string) error {
+			var wltPath string
+			if len(args) == 1 {
+				wltPath = args[0]
+			}
+
+			w, err := resolveWalletPath(cliConfig, wltPath)
+			if err != nil {
+				return err
+			}
+
+			wlt, err := wallet.Load(w)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(AddressBookResult{
+				AddressBook: newReadableAddressBook(wlt.GetAddressBook()),
+			})
+		},
+	}
+}
+
+// newReadableAddressBook creates []wallet.ReadableAddressBookEntry that is not nil, to be printed as an empty array
+func newReadableAddressBook(entries []wallet.AddressBookEntry) []wallet.ReadableAddressBookEntry {
+	book := wallet.NewReadableAddressBook(entries)
+	if book == nil {
+		book = []wallet.ReadableAddressBookEntry{}
+	}
+	return book
+}
+
+// resolveAddressBookNames replaces the recipients that are names in the address book of the wallet with their addresses.
+// Recipients that are addresses, or are not in the address book, are left unchanged.
+func resolveAddressBookNames(walletFile string, toAddrs []SendAmount) ([]SendAmount, error) {
+	var wlt *wallet.Wallet
+	resolved := make([]SendAmount, len(toAddrs))
+	for i, to := range toAddrs {
+		resolved[i] = to
+
+		if _, err := cipher.DecodeBase58Address(to.Addr); err == nil {
+			continue
+		}
+
+		if wlt == nil {
+			var err error
+			wlt, err = wallet.Load(walletFile)
+			if err != nil {
+				return nil, WalletLoadError{err}
+			}
+		}
+
+		if e, ok := wlt.LookupAddressBook(to.Addr); ok {
+			resolved[i].Addr = e.Address.String()
+		}
+	}
+
+	return resolved, nil
+}
+
+// PUBLIC
+
+// SetAddressLabelInFile sets the label of an address in a wallet file. An empty label removes the label.
+func SetAddressLabelInFile(walletFile, addr, label string) (*wallet.Wallet, error) {
+	return updateWalletInFile(walletFile, func(w *wallet.Wallet) error {
+		a, err := w.DecodeAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		return w.SetAddressLabel(a, label)
+	})
+}
+
+// AddAddressBookEntryInFile adds a recipient to the address book of a wallet file,
+// or replaces the recipient with the same name. Returns the address book.
+func AddAddressBookEntryInFile(walletFile, name, addr, note string) ([]wallet.AddressBookEntry, error) {
+	wlt, err := updateWalletInFile(walletFile, func(w *wallet.Wallet) error {
+		a, err := w.DecodeAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		return w.SetAddressBookEntry(wallet.AddressBookEntry{
+			Name:    name,
+			Address: a,
+			Note:    note,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wlt.GetAddressBook(), nil
+}
+
+// RemoveAddressBookEntryInFile removes a recipient from the address book of a wallet file. Returns the address book.
+func RemoveAddressBookEntryInFile(walletFile, name string) ([]wallet.AddressBookEntry, error) {
+	wlt, err := updateWalletInFile(walletFile, func(w *wallet.Wallet) error {
+		return w.RemoveAddressBookEntry(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wlt.GetAddressBook(), nil
+}
+
+func updateWalletInFile(walletFile string, f func(*wallet.Wallet) error) (*wallet.Wallet, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return nil, WalletLoadError{err}
+	}
+
+	if err := f(wlt); err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(walletFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlt.Save(dir); err != nil {
+		return nil, WalletSaveError{err}
+	}
+
+	return wlt, nil
+}
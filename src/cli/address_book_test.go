@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestAddressBookInFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := wallet.NewWallet("t.wlt", wallet.Options{
+		Seed:      "seed",
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	walletFile := filepath.Join(dir, "t.wlt")
+
+	addrs := w.GetAddresses()
+	alice := testutil.MakeAddress()
+
+	_, err = SetAddressLabelInFile(filepath.Join(dir, "missing.wlt"), addrs[0].String(), "savings")
+	require.IsType(t, WalletLoadError{}, err)
+
+	_, err = SetAddressLabelInFile(walletFile, "foo", "savings")
+	require.Error(t, err)
+
+	_, err = SetAddressLabelInFile(walletFile, alice.String(), "savings")
+	require.Equal(t, wallet.ErrUnknownAddress, err)
+
+	w, err = SetAddressLabelInFile(walletFile, addrs[1].String(), "savings")
+	require.NoError(t, err)
+	require.Equal(t, ListAddressesResult{
+		Addresses: []string{addrs[0].String(), addrs[1].String()},
+		Labels:    map[string]string{addrs[1].String(): "savings"},
+	}, newListAddressesResult(w))
+
+	_, err = AddAddressBookEntryInFile(walletFile, alice.String(), alice.String(), "")
+	require.Equal(t, wallet.ErrInvalidAddressBookName, err)
+
+	book, err := AddAddressBookEntryInFile(walletFile, "alice", alice.String(), "rent")
+	require.NoError(t, err)
+	require.Len(t, book, 1)
+
+	// The labels and the address book are persisted
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Equal(t, ListAddressesResult{
+		Addresses: []string{addrs[0].String(), addrs[1].String()},
+		Labels:    map[string]string{addrs[1].String(): "savings"},
+		AddressBook: []wallet.ReadableAddressBookEntry{
+			{Name: "alice", Address: alice.String(), Note: "rent"},
+		},
+	}, newListAddressesResult(w))
+
+	// Recipients are resolved by name, addresses and unknown names are left unchanged
+	toAddrs, err := resolveAddressBookNames(walletFile, []SendAmount{
+		{Addr: "alice", Coins: 1},
+		{Addr: addrs[0].String(), Coins: 2},
+		{Addr: "bob", Coins: 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []SendAmount{
+		{Addr: alice.String(), Coins: 1},
+		{Addr: addrs[0].String(), Coins: 2},
+		{Addr: "bob", Coins: 3},
+	}, toAddrs)
+	require.Equal(t, ErrAddress, validateSendAmounts(toAddrs))
+
+	_, err = resolveAddressBookNames(filepath.Join(dir, "missing.wlt"), []SendAmount{{Addr: "alice", Coins: 1}})
+	require.IsType(t, WalletLoadError{}, err)
+
+	_, err = RemoveAddressBookEntryInFile(walletFile, "bob")
+	require.Equal(t, wallet.ErrAddressBookEntryNotExist, err)
+
+	book, err = RemoveAddressBookEntryInFile(walletFile, "alice")
+	require.NoError(t, err)
+	require.Empty(t, book)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Empty(t, w.GetAddressBook())
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	gcli "github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// WalletsBackupResult is the result of exportWallets and importWallets
+type WalletsBackupResult struct {
+	BackupFile string   `json:"backup_file"`
+	Wallets    []string `json:"wallets"`
+}
+
+func exportWalletsCmd() *gcli.Command {
+	exportWalletsCmd := &gcli.Command{
+		Short: "Export wallets into an encrypted backup file",
+		Use:   "exportWallets [wallet files...]",
+		Long: fmt.Sprintf(`Export wallets, with their labels, address book and generated addresses,
+    into a single backup file that is authenticated and encrypted with a password.
+    Encrypted wallets stay encrypted with their own password in the backup.
+    All the wallets of the wallet directory (%s) are exported if no wallet was specified.
+    The backup file must not exist.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your backup password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, cliConfig.WalletDir),
+		Args:         gcli.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			backupFile := c.Flag("output").Value.String()
+			if backupFile == "" {
+				printHelp(c)
+				return errors.New("output is required")
+			}
+
+			walletFiles := make([]string, len(args))
+			for i, a := range args {
+				w, err := resolveWalletPath(cliConfig, a)
+				if err != nil {
+					return err
+				}
+				walletFiles[i] = w
+			}
+
+			if len(walletFiles) == 0 {
+				var err error
+				walletFiles, err = filepath.Glob(filepath.Join(cliConfig.WalletDir, "*"+walletExt))
+				if err != nil {
+					return err
+				}
+				sort.Strings(walletFiles)
+			}
+
+			pr := newNewPasswordReader([]byte(c.Flag("password").Value.String()))
+
+			wlts, err := ExportWalletsToFile(walletFiles, backupFile, pr)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(newWalletsBackupResult(backupFile, wlts))
+		},
+	}
+
+	exportWalletsCmd.Flags().StringP("output", "o", "", "backup file to create")
+	exportWalletsCmd.Flags().StringP("password", "p", "", "backup password")
+
+	return exportWalletsCmd
+}
+
+func importWalletsCmd() *gcli.Command {
+	importWalletsCmd := &gcli.Command{
+		Short: "Import the wallets of an encrypted backup file",
+		Use:   "importWallets [backup file]",
+		Long: fmt.Sprintf(`Import the wallets of a backup file created by exportWallets into a wallet directory.
+    The backup is checked with its password before any wallet is imported.
+    Wallets whose filenames are used in the wallet directory are renamed.
+    No wallet is imported if the backup contains a wallet that is already in the wallet directory.
+    The default wallet directory (%s) will be used if no wallet directory was specified.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your backup password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, cliConfig.WalletDir),
+		Args:         gcli.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			dir, err := filepath.Abs(c.Flag("wallet-dir").Value.String())
+			if err != nil {
+				return err
+			}
+
+			pr := NewPasswordReader([]byte(c.Flag("password").Value.String()))
+
+			wlts, err := ImportWalletsFromFile(args[0], dir, pr)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(newWalletsBackupResult(args[0], wlts))
+		},
+	}
+
+	importWalletsCmd.Flags().StringP("wallet-dir", "d", cliConfig.WalletDir, "wallet directory to import the wallets into")
+	importWalletsCmd.Flags().StringP("password", "p", "", "backup password")
+
+	return importWalletsCmd
+}
+
+func newWalletsBackupResult(backupFile string, wlts []*wallet.Wallet) WalletsBackupResult {
+	r := WalletsBackupResult{
+		BackupFile: backupFile,
+		Wallets:    make([]string, len(wlts)),
+	}
+	for i, w := range wlts {
+		r.Wallets[i] = w.Filename()
+	}
+	return r
+}
+
+// PUBLIC
+
+// ExportWalletsToFile packages wallet files into a new backup file encrypted with a password.
+// Returns the exported wallets.
+func ExportWalletsToFile(walletFiles []string, backupFile string, pr PasswordReader) ([]*wallet.Wallet, error) {
+	if len(walletFiles) == 0 {
+		return nil, wallet.ErrMissingBackupWallets
+	}
+
+	// Checks the backup file before the slow encryption, it's created exclusively later
+	if _, err := os.Stat(backupFile); err == nil {
+		return nil, &os.PathError{Op: "open", Path: backupFile, Err: os.ErrExist}
+	}
+
+	wlts := make([]*wallet.Wallet, len(walletFiles))
+	for i, fn := range walletFiles {
+		w, err := wallet.Load(fn)
+		if err != nil {
+			return nil, WalletLoadError{err}
+		}
+		wlts[i] = w
+	}
+
+	if pr == nil {
+		return nil, wallet.ErrMissingPassword
+	}
+
+	password, err := pr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := wallet.EncryptBackup(wlts, password)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(backupFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Write(backup); err != nil {
+		f.Close() // nolint: errcheck
+		return nil, err
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	return wlts, nil
+}
+
+// ImportWalletsFromFile imports the wallets of a backup file created by ExportWalletsToFile into the wallet directory dir.
+// Returns the imported wallets.
+func ImportWalletsFromFile(backupFile, dir string, pr PasswordReader) ([]*wallet.Wallet, error) {
+	backup, err := ioutil.ReadFile(backupFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr == nil {
+		return nil, wallet.ErrMissingPassword
+	}
+
+	password, err := pr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet.ImportBackupToDir(dir, backup, password)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestExportImportWalletsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w1, err := wallet.NewWallet("t1.wlt", wallet.Options{
+		Seed:      "seed1",
+		Label:     "savings",
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w1.SetAddressLabel(w1.Entries[1].Address, "rent"))
+	require.NoError(t, w1.Save(dir))
+
+	w2, err := wallet.NewWallet("t2.wlt", wallet.Options{
+		Seed:       "seed2",
+		GenerateN:  2,
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: wallet.CryptoTypeSha256Xor,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w2.Save(dir))
+
+	walletFiles := []string{filepath.Join(dir, "t1.wlt"), filepath.Join(dir, "t2.wlt")}
+	backupFile := filepath.Join(dir, "wallets.backup")
+
+	_, err = ExportWalletsToFile([]string{filepath.Join(dir, "missing.wlt")}, backupFile, PasswordFromBytes("backup pwd"))
+	require.IsType(t, WalletLoadError{}, err)
+
+	_, err = ExportWalletsToFile(walletFiles, backupFile, PasswordFromBytes(""))
+	require.Equal(t, wallet.ErrMissingPassword, err)
+
+	wlts, err := ExportWalletsToFile(walletFiles, backupFile, PasswordFromBytes("backup pwd"))
+	require.NoError(t, err)
+	require.Equal(t, WalletsBackupResult{
+		BackupFile: backupFile,
+		Wallets:    []string{"t1.wlt", "t2.wlt"},
+	}, newWalletsBackupResult(backupFile, wlts))
+
+	// The backup file is not overwritten
+	_, err = ExportWalletsToFile(walletFiles, backupFile, PasswordFromBytes("backup pwd"))
+	require.True(t, os.IsExist(err))
+
+	// The wallets can't be imported where they exist
+	_, err = ImportWalletsFromFile(backupFile, dir, PasswordFromBytes("backup pwd"))
+	require.Error(t, err)
+	require.IsType(t, wallet.Error{}, err)
+
+	dir2, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir2)
+
+	_, err = ImportWalletsFromFile(backupFile, dir2, PasswordFromBytes("wrong pwd"))
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	wlts, err = ImportWalletsFromFile(backupFile, dir2, PasswordFromBytes("backup pwd"))
+	require.NoError(t, err)
+	require.Len(t, wlts, 2)
+
+	// The wallets are restored with their labels and generated addresses
+	for _, w := range []*wallet.Wallet{w1, w2} {
+		w2, err := wallet.Load(filepath.Join(dir2, w.Filename()))
+		require.NoError(t, err)
+		require.Equal(t, wallet.NewReadableWallet(w), wallet.NewReadableWallet(w2))
+	}
+}
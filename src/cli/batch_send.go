@@ -0,0 +1,592 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+const (
+	// batchStatusPending is the report status of a payout whose transaction was created but not yet broadcast
+	batchStatusPending = "pending"
+	// batchStatusSent is the report status of a payout whose transaction was broadcast
+	batchStatusSent = "sent"
+)
+
+// batchReportHeader is the header row of a batch payout report
+var batchReportHeader = []string{"row", "address", "coins", "hours", "txid", "status"}
+
+// Payout is a row of a batch payouts file
+type Payout struct {
+	Addr  string
+	Coins uint64
+	Hours uint64
+}
+
+type payoutJSON struct {
+	Addr  string `json:"addr"`
+	Coins string `json:"coins"`
+	Hours string `json:"hours,omitempty"`
+}
+
+// batchReportRow is a row of a batch payout report
+type batchReportRow struct {
+	Payout
+	TxID   string
+	Status string
+}
+
+func batchSendCmd() *cobra.Command {
+	batchSendCmd := &cobra.Command{
+		Short: "Send skycoin from a wallet to the recipients of a payouts file",
+		Use:   "batchSend [flags] [payouts file]",
+		Long: `Sends coins to every recipient of a CSV or JSON payouts file.
+    Every row of the file is validated before any transaction is created.
+    The recipients are split into as few transactions as fit within the transaction size limit.
+    Each transaction spends different outputs of the wallet, so the wallet needs at least
+    one output per transaction.
+
+    A CSV file has rows of "address,coins" or "address,coins,hours".
+    A JSON file (with a .json extension) is an array of objects, for example:
+    [{"addr":"$addr1", "coins": "10.2", "hours": "5"}, {"addr":"$addr2", "coins": "20", "hours": "1"}]
+    If hours are given, they must be given for every row. Otherwise, half of the
+    available coin hours are shared between the recipients.
+
+    The result of every row is written to a CSV report file, with the transaction ID
+    of the row and its status: "pending" once its transaction is created and "sent"
+    once it is broadcast. If the command is interrupted, run it again with the same
+    payouts and report files to resume. Rows already sent are skipped.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			payoutsFile := args[0]
+
+			wltAddr, err := fromWalletOrAddress(c)
+			if err != nil {
+				printHelp(c)
+				return err
+			}
+
+			changeAddress, err := c.Flags().GetString("change-address")
+			if err != nil {
+				return err
+			}
+			chgAddr, err := getChangeAddress(wltAddr, changeAddress)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			password, err := c.Flags().GetString("password")
+			if err != nil {
+				return err
+			}
+
+			coinSelection, err := c.Flags().GetString("coin-selection")
+			if err != nil {
+				return err
+			}
+			if _, err := transaction.GetCoinSelector(coinSelection); err != nil {
+				return fmt.Errorf("invalid coin selection %q", coinSelection)
+			}
+
+			reportFile, err := c.Flags().GetString("report")
+			if err != nil {
+				return err
+			}
+			if reportFile == "" {
+				reportFile = payoutsFile + ".report.csv"
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			payouts, manualHours, err := loadPayouts(payoutsFile)
+			if err != nil {
+				return err
+			}
+
+			rows, err := loadBatchReport(reportFile, payouts)
+			if err != nil {
+				return err
+			}
+
+			if err := updatePendingPayouts(apiClient, rows); err != nil {
+				return err
+			}
+
+			txids, err := sendPayouts(apiClient, rows, reportFile, func(payouts []Payout) (*transaction.Batch, error) {
+				return CreateBatchTxnFromWallet(apiClient, wltAddr.Wallet, wltAddr.Address, chgAddr, payouts, manualHours, NewPasswordReader([]byte(password)), coinSelection)
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					Txids  []string `json:"txids"`
+					Report string   `json:"report"`
+				}{
+					Txids:  txids,
+					Report: reportFile,
+				})
+			}
+
+			for _, txid := range txids {
+				fmt.Printf("txid:%s\n", txid)
+			}
+			fmt.Printf("report:%s\n", reportFile)
+
+			return nil
+		},
+	}
+
+	batchSendCmd.Flags().StringP("wallet-file", "f", "", "wallet file or path. If no path is specified your default wallet path will be used.")
+	batchSendCmd.Flags().StringP("address", "a", "", "From address")
+	batchSendCmd.Flags().StringP("change-address", "c", "", `Specify different change address.
+By default the from address or a wallets coinbase address will be used.`)
+	batchSendCmd.Flags().StringP("password", "p", "", "Wallet password")
+	batchSendCmd.Flags().StringP("report", "r", "", "Report file. By default the payouts file name followed by .report.csv")
+	batchSendCmd.Flags().String("coin-selection", transaction.CoinSelectionMinimizeUxOuts, coinSelectionUsage)
+	batchSendCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return batchSendCmd
+}
+
+// loadPayouts loads a CSV or JSON payouts file.
+// Returns true if the coin hours of the payouts are specified
+func loadPayouts(payoutsFile string) ([]Payout, bool, error) {
+	if strings.ToLower(filepath.Ext(payoutsFile)) == ".json" {
+		data, err := ioutil.ReadFile(payoutsFile)
+		if err != nil {
+			return nil, false, err
+		}
+		return parsePayoutsFromJSON(data)
+	}
+
+	fields, err := openCSV(payoutsFile)
+	if err != nil {
+		return nil, false, err
+	}
+	return parsePayoutsFromCSV(fields)
+}
+
+func parsePayoutsFromCSV(fields [][]string) ([]Payout, bool, error) {
+	if len(fields) == 0 {
+		return nil, false, errors.New("no payouts")
+	}
+
+	// The csv reader ensures that every row has the same number of fields
+	var manualHours bool
+	switch len(fields[0]) {
+	case 2:
+	case 3:
+		manualHours = true
+	default:
+		return nil, false, errors.New("payout rows must have 2 or 3 fields: address,coins[,hours]")
+	}
+
+	var payouts []Payout
+	var errs []error
+	for i, f := range fields {
+		var hours string
+		if manualHours {
+			hours = f[2]
+		}
+
+		p, err := parsePayout(f[0], f[1], hours, manualHours)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[row %d] %v", i, err))
+			continue
+		}
+
+		payouts = append(payouts, p)
+	}
+
+	if err := joinErrors(errs); err != nil {
+		return nil, false, err
+	}
+
+	return payouts, manualHours, nil
+}
+
+func parsePayoutsFromJSON(data []byte) ([]Payout, bool, error) {
+	var rows []payoutJSON
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, false, fmt.Errorf("invalid payouts file: %v", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, false, errors.New("no payouts")
+	}
+
+	manualHours := rows[0].Hours != ""
+
+	var payouts []Payout
+	var errs []error
+	for i, r := range rows {
+		if (r.Hours != "") != manualHours {
+			errs = append(errs, fmt.Errorf("[row %d] hours must be specified for every row or none", i))
+			continue
+		}
+
+		p, err := parsePayout(r.Addr, r.Coins, r.Hours, manualHours)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[row %d] %v", i, err))
+			continue
+		}
+
+		payouts = append(payouts, p)
+	}
+
+	if err := joinErrors(errs); err != nil {
+		return nil, false, err
+	}
+
+	return payouts, manualHours, nil
+}
+
+func parsePayout(addr, coins, hours string, manualHours bool) (Payout, error) {
+	addr = strings.TrimSpace(addr)
+	if _, err := cipher.DecodeBase58Address(addr); err != nil {
+		return Payout{}, fmt.Errorf("Invalid address %s: %v", addr, err)
+	}
+
+	coins = strings.TrimSpace(coins)
+	amt, err := droplet.FromString(coins)
+	if err != nil {
+		return Payout{}, fmt.Errorf("Invalid amount %s: %v", coins, err)
+	}
+
+	if amt == 0 {
+		return Payout{}, errors.New("Cannot send 0 coins")
+	}
+
+	if err := params.DropletPrecisionCheck(params.UserVerifyTxn.MaxDropletPrecision, amt); err != nil {
+		return Payout{}, fmt.Errorf("Invalid amount %s: %v", coins, err)
+	}
+
+	var h uint64
+	if manualHours {
+		hours = strings.TrimSpace(hours)
+		h, err = strconv.ParseUint(hours, 10, 64)
+		if err != nil {
+			return Payout{}, fmt.Errorf("Invalid hours %s: %v", hours, err)
+		}
+	}
+
+	return Payout{
+		Addr:  addr,
+		Coins: amt,
+		Hours: h,
+	}, nil
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	errMsgs := make([]string, len(errs))
+	for i, err := range errs {
+		errMsgs[i] = err.Error()
+	}
+
+	return errors.New(strings.Join(errMsgs, "\n"))
+}
+
+// loadBatchReport loads the report of a previous run of payouts.
+// If the report file does not exist, returns a report of payouts that were not sent
+func loadBatchReport(reportFile string, payouts []Payout) ([]batchReportRow, error) {
+	rows := make([]batchReportRow, len(payouts))
+	for i, p := range payouts {
+		rows[i].Payout = p
+	}
+
+	fields, err := openCSV(reportFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rows, nil
+		}
+		return nil, err
+	}
+
+	if len(fields) != len(payouts)+1 {
+		return nil, fmt.Errorf("report file %s does not match the payouts file", reportFile)
+	}
+
+	for i, f := range fields[1:] {
+		if len(f) != len(batchReportHeader) {
+			return nil, fmt.Errorf("report file %s is invalid at row %d", reportFile, i)
+		}
+
+		coins, err := droplet.FromString(f[2])
+		if err != nil {
+			return nil, fmt.Errorf("report file %s is invalid at row %d: %v", reportFile, i, err)
+		}
+
+		hours, err := strconv.ParseUint(f[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("report file %s is invalid at row %d: %v", reportFile, i, err)
+		}
+
+		if f[1] != payouts[i].Addr || coins != payouts[i].Coins || hours != payouts[i].Hours {
+			return nil, fmt.Errorf("report file %s does not match the payouts file at row %d", reportFile, i)
+		}
+
+		switch f[5] {
+		case "", batchStatusPending, batchStatusSent:
+		default:
+			return nil, fmt.Errorf("report file %s has an invalid status at row %d", reportFile, i)
+		}
+
+		rows[i].TxID = f[4]
+		rows[i].Status = f[5]
+	}
+
+	return rows, nil
+}
+
+// saveBatchReport writes the report of payouts, replacing the report file atomically
+func saveBatchReport(reportFile string, rows []batchReportRow) error {
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, batchReportHeader)
+	for i, r := range rows {
+		coins, err := droplet.ToString(r.Coins)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, []string{
+			strconv.Itoa(i),
+			r.Addr,
+			coins,
+			strconv.FormatUint(r.Hours, 10),
+			r.TxID,
+			r.Status,
+		})
+	}
+
+	tmpFile := reportFile + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, reportFile)
+}
+
+// updatePendingPayouts checks whether the transactions of pending payouts were broadcast by a previous run.
+// Payouts whose transaction is known to the node are marked as sent, the others are reset to be sent again
+func updatePendingPayouts(c *api.Client, rows []batchReportRow) error {
+	known := make(map[string]bool)
+	for i := range rows {
+		if rows[i].Status != batchStatusPending {
+			continue
+		}
+
+		txid := rows[i].TxID
+		ok, checked := known[txid]
+		if !checked {
+			_, err := c.Transaction(txid)
+			switch e := err.(type) {
+			case nil:
+				ok = true
+			case api.ClientError:
+				if e.StatusCode != http.StatusNotFound {
+					return err
+				}
+			default:
+				return err
+			}
+			known[txid] = ok
+		}
+
+		if ok {
+			rows[i].Status = batchStatusSent
+		} else {
+			rows[i].TxID = ""
+			rows[i].Status = ""
+		}
+	}
+
+	return nil
+}
+
+// sendPayouts creates the transactions of the payouts that were not sent with createBatch, and broadcasts them.
+// The report file is updated before each transaction is broadcast, and after it was broadcast.
+// Returns the IDs of the broadcast transactions
+func sendPayouts(c *api.Client, rows []batchReportRow, reportFile string, createBatch func([]Payout) (*transaction.Batch, error)) ([]string, error) {
+	var unsent []int
+	var payouts []Payout
+	for i, r := range rows {
+		if r.Status != batchStatusSent {
+			unsent = append(unsent, i)
+			payouts = append(payouts, r.Payout)
+		}
+	}
+
+	if len(unsent) == 0 {
+		return nil, saveBatchReport(reportFile, rows)
+	}
+
+	b, err := createBatch(payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b.Receivers) != len(unsent) {
+		return nil, errors.New("batch receivers do not match the payouts")
+	}
+
+	txids := make([]string, len(b.Transactions))
+	for i, txn := range b.Transactions {
+		txids[i] = txn.Hash().Hex()
+	}
+
+	for i, txnIdx := range b.Receivers {
+		rows[unsent[i]].TxID = txids[txnIdx]
+		rows[unsent[i]].Status = batchStatusPending
+	}
+
+	if err := saveBatchReport(reportFile, rows); err != nil {
+		return nil, err
+	}
+
+	for i := range b.Transactions {
+		if _, err := c.InjectTransaction(&b.Transactions[i]); err != nil {
+			return nil, fmt.Errorf("broadcast of transaction %s failed: %v. Run the command again to resume the payouts", txids[i], err)
+		}
+
+		for j := range rows {
+			if rows[j].TxID == txids[i] {
+				rows[j].Status = batchStatusSent
+			}
+		}
+
+		if err := saveBatchReport(reportFile, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return txids, nil
+}
+
+// CreateBatchTxnFromWallet creates signed transactions paying payouts from the addresses of a wallet, or from addr if not empty.
+// If manualHours is false, half of the coin hours available to each transaction are shared between its payouts.
+// The payouts are split into as few transactions as fit within the transaction size limit, see transaction.CreateBatch
+func CreateBatchTxnFromWallet(c GetOutputser, walletFile, addr, chgAddr string, payouts []Payout, manualHours bool, pr PasswordReader, coinSelection string) (*transaction.Batch, error) {
+	cAddr, err := cipher.DecodeBase58Address(chgAddr)
+	if err != nil {
+		return nil, ErrAddress
+	}
+
+	wlt, inAddrs, password, err := loadSpendingWallet(walletFile, addr, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := wlt.GetEntry(cAddr); !ok {
+		return nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
+	}
+
+	to := make([]coin.TransactionOutput, len(payouts))
+	for i, p := range payouts {
+		a, err := cipher.DecodeBase58Address(p.Addr)
+		if err != nil {
+			return nil, ErrAddress
+		}
+
+		to[i] = coin.TransactionOutput{
+			Address: a,
+			Coins:   p.Coins,
+			Hours:   p.Hours,
+		}
+	}
+
+	p := transaction.Params{
+		ChangeAddress: &cAddr,
+		To:            to,
+		CoinSelection: coinSelection,
+	}
+
+	if manualHours {
+		p.HoursSelection = transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		}
+	} else {
+		shareFactor := decimal.New(5, -1)
+		p.HoursSelection = transaction.HoursSelection{
+			Type:        transaction.HoursSelectionTypeAuto,
+			Mode:        transaction.HoursSelectionModeShare,
+			ShareFactor: &shareFactor,
+		}
+	}
+
+	inUxs, head, err := getSpendableUxOuts(c, wlt, inAddrs)
+	if err != nil {
+		return nil, err
+	}
+	auxs := coin.NewAddressUxOuts(inUxs)
+
+	var b *transaction.Batch
+	if wlt.IsEncrypted() {
+		if err := wlt.GuardView(password, func(w *wallet.Wallet) error {
+			var err error
+			b, err = w.CreateBatchTransactionSigned(p, auxs, head.Time)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		b, err = wlt.CreateBatchTransactionSigned(p, auxs, head.Time)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := verifyCreatedTxns(b.Transactions, inUxs, *head); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
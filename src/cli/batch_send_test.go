@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayoutsFromCSV(t *testing.T) {
+	cases := []struct {
+		name        string
+		fields      [][]string
+		payouts     []Payout
+		manualHours bool
+		err         error
+	}{
+		{
+			name: "coins only",
+			fields: [][]string{
+				{"2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "123"},
+				{" 2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "123.456"},
+			},
+			payouts: []Payout{
+				{
+					Addr:  "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP",
+					Coins: 123e6,
+				},
+				{
+					Addr:  "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd",
+					Coins: 123456e3,
+				},
+			},
+		},
+
+		{
+			name: "coins and hours",
+			fields: [][]string{
+				{"2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "1", "10"},
+				{"2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "2", "0"},
+			},
+			payouts: []Payout{
+				{
+					Addr:  "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP",
+					Coins: 1e6,
+					Hours: 10,
+				},
+				{
+					Addr:  "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd",
+					Coins: 2e6,
+				},
+			},
+			manualHours: true,
+		},
+
+		{
+			name: "every invalid row is reported",
+			fields: [][]string{
+				{"2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "1", "10"},
+				{"foo", "1", "10"},
+				{"2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "0", "10"},
+				{"2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "1.2345", "10"},
+				{"2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "1", "-1"},
+			},
+			err: errors.New(`[row 1] Invalid address foo: Invalid address length
+[row 2] Cannot send 0 coins
+[row 3] Invalid amount 1.2345: invalid amount, too many decimal places
+[row 4] Invalid hours -1: strconv.ParseUint: parsing "-1": invalid syntax`),
+		},
+
+		{
+			name:   "no rows",
+			fields: [][]string{},
+			err:    errors.New("no payouts"),
+		},
+
+		{
+			name: "too many fields",
+			fields: [][]string{
+				{"2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "1", "10", "1"},
+			},
+			err: errors.New("payout rows must have 2 or 3 fields: address,coins[,hours]"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payouts, manualHours, err := parsePayoutsFromCSV(tc.fields)
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.payouts, payouts)
+			require.Equal(t, tc.manualHours, manualHours)
+		})
+	}
+}
+
+func TestParsePayoutsFromJSON(t *testing.T) {
+	payouts, manualHours, err := parsePayoutsFromJSON([]byte(`[
+		{"addr": "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "coins": "1.5", "hours": "2"},
+		{"addr": "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "coins": "3", "hours": "0"}
+	]`))
+	require.NoError(t, err)
+	require.True(t, manualHours)
+	require.Equal(t, []Payout{
+		{
+			Addr:  "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP",
+			Coins: 15e5,
+			Hours: 2,
+		},
+		{
+			Addr:  "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd",
+			Coins: 3e6,
+		},
+	}, payouts)
+
+	_, _, err = parsePayoutsFromJSON([]byte(`[
+		{"addr": "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP", "coins": "1.5", "hours": "2"},
+		{"addr": "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd", "coins": "3"}
+	]`))
+	require.Equal(t, errors.New("[row 1] hours must be specified for every row or none"), err)
+
+	_, _, err = parsePayoutsFromJSON([]byte(`[]`))
+	require.Equal(t, errors.New("no payouts"), err)
+}
+
+func TestBatchReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch-report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	reportFile := filepath.Join(dir, "payouts.report.csv")
+
+	payouts := []Payout{
+		{
+			Addr:  "2Niqzo12tZ9ioZq5vwPHMVR4g7UVpp9TCmP",
+			Coins: 15e5,
+			Hours: 2,
+		},
+		{
+			Addr:  "2UDzBKnxZf4d9pdrBJAqbtoeH641RFLYKxd",
+			Coins: 3e6,
+		},
+	}
+
+	// A missing report has no payouts sent
+	rows, err := loadBatchReport(reportFile, payouts)
+	require.NoError(t, err)
+	require.Equal(t, []batchReportRow{
+		{Payout: payouts[0]},
+		{Payout: payouts[1]},
+	}, rows)
+
+	rows[0].TxID = "5d7dd9e1e4b2c7b1bfd9b6b4bbb9e1b2b7a1d5c1c0c9a5e7e3d7c6b9a9f1e2d3"
+	rows[0].Status = batchStatusSent
+	rows[1].TxID = "1d7dd9e1e4b2c7b1bfd9b6b4bbb9e1b2b7a1d5c1c0c9a5e7e3d7c6b9a9f1e2d3"
+	rows[1].Status = batchStatusPending
+	require.NoError(t, saveBatchReport(reportFile, rows))
+
+	_, err = os.Stat(reportFile + ".tmp")
+	require.True(t, os.IsNotExist(err))
+
+	loadedRows, err := loadBatchReport(reportFile, payouts)
+	require.NoError(t, err)
+	require.Equal(t, rows, loadedRows)
+
+	// A report of other payouts is rejected
+	payouts[1].Coins = 4e6
+	_, err = loadBatchReport(reportFile, payouts)
+	require.Equal(t, errors.New("report file "+reportFile+" does not match the payouts file at row 1"), err)
+
+	_, err = loadBatchReport(reportFile, payouts[:1])
+	require.Equal(t, errors.New("report file "+reportFile+" does not match the payouts file"), err)
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+type fakeOutputser struct {
+	outputs *readable.UnspentOutputsSummary
+	err     error
+}
+
+func (f fakeOutputser) OutputsForAddresses([]string) (*readable.UnspentOutputsSummary, error) {
+	return f.outputs, f.err
+}
+
+func TestCreateRawTxnFreshChangeAddress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := wallet.NewWallet("t.wlt", wallet.Options{
+		Seed:                "seed",
+		GenerateN:           1,
+		ChangeAddressPolicy: wallet.ChangeAddressPolicyFresh,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	walletFile := filepath.Join(dir, "t.wlt")
+
+	fresh, err := hasFreshChangeAddressPolicy(walletFile)
+	require.NoError(t, err)
+	require.True(t, fresh)
+
+	_, err = hasFreshChangeAddressPolicy(filepath.Join(dir, "missing.wlt"))
+	require.IsType(t, WalletLoadError{}, err)
+
+	summary, err := readable.NewUnspentOutputsSummary(&visor.UnspentOutputsSummary{
+		HeadBlock: &coin.SignedBlock{
+			Block: coin.Block{
+				Head: coin.BlockHeader{
+					BkSeq: 10,
+					Time:  1540000000,
+				},
+			},
+		},
+		Confirmed: []visor.UnspentOutput{
+			{
+				UxOut: coin.UxOut{
+					Head: coin.UxHead{
+						BkSeq: 2,
+						Time:  1530000000,
+					},
+					Body: coin.UxBody{
+						SrcTransaction: testutil.RandSHA256(t),
+						Address:        w.Entries[0].SkycoinAddress(),
+						Coins:          10e6,
+						Hours:          1000,
+					},
+				},
+				CalculatedHours: 1000,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	toAddrs := []SendAmount{
+		{
+			Addr:  testutil.MakeAddress().String(),
+			Coins: 1e6,
+		},
+	}
+
+	// The wallet is not changed if the transaction can't be created
+	_, err = CreateRawTxnFromWallet(fakeOutputser{err: errors.New("failed")}, walletFile, "", toAddrs, nil, "")
+	require.EqualError(t, err, "failed")
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 1)
+
+	// The change is sent to a new change address, which is saved in the wallet file
+	txn, err := CreateRawTxnFromWallet(fakeOutputser{outputs: summary}, walletFile, "", toAddrs, nil, "")
+	require.NoError(t, err)
+	require.Len(t, txn.Out, 2)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 2)
+	require.True(t, w.Entries[1].IsChangeAddress())
+	require.Equal(t, w.Entries[1].SkycoinAddress(), txn.Out[1].Address)
+
+	// An explicit change address is used as is
+	chgAddr := w.Entries[0].Address.String()
+	txn, err = CreateRawTxnFromAddress(fakeOutputser{outputs: summary}, chgAddr, walletFile, chgAddr, toAddrs, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, w.Entries[0].SkycoinAddress(), txn.Out[1].Address)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 2)
+
+	// The change address is not saved if the transaction has no change
+	allAddrs := []SendAmount{
+		{
+			Addr:  testutil.MakeAddress().String(),
+			Coins: 10e6,
+		},
+	}
+	txn, err = CreateRawTxnFromWallet(fakeOutputser{outputs: summary}, walletFile, "", allAddrs, nil, "")
+	require.NoError(t, err)
+	require.Len(t, txn.Out, 1)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 2)
+
+	// A change address is required with the reuse policy
+	require.NoError(t, w.SetChangeAddressPolicy(wallet.ChangeAddressPolicyReuse))
+	require.NoError(t, w.Save(dir))
+	_, err = CreateRawTxnFromWallet(fakeOutputser{outputs: summary}, walletFile, "", toAddrs, nil, "")
+	require.Equal(t, ErrAddress, err)
+
+	_, err = CreateRawTxnFromWallet(fakeOutputser{outputs: summary}, walletFile, testutil.MakeAddress().String(), toAddrs, nil, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not in wallet")
+
+	_, err = CreateRawTxnFromWallet(fakeOutputser{outputs: summary}, walletFile, cipher.Address{}.String()+"x", toAddrs, nil, "")
+	require.Equal(t, ErrAddress, err)
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	gcli "github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func changeWalletPasswordCmd() *gcli.Command {
+	changeWalletPasswordCmd := &gcli.Command{
+		Short: "Change the password of an encrypted wallet",
+		Use:   "changeWalletPassword",
+		Long: fmt.Sprintf(`Change the password of an encrypted wallet, and optionally its crypto type.
+    The secrets are re-encrypted in memory, the wallet is never decrypted on disk.
+    The wallet file is replaced atomically, and the previous file is kept with the ".bak" extension.
+    The default wallet (%s) will be used if no wallet was specified.
+
+    Use caution when using the "-p" and "-n" commands. If you have command history enabled
+    your wallet encryption passwords can be recovered from the history log. If you
+    do not include the "-p" or "-n" options you will be prompted to enter the passwords
+    after you enter your command.`, cliConfig.FullWalletPath()),
+		Args:         gcli.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, _ []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			var cryptoType wallet.CryptoType
+			if ct := c.Flag("crypto-type").Value.String(); ct != "" {
+				cryptoType, err = wallet.CryptoTypeFromString(ct)
+				if err != nil {
+					printHelp(c)
+					return err
+				}
+			}
+
+			pr := NewPasswordReader([]byte(c.Flag("password").Value.String()))
+			newPr := newNewPasswordReader([]byte(c.Flag("new-password").Value.String()))
+
+			wlt, err := ChangeWalletPasswordInFile(w, pr, newPr, cryptoType)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(wallet.NewReadableWallet(wlt))
+		},
+	}
+
+	changeWalletPasswordCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+	changeWalletPasswordCmd.Flags().StringP("password", "p", "", "current wallet password")
+	changeWalletPasswordCmd.Flags().StringP("new-password", "n", "", "new wallet password")
+	changeWalletPasswordCmd.Flags().StringP("crypto-type", "x", "", "The new crypto type of the wallet, can be scrypt-chacha20poly1305, argon2id-xchacha20poly1305 or sha256-xor. The crypto type of the wallet is kept if not specified")
+
+	return changeWalletPasswordCmd
+}
+
+// newPasswordFromTerm reads a new password from terminal, and asks for it twice
+type newPasswordFromTerm struct{}
+
+// Password implements the PasswordReader's Password method
+func (p newPasswordFromTerm) Password() ([]byte, error) {
+	fmt.Fprint(os.Stdout, "enter new password:")
+	bp, err := terminal.ReadPassword(int(syscall.Stdin)) // nolint: unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, "")
+
+	fmt.Fprint(os.Stdout, "confirm new password:")
+	bp2, err := terminal.ReadPassword(int(syscall.Stdin)) // nolint: unconvert
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stdout, "")
+
+	if string(bp) != string(bp2) {
+		return nil, errors.New("new passwords do not match")
+	}
+
+	return bp, nil
+}
+
+// newNewPasswordReader creates a PasswordReader for a new password,
+// reads the password from the input bytes first, if it's empty, then read from terminal.
+func newNewPasswordReader(p []byte) PasswordReader {
+	if len(p) != 0 {
+		return PasswordFromBytes(p)
+	}
+
+	return newPasswordFromTerm{}
+}
+
+// PUBLIC
+
+// ChangeWalletPasswordInFile re-encrypts the secrets of an encrypted wallet file with a new password and,
+// if cryptoType is not empty, a new crypto type. The wallet file is replaced atomically,
+// and the previous file is kept as a backup.
+func ChangeWalletPasswordInFile(walletFile string, pr, newPr PasswordReader, cryptoType wallet.CryptoType) (*wallet.Wallet, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return nil, WalletLoadError{err}
+	}
+
+	if !wlt.IsEncrypted() {
+		return nil, wallet.ErrWalletNotEncrypted
+	}
+
+	if pr == nil || newPr == nil {
+		return nil, wallet.ErrMissingPassword
+	}
+
+	password, err := pr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	newPassword, err := newPr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlt.ChangePassword(password, newPassword, cryptoType); err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(walletFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlt.SaveAtomic(dir); err != nil {
+		return nil, WalletSaveError{err}
+	}
+
+	return wlt, nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestChangeWalletPasswordInFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := wallet.NewWallet("t.wlt", wallet.Options{
+		Seed:       "seed",
+		GenerateN:  1,
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: wallet.CryptoTypeSha256Xor,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	walletFile := filepath.Join(dir, "t.wlt")
+
+	data, err := ioutil.ReadFile(walletFile)
+	require.NoError(t, err)
+
+	_, err = ChangeWalletPasswordInFile(filepath.Join(dir, "missing.wlt"), PasswordFromBytes("pwd"), PasswordFromBytes("new pwd"), "")
+	require.IsType(t, WalletLoadError{}, err)
+
+	_, err = ChangeWalletPasswordInFile(walletFile, PasswordFromBytes("wrong"), PasswordFromBytes("new pwd"), "")
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+	testutil.RequireFileNotExists(t, walletFile+".bak")
+
+	w, err = ChangeWalletPasswordInFile(walletFile, PasswordFromBytes("pwd"), PasswordFromBytes("new pwd"), wallet.CryptoTypeScryptChacha20poly1305Insecure)
+	require.NoError(t, err)
+	require.True(t, w.IsEncrypted())
+	require.Equal(t, string(wallet.CryptoTypeScryptChacha20poly1305Insecure), w.Meta["cryptoType"])
+
+	// The wallet file is encrypted with the new password
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	_, err = w.Unlock([]byte("pwd"))
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+	_, err = w.Unlock([]byte("new pwd"))
+	require.NoError(t, err)
+
+	// The previous wallet file is kept as a backup
+	bak, err := ioutil.ReadFile(walletFile + ".bak")
+	require.NoError(t, err)
+	require.Equal(t, data, bak)
+
+	// Unencrypted wallets have no password to change
+	w, err = wallet.NewWallet("t2.wlt", wallet.Options{
+		Seed:      "seed",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	_, err = ChangeWalletPasswordInFile(filepath.Join(dir, "t2.wlt"), PasswordFromBytes("pwd"), PasswordFromBytes("new pwd"), "")
+	require.Equal(t, wallet.ErrWalletNotEncrypted, err)
+}
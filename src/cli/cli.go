@@ -228,29 +228,49 @@ func NewCLI(cfg Config) (*cobra.Command, error) {
 		fiberAddressGenCmd(),
 		addressOutputsCmd(),
 		blocksCmd(),
+		batchSendCmd(),
 		broadcastTxCmd(),
+		changeWalletPasswordCmd(),
 		checkDBCmd(),
 		checkDBEncodingCmd(),
 		createRawTxnCmd(),
+		createPartialTxnCmd(),
+		combinePartialTxnsCmd(),
+		combineSeedSharesCmd(),
+		consolidateCmd(),
 		decodeRawTxnCmd(),
 		decryptWalletCmd(),
 		encryptWalletCmd(),
+		exportWalletsCmd(),
+		finalizePartialTxnCmd(),
+		importWalletsCmd(),
 		lastBlocksCmd(),
 		listAddressesCmd(),
 		listWalletsCmd(),
+		multisigAddressCmd(),
 		sendCmd(),
 		showConfigCmd(),
 		showSeedCmd(),
+		signPartialTxnCmd(),
+		splitSeedCmd(),
 		statusCmd(),
+		timeLockAddressCmd(),
 		transactionCmd(),
 		verifyAddressCmd(),
 		versionCmd(),
 		walletCreateCmd(),
 		walletAddAddressesCmd(),
+		walletAddressBookCmd(),
+		walletAddressBookAddCmd(),
+		walletAddressBookRemoveCmd(),
+		walletAddressLabelCmd(),
 		walletBalanceCmd(),
 		walletDirCmd(),
+		walletFreezeOutputsCmd(),
+		walletFrozenOutputsCmd(),
 		walletHisCmd(),
 		walletOutputsCmd(),
+		walletUnfreezeOutputsCmd(),
 		richlistCmd(),
 		addressTransactionsCmd(),
 	}
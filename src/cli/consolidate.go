@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/util/droplet"
+	"github.com/skycoin/skycoin/src/visor"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func consolidateCmd() *cobra.Command {
+	consolidateCmd := &cobra.Command{
+		Short: "Merge the unspent outputs of a wallet into one address",
+		Use:   "consolidate [flags] [to address]",
+		Long: fmt.Sprintf(`Creates a series of transactions that merge the unspent outputs of a wallet,
+    or of one of its addresses, into a single output of the [to address].
+    Each transaction fits within the network's transaction size limit.
+    The default wallet (%s) will be used if no wallet and address was specified.
+    If no [to address] is specified, the from address or the wallet's first address is used.
+
+    The transactions are printed along with the total coin hours they burn.
+    They are only broadcast to the network if the "-b" option is used.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, cliConfig.FullWalletPath()),
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			wltAddr, err := fromWalletOrAddress(c)
+			if err != nil {
+				printHelp(c)
+				return err
+			}
+
+			var to string
+			if len(args) == 1 {
+				to = args[0]
+				if _, err := cipher.DecodeBase58Address(to); err != nil {
+					return fmt.Errorf("invalid to address: %s", to)
+				}
+			} else {
+				to, err = getChangeAddress(wltAddr, "")
+				switch err.(type) {
+				case nil:
+				case WalletLoadError:
+					printHelp(c)
+					return err
+				default:
+					return err
+				}
+			}
+
+			maxInputs, err := c.Flags().GetInt("max-inputs")
+			if err != nil {
+				return err
+			}
+
+			password, err := c.Flags().GetString("password")
+			if err != nil {
+				return err
+			}
+
+			broadcast, err := c.Flags().GetBool("broadcast")
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			cons, err := ConsolidateWallet(apiClient, wltAddr.Wallet, wltAddr.Address, to, maxInputs, NewPasswordReader([]byte(password)))
+			if err != nil {
+				return err
+			}
+
+			coins, err := droplet.ToString(cons.Coins)
+			if err != nil {
+				return err
+			}
+
+			rawTxns := make([]string, len(cons.Transactions))
+			for i, txn := range cons.Transactions {
+				rawTxns[i], err = txn.SerializeHex()
+				if err != nil {
+					return err
+				}
+			}
+
+			if !jsonOutput {
+				fmt.Printf("transactions:%d\ncoins:%s\nfee:%d\nskipped:%d\n", len(cons.Transactions), coins, cons.Fee, len(cons.Skipped))
+			}
+
+			var txids []string
+			if broadcast {
+				for i := range cons.Transactions {
+					txid, err := apiClient.InjectTransaction(&cons.Transactions[i])
+					if err != nil {
+						return err
+					}
+					txids = append(txids, txid)
+
+					if !jsonOutput {
+						fmt.Printf("txid:%s\n", txid)
+					}
+				}
+			} else if !jsonOutput {
+				for _, rawTxn := range rawTxns {
+					fmt.Println(rawTxn)
+				}
+			}
+
+			if jsonOutput {
+				return printJSON(struct {
+					RawTxns []string `json:"rawtxs"`
+					Txids   []string `json:"txids,omitempty"`
+					Coins   string   `json:"coins"`
+					Fee     uint64   `json:"fee"`
+					Skipped int      `json:"skipped"`
+				}{
+					RawTxns: rawTxns,
+					Txids:   txids,
+					Coins:   coins,
+					Fee:     cons.Fee,
+					Skipped: len(cons.Skipped),
+				})
+			}
+
+			return nil
+		},
+	}
+
+	consolidateCmd.Flags().StringP("wallet-file", "f", "", "wallet file or path. If no path is specified your default wallet path will be used.")
+	consolidateCmd.Flags().StringP("address", "a", "", "From address")
+	consolidateCmd.Flags().IntP("max-inputs", "n", 0, "Maximum number of inputs of each transaction. If 0, as many inputs as fit in a transaction are used")
+	consolidateCmd.Flags().StringP("password", "p", "", "Wallet password")
+	consolidateCmd.Flags().BoolP("broadcast", "b", false, "Broadcast the transactions to the network")
+	consolidateCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return consolidateCmd
+}
+
+// ConsolidateWallet creates signed transactions that merge the unspent outputs of a wallet into the to address.
+// If addr is not empty, only the outputs of addr are merged
+func ConsolidateWallet(c GetOutputser, walletFile, addr, to string, maxInputs int, pr PasswordReader) (*transaction.Consolidation, error) {
+	toAddr, err := cipher.DecodeBase58Address(to)
+	if err != nil {
+		return nil, ErrAddress
+	}
+
+	wlt, inAddrs, password, err := loadSpendingWallet(walletFile, addr, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	inUxs, head, err := getSpendableUxOuts(c, wlt, inAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	p := transaction.ConsolidateParams{
+		To:        toAddr,
+		MaxInputs: maxInputs,
+	}
+	auxs := coin.NewAddressUxOuts(inUxs)
+
+	var cons *transaction.Consolidation
+	if wlt.IsEncrypted() {
+		if err := wlt.GuardView(password, func(w *wallet.Wallet) error {
+			var err error
+			cons, err = w.CreateConsolidationSigned(p, auxs, head.Time)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		cons, err = wlt.CreateConsolidationSigned(p, auxs, head.Time)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := verifyCreatedTxns(cons.Transactions, inUxs, *head); err != nil {
+		return nil, err
+	}
+
+	return cons, nil
+}
+
+// loadSpendingWallet loads a wallet to spend the unspent outputs of its addresses, or of addr if not empty.
+// Returns the wallet, the addresses to spend from and the wallet's password, if it is encrypted
+func loadSpendingWallet(walletFile, addr string, pr PasswordReader) (*wallet.Wallet, []string, []byte, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var inAddrs []string
+	if addr != "" {
+		srcAddr, err := cipher.DecodeBase58Address(addr)
+		if err != nil {
+			return nil, nil, nil, ErrAddress
+		}
+
+		if _, ok := wlt.GetEntry(srcAddr); !ok {
+			return nil, nil, nil, fmt.Errorf("%v address is not in wallet", addr)
+		}
+
+		inAddrs = []string{addr}
+	} else {
+		for _, a := range wlt.GetAddresses() {
+			inAddrs = append(inAddrs, a.String())
+		}
+	}
+
+	switch pr.(type) {
+	case nil:
+		if wlt.IsEncrypted() {
+			return nil, nil, nil, wallet.ErrWalletEncrypted
+		}
+	case PasswordFromBytes:
+		p, err := pr.Password()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if !wlt.IsEncrypted() && len(p) != 0 {
+			return nil, nil, nil, wallet.ErrWalletNotEncrypted
+		}
+	}
+
+	var password []byte
+	if wlt.IsEncrypted() {
+		password, err = pr.Password()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return wlt, inAddrs, password, nil
+}
+
+// getSpendableUxOuts returns the spendable unspent outputs of addrs, except the frozen outputs of wlt,
+// and the head block header
+func getSpendableUxOuts(c GetOutputser, wlt *wallet.Wallet, addrs []string) (coin.UxArray, *coin.BlockHeader, error) {
+	outputs, err := c.OutputsForAddresses(addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	outputs = excludeFrozenOutputs(outputs, wlt)
+
+	inUxs, err := outputs.SpendableOutputs().ToUxArray()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head, err := outputs.Head.ToCoinBlockHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return inUxs, &head, nil
+}
+
+// verifyCreatedTxns verifies signed transactions spending the unspent outputs of uxs
+func verifyCreatedTxns(txns []coin.Transaction, uxs coin.UxArray, head coin.BlockHeader) error {
+	uxMap := make(map[cipher.SHA256]coin.UxOut, len(uxs))
+	for _, ux := range uxs {
+		uxMap[ux.Hash()] = ux
+	}
+
+	for _, txn := range txns {
+		inUxs := make(coin.UxArray, len(txn.In))
+		for i, h := range txn.In {
+			ux, ok := uxMap[h]
+			if !ok {
+				return errors.New("created transaction spends an unknown output")
+			}
+			inUxs[i] = ux
+		}
+
+		if err := visor.VerifySingleTxnSoftConstraints(txn, head.Time, inUxs, params.UserVerifyTxn); err != nil {
+			return err
+		}
+		if err := visor.VerifySingleTxnHardConstraints(txn, head, inUxs, visor.TxnSigned); err != nil {
+			return err
+		}
+		if err := visor.VerifySingleTxnUserConstraints(txn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
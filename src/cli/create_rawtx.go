@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/skycoin/skycoin/src/params"
@@ -51,6 +52,8 @@ func createRawTxnCmd() *cobra.Command {
     from all addresses within the wallet starting with the first address until
     the amount of the transaction is met.
 
+    The [to address] can be the name of a recipient in the address book of the wallet.
+
     Use caution when using the "-p" command. If you have command history enabled
     your wallet encryption password can be recovered from the history log. If you
     do not include the "-p" option you will be prompted to enter your password
@@ -95,16 +98,21 @@ func createRawTxnCmd() *cobra.Command {
 	createRawTxnCmd.Flags().StringP("wallet-file", "f", "", "wallet file or path. If no path is specified your default wallet path will be used.")
 	createRawTxnCmd.Flags().StringP("address", "a", "", "From address")
 	createRawTxnCmd.Flags().StringP("change-address", "c", "", `Specify different change address.
-By default the from address or a wallets coinbase address will be used.`)
+By default the from address or a wallets coinbase address will be used,
+or a new change address if the wallet's change address policy is fresh.`)
 	createRawTxnCmd.Flags().StringP("many", "m", "", `use JSON string to set multiple receive addresses and coins,
 example: -m '[{"addr":"$addr1", "coins": "10.2"}, {"addr":"$addr2", "coins": "20"}]'`)
 	createRawTxnCmd.Flags().StringP("password", "p", "", "Wallet password")
 	createRawTxnCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
 	createRawTxnCmd.Flags().String("csv", "", "CSV file containing addresses and amounts to send")
+	createRawTxnCmd.Flags().String("coin-selection", transaction.CoinSelectionMinimizeUxOuts, coinSelectionUsage)
 
 	return createRawTxnCmd
 }
 
+// coinSelectionUsage is the usage of the coin-selection flag of the send and createRawTransaction commands
+var coinSelectionUsage = fmt.Sprintf("Strategy used to choose the outputs to spend, one of: %s", strings.Join(transaction.CoinSelections(), ", "))
+
 type walletAddress struct {
 	Wallet  string
 	Address string
@@ -174,6 +182,16 @@ func getChangeAddress(wltAddr walletAddress, chgAddr string) (string, error) {
 	return chgAddr, nil
 }
 
+// hasFreshChangeAddressPolicy returns true if the change address policy of the wallet is fresh
+func hasFreshChangeAddressPolicy(walletFile string) (bool, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return false, WalletLoadError{err}
+	}
+
+	return wlt.ChangeAddressPolicy() == wallet.ChangeAddressPolicyFresh, nil
+}
+
 func getToAddresses(c *cobra.Command, args []string) ([]SendAmount, error) {
 	csvFile, err := c.Flags().GetString("csv")
 	if err != nil {
@@ -202,12 +220,9 @@ func getToAddresses(c *cobra.Command, args []string) ([]SendAmount, error) {
 		return nil, fmt.Errorf("requires at least 2 arg(s), only received %d", len(args))
 	}
 
+	// The recipient is validated after the address book names are resolved
 	toAddr := args[0]
 
-	if _, err := cipher.DecodeBase58Address(toAddr); err != nil {
-		return nil, err
-	}
-
 	amt, err := getAmount(args)
 	if err != nil {
 		return nil, err
@@ -312,6 +327,7 @@ type createRawTxnArgs struct {
 	ChangeAddress string
 	SendAmounts   []SendAmount
 	Password      PasswordReader
+	CoinSelection string
 }
 
 func parseCreateRawTxnArgs(c *cobra.Command, args []string) (*createRawTxnArgs, error) {
@@ -324,15 +340,34 @@ func parseCreateRawTxnArgs(c *cobra.Command, args []string) (*createRawTxnArgs,
 	if err != nil {
 		return nil, err
 	}
-	chgAddr, err := getChangeAddress(wltAddr, changeAddress)
-	if err != nil {
-		return nil, err
+
+	// If no change address is specified and the wallet's change address policy is fresh,
+	// the change address is left empty and a new one is generated when creating the transaction
+	fresh := false
+	if changeAddress == "" {
+		fresh, err = hasFreshChangeAddressPolicy(wltAddr.Wallet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var chgAddr string
+	if !fresh {
+		chgAddr, err = getChangeAddress(wltAddr, changeAddress)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	toAddrs, err := getToAddresses(c, args)
 	if err != nil {
 		return nil, err
 	}
+	// Recipients can be given by their name in the address book of the wallet
+	toAddrs, err = resolveAddressBookNames(wltAddr.Wallet, toAddrs)
+	if err != nil {
+		return nil, err
+	}
 	if err := validateSendAmounts(toAddrs); err != nil {
 		return nil, err
 	}
@@ -343,12 +378,21 @@ func parseCreateRawTxnArgs(c *cobra.Command, args []string) (*createRawTxnArgs,
 	}
 	pr := NewPasswordReader([]byte(password))
 
+	coinSelection, err := c.Flags().GetString("coin-selection")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := transaction.GetCoinSelector(coinSelection); err != nil {
+		return nil, fmt.Errorf("invalid coin selection %q", coinSelection)
+	}
+
 	return &createRawTxnArgs{
 		WalletID:      wltAddr.Wallet,
 		Address:       wltAddr.Address,
 		ChangeAddress: chgAddr,
 		SendAmounts:   toAddrs,
 		Password:      pr,
+		CoinSelection: coinSelection,
 	}, nil
 }
 
@@ -359,10 +403,10 @@ func createRawTxnCmdHandler(c *cobra.Command, args []string) (*coin.Transaction,
 	}
 
 	if parsedArgs.Address == "" {
-		return CreateRawTxnFromWallet(apiClient, parsedArgs.WalletID, parsedArgs.ChangeAddress, parsedArgs.SendAmounts, parsedArgs.Password)
+		return CreateRawTxnFromWallet(apiClient, parsedArgs.WalletID, parsedArgs.ChangeAddress, parsedArgs.SendAmounts, parsedArgs.Password, parsedArgs.CoinSelection)
 	}
 
-	return CreateRawTxnFromAddress(apiClient, parsedArgs.Address, parsedArgs.WalletID, parsedArgs.ChangeAddress, parsedArgs.SendAmounts, parsedArgs.Password)
+	return CreateRawTxnFromAddress(apiClient, parsedArgs.Address, parsedArgs.WalletID, parsedArgs.ChangeAddress, parsedArgs.SendAmounts, parsedArgs.Password, parsedArgs.CoinSelection)
 }
 
 func validateSendAmounts(toAddrs []SendAmount) error {
@@ -387,12 +431,16 @@ func validateSendAmounts(toAddrs []SendAmount) error {
 
 // PUBLIC
 
-// CreateRawTxnFromWallet creates a transaction from any address or combination of addresses in a wallet
-func CreateRawTxnFromWallet(c GetOutputser, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader) (*coin.Transaction, error) {
+// CreateRawTxnFromWallet creates a transaction from any address or combination of addresses in a wallet.
+// The outputs to spend are chosen with the coinSelection strategy, see transaction.GetCoinSelector.
+// If chgAddr is empty and the wallet's change address policy is fresh, a new change address is
+// generated and saved in the wallet file.
+func CreateRawTxnFromWallet(c GetOutputser, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader, coinSelection string) (*coin.Transaction, error) {
 	// check change address
-	cAddr, err := cipher.DecodeBase58Address(chgAddr)
-	if err != nil {
-		return nil, ErrAddress
+	if chgAddr != "" {
+		if _, err := cipher.DecodeBase58Address(chgAddr); err != nil {
+			return nil, ErrAddress
+		}
 	}
 
 	// check if the change address is in wallet.
@@ -401,9 +449,8 @@ func CreateRawTxnFromWallet(c GetOutputser, walletFile, chgAddr string, toAddrs
 		return nil, err
 	}
 
-	_, ok := wlt.GetEntry(cAddr)
-	if !ok {
-		return nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
+	if err := checkChangeAddress(wlt, chgAddr); err != nil {
+		return nil, err
 	}
 
 	switch pr.(type) {
@@ -438,11 +485,14 @@ func CreateRawTxnFromWallet(c GetOutputser, walletFile, chgAddr string, toAddrs
 		addrStrArray[i] = a.String()
 	}
 
-	return CreateRawTxn(c, wlt, addrStrArray, chgAddr, toAddrs, password)
+	return createRawTxnInFile(c, walletFile, wlt, addrStrArray, chgAddr, toAddrs, password, coinSelection)
 }
 
-// CreateRawTxnFromAddress creates a transaction from a specific address in a wallet
-func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader) (*coin.Transaction, error) {
+// CreateRawTxnFromAddress creates a transaction from a specific address in a wallet.
+// The outputs to spend are chosen with the coinSelection strategy, see transaction.GetCoinSelector.
+// If chgAddr is empty and the wallet's change address policy is fresh, a new change address is
+// generated and saved in the wallet file.
+func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, toAddrs []SendAmount, pr PasswordReader, coinSelection string) (*coin.Transaction, error) {
 	// check if the address is in the default wallet.
 	wlt, err := wallet.Load(walletFile)
 	if err != nil {
@@ -460,14 +510,8 @@ func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, t
 	}
 
 	// validate change address
-	cAddr, err := cipher.DecodeBase58Address(chgAddr)
-	if err != nil {
-		return nil, ErrAddress
-	}
-
-	_, ok = wlt.GetEntry(cAddr)
-	if !ok {
-		return nil, fmt.Errorf("change address %v is not in wallet", chgAddr)
+	if err := checkChangeAddress(wlt, chgAddr); err != nil {
+		return nil, err
 	}
 
 	switch pr.(type) {
@@ -495,7 +539,78 @@ func CreateRawTxnFromAddress(c GetOutputser, addr, walletFile, chgAddr string, t
 		}
 	}
 
-	return CreateRawTxn(c, wlt, []string{addr}, chgAddr, toAddrs, password)
+	return createRawTxnInFile(c, walletFile, wlt, []string{addr}, chgAddr, toAddrs, password, coinSelection)
+}
+
+// checkChangeAddress checks that the change address is in the wallet.
+// The change address can be empty if the wallet's change address policy is fresh.
+func checkChangeAddress(wlt *wallet.Wallet, chgAddr string) error {
+	if chgAddr == "" && wlt.ChangeAddressPolicy() == wallet.ChangeAddressPolicyFresh {
+		return nil
+	}
+
+	cAddr, err := cipher.DecodeBase58Address(chgAddr)
+	if err != nil {
+		return ErrAddress
+	}
+
+	if _, ok := wlt.GetEntry(cAddr); !ok {
+		return fmt.Errorf("change address %v is not in wallet", chgAddr)
+	}
+
+	return nil
+}
+
+// createRawTxnInFile creates a transaction with CreateRawTxn from a wallet loaded from walletFile.
+// If chgAddr is empty, a new change address is generated in the wallet, and the wallet
+// is saved to walletFile once the transaction is created, if the transaction pays change to the address.
+func createRawTxnInFile(c GetOutputser, walletFile string, wlt *wallet.Wallet, inAddrs []string, chgAddr string, toAddrs []SendAmount, password []byte, coinSelection string) (*coin.Transaction, error) {
+	if chgAddr != "" {
+		return CreateRawTxn(c, wlt, inAddrs, chgAddr, toAddrs, password, coinSelection)
+	}
+
+	var changeAddr cipher.Addresser
+	generateChangeAddress := func(w *wallet.Wallet) error {
+		var err error
+		changeAddr, err = w.GenerateChangeAddress()
+		return err
+	}
+
+	if wlt.IsEncrypted() {
+		if err := wlt.GuardUpdate(password, generateChangeAddress); err != nil {
+			return nil, err
+		}
+	} else if err := generateChangeAddress(wlt); err != nil {
+		return nil, err
+	}
+
+	txn, err := CreateRawTxn(c, wlt, inAddrs, changeAddr.String(), toAddrs, password, coinSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	// The change address is not kept if the transaction has no change
+	hasChange := false
+	for _, o := range txn.Out {
+		if o.Address.String() == changeAddr.String() {
+			hasChange = true
+			break
+		}
+	}
+	if !hasChange {
+		return txn, nil
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(walletFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlt.Save(dir); err != nil {
+		return nil, WalletSaveError{err}
+	}
+
+	return txn, nil
 }
 
 // GetOutputser implements unspent output querying
@@ -503,24 +618,30 @@ type GetOutputser interface {
 	OutputsForAddresses([]string) (*readable.UnspentOutputsSummary, error)
 }
 
-// CreateRawTxn creates a transaction from a set of addresses contained in a loaded *wallet.Wallet
-func CreateRawTxn(c GetOutputser, wlt *wallet.Wallet, inAddrs []string, chgAddr string, toAddrs []SendAmount, password []byte) (*coin.Transaction, error) {
+// CreateRawTxn creates a transaction from a set of addresses contained in a loaded *wallet.Wallet.
+// The outputs to spend are chosen with the coinSelection strategy, see transaction.GetCoinSelector
+func CreateRawTxn(c GetOutputser, wlt *wallet.Wallet, inAddrs []string, chgAddr string, toAddrs []SendAmount, password []byte, coinSelection string) (*coin.Transaction, error) {
+	if wlt.IsWatchOnly() {
+		return nil, wallet.ErrWatchOnlyWallet
+	}
+
 	if err := validateSendAmounts(toAddrs); err != nil {
 		return nil, err
 	}
 
-	// Get unspent outputs of those addresses
+	// Get unspent outputs of those addresses, except the frozen outputs of the wallet
 	outputs, err := c.OutputsForAddresses(inAddrs)
 	if err != nil {
 		return nil, err
 	}
+	outputs = excludeFrozenOutputs(outputs, wlt)
 
 	inUxs, err := outputs.SpendableOutputs().ToUxArray()
 	if err != nil {
 		return nil, err
 	}
 
-	txn, err := createRawTxn(outputs, wlt, chgAddr, toAddrs, password)
+	txn, err := createRawTxn(outputs, wlt, chgAddr, toAddrs, password, coinSelection)
 	if err != nil {
 		return nil, err
 	}
@@ -553,7 +674,7 @@ func CreateRawTxn(c GetOutputser, wlt *wallet.Wallet, inAddrs []string, chgAddr
 	return txn, nil
 }
 
-func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt *wallet.Wallet, chgAddr string, toAddrs []SendAmount, password []byte) (*coin.Transaction, error) {
+func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt *wallet.Wallet, chgAddr string, toAddrs []SendAmount, password []byte, coinSelection string) (*coin.Transaction, error) {
 	// Calculate total required coins
 	var totalCoins uint64
 	for _, arg := range toAddrs {
@@ -564,7 +685,7 @@ func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt *wallet.Wallet, ch
 		}
 	}
 
-	spendOutputs, err := chooseSpends(uxouts, totalCoins)
+	spendOutputs, err := chooseSpends(uxouts, totalCoins, coinSelection)
 	if err != nil {
 		return nil, err
 	}
@@ -605,7 +726,12 @@ func createRawTxn(uxouts *readable.UnspentOutputsSummary, wlt *wallet.Wallet, ch
 	return makeTxn()
 }
 
-func chooseSpends(uxouts *readable.UnspentOutputsSummary, coins uint64) ([]transaction.UxBalance, error) {
+func chooseSpends(uxouts *readable.UnspentOutputsSummary, coins uint64, coinSelection string) ([]transaction.UxBalance, error) {
+	selector, err := transaction.GetCoinSelector(coinSelection)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert spendable unspent outputs to []transaction.UxBalance
 	spendableOutputs, err := readable.OutputsToUxBalances(uxouts.SpendableOutputs())
 	if err != nil {
@@ -613,11 +739,11 @@ func chooseSpends(uxouts *readable.UnspentOutputsSummary, coins uint64) ([]trans
 	}
 
 	// Choose which unspent outputs to spend
-	// Use the MinimizeUxOuts strategy, since this is most likely used by
+	// The default is the MinimizeUxOuts strategy, since this is most likely used by
 	// application that may need to send frequently.
 	// Using fewer UxOuts will leave more available for other transactions,
 	// instead of waiting for confirmation.
-	outs, err := transaction.ChooseSpendsMinimizeUxOuts(spendableOutputs, coins, 0)
+	outs, err := selector.ChooseSpends(spendableOutputs, coins, 0)
 	if err != nil {
 		// If there is not enough balance in the spendable outputs,
 		// see if there is enough balance when including incoming outputs
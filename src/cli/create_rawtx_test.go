@@ -512,7 +512,7 @@ func TestChooseSpends(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			spends, err := chooseSpends(&tc.ros, coins)
+			spends, err := chooseSpends(&tc.ros, coins, "")
 
 			if tc.err != nil {
 				testutil.RequireError(t, err, tc.err.Error())
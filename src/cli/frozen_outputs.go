@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	gcli "github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// FrozenOutputsResult the frozen outputs json format
+type FrozenOutputsResult struct {
+	FrozenOutputs []wallet.ReadableFrozenOutput `json:"frozen_outputs"`
+}
+
+func walletFreezeOutputsCmd() *gcli.Command {
+	walletFreezeOutputsCmd := &gcli.Command{
+		Use:   "walletFreezeOutputs [uxids]",
+		Short: "Freeze unspent outputs of a wallet, so that they are never spent",
+		Long: fmt.Sprintf(`Freeze unspent outputs of a wallet. Frozen outputs are never chosen to spend
+    by the transactions created from the wallet, until they are unfrozen.
+    The default wallet (%s) will be used if no wallet was specified.`, cliConfig.FullWalletPath()),
+		Args:         gcli.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			reason, err := c.Flags().GetString("reason")
+			if err != nil {
+				return err
+			}
+
+			fos, err := FreezeOutputsInFile(w, args, reason)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(FrozenOutputsResult{
+				FrozenOutputs: newReadableFrozenOutputs(fos),
+			})
+		},
+	}
+
+	walletFreezeOutputsCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+	walletFreezeOutputsCmd.Flags().StringP("reason", "r", "", "Reason the outputs are frozen")
+
+	return walletFreezeOutputsCmd
+}
+
+func walletUnfreezeOutputsCmd() *gcli.Command {
+	walletUnfreezeOutputsCmd := &gcli.Command{
+		Use:   "walletUnfreezeOutputs [uxids]",
+		Short: "Unfreeze frozen unspent outputs of a wallet",
+		Long: fmt.Sprintf(`Unfreeze frozen unspent outputs of a wallet, so that they can be spent again.
+    The default wallet (%s) will be used if no wallet was specified.`, cliConfig.FullWalletPath()),
+		Args:         gcli.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			w, err := resolveWalletPath(cliConfig, c.Flag("wallet-file").Value.String())
+			if err != nil {
+				return err
+			}
+
+			fos, err := UnfreezeOutputsInFile(w, args)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			return printJSON(FrozenOutputsResult{
+				FrozenOutputs: newReadableFrozenOutputs(fos),
+			})
+		},
+	}
+
+	walletUnfreezeOutputsCmd.Flags().StringP("wallet-file", "f", cliConfig.FullWalletPath(), "wallet file or path. If no path is specified your default wallet path will be used.")
+
+	return walletUnfreezeOutputsCmd
+}
+
+func walletFrozenOutputsCmd() *gcli.Command {
+	return &gcli.Command{
+		Use:   "walletFrozenOutputs [wallet file]",
+		Short: "List the frozen unspent outputs of a wallet",
+		Long: fmt.Sprintf(`List the frozen unspent outputs of a wallet, the default wallet (%s) will be
+    used if no wallet was specified, use ENV 'WALLET_NAME'
+    to update default wallet file name, and 'WALLET_DIR' to update
+    the default wallet directory`, cliConfig.FullWalletPath()),
+		Args:                  gcli.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(_ *gcli.Command, args []string) error {
+			var wltPath string
+			if len(args) == 1 {
+				wltPath = args[0]
+			}
+
+			w, err := resolveWalletPath(cliConfig, wltPath)
+			if err != nil {
+				return err
+			}
+
+			wlt, err := wallet.Load(w)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(FrozenOutputsResult{
+				FrozenOutputs: newReadableFrozenOutputs(wlt.GetFrozenOutputs()),
+			})
+		},
+	}
+}
+
+// newReadableFrozenOutputs creates []wallet.ReadableFrozenOutput that is not nil, to be printed as an empty array
+func newReadableFrozenOutputs(fos []wallet.FrozenOutput) []wallet.ReadableFrozenOutput {
+	rfos := wallet.NewReadableFrozenOutputs(fos)
+	if rfos == nil {
+		rfos = []wallet.ReadableFrozenOutput{}
+	}
+	return rfos
+}
+
+// PUBLIC
+
+// FreezeOutputsInFile freezes unspent outputs in a wallet file. The outputs are not checked against the blockchain.
+// Returns the outputs that were frozen; outputs that were already frozen are not returned.
+func FreezeOutputsInFile(walletFile string, uxIDs []string, reason string) ([]wallet.FrozenOutput, error) {
+	uxOuts, err := parseUxIDs(uxIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateFrozenOutputsInFile(walletFile, func(w *wallet.Wallet) ([]wallet.FrozenOutput, error) {
+		return w.FreezeOutputs(uxOuts, reason, time.Now().UTC())
+	})
+}
+
+// UnfreezeOutputsInFile unfreezes unspent outputs in a wallet file.
+// Returns the outputs that were unfrozen; outputs that were not frozen are ignored.
+func UnfreezeOutputsInFile(walletFile string, uxIDs []string) ([]wallet.FrozenOutput, error) {
+	uxOuts, err := parseUxIDs(uxIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateFrozenOutputsInFile(walletFile, func(w *wallet.Wallet) ([]wallet.FrozenOutput, error) {
+		return w.UnfreezeOutputs(uxOuts)
+	})
+}
+
+func updateFrozenOutputsInFile(walletFile string, f func(*wallet.Wallet) ([]wallet.FrozenOutput, error)) ([]wallet.FrozenOutput, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return nil, WalletLoadError{err}
+	}
+
+	fos, err := f(wlt)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(walletFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlt.Save(dir); err != nil {
+		return nil, WalletSaveError{err}
+	}
+
+	return fos, nil
+}
+
+func parseUxIDs(uxIDs []string) ([]cipher.SHA256, error) {
+	uxOuts := make([]cipher.SHA256, len(uxIDs))
+	for i, id := range uxIDs {
+		h, err := cipher.SHA256FromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uxid %q: %v", id, err)
+		}
+		uxOuts[i] = h
+	}
+
+	return uxOuts, nil
+}
+
+// excludeFrozenOutputs returns a copy of the unspent outputs summary without the frozen outputs of the wallet
+func excludeFrozenOutputs(outputs *readable.UnspentOutputsSummary, wlt *wallet.Wallet) *readable.UnspentOutputsSummary {
+	if len(wlt.FrozenOutputs) == 0 {
+		return outputs
+	}
+
+	frozen := wlt.FrozenUxOuts()
+	exclude := func(uxs readable.UnspentOutputs) readable.UnspentOutputs {
+		var unfrozen readable.UnspentOutputs
+		for _, ux := range uxs {
+			h, err := cipher.SHA256FromHex(ux.Hash)
+			if err == nil {
+				if _, ok := frozen[h]; ok {
+					continue
+				}
+			}
+			unfrozen = append(unfrozen, ux)
+		}
+		return unfrozen
+	}
+
+	summary := *outputs
+	summary.HeadOutputs = exclude(outputs.HeadOutputs)
+	summary.IncomingOutputs = exclude(outputs.IncomingOutputs)
+	return &summary
+}
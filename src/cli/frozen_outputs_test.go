@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/readable"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestFreezeOutputsInFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := wallet.NewWallet("t.wlt", wallet.Options{
+		Seed:      "seed",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	walletFile := filepath.Join(dir, "t.wlt")
+
+	h1 := testutil.RandSHA256(t)
+	h2 := testutil.RandSHA256(t)
+	h3 := testutil.RandSHA256(t)
+
+	_, err = FreezeOutputsInFile(walletFile, []string{"foo"}, "")
+	require.Error(t, err)
+
+	_, err = FreezeOutputsInFile(filepath.Join(dir, "missing.wlt"), []string{h1.Hex()}, "")
+	require.IsType(t, WalletLoadError{}, err)
+
+	fos, err := FreezeOutputsInFile(walletFile, []string{h1.Hex(), h2.Hex()}, "tainted")
+	require.NoError(t, err)
+	require.Len(t, fos, 2)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.True(t, w.IsFrozen(h1))
+	require.True(t, w.IsFrozen(h2))
+	require.False(t, w.IsFrozen(h3))
+
+	// The frozen outputs are not spendable
+	outputs := &readable.UnspentOutputsSummary{
+		HeadOutputs: readable.UnspentOutputs{
+			{Hash: h1.Hex()},
+			{Hash: h3.Hex()},
+		},
+		IncomingOutputs: readable.UnspentOutputs{
+			{Hash: h2.Hex()},
+		},
+	}
+	unfrozen := excludeFrozenOutputs(outputs, w)
+	require.Equal(t, readable.UnspentOutputs{{Hash: h3.Hex()}}, unfrozen.SpendableOutputs())
+	require.Equal(t, readable.UnspentOutputs{{Hash: h3.Hex()}}, unfrozen.ExpectedOutputs())
+	require.Len(t, outputs.HeadOutputs, 2)
+
+	fos, err = UnfreezeOutputsInFile(walletFile, []string{h1.Hex(), h3.Hex()})
+	require.NoError(t, err)
+	require.Len(t, fos, 1)
+	require.Equal(t, h1, fos[0].UxOut)
+
+	w, err = wallet.Load(walletFile)
+	require.NoError(t, err)
+	require.False(t, w.IsFrozen(h1))
+	require.True(t, w.IsFrozen(h2))
+}
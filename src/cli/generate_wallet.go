@@ -44,10 +44,15 @@ func walletCreateCmd() *gcli.Command {
 	walletCreateCmd.Flags().StringP("wallet-file", "f", cliConfig.WalletName, `Name of wallet. The final format will be "yourName.wlt".
 If no wallet name is specified a generic name will be selected.`)
 	walletCreateCmd.Flags().StringP("label", "l", "", "Label used to idetify your wallet.")
+	walletCreateCmd.Flags().StringP("type", "t", wallet.WalletTypeDeterministic, "Wallet type, can be deterministic or bip44. bip44 wallets require a mnemonic seed")
 	walletCreateCmd.Flags().BoolP("encrypt", "e", false, "Create encrypted wallet.")
 	walletCreateCmd.Flags().StringP("crypto-type", "x", string(wallet.CryptoTypeScryptChacha20poly1305),
-		"The crypto type for wallet encryption, can be scrypt-chacha20poly1305 or sha256-xor")
+		"The crypto type for wallet encryption, can be scrypt-chacha20poly1305, argon2id-xchacha20poly1305 or sha256-xor")
 	walletCreateCmd.Flags().StringP("password", "p", "", "Wallet password")
+	walletCreateCmd.Flags().String("change-address-policy", wallet.ChangeAddressPolicyReuse, `Change address policy, can be reuse or fresh.
+With fresh, the change of transactions created without a change address is sent to a new address of the wallet.`)
+	walletCreateCmd.Flags().String("seed-passphrase", "", `Optional bip39 passphrase of the seed, only for bip44 wallets.
+The addresses of the wallet depend on it, the wallet can't be recovered without it.`)
 
 	return walletCreateCmd
 }
@@ -112,6 +117,17 @@ func generateWalletHandler(c *gcli.Command, _ []string) error {
 		return err
 	}
 
+	walletType := c.Flag("type").Value.String()
+	switch walletType {
+	case wallet.WalletTypeDeterministic:
+	case wallet.WalletTypeBip44:
+		if random {
+			return errors.New("bip44 wallets require a mnemonic seed, must not use -r")
+		}
+	default:
+		return wallet.ErrInvalidWalletType
+	}
+
 	sd, err := makeSeed(s, random, mnemonic)
 	if err != nil {
 		return err
@@ -145,11 +161,14 @@ func generateWalletHandler(c *gcli.Command, _ []string) error {
 	}
 
 	opts := wallet.Options{
-		Label:      label,
-		Seed:       sd,
-		Encrypt:    encrypt,
-		CryptoType: cryptoType,
-		Password:   password,
+		Type:                walletType,
+		Label:               label,
+		Seed:                sd,
+		Encrypt:             encrypt,
+		CryptoType:          cryptoType,
+		Password:            password,
+		ChangeAddressPolicy: c.Flag("change-address-policy").Value.String(),
+		SeedPassphrase:      c.Flag("seed-passphrase").Value.String(),
 	}
 
 	wlt, err := GenerateWallet(wltName, opts, num)
@@ -196,8 +215,11 @@ func GenerateWallet(walletFile string, opts wallet.Options, numAddrs uint64) (*w
 	walletFile = filepath.Base(walletFile)
 
 	wlt, err := wallet.NewWallet(walletFile, wallet.Options{
-		Seed:  opts.Seed,
-		Label: opts.Label,
+		Seed:                opts.Seed,
+		Label:               opts.Label,
+		Type:                opts.Type,
+		ChangeAddressPolicy: opts.ChangeAddressPolicy,
+		SeedPassphrase:      opts.SeedPassphrase,
 	})
 	if err != nil {
 		return nil, err
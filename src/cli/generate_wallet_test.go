@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestGenerateWalletSeedPassphrase(t *testing.T) {
+	seed := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	w, err := GenerateWallet("t.wlt", wallet.Options{
+		Type: wallet.WalletTypeBip44,
+		Seed: seed,
+	}, 2)
+	require.NoError(t, err)
+
+	w2, err := GenerateWallet("t.wlt", wallet.Options{
+		Type:           wallet.WalletTypeBip44,
+		Seed:           seed,
+		SeedPassphrase: "TREZOR",
+	}, 2)
+	require.NoError(t, err)
+	require.Len(t, w2.Entries, 2)
+	require.NotEqual(t, w.Entries[0].Address, w2.Entries[0].Address)
+
+	_, err = GenerateWallet("t.wlt", wallet.Options{
+		Seed:           seed,
+		SeedPassphrase: "TREZOR",
+	}, 1)
+	require.Equal(t, wallet.ErrSeedPassphraseNotSupported, err)
+
+	// The checksum of the mnemonic is validated
+	_, err = GenerateWallet("t.wlt", wallet.Options{
+		Type: wallet.WalletTypeBip44,
+		Seed: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+	}, 1)
+	require.Equal(t, wallet.ErrInvalidBip44Seed, err)
+}
@@ -1,8 +1,6 @@
 package cli
 
 import (
-	"fmt"
-
 	"github.com/skycoin/skycoin/src/wallet"
 
 	gcli "github.com/spf13/cobra"
@@ -36,14 +34,26 @@ func listAddresses(_ *gcli.Command, args []string) error {
 		return WalletLoadError{err}
 	}
 
-	addrs := wlt.GetAddresses()
+	return printJSON(newListAddressesResult(wlt))
+}
 
-	s, err := FormatAddressesAsJSON(addrs)
-	if err != nil {
-		return err
+// ListAddressesResult the listAddresses json format.
+// Labels and AddressBook are omitted if empty, so that the output is unchanged for wallets without them.
+type ListAddressesResult struct {
+	Addresses   []string                          `json:"addresses"`
+	Labels      map[string]string                 `json:"labels,omitempty"`
+	AddressBook []wallet.ReadableAddressBookEntry `json:"address_book,omitempty"`
+}
+
+func newListAddressesResult(wlt *wallet.Wallet) ListAddressesResult {
+	r := ListAddressesResult{
+		Addresses:   AddressesToStrings(wlt.GetAddresses()),
+		AddressBook: wallet.NewReadableAddressBook(wlt.GetAddressBook()),
 	}
 
-	fmt.Println(s)
+	if labels := wlt.AddressLabels(); len(labels) != 0 {
+		r.Labels = labels
+	}
 
-	return nil
+	return r
 }
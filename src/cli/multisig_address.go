@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+func multisigAddressCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Create a multisig address from public keys",
+		Use:   "multisigAddress [required signatures] [public key]...",
+		Long: `Creates an m-of-n multisig address, which requires signatures from
+    "required signatures" of the public keys to spend. The order of the public keys
+    is significant. Node access is not required.`,
+		Args:                  cobra.MinimumNArgs(2),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			required, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid number of required signatures: %v", err)
+			}
+
+			pubKeys, err := parsePubKeys(args[1:])
+			if err != nil {
+				return err
+			}
+
+			addr, err := cipher.MultisigAddressFromPubKeys(required, pubKeys)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(addr.String())
+			return nil
+		},
+	}
+}
+
+func parsePubKeys(pks []string) ([]cipher.PubKey, error) {
+	pubKeys := make([]cipher.PubKey, len(pks))
+	for i, pk := range pks {
+		p, err := cipher.PubKeyFromHex(pk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %v", pk, err)
+		}
+		pubKeys[i] = p
+	}
+	return pubKeys, nil
+}
@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/api"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func createPartialTxnCmd() *cobra.Command {
+	createPartialTxnCmd := &cobra.Command{
+		Short: "Create a partially signed transaction from a raw transaction",
+		Use:   "createPartialTransaction [flags] [raw transaction]",
+		Long: `Creates a partially signed transaction from an unsigned or partially signed raw transaction.
+    The outputs spent by the transaction are looked up on the node and included in the result,
+    so that it can be signed with signPartialTransaction on a machine without node access.
+
+    Inputs owned by multisig addresses can only be signed once the public keys of the address
+    are known. Specify them with --multisig-input for each multisig input, in the format
+    "index:required:pubkey1,pubkey2,...".
+
+    Inputs owned by time-locked addresses can only be signed once the lock and the owner
+    of the address are known. Specify them with --time-lock-input for each time-locked input,
+    in the format "index:owner:height|time:value". If the owner is a multisig address,
+    also specify its public keys with --multisig-input for the same index.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			multisigInputs, err := c.Flags().GetStringArray("multisig-input")
+			if err != nil {
+				return err
+			}
+
+			timeLockInputs, err := c.Flags().GetStringArray("time-lock-input")
+			if err != nil {
+				return err
+			}
+
+			req := api.CreatePartialTransactionRequest{
+				EncodedTransaction: args[0],
+			}
+
+			for _, s := range timeLockInputs {
+				in, err := parseTimeLockInput(s)
+				if err != nil {
+					return err
+				}
+				req.TimeLockInputs = append(req.TimeLockInputs, in)
+			}
+
+			for _, s := range multisigInputs {
+				in, err := parseMultisigInput(s)
+				if err != nil {
+					return err
+				}
+				req.MultisigInputs = append(req.MultisigInputs, in)
+			}
+
+			pst, err := apiClient.CreatePartialTransaction(req)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(pst)
+		},
+	}
+
+	createPartialTxnCmd.Flags().StringArrayP("multisig-input", "m", nil, "Public keys of a multisig input, as index:required:pubkey1,pubkey2,...")
+	createPartialTxnCmd.Flags().StringArrayP("time-lock-input", "l", nil, "Lock and owner of a time-locked input, as index:owner:height|time:value")
+
+	return createPartialTxnCmd
+}
+
+// parseMultisigInput parses a multisig input in the format index:required:pubkey1,pubkey2,...
+func parseMultisigInput(s string) (api.PartialTransactionMultisigInput, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 3 {
+		return api.PartialTransactionMultisigInput{}, fmt.Errorf("invalid multisig input %q, expected index:required:pubkey1,pubkey2,...", s)
+	}
+
+	index, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return api.PartialTransactionMultisigInput{}, fmt.Errorf("invalid multisig input index %q: %v", fields[0], err)
+	}
+
+	required, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return api.PartialTransactionMultisigInput{}, fmt.Errorf("invalid multisig input required signatures %q: %v", fields[1], err)
+	}
+
+	return api.PartialTransactionMultisigInput{
+		Index:    index,
+		Required: required,
+		PubKeys:  strings.Split(fields[2], ","),
+	}, nil
+}
+
+// parseTimeLockInput parses a time-locked input in the format index:owner:height|time:value
+func parseTimeLockInput(s string) (api.PartialTransactionTimeLockInput, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 4 {
+		return api.PartialTransactionTimeLockInput{}, fmt.Errorf("invalid time-lock input %q, expected index:owner:height|time:value", s)
+	}
+
+	index, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return api.PartialTransactionTimeLockInput{}, fmt.Errorf("invalid time-lock input index %q: %v", fields[0], err)
+	}
+
+	value, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return api.PartialTransactionTimeLockInput{}, fmt.Errorf("invalid time-lock input value %q: %v", fields[3], err)
+	}
+
+	return api.PartialTransactionTimeLockInput{
+		Index: index,
+		Owner: fields[1],
+		Kind:  fields[2],
+		Value: value,
+	}, nil
+}
+
+func signPartialTxnCmd() *cobra.Command {
+	signPartialTxnCmd := &cobra.Command{
+		Short: "Sign a partially signed transaction with a local wallet",
+		Use:   "signPartialTransaction [flags] [partial transaction file]",
+		Long: fmt.Sprintf(`Signs the inputs of a partially signed transaction that are owned by a wallet.
+    Node access is not required. If no sign indexes are specified, all unsigned inputs
+    owned by the wallet are signed.
+    The default wallet (%s) will be used if no wallet was specified.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, cliConfig.FullWalletPath()),
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			walletFile, err := c.Flags().GetString("wallet-file")
+			if err != nil {
+				return err
+			}
+
+			password, err := c.Flags().GetString("password")
+			if err != nil {
+				return err
+			}
+
+			signIndexes, err := c.Flags().GetIntSlice("sign-indexes")
+			if err != nil {
+				return err
+			}
+
+			w, err := resolveWalletPath(cliConfig, walletFile)
+			if err != nil {
+				return err
+			}
+
+			pst, err := loadPartiallySignedTransaction(args[0])
+			if err != nil {
+				return err
+			}
+
+			pr := NewPasswordReader([]byte(password))
+			signedPst, err := SignPartiallySignedTransaction(w, pst, signIndexes, pr)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			rpst, err := wallet.NewReadablePartiallySignedTransaction(signedPst)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(rpst)
+		},
+	}
+
+	signPartialTxnCmd.Flags().StringP("wallet-file", "f", "", "wallet file or path. If no path is specified your default wallet path will be used.")
+	signPartialTxnCmd.Flags().StringP("password", "p", "", "Wallet password")
+	signPartialTxnCmd.Flags().IntSliceP("sign-indexes", "i", nil, "Indexes of the inputs to sign. By default all unsigned inputs owned by the wallet are signed.")
+
+	return signPartialTxnCmd
+}
+
+// SignPartiallySignedTransaction signs a partially signed transaction with a wallet file
+func SignPartiallySignedTransaction(walletFile string, pst *wallet.PartiallySignedTransaction, signIndexes []int, pr PasswordReader) (*wallet.PartiallySignedTransaction, error) {
+	wlt, err := wallet.Load(walletFile)
+	if err != nil {
+		return nil, WalletLoadError{err}
+	}
+
+	if wlt.IsWatchOnly() {
+		return nil, wallet.ErrWatchOnlyWallet
+	}
+
+	switch pr.(type) {
+	case nil:
+		if wlt.IsEncrypted() {
+			return nil, wallet.ErrWalletEncrypted
+		}
+	case PasswordFromBytes:
+		p, err := pr.Password()
+		if err != nil {
+			return nil, err
+		}
+
+		if !wlt.IsEncrypted() && len(p) != 0 {
+			return nil, wallet.ErrWalletNotEncrypted
+		}
+	}
+
+	if !wlt.IsEncrypted() {
+		return wlt.SignPartiallySignedTransaction(pst, signIndexes)
+	}
+
+	password, err := pr.Password()
+	if err != nil {
+		return nil, err
+	}
+
+	var signedPst *wallet.PartiallySignedTransaction
+	if err := wlt.GuardView(password, func(w *wallet.Wallet) error {
+		var err error
+		signedPst, err = w.SignPartiallySignedTransaction(pst, signIndexes)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return signedPst, nil
+}
+
+func combinePartialTxnsCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Combine the signatures of partially signed transactions",
+		Use:   "combinePartialTransactions [partial transaction file]...",
+		Long: `Merges the signatures of partially signed transactions of the same transaction,
+    e.g. after each party signed their inputs separately. Node access is not required.`,
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			psts := make([]*wallet.PartiallySignedTransaction, len(args))
+			for i, f := range args {
+				pst, err := loadPartiallySignedTransaction(f)
+				if err != nil {
+					return err
+				}
+				psts[i] = pst
+			}
+
+			pst, err := wallet.CombinePartiallySignedTransactions(psts...)
+			if err != nil {
+				return err
+			}
+
+			rpst, err := wallet.NewReadablePartiallySignedTransaction(pst)
+			if err != nil {
+				return err
+			}
+
+			return printJSON(rpst)
+		},
+	}
+}
+
+func finalizePartialTxnCmd() *cobra.Command {
+	finalizePartialTxnCmd := &cobra.Command{
+		Short: "Extract the raw transaction from a fully signed partially signed transaction",
+		Use:   "finalizePartialTransaction [flags] [partial transaction file]",
+		Long: `Verifies that all inputs of a partially signed transaction are signed and prints the raw transaction.
+    The raw transaction can be broadcast with broadcastTransaction. Node access is not required.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			pst, err := loadPartiallySignedTransaction(args[0])
+			if err != nil {
+				return err
+			}
+
+			txn, err := pst.Finalize()
+			if err != nil {
+				return err
+			}
+
+			rawTxn, err := txn.SerializeHex()
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(api.FinalizePartialTransactionResponse{
+					TxID:               txn.Hash().Hex(),
+					EncodedTransaction: rawTxn,
+				})
+			}
+
+			fmt.Println(rawTxn)
+			return nil
+		},
+	}
+
+	finalizePartialTxnCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return finalizePartialTxnCmd
+}
+
+func loadPartiallySignedTransaction(filename string) (*wallet.PartiallySignedTransaction, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var rpst wallet.ReadablePartiallySignedTransaction
+	if err := json.Unmarshal(b, &rpst); err != nil {
+		return nil, fmt.Errorf("invalid partially signed transaction file %s: %v", filename, err)
+	}
+
+	return rpst.ToPartiallySignedTransaction()
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gcli "github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// SeedSharesResult is the result of splitSeed
+type SeedSharesResult struct {
+	Threshold int      `json:"threshold"`
+	Shares    []string `json:"shares"`
+}
+
+func splitSeedCmd() *gcli.Command {
+	splitSeedCmd := &gcli.Command{
+		Short: "Split the seed of a wallet into shares",
+		Use:   "splitSeed [wallet]",
+		Long: fmt.Sprintf(`Split the seed of a wallet into shares with Shamir's secret sharing.
+    Any threshold shares recover the seed with combineSeedShares, fewer shares reveal nothing about it.
+    Each share is a list of words with a checksum, to be kept by a different person.
+    The bip39 passphrase of a bip44 wallet seed is not part of the shares.
+    The default wallet (%s) will be used if no wallet was specified.
+    This command works offline.
+
+    Use caution when using the "-p" command. If you have command history enabled
+    your wallet encryption password can be recovered from the history log. If you
+    do not include the "-p" option you will be prompted to enter your password
+    after you enter your command.`, cliConfig.FullWalletPath()),
+		Args:         gcli.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			var w string
+			if len(args) == 1 {
+				w = args[0]
+			}
+
+			w, err := resolveWalletPath(cliConfig, w)
+			if err != nil {
+				return err
+			}
+
+			n, err := c.Flags().GetInt("shares")
+			if err != nil {
+				return err
+			}
+
+			threshold, err := c.Flags().GetInt("threshold")
+			if err != nil {
+				return err
+			}
+
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			pr := NewPasswordReader([]byte(c.Flag("password").Value.String()))
+			shares, err := SplitWalletSeed(w, pr, n, threshold)
+			switch err.(type) {
+			case nil:
+			case WalletLoadError:
+				printHelp(c)
+				return err
+			default:
+				return err
+			}
+
+			if jsonOutput {
+				return printJSON(SeedSharesResult{
+					Threshold: threshold,
+					Shares:    shares,
+				})
+			}
+
+			for _, s := range shares {
+				fmt.Println(s)
+			}
+			return nil
+		},
+	}
+
+	splitSeedCmd.Flags().IntP("shares", "n", 3, "Number of shares")
+	splitSeedCmd.Flags().IntP("threshold", "m", 2, "Number of shares required to recover the seed")
+	splitSeedCmd.Flags().StringP("password", "p", "", "Wallet password")
+	splitSeedCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return splitSeedCmd
+}
+
+func combineSeedSharesCmd() *gcli.Command {
+	combineSeedSharesCmd := &gcli.Command{
+		Short: "Recover a wallet seed from its shares",
+		Use:   "combineSeedShares [share...]",
+		Long: `Recover a wallet seed from the shares created by splitSeed.
+    Each share is a quoted list of words. If no share is given as an argument,
+    the shares are read from stdin, one share per line.
+    The recovered seed can be used to create the wallet with walletCreate.
+    This command works offline.
+
+    Use caution when giving the shares as arguments. If you have command history enabled
+    the shares can be recovered from the history log.`,
+		Args:         gcli.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE: func(c *gcli.Command, args []string) error {
+			jsonOutput, err := c.Flags().GetBool("json")
+			if err != nil {
+				return err
+			}
+
+			shares := args
+			if len(shares) == 0 {
+				shares, err = readSeedShares(os.Stdin)
+				if err != nil {
+					return err
+				}
+			}
+
+			seed, err := wallet.CombineSeedShares(shares)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				v := struct {
+					Seed string `json:"seed"`
+				}{
+					Seed: seed,
+				}
+
+				return printJSON(v)
+			}
+
+			fmt.Println(seed)
+			return nil
+		},
+	}
+
+	combineSeedSharesCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
+
+	return combineSeedSharesCmd
+}
+
+// readSeedShares reads one seed share per line, skipping blank lines
+func readSeedShares(r io.Reader) ([]string, error) {
+	var shares []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if s := strings.TrimSpace(scanner.Text()); s != "" {
+			shares = append(shares, s)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(shares) == 0 {
+		return nil, errors.New("no seed shares were given")
+	}
+
+	return shares, nil
+}
+
+// PUBLIC
+
+// SplitWalletSeed splits the seed of a wallet file into n shares, any threshold of which recover the seed
+func SplitWalletSeed(walletFile string, pr PasswordReader, n, threshold int) ([]string, error) {
+	seed, err := getSeed(walletFile, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet.SplitSeed(seed, n, threshold)
+}
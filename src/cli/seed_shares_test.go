@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestSplitWalletSeed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := wallet.NewWallet("t.wlt", wallet.Options{
+		Seed:       "seed",
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: wallet.CryptoTypeSha256Xor,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Save(dir))
+	walletFile := filepath.Join(dir, "t.wlt")
+
+	_, err = SplitWalletSeed(filepath.Join(dir, "missing.wlt"), PasswordFromBytes("pwd"), 3, 2)
+	require.IsType(t, WalletLoadError{}, err)
+
+	_, err = SplitWalletSeed(walletFile, PasswordFromBytes("wrong"), 3, 2)
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	shares, err := SplitWalletSeed(walletFile, PasswordFromBytes("pwd"), 3, 2)
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+
+	// The shares are read one per line, blank lines are skipped
+	ss, err := readSeedShares(strings.NewReader(shares[2] + "\n\n  " + shares[0] + "  \n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{shares[2], shares[0]}, ss)
+
+	seed, err := wallet.CombineSeedShares(ss)
+	require.NoError(t, err)
+	require.Equal(t, "seed", seed)
+
+	_, err = readSeedShares(strings.NewReader("\n"))
+	require.EqualError(t, err, "no seed shares were given")
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	gcli "github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/transaction"
 )
 
 func sendCmd() *gcli.Command {
@@ -15,6 +17,8 @@ func sendCmd() *gcli.Command {
     If you are sending from a wallet without specifying an address,
     the transaction will use one or more of the addresses within the wallet.
 
+    The [to address] can be the name of a recipient in the address book of the wallet.
+
     Use caution when using the “-p” command. If you have command history enabled
     your wallet encryption password can be recovered from the history log.
     If you do not include the “-p” option you will be prompted to enter your password
@@ -52,12 +56,14 @@ func sendCmd() *gcli.Command {
 	sendCmd.Flags().StringP("wallet-file", "f", "", "wallet file or path. If no path is specified your default wallet path will be used.")
 	sendCmd.Flags().StringP("address", "a", "", "From address")
 	sendCmd.Flags().StringP("change-address", "c", "", `Specify different change address.
-By default the from address or a wallets coinbase address will be used.`)
+By default the from address or a wallets coinbase address will be used,
+or a new change address if the wallet's change address policy is fresh.`)
 	sendCmd.Flags().StringP("many", "m", "", `use JSON string to set multiple receive addresses and coins,
 example: -m '[{"addr":"$addr1", "coins": "10.2"}, {"addr":"$addr2", "coins": "20"}]'`)
 	sendCmd.Flags().StringP("password", "p", "", "Wallet password")
 	sendCmd.Flags().BoolP("json", "j", false, "Returns the results in JSON format.")
 	sendCmd.Flags().String("csv", "", "CSV file containing addresses and amounts to send")
+	sendCmd.Flags().String("coin-selection", transaction.CoinSelectionMinimizeUxOuts, coinSelectionUsage)
 
 	return sendCmd
 }
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func timeLockAddressCmd() *cobra.Command {
+	return &cobra.Command{
+		Short: "Create a time-locked address owned by an address",
+		Use:   "timeLockAddress [owner address] [height|time] [value]",
+		Long: `Creates a time-locked address, whose outputs can't be spent before the block
+    height or unix time "value". Once unlocked, the outputs are spent with the
+    signatures of the owner address, which can be a standard or a multisig address.
+    Node access is not required.`,
+		Args:                  cobra.ExactArgs(3),
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			owner, err := cipher.DecodeBase58Address(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid owner address: %v", err)
+			}
+
+			kind, err := wallet.ParseTimeLockKind(args[1])
+			if err != nil {
+				return err
+			}
+
+			value, err := strconv.ParseUint(args[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid time-lock value: %v", err)
+			}
+
+			addr, err := cipher.TimeLockedAddress(owner, kind, value)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(addr.String())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,300 @@
+package coin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// TransactionTypeStandard is the transaction type with exactly one signature per input
+	TransactionTypeStandard uint8 = 0
+	// TransactionTypeMultisig is the transaction type that may spend multisignature and
+	// time-locked addresses. Inputs owned by these addresses are authorized by a Witness
+	// instead of a single signature.
+	TransactionTypeMultisig uint8 = 1
+)
+
+const (
+	sigSize    = len(cipher.Sig{})
+	pubKeySize = len(cipher.PubKey{})
+)
+
+var (
+	// ErrMultisigWitnessTruncated the signatures of a multisig transaction end in the middle of a witness
+	ErrMultisigWitnessTruncated = errors.New("Multisig witness truncated")
+	// ErrMultisigWitnessPadding the padding of a multisig witness is not zero
+	ErrMultisigWitnessPadding = errors.New("Multisig witness padding is not zero")
+	// ErrMultisigWitnessAddress the witness redeem script does not hash to the address being spent
+	ErrMultisigWitnessAddress = errors.New("Multisig witness does not match the address being spent")
+	// ErrMultisigWitnessSignatureCount the witness does not contain exactly the required number of signatures
+	ErrMultisigWitnessSignatureCount = errors.New("Multisig witness has wrong number of signatures")
+	// ErrMultisigWitnessSignatureOrder a witness signature is not made by a public key of the redeem script,
+	// or the signatures are not in the order of the public keys
+	ErrMultisigWitnessSignatureOrder = errors.New("Multisig witness signatures do not match the public keys in order")
+)
+
+// Witness authorizes the spend of an output owned by a multisig or time-locked address
+type Witness interface {
+	// Encode packs the witness into signature slots
+	Encode() ([]cipher.Sig, error)
+	// Verify checks that the witness redeems addr and that its signatures signed hash
+	Verify(addr cipher.Address, hash cipher.SHA256) error
+}
+
+/*
+MultisigWitness authorizes the spend of an output owned by an m-of-n multisignature address.
+
+In a multisig transaction, Sigs is the sequence of the authorizations of the inputs in order.
+Inputs owned by a standard address take one signature slot.
+Inputs owned by a time-locked address take the slots of a TimeLockWitness.
+Inputs owned by a multisig address take as many slots as the packed witness needs:
+- the redeem script (m, n and the n public keys, see cipher.MultisigRedeemScript)
+- the m signatures, in the same relative order as their public keys
+- zero padding to a multiple of the signature size
+
+Each signature signs the same hash as a standard input signature.
+*/
+type MultisigWitness struct {
+	Required int
+	PubKeys  []cipher.PubKey
+	Sigs     []cipher.Sig
+}
+
+// Address returns the multisig address that the witness redeems
+func (w MultisigWitness) Address() (cipher.Address, error) {
+	return cipher.MultisigAddressFromPubKeys(w.Required, w.PubKeys)
+}
+
+// Encode packs the witness into signature slots
+func (w MultisigWitness) Encode() ([]cipher.Sig, error) {
+	script, err := cipher.MultisigRedeemScript(w.Required, w.PubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w.Sigs) != w.Required {
+		return nil, ErrMultisigWitnessSignatureCount
+	}
+
+	b := script
+	for _, s := range w.Sigs {
+		b = append(b, s[:]...)
+	}
+
+	slots := make([]cipher.Sig, multisigWitnessSlots(w.Required, len(w.PubKeys)))
+	for i := range slots {
+		copy(slots[i][:], b[i*sigSize:])
+	}
+
+	return slots, nil
+}
+
+// Verify checks that the witness redeems addr and that its signatures signed hash
+func (w MultisigWitness) Verify(addr cipher.Address, hash cipher.SHA256) error {
+	a, err := w.Address()
+	if err != nil {
+		return err
+	}
+	if a != addr {
+		return ErrMultisigWitnessAddress
+	}
+
+	if len(w.Sigs) != w.Required {
+		return ErrMultisigWitnessSignatureCount
+	}
+
+	j := 0
+	for _, s := range w.Sigs {
+		pubKey, err := cipher.PubKeyFromSig(s, hash)
+		if err != nil {
+			return err
+		}
+
+		for j < len(w.PubKeys) && w.PubKeys[j] != pubKey {
+			j++
+		}
+		if j == len(w.PubKeys) {
+			return ErrMultisigWitnessSignatureOrder
+		}
+
+		if err := cipher.VerifyPubKeySignedHash(pubKey, s, hash); err != nil {
+			return err
+		}
+		j++
+	}
+
+	return nil
+}
+
+// multisigWitnessSlots returns the number of signature slots used by an m-of-n witness
+func multisigWitnessSlots(m, n int) int {
+	size := 2 + n*pubKeySize + m*sigSize
+	return (size + sigSize - 1) / sigSize
+}
+
+// decodeMultisigWitness decodes the witness at the start of slots and returns it with the number of slots it used
+func decodeMultisigWitness(slots []cipher.Sig) (MultisigWitness, int, error) {
+	if len(slots) == 0 {
+		return MultisigWitness{}, 0, ErrMultisigWitnessTruncated
+	}
+
+	m := int(slots[0][0])
+	n := int(slots[0][1])
+	if n > cipher.MaxMultisigPubKeys {
+		return MultisigWitness{}, 0, cipher.ErrMultisigTooManyPubKeys
+	}
+	if m < 1 || m > n {
+		return MultisigWitness{}, 0, cipher.ErrMultisigInvalidRequired
+	}
+
+	count := multisigWitnessSlots(m, n)
+	if len(slots) < count {
+		return MultisigWitness{}, 0, ErrMultisigWitnessTruncated
+	}
+
+	b := make([]byte, 0, count*sigSize)
+	for _, s := range slots[:count] {
+		b = append(b, s[:]...)
+	}
+
+	w := MultisigWitness{
+		Required: m,
+		PubKeys:  make([]cipher.PubKey, n),
+		Sigs:     make([]cipher.Sig, m),
+	}
+
+	offset := 2
+	for i := range w.PubKeys {
+		copy(w.PubKeys[i][:], b[offset:offset+pubKeySize])
+		offset += pubKeySize
+	}
+	for i := range w.Sigs {
+		copy(w.Sigs[i][:], b[offset:offset+sigSize])
+		offset += sigSize
+	}
+
+	for _, x := range b[offset:] {
+		if x != 0 {
+			return MultisigWitness{}, 0, ErrMultisigWitnessPadding
+		}
+	}
+
+	return w, count, nil
+}
+
+// inputAuthorizations splits the signatures of a multisig transaction by input.
+// For inputs owned by a multisig or time-locked address, the witness is returned;
+// other inputs have a single signature.
+func (txn Transaction) inputAuthorizations(uxIn UxArray) ([]cipher.Sig, map[int]Witness, error) {
+	sigs := make([]cipher.Sig, len(txn.In))
+	witnesses := make(map[int]Witness)
+
+	slot := 0
+	for i := range txn.In {
+		addr := uxIn[i].Body.Address
+		switch {
+		case addr.IsMultisig():
+			w, n, err := decodeMultisigWitness(txn.Sigs[slot:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid multisig witness for input %d: %v", i, err)
+			}
+			witnesses[i] = w
+			slot += n
+		case addr.IsTimeLocked():
+			w, n, err := decodeTimeLockWitness(txn.Sigs[slot:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("Invalid time-lock witness for input %d: %v", i, err)
+			}
+			witnesses[i] = w
+			slot += n
+		default:
+			if slot >= len(txn.Sigs) {
+				return nil, nil, errors.New("txn.In != txn.Sigs")
+			}
+			sigs[i] = txn.Sigs[slot]
+			slot++
+		}
+	}
+
+	if slot != len(txn.Sigs) {
+		return nil, nil, errors.New("Multisig transaction has extra signatures")
+	}
+
+	if len(witnesses) == 0 {
+		return nil, nil, errors.New("Multisig transaction does not spend a multisig or time-locked address")
+	}
+
+	return sigs, witnesses, nil
+}
+
+// verifyMultisigInputSignatures verifies the signatures and witnesses of a multisig transaction
+func (txn Transaction) verifyMultisigInputSignatures(uxIn UxArray) error {
+	sigs, witnesses, err := txn.inputAuthorizations(uxIn)
+	if err != nil {
+		return err
+	}
+
+	for i := range txn.In {
+		hash := cipher.AddSHA256(txn.InnerHash, txn.In[i]) // use inner hash, not outer hash
+
+		if w, ok := witnesses[i]; ok {
+			if err := w.Verify(uxIn[i].Body.Address, hash); err != nil {
+				return fmt.Errorf("Witness not valid for output being spent: %v", err)
+			}
+			continue
+		}
+
+		if sigs[i].Null() {
+			return errors.New("Unsigned input in transaction")
+		}
+
+		if err := cipher.VerifyAddressSignedHash(uxIn[i].Body.Address, sigs[i], hash); err != nil {
+			return errors.New("Signature not valid for output being spent")
+		}
+	}
+
+	return nil
+}
+
+// SetWitnesses converts a fully signed standard transaction into a multisig transaction.
+// witnesses maps the indexes of the inputs that spend multisig or time-locked addresses to their witness;
+// the signatures of those inputs in txn.Sigs are ignored.
+// The header is updated; the inner hash does not change so existing signatures stay valid.
+func (txn *Transaction) SetWitnesses(witnesses map[int]Witness) error {
+	if len(witnesses) == 0 {
+		return errors.New("No witnesses")
+	}
+	if len(txn.Sigs) != len(txn.In) {
+		return errors.New("Number of signatures does not match number of inputs")
+	}
+	for i := range witnesses {
+		if i < 0 || i >= len(txn.In) {
+			return errors.New("Witness index out of range")
+		}
+	}
+
+	var sigs []cipher.Sig
+	for i := range txn.In {
+		w, ok := witnesses[i]
+		if !ok {
+			sigs = append(sigs, txn.Sigs[i])
+			continue
+		}
+
+		slots, err := w.Encode()
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, slots...)
+	}
+
+	txn.Sigs = sigs
+	if err := txn.UpdateHeader(); err != nil {
+		return err
+	}
+	txn.Type = TransactionTypeMultisig
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package coin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+// makeMultisigTransaction creates a transaction spending an output of a 2-of-3 multisig address
+// and an output of a standard address. Only the standard input is signed.
+func makeMultisigTransaction(t *testing.T) (Transaction, UxArray, []cipher.PubKey, []cipher.SecKey) {
+	pubKeys := make([]cipher.PubKey, 3)
+	secKeys := make([]cipher.SecKey, 3)
+	for i := range pubKeys {
+		pubKeys[i], secKeys[i] = cipher.GenerateKeyPair()
+	}
+
+	addr, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+
+	msUx := UxOut{
+		Head: UxHead{
+			Time:  100,
+			BkSeq: 2,
+		},
+		Body: UxBody{
+			SrcTransaction: testutil.RandSHA256(t),
+			Address:        addr,
+			Coins:          1e6,
+			Hours:          100,
+		},
+	}
+	ux, s := makeUxOutWithSecret(t)
+	uxIn := UxArray{msUx, ux}
+
+	var txn Transaction
+	for _, ux := range uxIn {
+		require.NoError(t, txn.PushInput(ux.Hash()))
+	}
+	require.NoError(t, txn.PushOutput(makeAddress(), 2e6, 50))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+	require.NoError(t, txn.SignInput(s, 1))
+
+	return txn, uxIn, pubKeys, secKeys
+}
+
+func signMultisigInput(t *testing.T, txn Transaction, i int, secKeys ...cipher.SecKey) []cipher.Sig {
+	hash := cipher.AddSHA256(txn.InnerHash, txn.In[i])
+	sigs := make([]cipher.Sig, len(secKeys))
+	for j, s := range secKeys {
+		sigs[j] = cipher.MustSignHash(hash, s)
+	}
+	return sigs
+}
+
+func TestMultisigTransaction(t *testing.T) {
+	txn, uxIn, pubKeys, secKeys := makeMultisigTransaction(t)
+	innerHash := txn.InnerHash
+
+	// A standard transaction can't spend the multisig output
+	err := txn.VerifyInputSignatures(uxIn)
+	require.Error(t, err)
+
+	w := MultisigWitness{
+		Required: 2,
+		PubKeys:  pubKeys,
+		Sigs:     signMultisigInput(t, txn, 0, secKeys[0], secKeys[2]),
+	}
+	require.NoError(t, txn.SetWitnesses(map[int]Witness{0: w}))
+	require.Equal(t, TransactionTypeMultisig, txn.Type)
+	require.Equal(t, innerHash, txn.InnerHash)
+	require.Len(t, txn.Sigs, multisigWitnessSlots(2, 3)+1)
+
+	require.NoError(t, txn.Verify())
+	require.NoError(t, txn.VerifyInputSignatures(uxIn))
+	require.NoError(t, txn.VerifyPartialInputSignatures(uxIn))
+	require.Error(t, txn.VerifyUnsigned())
+
+	// The transaction roundtrips through its serialization
+	b, err := txn.Serialize()
+	require.NoError(t, err)
+	txn2, err := DeserializeTransaction(b)
+	require.NoError(t, err)
+	require.Equal(t, txn, txn2)
+	require.NoError(t, txn2.VerifyInputSignatures(uxIn))
+
+	// Unknown transaction types are rejected
+	txn2.Type = 2
+	require.EqualError(t, txn2.Verify(), "transaction type invalid")
+
+	// Trailing signature slots are rejected
+	txn3 := txn
+	txn3.Sigs = append(append([]cipher.Sig{}, txn.Sigs...), cipher.Sig{})
+	require.NoError(t, txn3.UpdateHeader())
+	txn3.Type = TransactionTypeMultisig
+	require.EqualError(t, txn3.VerifyInputSignatures(uxIn), "Multisig transaction has extra signatures")
+
+	// A multisig transaction must spend a multisig address
+	ux, s := makeUxOutWithSecret(t)
+	stdTxn := makeTransactionFromUxOut(t, ux, s)
+	stdTxn.Type = TransactionTypeMultisig
+	require.NoError(t, stdTxn.Verify())
+	require.EqualError(t, stdTxn.VerifyInputSignatures(UxArray{ux}), "Multisig transaction does not spend a multisig or time-locked address")
+}
+
+func TestDecodeMultisigWitness(t *testing.T) {
+	txn, _, pubKeys, secKeys := makeMultisigTransaction(t)
+
+	w := MultisigWitness{
+		Required: 2,
+		PubKeys:  pubKeys,
+		Sigs:     signMultisigInput(t, txn, 0, secKeys[0], secKeys[1]),
+	}
+	slots, err := w.Encode()
+	require.NoError(t, err)
+	require.Len(t, slots, multisigWitnessSlots(2, 3))
+
+	w2, n, err := decodeMultisigWitness(append(slots, testutil.RandSig(t)))
+	require.NoError(t, err)
+	require.Equal(t, len(slots), n)
+	require.Equal(t, w, w2)
+
+	_, _, err = decodeMultisigWitness(slots[:len(slots)-1])
+	require.Equal(t, ErrMultisigWitnessTruncated, err)
+
+	padded := append([]cipher.Sig{}, slots...)
+	padded[len(padded)-1][sigSize-1] = 1
+	_, _, err = decodeMultisigWitness(padded)
+	require.Equal(t, ErrMultisigWitnessPadding, err)
+
+	bad := append([]cipher.Sig{}, slots...)
+	bad[0][0] = 4
+	_, _, err = decodeMultisigWitness(bad)
+	require.Equal(t, cipher.ErrMultisigInvalidRequired, err)
+
+	bad[0][1] = cipher.MaxMultisigPubKeys + 1
+	_, _, err = decodeMultisigWitness(bad)
+	require.Equal(t, cipher.ErrMultisigTooManyPubKeys, err)
+
+	// Witnesses must have exactly the required number of signatures
+	w.Sigs = w.Sigs[:1]
+	_, err = w.Encode()
+	require.Equal(t, ErrMultisigWitnessSignatureCount, err)
+}
+
+func TestMultisigWitnessVerify(t *testing.T) {
+	txn, uxIn, pubKeys, secKeys := makeMultisigTransaction(t)
+	_, foreignSecKey := cipher.GenerateKeyPair()
+
+	cases := []struct {
+		name    string
+		witness MultisigWitness
+		err     error
+	}{
+		{
+			name: "all signatures",
+			witness: MultisigWitness{
+				Required: 3,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 0, secKeys...),
+			},
+			err: ErrMultisigWitnessAddress,
+		},
+		{
+			name: "too few signatures",
+			witness: MultisigWitness{
+				Required: 2,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 0, secKeys[1]),
+			},
+			err: ErrMultisigWitnessSignatureCount,
+		},
+		{
+			name: "signatures out of order",
+			witness: MultisigWitness{
+				Required: 2,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 0, secKeys[2], secKeys[0]),
+			},
+			err: ErrMultisigWitnessSignatureOrder,
+		},
+		{
+			name: "same key twice",
+			witness: MultisigWitness{
+				Required: 2,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 0, secKeys[1], secKeys[1]),
+			},
+			err: ErrMultisigWitnessSignatureOrder,
+		},
+		{
+			name: "signature of other input",
+			witness: MultisigWitness{
+				Required: 2,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 1, secKeys[0], secKeys[1]),
+			},
+			err: ErrMultisigWitnessSignatureOrder,
+		},
+		{
+			name: "foreign key",
+			witness: MultisigWitness{
+				Required: 2,
+				PubKeys:  pubKeys,
+				Sigs:     signMultisigInput(t, txn, 0, secKeys[0], foreignSecKey),
+			},
+			err: ErrMultisigWitnessSignatureOrder,
+		},
+	}
+
+	hash := cipher.AddSHA256(txn.InnerHash, txn.In[0])
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.witness.Verify(uxIn[0].Body.Address, hash)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package coin
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+var (
+	// ErrTimeLockWitnessTruncated the signatures of a multisig transaction end in the middle of a time-lock witness
+	ErrTimeLockWitnessTruncated = errors.New("Time-lock witness truncated")
+	// ErrTimeLockWitnessPadding the padding of a time-lock witness is not zero
+	ErrTimeLockWitnessPadding = errors.New("Time-lock witness padding is not zero")
+	// ErrTimeLockWitnessAddress the witness time-lock script does not hash to the address being spent
+	ErrTimeLockWitnessAddress = errors.New("Time-lock witness does not match the address being spent")
+	// ErrTimeLockWitnessOwner the witness does not have the authorization required by its owner address
+	ErrTimeLockWitnessOwner = errors.New("Time-lock witness owner authorization invalid")
+	// ErrTimeLocked an output being spent is time-locked until a later block height or time
+	ErrTimeLocked = errors.New("Transaction spends a time-locked output before its unlock height or time")
+)
+
+/*
+TimeLockWitness authorizes the spend of an output owned by a time-locked address.
+
+In a multisig transaction, the witness takes:
+- one slot with the time-lock script (see cipher.TimeLockScript), zero padded
+- the authorization of the owner address: one signature for a standard owner,
+or the slots of a MultisigWitness for a multisig owner
+
+The witness is only valid once the block height or time of the lock is reached,
+see Transaction.VerifyTimeLocks.
+*/
+type TimeLockWitness struct {
+	Kind     byte
+	Value    uint64
+	Owner    cipher.Address
+	Sig      cipher.Sig
+	Multisig *MultisigWitness
+}
+
+// Address returns the time-locked address that the witness redeems
+func (w TimeLockWitness) Address() (cipher.Address, error) {
+	return cipher.TimeLockedAddress(w.Owner, w.Kind, w.Value)
+}
+
+// Unlocked returns true if the lock has expired for a block at height seq, following a block created at time
+func (w TimeLockWitness) Unlocked(seq, time uint64) bool {
+	switch w.Kind {
+	case cipher.TimeLockHeight:
+		return seq >= w.Value
+	case cipher.TimeLockTime:
+		return time >= w.Value
+	default:
+		return false
+	}
+}
+
+// Encode packs the witness into signature slots
+func (w TimeLockWitness) Encode() ([]cipher.Sig, error) {
+	script, err := cipher.TimeLockScript(w.Owner, w.Kind, w.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]cipher.Sig, 1)
+	copy(slots[0][:], script)
+
+	if !w.Owner.IsMultisig() {
+		return append(slots, w.Sig), nil
+	}
+
+	if w.Multisig == nil {
+		return nil, ErrTimeLockWitnessOwner
+	}
+	msSlots, err := w.Multisig.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(slots, msSlots...), nil
+}
+
+// Verify checks that the witness redeems addr and that its owner authorized hash.
+// It does not check whether the lock has expired.
+func (w TimeLockWitness) Verify(addr cipher.Address, hash cipher.SHA256) error {
+	a, err := w.Address()
+	if err != nil {
+		return err
+	}
+	if a != addr {
+		return ErrTimeLockWitnessAddress
+	}
+
+	if w.Owner.IsMultisig() {
+		if w.Multisig == nil {
+			return ErrTimeLockWitnessOwner
+		}
+		return w.Multisig.Verify(w.Owner, hash)
+	}
+
+	if w.Sig.Null() {
+		return ErrTimeLockWitnessOwner
+	}
+	return cipher.VerifyAddressSignedHash(w.Owner, w.Sig, hash)
+}
+
+// decodeTimeLockWitness decodes the witness at the start of slots and returns it with the number of slots it used
+func decodeTimeLockWitness(slots []cipher.Sig) (TimeLockWitness, int, error) {
+	if len(slots) < 2 {
+		return TimeLockWitness{}, 0, ErrTimeLockWitnessTruncated
+	}
+
+	b := slots[0]
+	w := TimeLockWitness{
+		Kind:  b[0],
+		Value: binary.LittleEndian.Uint64(b[1:9]),
+		Owner: cipher.Address{
+			Version: b[9],
+		},
+	}
+	copy(w.Owner.Key[:], b[10:cipher.TimeLockScriptSize])
+
+	for _, x := range b[cipher.TimeLockScriptSize:] {
+		if x != 0 {
+			return TimeLockWitness{}, 0, ErrTimeLockWitnessPadding
+		}
+	}
+
+	// Checks the lock kind and the owner address version
+	if _, err := cipher.TimeLockScript(w.Owner, w.Kind, w.Value); err != nil {
+		return TimeLockWitness{}, 0, err
+	}
+
+	if !w.Owner.IsMultisig() {
+		w.Sig = slots[1]
+		return w, 2, nil
+	}
+
+	ms, n, err := decodeMultisigWitness(slots[1:])
+	if err != nil {
+		return TimeLockWitness{}, 0, err
+	}
+	w.Multisig = &ms
+
+	return w, n + 1, nil
+}
+
+// TimeLockOwners returns the owner addresses of the time-locked outputs spent by a multisig transaction,
+// which are only known from the witnesses of their inputs
+func (txn Transaction) TimeLockOwners(uxIn UxArray) ([]cipher.Address, error) {
+	if txn.Type != TransactionTypeMultisig {
+		return nil, nil
+	}
+
+	if len(txn.In) != len(uxIn) {
+		return nil, errors.New("txn.In != uxIn")
+	}
+
+	_, witnesses, err := txn.inputAuthorizations(uxIn)
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []cipher.Address
+	for _, w := range witnesses {
+		if tw, ok := w.(TimeLockWitness); ok {
+			owners = append(owners, tw.Owner)
+		}
+	}
+
+	return owners, nil
+}
+
+// VerifyTimeLocks checks that the time-locked outputs spent by the transaction are unlocked
+// for a block at height seq, following a block created at time.
+// Standard transactions can't spend time-locked outputs, their signatures don't verify.
+func (txn Transaction) VerifyTimeLocks(uxIn UxArray, seq, time uint64) error {
+	if txn.Type != TransactionTypeMultisig {
+		return nil
+	}
+
+	if len(txn.In) != len(uxIn) {
+		return errors.New("txn.In != uxIn")
+	}
+
+	_, witnesses, err := txn.inputAuthorizations(uxIn)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range witnesses {
+		tw, ok := w.(TimeLockWitness)
+		if !ok {
+			continue
+		}
+		if !tw.Unlocked(seq, time) {
+			return ErrTimeLocked
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package coin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+// makeTimeLockTransaction creates an unsigned transaction spending an output of a time-locked address
+func makeTimeLockTransaction(t *testing.T, owner cipher.Address, kind byte, value uint64) (Transaction, UxArray) {
+	addr, err := cipher.TimeLockedAddress(owner, kind, value)
+	require.NoError(t, err)
+
+	ux := UxOut{
+		Head: UxHead{
+			Time:  100,
+			BkSeq: 2,
+		},
+		Body: UxBody{
+			SrcTransaction: testutil.RandSHA256(t),
+			Address:        addr,
+			Coins:          1e6,
+			Hours:          100,
+		},
+	}
+
+	var txn Transaction
+	require.NoError(t, txn.PushInput(ux.Hash()))
+	require.NoError(t, txn.PushOutput(makeAddress(), 1e6, 50))
+	txn.Sigs = make([]cipher.Sig, 1)
+	require.NoError(t, txn.UpdateHeader())
+
+	return txn, UxArray{ux}
+}
+
+func TestTimeLockTransaction(t *testing.T) {
+	p, s := cipher.GenerateKeyPair()
+	owner := cipher.AddressFromPubKey(p)
+	txn, uxIn := makeTimeLockTransaction(t, owner, cipher.TimeLockHeight, 10)
+
+	// A standard transaction can't spend the time-locked output
+	txn.Sigs[0] = signMultisigInput(t, txn, 0, s)[0]
+	require.Error(t, txn.VerifyInputSignatures(uxIn))
+	require.NoError(t, txn.VerifyTimeLocks(uxIn, 1, 0))
+	owners, err := txn.TimeLockOwners(uxIn)
+	require.NoError(t, err)
+	require.Empty(t, owners)
+
+	w := TimeLockWitness{
+		Kind:  cipher.TimeLockHeight,
+		Value: 10,
+		Owner: owner,
+		Sig:   txn.Sigs[0],
+	}
+	require.NoError(t, txn.SetWitnesses(map[int]Witness{0: w}))
+	require.Equal(t, TransactionTypeMultisig, txn.Type)
+	require.Len(t, txn.Sigs, 2)
+
+	require.NoError(t, txn.Verify())
+	require.NoError(t, txn.VerifyInputSignatures(uxIn))
+
+	owners, err = txn.TimeLockOwners(uxIn)
+	require.NoError(t, err)
+	require.Equal(t, []cipher.Address{owner}, owners)
+
+	// The lock is checked separately from the signatures
+	require.Equal(t, ErrTimeLocked, txn.VerifyTimeLocks(uxIn, 9, 1e10))
+	require.NoError(t, txn.VerifyTimeLocks(uxIn, 10, 0))
+
+	// The transaction roundtrips through its serialization
+	b, err := txn.Serialize()
+	require.NoError(t, err)
+	txn2, err := DeserializeTransaction(b)
+	require.NoError(t, err)
+	require.Equal(t, txn, txn2)
+
+	hash := cipher.AddSHA256(txn.InnerHash, txn.In[0])
+	require.NoError(t, w.Verify(uxIn[0].Body.Address, hash))
+
+	// Changing the lock changes the address
+	w2 := w
+	w2.Value = 1
+	require.Equal(t, ErrTimeLockWitnessAddress, w2.Verify(uxIn[0].Body.Address, hash))
+
+	// A signature of another key is rejected
+	_, s2 := cipher.GenerateKeyPair()
+	w2 = w
+	w2.Sig = cipher.MustSignHash(hash, s2)
+	require.Error(t, w2.Verify(uxIn[0].Body.Address, hash))
+
+	// The owner must sign
+	w2.Sig = cipher.Sig{}
+	require.Equal(t, ErrTimeLockWitnessOwner, w2.Verify(uxIn[0].Body.Address, hash))
+}
+
+func TestTimeLockTransactionMultisigOwner(t *testing.T) {
+	pubKeys := make([]cipher.PubKey, 2)
+	secKeys := make([]cipher.SecKey, 2)
+	for i := range pubKeys {
+		pubKeys[i], secKeys[i] = cipher.GenerateKeyPair()
+	}
+	owner, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+
+	txn, uxIn := makeTimeLockTransaction(t, owner, cipher.TimeLockTime, 1000)
+
+	w := TimeLockWitness{
+		Kind:  cipher.TimeLockTime,
+		Value: 1000,
+		Owner: owner,
+	}
+
+	// The multisig witness of the owner is required
+	require.Equal(t, ErrTimeLockWitnessOwner, txn.SetWitnesses(map[int]Witness{0: w}))
+
+	w.Multisig = &MultisigWitness{
+		Required: 2,
+		PubKeys:  pubKeys,
+		Sigs:     signMultisigInput(t, txn, 0, secKeys...),
+	}
+	require.NoError(t, txn.SetWitnesses(map[int]Witness{0: w}))
+	require.Len(t, txn.Sigs, 1+multisigWitnessSlots(2, 2))
+
+	require.NoError(t, txn.Verify())
+	require.NoError(t, txn.VerifyInputSignatures(uxIn))
+	require.Equal(t, ErrTimeLocked, txn.VerifyTimeLocks(uxIn, 1e6, 999))
+	require.NoError(t, txn.VerifyTimeLocks(uxIn, 0, 1000))
+
+	// The owner is revealed by the witness
+	owners, err := txn.TimeLockOwners(uxIn)
+	require.NoError(t, err)
+	require.Equal(t, []cipher.Address{owner}, owners)
+}
+
+func TestDecodeTimeLockWitness(t *testing.T) {
+	owner := makeAddress()
+	w := TimeLockWitness{
+		Kind:  cipher.TimeLockHeight,
+		Value: 123456,
+		Owner: owner,
+		Sig:   testutil.RandSig(t),
+	}
+
+	slots, err := w.Encode()
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+
+	w2, n, err := decodeTimeLockWitness(append(slots, testutil.RandSig(t)))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, w, w2)
+
+	_, _, err = decodeTimeLockWitness(slots[:1])
+	require.Equal(t, ErrTimeLockWitnessTruncated, err)
+
+	bad := append([]cipher.Sig{}, slots...)
+	bad[0][sigSize-1] = 1
+	_, _, err = decodeTimeLockWitness(bad)
+	require.Equal(t, ErrTimeLockWitnessPadding, err)
+
+	bad = append([]cipher.Sig{}, slots...)
+	bad[0][0] = 3
+	_, _, err = decodeTimeLockWitness(bad)
+	require.Equal(t, cipher.ErrTimeLockInvalidKind, err)
+
+	bad = append([]cipher.Sig{}, slots...)
+	bad[0][9] = cipher.TimeLockedAddressVersion
+	_, _, err = decodeTimeLockWitness(bad)
+	require.Equal(t, cipher.ErrTimeLockInvalidOwner, err)
+}
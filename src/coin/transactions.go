@@ -96,8 +96,24 @@ func (txn *Transaction) verify(signed bool) error {
 		return errors.New("No outputs")
 	}
 
-	// Check signature index fields
-	if len(txn.Sigs) != len(txn.In) {
+	switch txn.Type {
+	case TransactionTypeStandard:
+	case TransactionTypeMultisig:
+		// Multisig witnesses can only be assembled once all of their signatures are collected
+		if !signed {
+			return errors.New("Multisig transaction cannot be unsigned")
+		}
+	default:
+		return errors.New("transaction type invalid")
+	}
+
+	// Check signature index fields.
+	// Multisig witnesses take more than one signature slot, their layout is checked
+	// against the outputs being spent by VerifyInputSignatures
+	if txn.Type == TransactionTypeStandard && len(txn.Sigs) != len(txn.In) {
+		return errors.New("Invalid number of signatures")
+	}
+	if len(txn.Sigs) < len(txn.In) {
 		return errors.New("Invalid number of signatures")
 	}
 	if len(txn.Sigs) > math.MaxUint16 {
@@ -117,10 +133,6 @@ func (txn *Transaction) verify(signed bool) error {
 		return errors.New("Duplicate spend")
 	}
 
-	if txn.Type != 0 {
-		return errors.New("transaction type invalid")
-	}
-
 	// Prevent zero coin outputs
 	// Artificial restriction to prevent spam
 	for _, txo := range txn.Out {
@@ -186,6 +198,11 @@ func (txn *Transaction) verify(signed bool) error {
 			continue
 		}
 
+		// Signature slots of multisig transactions don't map to inputs by index
+		if txn.Type == TransactionTypeMultisig {
+			continue
+		}
+
 		hash := cipher.AddSHA256(txn.InnerHash, txn.In[i])
 		if err := cipher.VerifySignatureRecoverPubKey(sig, hash); err != nil {
 			return err
@@ -206,7 +223,7 @@ func (txn Transaction) verifyInputSignaturesPrelude(uxIn UxArray) error {
 	if len(txn.In) != len(uxIn) {
 		return errors.New("txn.In != uxIn")
 	}
-	if len(txn.In) != len(txn.Sigs) {
+	if txn.Type == TransactionTypeStandard && len(txn.In) != len(txn.Sigs) {
 		return errors.New("txn.In != txn.Sigs")
 	}
 	if txn.InnerHash != txn.HashInner() {
@@ -229,6 +246,10 @@ func (txn Transaction) VerifyInputSignatures(uxIn UxArray) error {
 		return err
 	}
 
+	if txn.Type == TransactionTypeMultisig {
+		return txn.verifyMultisigInputSignatures(uxIn)
+	}
+
 	// Check signatures against unspent address
 	for i := range txn.In {
 		if txn.Sigs[i].Null() {
@@ -254,6 +275,11 @@ func (txn Transaction) VerifyPartialInputSignatures(uxIn UxArray) error {
 		return err
 	}
 
+	// Multisig transactions are always fully signed
+	if txn.Type == TransactionTypeMultisig {
+		return txn.verifyMultisigInputSignatures(uxIn)
+	}
+
 	// Check signatures against unspent address for signatures that are not null
 	for i := range txn.In {
 		if txn.Sigs[i].Null() {
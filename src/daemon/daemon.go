@@ -33,6 +33,8 @@ var (
 	ErrNetworkingDisabled = errors.New("Networking is disabled")
 	// ErrNoPeerAcceptsTxn is returned if no peer will propagate a transaction broadcasted with BroadcastUserTransaction
 	ErrNoPeerAcceptsTxn = errors.New("No peer will propagate this transaction")
+	// ErrRebroadcastInvalidTxn is recorded as a rebroadcast attempt of a transaction that is invalid in the unconfirmed pool
+	ErrRebroadcastInvalidTxn = errors.New("transaction is invalid and was not broadcast")
 
 	logger = logging.MustGetLogger("daemon")
 )
@@ -214,6 +216,14 @@ type DaemonConfig struct { // nolint: golint
 	UnconfirmedRefreshRate time.Duration
 	// How often to remove transactions that become permanently invalid from the unconfirmed pool
 	UnconfirmedRemoveInvalidRate time.Duration
+	// How often to check for transactions injected by this node that are due to be rebroadcast
+	RebroadcastRate time.Duration
+	// Delay before the first rebroadcast of a transaction injected by this node. The delay doubles after every rebroadcast
+	RebroadcastMinDelay time.Duration
+	// Maximum delay between rebroadcasts of a transaction injected by this node
+	RebroadcastMaxDelay time.Duration
+	// How long to keep the rebroadcast history of a transaction after it was confirmed or left the unconfirmed pool
+	RebroadcastRetention time.Duration
 	// Default "trusted" peers
 	DefaultConnections []string
 	// User agent (sent in introduction messages)
@@ -261,6 +271,10 @@ func NewDaemonConfig() DaemonConfig {
 		BlockCreationInterval:        10,
 		UnconfirmedRefreshRate:       time.Minute,
 		UnconfirmedRemoveInvalidRate: time.Minute,
+		RebroadcastRate:              time.Second * 10,
+		RebroadcastMinDelay:          time.Minute,
+		RebroadcastMaxDelay:          time.Hour,
+		RebroadcastRetention:         time.Hour * 24,
 		Mirror:                       rand.New(rand.NewSource(time.Now().UTC().UnixNano())).Uint32(),
 		UnconfirmedVerifyTxn:         params.UserVerifyTxn,
 		MaxOutgoingMessageLength:     256 * 1024,
@@ -308,6 +322,8 @@ type Daemon struct {
 
 	// Cache of announced transactions that are flushed to the database periodically
 	announcedTxns *announcedTxnsCache
+	// Transactions injected by this node, which are rebroadcast until they are confirmed
+	rebroadcastTxns *rebroadcastTxns
 	// Cache of connection metadata
 	connections *Connections
 	// connect, disconnect, message, error events channel
@@ -341,11 +357,12 @@ func NewDaemon(config Config, db *dbutil.DB) (*Daemon, error) {
 		pex:      pex,
 		visor:    vs,
 
-		announcedTxns: newAnnouncedTxnsCache(),
-		connections:   NewConnections(),
-		events:        make(chan interface{}, config.Pool.EventChannelSize),
-		quit:          make(chan struct{}),
-		done:          make(chan struct{}),
+		announcedTxns:   newAnnouncedTxnsCache(),
+		rebroadcastTxns: newRebroadcastTxns(config.Daemon.RebroadcastMinDelay, config.Daemon.RebroadcastMaxDelay, config.Daemon.RebroadcastRetention),
+		connections:     NewConnections(),
+		events:          make(chan interface{}, config.Pool.EventChannelSize),
+		quit:            make(chan struct{}),
+		done:            make(chan struct{}),
 	}
 
 	d.pool, err = NewPool(config.Pool, d)
@@ -356,6 +373,10 @@ func NewDaemon(config Config, db *dbutil.DB) (*Daemon, error) {
 	d.Gateway = NewGateway(config.Gateway, d)
 	d.Messages.Config.Register()
 
+	if err := d.restoreRebroadcastTxns(time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
 	return d, nil
 }
 
@@ -464,6 +485,8 @@ func (dm *Daemon) Run() error {
 	defer unconfirmedRefreshTicker.Stop()
 	unconfirmedRemoveInvalidTicker := time.NewTicker(dm.Config.UnconfirmedRemoveInvalidRate)
 	defer unconfirmedRemoveInvalidTicker.Stop()
+	rebroadcastTicker := time.NewTicker(dm.Config.RebroadcastRate)
+	defer rebroadcastTicker.Stop()
 	blocksRequestTicker := time.NewTicker(dm.Config.BlocksRequestRate)
 	defer blocksRequestTicker.Stop()
 	blocksAnnounceTicker := time.NewTicker(dm.Config.BlocksAnnounceRate)
@@ -685,6 +708,31 @@ loop:
 				logger.Infof("Remove %d txns from pool that began violating hard constraints", len(removedTxns))
 			}
 
+			// Remove transactions that stayed unconfirmed longer than the maximum age
+			expiredTxns, err := dm.visor.RemoveExpiredUnconfirmed()
+			if err != nil {
+				logger.WithError(err).Error("dm.Visor.RemoveExpiredUnconfirmed failed")
+				continue
+			}
+			if len(expiredTxns) > 0 {
+				logger.Infof("Remove %d txns from pool that expired", len(expiredTxns))
+			}
+
+			// Update the lifecycles of the submitted transactions executed in blocks,
+			// and reject those that left the pool without being confirmed
+			if err := dm.visor.UpdateTransactionLifecycles(); err != nil {
+				logger.WithError(err).Error("dm.Visor.UpdateTransactionLifecycles failed")
+			}
+
+		case <-rebroadcastTicker.C:
+			elapser.Register("rebroadcastTicker")
+			// Rebroadcast the transactions injected by this node that are not confirmed yet
+			if !dm.Config.DisableNetworking {
+				if err := dm.rebroadcastDueTxns(time.Now().UTC()); err != nil {
+					logger.WithError(err).Error("rebroadcastDueTxns failed")
+				}
+			}
+
 		case <-blocksRequestTicker.C:
 			elapser.Register("blocksRequestTicker")
 			if err := dm.requestBlocks(); err != nil {
@@ -1233,21 +1281,114 @@ func (dm *Daemon) BroadcastTransaction(txn coin.Transaction) ([]uint64, error) {
 
 // BroadcastUserTransaction broadcasts a single transaction to all peers.
 // Returns an error if no peers that would propagate the transaction could be reached.
-func (dm *Daemon) BroadcastUserTransaction(txn coin.Transaction, head *coin.SignedBlock, inputs coin.UxArray) error {
+// Returns the IDs of the connections the transaction was sent to.
+func (dm *Daemon) BroadcastUserTransaction(txn coin.Transaction, head *coin.SignedBlock, inputs coin.UxArray) ([]uint64, error) {
 	ids, err := dm.BroadcastTransaction(txn)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	accepts, err := checkBroadcastTxnRecipients(dm.connections, ids, txn, head, inputs)
 
 	if err != nil {
 		logger.WithError(err).Error("BroadcastUserTransaction")
-		return err
+		return nil, err
 	}
 
 	logger.Debugf("BroadcastUserTransaction transaction propagated by %d/%d conns", accepts, len(ids))
 
+	return ids, nil
+}
+
+// restoreRebroadcastTxns tracks again the transactions injected by this node before it restarted, which are
+// still pending in their lifecycles. They are rebroadcast on the next tick, since peers may have dropped them
+// while the node was down, and the delay to the following attempts starts again from the minimum
+func (dm *Daemon) restoreRebroadcastTxns(now time.Time) error {
+	lifecycles, err := dm.visor.GetPendingTransactionLifecycles()
+	if err != nil {
+		return err
+	}
+
+	var restored int
+	for _, l := range lifecycles {
+		switch l.State {
+		case visor.TxnLifecycleInjected, visor.TxnLifecycleBroadcast:
+		default:
+			continue
+		}
+
+		// The transaction may have been rejected and injected again, so the last injection is restored,
+		// with the peers it was broadcast to when injected
+		var injected time.Time
+		var peers int
+		for _, e := range l.Events {
+			switch e.State {
+			case visor.TxnLifecycleInjected:
+				injected = e.Time
+				peers = 0
+			case visor.TxnLifecycleBroadcast:
+				if e.Time.Equal(injected) {
+					peers = e.Peers
+				}
+			}
+		}
+
+		dm.rebroadcastTxns.restore(l.Hash, injected, peers, now)
+		restored++
+	}
+
+	if restored != 0 {
+		logger.Infof("Restored %d injected transactions for rebroadcast", restored)
+	}
+
+	return nil
+}
+
+// rebroadcastDueTxns rebroadcasts the transactions injected by this node whose next attempt is due.
+// Transactions that are no longer in the unconfirmed pool stop being rebroadcast, and invalid transactions
+// are not broadcast until they become valid again.
+func (dm *Daemon) rebroadcastDueTxns(now time.Time) error {
+	for _, h := range dm.rebroadcastTxns.due(now) {
+		utxn, err := dm.visor.GetUnconfirmedTxn(h)
+		if err != nil {
+			return err
+		}
+
+		if utxn == nil {
+			txn, err := dm.visor.GetTransaction(h)
+			if err != nil {
+				return err
+			}
+
+			status := RebroadcastStatusRemoved
+			if txn != nil {
+				switch {
+				case txn.Status.Confirmed:
+					status = RebroadcastStatusConfirmed
+				case txn.Status.Expired:
+					status = RebroadcastStatusExpired
+				}
+			}
+
+			logger.WithField("txid", h.Hex()).Debugf("Stop rebroadcasting transaction, status %s", status)
+			dm.rebroadcastTxns.finish(h, now, status)
+			continue
+		}
+
+		if utxn.IsValid != 1 {
+			dm.rebroadcastTxns.attempted(h, now, 0, ErrRebroadcastInvalidTxn)
+			continue
+		}
+
+		logger.WithField("txid", h.Hex()).Debug("Rebroadcast transaction")
+		ids, err := dm.BroadcastTransaction(utxn.Transaction)
+		dm.rebroadcastTxns.attempted(h, now, len(ids), err)
+
+		if err := dm.visor.RecordTransactionBroadcast(h, len(ids)); err != nil {
+			logger.WithError(err).Error("RecordTransactionBroadcast failed")
+		}
+	}
+
 	return nil
 }
 
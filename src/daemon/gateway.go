@@ -335,20 +335,124 @@ func (gw *Gateway) GetTransactionVerbose(txid cipher.SHA256) (*visor.Transaction
 // For transactions received over the network, use daemon.injectTransaction and check the result to
 // decide on repropagation.
 func (gw *Gateway) InjectBroadcastTransaction(txn coin.Transaction) error {
-	return gw.v.WithUpdateTx("gateway.InjectBroadcastTransaction", func(tx *dbutil.Tx) error {
+	var ids []uint64
+	if err := gw.v.WithUpdateTx("gateway.InjectBroadcastTransaction", func(tx *dbutil.Tx) error {
 		_, head, inputs, err := gw.v.InjectUserTransactionTx(tx, txn)
 		if err != nil {
 			logger.WithError(err).Error("InjectUserTransactionTx failed")
 			return err
 		}
 
-		if err := gw.d.BroadcastUserTransaction(txn, head, inputs); err != nil {
+		ids, err = gw.d.BroadcastUserTransaction(txn, head, inputs)
+		if err != nil {
 			logger.WithError(err).Error("BroadcastUserTransaction failed")
 			return err
 		}
 
+		gw.d.rebroadcastTxns.add(txn.Hash(), time.Now().UTC(), len(ids))
+
 		return nil
-	})
+	}); err != nil {
+		if err := gw.v.RecordTransactionRejected(txn.Hash(), err.Error()); err != nil {
+			logger.WithError(err).Error("RecordTransactionRejected failed")
+		}
+		return err
+	}
+
+	if err := gw.v.RecordTransactionInjected(txn.Hash(), len(ids)); err != nil {
+		logger.WithError(err).Error("RecordTransactionInjected failed")
+	}
+
+	return nil
+}
+
+// recordCreatedTransactions records the lifecycle of the signed transactions created by a wallet.
+// Failures are logged, and do not fail the creation of the transactions
+func (gw *Gateway) recordCreatedTransactions(wltID string, txns ...coin.Transaction) {
+	for _, txn := range txns {
+		if !txn.IsFullySigned() {
+			continue
+		}
+
+		if err := gw.v.RecordTransactionCreated(txn.Hash(), wltID); err != nil {
+			logger.WithError(err).Error("RecordTransactionCreated failed")
+		}
+	}
+}
+
+// GetIdempotentResponse returns the saved response of an API request made with an idempotency key for a wallet,
+// or nil if there is none within the retention period
+func (gw *Gateway) GetIdempotentResponse(wltID, key string) (*visor.IdempotentResponse, error) {
+	return gw.v.GetIdempotentResponse(wltID, key)
+}
+
+// SaveIdempotentResponse saves the response of an API request made with an idempotency key for a wallet
+func (gw *Gateway) SaveIdempotentResponse(wltID, key string, r visor.IdempotentResponse) error {
+	return gw.v.SaveIdempotentResponse(wltID, key, r)
+}
+
+// GetOutputReservations returns the unspent outputs reserved by the transactions created by a wallet
+func (gw *Gateway) GetOutputReservations(wltID string) ([]visor.OutputReservation, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.GetOutputReservations(wltID)
+}
+
+// ReleaseOutputReservations releases the reservations of a wallet for the outputs in uxOuts,
+// or all the reservations of the wallet if uxOuts is empty
+func (gw *Gateway) ReleaseOutputReservations(wltID string, uxOuts []cipher.SHA256) ([]visor.OutputReservation, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.ReleaseOutputReservations(wltID, uxOuts)
+}
+
+// GetFrozenOutputs returns the frozen outputs of a wallet
+func (gw *Gateway) GetFrozenOutputs(wltID string) ([]wallet.FrozenOutput, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.GetFrozenOutputs(wltID)
+}
+
+// FreezeOutputs freezes unspent outputs of a wallet, so that they are never chosen to spend
+func (gw *Gateway) FreezeOutputs(wltID string, uxOuts []cipher.SHA256, reason string) ([]wallet.FrozenOutput, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.FreezeOutputs(wltID, uxOuts, reason)
+}
+
+// UnfreezeOutputs unfreezes unspent outputs of a wallet
+func (gw *Gateway) UnfreezeOutputs(wltID string, uxOuts []cipher.SHA256) ([]wallet.FrozenOutput, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.UnfreezeOutputs(wltID, uxOuts)
+}
+
+// GetTransactionLifecycle returns the lifecycle of a transaction submitted through the API,
+// or nil if the transaction is not tracked
+func (gw *Gateway) GetTransactionLifecycle(txid cipher.SHA256) (*visor.TransactionLifecycle, error) {
+	return gw.v.GetTransactionLifecycle(txid)
+}
+
+// GetTransactionLifecycles returns the lifecycles of the transactions submitted through the API.
+// If wltID is not empty, only the transactions created by that wallet are returned
+func (gw *Gateway) GetTransactionLifecycles(wltID string) ([]visor.TransactionLifecycle, error) {
+	return gw.v.GetTransactionLifecycles(wltID)
+}
+
+// GetRebroadcastTransactions returns the transactions injected by this node with InjectBroadcastTransaction,
+// with the history of their broadcasts. Transactions are kept until a while after they are confirmed or leave the pool
+func (gw *Gateway) GetRebroadcastTransactions() []RebroadcastTransaction {
+	return gw.d.rebroadcastTxns.all()
 }
 
 // GetVerboseTransactionsForAddress returns transactions and their verbose input data for a given address.
@@ -387,6 +491,16 @@ func (gw *Gateway) GetAllUnconfirmedTransactionsVerbose() ([]visor.UnconfirmedTr
 	return gw.v.GetAllUnconfirmedTransactionsVerbose()
 }
 
+// GetEvictedUnconfirmedTransactions returns the transactions recently evicted from the full unconfirmed pool
+func (gw *Gateway) GetEvictedUnconfirmedTransactions() []visor.EvictedTransaction {
+	return gw.v.GetEvictedUnconfirmedTransactions()
+}
+
+// GetExpiredUnconfirmedTransactions returns the transactions that expired from the unconfirmed pool
+func (gw *Gateway) GetExpiredUnconfirmedTransactions() ([]visor.ExpiredTransaction, error) {
+	return gw.v.GetExpiredUnconfirmedTransactions()
+}
+
 // GetUnconfirmedTransactions returns addresses related unconfirmed transactions
 func (gw *Gateway) GetUnconfirmedTransactions(addrs []cipher.Address) ([]visor.UnconfirmedTransaction, error) {
 	return gw.v.GetUnconfirmedTransactions(visor.SendsToAddresses(addrs))
@@ -412,7 +526,91 @@ func (gw *Gateway) WalletCreateTransactionSigned(wltID string, password []byte,
 		return nil, nil, wallet.ErrWalletAPIDisabled
 	}
 
-	return gw.v.WalletCreateTransactionSigned(wltID, password, p, wp)
+	txn, inputs, err := gw.v.WalletCreateTransactionSigned(wltID, password, p, wp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw.recordCreatedTransactions(wltID, *txn)
+
+	return txn, inputs, nil
+}
+
+// WalletCreateBatchTransaction creates unsigned transactions paying a list of receivers with a wallet
+func (gw *Gateway) WalletCreateBatchTransaction(wltID string, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.WalletCreateBatchTransaction(wltID, p, wp)
+}
+
+// WalletCreateBatchTransactionSigned creates signed transactions paying a list of receivers with a wallet
+func (gw *Gateway) WalletCreateBatchTransactionSigned(wltID string, password []byte, p transaction.Params, wp visor.CreateTransactionParams) (*visor.Batch, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	b, err := gw.v.WalletCreateBatchTransactionSigned(wltID, password, p, wp)
+	if err != nil {
+		return nil, err
+	}
+
+	gw.recordCreatedTransactions(wltID, b.Transactions...)
+
+	return b, nil
+}
+
+// WalletCreateConsolidation creates unsigned transactions that merge the unspent outputs of a wallet into one address
+func (gw *Gateway) WalletCreateConsolidation(wltID string, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.WalletCreateConsolidation(wltID, p, wp)
+}
+
+// WalletCreateConsolidationSigned creates signed transactions that merge the unspent outputs of a wallet into one address
+func (gw *Gateway) WalletCreateConsolidationSigned(wltID string, password []byte, p transaction.ConsolidateParams, wp visor.CreateTransactionParams) (*visor.Consolidation, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	c, err := gw.v.WalletCreateConsolidationSigned(wltID, password, p, wp)
+	if err != nil {
+		return nil, err
+	}
+
+	gw.recordCreatedTransactions(wltID, c.Transactions...)
+
+	return c, nil
+}
+
+// WalletBumpTransaction creates an unsigned transaction that replaces an unconfirmed transaction of a wallet
+// with a transaction that burns more coin hours
+func (gw *Gateway) WalletBumpTransaction(wltID string, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.WalletBumpTransaction(wltID, txid, fee)
+}
+
+// WalletBumpTransactionSigned creates a signed transaction that replaces an unconfirmed transaction of a wallet
+// with a transaction that burns more coin hours
+func (gw *Gateway) WalletBumpTransactionSigned(wltID string, password []byte, txid cipher.SHA256, fee uint64) (*coin.Transaction, []visor.TransactionInput, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, nil, wallet.ErrWalletAPIDisabled
+	}
+
+	txn, inputs, err := gw.v.WalletBumpTransactionSigned(wltID, password, txid, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw.recordCreatedTransactions(wltID, *txn)
+
+	return txn, inputs, nil
 }
 
 // WalletSignTransaction signs an unsigned transaction using a wallet.
@@ -423,7 +621,24 @@ func (gw *Gateway) WalletSignTransaction(wltName string, password []byte, txn *c
 		return nil, nil, wallet.ErrWalletAPIDisabled
 	}
 
-	return gw.v.WalletSignTransaction(wltName, password, txn, signIndexes)
+	signedTxn, inputs, err := gw.v.WalletSignTransaction(wltName, password, txn, signIndexes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw.recordCreatedTransactions(wltName, *signedTxn)
+
+	return signedTxn, inputs, nil
+}
+
+// WalletSignPartiallySignedTransaction signs the inputs of a partially signed transaction owned by a wallet.
+// If signIndexes is empty, all unsigned inputs owned by the wallet will be signed.
+func (gw *Gateway) WalletSignPartiallySignedTransaction(wltName string, password []byte, pst *wallet.PartiallySignedTransaction, signIndexes []int) (*wallet.PartiallySignedTransaction, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.SignPartiallySignedTransaction(wltName, password, pst, signIndexes)
 }
 
 // CreateWallet creates wallet
@@ -435,13 +650,13 @@ func (gw *Gateway) CreateWallet(wltName string, options wallet.Options) (*wallet
 	return gw.v.Wallets.CreateWallet(wltName, options, gw.v)
 }
 
-// RecoverWallet recovers an encrypted wallet from seed
-func (gw *Gateway) RecoverWallet(wltName, seed string, password []byte) (*wallet.Wallet, error) {
+// RecoverWallet recovers an encrypted wallet from seed and its optional bip39 passphrase
+func (gw *Gateway) RecoverWallet(wltName, seed, seedPassphrase string, password []byte) (*wallet.Wallet, error) {
 	if !gw.Config.EnableWalletAPI {
 		return nil, wallet.ErrWalletAPIDisabled
 	}
 
-	return gw.v.Wallets.RecoverWallet(wltName, seed, password)
+	return gw.v.Wallets.RecoverWallet(wltName, seed, seedPassphrase, password)
 }
 
 // EncryptWallet encrypts the wallet
@@ -462,6 +677,33 @@ func (gw *Gateway) DecryptWallet(wltID string, password []byte) (*wallet.Wallet,
 	return gw.v.Wallets.DecryptWallet(wltID, password)
 }
 
+// ChangeWalletPassword re-encrypts the secrets of wallet with a new password and crypto type
+func (gw *Gateway) ChangeWalletPassword(wltID string, password, newPassword []byte, cryptoType wallet.CryptoType) (*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.ChangePassword(wltID, password, newPassword, cryptoType)
+}
+
+// ExportWallets packages wallets into a single encrypted backup
+func (gw *Gateway) ExportWallets(wltIDs []string, password []byte) ([]byte, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.ExportWallets(wltIDs, password)
+}
+
+// ImportWallets restores the wallets of an encrypted backup
+func (gw *Gateway) ImportWallets(backup, password []byte) ([]*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.ImportWallets(backup, password)
+}
+
 // GetWalletBalance returns balance pairs of specific wallet
 func (gw *Gateway) GetWalletBalance(wltID string) (wallet.BalancePair, wallet.AddressBalances, error) {
 	if !gw.Config.EnableWalletAPI {
@@ -502,6 +744,42 @@ func (gw *Gateway) UpdateWalletLabel(wltID, label string) error {
 	return gw.v.Wallets.UpdateWalletLabel(wltID, label)
 }
 
+// UpdateWalletChangeAddressPolicy updates the change address policy of wallet
+func (gw *Gateway) UpdateWalletChangeAddressPolicy(wltID, policy string) (*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.UpdateWalletChangeAddressPolicy(wltID, policy)
+}
+
+// UpdateWalletAddressLabel sets the label of an address of wallet
+func (gw *Gateway) UpdateWalletAddressLabel(wltID, addr, label string) (*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.UpdateWalletAddressLabel(wltID, addr, label)
+}
+
+// AddWalletAddressBookEntry adds an entry to the address book of wallet
+func (gw *Gateway) AddWalletAddressBookEntry(wltID, name, addr, note string) (*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.AddWalletAddressBookEntry(wltID, name, addr, note)
+}
+
+// RemoveWalletAddressBookEntry removes an entry from the address book of wallet
+func (gw *Gateway) RemoveWalletAddressBookEntry(wltID, name string) (*wallet.Wallet, error) {
+	if !gw.Config.EnableWalletAPI {
+		return nil, wallet.ErrWalletAPIDisabled
+	}
+
+	return gw.v.Wallets.RemoveWalletAddressBookEntry(wltID, name)
+}
+
 // GetWallet returns wallet by id
 func (gw *Gateway) GetWallet(wltID string) (*wallet.Wallet, error) {
 	if !gw.Config.EnableWalletAPI {
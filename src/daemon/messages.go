@@ -17,6 +17,7 @@ import (
 	"github.com/skycoin/skycoin/src/params"
 	"github.com/skycoin/skycoin/src/util/iputil"
 	"github.com/skycoin/skycoin/src/util/useragent"
+	"github.com/skycoin/skycoin/src/visor"
 )
 
 // Message represent a packet to be serialized over the network by
@@ -1343,8 +1344,13 @@ func (gtm *GiveTxnsMessage) process(d daemoner) {
 		// Only announce transactions that are new to us, so that peers can't spam relays
 		// It is not necessary to inject all of the transactions inside a database transaction,
 		// since each is independent
+		// Transactions that were replaced by a transaction burning more coin hours are not relayed,
+		// while replacements are new to us and are announced like any other new transaction
 		known, softErr, err := d.injectTransaction(txn)
-		if err != nil {
+		if err == visor.ErrTxnReplaced {
+			logger.WithField("txid", txn.Hash().Hex()).Debug("Replaced transaction")
+			continue
+		} else if err != nil {
 			logger.WithError(err).WithField("txid", txn.Hash().Hex()).Warning("Failed to record transaction")
 			continue
 		} else if softErr != nil {
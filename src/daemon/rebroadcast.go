@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+const (
+	// RebroadcastStatusPending is the status of a transaction that is still rebroadcast
+	RebroadcastStatusPending = "pending"
+	// RebroadcastStatusConfirmed is the status of a transaction that was confirmed
+	RebroadcastStatusConfirmed = "confirmed"
+	// RebroadcastStatusExpired is the status of a transaction that expired from the unconfirmed pool
+	RebroadcastStatusExpired = "expired"
+	// RebroadcastStatusRemoved is the status of a transaction that was removed from the unconfirmed pool
+	// without being confirmed, because it became invalid, was evicted or was replaced
+	RebroadcastStatusRemoved = "removed"
+)
+
+// RebroadcastAttempt is an attempt to broadcast a transaction injected by this node
+type RebroadcastAttempt struct {
+	Time time.Time
+	// Number of connections the transaction was sent to
+	Peers int
+	// Error of the attempt, if it failed
+	Error string
+}
+
+// RebroadcastTransaction is a transaction injected by this node, which is rebroadcast until it is confirmed or leaves the unconfirmed pool
+type RebroadcastTransaction struct {
+	Hash     cipher.SHA256
+	Injected time.Time
+	Status   string
+	// Time the status was last changed from pending
+	Finished time.Time
+	// Time of the next attempt, if pending
+	NextAttempt time.Time
+	Attempts    []RebroadcastAttempt
+}
+
+// rebroadcastTxns tracks the transactions injected by this node, and schedules their rebroadcast with exponential backoff.
+// The delay before the first rebroadcast is minDelay, and it doubles after every attempt, up to maxDelay.
+// Finished transactions are kept for retention, so that their attempt history is visible.
+type rebroadcastTxns struct {
+	sync.Mutex
+	txns      map[cipher.SHA256]*rebroadcastTxn
+	minDelay  time.Duration
+	maxDelay  time.Duration
+	retention time.Duration
+}
+
+type rebroadcastTxn struct {
+	RebroadcastTransaction
+	delay time.Duration
+}
+
+func newRebroadcastTxns(minDelay, maxDelay, retention time.Duration) *rebroadcastTxns {
+	return &rebroadcastTxns{
+		txns:      make(map[cipher.SHA256]*rebroadcastTxn),
+		minDelay:  minDelay,
+		maxDelay:  maxDelay,
+		retention: retention,
+	}
+}
+
+// add starts tracking a transaction broadcast at now to peers connections
+func (r *rebroadcastTxns) add(hash cipher.SHA256, now time.Time, peers int) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.txns[hash] = &rebroadcastTxn{
+		RebroadcastTransaction: RebroadcastTransaction{
+			Hash:        hash,
+			Injected:    now,
+			Status:      RebroadcastStatusPending,
+			NextAttempt: now.Add(r.minDelay),
+			Attempts: []RebroadcastAttempt{
+				{
+					Time:  now,
+					Peers: peers,
+				},
+			},
+		},
+		delay: r.minDelay,
+	}
+}
+
+// restore tracks again a pending transaction injected at injected and broadcast to peers connections,
+// whose rebroadcast history was lost when the node restarted. Its next attempt is due at now
+func (r *rebroadcastTxns) restore(hash cipher.SHA256, injected time.Time, peers int, now time.Time) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.txns[hash]; ok {
+		return
+	}
+
+	r.txns[hash] = &rebroadcastTxn{
+		RebroadcastTransaction: RebroadcastTransaction{
+			Hash:        hash,
+			Injected:    injected,
+			Status:      RebroadcastStatusPending,
+			NextAttempt: now,
+			Attempts: []RebroadcastAttempt{
+				{
+					Time:  injected,
+					Peers: peers,
+				},
+			},
+		},
+		delay: r.minDelay,
+	}
+}
+
+// due returns the hashes of the pending transactions whose next attempt is due at now, and removes
+// the finished transactions older than the retention period
+func (r *rebroadcastTxns) due(now time.Time) []cipher.SHA256 {
+	r.Lock()
+	defer r.Unlock()
+
+	var hashes []cipher.SHA256
+	for h, txn := range r.txns {
+		switch txn.Status {
+		case RebroadcastStatusPending:
+			if !now.Before(txn.NextAttempt) {
+				hashes = append(hashes, h)
+			}
+		default:
+			if now.Sub(txn.Finished) > r.retention {
+				delete(r.txns, h)
+			}
+		}
+	}
+
+	return hashes
+}
+
+// attempted records an attempt to broadcast a pending transaction, and doubles the delay to the next attempt
+func (r *rebroadcastTxns) attempted(hash cipher.SHA256, now time.Time, peers int, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	txn, ok := r.txns[hash]
+	if !ok || txn.Status != RebroadcastStatusPending {
+		return
+	}
+
+	attempt := RebroadcastAttempt{
+		Time:  now,
+		Peers: peers,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	txn.Attempts = append(txn.Attempts, attempt)
+
+	txn.delay *= 2
+	if txn.delay > r.maxDelay {
+		txn.delay = r.maxDelay
+	}
+	txn.NextAttempt = now.Add(txn.delay)
+}
+
+// finish stops rebroadcasting a pending transaction
+func (r *rebroadcastTxns) finish(hash cipher.SHA256, now time.Time, status string) {
+	r.Lock()
+	defer r.Unlock()
+
+	txn, ok := r.txns[hash]
+	if !ok || txn.Status != RebroadcastStatusPending {
+		return
+	}
+
+	txn.Status = status
+	txn.Finished = now
+	txn.NextAttempt = time.Time{}
+}
+
+// get returns a tracked transaction, or nil if not tracked
+func (r *rebroadcastTxns) get(hash cipher.SHA256) *RebroadcastTransaction {
+	r.Lock()
+	defer r.Unlock()
+
+	txn, ok := r.txns[hash]
+	if !ok {
+		return nil
+	}
+
+	return txn.copy()
+}
+
+// all returns the tracked transactions, ordered by injection time
+func (r *rebroadcastTxns) all() []RebroadcastTransaction {
+	r.Lock()
+	defer r.Unlock()
+
+	txns := make([]RebroadcastTransaction, 0, len(r.txns))
+	for _, txn := range r.txns {
+		txns = append(txns, *txn.copy())
+	}
+
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].Injected.Before(txns[j].Injected)
+	})
+
+	return txns
+}
+
+func (txn *rebroadcastTxn) copy() *RebroadcastTransaction {
+	c := txn.RebroadcastTransaction
+	c.Attempts = append([]RebroadcastAttempt{}, txn.Attempts...)
+	return &c
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestRebroadcastTxnsBackoff(t *testing.T) {
+	now := time.Unix(1540000000, 0).UTC()
+	r := newRebroadcastTxns(time.Minute, 3*time.Minute, time.Hour)
+
+	hash := testutil.RandSHA256(t)
+	r.add(hash, now, 2)
+	require.Empty(t, r.due(now))
+	require.Empty(t, r.due(now.Add(time.Minute-time.Second)))
+	require.Equal(t, []cipher.SHA256{hash}, r.due(now.Add(time.Minute)))
+
+	// The delay doubles after each attempt, up to the max delay
+	now = now.Add(time.Minute)
+	r.attempted(hash, now, 3, nil)
+	require.Equal(t, now.Add(2*time.Minute), r.get(hash).NextAttempt)
+
+	now = now.Add(2 * time.Minute)
+	r.attempted(hash, now, 0, ErrRebroadcastInvalidTxn)
+	require.Equal(t, now.Add(3*time.Minute), r.get(hash).NextAttempt)
+
+	now = now.Add(3 * time.Minute)
+	r.attempted(hash, now, 1, nil)
+	require.Equal(t, now.Add(3*time.Minute), r.get(hash).NextAttempt)
+
+	txn := r.get(hash)
+	require.Equal(t, RebroadcastStatusPending, txn.Status)
+	require.Equal(t, []RebroadcastAttempt{
+		{Time: now.Add(-6 * time.Minute), Peers: 2},
+		{Time: now.Add(-5 * time.Minute), Peers: 3},
+		{Time: now.Add(-3 * time.Minute), Error: ErrRebroadcastInvalidTxn.Error()},
+		{Time: now, Peers: 1},
+	}, txn.Attempts)
+
+	// The returned transaction is a copy
+	txn.Attempts[0].Peers = 100
+	require.Equal(t, 2, r.get(hash).Attempts[0].Peers)
+}
+
+func TestRebroadcastTxnsFinish(t *testing.T) {
+	now := time.Unix(1540000000, 0).UTC()
+	r := newRebroadcastTxns(time.Minute, time.Hour, time.Hour)
+
+	hash := testutil.RandSHA256(t)
+	other := testutil.RandSHA256(t)
+	r.add(hash, now, 1)
+	r.add(other, now.Add(time.Second), 1)
+
+	r.finish(hash, now.Add(time.Minute), RebroadcastStatusConfirmed)
+	txn := r.get(hash)
+	require.Equal(t, RebroadcastStatusConfirmed, txn.Status)
+	require.Equal(t, now.Add(time.Minute), txn.Finished)
+	require.True(t, txn.NextAttempt.IsZero())
+
+	// Finished transactions are not attempted again, and their status does not change
+	r.attempted(hash, now.Add(time.Minute), 1, nil)
+	r.finish(hash, now.Add(time.Minute), RebroadcastStatusExpired)
+	txn = r.get(hash)
+	require.Len(t, txn.Attempts, 1)
+	require.Equal(t, RebroadcastStatusConfirmed, txn.Status)
+
+	require.Equal(t, []cipher.SHA256{other}, r.due(now.Add(time.Hour)))
+
+	all := r.all()
+	require.Len(t, all, 2)
+	require.Equal(t, hash, all[0].Hash)
+	require.Equal(t, other, all[1].Hash)
+
+	// Finished transactions are removed after the retention period
+	r.due(now.Add(time.Hour + time.Minute + time.Second))
+	require.Nil(t, r.get(hash))
+	require.NotNil(t, r.get(other))
+}
+
+func TestRebroadcastTxnsRestore(t *testing.T) {
+	now := time.Unix(1540000000, 0).UTC()
+	injected := now.Add(-time.Hour)
+	r := newRebroadcastTxns(time.Minute, time.Hour, time.Hour)
+
+	hash := testutil.RandSHA256(t)
+	r.restore(hash, injected, 2, now)
+
+	// A restored transaction is due at once, and its delay starts again from the minimum
+	require.Equal(t, []cipher.SHA256{hash}, r.due(now))
+	txn := r.get(hash)
+	require.Equal(t, RebroadcastStatusPending, txn.Status)
+	require.Equal(t, injected, txn.Injected)
+	require.Equal(t, []RebroadcastAttempt{
+		{Time: injected, Peers: 2},
+	}, txn.Attempts)
+
+	r.attempted(hash, now, 3, nil)
+	require.Equal(t, now.Add(2*time.Minute), r.get(hash).NextAttempt)
+
+	// A tracked transaction is not restored over
+	r.restore(hash, now, 0, now)
+	require.Len(t, r.get(hash).Attempts, 2)
+}
@@ -36,6 +36,16 @@ var (
 		// MaxDropletPrecision can be overriden with `USER_MAX_DECIMALS` env var
 		MaxDropletPrecision: 3,
 	}
+
+	// Consensus parameters
+
+	// MultisigActivationHeight is the first block height at which transactions spending
+	// multisig addresses are accepted. 0 disables multisig transactions
+	MultisigActivationHeight uint64 = 0
+
+	// TimeLockActivationHeight is the first block height at which transactions spending
+	// time-locked addresses are accepted. 0 disables time-locked transactions
+	TimeLockActivationHeight uint64 = 0
 )
 
 // distributionAddresses are addresses that received coins from the genesis address in the first block,
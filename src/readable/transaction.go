@@ -23,15 +23,18 @@ type TransactionStatus struct {
 	Height uint64 `json:"height"`
 	// If confirmed, the sequence of the block in which the transaction was executed
 	BlockSeq uint64 `json:"block_seq"`
+	// If the transaction expired from the unconfirmed pool without being confirmed
+	Expired bool `json:"expired,omitempty"`
 }
 
 // NewTransactionStatus creates TransactionStatus from visor.TransactionStatus
 func NewTransactionStatus(status visor.TransactionStatus) TransactionStatus {
 	return TransactionStatus{
-		Unconfirmed: !status.Confirmed,
+		Unconfirmed: !status.Confirmed && !status.Expired,
 		Confirmed:   status.Confirmed,
 		Height:      status.Height,
 		BlockSeq:    status.BlockSeq,
+		Expired:     status.Expired,
 	}
 }
 
@@ -193,6 +196,62 @@ func NewUnconfirmedTransactions(txns []visor.UnconfirmedTransaction) ([]Unconfir
 	return rut, nil
 }
 
+// EvictedTransaction represents a readable transaction evicted from the full unconfirmed pool
+type EvictedTransaction struct {
+	Transaction Transaction `json:"transaction"`
+	Received    time.Time   `json:"received"`
+	Evicted     time.Time   `json:"evicted"`
+	Fee         uint64      `json:"fee"`
+}
+
+// NewEvictedTransactions converts []visor.EvictedTransaction to []EvictedTransaction
+func NewEvictedTransactions(txns []visor.EvictedTransaction) ([]EvictedTransaction, error) {
+	ret := make([]EvictedTransaction, len(txns))
+	for i, e := range txns {
+		isGenesis := false // unconfirmed transactions are never the genesis transaction
+		txn, err := NewTransaction(e.Transaction, isGenesis)
+		if err != nil {
+			return nil, err
+		}
+
+		ret[i] = EvictedTransaction{
+			Transaction: *txn,
+			Received:    timeutil.NanoToTime(e.Received),
+			Evicted:     timeutil.NanoToTime(e.Evicted),
+			Fee:         e.Fee,
+		}
+	}
+	return ret, nil
+}
+
+// ExpiredTransaction represents a readable transaction expired from the unconfirmed pool
+type ExpiredTransaction struct {
+	Transaction Transaction `json:"transaction"`
+	Received    time.Time   `json:"received"`
+	Announced   time.Time   `json:"announced"`
+	Expired     time.Time   `json:"expired"`
+}
+
+// NewExpiredTransactions converts []visor.ExpiredTransaction to []ExpiredTransaction
+func NewExpiredTransactions(txns []visor.ExpiredTransaction) ([]ExpiredTransaction, error) {
+	ret := make([]ExpiredTransaction, len(txns))
+	for i, e := range txns {
+		isGenesis := false // unconfirmed transactions are never the genesis transaction
+		txn, err := NewTransaction(e.Transaction, isGenesis)
+		if err != nil {
+			return nil, err
+		}
+
+		ret[i] = ExpiredTransaction{
+			Transaction: *txn,
+			Received:    timeutil.NanoToTime(e.Received),
+			Announced:   timeutil.NanoToTime(e.Announced),
+			Expired:     timeutil.NanoToTime(e.Expired),
+		}
+	}
+	return ret, nil
+}
+
 // TransactionWithStatus represents transaction result
 type TransactionWithStatus struct {
 	Status      TransactionStatus `json:"status"`
@@ -44,18 +44,49 @@ func NewAddressBalances(wab wallet.AddressBalances) AddressBalances {
 
 // WalletEntry the wallet entry struct
 type WalletEntry struct {
+	Address     string  `json:"address"`
+	Public      string  `json:"public_key"`
+	ChildNumber *uint32 `json:"child_number,omitempty"` // For bip44 wallets
+	Change      *uint32 `json:"change,omitempty"`       // For bip44 wallets, and change addresses of deterministic wallets
+	Label       string  `json:"label,omitempty"`
+}
+
+// AddressBookEntry the wallet address book entry struct
+type AddressBookEntry struct {
+	Name    string `json:"name"`
 	Address string `json:"address"`
-	Public  string `json:"public_key"`
+	Note    string `json:"note,omitempty"`
+}
+
+// NewAddressBook copies from []wallet.AddressBookEntry
+func NewAddressBook(entries []wallet.AddressBookEntry) []AddressBookEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	book := make([]AddressBookEntry, len(entries))
+	for i, e := range entries {
+		book[i] = AddressBookEntry{
+			Name:    e.Name,
+			Address: e.Address.String(),
+			Note:    e.Note,
+		}
+	}
+	return book
 }
 
 // WalletMeta the wallet meta struct
 type WalletMeta struct {
-	Coin       string `json:"coin"`
-	Filename   string `json:"filename"`
-	Label      string `json:"label"`
-	Type       string `json:"type"`
-	Version    string `json:"version"`
-	CryptoType string `json:"crypto_type"`
-	Timestamp  int64  `json:"timestamp"`
-	Encrypted  bool   `json:"encrypted"`
+	Coin       string  `json:"coin"`
+	Filename   string  `json:"filename"`
+	Label      string  `json:"label"`
+	Type       string  `json:"type"`
+	Version    string  `json:"version"`
+	CryptoType string  `json:"crypto_type"`
+	Timestamp  int64   `json:"timestamp"`
+	Encrypted  bool    `json:"encrypted"`
+	Bip44Coin  *uint32 `json:"bip44_coin,omitempty"` // For bip44 wallets
+	XPub       string  `json:"xpub,omitempty"`       // For xpub wallets
+
+	ChangeAddressPolicy string `json:"change_address_policy,omitempty"`
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/skycoin/skycoin/src/util/droplet"
 	"github.com/skycoin/skycoin/src/util/file"
 	"github.com/skycoin/skycoin/src/util/useragent"
+	"github.com/skycoin/skycoin/src/visor"
 	"github.com/skycoin/skycoin/src/wallet"
 )
 
@@ -149,6 +150,20 @@ type NodeConfig struct {
 	CreateBlockVerifyTxn params.VerifyTxn
 	// Maximum total size of transactions in a block
 	MaxBlockTransactionsSize uint32
+	// Maximum number of transactions in the unconfirmed pool, 0 for no limit
+	MaxUnconfirmedTxns int
+	// Maximum total size of the transactions in the unconfirmed pool, 0 for no limit
+	MaxUnconfirmedTxnsSize uint64
+	// Maximum time a transaction stays in the unconfirmed pool since it was first received, 0 for no limit
+	MaxUnconfirmedTxnAge time.Duration
+	// Number of blocks a transaction submitted through the API must be buried under to be considered confirmed
+	TxnConfirmationDepth uint64
+	// Time the lifecycle of a confirmed or rejected transaction is kept
+	TxnLifecycleRetention time.Duration
+	// Time the response of an API request made with an idempotency key is kept
+	IdempotencyKeyRetention time.Duration
+	// Time the outputs spent by a transaction created with a reservation stay reserved
+	OutputReservationTTL time.Duration
 
 	unconfirmedBurnFactor          uint64
 	maxUnconfirmedTransactionSize  uint64
@@ -281,6 +296,13 @@ func NewNodeConfig(mode string, node NodeParameters) NodeConfig {
 		UnconfirmedVerifyTxn:     params.UserVerifyTxn,
 		CreateBlockVerifyTxn:     params.UserVerifyTxn,
 		MaxBlockTransactionsSize: params.UserVerifyTxn.MaxTransactionSize,
+		MaxUnconfirmedTxns:       visor.DefaultMaxUnconfirmedTxns,
+		MaxUnconfirmedTxnsSize:   visor.DefaultMaxUnconfirmedTxnsSize,
+		MaxUnconfirmedTxnAge:     visor.DefaultMaxUnconfirmedTxnAge,
+		TxnConfirmationDepth:     visor.DefaultTxnConfirmationDepth,
+		TxnLifecycleRetention:    visor.DefaultTxnLifecycleRetention,
+		IdempotencyKeyRetention:  visor.DefaultIdempotencyKeyRetention,
+		OutputReservationTTL:     visor.DefaultOutputReservationTTL,
 
 		// Wallets
 		WalletDirectory:  "",
@@ -480,6 +502,29 @@ func (c *Config) postProcess() error {
 		return errors.New("-max-block-size must be >= -max-txn-size-create-block")
 	}
 
+	if c.Node.MaxUnconfirmedTxns < 0 {
+		return errors.New("-max-unconfirmed-txns must be >= 0")
+	}
+	if c.Node.MaxUnconfirmedTxnsSize != 0 && c.Node.MaxUnconfirmedTxnsSize < uint64(c.Node.UnconfirmedVerifyTxn.MaxTransactionSize) {
+		return errors.New("-max-unconfirmed-size must be 0 or >= -max-txn-size-unconfirmed")
+	}
+	if c.Node.MaxUnconfirmedTxnAge < 0 {
+		return errors.New("-max-unconfirmed-age must be >= 0")
+	}
+	if c.Node.TxnConfirmationDepth == 0 {
+		return errors.New("-txn-confirmation-depth must be > 0")
+	}
+	if c.Node.TxnLifecycleRetention <= 0 {
+		return errors.New("-txn-lifecycle-retention must be > 0")
+	}
+
+	if c.Node.IdempotencyKeyRetention <= 0 {
+		return errors.New("-idempotency-key-retention must be > 0")
+	}
+	if c.Node.OutputReservationTTL <= 0 {
+		return errors.New("-output-reservation-ttl must be > 0")
+	}
+
 	if c.Node.UnconfirmedVerifyTxn.BurnFactor < params.MinBurnFactor {
 		return fmt.Errorf("-burn-factor-unconfirmed must be >= params.MinBurnFactor (%d)", params.MinBurnFactor)
 	}
@@ -649,6 +694,13 @@ func (c *NodeConfig) RegisterFlags() {
 	flag.Uint64Var(&c.createBlockMaxTransactionSize, "max-txn-size-create-block", uint64(c.CreateBlockVerifyTxn.MaxTransactionSize), "maximum size of a transaction applied when creating blocks")
 	flag.Uint64Var(&c.createBlockMaxDropletPrecision, "max-decimals-create-block", uint64(c.CreateBlockVerifyTxn.MaxDropletPrecision), "max number of decimal places applied when creating blocks")
 	flag.Uint64Var(&c.maxBlockSize, "max-block-size", uint64(c.MaxBlockTransactionsSize), "maximum total size of transactions in a block")
+	flag.IntVar(&c.MaxUnconfirmedTxns, "max-unconfirmed-txns", c.MaxUnconfirmedTxns, "maximum number of transactions in the unconfirmed pool, 0 for no limit. When full, the transactions with the lowest fee per byte are evicted")
+	flag.Uint64Var(&c.MaxUnconfirmedTxnsSize, "max-unconfirmed-size", c.MaxUnconfirmedTxnsSize, "maximum total size of the transactions in the unconfirmed pool, 0 for no limit. When full, the transactions with the lowest fee per byte are evicted")
+	flag.DurationVar(&c.MaxUnconfirmedTxnAge, "max-unconfirmed-age", c.MaxUnconfirmedTxnAge, "maximum time a transaction stays in the unconfirmed pool since it was first received, 0 for no limit. Older transactions expire and are removed")
+	flag.Uint64Var(&c.TxnConfirmationDepth, "txn-confirmation-depth", c.TxnConfirmationDepth, "number of blocks, including its own, a transaction submitted through the API must be buried under to be reported as confirmed by its lifecycle")
+	flag.DurationVar(&c.TxnLifecycleRetention, "txn-lifecycle-retention", c.TxnLifecycleRetention, "time the lifecycle of a confirmed or rejected transaction submitted through the API is kept")
+	flag.DurationVar(&c.IdempotencyKeyRetention, "idempotency-key-retention", c.IdempotencyKeyRetention, "time the response of a /wallet/transaction or /injectTransaction request made with an Idempotency-Key header is returned to retries with the same key")
+	flag.DurationVar(&c.OutputReservationTTL, "output-reservation-ttl", c.OutputReservationTTL, "time the outputs spent by a /wallet/transaction request with the reserve option are not chosen by other transactions, unless the transaction is injected or the reservation is released")
 
 	flag.BoolVar(&c.RunBlockPublisher, "block-publisher", c.RunBlockPublisher, "run the daemon as a block publisher")
 	flag.StringVar(&c.BlockchainPubkeyStr, "blockchain-public-key", c.BlockchainPubkeyStr, "public key of the blockchain")
@@ -668,7 +720,7 @@ func (c *NodeConfig) RegisterFlags() {
 	flag.IntVar(&c.MaxIncomingMessageLength, "max-in-msg-len", c.MaxIncomingMessageLength, "Maximum length of incoming wire messages")
 	flag.BoolVar(&c.LocalhostOnly, "localhost-only", c.LocalhostOnly, "Run on localhost and only connect to localhost peers")
 	flag.BoolVar(&c.Arbitrating, "arbitrating", c.Arbitrating, "Run node in arbitrating mode")
-	flag.StringVar(&c.WalletCryptoType, "wallet-crypto-type", c.WalletCryptoType, "wallet crypto type. Can be sha256-xor or scrypt-chacha20poly1305")
+	flag.StringVar(&c.WalletCryptoType, "wallet-crypto-type", c.WalletCryptoType, "wallet crypto type. Can be sha256-xor, scrypt-chacha20poly1305 or argon2id-xchacha20poly1305")
 	flag.BoolVar(&c.Version, "version", false, "show node version")
 }
 
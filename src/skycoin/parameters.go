@@ -83,6 +83,12 @@ type ParamsParameters struct {
 	DistributionAddresses []string `mapstructure:"distribution_addresses"`
 	// UserBurnFactor inverse fraction of coinhours that must be burned, this value is used when creating transactions
 	UserBurnFactor uint64 `mapstructure:"user_burn_factor"`
+	// MultisigActivationHeight is the first block height at which multisig transactions are accepted.
+	// 0 disables multisig transactions
+	MultisigActivationHeight uint64 `mapstructure:"multisig_activation_height"`
+	// TimeLockActivationHeight is the first block height at which time-locked transactions are accepted.
+	// 0 disables time-locked transactions
+	TimeLockActivationHeight uint64 `mapstructure:"time_lock_activation_height"`
 }
 
 // NewParameters loads blockchain config parameters from a config file
@@ -148,4 +154,6 @@ func setDefaults() {
 	viper.SetDefault("params.user_max_decimals", 3)
 	viper.SetDefault("params.user_burn_factor", 2)
 	viper.SetDefault("params.user_max_transaction_size", 32*1024)
+	viper.SetDefault("params.multisig_activation_height", 0)
+	viper.SetDefault("params.time_lock_activation_height", 0)
 }
@@ -391,6 +391,13 @@ func (c *Coin) ConfigureDaemon() daemon.Config {
 	dc.Visor.UnconfirmedVerifyTxn = c.config.Node.UnconfirmedVerifyTxn
 	dc.Visor.CreateBlockVerifyTxn = c.config.Node.CreateBlockVerifyTxn
 	dc.Visor.MaxBlockTransactionsSize = c.config.Node.MaxBlockTransactionsSize
+	dc.Visor.MaxUnconfirmedTxns = c.config.Node.MaxUnconfirmedTxns
+	dc.Visor.MaxUnconfirmedTxnsSize = c.config.Node.MaxUnconfirmedTxnsSize
+	dc.Visor.MaxUnconfirmedTxnAge = c.config.Node.MaxUnconfirmedTxnAge
+	dc.Visor.TxnConfirmationDepth = c.config.Node.TxnConfirmationDepth
+	dc.Visor.TxnLifecycleRetention = c.config.Node.TxnLifecycleRetention
+	dc.Visor.IdempotencyKeyRetention = c.config.Node.IdempotencyKeyRetention
+	dc.Visor.OutputReservationTTL = c.config.Node.OutputReservationTTL
 
 	dc.Visor.GenesisAddress = c.config.Node.genesisAddress
 	dc.Visor.GenesisSignature = c.config.Node.genesisSignature
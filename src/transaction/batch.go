@@ -0,0 +1,143 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+)
+
+// Batch is a series of unsigned transactions that pay the receivers of Params.To
+type Batch struct {
+	Transactions []coin.Transaction
+	// Inputs are the inputs of each transaction
+	Inputs [][]UxBalance
+	// Receivers are the indexes in Transactions of the transaction paying each receiver of Params.To.
+	// The receivers paid by a transaction are its first outputs, in the order of Params.To
+	Receivers []int
+}
+
+// CreateBatch creates a series of transactions paying the receivers of p.To, each fitting within params.UserVerifyTxn.
+// The receivers are paid in order, with as many receivers in each transaction as fit, so that
+// the number of transactions is minimized. Each transaction is created with Create, and
+// spends distinct outputs from auxs, so they can be broadcast together.
+// The change outputs of a transaction are not spent by the following transactions,
+// so auxs must contain enough separate outputs to fund every transaction.
+func CreateBatch(p Params, auxs coin.AddressUxOuts, headTime uint64) (*Batch, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i, to := range p.To {
+		if err := params.DropletPrecisionCheck(params.UserVerifyTxn.MaxDropletPrecision, to.Coins); err != nil {
+			return nil, NewError(fmt.Errorf("To[%d].Coins: %v", i, err))
+		}
+	}
+
+	maxSize := params.UserVerifyTxn.MaxTransactionSize
+	maxReceivers, outputSize, err := batchMaxReceivers(maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Batch{
+		Receivers: make([]int, 0, len(p.To)),
+	}
+
+	for len(b.Receivers) < len(p.To) {
+		start := len(b.Receivers)
+		n := len(p.To) - start
+		if n > maxReceivers {
+			n = maxReceivers
+		}
+
+		var txn *coin.Transaction
+		var inputs []UxBalance
+		for {
+			bp := p
+			bp.To = p.To[start : start+n]
+
+			txn, inputs, err = Create(bp, auxs, headTime)
+			if err != nil {
+				return nil, err
+			}
+
+			if txn.Length <= maxSize {
+				break
+			}
+
+			// Remove enough receivers to make up the excess size, which may also reduce the number of inputs
+			excess := int((txn.Length - maxSize + outputSize - 1) / outputSize)
+			n -= excess
+			if n < 1 {
+				return nil, NewError(fmt.Errorf("the transaction paying To[%d] exceeds the max transaction size, consolidate the unspent outputs first", start))
+			}
+		}
+
+		for i := 0; i < n; i++ {
+			b.Receivers = append(b.Receivers, len(b.Transactions))
+		}
+		b.Transactions = append(b.Transactions, *txn)
+		b.Inputs = append(b.Inputs, inputs)
+
+		auxs = subtractUxBalances(auxs, inputs)
+	}
+
+	return b, nil
+}
+
+// batchMaxReceivers returns the maximum number of receivers of a transaction with a single input and a change output
+// whose size does not exceed maxSize, and the size of an output
+func batchMaxReceivers(maxSize uint32) (int, uint32, error) {
+	txn := coin.Transaction{
+		In:   []cipher.SHA256{{}},
+		Sigs: []cipher.Sig{{}},
+		Out:  []coin.TransactionOutput{{}},
+	}
+
+	baseSize, err := txn.Size()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	txn.Out = append(txn.Out, coin.TransactionOutput{})
+
+	size, err := txn.Size()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	outputSize := size - baseSize
+
+	if maxSize < size {
+		return 0, 0, NewError(errors.New("max transaction size is too small for a batch transaction"))
+	}
+
+	return int((maxSize - baseSize) / outputSize), outputSize, nil
+}
+
+// subtractUxBalances returns a copy of auxs without the unspent outputs of uxb
+func subtractUxBalances(auxs coin.AddressUxOuts, uxb []UxBalance) coin.AddressUxOuts {
+	spent := make(map[cipher.SHA256]struct{}, len(uxb))
+	for _, ux := range uxb {
+		spent[ux.Hash] = struct{}{}
+	}
+
+	out := make(coin.AddressUxOuts, len(auxs))
+	for a, uxs := range auxs {
+		var remaining coin.UxArray
+		for _, ux := range uxs {
+			if _, ok := spent[ux.Hash()]; !ok {
+				remaining = append(remaining, ux)
+			}
+		}
+
+		if len(remaining) > 0 {
+			out[a] = remaining
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,178 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestBatchMaxReceivers(t *testing.T) {
+	maxSize := params.UserVerifyTxn.MaxTransactionSize
+	n, outputSize, err := batchMaxReceivers(maxSize)
+	require.NoError(t, err)
+
+	txn := coin.Transaction{
+		In:   []cipher.SHA256{{}},
+		Sigs: []cipher.Sig{{}},
+		Out:  make([]coin.TransactionOutput, n+1),
+	}
+	size, err := txn.Size()
+	require.NoError(t, err)
+	require.True(t, size <= maxSize)
+
+	txn.Out = append(txn.Out, coin.TransactionOutput{})
+	size2, err := txn.Size()
+	require.NoError(t, err)
+	require.True(t, size2 > maxSize)
+	require.Equal(t, outputSize, size2-size)
+
+	_, _, err = batchMaxReceivers(10)
+	require.Error(t, err)
+}
+
+func TestCreateBatch(t *testing.T) {
+	headTime := uint64(1e9)
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 2)
+	changeAddress := testutil.MakeAddress()
+	shareFactor := decimal.New(5, -1)
+
+	makeUxOuts := func(s cipher.SecKey, n int, coins, hours uint64) coin.UxArray {
+		uxouts := make(coin.UxArray, n)
+		for i := range uxouts {
+			uxouts[i] = makeUxOut(t, s, coins, hours)
+			uxouts[i].Head.Time = headTime
+		}
+		return uxouts
+	}
+
+	makeReceivers := func(n int, coins uint64) []coin.TransactionOutput {
+		to := make([]coin.TransactionOutput, n)
+		for i := range to {
+			// Generating key pairs is slow, so a random key is used for the many receivers
+			to[i] = coin.TransactionOutput{
+				Address: cipher.Address{
+					Key: cipher.HashRipemd160(testutil.RandBytes(t, 32)),
+				},
+				Coins: coins,
+			}
+		}
+		return to
+	}
+
+	maxReceivers, _, err := batchMaxReceivers(params.UserVerifyTxn.MaxTransactionSize)
+	require.NoError(t, err)
+
+	t.Run("split into transactions", func(t *testing.T) {
+		uxouts := append(makeUxOuts(secKeys[0], 3, 1000e6, 1000), makeUxOuts(secKeys[1], 3, 1000e6, 1000)...)
+		auxs := coin.NewAddressUxOuts(uxouts)
+
+		to := makeReceivers(maxReceivers*2+10, 1e6)
+		p := Params{
+			HoursSelection: HoursSelection{
+				Type:        HoursSelectionTypeAuto,
+				Mode:        HoursSelectionModeShare,
+				ShareFactor: &shareFactor,
+			},
+			ChangeAddress: &changeAddress,
+			To:            to,
+		}
+
+		b, err := CreateBatch(p, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, b.Transactions, 3)
+		require.Len(t, b.Inputs, 3)
+		require.Len(t, b.Receivers, len(to))
+
+		spent := make(map[cipher.SHA256]struct{})
+		paid := 0
+		for i, txn := range b.Transactions {
+			require.NoError(t, txn.VerifyUnsigned())
+			require.True(t, txn.Length <= params.UserVerifyTxn.MaxTransactionSize)
+			require.Len(t, b.Inputs[i], len(txn.In))
+
+			for j, h := range txn.In {
+				require.Equal(t, h, b.Inputs[i][j].Hash)
+				_, ok := spent[h]
+				require.False(t, ok)
+				spent[h] = struct{}{}
+			}
+
+			// The receivers are paid in order, followed by the change output
+			for _, o := range txn.Out[:len(txn.Out)-1] {
+				require.Equal(t, i, b.Receivers[paid])
+				require.Equal(t, to[paid].Address, o.Address)
+				require.Equal(t, to[paid].Coins, o.Coins)
+				paid++
+			}
+			require.Equal(t, changeAddress, txn.Out[len(txn.Out)-1].Address)
+		}
+
+		require.Equal(t, len(to), paid)
+	})
+
+	t.Run("single transaction", func(t *testing.T) {
+		auxs := coin.NewAddressUxOuts(makeUxOuts(secKeys[0], 2, 10e6, 100))
+		to := makeReceivers(3, 2e6)
+		to[0].Hours = 10
+
+		b, err := CreateBatch(Params{
+			HoursSelection: HoursSelection{
+				Type: HoursSelectionTypeManual,
+			},
+			To: to,
+		}, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, b.Transactions, 1)
+		require.Equal(t, []int{0, 0, 0}, b.Receivers)
+		require.Equal(t, to, b.Transactions[0].Out[:3])
+	})
+
+	t.Run("insufficient separate outputs", func(t *testing.T) {
+		auxs := coin.NewAddressUxOuts(makeUxOuts(secKeys[0], 1, 1000e6, 1000))
+
+		_, err := CreateBatch(Params{
+			HoursSelection: HoursSelection{
+				Type:        HoursSelectionTypeAuto,
+				Mode:        HoursSelectionModeShare,
+				ShareFactor: &shareFactor,
+			},
+			To: makeReceivers(maxReceivers+1, 1e6),
+		}, auxs, headTime)
+		require.Equal(t, ErrNoUnspents, err)
+	})
+
+	t.Run("too many inputs", func(t *testing.T) {
+		auxs := coin.NewAddressUxOuts(makeUxOuts(secKeys[0], 400, 1e6, 10))
+
+		_, err := CreateBatch(Params{
+			HoursSelection: HoursSelection{
+				Type:        HoursSelectionTypeAuto,
+				Mode:        HoursSelectionModeShare,
+				ShareFactor: &shareFactor,
+			},
+			To: makeReceivers(1, 390e6),
+		}, auxs, headTime)
+		require.Equal(t, NewError(errors.New("the transaction paying To[0] exceeds the max transaction size, consolidate the unspent outputs first")), err)
+	})
+
+	t.Run("invalid precision", func(t *testing.T) {
+		auxs := coin.NewAddressUxOuts(makeUxOuts(secKeys[0], 1, 10e6, 100))
+		to := makeReceivers(2, 1e6)
+		to[1].Coins = 1e6 + 1
+
+		_, err := CreateBatch(Params{
+			HoursSelection: HoursSelection{
+				Type: HoursSelectionTypeManual,
+			},
+			To: to,
+		}, auxs, headTime)
+		require.Equal(t, NewError(errors.New("To[1].Coins: invalid amount, too many decimal places")), err)
+	})
+}
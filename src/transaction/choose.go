@@ -84,6 +84,18 @@ func uxBalancesSub(a, b []UxBalance) []UxBalance {
 	return x
 }
 
+// uxBalancesUnreserved returns the UxBalances of uxa whose hashes are not in reserved
+func uxBalancesUnreserved(uxa []UxBalance, reserved map[cipher.SHA256]struct{}) []UxBalance {
+	var x []UxBalance
+	for _, i := range uxa {
+		if _, ok := reserved[i.Hash]; !ok {
+			x = append(x, i)
+		}
+	}
+
+	return x
+}
+
 // ChooseSpendsMinimizeUxOuts chooses uxout spends to satisfy an amount, using the least number of uxouts
 //     -- PRO: Allows more frequent spending, less waiting for confirmations, useful for exchanges.
 //     -- PRO: When transaction is volume is higher, transactions are prioritized by fee/size. Minimizing uxouts minimizes size.
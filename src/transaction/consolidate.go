@@ -0,0 +1,242 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+)
+
+var (
+	// ErrNullConsolidationAddress To must not be the null address
+	ErrNullConsolidationAddress = NewError(errors.New("To must not be the null address"))
+	// ErrInvalidMaxInputs MaxInputs must be 0 or at least 2
+	ErrInvalidMaxInputs = NewError(errors.New("MaxInputs must be 0 or at least 2"))
+	// ErrNothingToConsolidate there are no unspent outputs that can be consolidated
+	ErrNothingToConsolidate = NewError(errors.New("no unspents to consolidate"))
+)
+
+// ConsolidateParams defines control parameters for consolidating unspent outputs
+type ConsolidateParams struct {
+	// To is the address receiving the consolidated outputs
+	To cipher.Address
+	// MaxInputs is the maximum number of inputs of each transaction.
+	// If 0, as many inputs as fit in params.UserVerifyTxn.MaxTransactionSize are used
+	MaxInputs int
+	// Reserved are the hashes of the outputs reserved by other transactions, which are not consolidated
+	Reserved map[cipher.SHA256]struct{}
+}
+
+// Validate validates ConsolidateParams
+func (p ConsolidateParams) Validate() error {
+	if p.To.Null() {
+		return ErrNullConsolidationAddress
+	}
+
+	if p.MaxInputs < 0 || p.MaxInputs == 1 {
+		return ErrInvalidMaxInputs
+	}
+
+	return nil
+}
+
+// Consolidation is a series of unsigned transactions that merge unspent outputs into one address
+type Consolidation struct {
+	// Transactions each spend a batch of unspent outputs to a single output owned by the To address
+	Transactions []coin.Transaction
+	// Inputs are the inputs of each transaction
+	Inputs [][]UxBalance
+	// Coins is the total of coins consolidated
+	Coins uint64
+	// Fee is the total of coin hours burned by the transactions
+	Fee uint64
+	// Skipped are the unspent outputs without coin hours that were left unspent,
+	// because no output with coin hours was left to pay the fee of their transaction
+	Skipped []UxBalance
+}
+
+// Consolidate plans a series of transactions that merge the unspent outputs in auxs into the address p.To.
+// Each transaction fits within params.UserVerifyTxn and spends all of its input hours, less the fee, to a single output.
+// The transactions spend distinct outputs, so they can be broadcast together.
+// Outputs are spent with the lowest balances first. Each transaction spends at least one output with coin hours
+// to pay its fee; outputs without coin hours are added to the transactions first.
+// An output already owned by p.To is not spent alone, since that would only burn its coin hours.
+// Outputs in p.Reserved are never spent.
+func Consolidate(p ConsolidateParams, auxs coin.AddressUxOuts, headTime uint64) (*Consolidation, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	maxInputs, err := ConsolidationMaxInputs(params.UserVerifyTxn.MaxTransactionSize)
+	if err != nil {
+		return nil, err
+	}
+	if p.MaxInputs != 0 && p.MaxInputs < maxInputs {
+		maxInputs = p.MaxInputs
+	}
+
+	uxb, err := NewUxBalances(auxs.Flatten(), headTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the outputs reserved by other transactions
+	if len(p.Reserved) != 0 && len(uxb) != 0 {
+		uxb = uxBalancesUnreserved(uxb, p.Reserved)
+		if len(uxb) == 0 {
+			return nil, ErrAllUxOutsReserved
+		}
+	}
+
+	// Split UxBalances into those with and without hours
+	seen := make(map[cipher.SHA256]struct{}, len(uxb))
+	var nonzero, zero []UxBalance
+	for _, ux := range uxb {
+		if _, ok := seen[ux.Hash]; ok {
+			return nil, errors.New("Duplicate UxBalance in array")
+		}
+		seen[ux.Hash] = struct{}{}
+
+		if ux.Hours == 0 {
+			zero = append(zero, ux)
+		} else {
+			nonzero = append(nonzero, ux)
+		}
+	}
+
+	sortSpendsCoinsLowToHigh(nonzero)
+	sortSpendsCoinsLowToHigh(zero)
+
+	var c Consolidation
+	for len(nonzero) > 0 {
+		batch := []UxBalance{nonzero[0]}
+		nonzero = nonzero[1:]
+
+		n := maxInputs - len(batch)
+		if n > len(zero) {
+			n = len(zero)
+		}
+		batch = append(batch, zero[:n]...)
+		zero = zero[n:]
+
+		n = maxInputs - len(batch)
+		if n > len(nonzero) {
+			n = len(nonzero)
+		}
+		batch = append(batch, nonzero[:n]...)
+		nonzero = nonzero[n:]
+
+		if len(batch) == 1 && batch[0].Address == p.To {
+			continue
+		}
+
+		txn, txnFee, err := consolidationTransaction(p.To, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Coins, err = mathutil.AddUint64(c.Coins, txn.Out[0].Coins)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Fee, err = mathutil.AddUint64(c.Fee, txnFee)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Transactions = append(c.Transactions, *txn)
+		c.Inputs = append(c.Inputs, batch)
+	}
+
+	c.Skipped = zero
+
+	if len(c.Transactions) == 0 {
+		return nil, ErrNothingToConsolidate
+	}
+
+	return &c, nil
+}
+
+// consolidationTransaction creates an unsigned transaction spending inputs to a single output owned by to.
+// Returns the transaction and its fee
+func consolidationTransaction(to cipher.Address, inputs []UxBalance) (*coin.Transaction, uint64, error) {
+	txn := &coin.Transaction{}
+
+	var coins, hours uint64
+	for _, ux := range inputs {
+		var err error
+		coins, err = mathutil.AddUint64(coins, ux.Coins)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		hours, err = mathutil.AddUint64(hours, ux.Hours)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if err := txn.PushInput(ux.Hash); err != nil {
+			logger.Critical().WithError(err).Error("PushInput failed")
+			return nil, 0, err
+		}
+	}
+
+	feeHours := fee.RequiredFee(hours, params.UserVerifyTxn.BurnFactor)
+	if feeHours == 0 {
+		err := errors.New("Consolidated inputs have no coin hours, unexpectedly")
+		logger.Critical().WithError(err).Error()
+		return nil, 0, err
+	}
+
+	if err := txn.PushOutput(to, coins, hours-feeHours); err != nil {
+		logger.Critical().WithError(err).Error("PushOutput failed")
+		return nil, 0, err
+	}
+
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+
+	if err := txn.UpdateHeader(); err != nil {
+		logger.Critical().WithError(err).Error("txn.UpdateHeader failed")
+		return nil, 0, err
+	}
+
+	if txn.Length > params.UserVerifyTxn.MaxTransactionSize {
+		err := fmt.Errorf("Consolidation transaction size %d exceeds the max transaction size, this should not occur", txn.Length)
+		logger.Critical().WithError(err).Error()
+		return nil, 0, err
+	}
+
+	return txn, feeHours, nil
+}
+
+// ConsolidationMaxInputs returns the maximum number of inputs of a transaction with a single output
+// whose size does not exceed maxSize
+func ConsolidationMaxInputs(maxSize uint32) (int, error) {
+	txn := coin.Transaction{
+		Out: []coin.TransactionOutput{{}},
+	}
+
+	baseSize, err := txn.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	txn.In = append(txn.In, cipher.SHA256{})
+	txn.Sigs = append(txn.Sigs, cipher.Sig{})
+
+	size, err := txn.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	if maxSize < size {
+		return 0, NewError(errors.New("max transaction size is too small for a consolidation transaction"))
+	}
+
+	return int((maxSize - baseSize) / (size - baseSize)), nil
+}
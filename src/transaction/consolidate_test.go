@@ -0,0 +1,231 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/fee"
+)
+
+func TestConsolidateParamsValidate(t *testing.T) {
+	to := testutil.MakeAddress()
+
+	cases := []struct {
+		name   string
+		params ConsolidateParams
+		err    error
+	}{
+		{
+			name:   "null address",
+			params: ConsolidateParams{},
+			err:    ErrNullConsolidationAddress,
+		},
+		{
+			name: "negative max inputs",
+			params: ConsolidateParams{
+				To:        to,
+				MaxInputs: -1,
+			},
+			err: ErrInvalidMaxInputs,
+		},
+		{
+			name: "one max input",
+			params: ConsolidateParams{
+				To:        to,
+				MaxInputs: 1,
+			},
+			err: ErrInvalidMaxInputs,
+		},
+		{
+			name: "valid",
+			params: ConsolidateParams{
+				To: to,
+			},
+		},
+		{
+			name: "valid max inputs",
+			params: ConsolidateParams{
+				To:        to,
+				MaxInputs: 2,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, tc.params.Validate())
+		})
+	}
+}
+
+func TestConsolidationMaxInputs(t *testing.T) {
+	maxSize := params.UserVerifyTxn.MaxTransactionSize
+	n, err := ConsolidationMaxInputs(maxSize)
+	require.NoError(t, err)
+
+	txn := coin.Transaction{
+		In:   make([]cipher.SHA256, n),
+		Sigs: make([]cipher.Sig, n),
+		Out:  []coin.TransactionOutput{{}},
+	}
+	size, err := txn.Size()
+	require.NoError(t, err)
+	require.True(t, size <= maxSize)
+
+	txn.In = append(txn.In, cipher.SHA256{})
+	txn.Sigs = append(txn.Sigs, cipher.Sig{})
+	size, err = txn.Size()
+	require.NoError(t, err)
+	require.True(t, size > maxSize)
+
+	_, err = ConsolidationMaxInputs(10)
+	require.Error(t, err)
+}
+
+func TestConsolidate(t *testing.T) {
+	headTime := uint64(1e9)
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 2)
+	to := testutil.MakeAddress()
+
+	makeUxOuts := func(s cipher.SecKey, n int, hours uint64) []coin.UxOut {
+		uxouts := make([]coin.UxOut, n)
+		for i := range uxouts {
+			uxouts[i] = makeUxOut(t, s, uint64(i+1)*1e6, hours)
+			uxouts[i].Head.Time = headTime
+		}
+		return uxouts
+	}
+
+	t.Run("batches", func(t *testing.T) {
+		uxouts := append(makeUxOuts(secKeys[0], 6, 10), makeUxOuts(secKeys[1], 4, 3)...)
+		auxs := coin.NewAddressUxOuts(uxouts)
+
+		c, err := Consolidate(ConsolidateParams{
+			To:        to,
+			MaxInputs: 4,
+		}, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, c.Transactions, 3)
+		require.Len(t, c.Inputs, 3)
+		require.Empty(t, c.Skipped)
+
+		spent := make(map[cipher.SHA256]struct{})
+		var coins, fees uint64
+		for i, txn := range c.Transactions {
+			require.NoError(t, txn.VerifyUnsigned())
+			require.True(t, len(txn.In) <= 4)
+			require.Len(t, c.Inputs[i], len(txn.In))
+			require.Len(t, txn.Out, 1)
+			require.Equal(t, to, txn.Out[0].Address)
+
+			var inCoins, inHours uint64
+			for j, h := range txn.In {
+				require.Equal(t, h, c.Inputs[i][j].Hash)
+				_, ok := spent[h]
+				require.False(t, ok)
+				spent[h] = struct{}{}
+
+				inCoins += c.Inputs[i][j].Coins
+				inHours += c.Inputs[i][j].Hours
+			}
+
+			require.Equal(t, inCoins, txn.Out[0].Coins)
+			require.Equal(t, fee.RemainingHours(inHours, params.UserVerifyTxn.BurnFactor), txn.Out[0].Hours)
+
+			coins += inCoins
+			fees += inHours - txn.Out[0].Hours
+		}
+
+		require.Len(t, spent, len(uxouts))
+		require.Equal(t, coins, c.Coins)
+		require.Equal(t, fees, c.Fee)
+	})
+
+	t.Run("outputs without hours", func(t *testing.T) {
+		withHours := makeUxOuts(secKeys[0], 2, 10)
+		withoutHours := makeUxOuts(secKeys[1], 5, 0)
+		auxs := coin.NewAddressUxOuts(append(withHours, withoutHours...))
+
+		c, err := Consolidate(ConsolidateParams{
+			To:        to,
+			MaxInputs: 3,
+		}, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, c.Transactions, 2)
+
+		// Each transaction spends one output with hours and two without
+		for _, inputs := range c.Inputs {
+			require.Len(t, inputs, 3)
+			require.NotZero(t, inputs[0].Hours)
+			require.Zero(t, inputs[1].Hours)
+			require.Zero(t, inputs[2].Hours)
+		}
+
+		// The output without hours with the most coins is left unspent
+		require.Len(t, c.Skipped, 1)
+		require.Equal(t, withoutHours[4].Hash(), c.Skipped[0].Hash)
+	})
+
+	t.Run("single output of the destination", func(t *testing.T) {
+		uxouts := makeUxOuts(secKeys[0], 1, 10)
+		auxs := coin.NewAddressUxOuts(uxouts)
+
+		_, err := Consolidate(ConsolidateParams{
+			To: uxouts[0].Body.Address,
+		}, auxs, headTime)
+		require.Equal(t, ErrNothingToConsolidate, err)
+
+		// It can be swept to another address
+		c, err := Consolidate(ConsolidateParams{
+			To: to,
+		}, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, c.Transactions, 1)
+	})
+
+	t.Run("no outputs with hours", func(t *testing.T) {
+		auxs := coin.NewAddressUxOuts(makeUxOuts(secKeys[0], 3, 0))
+
+		_, err := Consolidate(ConsolidateParams{
+			To: to,
+		}, auxs, headTime)
+		require.Equal(t, ErrNothingToConsolidate, err)
+	})
+
+	t.Run("reserved outputs", func(t *testing.T) {
+		uxouts := makeUxOuts(secKeys[0], 4, 10)
+		auxs := coin.NewAddressUxOuts(uxouts)
+
+		reserved := map[cipher.SHA256]struct{}{
+			uxouts[0].Hash(): {},
+			uxouts[2].Hash(): {},
+		}
+
+		c, err := Consolidate(ConsolidateParams{
+			To:       to,
+			Reserved: reserved,
+		}, auxs, headTime)
+		require.NoError(t, err)
+		require.Len(t, c.Transactions, 1)
+		require.Equal(t, []cipher.SHA256{uxouts[1].Hash(), uxouts[3].Hash()}, c.Transactions[0].In)
+
+		for _, ux := range uxouts {
+			reserved[ux.Hash()] = struct{}{}
+		}
+		_, err = Consolidate(ConsolidateParams{
+			To:       to,
+			Reserved: reserved,
+		}, auxs, headTime)
+		require.Equal(t, ErrAllUxOutsReserved, err)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		_, err := Consolidate(ConsolidateParams{}, nil, headTime)
+		require.Equal(t, ErrNullConsolidationAddress, err)
+	})
+}
@@ -27,8 +27,10 @@ var (
 
 // Create creates an unsigned transaction based upon Params.
 // NOTE: Caller must ensure that auxs correspond to params.UxOuts options
-// Outputs to spend are chosen from the pool of outputs provided.
-// The outputs are chosen by the following procedure:
+// Outputs to spend are chosen from the pool of outputs provided, with the coin selection strategy of Params.
+// Outputs in Params.Reserved are never chosen. A caller spending outputs requested explicitly should reject
+// the reserved ones with NewErrUxOutReserved, instead of having them skipped.
+// By default, the outputs are chosen by the following procedure:
 //   - All outputs are merged into one list and are sorted coins highest, hours lowest, with the hash as a tiebreaker
 //   - Outputs are chosen from the beginning of this list, until the requested amount of coins is met.
 //     If hours are also specified, selection continues until the requested amount of hours are met.
@@ -56,6 +58,14 @@ func create(p Params, auxs coin.AddressUxOuts, headTime uint64, callCount int) (
 		return nil, nil, err
 	}
 
+	// Skip the outputs reserved by other transactions
+	if len(p.Reserved) != 0 && len(uxb) != 0 {
+		uxb = uxBalancesUnreserved(uxb, p.Reserved)
+		if len(uxb) == 0 {
+			return nil, nil, ErrAllUxOutsReserved
+		}
+	}
+
 	// Reverse lookup set to recover the inputs
 	uxbMap := make(map[cipher.SHA256]UxBalance, len(uxb))
 	for _, u := range uxb {
@@ -80,10 +90,15 @@ func create(p Params, auxs coin.AddressUxOuts, headTime uint64, callCount int) (
 		}
 	}
 
-	// Use the MinimizeUxOuts strategy, to use least possible uxouts
+	// By default, use the MinimizeUxOuts strategy, to use least possible uxouts
 	// this will allow more frequent spending
 	// we don't need to check whether we have sufficient balance beforehand as ChooseSpends already checks that
-	spends, err := ChooseSpendsMinimizeUxOuts(uxb, totalOutCoins, requestedHours)
+	selector, err := GetCoinSelector(p.CoinSelection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spends, err := selector.ChooseSpends(uxb, totalOutCoins, requestedHours)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -111,7 +126,7 @@ func create(p Params, auxs coin.AddressUxOuts, headTime uint64, callCount int) (
 	feeHours := fee.RequiredFee(totalInputHours, params.UserVerifyTxn.BurnFactor)
 	if feeHours == 0 {
 		// feeHours can only be 0 if totalInputHours is 0, and if totalInputHours was 0
-		// then ChooseSpendsMinimizeUxOuts should have already returned an error
+		// then the coin selector should have already returned an error
 		err := errors.New("Chosen spends have no coin hours, unexpectedly")
 		logger.Critical().WithError(err).WithField("totalInputHours", totalInputHours).Error()
 		return nil, nil, err
@@ -197,6 +212,9 @@ func create(p Params, auxs coin.AddressUxOuts, headTime uint64, callCount int) (
 		// If size of the fee for this output is less than the changeHours, add it
 		// Update changeCoins and changeHours
 		z := uxBalancesSub(uxb, spends)
+		if f, ok := selector.(extraSpendFilter); ok {
+			z = f.filterExtraSpends(spends, z)
+		}
 		sortSpendsHoursLowToHigh(z)
 		if len(z) > 0 {
 			logger.Debug("Extra input found, evaluating if it can recover change hours")
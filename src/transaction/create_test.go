@@ -257,6 +257,50 @@ func TestCreate(t *testing.T) {
 			chosenUnspents: []coin.UxOut{originalUxouts[0]},
 		},
 
+		{
+			name: "manual, 1 output, no change, reserved unspent skipped",
+			params: Params{
+				ChangeAddress: &changeAddress,
+				HoursSelection: HoursSelection{
+					Type: HoursSelectionTypeManual,
+				},
+				To: []coin.TransactionOutput{
+					{
+						Address: addrs[0],
+						Hours:   50,
+						Coins:   2e6,
+					},
+				},
+				Reserved: map[cipher.SHA256]struct{}{
+					originalUxouts[0].Hash(): {},
+				},
+			},
+			unspents:       uxouts,
+			chosenUnspents: []coin.UxOut{originalUxouts[1]},
+		},
+
+		{
+			name: "all unspents reserved",
+			params: Params{
+				ChangeAddress: &changeAddress,
+				HoursSelection: HoursSelection{
+					Type: HoursSelectionTypeManual,
+				},
+				To: []coin.TransactionOutput{
+					{
+						Address: addrs[0],
+						Hours:   10,
+						Coins:   1e6,
+					},
+				},
+				Reserved: map[cipher.SHA256]struct{}{
+					uxouts[0].Hash(): {},
+				},
+			},
+			unspents: uxouts[:1],
+			err:      ErrAllUxOutsReserved,
+		},
+
 		// TODO -- belongs in visor_wallet_test.go
 		// {
 		// 	name: "manual, 1 output, no change, unknown address in auxs",
@@ -353,6 +397,29 @@ func TestCreate(t *testing.T) {
 			},
 		},
 
+		{
+			// there are leftover coin hours and no coins change,
+			// but the exact match coin selection does not add an input to force a change output
+			name: "manual, 1 output, exact match coin selection, no forced change",
+			params: Params{
+				ChangeAddress: &changeAddress,
+				HoursSelection: HoursSelection{
+					Type: HoursSelectionTypeManual,
+				},
+				To: []coin.TransactionOutput{
+					{
+						Address: addrs[0],
+						Hours:   0,
+						Coins:   2e6 * 2,
+					},
+				},
+				CoinSelection: CoinSelectionExactMatch,
+			},
+			unspents:       uxouts,
+			chosenUnspents: []coin.UxOut{originalUxouts[0], originalUxouts[1]},
+			changeOutput:   nil,
+		},
+
 		{
 			// there are leftover coin hours and no coins change,
 			// but there are no more unspents to use to force a change output
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/shopspring/decimal"
 
@@ -60,8 +61,17 @@ var (
 	ErrInvalidShareFactor = NewError(errors.New("HoursSelection.ShareFactor can only be used for share mode"))
 	// ErrShareFactorOutOfRange HoursSelection.ShareFactor must be >= 0 and <= 1
 	ErrShareFactorOutOfRange = NewError(errors.New("HoursSelection.ShareFactor must be >= 0 and <= 1"))
+	// ErrInvalidCoinSelection Invalid CoinSelection
+	ErrInvalidCoinSelection = NewError(errors.New("Invalid CoinSelection"))
+	// ErrAllUxOutsReserved all of the unspent outputs are reserved by other transactions
+	ErrAllUxOutsReserved = NewError(errors.New("All unspent outputs are reserved by other transactions"))
 )
 
+// NewErrUxOutReserved returns the error for an unspent output requested to spend that is reserved by another transaction
+func NewErrUxOutReserved(uxOut cipher.SHA256) error {
+	return NewError(fmt.Errorf("Unspent output %s is reserved by another transaction", uxOut.Hex()))
+}
+
 // HoursSelection defines options for hours distribution
 type HoursSelection struct {
 	Type        string
@@ -74,6 +84,11 @@ type Params struct {
 	HoursSelection HoursSelection
 	To             []coin.TransactionOutput
 	ChangeAddress  *cipher.Address
+	// CoinSelection is the name of the strategy used to choose the outputs to spend,
+	// see GetCoinSelector. The default is CoinSelectionMinimizeUxOuts
+	CoinSelection string
+	// Reserved are the hashes of the outputs reserved by other transactions, which are not chosen to spend
+	Reserved map[cipher.SHA256]struct{}
 }
 
 // Validate validates Params
@@ -151,5 +166,9 @@ func (c Params) Validate() error {
 		}
 	}
 
+	if _, err := GetCoinSelector(c.CoinSelection); err != nil {
+		return err
+	}
+
 	return nil
 }
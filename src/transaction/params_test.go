@@ -260,6 +260,19 @@ func TestCreateWalletParamsVerify(t *testing.T) {
 			},
 		},
 
+		{
+			name: "invalid coin selection",
+			params: Params{
+				ChangeAddress: &changeAddress,
+				To:            toManual,
+				HoursSelection: HoursSelection{
+					Type: HoursSelectionTypeManual,
+				},
+				CoinSelection: "foo",
+			},
+			err: "Invalid CoinSelection",
+		},
+
 		{
 			name: "valid manual",
 			params: Params{
@@ -270,6 +283,18 @@ func TestCreateWalletParamsVerify(t *testing.T) {
 				},
 			},
 		},
+
+		{
+			name: "valid manual with coin selection",
+			params: Params{
+				ChangeAddress: &changeAddress,
+				To:            toManual,
+				HoursSelection: HoursSelection{
+					Type: HoursSelectionTypeManual,
+				},
+				CoinSelection: CoinSelectionExactMatch,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,378 @@
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/util/fee"
+)
+
+const (
+	// CoinSelectionMinimizeUxOuts spends the fewest outputs possible, see ChooseSpendsMinimizeUxOuts.
+	// This is the default coin selection strategy.
+	CoinSelectionMinimizeUxOuts = "minimize_uxouts"
+	// CoinSelectionMaximizeUxOuts spends the most outputs possible, see ChooseSpendsMaximizeUxOuts
+	CoinSelectionMaximizeUxOuts = "maximize_uxouts"
+	// CoinSelectionExactMatch looks for outputs that add up to the amount exactly, to avoid change,
+	// see ChooseSpendsExactMatch
+	CoinSelectionExactMatch = "exact_match"
+	// CoinSelectionOldestFirst spends the oldest outputs first, see ChooseSpendsOldestFirst
+	CoinSelectionOldestFirst = "oldest_first"
+	// CoinSelectionPrivacy avoids spending outputs of several addresses together, see ChooseSpendsPrivacy
+	CoinSelectionPrivacy = "privacy"
+	// CoinSelectionConsolidate spends as many small outputs as possible, see ChooseSpendsConsolidate
+	CoinSelectionConsolidate = "consolidate"
+)
+
+const (
+	// exactMatchMaxTries bounds the number of branches visited by ChooseSpendsExactMatch
+	exactMatchMaxTries = 100000
+	// MaxConsolidationInputs is the maximum number of outputs spent by ChooseSpendsConsolidate.
+	// It keeps the transaction well under the default max transaction size.
+	MaxConsolidationInputs = 128
+)
+
+// CoinSelector chooses the outputs to spend to send an amount of coins and hours
+type CoinSelector interface {
+	ChooseSpends(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error)
+}
+
+// CoinSelectorFunc adapts a function to a CoinSelector
+type CoinSelectorFunc func(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error)
+
+// ChooseSpends calls f
+func (f CoinSelectorFunc) ChooseSpends(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	return f(uxa, coins, hours)
+}
+
+// extraSpendFilter is implemented by coin selectors that restrict the extra output that Create
+// may add to the chosen outputs to recover change hours
+type extraSpendFilter interface {
+	filterExtraSpends(spends, candidates []UxBalance) []UxBalance
+}
+
+var coinSelectors = map[string]CoinSelector{
+	CoinSelectionMinimizeUxOuts: CoinSelectorFunc(ChooseSpendsMinimizeUxOuts),
+	CoinSelectionMaximizeUxOuts: CoinSelectorFunc(ChooseSpendsMaximizeUxOuts),
+	CoinSelectionExactMatch:     exactMatchSelector{},
+	CoinSelectionOldestFirst:    CoinSelectorFunc(ChooseSpendsOldestFirst),
+	CoinSelectionPrivacy:        privacySelector{},
+	CoinSelectionConsolidate:    CoinSelectorFunc(ChooseSpendsConsolidate),
+}
+
+// CoinSelections returns the names of the coin selection strategies, sorted
+func CoinSelections() []string {
+	names := make([]string, 0, len(coinSelectors))
+	for name := range coinSelectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetCoinSelector returns the coin selection strategy with the given name.
+// An empty name selects the default strategy, CoinSelectionMinimizeUxOuts.
+func GetCoinSelector(name string) (CoinSelector, error) {
+	if name == "" {
+		name = CoinSelectionMinimizeUxOuts
+	}
+
+	s, ok := coinSelectors[name]
+	if !ok {
+		return nil, ErrInvalidCoinSelection
+	}
+
+	return s, nil
+}
+
+// checkSpendable returns an error if no outputs can be chosen for the amount of coins
+func checkSpendable(uxa []UxBalance, coins uint64) error {
+	if coins == 0 {
+		return ErrZeroSpend
+	}
+
+	if len(uxa) == 0 {
+		return ErrNoUnspents
+	}
+
+	for _, ux := range uxa {
+		if ux.Coins == 0 {
+			logger.Panic("UxOut coins are 0, can't spend")
+			return errors.New("UxOut coins are 0, can't spend")
+		}
+	}
+
+	return nil
+}
+
+// hasEnough returns true if outputs with haveCoins and haveHours can send coins and hours, after the fee
+func hasEnough(haveCoins, haveHours, coins, hours uint64) bool {
+	return haveCoins >= coins && haveHours > 0 && fee.RemainingHours(haveHours, params.UserVerifyTxn.BurnFactor) >= hours
+}
+
+// chooseSpendsInOrder chooses outputs in the order of sortStrategy until the amount is met
+func chooseSpendsInOrder(uxa []UxBalance, coins, hours uint64, sortStrategy func([]UxBalance)) ([]UxBalance, error) {
+	if err := checkSpendable(uxa, coins); err != nil {
+		return nil, err
+	}
+
+	sorted := append([]UxBalance{}, uxa...)
+	sortStrategy(sorted)
+
+	var haveCoins, haveHours uint64
+	var spending []UxBalance
+	for _, ux := range sorted {
+		spending = append(spending, ux)
+		haveCoins += ux.Coins
+		haveHours += ux.Hours
+
+		if hasEnough(haveCoins, haveHours, coins, hours) {
+			return spending, nil
+		}
+	}
+
+	switch {
+	case haveCoins < coins:
+		return nil, ErrInsufficientBalance
+	case haveHours == 0:
+		return nil, fee.ErrTxnNoFee
+	default:
+		return nil, ErrInsufficientHours
+	}
+}
+
+// ChooseSpendsOldestFirst chooses uxout spends to satisfy an amount, spending the oldest uxouts first.
+//     -- PRO: Newer uxouts are left unspent and keep accumulating coin hours.
+//     -- PRO: Old uxouts, which have earned the most coin hours, transfer their hours to the change output.
+//     -- CON: May spend more uxouts than needed, increasing the transaction size.
+func ChooseSpendsOldestFirst(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	return chooseSpendsInOrder(uxa, coins, hours, sortSpendsOldestFirst)
+}
+
+// sortSpendsOldestFirst sorts uxout spends with the oldest first
+func sortSpendsOldestFirst(uxa []UxBalance) {
+	// Sort by:
+	// oldest first
+	//  coins highest
+	//   tie break with hash comparison
+	sort.Slice(uxa, func(i, j int) bool {
+		a := uxa[i]
+		b := uxa[j]
+
+		if a.BkSeq == b.BkSeq {
+			if a.Coins == b.Coins {
+				return cmpUxBalanceByUxID(a, b)
+			}
+			return a.Coins > b.Coins
+		}
+		return a.BkSeq < b.BkSeq
+	})
+}
+
+// ChooseSpendsConsolidate chooses uxout spends to satisfy an amount, then adds as many other uxouts as possible,
+// up to MaxConsolidationInputs, with the lowest balances first.
+// The uxouts are consolidated into the change output.
+//     -- PRO: Reduces the number of uxouts of a wallet, making later transactions smaller.
+//     -- CON: The transaction is large, and burns the coin hours of every uxout spent.
+func ChooseSpendsConsolidate(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	spending, err := ChooseSpendsMaximizeUxOuts(uxa, coins, hours)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(spending) >= MaxConsolidationInputs {
+		return spending, nil
+	}
+
+	rest := uxBalancesSub(uxa, spending)
+	sortSpendsCoinsLowToHigh(rest)
+
+	n := MaxConsolidationInputs - len(spending)
+	if n > len(rest) {
+		n = len(rest)
+	}
+
+	return append(spending, rest[:n]...), nil
+}
+
+// ChooseSpendsExactMatch chooses uxout spends whose coins add up to the amount exactly, so that no
+// change output is needed. The combinations are searched depth first with branch and bound, with
+// the highest balances first. If no exact match is found in a bounded number of tries,
+// it falls back to ChooseSpendsMinimizeUxOuts.
+//     -- PRO: Avoids creating a change output, which doesn't leak the change address and doesn't
+//             create a new small uxout.
+//     -- CON: Searching is slow for wallets with many uxouts.
+func ChooseSpendsExactMatch(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	if err := checkSpendable(uxa, coins); err != nil {
+		return nil, err
+	}
+
+	sorted := append([]UxBalance{}, uxa...)
+	sortSpendsCoinsHighToLow(sorted)
+
+	// remaining[i] is the sum of the coins of sorted[i:], it bounds the coins a branch can add.
+	// The sum can't overflow because the sum of all unspent outputs is bounded by the coin supply.
+	remaining := make([]uint64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].Coins
+	}
+
+	s := exactMatchSearch{
+		uxa:       sorted,
+		remaining: remaining,
+		hours:     hours,
+	}
+	if s.search(0, coins, 0) {
+		return s.chosen, nil
+	}
+
+	return ChooseSpendsMinimizeUxOuts(uxa, coins, hours)
+}
+
+type exactMatchSearch struct {
+	uxa       []UxBalance
+	remaining []uint64
+	hours     uint64
+	tries     int
+	chosen    []UxBalance
+}
+
+// search looks for outputs in uxa[i:] that add up to coins exactly, with enough hours added to haveHours
+func (s *exactMatchSearch) search(i int, coins, haveHours uint64) bool {
+	s.tries++
+	if s.tries > exactMatchMaxTries {
+		return false
+	}
+
+	if coins == 0 {
+		return hasEnough(0, haveHours, 0, s.hours)
+	}
+
+	if i == len(s.uxa) || s.remaining[i] < coins {
+		return false
+	}
+
+	// Include uxa[i]
+	ux := s.uxa[i]
+	if ux.Coins <= coins {
+		s.chosen = append(s.chosen, ux)
+		if s.search(i+1, coins-ux.Coins, haveHours+ux.Hours) {
+			return true
+		}
+		s.chosen = s.chosen[:len(s.chosen)-1]
+	}
+
+	// Exclude uxa[i]
+	return s.search(i+1, coins, haveHours)
+}
+
+type exactMatchSelector struct{}
+
+func (exactMatchSelector) ChooseSpends(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	return ChooseSpendsExactMatch(uxa, coins, hours)
+}
+
+// filterExtraSpends does not add any output, it would create the change output that an exact match avoids
+func (exactMatchSelector) filterExtraSpends(spends, candidates []UxBalance) []UxBalance {
+	return nil
+}
+
+// ChooseSpendsPrivacy chooses uxout spends to satisfy an amount while avoiding spending uxouts of
+// different addresses together, which would reveal that the addresses have the same owner.
+// If a single address has enough balance, its uxouts are chosen with ChooseSpendsMinimizeUxOuts,
+// preferring the address that needs the fewest uxouts, then the least change.
+// Otherwise, the addresses with the highest balances are combined until the amount is met.
+//     -- PRO: Links as few addresses as possible.
+//     -- CON: May spend more uxouts, or leave more change, than ChooseSpendsMinimizeUxOuts.
+func ChooseSpendsPrivacy(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	if err := checkSpendable(uxa, coins); err != nil {
+		return nil, err
+	}
+
+	byAddress := make(map[cipher.Address][]UxBalance)
+	var addrs []cipher.Address
+	for _, ux := range uxa {
+		if _, ok := byAddress[ux.Address]; !ok {
+			addrs = append(addrs, ux.Address)
+		}
+		byAddress[ux.Address] = append(byAddress[ux.Address], ux)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0
+	})
+
+	// Spend from a single address if possible
+	var best []UxBalance
+	var bestCoins uint64
+	for _, a := range addrs {
+		spends, err := ChooseSpendsMinimizeUxOuts(byAddress[a], coins, hours)
+		if err != nil {
+			continue
+		}
+
+		var haveCoins uint64
+		for _, ux := range spends {
+			haveCoins += ux.Coins
+		}
+
+		if best == nil || len(spends) < len(best) || (len(spends) == len(best) && haveCoins < bestCoins) {
+			best = spends
+			bestCoins = haveCoins
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+
+	// Combine the addresses with the highest balances
+	balances := make(map[cipher.Address]uint64, len(addrs))
+	for _, ux := range uxa {
+		balances[ux.Address] += ux.Coins
+	}
+
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return balances[addrs[i]] > balances[addrs[j]]
+	})
+
+	var combined []UxBalance
+	var err error
+	for _, a := range addrs {
+		combined = append(combined, byAddress[a]...)
+
+		var spends []UxBalance
+		spends, err = ChooseSpendsMinimizeUxOuts(combined, coins, hours)
+		if err == nil {
+			return spends, nil
+		}
+	}
+
+	return nil, err
+}
+
+type privacySelector struct{}
+
+func (privacySelector) ChooseSpends(uxa []UxBalance, coins, hours uint64) ([]UxBalance, error) {
+	return ChooseSpendsPrivacy(uxa, coins, hours)
+}
+
+// filterExtraSpends only adds outputs of the addresses that are already spent
+func (privacySelector) filterExtraSpends(spends, candidates []UxBalance) []UxBalance {
+	addrs := make(map[cipher.Address]struct{}, len(spends))
+	for _, ux := range spends {
+		addrs[ux.Address] = struct{}{}
+	}
+
+	var filtered []UxBalance
+	for _, ux := range candidates {
+		if _, ok := addrs[ux.Address]; ok {
+			filtered = append(filtered, ux)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,345 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/util/fee"
+)
+
+func TestGetCoinSelector(t *testing.T) {
+	for _, name := range append(CoinSelections(), "") {
+		s, err := GetCoinSelector(name)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	}
+
+	_, err := GetCoinSelector("foo")
+	require.Equal(t, ErrInvalidCoinSelection, err)
+}
+
+func TestCoinSelectorsErrors(t *testing.T) {
+	uxb := []UxBalance{
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 10,
+			Hours: 10,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 5,
+			Hours: 0,
+		},
+	}
+
+	uxbNoHours := []UxBalance{
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 10,
+		},
+	}
+
+	cases := []struct {
+		name  string
+		uxb   []UxBalance
+		coins uint64
+		hours uint64
+		err   error
+	}{
+		{
+			name:  "zero spend",
+			uxb:   uxb,
+			coins: 0,
+			err:   ErrZeroSpend,
+		},
+		{
+			name:  "no unspents",
+			coins: 1,
+			err:   ErrNoUnspents,
+		},
+		{
+			name:  "insufficient balance",
+			uxb:   uxb,
+			coins: 16,
+			err:   ErrInsufficientBalance,
+		},
+		{
+			name:  "no hours",
+			uxb:   uxbNoHours,
+			coins: 1,
+			err:   fee.ErrTxnNoFee,
+		},
+		{
+			name:  "insufficient hours",
+			uxb:   uxb,
+			coins: 1,
+			hours: 10,
+			err:   ErrInsufficientHours,
+		},
+	}
+
+	for _, name := range CoinSelections() {
+		s, err := GetCoinSelector(name)
+		require.NoError(t, err)
+
+		for _, tc := range cases {
+			t.Run(name+" "+tc.name, func(t *testing.T) {
+				_, err := s.ChooseSpends(tc.uxb, tc.coins, tc.hours)
+				require.Equal(t, tc.err, err)
+			})
+		}
+	}
+}
+
+func TestChooseSpendsExactMatch(t *testing.T) {
+	uxb := []UxBalance{
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 10,
+			Hours: 10,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 7,
+			Hours: 0,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 5,
+			Hours: 4,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 3,
+			Hours: 6,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 2,
+			Hours: 1,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		coins  uint64
+		hours  uint64
+		chosen []UxBalance
+	}{
+		{
+			name:   "single output",
+			coins:  10,
+			chosen: []UxBalance{uxb[0]},
+		},
+		{
+			name:   "several outputs",
+			coins:  8,
+			chosen: []UxBalance{uxb[2], uxb[3]},
+		},
+		{
+			name:   "skips a match without hours",
+			coins:  7,
+			chosen: []UxBalance{uxb[2], uxb[4]},
+		},
+		{
+			name:   "skips a match without enough hours",
+			coins:  15,
+			hours:  8,
+			chosen: []UxBalance{uxb[0], uxb[3], uxb[4]},
+		},
+		{
+			name:   "no exact match falls back to minimize uxouts",
+			coins:  11,
+			chosen: []UxBalance{uxb[0], uxb[1]},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chosen, err := ChooseSpendsExactMatch(uxb, tc.coins, tc.hours)
+			require.NoError(t, err)
+			require.Equal(t, tc.chosen, chosen)
+		})
+	}
+
+	// No extra output is added to recover change hours
+	require.Empty(t, exactMatchSelector{}.filterExtraSpends(uxb[:1], uxb[1:]))
+}
+
+func TestChooseSpendsOldestFirst(t *testing.T) {
+	uxb := []UxBalance{
+		{
+			Hash:  testutil.RandSHA256(t),
+			BkSeq: 5,
+			Coins: 10,
+			Hours: 10,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			BkSeq: 1,
+			Coins: 2,
+			Hours: 0,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			BkSeq: 3,
+			Coins: 4,
+			Hours: 20,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			BkSeq: 3,
+			Coins: 6,
+			Hours: 2,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		coins  uint64
+		hours  uint64
+		chosen []UxBalance
+	}{
+		{
+			// the oldest output has no hours, so another output is needed to pay the fee;
+			// outputs with the same seq are chosen by coins highest
+			name:   "oldest output has no hours",
+			coins:  1,
+			chosen: []UxBalance{uxb[1], uxb[3]},
+		},
+		{
+			name:   "continues until coins are met",
+			coins:  9,
+			chosen: []UxBalance{uxb[1], uxb[3], uxb[2]},
+		},
+		{
+			name:   "continues until hours are met",
+			coins:  1,
+			hours:  12,
+			chosen: []UxBalance{uxb[1], uxb[3], uxb[2], uxb[0]},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chosen, err := ChooseSpendsOldestFirst(uxb, tc.coins, tc.hours)
+			require.NoError(t, err)
+			require.Equal(t, tc.chosen, chosen)
+		})
+	}
+}
+
+func TestChooseSpendsPrivacy(t *testing.T) {
+	addrs := []cipher.Address{
+		testutil.MakeAddress(),
+		testutil.MakeAddress(),
+		testutil.MakeAddress(),
+	}
+
+	uxb := []UxBalance{
+		{
+			Hash:    testutil.RandSHA256(t),
+			Address: addrs[0],
+			Coins:   10,
+			Hours:   10,
+		},
+		{
+			Hash:    testutil.RandSHA256(t),
+			Address: addrs[1],
+			Coins:   6,
+			Hours:   10,
+		},
+		{
+			Hash:    testutil.RandSHA256(t),
+			Address: addrs[1],
+			Coins:   5,
+			Hours:   10,
+		},
+		{
+			Hash:    testutil.RandSHA256(t),
+			Address: addrs[2],
+			Coins:   4,
+			Hours:   10,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		coins  uint64
+		chosen []UxBalance
+	}{
+		{
+			name:   "single address with the fewest outputs",
+			coins:  5,
+			chosen: []UxBalance{uxb[1]},
+		},
+		{
+			name:   "single address with the least change",
+			coins:  4,
+			chosen: []UxBalance{uxb[3]},
+		},
+		{
+			name:   "single address with several outputs",
+			coins:  11,
+			chosen: []UxBalance{uxb[1], uxb[2]},
+		},
+		{
+			name:   "addresses with the highest balances",
+			coins:  20,
+			chosen: []UxBalance{uxb[0], uxb[1], uxb[2]},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chosen, err := ChooseSpendsPrivacy(uxb, tc.coins, 0)
+			require.NoError(t, err)
+			require.Equal(t, tc.chosen, chosen)
+		})
+	}
+
+	// Only outputs of the addresses being spent are added to recover change hours
+	extra := privacySelector{}.filterExtraSpends([]UxBalance{uxb[1]}, []UxBalance{uxb[0], uxb[2], uxb[3]})
+	require.Equal(t, []UxBalance{uxb[2]}, extra)
+}
+
+func TestChooseSpendsConsolidate(t *testing.T) {
+	uxb := []UxBalance{
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 10,
+			Hours: 10,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 1,
+			Hours: 1,
+		},
+		{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 3,
+			Hours: 1,
+		},
+	}
+
+	chosen, err := ChooseSpendsConsolidate(uxb, 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, []UxBalance{uxb[0], uxb[1], uxb[2]}, chosen)
+
+	// The number of outputs spent is limited
+	uxb = nil
+	for i := 0; i < MaxConsolidationInputs+10; i++ {
+		uxb = append(uxb, UxBalance{
+			Hash:  testutil.RandSHA256(t),
+			Coins: 1,
+			Hours: 1,
+		})
+	}
+
+	chosen, err = ChooseSpendsConsolidate(uxb, 2, 0)
+	require.NoError(t, err)
+	require.Len(t, chosen, MaxConsolidationInputs)
+}
@@ -167,6 +167,75 @@ func SaveBinary(filename string, data []byte, mode os.FileMode) error {
 	return os.Remove(tmpname)
 }
 
+// SaveJSONAtomic writes value into json file atomically, see SaveBinaryAtomic
+func SaveJSONAtomic(filename string, thing interface{}, mode os.FileMode, backupFilename string) error {
+	data, err := json.MarshalIndent(thing, "", "    ")
+	if err != nil {
+		return err
+	}
+	return SaveBinaryAtomic(filename, data, mode, backupFilename)
+}
+
+// SaveBinaryAtomic persists data into given file atomically. The data is written and synced
+// to a temporary file, which is then renamed to the file, so that the file has either
+// the previous or the new data, even if the process is interrupted.
+// If backupFilename is not empty, the previous file, if there was one, is copied to it first.
+func SaveBinaryAtomic(filename string, data []byte, mode os.FileMode, backupFilename string) error {
+	tmpname := filename + ".tmp"
+	if err := writeFileSync(tmpname, data, mode); err != nil {
+		removeFile(tmpname)
+		return err
+	}
+
+	if backupFilename != "" {
+		prev, err := ioutil.ReadFile(filename)
+		switch {
+		case err == nil:
+			if err := writeFileSync(backupFilename, prev, mode); err != nil {
+				removeFile(tmpname)
+				return err
+			}
+		case os.IsNotExist(err):
+		default:
+			removeFile(tmpname)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpname, filename); err != nil {
+		removeFile(tmpname)
+		return err
+	}
+
+	return nil
+}
+
+// writeFileSync writes data to a file and syncs it to the disk
+func writeFileSync(filename string, data []byte, mode os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+
+	return f.Close()
+}
+
+func removeFile(filename string) {
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		logger.WithError(err).Warningf("os.Remove(%s) failed", filename)
+	}
+}
+
 //TODO: require file named after application and then hashcode, in static directory
 
 // ResolveResourceDirectory searches locations for a research directory and returns absolute path
@@ -212,3 +212,43 @@ func TestSaveBinary(t *testing.T) {
 	requireFileMode(t, fn, 0644)
 	// requireFileMode(t, fn+".bak", 0644)
 }
+
+func TestSaveBinaryAtomic(t *testing.T) {
+	fn := "test.bin"
+	bak := fn + ".bak"
+	defer cleanup(fn)
+
+	b := make([]byte, 128)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	// No backup is made if there was no file
+	err = SaveBinaryAtomic(fn, b, 0600, bak)
+	require.NoError(t, err)
+	testutil.RequireFileNotExists(t, fn+".tmp")
+	testutil.RequireFileNotExists(t, bak)
+	requireIsRegularFile(t, fn)
+	requireFileContentsBinary(t, fn, b)
+	requireFileMode(t, fn, 0600)
+
+	b2 := make([]byte, 128)
+	_, err = rand.Read(b2)
+	require.NoError(t, err)
+	require.False(t, bytes.Equal(b, b2))
+
+	// The previous file is kept in the backup
+	err = SaveBinaryAtomic(fn, b2, 0600, bak)
+	require.NoError(t, err)
+	testutil.RequireFileNotExists(t, fn+".tmp")
+	requireFileContentsBinary(t, fn, b2)
+	requireFileMode(t, fn, 0600)
+	requireIsRegularFile(t, bak)
+	requireFileContentsBinary(t, bak, b)
+	requireFileMode(t, bak, 0600)
+
+	// Without a backup filename, the backup is left unchanged
+	err = SaveBinaryAtomic(fn, b, 0600, "")
+	require.NoError(t, err)
+	requireFileContentsBinary(t, fn, b)
+	requireFileContentsBinary(t, bak, b)
+}
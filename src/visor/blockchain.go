@@ -98,6 +98,17 @@ func CreateBuckets(db *dbutil.DB) error {
 		return dbutil.CreateBuckets(tx, [][]byte{
 			UnconfirmedTxnsBkt,
 			UnconfirmedUnspentsBkt,
+			UnconfirmedLastReceivedBkt,
+			UnconfirmedFeeIndexBkt,
+			UnconfirmedFeeIndexKeysBkt,
+			UnconfirmedMetaBkt,
+			UnconfirmedOutputsBkt,
+			UnconfirmedSpendsBkt,
+			TxnLifecyclesBkt,
+			TxnLifecyclesPendingBkt,
+			TxnLifecyclesFinalBkt,
+			IdempotencyKeysBkt,
+			OutputReservationsBkt,
 		})
 	})
 }
@@ -663,3 +663,278 @@ func testVerifyTransactionAddressLocking(t *testing.T, toAddr string, expectedEr
 		requireSoftViolation(t, expectedErr.Error(), err)
 	}
 }
+
+func TestVerifyMultisigTransactionActivation(t *testing.T) {
+	db, close := prepareDB(t)
+	defer close()
+
+	_, s := cipher.GenerateKeyPair()
+	bc := MakeBlockchain(t, db, s)
+
+	// Send coins to a 2-of-2 multisig address
+	p1, s1 := cipher.GenerateKeyPair()
+	p2, s2 := cipher.GenerateKeyPair()
+	pubKeys := []cipher.PubKey{p1, p2}
+	addr, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+
+	txn := CreateGenesisSpendTransaction(t, db, bc, addr, GenesisCoins, 1e6, 5e8)
+	uxOut := ExecuteGenesisSpendTransaction(t, db, bc, txn)
+
+	// Spend the multisig output
+	txn = coin.Transaction{}
+	require.NoError(t, txn.PushInput(uxOut.Hash()))
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), uxOut.Body.Coins, uxOut.Body.Hours/2))
+	txn.Sigs = make([]cipher.Sig, 1)
+	require.NoError(t, txn.UpdateHeader())
+
+	hash := cipher.AddSHA256(txn.InnerHash, txn.In[0])
+	require.NoError(t, txn.SetWitnesses(map[int]coin.Witness{
+		0: coin.MultisigWitness{
+			Required: 2,
+			PubKeys:  pubKeys,
+			Sigs:     []cipher.Sig{cipher.MustSignHash(hash, s1), cipher.MustSignHash(hash, s2)},
+		},
+	}))
+
+	defer func(h uint64) {
+		params.MultisigActivationHeight = h
+	}(params.MultisigActivationHeight)
+
+	verify := func() error {
+		return db.View("", func(tx *dbutil.Tx) error {
+			return bc.VerifySingleTxnHardConstraints(tx, txn, TxnSigned)
+		})
+	}
+
+	// The head is at block 1, the transaction would go in block 2
+	params.MultisigActivationHeight = 0
+	requireHardViolation(t, ErrTxnMultisigNotActivated.Error(), verify())
+
+	params.MultisigActivationHeight = 3
+	requireHardViolation(t, ErrTxnMultisigNotActivated.Error(), verify())
+
+	params.MultisigActivationHeight = 2
+	require.NoError(t, verify())
+
+	// Multisig transactions can't be verified as unsigned
+	err = db.View("", func(tx *dbutil.Tx) error {
+		return bc.VerifySingleTxnHardConstraints(tx, txn, TxnUnsigned)
+	})
+	requireHardViolation(t, "Multisig transaction cannot be unsigned", err)
+}
+
+func TestVerifyTimeLockedTransaction(t *testing.T) {
+	defer func(h uint64) {
+		params.TimeLockActivationHeight = h
+	}(params.TimeLockActivationHeight)
+
+	// The head is at block 1 created at GenesisTime+TimeIncrement, the transaction would go in block 2
+	headTime := uint64(GenesisTime + TimeIncrement)
+
+	cases := []struct {
+		name       string
+		kind       byte
+		value      uint64
+		activation uint64
+		err        error
+	}{
+		{
+			name:       "not activated",
+			kind:       cipher.TimeLockHeight,
+			value:      2,
+			activation: 0,
+			err:        ErrTxnTimeLockNotActivated,
+		},
+		{
+			name:       "activated later",
+			kind:       cipher.TimeLockHeight,
+			value:      2,
+			activation: 3,
+			err:        ErrTxnTimeLockNotActivated,
+		},
+		{
+			name:       "height locked",
+			kind:       cipher.TimeLockHeight,
+			value:      3,
+			activation: 2,
+			err:        coin.ErrTimeLocked,
+		},
+		{
+			name:       "height unlocked",
+			kind:       cipher.TimeLockHeight,
+			value:      2,
+			activation: 2,
+		},
+		{
+			name:       "time locked",
+			kind:       cipher.TimeLockTime,
+			value:      headTime + 1,
+			activation: 2,
+			err:        coin.ErrTimeLocked,
+		},
+		{
+			name:       "time unlocked",
+			kind:       cipher.TimeLockTime,
+			value:      headTime,
+			activation: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, close := prepareDB(t)
+			defer close()
+
+			_, s := cipher.GenerateKeyPair()
+			bc := MakeBlockchain(t, db, s)
+
+			// Send coins to a time-locked address
+			p, s1 := cipher.GenerateKeyPair()
+			owner := cipher.AddressFromPubKey(p)
+			addr, err := cipher.TimeLockedAddress(owner, tc.kind, tc.value)
+			require.NoError(t, err)
+
+			txn := CreateGenesisSpendTransaction(t, db, bc, addr, GenesisCoins, 1e6, 5e8)
+			uxOut := ExecuteGenesisSpendTransaction(t, db, bc, txn)
+
+			// Spend the time-locked output
+			txn = coin.Transaction{}
+			require.NoError(t, txn.PushInput(uxOut.Hash()))
+			require.NoError(t, txn.PushOutput(testutil.MakeAddress(), uxOut.Body.Coins, uxOut.Body.Hours/2))
+			txn.Sigs = make([]cipher.Sig, 1)
+			require.NoError(t, txn.UpdateHeader())
+
+			hash := cipher.AddSHA256(txn.InnerHash, txn.In[0])
+			require.NoError(t, txn.SetWitnesses(map[int]coin.Witness{
+				0: coin.TimeLockWitness{
+					Kind:  tc.kind,
+					Value: tc.value,
+					Owner: owner,
+					Sig:   cipher.MustSignHash(hash, s1),
+				},
+			}))
+
+			params.TimeLockActivationHeight = tc.activation
+
+			err = db.View("", func(tx *dbutil.Tx) error {
+				return bc.VerifySingleTxnHardConstraints(tx, txn, TxnSigned)
+			})
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				requireHardViolation(t, tc.err.Error(), err)
+			}
+		})
+	}
+}
+
+func TestVerifyTimeLockedMultisigTransaction(t *testing.T) {
+	defer func(h uint64) {
+		params.TimeLockActivationHeight = h
+	}(params.TimeLockActivationHeight)
+	defer func(h uint64) {
+		params.MultisigActivationHeight = h
+	}(params.MultisigActivationHeight)
+
+	// The head is at block 1, the transaction would go in block 2
+	cases := []struct {
+		name               string
+		timeLockActivation uint64
+		multisigActivation uint64
+		err                error
+	}{
+		{
+			name:               "multisig not activated",
+			timeLockActivation: 2,
+			multisigActivation: 0,
+			err:                ErrTxnMultisigNotActivated,
+		},
+		{
+			name:               "multisig activated later",
+			timeLockActivation: 2,
+			multisigActivation: 3,
+			err:                ErrTxnMultisigNotActivated,
+		},
+		{
+			name:               "time lock activated later",
+			timeLockActivation: 3,
+			multisigActivation: 2,
+			err:                ErrTxnTimeLockNotActivated,
+		},
+		{
+			name:               "both activated",
+			timeLockActivation: 2,
+			multisigActivation: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, close := prepareDB(t)
+			defer close()
+
+			_, s := cipher.GenerateKeyPair()
+			bc := MakeBlockchain(t, db, s)
+
+			// Send coins to a time-locked address owned by a 2-of-2 multisig address
+			p1, s1 := cipher.GenerateKeyPair()
+			p2, s2 := cipher.GenerateKeyPair()
+			pubKeys := []cipher.PubKey{p1, p2}
+			owner, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+			require.NoError(t, err)
+			addr, err := cipher.TimeLockedAddress(owner, cipher.TimeLockHeight, 2)
+			require.NoError(t, err)
+
+			txn := CreateGenesisSpendTransaction(t, db, bc, addr, GenesisCoins, 1e6, 5e8)
+			uxOut := ExecuteGenesisSpendTransaction(t, db, bc, txn)
+
+			// Spend the time-locked output
+			txn = coin.Transaction{}
+			require.NoError(t, txn.PushInput(uxOut.Hash()))
+			require.NoError(t, txn.PushOutput(testutil.MakeAddress(), uxOut.Body.Coins, uxOut.Body.Hours/2))
+			txn.Sigs = make([]cipher.Sig, 1)
+			require.NoError(t, txn.UpdateHeader())
+
+			hash := cipher.AddSHA256(txn.InnerHash, txn.In[0])
+			require.NoError(t, txn.SetWitnesses(map[int]coin.Witness{
+				0: coin.TimeLockWitness{
+					Kind:  cipher.TimeLockHeight,
+					Value: 2,
+					Owner: owner,
+					Multisig: &coin.MultisigWitness{
+						Required: 2,
+						PubKeys:  pubKeys,
+						Sigs:     []cipher.Sig{cipher.MustSignHash(hash, s1), cipher.MustSignHash(hash, s2)},
+					},
+				},
+			}))
+
+			params.TimeLockActivationHeight = tc.timeLockActivation
+			params.MultisigActivationHeight = tc.multisigActivation
+
+			err = db.View("", func(tx *dbutil.Tx) error {
+				return bc.VerifySingleTxnHardConstraints(tx, txn, TxnSigned)
+			})
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				requireHardViolation(t, tc.err.Error(), err)
+			}
+
+			// Blocks are verified with the same rule
+			err = db.View("", func(tx *dbutil.Tx) error {
+				head, err := bc.Head(tx)
+				require.NoError(t, err)
+				uxIn, err := bc.Unspent().GetArray(tx, txn.In)
+				require.NoError(t, err)
+				return VerifyBlockTxnConstraints(txn, head.Head, uxIn)
+			})
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				requireHardViolation(t, tc.err.Error(), err)
+			}
+		})
+	}
+}
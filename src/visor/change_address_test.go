@@ -0,0 +1,162 @@
+package visor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestWalletCreateTransactionFreshChangeAddress(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	v.outputReservations = &outputReservations{}
+
+	txn := makeUnspentsTxn(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 3, params.UserVerifyTxn.MaxDropletPrecision)
+	_, softErr, err := v.InjectForeignTransaction(txn)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		sb, err := v.createBlock(tx, genTime+100)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+
+	wltDir := prepareWltDir()
+	ws, err := wallet.NewService(wallet.Config{
+		EnableWalletAPI: true,
+		CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+		WalletDir:       wltDir,
+	})
+	require.NoError(t, err)
+	v.Wallets = ws
+
+	for _, id := range []string{"reuse.wlt", "fresh.wlt"} {
+		policy := wallet.ChangeAddressPolicyReuse
+		if id == "fresh.wlt" {
+			policy = wallet.ChangeAddressPolicyFresh
+		}
+
+		_, err = ws.CreateWallet(id, wallet.Options{
+			Coin:                wallet.CoinTypeSkycoin,
+			Seed:                id,
+			GenerateN:           1,
+			Encrypt:             true,
+			Password:            []byte("pwd"),
+			ChangeAddressPolicy: policy,
+		}, nil)
+		require.NoError(t, err)
+
+		err = ws.UpdateSecrets(id, []byte("pwd"), func(w *wallet.Wallet) error {
+			return w.AddEntry(wallet.Entry{
+				Address: genAddress,
+				Public:  genPublic,
+				Secret:  genSecret,
+			})
+		})
+		require.NoError(t, err)
+	}
+
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   1e6,
+				Hours:   1,
+			},
+		},
+	}
+
+	// The change is sent back to the input address
+	txn1, _, err := v.WalletCreateTransactionSigned("reuse.wlt", []byte("pwd"), p, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Len(t, txn1.Out, 2)
+	require.Equal(t, genAddress, txn1.Out[1].Address)
+
+	// The change is sent to a new change address of the wallet, which is saved
+	txn2, _, err := v.WalletCreateTransactionSigned("fresh.wlt", []byte("pwd"), p, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Len(t, txn2.Out, 2)
+	changeAddr := txn2.Out[1].Address
+	require.NotEqual(t, genAddress, changeAddr)
+
+	w, err := wallet.Load(filepath.Join(wltDir, "fresh.wlt"))
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 3)
+	require.Equal(t, changeAddr, w.Entries[2].SkycoinAddress())
+	require.True(t, w.Entries[2].IsChangeAddress())
+
+	// Each transaction uses a new change address
+	txn3, _, err := v.WalletCreateTransactionSigned("fresh.wlt", []byte("pwd"), p, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.NotEqual(t, changeAddr, txn3.Out[1].Address)
+
+	// The wallet is not changed if the transaction can't be created
+	_, _, err = v.WalletCreateTransactionSigned("fresh.wlt", []byte("bad"), p, CreateTransactionParams{})
+	require.Equal(t, wallet.ErrInvalidPassword, err)
+
+	pBig := p
+	pBig.To = []coin.TransactionOutput{
+		{
+			Address: testutil.MakeAddress(),
+			Coins:   genesisUxs[0].Body.Coins * 2,
+			Hours:   1,
+		},
+	}
+	_, _, err = v.WalletCreateTransactionSigned("fresh.wlt", []byte("pwd"), pBig, CreateTransactionParams{})
+	require.Equal(t, transaction.ErrInsufficientBalance, err)
+
+	w, err = wallet.Load(filepath.Join(wltDir, "fresh.wlt"))
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 4)
+
+	// An explicit change address is used as is
+	explicitChange := testutil.MakeAddress()
+	pChange := p
+	pChange.ChangeAddress = &explicitChange
+	txn4, _, err := v.WalletCreateTransactionSigned("fresh.wlt", []byte("pwd"), pChange, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Equal(t, explicitChange, txn4.Out[1].Address)
+
+	// The change address is discarded if the transaction has no change
+	pNoChange := p
+	pNoChange.To = []coin.TransactionOutput{
+		{
+			Address: testutil.MakeAddress(),
+			Coins:   txn.Out[0].Coins,
+			Hours:   1,
+		},
+	}
+	txn5, _, err := v.WalletCreateTransactionSigned("fresh.wlt", []byte("pwd"), pNoChange, CreateTransactionParams{
+		UxOuts: []cipher.SHA256{unconfirmedOutputHash(txn, 0)},
+	})
+	require.NoError(t, err)
+	require.Len(t, txn5.Out, 1)
+
+	w, err = wallet.Load(filepath.Join(wltDir, "fresh.wlt"))
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 4)
+
+	// The change address of an encrypted wallet can't be generated without its password
+	_, _, err = v.WalletCreateTransaction("fresh.wlt", p, CreateTransactionParams{})
+	require.Equal(t, wallet.ErrWalletEncrypted, err)
+
+	w, err = wallet.Load(filepath.Join(wltDir, "fresh.wlt"))
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 4)
+}
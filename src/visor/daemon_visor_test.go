@@ -28,7 +28,7 @@ func setupSimpleVisor(t *testing.T, db *dbutil.DB, bc *Blockchain) *Visor {
 	cfg := NewConfig()
 	cfg.DBPath = db.Path()
 
-	pool, err := NewUnconfirmedTransactionPool(db)
+	pool, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
 	require.NoError(t, err)
 
 	return &Visor{
@@ -0,0 +1,87 @@
+package visor
+
+import (
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+// GetFrozenOutputs returns the frozen outputs of a wallet, ordered by the time they were frozen
+func (vs *Visor) GetFrozenOutputs(wltID string) ([]wallet.FrozenOutput, error) {
+	var fos []wallet.FrozenOutput
+	if err := vs.Wallets.View(wltID, func(w *wallet.Wallet) error {
+		fos = w.GetFrozenOutputs()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return fos, nil
+}
+
+// FreezeOutputs freezes unspent outputs of a wallet, so that they are never chosen to spend
+// by the transactions created by the wallet. The outputs must be unspent and owned by an address of the wallet.
+// Outputs that are already frozen keep their reason. Returns the outputs that were frozen.
+func (vs *Visor) FreezeOutputs(wltID string, uxOuts []cipher.SHA256, reason string) ([]wallet.FrozenOutput, error) {
+	if len(uxOuts) == 0 {
+		return nil, wallet.ErrNoUxOutsSpecified
+	}
+
+	var fos []wallet.FrozenOutput
+	if err := vs.Wallets.Update(wltID, func(w *wallet.Wallet) error {
+		addrs, err := w.GetSkycoinAddresses()
+		if err != nil {
+			return err
+		}
+
+		addrsMap := make(map[cipher.Address]struct{}, len(addrs))
+		for _, a := range addrs {
+			addrsMap[a] = struct{}{}
+		}
+
+		if err := vs.DB.View("FreezeOutputs", func(tx *dbutil.Tx) error {
+			uxa, err := vs.Blockchain.Unspent().GetArray(tx, uxOuts)
+			if err != nil {
+				return err
+			}
+
+			for _, ux := range uxa {
+				if _, ok := addrsMap[ux.Body.Address]; !ok {
+					return wallet.ErrUnknownUxOut
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		fos, err = w.FreezeOutputs(uxOuts, reason, time.Now().UTC())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return fos, nil
+}
+
+// UnfreezeOutputs unfreezes unspent outputs of a wallet. Outputs that are not frozen are ignored.
+// Returns the outputs that were unfrozen.
+func (vs *Visor) UnfreezeOutputs(wltID string, uxOuts []cipher.SHA256) ([]wallet.FrozenOutput, error) {
+	if len(uxOuts) == 0 {
+		return nil, wallet.ErrNoUxOutsSpecified
+	}
+
+	var fos []wallet.FrozenOutput
+	if err := vs.Wallets.Update(wltID, func(w *wallet.Wallet) error {
+		var err error
+		fos, err = w.UnfreezeOutputs(uxOuts)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return fos, nil
+}
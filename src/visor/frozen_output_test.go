@@ -0,0 +1,156 @@
+package visor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestFrozenOutputs(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	v.outputReservations = &outputReservations{}
+
+	// Split the genesis output so that the wallet has several outputs to spend
+	txn := makeUnspentsTxn(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 3, params.UserVerifyTxn.MaxDropletPrecision)
+	_, softErr, err := v.InjectForeignTransaction(txn)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		sb, err := v.createBlock(tx, genTime+100)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+
+	wltDir := prepareWltDir()
+	ws, err := wallet.NewService(wallet.Config{
+		EnableWalletAPI: true,
+		CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+		WalletDir:       wltDir,
+	})
+	require.NoError(t, err)
+	v.Wallets = ws
+
+	_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+		Coin:      wallet.CoinTypeSkycoin,
+		Seed:      "foo",
+		GenerateN: 1,
+	}, nil)
+	require.NoError(t, err)
+
+	err = ws.UpdateSecrets("foo.wlt", nil, func(w *wallet.Wallet) error {
+		return w.AddEntry(wallet.Entry{
+			Address: genAddress,
+			Public:  genPublic,
+			Secret:  genSecret,
+		})
+	})
+	require.NoError(t, err)
+
+	_, err = ws.CreateWallet("bar.wlt", wallet.Options{
+		Coin:      wallet.CoinTypeSkycoin,
+		Seed:      "bar",
+		GenerateN: 1,
+	}, nil)
+	require.NoError(t, err)
+
+	// The hash of an unspent output only depends on its block header for the genesis block
+	uxOuts := coin.CreateUnspents(coin.BlockHeader{BkSeq: 1}, txn).Hashes()
+
+	// Only the unspent outputs of the wallet can be frozen
+	_, err = v.FreezeOutputs("foo.wlt", nil, "")
+	require.Equal(t, wallet.ErrNoUxOutsSpecified, err)
+
+	_, err = v.FreezeOutputs("foo.wlt", []cipher.SHA256{genesisUxs[0].Hash()}, "")
+	require.Equal(t, blockdb.NewErrUnspentNotExist(genesisUxs[0].Hash().Hex()), err)
+
+	_, err = v.FreezeOutputs("bar.wlt", uxOuts[:1], "")
+	require.Equal(t, wallet.ErrUnknownUxOut, err)
+
+	fos, err := v.FreezeOutputs("foo.wlt", uxOuts[:2], "tainted")
+	require.NoError(t, err)
+	require.Len(t, fos, 2)
+
+	fos, err = v.GetFrozenOutputs("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, fos, 2)
+	for _, fo := range fos {
+		require.Equal(t, "tainted", fo.Reason)
+	}
+
+	// The frozen outputs are saved in the wallet file
+	w, err := wallet.Load(filepath.Join(wltDir, "foo.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, fos, w.GetFrozenOutputs())
+
+	changeAddress := testutil.MakeAddress()
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		ChangeAddress: &changeAddress,
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   1e6,
+				Hours:   1,
+			},
+		},
+	}
+
+	// Frozen outputs are not chosen to spend
+	txn1, _, err := v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Len(t, txn1.In, 1)
+	require.NotContains(t, uxOuts[:2], txn1.In[0])
+
+	// Frozen outputs can't be spent explicitly
+	_, _, err = v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{
+		UxOuts: uxOuts[:1],
+	})
+	require.Equal(t, wallet.ErrUxOutFrozen, err)
+
+	// Frozen outputs are not consolidated
+	c, err := v.WalletCreateConsolidation("foo.wlt", transaction.ConsolidateParams{
+		To: testutil.MakeAddress(),
+	}, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Len(t, c.Transactions, 1)
+	require.Len(t, c.Transactions[0].In, len(uxOuts)-2)
+	require.NotContains(t, c.Transactions[0].In, uxOuts[0])
+	require.NotContains(t, c.Transactions[0].In, uxOuts[1])
+
+	// Unfrozen outputs can be spent again
+	fos, err = v.UnfreezeOutputs("foo.wlt", uxOuts[:1])
+	require.NoError(t, err)
+	require.Len(t, fos, 1)
+	require.Equal(t, uxOuts[0], fos[0].UxOut)
+
+	txn2, _, err := v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{
+		UxOuts: uxOuts[:1],
+	})
+	require.NoError(t, err)
+	require.Equal(t, uxOuts[:1], txn2.In)
+
+	fos, err = v.GetFrozenOutputs("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, fos, 1)
+	require.Equal(t, uxOuts[1], fos[0].UxOut)
+
+	_, err = v.GetFrozenOutputs("baz.wlt")
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
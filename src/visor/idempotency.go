@@ -0,0 +1,115 @@
+package visor
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+const (
+	// DefaultIdempotencyKeyRetention is the default time the response of a request with an idempotency key is kept
+	DefaultIdempotencyKeyRetention = 24 * time.Hour
+)
+
+var (
+	// IdempotencyKeysBkt holds the responses of the API requests made with an idempotency key
+	IdempotencyKeysBkt = []byte("idempotency_keys")
+)
+
+// IdempotentResponse is the saved response of an API request made with an idempotency key
+type IdempotentResponse struct {
+	// Hash of the request, to detect a key reused for a different request
+	RequestHash string    `json:"request_hash"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	Created     time.Time `json:"created"`
+}
+
+// idempotency keys bucket
+type idempotencyKeys struct{}
+
+// idempotencyKey returns the bucket key of an idempotency key of a wallet.
+// Keys of requests that are not made for a wallet have an empty wallet ID.
+func idempotencyKey(wltID, key string) []byte {
+	return []byte(wltID + "\x00" + key)
+}
+
+func (ik *idempotencyKeys) get(tx *dbutil.Tx, wltID, key string) (*IdempotentResponse, error) {
+	var r IdempotentResponse
+	if ok, err := dbutil.GetBucketObjectJSON(tx, IdempotencyKeysBkt, idempotencyKey(wltID, key), &r); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	return &r, nil
+}
+
+func (ik *idempotencyKeys) put(tx *dbutil.Tx, wltID, key string, r IdempotentResponse) error {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, IdempotencyKeysBkt, idempotencyKey(wltID, key), buf)
+}
+
+// removeExpired removes the responses created before expiry
+func (ik *idempotencyKeys) removeExpired(tx *dbutil.Tx, expiry time.Time) error {
+	var expired [][]byte
+	if err := dbutil.ForEach(tx, IdempotencyKeysBkt, func(k, v []byte) error {
+		var r IdempotentResponse
+		if err := json.Unmarshal(v, &r); err != nil {
+			return err
+		}
+
+		if r.Created.Before(expiry) {
+			expired = append(expired, append([]byte{}, k...))
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, k := range expired {
+		if err := dbutil.Delete(tx, IdempotencyKeysBkt, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetIdempotentResponse returns the saved response of a request made with an idempotency key for a wallet,
+// or nil if there is none within the retention period
+func (vs *Visor) GetIdempotentResponse(wltID, key string) (*IdempotentResponse, error) {
+	var r *IdempotentResponse
+	if err := vs.DB.View("GetIdempotentResponse", func(tx *dbutil.Tx) error {
+		var err error
+		r, err = vs.idempotencyKeys.get(tx, wltID, key)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if r != nil && time.Since(r.Created) > vs.Config.IdempotencyKeyRetention {
+		return nil, nil
+	}
+
+	return r, nil
+}
+
+// SaveIdempotentResponse saves the response of a request made with an idempotency key for a wallet.
+// The responses older than the retention period are removed.
+func (vs *Visor) SaveIdempotentResponse(wltID, key string, r IdempotentResponse) error {
+	return vs.DB.Update("SaveIdempotentResponse", func(tx *dbutil.Tx) error {
+		if err := vs.idempotencyKeys.removeExpired(tx, r.Created.Add(-vs.Config.IdempotencyKeyRetention)); err != nil {
+			return err
+		}
+
+		return vs.idempotencyKeys.put(tx, wltID, key, r)
+	})
+}
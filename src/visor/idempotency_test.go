@@ -0,0 +1,72 @@
+package visor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+func TestIdempotentResponse(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	cfg := NewConfig()
+	cfg.IdempotencyKeyRetention = time.Hour
+	v := &Visor{
+		Config:          cfg,
+		DB:              db,
+		idempotencyKeys: &idempotencyKeys{},
+	}
+
+	r, err := v.GetIdempotentResponse("foo.wlt", "a")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	now := time.Now().UTC()
+	resp := IdempotentResponse{
+		RequestHash: "hash",
+		Status:      200,
+		ContentType: "application/json",
+		Body:        []byte(`"txid"`),
+		Created:     now,
+	}
+	require.NoError(t, v.SaveIdempotentResponse("foo.wlt", "a", resp))
+
+	r, err = v.GetIdempotentResponse("foo.wlt", "a")
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	require.Equal(t, resp.Body, r.Body)
+	require.Equal(t, resp.RequestHash, r.RequestHash)
+	require.True(t, resp.Created.Equal(r.Created))
+
+	// Keys are scoped by wallet
+	r, err = v.GetIdempotentResponse("bar.wlt", "a")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	r, err = v.GetIdempotentResponse("", "a")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	// A response older than the retention period is not returned
+	old := resp
+	old.Created = now.Add(-2 * time.Hour)
+	require.NoError(t, v.SaveIdempotentResponse("", "b", old))
+
+	r, err = v.GetIdempotentResponse("", "b")
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	// and is removed when another response is saved
+	require.NoError(t, v.SaveIdempotentResponse("", "c", resp))
+	err = db.View("", func(tx *dbutil.Tx) error {
+		n, err := dbutil.Len(tx, IdempotencyKeysBkt)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), n)
+		return nil
+	})
+	require.NoError(t, err)
+}
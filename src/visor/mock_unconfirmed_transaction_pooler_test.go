@@ -7,6 +7,7 @@ import coin "github.com/skycoin/skycoin/src/coin"
 import dbutil "github.com/skycoin/skycoin/src/visor/dbutil"
 import mock "github.com/stretchr/testify/mock"
 import params "github.com/skycoin/skycoin/src/params"
+import time "time"
 
 // MockUnconfirmedTransactionPooler is an autogenerated mock type for the UnconfirmedTransactionPooler type
 type MockUnconfirmedTransactionPooler struct {
@@ -96,6 +97,22 @@ func (_m *MockUnconfirmedTransactionPooler) Get(tx *dbutil.Tx, hash cipher.SHA25
 	return r0, r1
 }
 
+// GetEvicted provides a mock function with given fields:
+func (_m *MockUnconfirmedTransactionPooler) GetEvicted() []EvictedTransaction {
+	ret := _m.Called()
+
+	var r0 []EvictedTransaction
+	if rf, ok := ret.Get(0).(func() []EvictedTransaction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]EvictedTransaction)
+		}
+	}
+
+	return r0
+}
+
 // GetFiltered provides a mock function with given fields: tx, filter
 func (_m *MockUnconfirmedTransactionPooler) GetFiltered(tx *dbutil.Tx, filter func(UnconfirmedTransaction) bool) ([]UnconfirmedTransaction, error) {
 	ret := _m.Called(tx, filter)
@@ -188,6 +205,22 @@ func (_m *MockUnconfirmedTransactionPooler) GetKnown(tx *dbutil.Tx, txns []ciphe
 	return r0, r1
 }
 
+// GetReplaced provides a mock function with given fields:
+func (_m *MockUnconfirmedTransactionPooler) GetReplaced() []ReplacedTransaction {
+	ret := _m.Called()
+
+	var r0 []ReplacedTransaction
+	if rf, ok := ret.Get(0).(func() []ReplacedTransaction); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ReplacedTransaction)
+		}
+	}
+
+	return r0
+}
+
 // GetUnspentsOfAddr provides a mock function with given fields: tx, addr
 func (_m *MockUnconfirmedTransactionPooler) GetUnspentsOfAddr(tx *dbutil.Tx, addr cipher.Address) (coin.UxArray, error) {
 	ret := _m.Called(tx, addr)
@@ -308,6 +341,29 @@ func (_m *MockUnconfirmedTransactionPooler) Refresh(tx *dbutil.Tx, bc Blockchain
 	return r0, r1
 }
 
+// RemoveExpired provides a mock function with given fields: tx, bc, now
+func (_m *MockUnconfirmedTransactionPooler) RemoveExpired(tx *dbutil.Tx, bc Blockchainer, now time.Time) ([]ExpiredTransaction, error) {
+	ret := _m.Called(tx, bc, now)
+
+	var r0 []ExpiredTransaction
+	if rf, ok := ret.Get(0).(func(*dbutil.Tx, Blockchainer, time.Time) []ExpiredTransaction); ok {
+		r0 = rf(tx, bc, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ExpiredTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dbutil.Tx, Blockchainer, time.Time) error); ok {
+		r1 = rf(tx, bc, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveInvalid provides a mock function with given fields: tx, bc
 func (_m *MockUnconfirmedTransactionPooler) RemoveInvalid(tx *dbutil.Tx, bc Blockchainer) ([]cipher.SHA256, error) {
 	ret := _m.Called(tx, bc)
@@ -358,3 +414,35 @@ func (_m *MockUnconfirmedTransactionPooler) SetTransactionsAnnounced(tx *dbutil.
 
 	return r0
 }
+
+// VerifyTransaction provides a mock function with given fields: tx, bc, txn, verifyParams, signed
+func (_m *MockUnconfirmedTransactionPooler) VerifyTransaction(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, verifyParams params.VerifyTxn, signed TxnSignedFlag) (*coin.SignedBlock, coin.UxArray, error) {
+	ret := _m.Called(tx, bc, txn, verifyParams, signed)
+
+	var r0 *coin.SignedBlock
+	if rf, ok := ret.Get(0).(func(*dbutil.Tx, Blockchainer, coin.Transaction, params.VerifyTxn, TxnSignedFlag) *coin.SignedBlock); ok {
+		r0 = rf(tx, bc, txn, verifyParams, signed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coin.SignedBlock)
+		}
+	}
+
+	var r1 coin.UxArray
+	if rf, ok := ret.Get(1).(func(*dbutil.Tx, Blockchainer, coin.Transaction, params.VerifyTxn, TxnSignedFlag) coin.UxArray); ok {
+		r1 = rf(tx, bc, txn, verifyParams, signed)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(coin.UxArray)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*dbutil.Tx, Blockchainer, coin.Transaction, params.VerifyTxn, TxnSignedFlag) error); ok {
+		r2 = rf(tx, bc, txn, verifyParams, signed)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
@@ -22,6 +22,8 @@ type TransactionStatus struct {
 	Height uint64
 	// If confirmed, the sequence of the block in which the transaction was executed
 	BlockSeq uint64
+	// If the transaction was removed from the unconfirmed pool after reaching the maximum age, without being confirmed
+	Expired bool
 }
 
 // NewUnconfirmedTransactionStatus creates unconfirmed transaction status
@@ -33,6 +35,13 @@ func NewUnconfirmedTransactionStatus() TransactionStatus {
 	}
 }
 
+// NewExpiredTransactionStatus creates expired transaction status
+func NewExpiredTransactionStatus() TransactionStatus {
+	return TransactionStatus{
+		Expired: true,
+	}
+}
+
 // NewConfirmedTransactionStatus creates confirmed transaction status
 func NewConfirmedTransactionStatus(height, blockSeq uint64) TransactionStatus {
 	// Height starts at 1
@@ -142,7 +151,7 @@ func NewBlockchainMetadata(head coin.SignedBlock, unconfirmedLen, unspentsLen ui
 // UnconfirmedTransaction unconfirmed transaction
 type UnconfirmedTransaction struct {
 	Transaction coin.Transaction
-	// Time the txn was last received
+	// Time the txn was first received
 	Received int64
 	// Time the txn was last checked against the blockchain
 	Checked int64
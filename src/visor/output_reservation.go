@@ -0,0 +1,245 @@
+package visor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+const (
+	// DefaultOutputReservationTTL is the default time the outputs spent by a transaction created with a reservation stay reserved
+	DefaultOutputReservationTTL = 10 * time.Minute
+)
+
+var (
+	// OutputReservationsBkt holds the unspent outputs reserved by the transactions created by wallets
+	OutputReservationsBkt = []byte("output_reservations")
+)
+
+// OutputReservation is an unspent output reserved by a transaction created by a wallet.
+// A reserved output is not chosen to spend by other transactions until the reservation expires,
+// is released, or the transaction is injected.
+type OutputReservation struct {
+	UxOut    cipher.SHA256 `json:"-"`
+	WalletID string        `json:"wallet_id"`
+	// Inner hash of the transaction the output is reserved for, which doesn't change when the transaction is signed
+	TxnInnerHash cipher.SHA256 `json:"txn_inner_hash"`
+	Created      time.Time     `json:"created"`
+	Expires      time.Time     `json:"expires"`
+}
+
+// Expired returns true if the reservation has expired at time t
+func (r *OutputReservation) Expired(t time.Time) bool {
+	return !t.Before(r.Expires)
+}
+
+// output reservations bucket
+type outputReservations struct{}
+
+func (or *outputReservations) put(tx *dbutil.Tx, r *OutputReservation) error {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, OutputReservationsBkt, []byte(r.UxOut.Hex()), buf)
+}
+
+func (or *outputReservations) delete(tx *dbutil.Tx, uxOut cipher.SHA256) error {
+	return dbutil.Delete(tx, OutputReservationsBkt, []byte(uxOut.Hex()))
+}
+
+func (or *outputReservations) forEach(tx *dbutil.Tx, f func(*OutputReservation) error) error {
+	if !dbutil.Exists(tx, OutputReservationsBkt) {
+		return nil
+	}
+
+	return dbutil.ForEach(tx, OutputReservationsBkt, func(k, v []byte) error {
+		uxOut, err := cipher.SHA256FromHex(string(k))
+		if err != nil {
+			return err
+		}
+
+		var r OutputReservation
+		if err := json.Unmarshal(v, &r); err != nil {
+			return fmt.Errorf("json.Unmarshal failed: %v", err)
+		}
+		r.UxOut = uxOut
+
+		return f(&r)
+	})
+}
+
+// reserved returns the hashes of the outputs with a reservation that has not expired at time t
+func (or *outputReservations) reserved(tx *dbutil.Tx, t time.Time) (map[cipher.SHA256]struct{}, error) {
+	reserved := make(map[cipher.SHA256]struct{})
+	if err := or.forEach(tx, func(r *OutputReservation) error {
+		if !r.Expired(t) {
+			reserved[r.UxOut] = struct{}{}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return reserved, nil
+}
+
+// getReservedUxOuts returns the outputs reserved by other transactions, which are skipped when the outputs to spend
+// are chosen from addresses. An output requested explicitly by wp.UxOuts that is reserved is an error instead.
+func (vs *Visor) getReservedUxOuts(tx *dbutil.Tx, wp CreateTransactionParams) (map[cipher.SHA256]struct{}, error) {
+	reserved, err := vs.outputReservations.reserved(tx, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range wp.UxOuts {
+		if _, ok := reserved[h]; ok {
+			return nil, transaction.NewErrUxOutReserved(h)
+		}
+	}
+
+	return reserved, nil
+}
+
+// reserve reserves the inputs of a transaction created by a wallet until ttl has passed since time t.
+// Expired reservations are removed.
+func (or *outputReservations) reserve(tx *dbutil.Tx, wltID string, txn *coin.Transaction, t time.Time, ttl time.Duration) error {
+	if err := or.removeExpired(tx, t); err != nil {
+		return err
+	}
+
+	innerHash := txn.HashInner()
+	for _, in := range txn.In {
+		if err := or.put(tx, &OutputReservation{
+			UxOut:        in,
+			WalletID:     wltID,
+			TxnInnerHash: innerHash,
+			Created:      t,
+			Expires:      t.Add(ttl),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// release removes the reservations of the outputs spent by a transaction
+func (or *outputReservations) release(tx *dbutil.Tx, txn coin.Transaction) error {
+	if !dbutil.Exists(tx, OutputReservationsBkt) {
+		return nil
+	}
+
+	for _, in := range txn.In {
+		if err := or.delete(tx, in); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeExpired removes the reservations that have expired at time t
+func (or *outputReservations) removeExpired(tx *dbutil.Tx, t time.Time) error {
+	var expired []cipher.SHA256
+	if err := or.forEach(tx, func(r *OutputReservation) error {
+		if r.Expired(t) {
+			expired = append(expired, r.UxOut)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, h := range expired {
+		if err := or.delete(tx, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOutputReservations returns the reservations of a wallet that have not expired, ordered by creation time
+func (vs *Visor) GetOutputReservations(wltID string) ([]OutputReservation, error) {
+	var rs []OutputReservation
+	if err := vs.Wallets.View(wltID, func(_ *wallet.Wallet) error {
+		return vs.DB.View("GetOutputReservations", func(tx *dbutil.Tx) error {
+			rs = nil
+			now := time.Now().UTC()
+			return vs.outputReservations.forEach(tx, func(r *OutputReservation) error {
+				if r.WalletID == wltID && !r.Expired(now) {
+					rs = append(rs, *r)
+				}
+				return nil
+			})
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sortOutputReservations(rs)
+
+	return rs, nil
+}
+
+// ReleaseOutputReservations releases the reservations of a wallet for the outputs in uxOuts,
+// or all the reservations of the wallet if uxOuts is empty. Outputs not reserved by the wallet are ignored.
+// Returns the released reservations, ordered by creation time.
+func (vs *Visor) ReleaseOutputReservations(wltID string, uxOuts []cipher.SHA256) ([]OutputReservation, error) {
+	uxOutsMap := make(map[cipher.SHA256]struct{}, len(uxOuts))
+	for _, h := range uxOuts {
+		uxOutsMap[h] = struct{}{}
+	}
+
+	var released []OutputReservation
+	if err := vs.Wallets.View(wltID, func(_ *wallet.Wallet) error {
+		return vs.DB.Update("ReleaseOutputReservations", func(tx *dbutil.Tx) error {
+			released = nil
+			now := time.Now().UTC()
+			if err := vs.outputReservations.forEach(tx, func(r *OutputReservation) error {
+				if r.WalletID != wltID || r.Expired(now) {
+					return nil
+				}
+
+				if _, ok := uxOutsMap[r.UxOut]; ok || len(uxOuts) == 0 {
+					released = append(released, *r)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			for _, r := range released {
+				if err := vs.outputReservations.delete(tx, r.UxOut); err != nil {
+					return err
+				}
+			}
+
+			return vs.outputReservations.removeExpired(tx, now)
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sortOutputReservations(released)
+
+	return released, nil
+}
+
+func sortOutputReservations(rs []OutputReservation) {
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].Created.Equal(rs[j].Created) {
+			return rs[i].UxOut.Hex() < rs[j].UxOut.Hex()
+		}
+		return rs[i].Created.Before(rs[j].Created)
+	})
+}
@@ -0,0 +1,191 @@
+package visor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+	"github.com/skycoin/skycoin/src/wallet"
+)
+
+func TestOutputReservations(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	v.outputReservations = &outputReservations{}
+
+	// Split the genesis output so that the wallet has several outputs to spend
+	txn := makeUnspentsTxn(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 3, params.UserVerifyTxn.MaxDropletPrecision)
+	_, softErr, err := v.InjectForeignTransaction(txn)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		sb, err := v.createBlock(tx, genTime+100)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+
+	ws, err := wallet.NewService(wallet.Config{
+		EnableWalletAPI: true,
+		CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+		WalletDir:       prepareWltDir(),
+	})
+	require.NoError(t, err)
+	v.Wallets = ws
+
+	_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+		Coin:      wallet.CoinTypeSkycoin,
+		Seed:      "foo",
+		GenerateN: 1,
+	}, nil)
+	require.NoError(t, err)
+
+	err = ws.UpdateSecrets("foo.wlt", nil, func(w *wallet.Wallet) error {
+		return w.AddEntry(wallet.Entry{
+			Address: genAddress,
+			Public:  genPublic,
+			Secret:  genSecret,
+		})
+	})
+	require.NoError(t, err)
+
+	changeAddress := testutil.MakeAddress()
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		ChangeAddress: &changeAddress,
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   1e6,
+				Hours:   1,
+			},
+		},
+	}
+	reserve := CreateTransactionParams{
+		Reserve: true,
+	}
+
+	// A transaction created with a reservation reserves its inputs
+	txn1, _, err := v.WalletCreateTransactionSigned("foo.wlt", nil, p, reserve)
+	require.NoError(t, err)
+	require.Len(t, txn1.In, 1)
+
+	rs, err := v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	require.Equal(t, txn1.In[0], rs[0].UxOut)
+	require.Equal(t, "foo.wlt", rs[0].WalletID)
+	require.Equal(t, txn1.HashInner(), rs[0].TxnInnerHash)
+	require.Equal(t, v.Config.OutputReservationTTL, rs[0].Expires.Sub(rs[0].Created))
+
+	// Other transactions don't spend the reserved outputs
+	txn2, _, err := v.WalletCreateTransaction("foo.wlt", p, reserve)
+	require.NoError(t, err)
+	require.Len(t, txn2.In, 1)
+	require.NotEqual(t, txn1.In[0], txn2.In[0])
+
+	txn3, _, err := v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{})
+	require.NoError(t, err)
+	require.Len(t, txn3.In, 1)
+	require.NotEqual(t, txn1.In[0], txn3.In[0])
+	require.NotEqual(t, txn2.In[0], txn3.In[0])
+
+	rs, err = v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	// A reserved output requested explicitly is rejected
+	_, _, err = v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{
+		UxOuts: []cipher.SHA256{txn3.In[0], txn1.In[0]},
+	})
+	require.Equal(t, transaction.NewErrUxOutReserved(txn1.In[0]), err)
+
+	_, _, err = v.CreateTransaction(p, CreateTransactionParams{
+		UxOuts: txn2.In,
+	})
+	require.Equal(t, transaction.NewErrUxOutReserved(txn2.In[0]), err)
+
+	_, err = v.WalletCreateConsolidation("foo.wlt", transaction.ConsolidateParams{
+		To: testutil.MakeAddress(),
+	}, CreateTransactionParams{
+		UxOuts: txn1.In,
+	})
+	require.Equal(t, transaction.NewErrUxOutReserved(txn1.In[0]), err)
+
+	// The reservations of a wallet are released by output
+	released, err := v.ReleaseOutputReservations("foo.wlt", txn2.In)
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	require.Equal(t, txn2.In[0], released[0].UxOut)
+
+	rs, err = v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	require.Equal(t, txn1.In[0], rs[0].UxOut)
+
+	// An expired reservation doesn't reserve its output
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return v.outputReservations.put(tx, &OutputReservation{
+			UxOut:    txn2.In[0],
+			WalletID: "foo.wlt",
+			Created:  time.Now().Add(-time.Hour),
+			Expires:  time.Now().Add(-time.Minute),
+		})
+	})
+	require.NoError(t, err)
+
+	rs, err = v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	// Consolidations don't spend the reserved outputs, and reserve their inputs
+	c, err := v.WalletCreateConsolidation("foo.wlt", transaction.ConsolidateParams{
+		To: testutil.MakeAddress(),
+	}, reserve)
+	require.NoError(t, err)
+	require.Len(t, c.Transactions, 1)
+	require.NotContains(t, c.Transactions[0].In, txn1.In[0])
+	require.Len(t, c.Transactions[0].In, 3)
+
+	rs, err = v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, rs, 4)
+
+	released, err = v.ReleaseOutputReservations("foo.wlt", c.Transactions[0].In)
+	require.NoError(t, err)
+	require.Len(t, released, 3)
+
+	// The reservations are released when the transaction is injected
+	_, _, _, err = v.InjectUserTransaction(*txn1)
+	require.NoError(t, err)
+
+	rs, err = v.GetOutputReservations("foo.wlt")
+	require.NoError(t, err)
+	require.Empty(t, rs)
+
+	// Releasing without outputs releases all the reservations of the wallet
+	_, _, err = v.WalletCreateTransaction("foo.wlt", p, CreateTransactionParams{
+		Reserve:           true,
+		IgnoreUnconfirmed: true,
+	})
+	require.NoError(t, err)
+
+	released, err = v.ReleaseOutputReservations("foo.wlt", nil)
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+
+	_, err = v.GetOutputReservations("bar.wlt")
+	require.Equal(t, wallet.ErrWalletNotExist, err)
+}
@@ -0,0 +1,589 @@
+package visor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/util/timeutil"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+const (
+	// DefaultTxnConfirmationDepth is the default number of blocks, including its own, a transaction
+	// must be buried under to be considered confirmed by the transaction lifecycle
+	DefaultTxnConfirmationDepth = 6
+	// DefaultTxnLifecycleRetention is the default time the lifecycle of a confirmed or rejected transaction is kept
+	DefaultTxnLifecycleRetention = 7 * 24 * time.Hour
+
+	// TxnLifecycleCreated is the state of a transaction created and signed by a wallet of the node
+	TxnLifecycleCreated = "created"
+	// TxnLifecycleInjected is the state of a transaction injected to the unconfirmed pool
+	TxnLifecycleInjected = "injected"
+	// TxnLifecycleBroadcast is the state of an injected transaction broadcast to peers
+	TxnLifecycleBroadcast = "broadcast"
+	// TxnLifecycleInBlock is the state of a transaction executed in a block, with fewer confirmations than the confirmation depth
+	TxnLifecycleInBlock = "in_block"
+	// TxnLifecycleConfirmed is the state of a transaction with at least as many confirmations as the confirmation depth
+	TxnLifecycleConfirmed = "confirmed"
+	// TxnLifecycleRejected is the state of a transaction that was not accepted by the unconfirmed pool,
+	// or left the pool without being executed in a block
+	TxnLifecycleRejected = "rejected"
+
+	// TxnLifecycleReasonExpired is the rejection reason of a transaction that expired from the unconfirmed pool
+	TxnLifecycleReasonExpired = "expired"
+)
+
+var (
+	// TxnLifecyclesBkt holds the lifecycle of the transactions submitted through the API
+	TxnLifecyclesBkt = []byte("txn_lifecycles")
+	// TxnLifecyclesPendingBkt indexes the hashes of the lifecycles that are not confirmed or rejected
+	TxnLifecyclesPendingBkt = []byte("txn_lifecycles_pending")
+	// TxnLifecyclesFinalBkt indexes the confirmed and rejected lifecycles by the time they reached their state
+	TxnLifecyclesFinalBkt = []byte("txn_lifecycles_final")
+)
+
+// TransactionLifecycleEvent is a change of state of a transaction lifecycle
+type TransactionLifecycleEvent struct {
+	State string    `json:"state"`
+	Time  time.Time `json:"time"`
+	// Number of connections the transaction was broadcast to, for the broadcast state
+	Peers int `json:"peers,omitempty"`
+	// Sequence of the block the transaction was executed in, for the in_block and confirmed states
+	BlockSeq uint64 `json:"block_seq,omitempty"`
+	// Reason of the rejection, for the rejected state
+	Reason string `json:"reason,omitempty"`
+}
+
+// TransactionLifecycle is the state of a transaction submitted through the API, with the history of its state changes.
+// A transaction moves from created to injected, broadcast, in_block and confirmed, or to rejected.
+// A rejected transaction may be injected again.
+type TransactionLifecycle struct {
+	Hash cipher.SHA256 `json:"-"`
+	// ID of the wallet that created the transaction, if it was created by a wallet of the node
+	WalletID string `json:"wallet_id,omitempty"`
+	State    string `json:"state"`
+	// Number of connections the transaction was last broadcast to
+	Peers    int    `json:"peers,omitempty"`
+	BlockSeq uint64 `json:"block_seq,omitempty"`
+	// Number of blocks the transaction is buried under, including its own block
+	Confirmations uint64                      `json:"confirmations,omitempty"`
+	Reason        string                      `json:"reason,omitempty"`
+	Created       time.Time                   `json:"created"`
+	Updated       time.Time                   `json:"updated"`
+	Events        []TransactionLifecycleEvent `json:"events"`
+	// Transaction that expired from the unconfirmed pool, for a transaction rejected with TxnLifecycleReasonExpired
+	Expired *ExpiredTransaction `json:"expired,omitempty"`
+}
+
+// Final returns true if the transaction is confirmed or rejected
+func (l *TransactionLifecycle) Final() bool {
+	return l.State == TxnLifecycleConfirmed || l.State == TxnLifecycleRejected
+}
+
+// transition moves the lifecycle to the state of an event
+func (l *TransactionLifecycle) transition(e TransactionLifecycleEvent) {
+	l.State = e.State
+	l.Updated = e.Time
+	l.Reason = e.Reason
+	l.Events = append(l.Events, e)
+}
+
+// txn lifecycles bucket
+type txnLifecycles struct{}
+
+func (tl *txnLifecycles) get(tx *dbutil.Tx, hash cipher.SHA256) (*TransactionLifecycle, error) {
+	var l TransactionLifecycle
+	if ok, err := dbutil.GetBucketObjectJSON(tx, TxnLifecyclesBkt, []byte(hash.Hex()), &l); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	l.Hash = hash
+	return &l, nil
+}
+
+// txnLifecycleFinalKey returns the final index key of a confirmed or rejected lifecycle,
+// which orders the lifecycles by the time they reached their state
+func txnLifecycleFinalKey(l *TransactionLifecycle) []byte {
+	return append(dbutil.Itob(uint64(l.Updated.UnixNano())), l.Hash[:]...)
+}
+
+// put saves a lifecycle and moves it to the pending or final index according to its state
+func (tl *txnLifecycles) put(tx *dbutil.Tx, l *TransactionLifecycle) error {
+	prev, err := tl.get(tx, l.Hash)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil && prev.Final() {
+		if err := dbutil.Delete(tx, TxnLifecyclesFinalBkt, txnLifecycleFinalKey(prev)); err != nil {
+			return err
+		}
+	}
+
+	buf, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	if err := dbutil.PutBucketValue(tx, TxnLifecyclesBkt, []byte(l.Hash.Hex()), buf); err != nil {
+		return err
+	}
+
+	if !l.Final() {
+		return dbutil.PutBucketValue(tx, TxnLifecyclesPendingBkt, l.Hash[:], nil)
+	}
+
+	if err := dbutil.Delete(tx, TxnLifecyclesPendingBkt, l.Hash[:]); err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, TxnLifecyclesFinalBkt, txnLifecycleFinalKey(l), nil)
+}
+
+// pendingHashes returns the hashes of the lifecycles that are not confirmed or rejected
+func (tl *txnLifecycles) pendingHashes(tx *dbutil.Tx) ([]cipher.SHA256, error) {
+	var hashes []cipher.SHA256
+	if err := dbutil.ForEach(tx, TxnLifecyclesPendingBkt, func(k, _ []byte) error {
+		hash, err := cipher.SHA256FromBytes(k)
+		if err != nil {
+			return err
+		}
+
+		hashes = append(hashes, hash)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// removeFinalBefore removes the confirmed and rejected lifecycles that reached their state before t.
+// Only the removed lifecycles are visited.
+func (tl *txnLifecycles) removeFinalBefore(tx *dbutil.Tx, t time.Time) error {
+	bkt := tx.Bucket(TxnLifecyclesFinalBkt)
+	if bkt == nil {
+		return dbutil.NewErrBucketNotExist(TxnLifecyclesFinalBkt)
+	}
+
+	before := dbutil.Itob(uint64(t.UnixNano()))
+
+	var keys [][]byte
+	c := bkt.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:8], before) < 0; k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+
+	for _, k := range keys {
+		hash, err := cipher.SHA256FromBytes(k[8:])
+		if err != nil {
+			return err
+		}
+
+		if err := dbutil.Delete(tx, TxnLifecyclesBkt, []byte(hash.Hex())); err != nil {
+			return err
+		}
+
+		if err := dbutil.Delete(tx, TxnLifecyclesFinalBkt, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tl *txnLifecycles) forEach(tx *dbutil.Tx, f func(*TransactionLifecycle) error) error {
+	return dbutil.ForEach(tx, TxnLifecyclesBkt, func(k, v []byte) error {
+		hash, err := cipher.SHA256FromHex(string(k))
+		if err != nil {
+			return err
+		}
+
+		var l TransactionLifecycle
+		if err := json.Unmarshal(v, &l); err != nil {
+			return fmt.Errorf("json.Unmarshal failed: %v", err)
+		}
+		l.Hash = hash
+
+		return f(&l)
+	})
+}
+
+// RecordTransactionCreated records a transaction created and signed by a wallet.
+// A transaction that is already tracked is not changed.
+func (vs *Visor) RecordTransactionCreated(hash cipher.SHA256, wltID string) error {
+	return vs.DB.Update("RecordTransactionCreated", func(tx *dbutil.Tx) error {
+		if l, err := vs.txnLifecycles.get(tx, hash); err != nil {
+			return err
+		} else if l != nil {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		l := &TransactionLifecycle{
+			Hash:     hash,
+			WalletID: wltID,
+			Created:  now,
+		}
+		l.transition(TransactionLifecycleEvent{
+			State: TxnLifecycleCreated,
+			Time:  now,
+		})
+
+		return vs.txnLifecycles.put(tx, l)
+	})
+}
+
+// RecordTransactionInjected records a transaction injected to the unconfirmed pool and broadcast to peers connections
+func (vs *Visor) RecordTransactionInjected(hash cipher.SHA256, peers int) error {
+	return vs.DB.Update("RecordTransactionInjected", func(tx *dbutil.Tx) error {
+		now := time.Now().UTC()
+		l, err := vs.getOrNewTxnLifecycle(tx, hash, now)
+		if err != nil {
+			return err
+		}
+
+		switch l.State {
+		case "", TxnLifecycleCreated, TxnLifecycleRejected:
+			// Not tracked yet, created by a wallet, or rejected
+		default:
+			// Already injected
+			return nil
+		}
+
+		l.transition(TransactionLifecycleEvent{
+			State: TxnLifecycleInjected,
+			Time:  now,
+		})
+
+		if peers > 0 {
+			l.Peers = peers
+			l.transition(TransactionLifecycleEvent{
+				State: TxnLifecycleBroadcast,
+				Time:  now,
+				Peers: peers,
+			})
+		}
+
+		return vs.txnLifecycles.put(tx, l)
+	})
+}
+
+// RecordTransactionBroadcast records a broadcast of an injected transaction to peers connections
+func (vs *Visor) RecordTransactionBroadcast(hash cipher.SHA256, peers int) error {
+	if peers == 0 {
+		return nil
+	}
+
+	return vs.DB.Update("RecordTransactionBroadcast", func(tx *dbutil.Tx) error {
+		l, err := vs.txnLifecycles.get(tx, hash)
+		if err != nil {
+			return err
+		}
+
+		if l == nil {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		switch l.State {
+		case TxnLifecycleInjected:
+			l.transition(TransactionLifecycleEvent{
+				State: TxnLifecycleBroadcast,
+				Time:  now,
+				Peers: peers,
+			})
+		case TxnLifecycleBroadcast:
+			l.Updated = now
+		default:
+			return nil
+		}
+
+		l.Peers = peers
+
+		return vs.txnLifecycles.put(tx, l)
+	})
+}
+
+// RecordTransactionRejected records a transaction that was not accepted by the unconfirmed pool
+func (vs *Visor) RecordTransactionRejected(hash cipher.SHA256, reason string) error {
+	return vs.DB.Update("RecordTransactionRejected", func(tx *dbutil.Tx) error {
+		now := time.Now().UTC()
+		l, err := vs.getOrNewTxnLifecycle(tx, hash, now)
+		if err != nil {
+			return err
+		}
+
+		if l.Final() {
+			return nil
+		}
+
+		l.transition(TransactionLifecycleEvent{
+			State:  TxnLifecycleRejected,
+			Time:   now,
+			Reason: reason,
+		})
+
+		return vs.txnLifecycles.put(tx, l)
+	})
+}
+
+// recordTxnExpired rejects the lifecycle of a transaction that expired from the unconfirmed pool, and keeps the expired
+// transaction in it. Transactions received from peers have a lifecycle created, so that their expiry is kept too
+func (vs *Visor) recordTxnExpired(tx *dbutil.Tx, e ExpiredTransaction, now time.Time) error {
+	l, err := vs.getOrNewTxnLifecycle(tx, e.Transaction.Hash(), timeutil.NanoToTime(e.Received))
+	if err != nil {
+		return err
+	}
+
+	// A transaction rejected before, when it was injected through the API, expires like any other
+	if l.State == TxnLifecycleConfirmed {
+		return nil
+	}
+
+	l.Expired = &e
+	l.transition(TransactionLifecycleEvent{
+		State:  TxnLifecycleRejected,
+		Time:   now,
+		Reason: TxnLifecycleReasonExpired,
+	})
+
+	return vs.txnLifecycles.put(tx, l)
+}
+
+func (vs *Visor) getOrNewTxnLifecycle(tx *dbutil.Tx, hash cipher.SHA256, now time.Time) (*TransactionLifecycle, error) {
+	l, err := vs.txnLifecycles.get(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil {
+		l = &TransactionLifecycle{
+			Hash:    hash,
+			Created: now,
+		}
+	}
+
+	return l, nil
+}
+
+// UpdateTransactionLifecycles updates the lifecycles of the transactions that are not confirmed or rejected
+// from the blockchain and the unconfirmed pool
+func (vs *Visor) UpdateTransactionLifecycles() error {
+	return vs.DB.Update("UpdateTransactionLifecycles", func(tx *dbutil.Tx) error {
+		return vs.updateTxnLifecycles(tx, time.Now().UTC())
+	})
+}
+
+// updateTxnLifecycles moves the transactions executed in a block to in_block, and then to confirmed once they
+// reach the confirmation depth. Injected transactions that left the unconfirmed pool without being executed are rejected,
+// and so are created transactions that were not injected within the retention.
+// Only the pending lifecycles are visited, and the confirmed and rejected lifecycles older than the retention are removed.
+func (vs *Visor) updateTxnLifecycles(tx *dbutil.Tx, now time.Time) error {
+	if !dbutil.Exists(tx, TxnLifecyclesBkt) {
+		return nil
+	}
+
+	if err := vs.txnLifecycles.removeFinalBefore(tx, now.Add(-vs.Config.TxnLifecycleRetention)); err != nil {
+		return err
+	}
+
+	headSeq, ok, err := vs.Blockchain.HeadSeq(tx)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	hashes, err := vs.txnLifecycles.pendingHashes(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		l, err := vs.txnLifecycles.get(tx, hash)
+		if err != nil {
+			return err
+		}
+
+		if l == nil || l.Final() {
+			// Stale index entry
+			if err := dbutil.Delete(tx, TxnLifecyclesPendingBkt, hash[:]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		changed, err := vs.updateTxnLifecycle(tx, l, headSeq, now)
+		if err != nil {
+			return err
+		}
+
+		if changed {
+			if err := vs.txnLifecycles.put(tx, l); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (vs *Visor) updateTxnLifecycle(tx *dbutil.Tx, l *TransactionLifecycle, headSeq uint64, now time.Time) (bool, error) {
+	htxn, err := vs.history.GetTransaction(tx, l.Hash)
+	if err != nil {
+		return false, err
+	}
+
+	if htxn != nil {
+		changed := false
+		if l.State != TxnLifecycleInBlock {
+			l.BlockSeq = htxn.BlockSeq
+			l.transition(TransactionLifecycleEvent{
+				State:    TxnLifecycleInBlock,
+				Time:     now,
+				BlockSeq: htxn.BlockSeq,
+			})
+			changed = true
+		}
+
+		confirmations := headSeq - htxn.BlockSeq + 1
+		if confirmations != l.Confirmations {
+			l.Confirmations = confirmations
+			l.Updated = now
+			changed = true
+		}
+
+		if l.Confirmations >= vs.Config.TxnConfirmationDepth {
+			l.transition(TransactionLifecycleEvent{
+				State:    TxnLifecycleConfirmed,
+				Time:     now,
+				BlockSeq: htxn.BlockSeq,
+			})
+			changed = true
+		}
+
+		return changed, nil
+	}
+
+	switch l.State {
+	case TxnLifecycleCreated:
+		// A transaction created by a wallet that was never injected is rejected after the retention,
+		// so that it does not stay pending forever
+		if now.Sub(l.Created) < vs.Config.TxnLifecycleRetention {
+			return false, nil
+		}
+
+		l.transition(TransactionLifecycleEvent{
+			State:  TxnLifecycleRejected,
+			Time:   now,
+			Reason: "not injected",
+		})
+
+		return true, nil
+	case TxnLifecycleInjected, TxnLifecycleBroadcast:
+	default:
+		return false, nil
+	}
+
+	if ok, err := vs.Unconfirmed.Get(tx, l.Hash); err != nil {
+		return false, err
+	} else if ok != nil {
+		return false, nil
+	}
+
+	l.transition(TransactionLifecycleEvent{
+		State:  TxnLifecycleRejected,
+		Time:   now,
+		Reason: vs.unconfirmedRemovalReason(l.Hash),
+	})
+
+	return true, nil
+}
+
+// unconfirmedRemovalReason returns why a transaction left the unconfirmed pool without being executed in a block
+func (vs *Visor) unconfirmedRemovalReason(hash cipher.SHA256) string {
+	for _, r := range vs.Unconfirmed.GetReplaced() {
+		if r.Transaction.Hash() == hash {
+			return fmt.Sprintf("replaced in the unconfirmed pool by %s", r.ReplacedBy.Hex())
+		}
+	}
+
+	for _, e := range vs.Unconfirmed.GetEvicted() {
+		if e.Transaction.Hash() == hash {
+			return "evicted from the full unconfirmed pool"
+		}
+	}
+
+	return "removed from the unconfirmed pool"
+}
+
+// GetTransactionLifecycle returns the lifecycle of a transaction, or nil if the transaction is not tracked
+func (vs *Visor) GetTransactionLifecycle(hash cipher.SHA256) (*TransactionLifecycle, error) {
+	var l *TransactionLifecycle
+	if err := vs.DB.View("GetTransactionLifecycle", func(tx *dbutil.Tx) error {
+		var err error
+		l, err = vs.txnLifecycles.get(tx, hash)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// GetPendingTransactionLifecycles returns the lifecycles of the tracked transactions that are not confirmed or rejected
+func (vs *Visor) GetPendingTransactionLifecycles() ([]TransactionLifecycle, error) {
+	var ls []TransactionLifecycle
+	if err := vs.DB.View("GetPendingTransactionLifecycles", func(tx *dbutil.Tx) error {
+		hashes, err := vs.txnLifecycles.pendingHashes(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, h := range hashes {
+			l, err := vs.txnLifecycles.get(tx, h)
+			if err != nil {
+				return err
+			} else if l == nil {
+				return fmt.Errorf("pending txn lifecycle %s not found", h.Hex())
+			}
+
+			ls = append(ls, *l)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ls, nil
+}
+
+// GetTransactionLifecycles returns the lifecycles of the tracked transactions, ordered by creation time.
+// If wltID is not empty, only the transactions created by that wallet are returned.
+func (vs *Visor) GetTransactionLifecycles(wltID string) ([]TransactionLifecycle, error) {
+	var ls []TransactionLifecycle
+	if err := vs.DB.View("GetTransactionLifecycles", func(tx *dbutil.Tx) error {
+		return vs.txnLifecycles.forEach(tx, func(l *TransactionLifecycle) error {
+			if wltID == "" || l.WalletID == wltID {
+				ls = append(ls, *l)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ls, func(i, j int) bool {
+		return ls[i].Created.Before(ls[j].Created)
+	})
+
+	return ls, nil
+}
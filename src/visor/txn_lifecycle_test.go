@@ -0,0 +1,255 @@
+package visor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+func TestTransactionLifecycle(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	v.Config.TxnConfirmationDepth = 2
+
+	requireLifecycle := func(hash cipher.SHA256, state string, events ...string) *TransactionLifecycle {
+		l, err := v.GetTransactionLifecycle(hash)
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		require.Equal(t, hash, l.Hash)
+		require.Equal(t, state, l.State)
+		require.Len(t, l.Events, len(events))
+		for i, e := range events {
+			require.Equal(t, e, l.Events[i].State)
+		}
+		return l
+	}
+
+	txn := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 10e6)
+	hash := txn.Hash()
+
+	l, err := v.GetTransactionLifecycle(hash)
+	require.NoError(t, err)
+	require.Nil(t, l)
+
+	// A transaction created by a wallet keeps its wallet
+	require.NoError(t, v.RecordTransactionCreated(hash, "foo.wlt"))
+	require.NoError(t, v.RecordTransactionCreated(hash, "bar.wlt"))
+	l = requireLifecycle(hash, TxnLifecycleCreated, TxnLifecycleCreated)
+	require.Equal(t, "foo.wlt", l.WalletID)
+
+	_, softErr, err := v.InjectForeignTransaction(txn)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	require.NoError(t, v.RecordTransactionInjected(hash, 3))
+	l = requireLifecycle(hash, TxnLifecycleBroadcast, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast)
+	require.Equal(t, 3, l.Peers)
+
+	// A rebroadcast updates the number of peers without adding an event
+	require.NoError(t, v.RecordTransactionBroadcast(hash, 5))
+	l = requireLifecycle(hash, TxnLifecycleBroadcast, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast)
+	require.Equal(t, 5, l.Peers)
+
+	// A transaction in the pool is not changed
+	require.NoError(t, v.UpdateTransactionLifecycles())
+	requireLifecycle(hash, TxnLifecycleBroadcast, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast)
+
+	// The transaction is executed in a block
+	var sb coin.SignedBlock
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		var err error
+		sb, err = v.createBlock(tx, genTime+100)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+	require.Equal(t, coin.Transactions{txn}, sb.Body.Transactions)
+
+	// The lifecycles are updated apart from the block execution
+	requireLifecycle(hash, TxnLifecycleBroadcast, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast)
+	require.NoError(t, v.UpdateTransactionLifecycles())
+
+	l = requireLifecycle(hash, TxnLifecycleInBlock, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast, TxnLifecycleInBlock)
+	require.Equal(t, uint64(1), l.BlockSeq)
+	require.Equal(t, uint64(1), l.Confirmations)
+
+	// The transaction is confirmed once it is buried under the confirmation depth
+	child, _ := makeChildTxn(t, v, txn, genAddress, 100e6, 10e6)
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		b, err := v.Blockchain.NewBlock(tx, coin.Transactions{child}, genTime+200)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, v.signBlock(*b))
+	})
+	require.NoError(t, err)
+	require.NoError(t, v.UpdateTransactionLifecycles())
+
+	l = requireLifecycle(hash, TxnLifecycleConfirmed, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast, TxnLifecycleInBlock, TxnLifecycleConfirmed)
+	require.Equal(t, uint64(1), l.BlockSeq)
+	require.Equal(t, uint64(2), l.Confirmations)
+	require.Equal(t, uint64(1), l.Events[4].BlockSeq)
+
+	// A rejected transaction can be injected again, and is rejected once it is not in the pool
+	rejected := testutil.RandSHA256(t)
+	require.NoError(t, v.RecordTransactionRejected(rejected, "invalid"))
+	l = requireLifecycle(rejected, TxnLifecycleRejected, TxnLifecycleRejected)
+	require.Equal(t, "invalid", l.Reason)
+	require.Empty(t, l.WalletID)
+
+	require.NoError(t, v.RecordTransactionInjected(rejected, 0))
+	requireLifecycle(rejected, TxnLifecycleInjected, TxnLifecycleRejected, TxnLifecycleInjected)
+
+	require.NoError(t, v.UpdateTransactionLifecycles())
+	l = requireLifecycle(rejected, TxnLifecycleRejected, TxnLifecycleRejected, TxnLifecycleInjected, TxnLifecycleRejected)
+	require.Equal(t, "removed from the unconfirmed pool", l.Reason)
+
+	// A confirmed transaction is not rejected
+	require.NoError(t, v.RecordTransactionRejected(hash, "invalid"))
+	requireLifecycle(hash, TxnLifecycleConfirmed, TxnLifecycleCreated, TxnLifecycleInjected, TxnLifecycleBroadcast, TxnLifecycleInBlock, TxnLifecycleConfirmed)
+
+	// The lifecycles are filtered by wallet
+	ls, err := v.GetTransactionLifecycles("foo.wlt")
+	require.NoError(t, err)
+	require.Len(t, ls, 1)
+	require.Equal(t, hash, ls[0].Hash)
+
+	ls, err = v.GetTransactionLifecycles("bar.wlt")
+	require.NoError(t, err)
+	require.Empty(t, ls)
+
+	ls, err = v.GetTransactionLifecycles("")
+	require.NoError(t, err)
+	require.Len(t, ls, 2)
+	require.Equal(t, hash, ls[0].Hash)
+	require.Equal(t, rejected, ls[1].Hash)
+
+	// The lifecycles are saved in the DB
+	v2 := &Visor{
+		DB:            db,
+		txnLifecycles: &txnLifecycles{},
+	}
+	ls2, err := v2.GetTransactionLifecycles("")
+	require.NoError(t, err)
+	require.Equal(t, ls, ls2)
+
+	// A transaction injected without being created by a wallet is tracked from its injection
+	injected := testutil.RandSHA256(t)
+	require.NoError(t, v.RecordTransactionInjected(injected, 0))
+	l = requireLifecycle(injected, TxnLifecycleInjected, TxnLifecycleInjected)
+	require.Empty(t, l.WalletID)
+	require.False(t, l.Created.IsZero())
+
+	broadcast := testutil.RandSHA256(t)
+	require.NoError(t, v.RecordTransactionInjected(broadcast, 2))
+	l = requireLifecycle(broadcast, TxnLifecycleBroadcast, TxnLifecycleInjected, TxnLifecycleBroadcast)
+	require.Equal(t, 2, l.Peers)
+
+	// Injecting it again doesn't add events
+	require.NoError(t, v.RecordTransactionInjected(broadcast, 4))
+	requireLifecycle(broadcast, TxnLifecycleBroadcast, TxnLifecycleInjected, TxnLifecycleBroadcast)
+}
+
+func TestTransactionLifecycleIndexes(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, _ := setupChainVisor(t, db)
+	v.Config.TxnLifecycleRetention = time.Hour
+
+	hashSet := func(hashes []cipher.SHA256) map[cipher.SHA256]struct{} {
+		m := make(map[cipher.SHA256]struct{}, len(hashes))
+		for _, h := range hashes {
+			m[h] = struct{}{}
+		}
+		return m
+	}
+
+	requireIndexes := func(pending []cipher.SHA256, final []cipher.SHA256) {
+		err := db.View("", func(tx *dbutil.Tx) error {
+			hashes, err := v.txnLifecycles.pendingHashes(tx)
+			require.NoError(t, err)
+			require.Equal(t, hashSet(pending), hashSet(hashes))
+
+			hashes = nil
+			err = dbutil.ForEach(tx, TxnLifecyclesFinalBkt, func(k, _ []byte) error {
+				hashes = append(hashes, cipher.MustSHA256FromBytes(k[8:]))
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, hashSet(final), hashSet(hashes))
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	created := testutil.RandSHA256(t)
+	rejected := testutil.RandSHA256(t)
+	reinjected := testutil.RandSHA256(t)
+
+	require.NoError(t, v.RecordTransactionCreated(created, "foo.wlt"))
+	require.NoError(t, v.RecordTransactionRejected(rejected, "invalid"))
+	require.NoError(t, v.RecordTransactionRejected(reinjected, "invalid"))
+	requireIndexes([]cipher.SHA256{created}, []cipher.SHA256{rejected, reinjected})
+
+	// A rejected transaction injected again is pending again
+	require.NoError(t, v.RecordTransactionInjected(reinjected, 0))
+	requireIndexes([]cipher.SHA256{created, reinjected}, []cipher.SHA256{rejected})
+
+	pending, err := v.GetPendingTransactionLifecycles()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	pendingStates := map[cipher.SHA256]string{
+		pending[0].Hash: pending[0].State,
+		pending[1].Hash: pending[1].State,
+	}
+	require.Equal(t, map[cipher.SHA256]string{
+		created:    TxnLifecycleCreated,
+		reinjected: TxnLifecycleInjected,
+	}, pendingStates)
+
+	// The injected transaction is not in the pool and is rejected by the update
+	require.NoError(t, v.UpdateTransactionLifecycles())
+	requireIndexes([]cipher.SHA256{created}, []cipher.SHA256{rejected, reinjected})
+
+	// The final lifecycles are kept during the retention
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return v.updateTxnLifecycles(tx, time.Now().UTC().Add(time.Minute))
+	})
+	require.NoError(t, err)
+	requireIndexes([]cipher.SHA256{created}, []cipher.SHA256{rejected, reinjected})
+
+	// and removed after it, while a created transaction that was not injected within the retention is rejected
+	updated := time.Now().UTC().Add(2 * time.Hour)
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return v.updateTxnLifecycles(tx, updated)
+	})
+	require.NoError(t, err)
+	requireIndexes(nil, []cipher.SHA256{created})
+
+	for _, h := range []cipher.SHA256{rejected, reinjected} {
+		l, err := v.GetTransactionLifecycle(h)
+		require.NoError(t, err)
+		require.Nil(t, l)
+	}
+
+	ls, err := v.GetTransactionLifecycles("")
+	require.NoError(t, err)
+	require.Len(t, ls, 1)
+	require.Equal(t, created, ls[0].Hash)
+	require.Equal(t, TxnLifecycleRejected, ls[0].State)
+	require.Equal(t, "not injected", ls[0].Reason)
+
+	// The rejected created transaction is removed after the retention too
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return v.updateTxnLifecycles(tx, updated.Add(2*time.Hour))
+	})
+	require.NoError(t, err)
+	requireIndexes(nil, nil)
+}
@@ -1,25 +1,91 @@
 package visor
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
 	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
 	"github.com/skycoin/skycoin/src/visor/dbutil"
 )
 
+const (
+	// DefaultMaxUnconfirmedTxns is the default maximum number of transactions in the unconfirmed pool
+	DefaultMaxUnconfirmedTxns = 10000
+	// DefaultMaxUnconfirmedTxnsSize is the default maximum total size of the transactions in the unconfirmed pool, in bytes
+	DefaultMaxUnconfirmedTxnsSize = 32 * 1024 * 1024
+	// DefaultMaxUnconfirmedTxnAge is the default maximum time a transaction stays in the unconfirmed pool since it was first received
+	DefaultMaxUnconfirmedTxnAge = 72 * time.Hour
+	// maxEvictedTxns is the number of recently evicted transactions remembered by the unconfirmed pool
+	maxEvictedTxns = 100
+	// maxReplacedTxns is the number of recently replaced transactions remembered by the unconfirmed pool
+	maxReplacedTxns = 1000
+	// ReplacementFeeRate is the minimum fee rate, in coin hours per 1000 bytes, of the coin hours a transaction
+	// must burn above the transactions it replaces. It prices the relay of the replacement,
+	// so that a transaction can't be replaced over and over for one more coin hour each time.
+	ReplacementFeeRate = 10
+)
+
 var (
 	// UnconfirmedTxnsBkt holds unconfirmed transactions
 	UnconfirmedTxnsBkt = []byte("unconfirmed_txns")
 	// UnconfirmedUnspentsBkt holds unconfirmed unspent outputs
 	UnconfirmedUnspentsBkt = []byte("unconfirmed_unspents")
+	// UnconfirmedLastReceivedBkt holds the time unconfirmed transactions were last received
+	UnconfirmedLastReceivedBkt = []byte("unconfirmed_last_received")
 
 	errUpdateObjectDoesNotExist = errors.New("object does not exist in bucket")
+
+	// ErrUnconfirmedPoolFull is returned when the unconfirmed pool is full and a transaction's fee per byte
+	// is too low to evict other transactions
+	ErrUnconfirmedPoolFull = errors.New("unconfirmed transaction pool is full and the transaction fee per byte is too low to evict other transactions")
+
+	// ErrTxnReplaced is returned when a transaction was replaced in the unconfirmed pool by a transaction
+	// that burns more coin hours, and the replacement is still unconfirmed
+	ErrTxnReplaced = errors.New("transaction was replaced by an unconfirmed transaction that burns more coin hours")
+
+	// ErrTxnSpendsInvalidUnconfirmed is a soft constraint violation of a transaction that spends the outputs of
+	// an invalid unconfirmed transaction
+	ErrTxnSpendsInvalidUnconfirmed = errors.New("transaction spends the outputs of an invalid unconfirmed transaction")
+
+	unconfirmedEvictedTxns = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "skycoin",
+		Subsystem: "unconfirmed",
+		Name:      "evicted_transactions_total",
+		Help:      "Number of transactions evicted from the full unconfirmed pool",
+	})
+
+	unconfirmedReplacedTxns = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "skycoin",
+		Subsystem: "unconfirmed",
+		Name:      "replaced_transactions_total",
+		Help:      "Number of unconfirmed transactions replaced by transactions that burn more coin hours",
+	})
+
+	unconfirmedExpiredTxns = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "skycoin",
+		Subsystem: "unconfirmed",
+		Name:      "expired_transactions_total",
+		Help:      "Number of transactions removed from the unconfirmed pool after reaching the maximum age",
+	})
 )
 
+func init() {
+	prometheus.MustRegister(unconfirmedEvictedTxns)
+	prometheus.MustRegister(unconfirmedReplacedTxns)
+	prometheus.MustRegister(unconfirmedExpiredTxns)
+}
+
 //go:generate skyencoder -unexported -struct UnconfirmedTransaction
 //go:generate skyencoder -unexported -struct UxArray
 
@@ -143,6 +209,28 @@ func (txus *txnUnspents) delete(tx *dbutil.Tx, hash cipher.SHA256) error {
 	return dbutil.Delete(tx, UnconfirmedUnspentsBkt, []byte(hash.Hex()))
 }
 
+// last received times of unconfirmed transactions bucket
+type txnLastReceived struct{}
+
+func (tlr *txnLastReceived) get(tx *dbutil.Tx, hash cipher.SHA256) (int64, bool, error) {
+	v, err := dbutil.GetBucketValue(tx, UnconfirmedLastReceivedBkt, []byte(hash.Hex()))
+	if err != nil {
+		return 0, false, err
+	} else if v == nil {
+		return 0, false, nil
+	}
+
+	return int64(dbutil.Btoi(v)), true, nil
+}
+
+func (tlr *txnLastReceived) put(tx *dbutil.Tx, hash cipher.SHA256, received int64) error {
+	return dbutil.PutBucketValue(tx, UnconfirmedLastReceivedBkt, []byte(hash.Hex()), dbutil.Itob(uint64(received)))
+}
+
+func (tlr *txnLastReceived) delete(tx *dbutil.Tx, hash cipher.SHA256) error {
+	return dbutil.Delete(tx, UnconfirmedLastReceivedBkt, []byte(hash.Hex()))
+}
+
 func (txus *txnUnspents) getByAddr(tx *dbutil.Tx, a cipher.Address) (coin.UxArray, error) {
 	var uxo coin.UxArray
 
@@ -166,6 +254,52 @@ func (txus *txnUnspents) getByAddr(tx *dbutil.Tx, a cipher.Address) (coin.UxArra
 	return uxo, nil
 }
 
+// UnconfirmedPoolLimits are the limits of the unconfirmed pool. A zero value is no limit
+type UnconfirmedPoolLimits struct {
+	// Maximum number of transactions
+	MaxTxns int
+	// Maximum total size of the transactions, in bytes
+	MaxSize uint64
+	// Maximum time a transaction stays in the pool since it was first received
+	MaxAge time.Duration
+}
+
+// EvictedTransaction is an unconfirmed transaction that was evicted from the full pool
+type EvictedTransaction struct {
+	Transaction coin.Transaction
+	// Time the txn was first received
+	Received int64
+	// Time the txn was evicted
+	Evicted int64
+	// Fee of the txn in coin hours, as calculated when it entered the pool or when the pool was last refreshed
+	Fee uint64
+}
+
+// ReplacedTransaction is an unconfirmed transaction that was replaced by a transaction spending
+// the same inputs and burning more coin hours
+type ReplacedTransaction struct {
+	Transaction coin.Transaction
+	// Time the txn was first received
+	Received int64
+	// Time the txn was replaced
+	Replaced int64
+	// Fee of the txn in coin hours when it was replaced
+	Fee uint64
+	// Hash of the replacing txn
+	ReplacedBy cipher.SHA256
+}
+
+// ExpiredTransaction is an unconfirmed transaction that was removed from the pool after reaching the maximum age
+type ExpiredTransaction struct {
+	Transaction coin.Transaction
+	// Time the txn was first received
+	Received int64
+	// Time the txn was last announced
+	Announced int64
+	// Time the txn expired
+	Expired int64
+}
+
 // UnconfirmedTransactionPool manages unconfirmed transactions
 type UnconfirmedTransactionPool struct {
 	db   *dbutil.DB
@@ -174,10 +308,25 @@ type UnconfirmedTransactionPool struct {
 	// our future balance and avoid double spending our own coins
 	// Maps from Transaction.Hash() to UxArray.
 	unspent *txnUnspents
+	// Time the txns were last received, kept apart from Received which the maximum age is measured from
+	lastReceived *txnLastReceived
+	// Txns in eviction order, with the number and total size of the txns
+	fees *unconfirmedFeeIndex
+	// Txns by the outputs they create and spend
+	chainIndex *unconfirmedChainIndex
+	limits     UnconfirmedPoolLimits
+
+	// Recently evicted transactions, the most recent last
+	evicted     []EvictedTransaction
+	evictedLock sync.Mutex
+
+	// Recently replaced transactions, the most recent last
+	replaced     []ReplacedTransaction
+	replacedLock sync.Mutex
 }
 
 // NewUnconfirmedTransactionPool creates an UnconfirmedTransactionPool instance
-func NewUnconfirmedTransactionPool(db *dbutil.DB) (*UnconfirmedTransactionPool, error) {
+func NewUnconfirmedTransactionPool(db *dbutil.DB, limits UnconfirmedPoolLimits) (*UnconfirmedTransactionPool, error) {
 	if err := db.View("Check unconfirmed txn pool size", func(tx *dbutil.Tx) error {
 		n, err := dbutil.Len(tx, UnconfirmedTxnsBkt)
 		if err != nil {
@@ -191,9 +340,13 @@ func NewUnconfirmedTransactionPool(db *dbutil.DB) (*UnconfirmedTransactionPool,
 	}
 
 	return &UnconfirmedTransactionPool{
-		db:      db,
-		txns:    &unconfirmedTxns{},
-		unspent: &txnUnspents{},
+		db:           db,
+		txns:         &unconfirmedTxns{},
+		unspent:      &txnUnspents{},
+		lastReceived: &txnLastReceived{},
+		fees:         &unconfirmedFeeIndex{},
+		chainIndex:   &unconfirmedChainIndex{},
+		limits:       limits,
 	}, nil
 }
 
@@ -231,11 +384,19 @@ func (utp *UnconfirmedTransactionPool) SetTransactionsAnnounced(tx *dbutil.Tx, h
 // existed in the pool.
 // If the transaction violates hard constraints, it is rejected.
 // Soft constraints violations mark a txn as invalid, but the txn is inserted. The soft violation is returned.
+// If a valid txn burns more coin hours than all of the pooled txns that spend any of its inputs and their descendants,
+// by at least MinReplacementFeeIncrement, it replaces them.
+// Otherwise the conflicting txns are kept alongside the txn, and the block publisher chooses which one to confirm.
+// A txn that was replaced is rejected with ErrTxnReplaced while its replacement is in the pool.
+// If the pool is full, the transactions with the lowest fee per byte are evicted to make room for the txn.
+// If the txn has a lower fee per byte than the transactions that would be evicted, ErrUnconfirmedPoolFull is returned.
+// The txn may spend the outputs of other transactions in the pool, see VerifyTransaction.
 func (utp *UnconfirmedTransactionPool) InjectTransaction(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, verifyParams params.VerifyTxn) (bool, *ErrTxnViolatesSoftConstraint, error) {
 	var isValid int8 = 1
 	var softErr *ErrTxnViolatesSoftConstraint
-	if _, _, err := bc.VerifySingleTxnSoftHardConstraints(tx, txn, verifyParams, TxnSigned); err != nil {
-		logger.Warningf("bc.VerifySingleTxnSoftHardConstraints failed for txn %s: %v", txn.Hash().Hex(), err)
+	head, uxIn, err := utp.VerifyTransaction(tx, bc, txn, verifyParams, TxnSigned)
+	if err != nil {
+		logger.Warningf("utp.VerifyTransaction failed for txn %s: %v", txn.Hash().Hex(), err)
 		switch e := err.(type) {
 		case ErrTxnViolatesSoftConstraint:
 			softErr = &e
@@ -247,6 +408,16 @@ func (utp *UnconfirmedTransactionPool) InjectTransaction(tx *dbutil.Tx, bc Block
 		}
 	}
 
+	// Invalid txns are indexed with no fee, since they can't be confirmed until they are valid
+	var txnFee uint64
+	if isValid == 1 {
+		txnFee, err = fee.TransactionFee(&txn, head.Time(), uxIn)
+		if err != nil {
+			logger.Errorf("InjectTransaction calculate fee failed: %v", err)
+			return false, nil, err
+		}
+	}
+
 	hash := txn.Hash()
 	known, err := utp.txns.hasKey(tx, hash)
 	if err != nil {
@@ -254,32 +425,80 @@ func (utp *UnconfirmedTransactionPool) InjectTransaction(tx *dbutil.Tx, bc Block
 		return false, nil, err
 	}
 
-	// Update if we already have this txn
+	// Update if we already have this txn.
+	// Received is kept, so that receiving a txn again does not reset its age
 	if known {
+		now := time.Now().UTC().UnixNano()
+		var utx UnconfirmedTransaction
 		if err := utp.txns.update(tx, hash, func(utxn *UnconfirmedTransaction) error {
-			now := time.Now().UTC().UnixNano()
-			utxn.Received = now
 			utxn.Checked = now
 			utxn.IsValid = isValid
+			utx = *utxn
 			return nil
 		}); err != nil {
 			logger.Errorf("InjectTransaction update known txn failed: %v", err)
 			return false, nil, err
 		}
 
+		if err := utp.fees.put(tx, newFeeIndexEntry(utx, txnFee)); err != nil {
+			logger.Errorf("InjectTransaction update fee index failed: %v", err)
+			return false, nil, err
+		}
+
+		if err := utp.lastReceived.put(tx, hash, now); err != nil {
+			logger.Errorf("InjectTransaction put last received time failed: %v", err)
+			return false, nil, err
+		}
+
 		return true, softErr, nil
 	}
 
+	if replaced, err := utp.isReplaced(tx, hash); err != nil {
+		logger.Errorf("InjectTransaction check txn replaced failed: %v", err)
+		return false, nil, err
+	} else if replaced {
+		return false, nil, ErrTxnReplaced
+	}
+
 	utx := NewUnconfirmedTransaction(txn)
 	utx.IsValid = isValid
 
+	replaced, err := utp.replaceConflicts(tx, bc, utx)
+	if err != nil {
+		logger.Errorf("InjectTransaction replace conflicting txns failed: %v", err)
+		return false, nil, err
+	}
+
+	evicted, err := utp.evictForTransaction(tx, bc, utx, txnFee)
+	if err != nil {
+		if err != ErrUnconfirmedPoolFull {
+			logger.Errorf("InjectTransaction evict unconfirmed txns failed: %v", err)
+		}
+		return false, nil, err
+	}
+
 	// add txn to index
 	if err := utp.txns.put(tx, &utx); err != nil {
 		logger.Errorf("InjectTransaction put new unconfirmed txn failed: %v", err)
 		return false, nil, err
 	}
 
-	head, err := bc.Head(tx)
+	if err := utp.lastReceived.put(tx, hash, utx.Received); err != nil {
+		logger.Errorf("InjectTransaction put last received time failed: %v", err)
+		return false, nil, err
+	}
+
+	if err := utp.chainIndex.put(tx, txn); err != nil {
+		logger.Errorf("InjectTransaction put outputs and spends index failed: %v", err)
+		return false, nil, err
+	}
+
+	if err := utp.fees.put(tx, newFeeIndexEntry(utx, txnFee)); err != nil {
+		logger.Errorf("InjectTransaction put fee index failed: %v", err)
+		return false, nil, err
+	}
+
+	head, err = bc.Head(tx)
 	if err != nil {
 		logger.Errorf("InjectTransaction bc.Head() failed: %v", err)
 		return false, nil, err
@@ -292,9 +511,405 @@ func (utp *UnconfirmedTransactionPool) InjectTransaction(tx *dbutil.Tx, bc Block
 		return false, nil, err
 	}
 
+	utp.recordReplaced(replaced)
+	utp.recordEvicted(evicted)
+
 	return false, softErr, nil
 }
 
+// VerifyTransaction checks that txn does not violate hard or soft constraints, like Blockchainer.VerifySingleTxnSoftHardConstraints,
+// except that txn may spend the outputs of transactions in the pool.
+// The outputs of pooled transactions have no coin hours accrued before they are confirmed.
+// A txn spending the outputs of an invalid pooled transaction violates soft constraints.
+// Only the pooled ancestors of txn are loaded, found by the outputs they create.
+// If txn and its pooled ancestors, or any of its ancestors and their descendants with txn,
+// exceed UnconfirmedChainMaxTxns or UnconfirmedChainMaxSize, ErrUnconfirmedChainTooLong is returned.
+func (utp *UnconfirmedTransactionPool) VerifyTransaction(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, verifyParams params.VerifyTxn, signed TxnSignedFlag) (*coin.SignedBlock, coin.UxArray, error) {
+	head, uxIn, err := bc.VerifySingleTxnSoftHardConstraints(tx, txn, verifyParams, signed)
+	if !spendsMissingOutputs(err) {
+		return head, uxIn, err
+	}
+
+	size, err := txn.Size()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A txn whose missing inputs are not created by pooled transactions has no ancestors,
+	// and is rejected by chains.verifyTransaction
+	ancestors, err := utp.ancestors(tx, txn, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := utp.verifyDescendantLimits(tx, ancestors, txn, size); err != nil {
+		return nil, nil, err
+	}
+
+	head, err = bc.Head(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	chains := newUnconfirmedChains(head.Head, ancestors)
+
+	uxIn, err = chains.verifyTransaction(tx, bc, txn, verifyParams, signed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return head, uxIn, nil
+}
+
+// replaceConflicts removes the pooled transactions that spend any of utxn's inputs, and their descendants which spend their outputs,
+// if utxn burns more coin hours than all of them together, by at least MinReplacementFeeIncrement of its own size.
+// Only a valid transaction can replace others, and a transaction can't replace its own parents.
+// The fees are calculated at the current head time.
+// The conflicting txns are found in the index of the outputs spent by pooled transactions,
+// and only they, their descendants and the parents that create their inputs are loaded.
+// Returns the replaced transactions.
+func (utp *UnconfirmedTransactionPool) replaceConflicts(tx *dbutil.Tx, bc Blockchainer, utxn UnconfirmedTransaction) ([]ReplacedTransaction, error) {
+	if utxn.IsValid != 1 {
+		return nil, nil
+	}
+
+	conflictHashes, err := utp.conflicts(tx, utxn.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conflictHashes) == 0 {
+		return nil, nil
+	}
+
+	conflicts, err := utp.getTxns(tx, conflictHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := utp.descendants(tx, conflicts)
+	if err != nil {
+		return nil, err
+	}
+	replace := append(conflicts, descendants...)
+
+	inChains := make(map[cipher.SHA256]struct{}, len(replace))
+	for _, u := range replace {
+		inChains[u.Transaction.Hash()] = struct{}{}
+	}
+
+	parents, err := utp.parents(tx, utxn.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range parents {
+		if _, ok := inChains[p.Transaction.Hash()]; ok {
+			return nil, nil
+		}
+	}
+
+	// The fees are calculated in chains of the replaced transactions and the parents
+	// that create their unconfirmed inputs and utxn's
+	chainTxns := append([]UnconfirmedTransaction{}, replace...)
+	addParents := func(parents []UnconfirmedTransaction) {
+		for _, p := range parents {
+			if _, ok := inChains[p.Transaction.Hash()]; !ok {
+				inChains[p.Transaction.Hash()] = struct{}{}
+				chainTxns = append(chainTxns, p)
+			}
+		}
+	}
+
+	addParents(parents)
+	for _, u := range conflicts {
+		parents, err := utp.parents(tx, u.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		addParents(parents)
+	}
+
+	head, err := bc.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+	chains := newUnconfirmedChains(head.Head, chainTxns)
+
+	calcFee := chains.transactionFee(tx, bc)
+
+	fee, err := calcFee(&utxn.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	fees := make([]uint64, len(replace))
+	var replaceFee uint64
+	for i, u := range replace {
+		f, err := calcFee(&u.Transaction)
+		if err != nil {
+			switch err.(type) {
+			case blockdb.ErrUnspentNotExist:
+				// The transaction spends outputs that no longer exist and can't be confirmed
+				f = 0
+			default:
+				return nil, err
+			}
+		}
+
+		fees[i] = f
+		replaceFee, err = mathutil.AddUint64(replaceFee, f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	minFee, err := mathutil.AddUint64(replaceFee, MinReplacementFeeIncrement(utxn.Transaction.Length))
+	if err != nil || fee < minFee {
+		return nil, nil
+	}
+
+	hash := utxn.Transaction.Hash()
+	now := time.Now().UTC().UnixNano()
+	replaced := make([]ReplacedTransaction, len(replace))
+	for i, u := range replace {
+		if err := utp.removeTransaction(tx, u.Transaction.Hash()); err != nil {
+			return nil, err
+		}
+
+		replaced[i] = ReplacedTransaction{
+			Transaction: u.Transaction,
+			Received:    u.Received,
+			Replaced:    now,
+			Fee:         fees[i],
+			ReplacedBy:  hash,
+		}
+	}
+
+	return replaced, nil
+}
+
+// MinReplacementFeeIncrement returns the minimum number of coin hours a transaction of size bytes must burn
+// above the transactions it replaces, which is its size times ReplacementFeeRate, rounded up
+func MinReplacementFeeIncrement(size uint32) uint64 {
+	return (uint64(size)*ReplacementFeeRate + 999) / 1000
+}
+
+// recordReplaced logs replaced transactions and adds them to the recently replaced transactions
+func (utp *UnconfirmedTransactionPool) recordReplaced(replaced []ReplacedTransaction) {
+	if len(replaced) == 0 {
+		return
+	}
+
+	for _, r := range replaced {
+		logger.WithFields(logrus.Fields{
+			"txid":       r.Transaction.Hash().Hex(),
+			"fee":        r.Fee,
+			"replacedBy": r.ReplacedBy.Hex(),
+		}).Info("Replaced unconfirmed transaction")
+	}
+
+	unconfirmedReplacedTxns.Add(float64(len(replaced)))
+
+	utp.replacedLock.Lock()
+	defer utp.replacedLock.Unlock()
+
+	utp.replaced = append(utp.replaced, replaced...)
+	if len(utp.replaced) > maxReplacedTxns {
+		utp.replaced = append([]ReplacedTransaction{}, utp.replaced[len(utp.replaced)-maxReplacedTxns:]...)
+	}
+}
+
+// isReplaced returns true if the transaction was recently replaced and its replacement is still in the pool
+func (utp *UnconfirmedTransactionPool) isReplaced(tx *dbutil.Tx, hash cipher.SHA256) (bool, error) {
+	var replacedBy []cipher.SHA256
+	utp.replacedLock.Lock()
+	for _, r := range utp.replaced {
+		if r.Transaction.Hash() == hash {
+			replacedBy = append(replacedBy, r.ReplacedBy)
+		}
+	}
+	utp.replacedLock.Unlock()
+
+	for _, h := range replacedBy {
+		if ok, err := utp.txns.hasKey(tx, h); err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetReplaced returns the transactions recently replaced in the pool, the most recent last
+func (utp *UnconfirmedTransactionPool) GetReplaced() []ReplacedTransaction {
+	utp.replacedLock.Lock()
+	defer utp.replacedLock.Unlock()
+
+	replaced := make([]ReplacedTransaction, len(utp.replaced))
+	copy(replaced, utp.replaced)
+	return replaced
+}
+
+// evictForTransaction removes the transactions with the lowest fee per byte from the pool, until utxn fits within the pool's limits.
+// Invalid transactions are evicted first. Of transactions with the same fee per byte, the most recently received is evicted first.
+// A transaction is evicted with its descendants, which spend its outputs.
+// The transactions are visited in the order of the fee index, with the fees calculated when they entered the pool
+// or when the pool was last refreshed. txnFee is the fee of utxn.
+// If utxn would be evicted, no transaction is removed and ErrUnconfirmedPoolFull is returned.
+// Returns the evicted transactions.
+func (utp *UnconfirmedTransactionPool) evictForTransaction(tx *dbutil.Tx, bc Blockchainer, utxn UnconfirmedTransaction, txnFee uint64) ([]EvictedTransaction, error) {
+	if utp.limits.MaxTxns == 0 && utp.limits.MaxSize == 0 {
+		return nil, nil
+	}
+
+	n, size, _, err := utp.fees.stats(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(n) + 1
+	size += uint64(utxn.Transaction.Length)
+
+	if utp.withinLimits(count, size) {
+		return nil, nil
+	}
+
+	// The descendants of the evicted transactions are found in chains, which are only built
+	// once a transaction has to be evicted
+	var chains *unconfirmedChains
+	hash := utxn.Transaction.Hash()
+	txnKey := newFeeIndexEntry(utxn, txnFee).key()
+
+	var evict []feeIndexEntry
+	evicting := make(map[cipher.SHA256]struct{})
+	full := false
+	if err := utp.fees.forEach(tx, func(c feeIndexEntry) (bool, error) {
+		if utp.withinLimits(count, size) {
+			return false, nil
+		}
+
+		// utxn would be evicted before c
+		if bytes.Compare(txnKey, c.key()) < 0 {
+			full = true
+			return false, nil
+		}
+
+		if _, ok := evicting[c.hash]; ok {
+			return true, nil
+		}
+
+		if chains == nil {
+			utxns, err := utp.txns.getAll(tx)
+			if err != nil {
+				return false, err
+			}
+
+			head, err := bc.Head(tx)
+			if err != nil {
+				return false, err
+			}
+			chains = newUnconfirmedChains(head.Head, append(utxns, utxn))
+		}
+
+		for _, h := range append([]cipher.SHA256{c.hash}, chains.descendants([]cipher.SHA256{c.hash})...) {
+			if h == hash {
+				full = true
+				return false, nil
+			}
+
+			if _, ok := evicting[h]; ok {
+				continue
+			}
+
+			e := &c
+			if h != c.hash {
+				e, err = utp.fees.get(tx, h)
+				if err != nil {
+					return false, err
+				} else if e == nil {
+					return false, fmt.Errorf("unconfirmed txn %s is not in the fee index", h.Hex())
+				}
+			}
+
+			evicting[h] = struct{}{}
+			evict = append(evict, *e)
+			count--
+			size -= uint64(e.size)
+		}
+
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if full || !utp.withinLimits(count, size) {
+		return nil, ErrUnconfirmedPoolFull
+	}
+
+	now := time.Now().UTC().UnixNano()
+	evicted := make([]EvictedTransaction, len(evict))
+	for i, e := range evict {
+		if err := utp.removeTransaction(tx, e.hash); err != nil {
+			return nil, err
+		}
+
+		evicted[i] = EvictedTransaction{
+			Transaction: chains.txns[e.hash].Transaction,
+			Received:    e.received,
+			Evicted:     now,
+			Fee:         e.fee,
+		}
+	}
+
+	return evicted, nil
+}
+
+func (utp *UnconfirmedTransactionPool) withinLimits(count int, size uint64) bool {
+	if utp.limits.MaxTxns != 0 && count > utp.limits.MaxTxns {
+		return false
+	}
+	if utp.limits.MaxSize != 0 && size > utp.limits.MaxSize {
+		return false
+	}
+	return true
+}
+
+// recordEvicted logs evicted transactions and adds them to the recently evicted transactions
+func (utp *UnconfirmedTransactionPool) recordEvicted(evicted []EvictedTransaction) {
+	if len(evicted) == 0 {
+		return
+	}
+
+	for _, e := range evicted {
+		logger.WithFields(logrus.Fields{
+			"txid": e.Transaction.Hash().Hex(),
+			"fee":  e.Fee,
+			"size": e.Transaction.Length,
+		}).Info("Evicted transaction from the full unconfirmed pool")
+	}
+
+	unconfirmedEvictedTxns.Add(float64(len(evicted)))
+
+	utp.evictedLock.Lock()
+	defer utp.evictedLock.Unlock()
+
+	utp.evicted = append(utp.evicted, evicted...)
+	if len(utp.evicted) > maxEvictedTxns {
+		utp.evicted = append([]EvictedTransaction{}, utp.evicted[len(utp.evicted)-maxEvictedTxns:]...)
+	}
+}
+
+// GetEvicted returns the transactions recently evicted from the full pool, the most recent last
+func (utp *UnconfirmedTransactionPool) GetEvicted() []EvictedTransaction {
+	utp.evictedLock.Lock()
+	defer utp.evictedLock.Unlock()
+
+	evicted := make([]EvictedTransaction, len(utp.evicted))
+	copy(evicted, utp.evicted)
+	return evicted
+}
+
 // AllRawTransactions returns underlying coin.Transactions
 func (utp *UnconfirmedTransactionPool) AllRawTransactions(tx *dbutil.Tx) (coin.Transactions, error) {
 	utxns, err := utp.txns.getAll(tx)
@@ -311,10 +926,29 @@ func (utp *UnconfirmedTransactionPool) AllRawTransactions(tx *dbutil.Tx) (coin.T
 
 // Remove a single txn by hash
 func (utp *UnconfirmedTransactionPool) removeTransaction(tx *dbutil.Tx, txHash cipher.SHA256) error {
+	utxn, err := utp.txns.get(tx, txHash)
+	if err != nil {
+		return err
+	}
+
+	if utxn != nil {
+		if err := utp.chainIndex.delete(tx, utxn.Transaction); err != nil {
+			return err
+		}
+	}
+
 	if err := utp.txns.delete(tx, txHash); err != nil {
 		return err
 	}
 
+	if err := utp.lastReceived.delete(tx, txHash); err != nil {
+		return err
+	}
+
+	if err := utp.fees.delete(tx, txHash); err != nil {
+		return err
+	}
+
 	return utp.unspent.delete(tx, txHash)
 }
 
@@ -332,35 +966,68 @@ func (utp *UnconfirmedTransactionPool) RemoveTransactions(tx *dbutil.Tx, txHashe
 // Refresh checks all unconfirmed txns against the blockchain.
 // If the transaction becomes invalid it is marked invalid.
 // If the transaction becomes valid it is marked valid and is returned to the caller.
+// Parents are checked before their children, and the children of invalid transactions are invalid.
+// The fees of the transactions are recalculated at the head time, to order them for eviction.
 func (utp *UnconfirmedTransactionPool) Refresh(tx *dbutil.Tx, bc Blockchainer, verifyParams params.VerifyTxn) ([]cipher.SHA256, error) {
 	utxns, err := utp.txns.getAll(tx)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(utxns) == 0 {
+		return nil, nil
+	}
+
+	head, err := bc.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+	chains := newUnconfirmedChains(head.Head, utxns)
+	calcFee := chains.transactionFee(tx, bc)
+
 	now := time.Now().UTC()
 	var nowValid []cipher.SHA256
 
-	for _, utxn := range utxns {
+	for _, utxn := range chains.sorted() {
 		utxn.Checked = now.UnixNano()
+		hash := utxn.Transaction.Hash()
 
 		_, _, err := bc.VerifySingleTxnSoftHardConstraints(tx, utxn.Transaction, verifyParams, TxnSigned)
+		if spendsMissingOutputs(err) {
+			_, err = chains.verifyTransaction(tx, bc, utxn.Transaction, verifyParams, TxnSigned)
+		}
 
 		switch err.(type) {
 		case ErrTxnViolatesSoftConstraint, ErrTxnViolatesHardConstraint:
 			utxn.IsValid = 0
 		case nil:
 			if utxn.IsValid == 0 {
-				nowValid = append(nowValid, utxn.Transaction.Hash())
+				nowValid = append(nowValid, hash)
 			}
 			utxn.IsValid = 1
 		default:
 			return nil, err
 		}
 
+		// Update the validity seen by the children of the txn
+		chains.txns[hash].IsValid = utxn.IsValid
+
 		if err := utp.txns.put(tx, &utxn); err != nil {
 			return nil, err
 		}
+
+		// Reindex the txn with its validity and its fee at the new head time
+		var txnFee uint64
+		if utxn.IsValid == 1 {
+			txnFee, err = calcFee(&utxn.Transaction)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := utp.fees.put(tx, newFeeIndexEntry(utxn, txnFee)); err != nil {
+			return nil, err
+		}
 	}
 
 	return nowValid, nil
@@ -368,6 +1035,7 @@ func (utp *UnconfirmedTransactionPool) Refresh(tx *dbutil.Tx, bc Blockchainer, v
 
 // RemoveInvalid checks all unconfirmed txns against the blockchain.
 // If a transaction violates hard constraints it is removed from the pool.
+// The descendants of a removed transaction, which spend its outputs, are removed with it.
 // The transactions that were removed are returned.
 func (utp *UnconfirmedTransactionPool) RemoveInvalid(tx *dbutil.Tx, bc Blockchainer) ([]cipher.SHA256, error) {
 	var removeUtxns []cipher.SHA256
@@ -377,12 +1045,30 @@ func (utp *UnconfirmedTransactionPool) RemoveInvalid(tx *dbutil.Tx, bc Blockchai
 		return nil, err
 	}
 
-	for _, utxn := range utxns {
+	if len(utxns) == 0 {
+		return nil, nil
+	}
+
+	head, err := bc.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+	chains := newUnconfirmedChains(head.Head, utxns)
+
+	// Parents are checked before their children. Once a parent is removed, its outputs
+	// no longer exist and its children violate hard constraints too
+	for _, utxn := range chains.sorted() {
 		err := bc.VerifySingleTxnHardConstraints(tx, utxn.Transaction, TxnSigned)
+		if spendsMissingOutputs(err) {
+			_, err = chains.verifyHardConstraints(tx, bc, utxn.Transaction, TxnSigned)
+		}
+
 		if err != nil {
 			switch err.(type) {
 			case ErrTxnViolatesHardConstraint:
-				removeUtxns = append(removeUtxns, utxn.Transaction.Hash())
+				hash := utxn.Transaction.Hash()
+				removeUtxns = append(removeUtxns, hash)
+				chains.remove(hash)
 			default:
 				return nil, err
 			}
@@ -396,14 +1082,94 @@ func (utp *UnconfirmedTransactionPool) RemoveInvalid(tx *dbutil.Tx, bc Blockchai
 	return removeUtxns, nil
 }
 
-// FilterKnown returns txn hashes with known ones removed
+// RemoveExpired removes the transactions that were first received more than the pool's maximum age before now.
+// Receiving a transaction again does not extend its age.
+// The descendants of an expired transaction, which spend its outputs, expire with it.
+// The transactions that expired are returned, and are kept by the caller in their transaction lifecycles.
+func (utp *UnconfirmedTransactionPool) RemoveExpired(tx *dbutil.Tx, bc Blockchainer, now time.Time) ([]ExpiredTransaction, error) {
+	if utp.limits.MaxAge == 0 {
+		return nil, nil
+	}
+
+	utxns, err := utp.txns.getAll(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(utxns) == 0 {
+		return nil, nil
+	}
+
+	cutoff := now.Add(-utp.limits.MaxAge).UnixNano()
+	var expiredHashes []cipher.SHA256
+	for _, utxn := range utxns {
+		if utxn.Received < cutoff {
+			expiredHashes = append(expiredHashes, utxn.Transaction.Hash())
+		}
+	}
+
+	if len(expiredHashes) == 0 {
+		return nil, nil
+	}
+
+	head, err := bc.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+	chains := newUnconfirmedChains(head.Head, utxns)
+	expiredHashes = append(expiredHashes, chains.descendants(expiredHashes)...)
+
+	expired := make([]ExpiredTransaction, len(expiredHashes))
+	for i, h := range expiredHashes {
+		utxn := chains.txns[h]
+		expired[i] = ExpiredTransaction{
+			Transaction: utxn.Transaction,
+			Received:    utxn.Received,
+			Announced:   utxn.Announced,
+			Expired:     now.UnixNano(),
+		}
+	}
+
+	if err := utp.RemoveTransactions(tx, expiredHashes); err != nil {
+		return nil, err
+	}
+
+	utp.recordExpired(expired)
+
+	return expired, nil
+}
+
+// recordExpired logs expired transactions and counts them
+func (utp *UnconfirmedTransactionPool) recordExpired(expired []ExpiredTransaction) {
+	if len(expired) == 0 {
+		return
+	}
+
+	for _, e := range expired {
+		logger.WithFields(logrus.Fields{
+			"txid":     e.Transaction.Hash().Hex(),
+			"received": e.Received,
+		}).Info("Expired transaction from the unconfirmed pool")
+	}
+
+	unconfirmedExpiredTxns.Add(float64(len(expired)))
+}
+
+// FilterKnown returns txn hashes with known ones removed.
+// Txns replaced by a txn that is still in the pool are known, so that they are not requested from peers again.
 func (utp *UnconfirmedTransactionPool) FilterKnown(tx *dbutil.Tx, txns []cipher.SHA256) ([]cipher.SHA256, error) {
 	var unknown []cipher.SHA256
 
 	for _, h := range txns {
 		if hasKey, err := utp.txns.hasKey(tx, h); err != nil {
 			return nil, err
-		} else if !hasKey {
+		} else if hasKey {
+			continue
+		}
+
+		if replaced, err := utp.isReplaced(tx, h); err != nil {
+			return nil, err
+		} else if !replaced {
 			unknown = append(unknown, h)
 		}
 	}
@@ -426,34 +1192,28 @@ func (utp *UnconfirmedTransactionPool) GetKnown(tx *dbutil.Tx, txns []cipher.SHA
 	return known, nil
 }
 
-// RecvOfAddresses returns unconfirmed receiving uxouts of addresses
+// RecvOfAddresses returns unconfirmed receiving uxouts of addresses.
+// Outputs spent by other unconfirmed transactions are excluded
 func (utp *UnconfirmedTransactionPool) RecvOfAddresses(tx *dbutil.Tx, bh coin.BlockHeader, addrs []cipher.Address) (coin.AddressUxOuts, error) {
-	addrm := make(map[cipher.Address]struct{}, len(addrs))
-	for _, addr := range addrs {
-		addrm[addr] = struct{}{}
+	txns, err := utp.AllRawTransactions(tx)
+	if err != nil {
+		return nil, err
 	}
 
-	auxs := make(coin.AddressUxOuts, len(addrs))
-	if err := utp.txns.forEach(tx, func(_ cipher.SHA256, txn UnconfirmedTransaction) error {
-		for i, o := range txn.Transaction.Out {
-			if _, ok := addrm[o.Address]; ok {
-				uxout, err := coin.CreateUnspent(bh, txn.Transaction, i)
-				if err != nil {
-					return err
-				}
-
-				auxs[o.Address] = append(auxs[o.Address], uxout)
-			}
-		}
-		return nil
-	}); err != nil {
+	auxs, err := txnOutputsForAddrs(bh, addrs, txns)
+	if err != nil {
 		return nil, err
 	}
 
+	if auxs == nil {
+		auxs = make(coin.AddressUxOuts, len(addrs))
+	}
+
 	return auxs, nil
 }
 
-// txnOutputsForAddrs returns unspent outputs assigned to addresses in addrs, created by a set of transactions
+// txnOutputsForAddrs returns unspent outputs assigned to addresses in addrs, created by a set of transactions.
+// Outputs spent by other transactions of the set are excluded
 func txnOutputsForAddrs(bh coin.BlockHeader, addrs []cipher.Address, txns []coin.Transaction) (coin.AddressUxOuts, error) {
 	if len(txns) == 0 || len(addrs) == 0 {
 		return nil, nil
@@ -464,6 +1224,8 @@ func txnOutputsForAddrs(bh coin.BlockHeader, addrs []cipher.Address, txns []coin
 		addrm[addr] = struct{}{}
 	}
 
+	_, spent := unconfirmedInputs(bh, txns)
+
 	auxs := make(coin.AddressUxOuts, len(addrs))
 
 	for _, txn := range txns {
@@ -474,6 +1236,10 @@ func txnOutputsForAddrs(bh coin.BlockHeader, addrs []cipher.Address, txns []coin
 					return nil, err
 				}
 
+				if _, ok := spent[unconfirmedOutputHash(txn, i)]; ok {
+					continue
+				}
+
 				auxs[o.Address] = append(auxs[o.Address], uxout)
 			}
 		}
@@ -483,16 +1249,24 @@ func txnOutputsForAddrs(bh coin.BlockHeader, addrs []cipher.Address, txns []coin
 }
 
 // GetIncomingOutputs returns all predicted incoming outputs.
+// Outputs spent by other unconfirmed transactions are excluded
 func (utp *UnconfirmedTransactionPool) GetIncomingOutputs(tx *dbutil.Tx, bh coin.BlockHeader) (coin.UxArray, error) {
-	var outs coin.UxArray
-
-	if err := utp.txns.forEach(tx, func(_ cipher.SHA256, txn UnconfirmedTransaction) error {
-		outs = append(outs, coin.CreateUnspents(bh, txn.Transaction)...)
-		return nil
-	}); err != nil {
+	txns, err := utp.AllRawTransactions(tx)
+	if err != nil {
 		return nil, err
 	}
 
+	_, spent := unconfirmedInputs(bh, txns)
+
+	var outs coin.UxArray
+	for _, txn := range txns {
+		for i, ux := range coin.CreateUnspents(bh, txn) {
+			if _, ok := spent[unconfirmedOutputHash(txn, i)]; !ok {
+				outs = append(outs, ux)
+			}
+		}
+	}
+
 	return outs, nil
 }
 
@@ -501,6 +1275,23 @@ func (utp *UnconfirmedTransactionPool) Get(tx *dbutil.Tx, hash cipher.SHA256) (*
 	return utp.txns.get(tx, hash)
 }
 
+// LastReceived returns the time the unconfirmed transaction of given tx hash was last received.
+// Returns false if the transaction is not in the pool.
+func (utp *UnconfirmedTransactionPool) LastReceived(tx *dbutil.Tx, hash cipher.SHA256) (int64, bool, error) {
+	received, ok, err := utp.lastReceived.get(tx, hash)
+	if err != nil || ok {
+		return received, ok, err
+	}
+
+	// Transactions pooled before the last received times were recorded were last received when first received
+	utxn, err := utp.txns.get(tx, hash)
+	if err != nil || utxn == nil {
+		return 0, false, err
+	}
+
+	return utxn.Received, true, nil
+}
+
 // GetFiltered returns all transactions that can pass the filter
 func (utp *UnconfirmedTransactionPool) GetFiltered(tx *dbutil.Tx, filter func(UnconfirmedTransaction) bool) ([]UnconfirmedTransaction, error) {
 	var txns []UnconfirmedTransaction
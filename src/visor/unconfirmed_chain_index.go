@@ -0,0 +1,362 @@
+package visor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+const (
+	// UnconfirmedChainMaxTxns is the maximum number of transactions in the pool that a transaction and its unconfirmed
+	// ancestors may count, and that an unconfirmed transaction and its descendants may count
+	UnconfirmedChainMaxTxns = 25
+	// UnconfirmedChainMaxSize is the maximum total size, in bytes, of a transaction and its unconfirmed ancestors,
+	// and of an unconfirmed transaction and its descendants
+	UnconfirmedChainMaxSize = 101000
+)
+
+var (
+	unconfirmedChainIndexKey = []byte("chain_index")
+
+	// UnconfirmedOutputsBkt maps the hashes of the outputs created by unconfirmed transactions to the transactions
+	UnconfirmedOutputsBkt = []byte("unconfirmed_outputs")
+	// UnconfirmedSpendsBkt indexes unconfirmed transactions by the outputs they spend.
+	// A key is the hash of the output followed by the hash of the transaction, with no value
+	UnconfirmedSpendsBkt = []byte("unconfirmed_spends")
+
+	// ErrUnconfirmedChainTooLong is returned when a transaction would exceed the limits of unconfirmed ancestors
+	// or descendants, UnconfirmedChainMaxTxns and UnconfirmedChainMaxSize
+	ErrUnconfirmedChainTooLong = errors.New("transaction exceeds the limits of unconfirmed ancestors or descendants in the unconfirmed pool")
+)
+
+// unconfirmed transactions outputs and spends index buckets
+type unconfirmedChainIndex struct{}
+
+func spendKey(ux, hash cipher.SHA256) []byte {
+	return append(append(make([]byte, 0, len(ux)+len(hash)), ux[:]...), hash[:]...)
+}
+
+// put indexes the outputs created and spent by txn
+func (ci *unconfirmedChainIndex) put(tx *dbutil.Tx, txn coin.Transaction) error {
+	hash := txn.Hash()
+	for i := range txn.Out {
+		ux := unconfirmedOutputHash(txn, i)
+		if err := dbutil.PutBucketValue(tx, UnconfirmedOutputsBkt, ux[:], hash[:]); err != nil {
+			return err
+		}
+	}
+
+	for _, h := range txn.In {
+		if err := dbutil.PutBucketValue(tx, UnconfirmedSpendsBkt, spendKey(h, hash), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delete removes the outputs created and spent by txn from the index
+func (ci *unconfirmedChainIndex) delete(tx *dbutil.Tx, txn coin.Transaction) error {
+	hash := txn.Hash()
+	for i := range txn.Out {
+		ux := unconfirmedOutputHash(txn, i)
+		if err := dbutil.Delete(tx, UnconfirmedOutputsBkt, ux[:]); err != nil {
+			return err
+		}
+	}
+
+	for _, h := range txn.In {
+		if err := dbutil.Delete(tx, UnconfirmedSpendsBkt, spendKey(h, hash)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// creator returns the hash of the pooled transaction that creates the output ux.
+// Returns false if no pooled transaction creates it
+func (ci *unconfirmedChainIndex) creator(tx *dbutil.Tx, ux cipher.SHA256) (cipher.SHA256, bool, error) {
+	v, err := dbutil.GetBucketValue(tx, UnconfirmedOutputsBkt, ux[:])
+	if err != nil {
+		return cipher.SHA256{}, false, err
+	} else if v == nil {
+		return cipher.SHA256{}, false, nil
+	}
+
+	hash, err := cipher.SHA256FromBytes(v)
+	if err != nil {
+		return cipher.SHA256{}, false, err
+	}
+
+	return hash, true, nil
+}
+
+// spenders returns the hashes of the pooled transactions that spend the output ux
+func (ci *unconfirmedChainIndex) spenders(tx *dbutil.Tx, ux cipher.SHA256) ([]cipher.SHA256, error) {
+	bkt := tx.Bucket(UnconfirmedSpendsBkt)
+	if bkt == nil {
+		return nil, dbutil.NewErrBucketNotExist(UnconfirmedSpendsBkt)
+	}
+
+	var hashes []cipher.SHA256
+	c := bkt.Cursor()
+	for k, _ := c.Seek(ux[:]); k != nil && bytes.HasPrefix(k, ux[:]); k, _ = c.Next() {
+		hash, err := cipher.SHA256FromBytes(k[len(ux):])
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+// isBuilt returns true if the index was built for the transactions already in the pool
+func (ci *unconfirmedChainIndex) isBuilt(tx *dbutil.Tx) (bool, error) {
+	v, err := dbutil.GetBucketValue(tx, UnconfirmedMetaBkt, unconfirmedChainIndexKey)
+	if err != nil {
+		return false, err
+	}
+	return v != nil, nil
+}
+
+func (ci *unconfirmedChainIndex) setBuilt(tx *dbutil.Tx) error {
+	return dbutil.PutBucketValue(tx, UnconfirmedMetaBkt, unconfirmedChainIndexKey, dbutil.Itob(1))
+}
+
+// maybeBuildChainIndex builds the outputs and spends index of the pool if it was not built yet,
+// for a pool saved by a version that did not index them
+func (utp *UnconfirmedTransactionPool) maybeBuildChainIndex(tx *dbutil.Tx) error {
+	if ok, err := utp.chainIndex.isBuilt(tx); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	logger.Info("Building unconfirmed outputs and spends index")
+
+	for _, b := range [][]byte{UnconfirmedOutputsBkt, UnconfirmedSpendsBkt} {
+		if err := dbutil.Reset(tx, b); err != nil {
+			return err
+		}
+	}
+
+	if err := utp.txns.forEach(tx, func(_ cipher.SHA256, utxn UnconfirmedTransaction) error {
+		return utp.chainIndex.put(tx, utxn.Transaction)
+	}); err != nil {
+		return err
+	}
+
+	return utp.chainIndex.setBuilt(tx)
+}
+
+// children returns the hashes of the pooled transactions that spend the outputs of txn
+func (utp *UnconfirmedTransactionPool) children(tx *dbutil.Tx, txn coin.Transaction) ([]cipher.SHA256, error) {
+	var children []cipher.SHA256
+	for i := range txn.Out {
+		spenders, err := utp.chainIndex.spenders(tx, unconfirmedOutputHash(txn, i))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range spenders {
+			if !containsHash(children, h) {
+				children = append(children, h)
+			}
+		}
+	}
+
+	return children, nil
+}
+
+// ancestors returns the pooled transactions that create the inputs of txn, directly or through other pooled transactions.
+// Returns ErrUnconfirmedChainTooLong once txn and its ancestors exceed UnconfirmedChainMaxTxns or UnconfirmedChainMaxSize,
+// so that a long chain is not loaded
+func (utp *UnconfirmedTransactionPool) ancestors(tx *dbutil.Tx, txn coin.Transaction, size uint32) ([]UnconfirmedTransaction, error) {
+	var ancestors []UnconfirmedTransaction
+	seen := make(map[cipher.SHA256]struct{})
+	count := 1
+	totalSize := uint64(size)
+
+	queue := []coin.Transaction{txn}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+
+		for _, h := range t.In {
+			p, ok, err := utp.chainIndex.creator(tx, h)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+
+			utxn, err := utp.txns.get(tx, p)
+			if err != nil {
+				return nil, err
+			} else if utxn == nil {
+				return nil, errors.New("unconfirmed output index refers to a transaction that is not in the pool")
+			}
+
+			count++
+			totalSize += uint64(utxn.Transaction.Length)
+			if count > UnconfirmedChainMaxTxns || totalSize > UnconfirmedChainMaxSize {
+				return nil, ErrUnconfirmedChainTooLong
+			}
+
+			ancestors = append(ancestors, *utxn)
+			queue = append(queue, utxn.Transaction)
+		}
+	}
+
+	return ancestors, nil
+}
+
+// verifyDescendantLimits checks that each of the pooled transactions ancestors, with its descendants and a new
+// descendant txn of size bytes, is within UnconfirmedChainMaxTxns and UnconfirmedChainMaxSize.
+// The descendants are walked in the spends index, and the walk stops once a limit is exceeded
+func (utp *UnconfirmedTransactionPool) verifyDescendantLimits(tx *dbutil.Tx, ancestors []UnconfirmedTransaction, txn coin.Transaction, size uint32) error {
+	hash := txn.Hash()
+	for _, a := range ancestors {
+		seen := map[cipher.SHA256]struct{}{
+			hash: {},
+		}
+		count := 2
+		totalSize := uint64(a.Transaction.Length) + uint64(size)
+
+		queue := []coin.Transaction{a.Transaction}
+		for len(queue) > 0 {
+			children, err := utp.children(tx, queue[0])
+			if err != nil {
+				return err
+			}
+			queue = queue[1:]
+
+			for _, h := range children {
+				if _, ok := seen[h]; ok {
+					continue
+				}
+				seen[h] = struct{}{}
+
+				utxn, err := utp.txns.get(tx, h)
+				if err != nil {
+					return err
+				} else if utxn == nil {
+					return errors.New("unconfirmed spends index refers to a transaction that is not in the pool")
+				}
+
+				count++
+				totalSize += uint64(utxn.Transaction.Length)
+				if count > UnconfirmedChainMaxTxns || totalSize > UnconfirmedChainMaxSize {
+					return ErrUnconfirmedChainTooLong
+				}
+
+				queue = append(queue, utxn.Transaction)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getTxns returns the pooled transactions of hashes, which the index refers to
+func (utp *UnconfirmedTransactionPool) getTxns(tx *dbutil.Tx, hashes []cipher.SHA256) ([]UnconfirmedTransaction, error) {
+	utxns := make([]UnconfirmedTransaction, len(hashes))
+	for i, h := range hashes {
+		utxn, err := utp.txns.get(tx, h)
+		if err != nil {
+			return nil, err
+		} else if utxn == nil {
+			return nil, fmt.Errorf("unconfirmed txn %s is indexed but not in the pool", h.Hex())
+		}
+
+		utxns[i] = *utxn
+	}
+
+	return utxns, nil
+}
+
+// parents returns the pooled transactions that create the inputs of txn
+func (utp *UnconfirmedTransactionPool) parents(tx *dbutil.Tx, txn coin.Transaction) ([]UnconfirmedTransaction, error) {
+	var hashes []cipher.SHA256
+	for _, h := range txn.In {
+		p, ok, err := utp.chainIndex.creator(tx, h)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok && !containsHash(hashes, p) {
+			hashes = append(hashes, p)
+		}
+	}
+
+	return utp.getTxns(tx, hashes)
+}
+
+// conflicts returns the hashes of the pooled transactions, other than txn, that spend any of the inputs of txn
+func (utp *UnconfirmedTransactionPool) conflicts(tx *dbutil.Tx, txn coin.Transaction) ([]cipher.SHA256, error) {
+	hash := txn.Hash()
+
+	var conflicts []cipher.SHA256
+	for _, h := range txn.In {
+		spenders, err := utp.chainIndex.spenders(tx, h)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range spenders {
+			if s != hash && !containsHash(conflicts, s) {
+				conflicts = append(conflicts, s)
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// descendants returns the pooled transactions that spend the outputs of utxns, directly or through other
+// pooled transactions, the nearest first. The transactions of utxns are not included
+func (utp *UnconfirmedTransactionPool) descendants(tx *dbutil.Tx, utxns []UnconfirmedTransaction) ([]UnconfirmedTransaction, error) {
+	seen := make(map[cipher.SHA256]struct{}, len(utxns))
+	for _, u := range utxns {
+		seen[u.Transaction.Hash()] = struct{}{}
+	}
+
+	var descendants []UnconfirmedTransaction
+	queue := append([]UnconfirmedTransaction{}, utxns...)
+	for len(queue) > 0 {
+		children, err := utp.children(tx, queue[0].Transaction)
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		var hashes []cipher.SHA256
+		for _, h := range children {
+			if _, ok := seen[h]; !ok {
+				seen[h] = struct{}{}
+				hashes = append(hashes, h)
+			}
+		}
+
+		childTxns, err := utp.getTxns(tx, hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		descendants = append(descendants, childTxns...)
+		queue = append(queue, childTxns...)
+	}
+
+	return descendants, nil
+}
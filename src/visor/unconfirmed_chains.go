@@ -0,0 +1,509 @@
+package visor
+
+import (
+	"math"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/params"
+	"github.com/skycoin/skycoin/src/util/fee"
+	"github.com/skycoin/skycoin/src/util/mathutil"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+/*
+
+unconfirmed_chains.go: Transactions of the unconfirmed pool that spend the outputs of other pooled transactions
+
+A transaction in the unconfirmed pool may spend the outputs created by other pooled transactions, its parents.
+This lets a user spend the change of a pending transaction before it is confirmed.
+
+Blocks verify their transactions against the unspent pool of the previous block, so a transaction
+can't be confirmed in the same block as its parents. A child waits in the pool until all of its
+parents are confirmed, and is included in a later block.
+
+The outputs of a pooled transaction are created as if they were confirmed in the block following
+the head block, at the head block's time. They have no coin hours accrued while they are unconfirmed,
+so a child can't burn or spend coin hours that its inputs won't have once they are confirmed.
+
+When creating a block, a transaction is ranked by the fee per kilobyte of its package, the transaction
+and its descendants, if higher than its own. A child burning many coin hours can therefore pay for
+the confirmation of a parent burning few, and be confirmed itself in the following block.
+
+A transaction and its unconfirmed ancestors may count at most UnconfirmedChainMaxTxns transactions of
+UnconfirmedChainMaxSize bytes, and so may an unconfirmed transaction and its descendants, as in Bitcoin.
+The ancestors of a new transaction are found through an index of the outputs created by pooled transactions,
+and its ancestors' descendants through an index of the outputs spent by pooled transactions.
+
+A child of an invalid transaction is invalid too. When a parent is removed from the pool,
+because it was double spent, evicted or replaced, its children are removed with it.
+
+*/
+
+// unconfirmedOutputs returns the outputs created by txns, as if they were confirmed in the block following head.
+// Maps the hash of each output to the output.
+func unconfirmedOutputs(head coin.BlockHeader, txns coin.Transactions) map[cipher.SHA256]coin.UxOut {
+	next := coin.BlockHeader{
+		BkSeq: head.BkSeq + 1,
+		Time:  head.Time,
+	}
+
+	outputs := make(map[cipher.SHA256]coin.UxOut)
+	for _, txn := range txns {
+		for _, ux := range coin.CreateUnspents(next, txn) {
+			outputs[ux.Hash()] = ux
+		}
+	}
+
+	return outputs
+}
+
+// unconfirmedOutputHash returns the hash of the output i of txn, once txn is confirmed.
+// coin.CreateUnspent does not set the source transaction of the outputs of the genesis block,
+// so its outputs have a different hash when head is the genesis block
+func unconfirmedOutputHash(txn coin.Transaction, i int) cipher.SHA256 {
+	body := coin.UxBody{
+		SrcTransaction: txn.Hash(),
+		Address:        txn.Out[i].Address,
+		Coins:          txn.Out[i].Coins,
+		Hours:          txn.Out[i].Hours,
+	}
+	return body.Hash()
+}
+
+// unconfirmedInputs returns the inputs of txns that are in the unspent pool, and the set of inputs
+// of txns that are created by other transactions of txns
+func unconfirmedInputs(head coin.BlockHeader, txns coin.Transactions) ([]cipher.SHA256, map[cipher.SHA256]struct{}) {
+	outputs := unconfirmedOutputs(head, txns)
+
+	var confirmed []cipher.SHA256
+	unconfirmed := make(map[cipher.SHA256]struct{})
+	for _, txn := range txns {
+		for _, h := range txn.In {
+			if _, ok := outputs[h]; ok {
+				unconfirmed[h] = struct{}{}
+			} else {
+				confirmed = append(confirmed, h)
+			}
+		}
+	}
+
+	return confirmed, unconfirmed
+}
+
+// spendsUnconfirmed returns true if txn spends any of the unconfirmed outputs of spends
+func spendsUnconfirmed(txn coin.Transaction, spends map[cipher.SHA256]struct{}) bool {
+	for _, h := range txn.In {
+		if _, ok := spends[h]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// spendsMissingOutputs returns true if err is a hard constraint violation caused by
+// spending outputs that are not in the unspent pool
+func spendsMissingOutputs(err error) bool {
+	e, ok := err.(ErrTxnViolatesHardConstraint)
+	if !ok {
+		return false
+	}
+
+	_, ok = e.Err.(blockdb.ErrUnspentNotExist)
+	return ok
+}
+
+// unconfirmedChains indexes the transactions of the unconfirmed pool by the outputs they create
+type unconfirmedChains struct {
+	head coin.BlockHeader
+	txns map[cipher.SHA256]*UnconfirmedTransaction
+	// Hashes of the transactions, in the order they were added
+	hashes []cipher.SHA256
+	// Maps the hash of an output created by a pooled transaction to the output
+	outputs map[cipher.SHA256]coin.UxOut
+	// Maps the hash of a pooled transaction to the transactions that spend its outputs
+	children map[cipher.SHA256][]cipher.SHA256
+}
+
+// newUnconfirmedChains creates unconfirmedChains of the pooled transactions utxns on top of the head block
+func newUnconfirmedChains(head coin.BlockHeader, utxns []UnconfirmedTransaction) *unconfirmedChains {
+	c := &unconfirmedChains{
+		head:     head,
+		txns:     make(map[cipher.SHA256]*UnconfirmedTransaction, len(utxns)),
+		hashes:   make([]cipher.SHA256, 0, len(utxns)),
+		outputs:  make(map[cipher.SHA256]coin.UxOut),
+		children: make(map[cipher.SHA256][]cipher.SHA256),
+	}
+
+	// All outputs are indexed before the children, since utxns is not ordered by dependency
+	for _, u := range utxns {
+		c.addOutputs(u)
+	}
+	for _, u := range utxns {
+		c.addChild(u.Transaction)
+	}
+
+	return c
+}
+
+// newUnconfirmedChains creates unconfirmedChains of the transactions in the pool
+func (utp *UnconfirmedTransactionPool) newUnconfirmedChains(tx *dbutil.Tx, bc Blockchainer) (*unconfirmedChains, error) {
+	utxns, err := utp.txns.getAll(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := bc.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUnconfirmedChains(head.Head, utxns), nil
+}
+
+func (c *unconfirmedChains) addOutputs(utxn UnconfirmedTransaction) {
+	hash := utxn.Transaction.Hash()
+	c.txns[hash] = &utxn
+	c.hashes = append(c.hashes, hash)
+
+	for h, ux := range unconfirmedOutputs(c.head, coin.Transactions{utxn.Transaction}) {
+		c.outputs[h] = ux
+	}
+}
+
+func (c *unconfirmedChains) addChild(txn coin.Transaction) {
+	hash := txn.Hash()
+	for _, p := range c.parents(txn) {
+		c.children[p] = append(c.children[p], hash)
+	}
+}
+
+// add adds a transaction that is not spent by any of the indexed transactions
+func (c *unconfirmedChains) add(utxn UnconfirmedTransaction) {
+	c.addOutputs(utxn)
+	c.addChild(utxn.Transaction)
+}
+
+// remove removes a transaction and its outputs. Its children can no longer resolve their inputs
+func (c *unconfirmedChains) remove(hash cipher.SHA256) {
+	utxn, ok := c.txns[hash]
+	if !ok {
+		return
+	}
+
+	delete(c.txns, hash)
+	for h := range unconfirmedOutputs(c.head, coin.Transactions{utxn.Transaction}) {
+		delete(c.outputs, h)
+	}
+}
+
+// parents returns the hashes of the indexed transactions that create the inputs of txn
+func (c *unconfirmedChains) parents(txn coin.Transaction) []cipher.SHA256 {
+	var parents []cipher.SHA256
+	for _, h := range txn.In {
+		ux, ok := c.outputs[h]
+		if !ok {
+			continue
+		}
+
+		if !containsHash(parents, ux.Body.SrcTransaction) {
+			parents = append(parents, ux.Body.SrcTransaction)
+		}
+	}
+
+	return parents
+}
+
+// descendants returns the hashes of the indexed transactions that spend the outputs of the transactions of hashes,
+// directly or through other transactions. The transactions of hashes are not included
+func (c *unconfirmedChains) descendants(hashes []cipher.SHA256) []cipher.SHA256 {
+	return c.limitedDescendants(hashes, 0)
+}
+
+// limitedDescendants returns the descendants of the transactions of hashes, like descendants,
+// the nearest first, up to max descendants. If max is 0 all of the descendants are returned
+func (c *unconfirmedChains) limitedDescendants(hashes []cipher.SHA256, max int) []cipher.SHA256 {
+	seen := make(map[cipher.SHA256]struct{}, len(hashes))
+	for _, h := range hashes {
+		seen[h] = struct{}{}
+	}
+
+	var descendants []cipher.SHA256
+	queue := append([]cipher.SHA256{}, hashes...)
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+
+		for _, child := range c.children[h] {
+			if _, ok := seen[child]; ok {
+				continue
+			}
+			if _, ok := c.txns[child]; !ok {
+				continue
+			}
+
+			seen[child] = struct{}{}
+			descendants = append(descendants, child)
+			if max != 0 && len(descendants) == max {
+				return descendants
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	return descendants
+}
+
+// sorted returns the indexed transactions ordered so that every transaction follows its parents.
+// Otherwise the transactions keep the order they were added in.
+// The parents are visited with an explicit stack, since a pool saved before the chain limits
+// may hold chains of any length
+func (c *unconfirmedChains) sorted() []UnconfirmedTransaction {
+	sorted := make([]UnconfirmedTransaction, 0, len(c.txns))
+	visited := make(map[cipher.SHA256]struct{}, len(c.txns))
+
+	type frame struct {
+		hash cipher.SHA256
+		// The parents of the transaction were visited, and it follows them
+		parentsVisited bool
+	}
+
+	for _, h := range c.hashes {
+		stack := []frame{{hash: h}}
+		for len(stack) > 0 {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if f.parentsVisited {
+				sorted = append(sorted, *c.txns[f.hash])
+				continue
+			}
+
+			if _, ok := visited[f.hash]; ok {
+				continue
+			}
+			visited[f.hash] = struct{}{}
+
+			utxn, ok := c.txns[f.hash]
+			if !ok {
+				continue
+			}
+
+			stack = append(stack, frame{
+				hash:           f.hash,
+				parentsVisited: true,
+			})
+
+			// The parents are pushed in reverse, so that they are visited in order
+			parents := c.parents(utxn.Transaction)
+			for i := len(parents) - 1; i >= 0; i-- {
+				if _, ok := visited[parents[i]]; !ok {
+					stack = append(stack, frame{hash: parents[i]})
+				}
+			}
+		}
+	}
+
+	return sorted
+}
+
+// inputs returns the outputs spent by txn, from the unspent pool or created by the indexed transactions.
+// Returns blockdb.ErrUnspentNotExist if an input does not exist in either
+func (c *unconfirmedChains) inputs(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction) (coin.UxArray, error) {
+	uxIn := make(coin.UxArray, len(txn.In))
+
+	var confirmed []cipher.SHA256
+	var confirmedIdx []int
+	for i, h := range txn.In {
+		if ux, ok := c.outputs[h]; ok {
+			uxIn[i] = ux
+			continue
+		}
+
+		confirmed = append(confirmed, h)
+		confirmedIdx = append(confirmedIdx, i)
+	}
+
+	if len(confirmed) != 0 {
+		uxs, err := bc.Unspent().GetArray(tx, confirmed)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, ux := range uxs {
+			uxIn[confirmedIdx[i]] = ux
+		}
+	}
+
+	return uxIn, nil
+}
+
+// verifyHardConstraints checks that txn does not violate hard constraints, like Blockchainer.VerifySingleTxnHardConstraints,
+// except that txn may spend the outputs of the indexed transactions
+func (c *unconfirmedChains) verifyHardConstraints(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, signed TxnSignedFlag) (coin.UxArray, error) {
+	uxIn, err := c.inputs(tx, bc, txn)
+	if err != nil {
+		switch err.(type) {
+		case blockdb.ErrUnspentNotExist:
+			return nil, NewErrTxnViolatesHardConstraint(err)
+		default:
+			return nil, err
+		}
+	}
+
+	if err := VerifySingleTxnHardConstraints(txn, c.head, uxIn, signed); err != nil {
+		return nil, err
+	}
+
+	return uxIn, nil
+}
+
+// verifyTransaction checks that txn does not violate hard or soft constraints, like Blockchainer.VerifySingleTxnSoftHardConstraints,
+// except that txn may spend the outputs of the indexed transactions.
+// A transaction spending the outputs of an invalid transaction violates soft constraints
+func (c *unconfirmedChains) verifyTransaction(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, verifyParams params.VerifyTxn, signed TxnSignedFlag) (coin.UxArray, error) {
+	uxIn, err := c.verifyHardConstraints(tx, bc, txn, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifySingleTxnSoftConstraints(txn, c.head.Time, uxIn, verifyParams); err != nil {
+		return nil, err
+	}
+
+	for _, p := range c.parents(txn) {
+		if c.txns[p].IsValid != 1 {
+			return nil, NewErrTxnViolatesSoftConstraint(ErrTxnSpendsInvalidUnconfirmed)
+		}
+	}
+
+	return uxIn, nil
+}
+
+// transactionFee returns a coin.FeeCalculator that calculates the fee of transactions at the head time,
+// for transactions that may spend the outputs of the indexed transactions
+func (c *unconfirmedChains) transactionFee(tx *dbutil.Tx, bc Blockchainer) coin.FeeCalculator {
+	return func(txn *coin.Transaction) (uint64, error) {
+		uxIn, err := c.inputs(tx, bc, *txn)
+		if err != nil {
+			return 0, err
+		}
+
+		return fee.TransactionFee(txn, c.head.Time, uxIn)
+	}
+}
+
+// sortTransactionPackages sorts txns by highest fee per kilobyte of their packages, and by lowest hash if tied.
+// The package of a transaction is the transaction and its valid descendants in chains, which can only be confirmed
+// after it. A transaction is ranked by the higher of its own fee per kilobyte and its package's fee per kilobyte,
+// so that children burning many coin hours raise the priority of a parent burning few, while children burning few
+// do not lower it.
+// Only the nearest UnconfirmedChainMaxTxns-1 descendants of a transaction are counted in its package,
+// so that ranking the transactions of a pool saved before the chain limits is not quadratic.
+// Transactions whose fee can't be calculated are removed
+func sortTransactionPackages(tx *dbutil.Tx, bc Blockchainer, chains *unconfirmedChains, txns coin.Transactions) (coin.Transactions, error) {
+	feeCalc := chains.transactionFee(tx, bc)
+
+	type feeSize struct {
+		fee  uint64
+		size uint64
+		ok   bool
+	}
+
+	// The fees of descendants are cached, since chains may share descendants
+	descendantFees := make(map[cipher.SHA256]feeSize)
+	descendantFee := func(h cipher.SHA256) (feeSize, error) {
+		if fs, ok := descendantFees[h]; ok {
+			return fs, nil
+		}
+
+		var fs feeSize
+		if utxn := chains.txns[h]; utxn.IsValid == 1 {
+			size, err := utxn.Transaction.Size()
+			if err != nil {
+				return feeSize{}, err
+			}
+
+			if fee, err := feeCalc(&utxn.Transaction); err == nil {
+				fs = feeSize{
+					fee:  fee,
+					size: uint64(size),
+					ok:   true,
+				}
+			}
+		}
+
+		descendantFees[h] = fs
+		return fs, nil
+	}
+
+	sorted := coin.SortableTransactions{
+		Transactions: make(coin.Transactions, 0, len(txns)),
+		Fees:         make([]uint64, 0, len(txns)),
+		Hashes:       make([]cipher.SHA256, 0, len(txns)),
+	}
+
+	for i := range txns {
+		fee, err := feeCalc(&txns[i])
+		if err != nil {
+			continue
+		}
+
+		size, hash, err := txns[i].SizeHash()
+		if err != nil {
+			return nil, err
+		}
+
+		packageFee := fee
+		packageSize := uint64(size)
+		for _, h := range chains.limitedDescendants([]cipher.SHA256{hash}, UnconfirmedChainMaxTxns-1) {
+			fs, err := descendantFee(h)
+			if err != nil {
+				return nil, err
+			}
+			if !fs.ok {
+				continue
+			}
+
+			packageFee, err = mathutil.AddUint64(packageFee, fs.fee)
+			if err != nil {
+				packageFee = math.MaxUint64
+			}
+			packageSize += fs.size
+		}
+
+		priority := feePerKB(fee, uint64(size))
+		if packagePriority := feePerKB(packageFee, packageSize); packagePriority > priority {
+			priority = packagePriority
+		}
+
+		sorted.Transactions = append(sorted.Transactions, txns[i])
+		sorted.Fees = append(sorted.Fees, priority)
+		sorted.Hashes = append(sorted.Hashes, hash)
+	}
+
+	sorted.Sort()
+	return sorted.Transactions, nil
+}
+
+// feePerKB returns the fee per kilobyte of transactions of size bytes burning fee coin hours
+func feePerKB(fee, size uint64) uint64 {
+	// If the fee * 1024 would exceed math.MaxUint64, set it to math.MaxUint64 so that
+	// the transactions can still be processed
+	feeKB, err := mathutil.MultUint64(fee, 1024)
+	if err != nil {
+		feeKB = math.MaxUint64
+	}
+
+	return feeKB / size
+}
+
+func containsHash(hashes []cipher.SHA256, h cipher.SHA256) bool {
+	for _, x := range hashes {
+		if x == h {
+			return true
+		}
+	}
+	return false
+}
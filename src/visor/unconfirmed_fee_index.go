@@ -0,0 +1,270 @@
+package visor
+
+import (
+	"errors"
+	"math"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/visor/blockdb"
+	"github.com/skycoin/skycoin/src/visor/dbutil"
+)
+
+var (
+	unconfirmedCountKey = []byte("count")
+	unconfirmedSizeKey  = []byte("size")
+
+	// UnconfirmedFeeIndexBkt orders the unconfirmed transactions by validity, fee per byte and time received,
+	// so that the first key is the first transaction to evict
+	UnconfirmedFeeIndexBkt = []byte("unconfirmed_fee_index")
+	// UnconfirmedFeeIndexKeysBkt maps unconfirmed transaction hashes to their keys in UnconfirmedFeeIndexBkt
+	UnconfirmedFeeIndexKeysBkt = []byte("unconfirmed_fee_index_keys")
+	// UnconfirmedMetaBkt holds the number and total size of the unconfirmed transactions
+	UnconfirmedMetaBkt = []byte("unconfirmed_meta")
+)
+
+// feeIndexEntry is a transaction in the fee index
+type feeIndexEntry struct {
+	hash     cipher.SHA256
+	isValid  int8
+	received int64
+	fee      uint64
+	size     uint32
+}
+
+func newFeeIndexEntry(utxn UnconfirmedTransaction, fee uint64) feeIndexEntry {
+	return feeIndexEntry{
+		hash:     utxn.Transaction.Hash(),
+		isValid:  utxn.IsValid,
+		received: utxn.Received,
+		fee:      fee,
+		size:     utxn.Transaction.Length,
+	}
+}
+
+// key sorts invalid transactions first, then by lowest fee per byte, then by most recently received.
+// The bits of a non-negative float64 sort in the same order as the float64
+func (e feeIndexEntry) key() []byte {
+	feePerByte := float64(e.fee) / float64(e.size)
+
+	k := make([]byte, 0, 1+8+8+len(e.hash))
+	k = append(k, byte(e.isValid))
+	k = append(k, dbutil.Itob(math.Float64bits(feePerByte))...)
+	k = append(k, dbutil.Itob(^uint64(e.received))...)
+	return append(k, e.hash[:]...)
+}
+
+func (e feeIndexEntry) value() []byte {
+	return append(dbutil.Itob(e.fee), dbutil.Itob(uint64(e.size))...)
+}
+
+func decodeFeeIndexEntry(k, v []byte) (feeIndexEntry, error) {
+	if len(k) != 1+8+8+len(cipher.SHA256{}) || len(v) != 16 {
+		return feeIndexEntry{}, errors.New("invalid unconfirmed fee index entry")
+	}
+
+	hash, err := cipher.SHA256FromBytes(k[17:])
+	if err != nil {
+		return feeIndexEntry{}, err
+	}
+
+	return feeIndexEntry{
+		hash:     hash,
+		isValid:  int8(k[0]),
+		received: int64(^dbutil.Btoi(k[9:17])),
+		fee:      dbutil.Btoi(v[:8]),
+		size:     uint32(dbutil.Btoi(v[8:])),
+	}, nil
+}
+
+// unconfirmed transactions fee index buckets
+type unconfirmedFeeIndex struct{}
+
+func (fi *unconfirmedFeeIndex) get(tx *dbutil.Tx, hash cipher.SHA256) (*feeIndexEntry, error) {
+	k, err := dbutil.GetBucketValue(tx, UnconfirmedFeeIndexKeysBkt, []byte(hash.Hex()))
+	if err != nil {
+		return nil, err
+	} else if k == nil {
+		return nil, nil
+	}
+
+	v, err := dbutil.GetBucketValue(tx, UnconfirmedFeeIndexBkt, k)
+	if err != nil {
+		return nil, err
+	} else if v == nil {
+		return nil, errors.New("unconfirmed fee index key has no entry")
+	}
+
+	e, err := decodeFeeIndexEntry(k, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// put adds or updates the entry of a transaction, and adds a new transaction to the pool's count and size
+func (fi *unconfirmedFeeIndex) put(tx *dbutil.Tx, e feeIndexEntry) error {
+	prev, err := fi.get(tx, e.hash)
+	if err != nil {
+		return err
+	}
+
+	if prev != nil {
+		if err := dbutil.Delete(tx, UnconfirmedFeeIndexBkt, prev.key()); err != nil {
+			return err
+		}
+	} else if err := fi.addStats(tx, 1, int64(e.size)); err != nil {
+		return err
+	}
+
+	k := e.key()
+	if err := dbutil.PutBucketValue(tx, UnconfirmedFeeIndexBkt, k, e.value()); err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, UnconfirmedFeeIndexKeysBkt, []byte(e.hash.Hex()), k)
+}
+
+// delete removes the entry of a transaction, and removes it from the pool's count and size
+func (fi *unconfirmedFeeIndex) delete(tx *dbutil.Tx, hash cipher.SHA256) error {
+	e, err := fi.get(tx, hash)
+	if err != nil {
+		return err
+	} else if e == nil {
+		return nil
+	}
+
+	if err := dbutil.Delete(tx, UnconfirmedFeeIndexBkt, e.key()); err != nil {
+		return err
+	}
+
+	if err := dbutil.Delete(tx, UnconfirmedFeeIndexKeysBkt, []byte(hash.Hex())); err != nil {
+		return err
+	}
+
+	return fi.addStats(tx, -1, -int64(e.size))
+}
+
+// stats returns the number and total size of the transactions in the pool.
+// Returns false if the index was not built
+func (fi *unconfirmedFeeIndex) stats(tx *dbutil.Tx) (uint64, uint64, bool, error) {
+	count, err := dbutil.GetBucketValue(tx, UnconfirmedMetaBkt, unconfirmedCountKey)
+	if err != nil {
+		return 0, 0, false, err
+	} else if count == nil {
+		return 0, 0, false, nil
+	}
+
+	size, err := dbutil.GetBucketValue(tx, UnconfirmedMetaBkt, unconfirmedSizeKey)
+	if err != nil {
+		return 0, 0, false, err
+	} else if size == nil {
+		return 0, 0, false, nil
+	}
+
+	return dbutil.Btoi(count), dbutil.Btoi(size), true, nil
+}
+
+func (fi *unconfirmedFeeIndex) setStats(tx *dbutil.Tx, count, size uint64) error {
+	if err := dbutil.PutBucketValue(tx, UnconfirmedMetaBkt, unconfirmedCountKey, dbutil.Itob(count)); err != nil {
+		return err
+	}
+
+	return dbutil.PutBucketValue(tx, UnconfirmedMetaBkt, unconfirmedSizeKey, dbutil.Itob(size))
+}
+
+func (fi *unconfirmedFeeIndex) addStats(tx *dbutil.Tx, count, size int64) error {
+	c, s, _, err := fi.stats(tx)
+	if err != nil {
+		return err
+	}
+
+	return fi.setStats(tx, uint64(int64(c)+count), uint64(int64(s)+size))
+}
+
+// forEach visits the entries in eviction order, until f returns false.
+// The index must not be modified by f
+func (fi *unconfirmedFeeIndex) forEach(tx *dbutil.Tx, f func(feeIndexEntry) (bool, error)) error {
+	bkt := tx.Bucket(UnconfirmedFeeIndexBkt)
+	if bkt == nil {
+		return dbutil.NewErrBucketNotExist(UnconfirmedFeeIndexBkt)
+	}
+
+	c := bkt.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		e, err := decodeFeeIndexEntry(k, v)
+		if err != nil {
+			return err
+		}
+
+		if next, err := f(e); err != nil {
+			return err
+		} else if !next {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// MaybeBuildIndexes builds the indexes of the pool that were not built yet,
+// for a pool saved by a version that did not index the transactions
+func (utp *UnconfirmedTransactionPool) MaybeBuildIndexes(tx *dbutil.Tx, bc Blockchainer) error {
+	if err := utp.maybeBuildChainIndex(tx); err != nil {
+		return err
+	}
+
+	return utp.maybeBuildFeeIndex(tx, bc)
+}
+
+// maybeBuildFeeIndex builds the fee index of the pool if it was not built yet,
+// for a pool saved by a version that did not index the fees of the transactions
+func (utp *UnconfirmedTransactionPool) maybeBuildFeeIndex(tx *dbutil.Tx, bc Blockchainer) error {
+	if _, _, ok, err := utp.fees.stats(tx); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	logger.Info("Building unconfirmed fee index")
+
+	for _, b := range [][]byte{UnconfirmedFeeIndexBkt, UnconfirmedFeeIndexKeysBkt} {
+		if err := dbutil.Reset(tx, b); err != nil {
+			return err
+		}
+	}
+
+	if err := utp.fees.setStats(tx, 0, 0); err != nil {
+		return err
+	}
+
+	chains, err := utp.newUnconfirmedChains(tx, bc)
+	if err != nil {
+		return err
+	}
+	calcFee := chains.transactionFee(tx, bc)
+
+	for _, h := range chains.hashes {
+		utxn := chains.txns[h]
+
+		var fee uint64
+		if utxn.IsValid == 1 {
+			fee, err = calcFee(&utxn.Transaction)
+			if err != nil {
+				switch err.(type) {
+				case blockdb.ErrUnspentNotExist:
+					// The transaction spends outputs that no longer exist and can't be confirmed
+					fee = 0
+				default:
+					return err
+				}
+			}
+		}
+
+		if err := utp.fees.put(tx, newFeeIndexEntry(*utxn, fee)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -72,6 +72,10 @@ var (
 	ErrTxnExceedsMaxBlockSize = errors.New("Transaction size bigger than max block size")
 	// ErrTxnIsLocked transaction has locked address inputs
 	ErrTxnIsLocked = errors.New("Transaction has locked address inputs")
+	// ErrTxnMultisigNotActivated multisig transaction before the multisig activation height
+	ErrTxnMultisigNotActivated = errors.New("Multisig transactions are not activated")
+	// ErrTxnTimeLockNotActivated time-locked output spent before the time-lock activation height
+	ErrTxnTimeLockNotActivated = errors.New("Time-locked transactions are not activated")
 )
 
 // TxnSignedFlag indicates if the transaction is unsigned or not
@@ -202,6 +206,8 @@ func verifyTxnSoftConstraints(txn coin.Transaction, headTime uint64, uxIn coin.U
 //      * That the signatures on the transaction are valid
 //      * That there are no duplicate ux inputs
 //      * That there are no duplicate outputs
+//      * That multisig transactions are activated at the height of the next block
+//      * That time-locked outputs are activated and unlocked at the height of the next block
 //      * That the transaction input and output coins do not overflow uint64
 //      * That the transaction input and output hours do not overflow uint64
 // NOTE: Double spends are checked against the unspent output pool when querying for uxIn
@@ -240,6 +246,8 @@ func VerifySingleTxnHardConstraints(txn coin.Transaction, head coin.BlockHeader,
 //      * That the signatures on the transaction are valid
 //      * That there are no duplicate ux inputs
 //      * That there are no duplicate outputs
+//      * That multisig transactions are activated at the height of the next block
+//      * That time-locked outputs are activated and unlocked at the height of the next block
 //      * That the transaction input and output coins do not overflow uint64
 //      * That the transaction input hours do not overflow uint64
 // NOTE: Double spends are checked against the unspent output pool when querying for uxIn
@@ -275,6 +283,13 @@ func verifyTxnHardConstraints(txn coin.Transaction, head coin.BlockHeader, uxIn
 	// Check for zero coin outputs
 	// Check valid looking signatures
 
+	// Check that multisig and time-locked outputs can be spent in the next block
+	if txn.Type == coin.TransactionTypeMultisig {
+		if err := verifyWitnessActivation(head.BkSeq+1, txn, uxIn); err != nil {
+			return err
+		}
+	}
+
 	switch signed {
 	case TxnSigned:
 		if err := txn.Verify(); err != nil {
@@ -298,6 +313,12 @@ func verifyTxnHardConstraints(txn coin.Transaction, head coin.BlockHeader, uxIn
 		logger.Panic("Invalid TxnSignedFlag")
 	}
 
+	// Check that time-locked outputs are unlocked in the next block.
+	// The lock time is compared to the time of the head block, like coin hours
+	if err := txn.VerifyTimeLocks(uxIn, head.BkSeq+1, head.Time); err != nil {
+		return err
+	}
+
 	uxOut := coin.CreateUnspents(head, txn)
 
 	// Check that there are any duplicates within this set
@@ -334,3 +355,50 @@ func VerifySingleTxnUserConstraints(txn coin.Transaction) error {
 
 	return nil
 }
+
+// multisigActivated returns true if multisig transactions are accepted in the block with sequence seq
+func multisigActivated(seq uint64) bool {
+	return params.MultisigActivationHeight != 0 && seq >= params.MultisigActivationHeight
+}
+
+// timeLockActivated returns true if time-locked outputs can be spent in the block with sequence seq
+func timeLockActivated(seq uint64) bool {
+	return params.TimeLockActivationHeight != 0 && seq >= params.TimeLockActivationHeight
+}
+
+// verifyWitnessActivation checks that the multisig and time-locked outputs in uxIn can be spent
+// in the block with sequence seq. A time-locked output owned by a multisig address requires both activations.
+func verifyWitnessActivation(seq uint64, txn coin.Transaction, uxIn coin.UxArray) error {
+	timeLocked := false
+	for _, ux := range uxIn {
+		switch {
+		case ux.Body.Address.IsMultisig():
+			if !multisigActivated(seq) {
+				return ErrTxnMultisigNotActivated
+			}
+		case ux.Body.Address.IsTimeLocked():
+			if !timeLockActivated(seq) {
+				return ErrTxnTimeLockNotActivated
+			}
+			timeLocked = true
+		}
+	}
+
+	if !timeLocked || multisigActivated(seq) {
+		return nil
+	}
+
+	// The owner of a time-locked output is hidden in its address, and only revealed by the witness
+	owners, err := txn.TimeLockOwners(uxIn)
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		if owner.IsMultisig() {
+			return ErrTxnMultisigNotActivated
+		}
+	}
+
+	return nil
+}
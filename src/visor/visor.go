@@ -52,6 +52,21 @@ type Config struct {
 	CreateBlockVerifyTxn params.VerifyTxn
 	// Maximum size of a block, in bytes for creating blocks
 	MaxBlockTransactionsSize uint32
+	// Maximum number of transactions in the unconfirmed pool, 0 for no limit
+	MaxUnconfirmedTxns int
+	// Maximum total size of the transactions in the unconfirmed pool, in bytes, 0 for no limit
+	MaxUnconfirmedTxnsSize uint64
+	// Maximum time a transaction stays in the unconfirmed pool since it was first received, 0 for no limit
+	MaxUnconfirmedTxnAge time.Duration
+	// Number of blocks, including its own, a transaction submitted through the API must be buried under
+	// to be considered confirmed by its lifecycle
+	TxnConfirmationDepth uint64
+	// Time the lifecycle of a confirmed or rejected transaction is kept
+	TxnLifecycleRetention time.Duration
+	// Time the response of an API request made with an idempotency key is kept
+	IdempotencyKeyRetention time.Duration
+	// Time the outputs spent by a transaction created with a reservation stay reserved
+	OutputReservationTTL time.Duration
 
 	// Where the blockchain is saved
 	BlockchainFile string
@@ -91,6 +106,13 @@ func NewConfig() Config {
 		UnconfirmedVerifyTxn:     params.UserVerifyTxn,
 		CreateBlockVerifyTxn:     params.UserVerifyTxn,
 		MaxBlockTransactionsSize: params.UserVerifyTxn.MaxTransactionSize,
+		MaxUnconfirmedTxns:       DefaultMaxUnconfirmedTxns,
+		MaxUnconfirmedTxnsSize:   DefaultMaxUnconfirmedTxnsSize,
+		MaxUnconfirmedTxnAge:     DefaultMaxUnconfirmedTxnAge,
+		TxnConfirmationDepth:     DefaultTxnConfirmationDepth,
+		TxnLifecycleRetention:    DefaultTxnLifecycleRetention,
+		IdempotencyKeyRetention:  DefaultIdempotencyKeyRetention,
+		OutputReservationTTL:     DefaultOutputReservationTTL,
 
 		GenesisAddress:    cipher.Address{},
 		GenesisSignature:  cipher.Sig{},
@@ -145,6 +167,34 @@ func (c Config) Verify() error {
 		return errors.New("MaxBlockTransactionsSize must be >= CreateBlockVerifyTxn.MaxTransactionSize")
 	}
 
+	if c.MaxUnconfirmedTxns < 0 {
+		return errors.New("MaxUnconfirmedTxns must be >= 0")
+	}
+
+	if c.MaxUnconfirmedTxnsSize != 0 && c.MaxUnconfirmedTxnsSize < uint64(c.UnconfirmedVerifyTxn.MaxTransactionSize) {
+		return errors.New("MaxUnconfirmedTxnsSize must be 0 or >= UnconfirmedVerifyTxn.MaxTransactionSize")
+	}
+
+	if c.MaxUnconfirmedTxnAge < 0 {
+		return errors.New("MaxUnconfirmedTxnAge must be >= 0")
+	}
+
+	if c.TxnConfirmationDepth == 0 {
+		return errors.New("TxnConfirmationDepth must be > 0")
+	}
+
+	if c.TxnLifecycleRetention <= 0 {
+		return errors.New("TxnLifecycleRetention must be > 0")
+	}
+
+	if c.IdempotencyKeyRetention <= 0 {
+		return errors.New("IdempotencyKeyRetention must be > 0")
+	}
+
+	if c.OutputReservationTTL <= 0 {
+		return errors.New("OutputReservationTTL must be > 0")
+	}
+
 	return nil
 }
 
@@ -191,10 +241,12 @@ type Blockchainer interface {
 type UnconfirmedTransactionPooler interface {
 	SetTransactionsAnnounced(tx *dbutil.Tx, hashes map[cipher.SHA256]int64) error
 	InjectTransaction(tx *dbutil.Tx, bc Blockchainer, t coin.Transaction, verifyParams params.VerifyTxn) (bool, *ErrTxnViolatesSoftConstraint, error)
+	VerifyTransaction(tx *dbutil.Tx, bc Blockchainer, txn coin.Transaction, verifyParams params.VerifyTxn, signed TxnSignedFlag) (*coin.SignedBlock, coin.UxArray, error)
 	AllRawTransactions(tx *dbutil.Tx) (coin.Transactions, error)
 	RemoveTransactions(tx *dbutil.Tx, txns []cipher.SHA256) error
 	Refresh(tx *dbutil.Tx, bc Blockchainer, verifyParams params.VerifyTxn) ([]cipher.SHA256, error)
 	RemoveInvalid(tx *dbutil.Tx, bc Blockchainer) ([]cipher.SHA256, error)
+	RemoveExpired(tx *dbutil.Tx, bc Blockchainer, now time.Time) ([]ExpiredTransaction, error)
 	FilterKnown(tx *dbutil.Tx, txns []cipher.SHA256) ([]cipher.SHA256, error)
 	GetKnown(tx *dbutil.Tx, txns []cipher.SHA256) (coin.Transactions, error)
 	RecvOfAddresses(tx *dbutil.Tx, bh coin.BlockHeader, addrs []cipher.Address) (coin.AddressUxOuts, error)
@@ -205,6 +257,8 @@ type UnconfirmedTransactionPooler interface {
 	ForEach(tx *dbutil.Tx, f func(cipher.SHA256, UnconfirmedTransaction) error) error
 	GetUnspentsOfAddr(tx *dbutil.Tx, addr cipher.Address) (coin.UxArray, error)
 	Len(tx *dbutil.Tx) (uint64, error)
+	GetEvicted() []EvictedTransaction
+	GetReplaced() []ReplacedTransaction
 }
 
 // Visor manages the blockchain
@@ -216,7 +270,10 @@ type Visor struct {
 	Wallets     *wallet.Service
 	StartedAt   time.Time
 
-	history Historyer
+	history            Historyer
+	txnLifecycles      *txnLifecycles
+	idempotencyKeys    *idempotencyKeys
+	outputReservations *outputReservations
 }
 
 // NewVisor creates a Visor for managing the blockchain database
@@ -237,6 +294,13 @@ func NewVisor(c Config, db *dbutil.DB) (*Visor, error) {
 	logger.Infof("Max transaction size for transactions when creating blocks is %d", c.CreateBlockVerifyTxn.MaxTransactionSize)
 	logger.Infof("Max decimals for transactions when creating blocks is %d", c.CreateBlockVerifyTxn.MaxDropletPrecision)
 	logger.Infof("Max block size is %d", c.MaxBlockTransactionsSize)
+	logger.Infof("Max number of unconfirmed transactions is %d", c.MaxUnconfirmedTxns)
+	logger.Infof("Max total size of unconfirmed transactions is %d", c.MaxUnconfirmedTxnsSize)
+	logger.Infof("Max age of unconfirmed transactions is %v", c.MaxUnconfirmedTxnAge)
+	logger.Infof("Confirmation depth of submitted transactions is %d", c.TxnConfirmationDepth)
+	logger.Infof("Retention of confirmed and rejected transaction lifecycles is %v", c.TxnLifecycleRetention)
+	logger.Infof("Retention of idempotency keys is %v", c.IdempotencyKeyRetention)
+	logger.Infof("TTL of output reservations is %v", c.OutputReservationTTL)
 
 	// Loads wallet
 	wltServConfig := wallet.Config{
@@ -285,19 +349,34 @@ func NewVisor(c Config, db *dbutil.DB) (*Visor, error) {
 		}
 	}
 
-	utp, err := NewUnconfirmedTransactionPool(db)
+	utp, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{
+		MaxTxns: c.MaxUnconfirmedTxns,
+		MaxSize: c.MaxUnconfirmedTxnsSize,
+		MaxAge:  c.MaxUnconfirmedTxnAge,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if !db.IsReadOnly() {
+		if err := db.Update("build unconfirmed indexes", func(tx *dbutil.Tx) error {
+			return utp.MaybeBuildIndexes(tx, bc)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	v := &Visor{
-		Config:      c,
-		DB:          db,
-		Blockchain:  bc,
-		Unconfirmed: utp,
-		history:     history,
-		Wallets:     wltServ,
-		StartedAt:   time.Now(),
+		Config:             c,
+		DB:                 db,
+		Blockchain:         bc,
+		Unconfirmed:        utp,
+		history:            history,
+		txnLifecycles:      &txnLifecycles{},
+		idempotencyKeys:    &idempotencyKeys{},
+		outputReservations: &outputReservations{},
+		Wallets:            wltServ,
+		StartedAt:          time.Now(),
 	}
 
 	return v, nil
@@ -457,6 +536,35 @@ func (vs *Visor) RemoveInvalidUnconfirmed() ([]cipher.SHA256, error) {
 	return hashes, nil
 }
 
+// RemoveExpiredUnconfirmed removes transactions that stayed in the pool longer than
+// the maximum age since they were first received, along with their descendants.
+// The expired transactions are rejected in their lifecycles with the reason TxnLifecycleReasonExpired.
+// Returns the transaction hashes that were removed.
+func (vs *Visor) RemoveExpiredUnconfirmed() ([]cipher.SHA256, error) {
+	var hashes []cipher.SHA256
+	if err := vs.DB.Update("RemoveExpiredUnconfirmed", func(tx *dbutil.Tx) error {
+		now := time.Now().UTC()
+		expired, err := vs.Unconfirmed.RemoveExpired(tx, vs.Blockchain, now)
+		if err != nil {
+			return err
+		}
+
+		hashes = make([]cipher.SHA256, len(expired))
+		for i, e := range expired {
+			if err := vs.recordTxnExpired(tx, e, now); err != nil {
+				return err
+			}
+			hashes[i] = e.Transaction.Hash()
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
 // CreateBlock creates a SignedBlock from pending transactions
 func (vs *Visor) createBlock(tx *dbutil.Tx, when uint64) (coin.SignedBlock, error) {
 	if !vs.Config.IsBlockPublisher {
@@ -464,20 +572,42 @@ func (vs *Visor) createBlock(tx *dbutil.Tx, when uint64) (coin.SignedBlock, erro
 	}
 
 	// Gather all unconfirmed transactions
-	txns, err := vs.Unconfirmed.AllRawTransactions(tx)
+	utxns, err := vs.Unconfirmed.GetFiltered(tx, All)
 	if err != nil {
 		return coin.SignedBlock{}, err
 	}
 
+	txns := make(coin.Transactions, len(utxns))
+	for i, utxn := range utxns {
+		txns[i] = utxn.Transaction
+	}
+
 	if len(txns) == 0 {
 		return coin.SignedBlock{}, errors.New("No transactions")
 	}
 
 	logger.Infof("Unconfirmed pool has %d transactions pending", len(txns))
 
+	head, err := vs.Blockchain.Head(tx)
+	if err != nil {
+		return coin.SignedBlock{}, err
+	}
+
+	// Transactions that spend the outputs of other unconfirmed transactions can't be included
+	// in the same block as their parents, since a block's transactions are verified against
+	// the unspent outputs of the previous block. They wait for their parents to be confirmed.
+	_, unconfirmedSpends := unconfirmedInputs(head.Head, txns)
+
 	// Filter transactions that violate all constraints
 	var filteredTxns coin.Transactions
+	var nWaiting int
 	for _, txn := range txns {
+		if spendsUnconfirmed(txn, unconfirmedSpends) {
+			logger.Debugf("Transaction %s waits for the confirmation of the unconfirmed transactions it spends", txn.Hash().Hex())
+			nWaiting++
+			continue
+		}
+
 		if _, _, err := vs.Blockchain.VerifySingleTxnSoftHardConstraints(tx, txn, vs.Config.CreateBlockVerifyTxn, TxnSigned); err != nil {
 			switch err.(type) {
 			case ErrTxnViolatesHardConstraint, ErrTxnViolatesSoftConstraint:
@@ -490,7 +620,11 @@ func (vs *Visor) createBlock(tx *dbutil.Tx, when uint64) (coin.SignedBlock, erro
 		}
 	}
 
-	nRemoved := len(txns) - len(filteredTxns)
+	if nWaiting > 0 {
+		logger.Infof("CreateBlock deferred %d transactions spending the outputs of unconfirmed transactions", nWaiting)
+	}
+
+	nRemoved := len(txns) - len(filteredTxns) - nWaiting
 	if nRemoved > 0 {
 		logger.Infof("CreateBlock ignored %d transactions violating constraints", nRemoved)
 	}
@@ -502,15 +636,12 @@ func (vs *Visor) createBlock(tx *dbutil.Tx, when uint64) (coin.SignedBlock, erro
 		return coin.SignedBlock{}, errors.New("No transactions after filtering for constraint violations")
 	}
 
-	head, err := vs.Blockchain.Head(tx)
-	if err != nil {
-		return coin.SignedBlock{}, err
-	}
-
-	// Sort them by highest fee per kilobyte
-	txns, err = coin.SortTransactions(txns, vs.Blockchain.TransactionFee(tx, head.Time()))
+	// Sort them by highest fee per kilobyte, counting the fees of the unconfirmed transactions
+	// that wait for them to be confirmed
+	chains := newUnconfirmedChains(head.Head, utxns)
+	txns, err = sortTransactionPackages(tx, vs.Blockchain, chains, txns)
 	if err != nil {
-		logger.Critical().WithError(err).Error("SortTransactions failed, no block can be made until the offending transaction is removed")
+		logger.Critical().WithError(err).Error("sortTransactionPackages failed, no block can be made until the offending transaction is removed")
 		return coin.SignedBlock{}, err
 	}
 
@@ -653,17 +784,33 @@ func (vs *Visor) UnconfirmedOutgoingOutputs() (coin.UxArray, error) {
 }
 
 func (vs *Visor) unconfirmedOutgoingOutputs(tx *dbutil.Tx) (coin.UxArray, error) {
+	inputs, err := vs.unconfirmedSpentUnspents(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.Blockchain.Unspent().GetArray(tx, inputs)
+}
+
+// unconfirmedSpentUnspents returns the hashes of the unspent outputs spent by unconfirmed transactions.
+// The inputs created by other unconfirmed transactions are excluded, as they are not in the unspent pool yet
+func (vs *Visor) unconfirmedSpentUnspents(tx *dbutil.Tx) ([]cipher.SHA256, error) {
 	txns, err := vs.Unconfirmed.AllRawTransactions(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	var inputs []cipher.SHA256
-	for _, txn := range txns {
-		inputs = append(inputs, txn.In...)
+	if len(txns) == 0 {
+		return nil, nil
 	}
 
-	return vs.Blockchain.Unspent().GetArray(tx, inputs)
+	head, err := vs.Blockchain.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, _ := unconfirmedInputs(head.Head, txns)
+	return inputs, nil
 }
 
 // UnconfirmedIncomingOutputs returns all outputs that would be created by unconfirmed transactions
@@ -972,7 +1119,7 @@ func (vs *Visor) InjectUserTransactionTx(tx *dbutil.Tx, txn coin.Transaction) (b
 		return false, nil, nil, err
 	}
 
-	head, inputs, err := vs.Blockchain.VerifySingleTxnSoftHardConstraints(tx, txn, params.UserVerifyTxn, TxnSigned)
+	head, inputs, err := vs.Unconfirmed.VerifyTransaction(tx, vs.Blockchain, txn, params.UserVerifyTxn, TxnSigned)
 	if err != nil {
 		return false, nil, nil, err
 	}
@@ -981,8 +1128,16 @@ func (vs *Visor) InjectUserTransactionTx(tx *dbutil.Tx, txn coin.Transaction) (b
 	if softErr != nil {
 		logger.WithError(softErr).Warning("InjectUserTransaction vs.Unconfirmed.InjectTransaction returned a softErr unexpectedly")
 	}
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	// The outputs spent by the transaction are excluded from spending by the unconfirmed pool from now on
+	if err := vs.outputReservations.release(tx, txn); err != nil {
+		return false, nil, nil, err
+	}
 
-	return known, head, inputs, err
+	return known, head, inputs, nil
 }
 
 // GetTransactionsForAddress returns the Transactions whose unspents give coins to a cipher.Address.
@@ -1070,7 +1225,7 @@ func (vs *Visor) getTransaction(tx *dbutil.Tx, txnHash cipher.SHA256) (*Transact
 	}
 
 	if htxn == nil {
-		return nil, nil
+		return vs.getExpiredTransaction(tx, txnHash)
 	}
 
 	headSeq, ok, err := vs.Blockchain.HeadSeq(tx)
@@ -1101,6 +1256,25 @@ func (vs *Visor) getTransaction(tx *dbutil.Tx, txnHash cipher.SHA256) (*Transact
 	}, nil
 }
 
+// getExpiredTransaction returns a transaction that expired from the unconfirmed pool, from its lifecycle,
+// or nil if not found. The lifecycle is kept for the lifecycle retention
+func (vs *Visor) getExpiredTransaction(tx *dbutil.Tx, txnHash cipher.SHA256) (*Transaction, error) {
+	l, err := vs.txnLifecycles.get(tx, txnHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil || l.State != TxnLifecycleRejected || l.Expired == nil {
+		return nil, nil
+	}
+
+	return &Transaction{
+		Transaction: l.Expired.Transaction,
+		Status:      NewExpiredTransactionStatus(),
+		Time:        uint64(timeutil.NanoToTime(l.Expired.Received).Unix()),
+	}, nil
+}
+
 // TxFilter transaction filter type
 type TxFilter interface {
 	// Returns whether the transaction is matched
@@ -1544,6 +1718,36 @@ func (vs *Visor) GetAllUnconfirmedTransactions() ([]UnconfirmedTransaction, erro
 	return txns, nil
 }
 
+// GetEvictedUnconfirmedTransactions returns the transactions recently evicted from the full unconfirmed pool, the most recent last
+func (vs *Visor) GetEvictedUnconfirmedTransactions() []EvictedTransaction {
+	return vs.Unconfirmed.GetEvicted()
+}
+
+// GetExpiredUnconfirmedTransactions returns the transactions that expired from the unconfirmed pool, the most recent last.
+// They are read from their lifecycles, which are kept for the lifecycle retention
+func (vs *Visor) GetExpiredUnconfirmedTransactions() ([]ExpiredTransaction, error) {
+	var expired []ExpiredTransaction
+	if err := vs.DB.View("GetExpiredUnconfirmedTransactions", func(tx *dbutil.Tx) error {
+		return vs.txnLifecycles.forEach(tx, func(l *TransactionLifecycle) error {
+			if l.State == TxnLifecycleRejected && l.Expired != nil {
+				expired = append(expired, *l.Expired)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		if expired[i].Expired == expired[j].Expired {
+			return expired[i].Received < expired[j].Received
+		}
+		return expired[i].Expired < expired[j].Expired
+	})
+
+	return expired, nil
+}
+
 // GetAllUnconfirmedTransactionsVerbose returns all unconfirmed transactions with verbose transaction input data
 func (vs *Visor) GetAllUnconfirmedTransactionsVerbose() ([]UnconfirmedTransaction, [][]TransactionInput, error) {
 	var txns []UnconfirmedTransaction
@@ -1792,9 +1996,9 @@ func (vs *Visor) getTransactionInputs(tx *dbutil.Tx, feeCalcTime uint64, inputs
 		return nil, err
 	}
 
-	uxOuts, err := vs.history.GetUxOuts(tx, inputs)
+	uxOuts, err := vs.getUxOuts(tx, inputs)
 	if err != nil {
-		logger.WithError(err).Error("getTransactionInputs GetUxOuts failed")
+		logger.WithError(err).Error("getTransactionInputs getUxOuts failed")
 		return nil, err
 	}
 
@@ -1811,6 +2015,50 @@ func (vs *Visor) getTransactionInputs(tx *dbutil.Tx, feeCalcTime uint64, inputs
 	return ret, nil
 }
 
+// getUxOuts returns the outputs of uxIDs from the history, or created by unconfirmed transactions.
+// Unconfirmed transactions may spend the outputs of other unconfirmed transactions,
+// which are not in the history until they are confirmed
+func (vs *Visor) getUxOuts(tx *dbutil.Tx, uxIDs []cipher.SHA256) ([]historydb.UxOut, error) {
+	uxOuts, err := vs.history.GetUxOuts(tx, uxIDs)
+	if err == nil {
+		return uxOuts, nil
+	}
+
+	if _, ok := err.(historydb.ErrUxOutNotExist); !ok {
+		return nil, err
+	}
+
+	txns, err := vs.Unconfirmed.AllRawTransactions(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := vs.Blockchain.Head(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := unconfirmedOutputs(head.Head, txns)
+
+	uxOuts = make([]historydb.UxOut, len(uxIDs))
+	for i, h := range uxIDs {
+		if ux, ok := outputs[h]; ok {
+			uxOuts[i] = historydb.UxOut{
+				Out: ux,
+			}
+			continue
+		}
+
+		outs, err := vs.history.GetUxOuts(tx, []cipher.SHA256{h})
+		if err != nil {
+			return nil, err
+		}
+		uxOuts[i] = outs[0]
+	}
+
+	return uxOuts, nil
+}
+
 // GetHeadBlock gets head block.
 func (vs Visor) GetHeadBlock() (*coin.SignedBlock, error) {
 	var b *coin.SignedBlock
@@ -1982,16 +2230,11 @@ func (vs *Visor) UnconfirmedSpendsOfAddresses(addrs []cipher.Address) (coin.Addr
 
 // unconfirmedSpendsOfAddresses returns all unconfirmed coin.UxOut spends of addresses
 func (vs *Visor) unconfirmedSpendsOfAddresses(tx *dbutil.Tx, addrs []cipher.Address) (coin.AddressUxOuts, error) {
-	txns, err := vs.Unconfirmed.AllRawTransactions(tx)
+	inputs, err := vs.unconfirmedSpentUnspents(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	var inputs []cipher.SHA256
-	for _, txn := range txns {
-		inputs = append(inputs, txn.In...)
-	}
-
 	uxa, err := vs.Blockchain.Unspent().GetArray(tx, inputs)
 	if err != nil {
 		return nil, err
@@ -2054,10 +2297,9 @@ func (vs Visor) GetBalanceOfAddrs(addrs []cipher.Address) ([]wallet.BalancePair,
 			return err
 		}
 
-		var inputs []cipher.SHA256
-		for _, txn := range txns {
-			inputs = append(inputs, txn.In...)
-		}
+		// Inputs created by other unconfirmed transactions are not in the unspent pool yet.
+		// Those outputs were already excluded from the predicted unspent outputs
+		inputs, _ := unconfirmedInputs(head.Head, txns)
 
 		// Get unspents for the inputs being spent
 		uxa, err = vs.Blockchain.Unspent().GetArray(tx, inputs)
@@ -2320,9 +2562,9 @@ func (vs *Visor) GetVerboseTransactionsForAddress(a cipher.Address) ([]Transacti
 
 			txnInputs := make([]TransactionInput, len(txn.Transaction.In))
 			for j, inputID := range txn.Transaction.In {
-				uxOuts, err := vs.history.GetUxOuts(tx, []cipher.SHA256{inputID})
+				uxOuts, err := vs.getUxOuts(tx, []cipher.SHA256{inputID})
 				if err != nil {
-					logger.Errorf("GetVerboseTransactionsForAddress: vs.history.GetUxOuts failed: %v", err)
+					logger.Errorf("GetVerboseTransactionsForAddress: vs.getUxOuts failed: %v", err)
 					return err
 				}
 				if len(uxOuts) == 0 {
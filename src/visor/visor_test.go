@@ -270,7 +270,7 @@ func TestVisorCreateBlock(t *testing.T) {
 		Pubkey: genPublic,
 	})
 
-	unconfirmed, err := NewUnconfirmedTransactionPool(db)
+	unconfirmed, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
 	require.NoError(t, err)
 
 	his := historydb.New()
@@ -496,7 +496,7 @@ func TestVisorInjectTransaction(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	unconfirmed, err := NewUnconfirmedTransactionPool(db)
+	unconfirmed, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
 	require.NoError(t, err)
 
 	his := historydb.New()
@@ -1921,6 +1921,1102 @@ func TestGetTransactions(t *testing.T) {
 	}
 }
 
+func TestInjectTransactionEviction(t *testing.T) {
+	setup := func(t *testing.T, limits UnconfirmedPoolLimits) (*Visor, *dbutil.DB, *Blockchain, func()) {
+		db, shutdown := prepareDB(t)
+
+		_, s := cipher.GenerateKeyPair()
+		bc := MakeBlockchain(t, db, s)
+
+		pool, err := NewUnconfirmedTransactionPool(db, limits)
+		require.NoError(t, err)
+
+		v := setupSimpleVisor(t, db, bc)
+		v.Unconfirmed = pool
+
+		return v, db, bc, shutdown
+	}
+
+	makeTxn := func(t *testing.T, db *dbutil.DB, bc *Blockchain, coins, fee uint64) coin.Transaction {
+		return CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), coins, 1000, fee)
+	}
+
+	requirePool := func(t *testing.T, v *Visor, txns ...coin.Transaction) {
+		var hashes []cipher.SHA256
+		err := v.DB.View("", func(tx *dbutil.Tx) error {
+			var err error
+			hashes, err = v.Unconfirmed.GetHashes(tx, All)
+			return err
+		})
+		require.NoError(t, err)
+
+		expected := make(map[cipher.SHA256]struct{}, len(txns))
+		for _, txn := range txns {
+			expected[txn.Hash()] = struct{}{}
+		}
+
+		actual := make(map[cipher.SHA256]struct{}, len(hashes))
+		for _, h := range hashes {
+			actual[h] = struct{}{}
+		}
+
+		require.Equal(t, expected, actual)
+	}
+
+	testEviction := func(t *testing.T, limits func(txnSize uint32) UnconfirmedPoolLimits) {
+		v, db, bc, shutdown := setup(t, UnconfirmedPoolLimits{})
+		defer shutdown()
+
+		txnA := makeTxn(t, db, bc, 10e6, 600e6)
+		txnB := makeTxn(t, db, bc, 10e6, 700e6)
+		txnC := makeTxn(t, db, bc, 10e6, 550e6)
+		txnD := makeTxn(t, db, bc, 10e6, 800e6)
+
+		pool, err := NewUnconfirmedTransactionPool(db, limits(txnA.Length))
+		require.NoError(t, err)
+		v.Unconfirmed = pool
+
+		// The transactions all spend the genesis output. None of them burns more than the conflicting
+		// transactions in the pool together, so they are added without replacing each other
+		for _, txn := range []coin.Transaction{txnB, txnA} {
+			known, softErr, err := v.InjectForeignTransaction(txn)
+			require.NoError(t, err)
+			require.Nil(t, softErr)
+			require.False(t, known)
+		}
+		requirePool(t, v, txnA, txnB)
+
+		// The pool is full and txnC has the lowest fee, so it is rejected
+		_, _, err = v.InjectForeignTransaction(txnC)
+		require.Equal(t, ErrUnconfirmedPoolFull, err)
+		requirePool(t, v, txnA, txnB)
+		require.Empty(t, v.GetEvictedUnconfirmedTransactions())
+
+		// A known transaction is updated without evicting another
+		known, _, err := v.InjectForeignTransaction(txnA)
+		require.NoError(t, err)
+		require.True(t, known)
+		requirePool(t, v, txnA, txnB)
+
+		// txnD has the highest fee, so the transaction with the lowest fee is evicted
+		known, _, err = v.InjectForeignTransaction(txnD)
+		require.NoError(t, err)
+		require.False(t, known)
+		requirePool(t, v, txnB, txnD)
+
+		evicted := v.GetEvictedUnconfirmedTransactions()
+		require.Len(t, evicted, 1)
+		require.Equal(t, txnA, evicted[0].Transaction)
+		require.Equal(t, uint64(600e6), evicted[0].Fee)
+		require.NotZero(t, evicted[0].Evicted)
+
+		// The unconfirmed unspents of the evicted transaction are removed
+		err = db.View("", func(tx *dbutil.Tx) error {
+			uxs, err := pool.GetUnspentsOfAddr(tx, txnA.Out[0].Address)
+			require.NoError(t, err)
+			require.Empty(t, uxs)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("max txns", func(t *testing.T) {
+		testEviction(t, func(uint32) UnconfirmedPoolLimits {
+			return UnconfirmedPoolLimits{
+				MaxTxns: 2,
+			}
+		})
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		testEviction(t, func(txnSize uint32) UnconfirmedPoolLimits {
+			return UnconfirmedPoolLimits{
+				MaxSize: uint64(txnSize)*2 + 1,
+			}
+		})
+	})
+
+	t.Run("invalid transactions are evicted first", func(t *testing.T) {
+		v, db, bc, shutdown := setup(t, UnconfirmedPoolLimits{
+			MaxTxns: 1,
+		})
+		defer shutdown()
+
+		// A transaction with too many decimal places is injected, but is invalid
+		invalidTxn := makeTxn(t, db, bc, 10e6+1, 800e6)
+		_, softErr, err := v.InjectForeignTransaction(invalidTxn)
+		require.NoError(t, err)
+		require.NotNil(t, softErr)
+
+		validTxn := makeTxn(t, db, bc, 10e6, 550e6)
+		_, softErr, err = v.InjectForeignTransaction(validTxn)
+		require.NoError(t, err)
+		require.Nil(t, softErr)
+
+		requirePool(t, v, validTxn)
+		evicted := v.GetEvictedUnconfirmedTransactions()
+		require.Len(t, evicted, 1)
+		require.Equal(t, invalidTxn, evicted[0].Transaction)
+	})
+}
+
+func TestUnconfirmedFeeIndex(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	_, s := cipher.GenerateKeyPair()
+	bc := MakeBlockchain(t, db, s)
+
+	pool, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
+	require.NoError(t, err)
+
+	v := setupSimpleVisor(t, db, bc)
+	v.Unconfirmed = pool
+
+	txnA := CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), 10e6, 1000, 600e6)
+	txnB := CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), 10e6, 1000, 700e6)
+	// A transaction with too many decimal places is invalid
+	invalidTxn := CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), 10e6+1, 1000, 800e6)
+
+	// txnB is injected first, so that txnA burns less and does not replace it
+	for _, txn := range []coin.Transaction{txnB, txnA, invalidTxn} {
+		_, _, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+	}
+
+	requireIndex := func(t *testing.T, txns []coin.Transaction, fees []uint64) {
+		err := db.View("", func(tx *dbutil.Tx) error {
+			var hashes []cipher.SHA256
+			var indexFees []uint64
+			err := pool.fees.forEach(tx, func(e feeIndexEntry) (bool, error) {
+				hashes = append(hashes, e.hash)
+				indexFees = append(indexFees, e.fee)
+				return true, nil
+			})
+			require.NoError(t, err)
+
+			var expectedHashes []cipher.SHA256
+			var expectedSize uint64
+			for _, txn := range txns {
+				expectedHashes = append(expectedHashes, txn.Hash())
+				expectedSize += uint64(txn.Length)
+			}
+			require.Equal(t, expectedHashes, hashes)
+			require.Equal(t, fees, indexFees)
+
+			count, size, ok, err := pool.fees.stats(tx)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, uint64(len(txns)), count)
+			require.Equal(t, expectedSize, size)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	// Invalid transactions come first, with no fee, then the lowest fee per byte
+	requireIndex(t, []coin.Transaction{invalidTxn, txnA, txnB}, []uint64{0, 600e6, 700e6})
+
+	// The index of a pool saved without it is built again
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		for _, b := range [][]byte{UnconfirmedFeeIndexBkt, UnconfirmedFeeIndexKeysBkt, UnconfirmedMetaBkt} {
+			require.NoError(t, dbutil.Reset(tx, b))
+		}
+		return pool.MaybeBuildIndexes(tx, bc)
+	})
+	require.NoError(t, err)
+	requireIndex(t, []coin.Transaction{invalidTxn, txnA, txnB}, []uint64{0, 600e6, 700e6})
+
+	// A built index is not built again
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		require.NoError(t, dbutil.Reset(tx, UnconfirmedFeeIndexBkt))
+		return pool.MaybeBuildIndexes(tx, bc)
+	})
+	require.NoError(t, err)
+	err = db.View("", func(tx *dbutil.Tx) error {
+		empty, err := dbutil.IsEmpty(tx, UnconfirmedFeeIndexBkt)
+		require.NoError(t, err)
+		require.True(t, empty)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		require.NoError(t, dbutil.Reset(tx, UnconfirmedMetaBkt))
+		return pool.MaybeBuildIndexes(tx, bc)
+	})
+	require.NoError(t, err)
+
+	// Removed transactions are removed from the index
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return pool.RemoveTransactions(tx, []cipher.SHA256{txnA.Hash()})
+	})
+	require.NoError(t, err)
+	requireIndex(t, []coin.Transaction{invalidTxn, txnB}, []uint64{0, 700e6})
+}
+
+func TestInjectTransactionReplaceByFee(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	_, s := cipher.GenerateKeyPair()
+	bc := MakeBlockchain(t, db, s)
+
+	pool, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
+	require.NoError(t, err)
+
+	v := setupSimpleVisor(t, db, bc)
+	v.Unconfirmed = pool
+
+	// The transactions all spend the genesis output
+	makeTxn := func(coins, fee uint64) coin.Transaction {
+		return CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), coins, 1000, fee)
+	}
+
+	requirePool := func(txns ...coin.Transaction) {
+		var hashes []cipher.SHA256
+		err := db.View("", func(tx *dbutil.Tx) error {
+			var err error
+			hashes, err = pool.GetHashes(tx, All)
+			return err
+		})
+		require.NoError(t, err)
+
+		expected := make(map[cipher.SHA256]struct{}, len(txns))
+		for _, txn := range txns {
+			expected[txn.Hash()] = struct{}{}
+		}
+
+		actual := make(map[cipher.SHA256]struct{}, len(hashes))
+		for _, h := range hashes {
+			actual[h] = struct{}{}
+		}
+
+		require.Equal(t, expected, actual)
+	}
+
+	filterKnown := func(hashes ...cipher.SHA256) []cipher.SHA256 {
+		var unknown []cipher.SHA256
+		err := db.View("", func(tx *dbutil.Tx) error {
+			var err error
+			unknown, err = pool.FilterKnown(tx, hashes)
+			return err
+		})
+		require.NoError(t, err)
+		return unknown
+	}
+
+	txnA := makeTxn(10e6, 550e6)
+	txnB := makeTxn(10e6, 600e6)
+	txnC := makeTxn(10e6, 600e6)
+	txnD := makeTxn(10e6+1, 900e6)
+
+	known, softErr, err := v.InjectForeignTransaction(txnA)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+	requirePool(txnA)
+
+	// txnB burns more coin hours than txnA, so it replaces txnA
+	known, softErr, err = v.InjectForeignTransaction(txnB)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+	requirePool(txnB)
+
+	replaced := pool.GetReplaced()
+	require.Len(t, replaced, 1)
+	require.Equal(t, txnA, replaced[0].Transaction)
+	require.Equal(t, uint64(550e6), replaced[0].Fee)
+	require.Equal(t, txnB.Hash(), replaced[0].ReplacedBy)
+	require.NotZero(t, replaced[0].Replaced)
+
+	// The unconfirmed unspents of the replaced transaction are removed
+	err = db.View("", func(tx *dbutil.Tx) error {
+		uxs, err := pool.GetUnspentsOfAddr(tx, txnA.Out[0].Address)
+		require.NoError(t, err)
+		require.Empty(t, uxs)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The replaced transaction is rejected, and is not requested from peers
+	_, _, err = v.InjectForeignTransaction(txnA)
+	require.Equal(t, ErrTxnReplaced, err)
+	require.Equal(t, []cipher.SHA256{txnC.Hash()}, filterKnown(txnA.Hash(), txnB.Hash(), txnC.Hash()))
+
+	// txnC burns the same coin hours as txnB, so it is added without replacing txnB
+	known, softErr, err = v.InjectForeignTransaction(txnC)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+	requirePool(txnB, txnC)
+
+	// txnD is invalid, so it can't replace other transactions
+	known, softErr, err = v.InjectForeignTransaction(txnD)
+	require.NoError(t, err)
+	require.NotNil(t, softErr)
+	require.False(t, known)
+	requirePool(txnB, txnC, txnD)
+	require.Len(t, pool.GetReplaced(), 1)
+
+	// Once the replacement leaves the pool, the replaced transaction can be injected again
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return pool.RemoveTransactions(tx, []cipher.SHA256{txnB.Hash()})
+	})
+	require.NoError(t, err)
+	require.Equal(t, []cipher.SHA256{txnA.Hash()}, filterKnown(txnA.Hash()))
+
+	known, softErr, err = v.InjectForeignTransaction(txnA)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+	requirePool(txnA, txnC, txnD)
+}
+
+func setupChainVisor(t *testing.T, db *dbutil.DB) (*Visor, coin.UxArray) {
+	bc, err := NewBlockchain(db, BlockchainConfig{
+		Pubkey: genPublic,
+	})
+	require.NoError(t, err)
+
+	unconfirmed, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
+	require.NoError(t, err)
+
+	cfg := NewConfig()
+	cfg.DBPath = db.Path()
+	cfg.IsBlockPublisher = true
+	cfg.BlockchainSeckey = genSecret
+	cfg.BlockchainPubkey = genPublic
+	cfg.GenesisAddress = genAddress
+
+	v := &Visor{
+		Config:      cfg,
+		Unconfirmed: unconfirmed,
+		Blockchain:  bc,
+		DB:          db,
+		history:     historydb.New(),
+	}
+
+	gb := addGenesisBlockToVisor(t, v)
+	return v, coin.CreateUnspents(gb.Head, gb.Body.Transactions[0])
+}
+
+// makeChildTxn creates a transaction spending the first output of an unconfirmed parent transaction
+func makeChildTxn(t *testing.T, v *Visor, parent coin.Transaction, toAddr cipher.Address, coins, fee uint64) (coin.Transaction, coin.UxOut) {
+	var head *coin.SignedBlock
+	err := v.DB.View("", func(tx *dbutil.Tx) error {
+		var err error
+		head, err = v.Blockchain.Head(tx)
+		return err
+	})
+	require.NoError(t, err)
+
+	// The outputs of unconfirmed transactions are created as if they are confirmed in the next block
+	uxs := coin.CreateUnspents(coin.BlockHeader{
+		BkSeq: head.Head.BkSeq + 1,
+		Time:  head.Head.Time,
+	}, parent)
+
+	return makeSpendTxWithFee(t, uxs[:1], []cipher.SecKey{genSecret}, toAddr, coins, fee), uxs[0]
+}
+
+func TestInjectTransactionChain(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+
+	requirePool := func(txns ...coin.Transaction) {
+		hashes, err := v.GetAllValidUnconfirmedTxHashes()
+		require.NoError(t, err)
+
+		expected := make(map[cipher.SHA256]struct{}, len(txns))
+		for _, txn := range txns {
+			expected[txn.Hash()] = struct{}{}
+		}
+
+		actual := make(map[cipher.SHA256]struct{}, len(hashes))
+		for _, h := range hashes {
+			actual[h] = struct{}{}
+		}
+
+		require.Equal(t, expected, actual)
+	}
+
+	addr := testutil.MakeAddress()
+	parent := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 10e6)
+	child, parentUx := makeChildTxn(t, v, parent, genAddress, 100e6, 10e6)
+	grandchild, _ := makeChildTxn(t, v, child, addr, 50e6, 10e6)
+
+	// A child can't be injected before its parent
+	_, _, err := v.InjectForeignTransaction(child)
+	testutil.RequireError(t, err, NewErrTxnViolatesHardConstraint(blockdb.NewErrUnspentNotExist(parentUx.Hash().Hex())).Error())
+	requirePool()
+
+	known, softErr, err := v.InjectForeignTransaction(parent)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+
+	// The child spends the output of the unconfirmed parent, which has no coin hours accrued
+	known, head, inputs, err := v.InjectUserTransaction(child)
+	require.NoError(t, err)
+	require.False(t, known)
+	require.Equal(t, uint64(0), head.Head.BkSeq)
+	require.Equal(t, coin.UxArray{parentUx}, inputs)
+
+	known, softErr, err = v.InjectForeignTransaction(grandchild)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+	requirePool(parent, child, grandchild)
+
+	// The outputs spent by the children are not predicted unspent outputs
+	bps, err := v.GetBalanceOfAddrs([]cipher.Address{genAddress})
+	require.NoError(t, err)
+	require.Equal(t, genCoins, bps[0].Confirmed.Coins)
+	require.Equal(t, uint64(500e6+400e6+50e6), bps[0].Predicted.Coins)
+
+	outgoing, err := v.UnconfirmedOutgoingOutputs()
+	require.NoError(t, err)
+	require.Equal(t, genesisUxs, outgoing)
+
+	incoming, err := v.UnconfirmedIncomingOutputs()
+	require.NoError(t, err)
+	require.Len(t, incoming, 4)
+
+	// The inputs of the child are found in the unconfirmed pool
+	txn, txnInputs, err := v.GetTransactionWithInputs(child.Hash())
+	require.NoError(t, err)
+	require.Equal(t, child, txn.Transaction)
+	require.Len(t, txnInputs, 1)
+	require.Equal(t, parentUx, txnInputs[0].UxOut)
+
+	createAndExecuteBlock := func(when uint64) coin.SignedBlock {
+		var sb coin.SignedBlock
+		err := db.Update("", func(tx *dbutil.Tx) error {
+			var err error
+			sb, err = v.createBlock(tx, when)
+			if err != nil {
+				return err
+			}
+			return v.executeSignedBlock(tx, sb)
+		})
+		require.NoError(t, err)
+		return sb
+	}
+
+	// The children wait for their parents to be confirmed
+	sb := createAndExecuteBlock(genTime + 100)
+	require.Equal(t, coin.Transactions{parent}, sb.Body.Transactions)
+	requirePool(child, grandchild)
+
+	removed, err := v.RemoveInvalidUnconfirmed()
+	require.NoError(t, err)
+	require.Empty(t, removed)
+
+	sb = createAndExecuteBlock(genTime + 200)
+	require.Equal(t, coin.Transactions{child}, sb.Body.Transactions)
+	requirePool(grandchild)
+
+	sb = createAndExecuteBlock(genTime + 300)
+	require.Equal(t, coin.Transactions{grandchild}, sb.Body.Transactions)
+	requirePool()
+}
+
+func TestRemoveInvalidUnconfirmedChain(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+
+	requirePool := func(txns ...coin.Transaction) {
+		var hashes []cipher.SHA256
+		err := db.View("", func(tx *dbutil.Tx) error {
+			var err error
+			hashes, err = v.Unconfirmed.GetHashes(tx, All)
+			return err
+		})
+		require.NoError(t, err)
+
+		expected := make(map[cipher.SHA256]struct{}, len(txns))
+		for _, txn := range txns {
+			expected[txn.Hash()] = struct{}{}
+		}
+
+		actual := make(map[cipher.SHA256]struct{}, len(hashes))
+		for _, h := range hashes {
+			actual[h] = struct{}{}
+		}
+
+		require.Equal(t, expected, actual)
+	}
+
+	parent := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 10e6)
+	child, _ := makeChildTxn(t, v, parent, genAddress, 100e6, 10e6)
+
+	// An invalid parent conflicting with parent, and its child
+	invalidParent := makeSpendTxWithHoursBurned(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 100e6)
+	invalidChild, _ := makeChildTxn(t, v, invalidParent, genAddress, 100e6, 10e6)
+
+	for _, txn := range []coin.Transaction{parent, child, invalidParent} {
+		_, _, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+	}
+
+	// The child of an invalid transaction is invalid
+	known, softErr, err := v.InjectForeignTransaction(invalidChild)
+	require.NoError(t, err)
+	require.False(t, known)
+	require.Equal(t, NewErrTxnViolatesSoftConstraint(ErrTxnSpendsInvalidUnconfirmed), *softErr)
+
+	_, _, _, err = v.InjectUserTransaction(invalidChild)
+	require.Equal(t, NewErrTxnViolatesSoftConstraint(ErrTxnSpendsInvalidUnconfirmed), err)
+
+	nowValid, err := v.RefreshUnconfirmed()
+	require.NoError(t, err)
+	require.Empty(t, nowValid)
+	requirePool(parent, child, invalidParent, invalidChild)
+
+	validHashes, err := v.GetAllValidUnconfirmedTxHashes()
+	require.NoError(t, err)
+	require.Len(t, validHashes, 2)
+	require.Contains(t, validHashes, parent.Hash())
+	require.Contains(t, validHashes, child.Hash())
+
+	// A block confirms a transaction double spending the parents' input
+	doubleSpend := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, testutil.MakeAddress(), 1e6, 10e6)
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		b, err := v.Blockchain.NewBlock(tx, coin.Transactions{doubleSpend}, genTime+100)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, v.signBlock(*b))
+	})
+	require.NoError(t, err)
+
+	// The parents and their whole chains are removed
+	removed, err := v.RemoveInvalidUnconfirmed()
+	require.NoError(t, err)
+	require.Len(t, removed, 4)
+	requirePool()
+}
+
+func TestInjectTransactionEvictionChain(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	pool := v.Unconfirmed.(*UnconfirmedTransactionPool)
+
+	// Split the genesis output into two confirmed outputs with coin hours.
+	// Their hours differ, since a transaction can't create duplicate outputs
+	split := coin.Transaction{}
+	err := split.PushInput(genesisUxs[0].Hash())
+	require.NoError(t, err)
+	hours := genesisUxs[0].Body.Hours / 4
+	for i := uint64(0); i < 2; i++ {
+		err = split.PushOutput(genAddress, genCoins/2, hours-i)
+		require.NoError(t, err)
+	}
+	split.SignInputs([]cipher.SecKey{genSecret})
+	err = split.UpdateHeader()
+	require.NoError(t, err)
+
+	_, softErr, err := v.InjectForeignTransaction(split)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	var sb coin.SignedBlock
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		var err error
+		sb, err = v.createBlock(tx, genTime+100)
+		if err != nil {
+			return err
+		}
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+	require.Equal(t, coin.Transactions{split}, sb.Body.Transactions)
+	uxs := coin.CreateUnspents(sb.Head, split)
+
+	// The parent has a change output, so it burns less per byte than its child, which burns all the hours
+	// of the parent's output, and the other transaction, which burns more hours
+	parent := makeSpendTxWithHoursBurned(t, uxs[:1], []cipher.SecKey{genSecret}, genAddress, genCoins/4, hours/2)
+	_, parentUx := makeChildTxn(t, v, parent, genAddress, genCoins/4, 0)
+	child := makeSpendTxWithHoursBurned(t, coin.UxArray{parentUx}, []cipher.SecKey{genSecret}, genAddress, genCoins/4, parentUx.Body.Hours)
+	other := makeSpendTxWithHoursBurned(t, uxs[1:], []cipher.SecKey{genSecret}, genAddress, genCoins/2, uxs[1].Body.Hours*3/5)
+
+	for _, txn := range []coin.Transaction{parent, child} {
+		_, softErr, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+		require.Nil(t, softErr)
+	}
+
+	// The parent is evicted with its child, to make room for the other transaction
+	pool.limits.MaxTxns = 2
+	known, softErr, err := v.InjectForeignTransaction(other)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+
+	hashes, err := v.GetAllValidUnconfirmedTxHashes()
+	require.NoError(t, err)
+	require.Equal(t, []cipher.SHA256{other.Hash()}, hashes)
+
+	evicted := v.GetEvictedUnconfirmedTransactions()
+	require.Len(t, evicted, 2)
+	require.Equal(t, parent, evicted[0].Transaction)
+	require.Equal(t, hours/2, evicted[0].Fee)
+	require.Equal(t, child, evicted[1].Transaction)
+
+	err = db.View("", func(tx *dbutil.Tx) error {
+		count, size, _, err := pool.fees.stats(tx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), count)
+		require.Equal(t, uint64(other.Length), size)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestInjectTransactionChainLimits(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	pool := v.Unconfirmed.(*UnconfirmedTransactionPool)
+
+	// A chain of UnconfirmedChainMaxTxns transactions, each spending the output of the previous one
+	chain := []coin.Transaction{makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, genCoins, 0)}
+	for len(chain) < UnconfirmedChainMaxTxns+1 {
+		child, _ := makeChildTxn(t, v, chain[len(chain)-1], genAddress, genCoins, 0)
+		chain = append(chain, child)
+	}
+
+	for _, txn := range chain[:UnconfirmedChainMaxTxns] {
+		_, softErr, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+		require.Nil(t, softErr)
+	}
+
+	// The last transaction would have UnconfirmedChainMaxTxns unconfirmed ancestors
+	_, _, err := v.InjectForeignTransaction(chain[UnconfirmedChainMaxTxns])
+	require.Equal(t, ErrUnconfirmedChainTooLong, err)
+
+	// A transaction already in the pool is not counted as its own descendant
+	known, softErr, err := v.InjectForeignTransaction(chain[UnconfirmedChainMaxTxns-1])
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.True(t, known)
+
+	// The outputs and spends of the pooled transactions are indexed
+	err = db.View("", func(tx *dbutil.Tx) error {
+		for i, txn := range chain[:UnconfirmedChainMaxTxns] {
+			h, ok, err := pool.chainIndex.creator(tx, unconfirmedOutputHash(txn, 0))
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, txn.Hash(), h)
+
+			children, err := pool.children(tx, txn)
+			require.NoError(t, err)
+			if i < UnconfirmedChainMaxTxns-1 {
+				require.Equal(t, []cipher.SHA256{chain[i+1].Hash()}, children)
+			} else {
+				require.Empty(t, children)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// A pool saved without the index has it built when loaded
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		for _, b := range [][]byte{UnconfirmedOutputsBkt, UnconfirmedSpendsBkt} {
+			if err := dbutil.Reset(tx, b); err != nil {
+				return err
+			}
+		}
+		if err := dbutil.Delete(tx, UnconfirmedMetaBkt, unconfirmedChainIndexKey); err != nil {
+			return err
+		}
+
+		if err := pool.MaybeBuildIndexes(tx, v.Blockchain); err != nil {
+			return err
+		}
+
+		h, ok, err := pool.chainIndex.creator(tx, unconfirmedOutputHash(chain[0], 0))
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, chain[0].Hash(), h)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Removing the chain removes it from the index
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		if err := pool.RemoveTransactions(tx, coin.Transactions(chain[:UnconfirmedChainMaxTxns]).Hashes()); err != nil {
+			return err
+		}
+
+		for _, b := range [][]byte{UnconfirmedOutputsBkt, UnconfirmedSpendsBkt} {
+			err := dbutil.ForEach(tx, b, func(k, _ []byte) error {
+				return fmt.Errorf("unexpected key %x in %s", k, b)
+			})
+			require.NoError(t, err)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// A parent with UnconfirmedChainMaxTxns outputs can have UnconfirmedChainMaxTxns-1 unconfirmed children
+	parent := coin.Transaction{}
+	err = parent.PushInput(genesisUxs[0].Hash())
+	require.NoError(t, err)
+	for i := uint64(0); i < UnconfirmedChainMaxTxns; i++ {
+		err = parent.PushOutput(genAddress, genCoins/UnconfirmedChainMaxTxns, genCoins/(4*UnconfirmedChainMaxTxns)-i)
+		require.NoError(t, err)
+	}
+	parent.SignInputs([]cipher.SecKey{genSecret})
+	err = parent.UpdateHeader()
+	require.NoError(t, err)
+
+	_, softErr, err = v.InjectForeignTransaction(parent)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+
+	var head *coin.SignedBlock
+	err = db.View("", func(tx *dbutil.Tx) error {
+		var err error
+		head, err = v.Blockchain.Head(tx)
+		return err
+	})
+	require.NoError(t, err)
+	parentUxs := coin.CreateUnspents(coin.BlockHeader{
+		BkSeq: head.Head.BkSeq + 1,
+		Time:  head.Head.Time,
+	}, parent)
+
+	for i := range parentUxs {
+		child := makeSpendTxWithFee(t, parentUxs[i:i+1], []cipher.SecKey{genSecret}, genAddress, parentUxs[i].Body.Coins, 0)
+		_, softErr, err := v.InjectForeignTransaction(child)
+		if i < UnconfirmedChainMaxTxns-1 {
+			require.NoError(t, err)
+			require.Nil(t, softErr)
+		} else {
+			// The parent would have UnconfirmedChainMaxTxns unconfirmed descendants
+			require.Equal(t, ErrUnconfirmedChainTooLong, err)
+		}
+	}
+}
+
+func TestInjectTransactionReplaceByFeeIncrement(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	_, s := cipher.GenerateKeyPair()
+	bc := MakeBlockchain(t, db, s)
+
+	pool, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
+	require.NoError(t, err)
+
+	v := setupSimpleVisor(t, db, bc)
+	v.Unconfirmed = pool
+
+	// The transactions all spend the genesis output, and have the same size
+	makeTxn := func(fee uint64) coin.Transaction {
+		return CreateGenesisSpendTransaction(t, db, bc, testutil.MakeAddress(), 10e6, 1000, fee)
+	}
+
+	txnA := makeTxn(550e6)
+	increment := MinReplacementFeeIncrement(txnA.Length)
+	require.True(t, increment > 1)
+
+	_, _, err = v.InjectForeignTransaction(txnA)
+	require.NoError(t, err)
+
+	// txnB burns more coin hours than txnA, but less than the increment more, so it doesn't replace txnA
+	txnB := makeTxn(550e6 + increment - 1)
+	require.Equal(t, txnA.Length, txnB.Length)
+	_, _, err = v.InjectForeignTransaction(txnB)
+	require.NoError(t, err)
+	require.Empty(t, pool.GetReplaced())
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return pool.RemoveTransactions(tx, []cipher.SHA256{txnB.Hash()})
+	})
+	require.NoError(t, err)
+
+	// txnC burns the increment more than txnA, so it replaces txnA
+	txnC := makeTxn(550e6 + increment)
+	require.Equal(t, txnA.Length, txnC.Length)
+	_, _, err = v.InjectForeignTransaction(txnC)
+	require.NoError(t, err)
+
+	replaced := pool.GetReplaced()
+	require.Len(t, replaced, 1)
+	require.Equal(t, txnA, replaced[0].Transaction)
+	require.Equal(t, txnC.Hash(), replaced[0].ReplacedBy)
+}
+
+func TestMinReplacementFeeIncrement(t *testing.T) {
+	require.Equal(t, uint64(0), MinReplacementFeeIncrement(0))
+	require.Equal(t, uint64(1), MinReplacementFeeIncrement(1))
+	require.Equal(t, uint64(1), MinReplacementFeeIncrement(100))
+	require.Equal(t, uint64(2), MinReplacementFeeIncrement(101))
+	require.Equal(t, uint64(10), MinReplacementFeeIncrement(1000))
+	require.Equal(t, uint64(328), MinReplacementFeeIncrement(32*1024))
+}
+
+func TestInjectTransactionReplaceChain(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	pool := v.Unconfirmed.(*UnconfirmedTransactionPool)
+
+	parent := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 10e6)
+	child, _ := makeChildTxn(t, v, parent, genAddress, 100e6, 10e6)
+
+	for _, txn := range []coin.Transaction{parent, child} {
+		_, _, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+	}
+
+	// A transaction conflicting with the parent must burn more than the parent and its child together.
+	// The parent burns 510e6 coin hours and the child burns 255e6 coin hours.
+	// txnA burns 750e6 coin hours, so it is added alongside them
+	txnA := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, testutil.MakeAddress(), 1e6, 250e6)
+	_, _, err := v.InjectForeignTransaction(txnA)
+	require.NoError(t, err)
+	require.Empty(t, pool.GetReplaced())
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return pool.RemoveTransactions(tx, []cipher.SHA256{txnA.Hash()})
+	})
+	require.NoError(t, err)
+
+	// txnB burns 900e6 coin hours, so it replaces the parent and its child
+	txnB := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, testutil.MakeAddress(), 1e6, 400e6)
+
+	// The conflicts are found in the spends index, and the child through the outputs of the parent
+	err = db.View("", func(tx *dbutil.Tx) error {
+		conflicts, err := pool.conflicts(tx, txnB)
+		require.NoError(t, err)
+		require.Equal(t, []cipher.SHA256{parent.Hash()}, conflicts)
+
+		utxns, err := pool.getTxns(tx, conflicts)
+		require.NoError(t, err)
+		descendants, err := pool.descendants(tx, utxns)
+		require.NoError(t, err)
+		require.Len(t, descendants, 1)
+		require.Equal(t, child, descendants[0].Transaction)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, _, err = v.InjectForeignTransaction(txnB)
+	require.NoError(t, err)
+
+	replaced := pool.GetReplaced()
+	require.Len(t, replaced, 2)
+	require.Equal(t, parent, replaced[0].Transaction)
+	require.Equal(t, uint64(510e6), replaced[0].Fee)
+	require.Equal(t, child, replaced[1].Transaction)
+	require.Equal(t, uint64(255e6), replaced[1].Fee)
+	for _, r := range replaced {
+		require.Equal(t, txnB.Hash(), r.ReplacedBy)
+	}
+
+	hashes, err := v.GetAllValidUnconfirmedTxHashes()
+	require.NoError(t, err)
+	require.Equal(t, []cipher.SHA256{txnB.Hash()}, hashes)
+}
+
+func TestCreateBlockChildPaysForParent(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+
+	// Split the genesis output into outputs for the parent, the competing transaction and the child
+	split := coin.Transaction{}
+	err := split.PushInput(genesisUxs[0].Hash())
+	require.NoError(t, err)
+	require.NoError(t, split.PushOutput(genAddress, 300e6, 100e6))
+	require.NoError(t, split.PushOutput(genAddress, 200e6, 100e6))
+	require.NoError(t, split.PushOutput(genAddress, 500e6, 300e6))
+	split.SignInputs([]cipher.SecKey{genSecret})
+	require.NoError(t, split.UpdateHeader())
+
+	var sb coin.SignedBlock
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		b, err := v.Blockchain.NewBlock(tx, coin.Transactions{split}, genTime+100)
+		require.NoError(t, err)
+		sb = v.signBlock(*b)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+	uxs := coin.CreateUnspents(sb.Head, split)
+
+	// The parent burns fewer coin hours than the competing transaction of the same size,
+	// but its child spends a confirmed output too and burns many coin hours
+	parent := makeSpendTxWithHoursBurned(t, uxs[:1], []cipher.SecKey{genSecret}, genAddress, 300e6, 50e6)
+	competing := makeSpendTxWithHoursBurned(t, uxs[1:2], []cipher.SecKey{genSecret}, genAddress, 200e6, 80e6)
+	parentUxs := coin.CreateUnspents(coin.BlockHeader{
+		BkSeq: sb.Head.BkSeq + 1,
+		Time:  sb.Head.Time,
+	}, parent)
+	child := makeSpendTxWithHoursBurned(t, coin.UxArray{parentUxs[0], uxs[2]}, []cipher.SecKey{genSecret, genSecret}, genAddress, 800e6, 350e6)
+
+	for _, txn := range []coin.Transaction{parent, competing, child} {
+		known, softErr, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+		require.Nil(t, softErr)
+		require.False(t, known)
+	}
+
+	// Only one of the parent and the competing transaction fits in the block
+	v.Config.MaxBlockTransactionsSize, err = parent.Size()
+	require.NoError(t, err)
+	competingSize, err := competing.Size()
+	require.NoError(t, err)
+	require.Equal(t, v.Config.MaxBlockTransactionsSize, competingSize)
+
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		// Ranked by its own fee, the parent would not be included
+		sorted, err := coin.SortTransactions(coin.Transactions{parent, competing}, v.Blockchain.TransactionFee(tx, sb.Head.Time))
+		require.NoError(t, err)
+		require.Equal(t, coin.Transactions{competing, parent}, sorted)
+
+		sb, err = v.createBlock(tx, genTime+200)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+	require.Equal(t, coin.Transactions{parent}, sb.Body.Transactions)
+
+	// The child is ranked by its own fee once its parent is confirmed
+	v.Config.MaxBlockTransactionsSize = 1024 * 32
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		sb, err = v.createBlock(tx, genTime+300)
+		require.NoError(t, err)
+		return v.executeSignedBlock(tx, sb)
+	})
+	require.NoError(t, err)
+	require.Equal(t, coin.Transactions{child, competing}, sb.Body.Transactions)
+}
+
+func TestRemoveExpiredUnconfirmed(t *testing.T) {
+	db, shutdown := prepareDB(t)
+	defer shutdown()
+
+	v, genesisUxs := setupChainVisor(t, db)
+	pool := v.Unconfirmed.(*UnconfirmedTransactionPool)
+
+	parent := makeSpendTxWithFee(t, genesisUxs, []cipher.SecKey{genSecret}, genAddress, 500e6, 10e6)
+	child, _ := makeChildTxn(t, v, parent, genAddress, 100e6, 10e6)
+	other, _ := makeChildTxn(t, v, parent, testutil.MakeAddress(), 200e6, 10e6)
+
+	for _, txn := range []coin.Transaction{parent, child} {
+		_, _, err := v.InjectForeignTransaction(txn)
+		require.NoError(t, err)
+	}
+
+	// No transaction expires without a maximum age
+	pool.limits.MaxAge = 0
+	expired, err := v.RemoveExpiredUnconfirmed()
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	pool.limits.MaxAge = time.Hour
+	expired, err = v.RemoveExpiredUnconfirmed()
+	require.NoError(t, err)
+	require.Empty(t, expired)
+
+	// The parent was first received two hours ago, so it expires with its child
+	received := time.Now().Add(-2 * time.Hour).UnixNano()
+	err = db.Update("", func(tx *dbutil.Tx) error {
+		return pool.txns.update(tx, parent.Hash(), func(utxn *UnconfirmedTransaction) error {
+			utxn.Received = received
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	// Receiving the parent again records when it was last received, but does not reset its age
+	known, _, err := v.InjectForeignTransaction(parent)
+	require.NoError(t, err)
+	require.True(t, known)
+
+	err = db.View("", func(tx *dbutil.Tx) error {
+		utxn, err := pool.Get(tx, parent.Hash())
+		require.NoError(t, err)
+		require.Equal(t, received, utxn.Received)
+
+		lastReceived, ok, err := pool.LastReceived(tx, parent.Hash())
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, lastReceived > received)
+		require.True(t, lastReceived >= utxn.Checked)
+		return nil
+	})
+	require.NoError(t, err)
+
+	expired, err = v.RemoveExpiredUnconfirmed()
+	require.NoError(t, err)
+	require.Equal(t, []cipher.SHA256{parent.Hash(), child.Hash()}, expired)
+
+	hashes, err := v.GetAllValidUnconfirmedTxHashes()
+	require.NoError(t, err)
+	require.Empty(t, hashes)
+
+	expiredTxns, err := v.GetExpiredUnconfirmedTransactions()
+	require.NoError(t, err)
+	require.Len(t, expiredTxns, 2)
+	require.Equal(t, parent, expiredTxns[0].Transaction)
+	require.Equal(t, received, expiredTxns[0].Received)
+	require.Equal(t, child, expiredTxns[1].Transaction)
+
+	// The expiry is kept in the lifecycles of the transactions, received from peers
+	for _, txn := range []coin.Transaction{parent, child} {
+		l, err := v.GetTransactionLifecycle(txn.Hash())
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		require.Equal(t, TxnLifecycleRejected, l.State)
+		require.Equal(t, TxnLifecycleReasonExpired, l.Reason)
+		require.Equal(t, txn, l.Expired.Transaction)
+	}
+
+	// An expired transaction is reported with the expired status
+	txn, err := v.GetTransaction(parent.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, txn)
+	require.Equal(t, parent, txn.Transaction)
+	require.Equal(t, NewExpiredTransactionStatus(), txn.Status)
+
+	txn, err = v.GetTransaction(other.Hash())
+	require.NoError(t, err)
+	require.Nil(t, txn)
+
+	err = db.View("", func(tx *dbutil.Tx) error {
+		_, ok, err := pool.LastReceived(tx, parent.Hash())
+		require.NoError(t, err)
+		require.False(t, ok)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// An expired transaction can be injected again
+	known, softErr, err := v.InjectForeignTransaction(parent)
+	require.NoError(t, err)
+	require.Nil(t, softErr)
+	require.False(t, known)
+
+	txn, err = v.GetTransaction(parent.Hash())
+	require.NoError(t, err)
+	require.Equal(t, NewUnconfirmedTransactionStatus(), txn.Status)
+}
+
 func TestRefreshUnconfirmed(t *testing.T) {
 	db, shutdown := prepareDB(t)
 	defer shutdown()
@@ -1930,7 +3026,7 @@ func TestRefreshUnconfirmed(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	unconfirmed, err := NewUnconfirmedTransactionPool(db)
+	unconfirmed, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
 	require.NoError(t, err)
 
 	his := historydb.New()
@@ -2062,7 +3158,7 @@ func TestRemoveInvalidUnconfirmedDoubleSpendArbitrating(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	unconfirmed, err := NewUnconfirmedTransactionPool(db)
+	unconfirmed, err := NewUnconfirmedTransactionPool(db, UnconfirmedPoolLimits{})
 	require.NoError(t, err)
 
 	his := historydb.New()
@@ -2095,14 +3191,18 @@ func TestRemoveInvalidUnconfirmedDoubleSpendArbitrating(t *testing.T) {
 
 	uxs := coin.CreateUnspents(gb.Head, gb.Body.Transactions[0])
 
-	// Create two valid transactions, both spending the same inputs, one with a higher fee
+	// Create two valid transactions, both spending the same inputs, one with a higher fee.
+	// The one with the higher fee is injected first, otherwise it would replace the other in the pool.
 	// Then, create a block from these transactions.
 	// The one with the higher fee should be included in the block, and the other should be ignored.
 	// A call to RemoveInvalidUnconfirmed will remove the other txn, because it would now be a double spend.
 
 	var coins uint64 = 10e6
+	var fee uint64 = 1
 	txn1 := makeSpendTxn(t, uxs, []cipher.SecKey{genSecret}, genAddress, coins)
-	known, softErr, err := v.InjectForeignTransaction(txn1)
+	txn2 := makeSpendTxWithFee(t, uxs, []cipher.SecKey{genSecret}, genAddress, coins, fee)
+
+	known, softErr, err := v.InjectForeignTransaction(txn2)
 	require.False(t, known)
 	require.Nil(t, softErr)
 	require.NoError(t, err)
@@ -2115,9 +3215,7 @@ func TestRemoveInvalidUnconfirmedDoubleSpendArbitrating(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	var fee uint64 = 1
-	txn2 := makeSpendTxWithFee(t, uxs, []cipher.SecKey{genSecret}, genAddress, coins, fee)
-	known, softErr, err = v.InjectForeignTransaction(txn2)
+	known, softErr, err = v.InjectForeignTransaction(txn1)
 	require.False(t, known)
 	require.Nil(t, softErr)
 	require.NoError(t, err)
@@ -4,6 +4,8 @@ package visor
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/skycoin/skycoin/src/cipher"
 	"github.com/skycoin/skycoin/src/coin"
@@ -215,6 +217,9 @@ type CreateTransactionParams struct {
 	// IgnoreUnconfirmed if true, outputs matching Addresses or UxOuts spent by
 	// an unconfirmed transactions will be ignored, otherwise an error will be returned
 	IgnoreUnconfirmed bool
+	// Reserve if true, the outputs spent by the transactions created by a wallet are reserved for
+	// Config.OutputReservationTTL, so that they are not chosen by other transactions until they are injected
+	Reserve bool
 }
 
 // Validate validates params
@@ -262,10 +267,13 @@ func (vs *Visor) WalletCreateTransactionSigned(wltID string, password []byte, p
 	var txn *coin.Transaction
 	var inputs []TransactionInput
 
-	if err := vs.Wallets.ViewSecrets(wltID, password, func(w *wallet.Wallet) error {
+	if err := vs.openWalletCreateTransaction(wltID, password, &p, TxnSigned, func(w *wallet.Wallet) ([]coin.Transaction, error) {
 		var err error
 		txn, inputs, err = vs.walletCreateTransaction("WalletCreateTransactionSigned", w, p, wp, TxnSigned)
-		return err
+		if err != nil {
+			return nil, err
+		}
+		return []coin.Transaction{*txn}, nil
 	}); err != nil {
 		return nil, nil, err
 	}
@@ -286,10 +294,13 @@ func (vs *Visor) WalletCreateTransaction(wltID string, p transaction.Params, wp
 	var txn *coin.Transaction
 	var inputs []TransactionInput
 
-	if err := vs.Wallets.View(wltID, func(w *wallet.Wallet) error {
+	if err := vs.openWalletCreateTransaction(wltID, nil, &p, TxnUnsigned, func(w *wallet.Wallet) ([]coin.Transaction, error) {
 		var err error
 		txn, inputs, err = vs.walletCreateTransaction("WalletCreateTransaction", w, p, wp, TxnUnsigned)
-		return err
+		if err != nil {
+			return nil, err
+		}
+		return []coin.Transaction{*txn}, nil
 	}); err != nil {
 		return nil, nil, err
 	}
@@ -297,6 +308,77 @@ func (vs *Visor) WalletCreateTransaction(wltID string, p transaction.Params, wp
 	return txn, inputs, nil
 }
 
+// errChangeAddressUnused aborts the update of a wallet whose new change address is not paid by the created transactions
+var errChangeAddressUnused = errors.New("change address is not used by the created transactions")
+
+// openWalletCreateTransaction opens a wallet to create transactions from it with f, with its secrets if signed.
+// If no change address is specified and the wallet's change address policy is fresh,
+// a new change address is generated in the wallet and set in p before calling f.
+// The wallet is then saved with the new address, if f succeeds and one of the transactions it returns
+// pays change to the address. Otherwise the address is discarded and the wallet is not changed.
+func (vs *Visor) openWalletCreateTransaction(wltID string, password []byte, p *transaction.Params, signed TxnSignedFlag, f func(*wallet.Wallet) ([]coin.Transaction, error)) error {
+	fresh := false
+	if p.ChangeAddress == nil {
+		if err := vs.Wallets.View(wltID, func(w *wallet.Wallet) error {
+			fresh = w.ChangeAddressPolicy() == wallet.ChangeAddressPolicyFresh
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !fresh {
+		view := func(w *wallet.Wallet) error {
+			_, err := f(w)
+			return err
+		}
+
+		switch signed {
+		case TxnSigned:
+			return vs.Wallets.ViewSecrets(wltID, password, view)
+		default:
+			return vs.Wallets.View(wltID, view)
+		}
+	}
+
+	withChangeAddress := func(w *wallet.Wallet) error {
+		changeAddr, err := w.GenerateSkycoinChangeAddress()
+		if err != nil {
+			return err
+		}
+		p.ChangeAddress = &changeAddr
+
+		txns, err := f(w)
+		if err != nil {
+			return err
+		}
+
+		for _, txn := range txns {
+			for _, o := range txn.Out {
+				if o.Address == changeAddr {
+					return nil
+				}
+			}
+		}
+
+		// The wallet service discards the changes of the wallet when the update fails
+		return errChangeAddressUnused
+	}
+
+	var err error
+	switch signed {
+	case TxnSigned:
+		err = vs.Wallets.UpdateSecrets(wltID, password, withChangeAddress)
+	default:
+		err = vs.Wallets.Update(wltID, withChangeAddress)
+	}
+
+	if err == errChangeAddressUnused {
+		return nil
+	}
+	return err
+}
+
 func (vs *Visor) walletCreateTransaction(methodName string, w *wallet.Wallet, p transaction.Params, wp CreateTransactionParams, signed TxnSignedFlag) (*coin.Transaction, []TransactionInput, error) {
 	if err := p.Validate(); err != nil {
 		return nil, nil, err
@@ -305,6 +387,46 @@ func (vs *Visor) walletCreateTransaction(methodName string, w *wallet.Wallet, p
 		return nil, nil, err
 	}
 
+	addrs, walletAddressesMap, err := walletSpendAddresses(w, wp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var txn *coin.Transaction
+	var uxb []transaction.UxBalance
+
+	// Reserving the outputs writes to the database, which also serializes the selection of
+	// outputs with the other transactions that reserve their outputs
+	dbTx := vs.DB.View
+	if wp.Reserve {
+		dbTx = vs.DB.Update
+	}
+
+	if err := dbTx(methodName, func(tx *dbutil.Tx) error {
+		var err error
+		txn, uxb, err = vs.walletCreateTransactionTx(tx, methodName, w, p, wp, signed, addrs, walletAddressesMap)
+		if err != nil {
+			return err
+		}
+
+		if wp.Reserve {
+			return vs.outputReservations.reserve(tx, w.Filename(), txn, time.Now().UTC(), vs.Config.OutputReservationTTL)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	inputs := NewTransactionInputsFromUxBalance(uxb)
+
+	return txn, inputs, nil
+}
+
+// walletSpendAddresses returns the addresses to spend from for CreateTransactionParams,
+// and a set of all the addresses of the wallet.
+// The addresses are wp.Addresses, which must be in the wallet, or all the wallet addresses if none are specified.
+func walletSpendAddresses(w *wallet.Wallet, wp CreateTransactionParams) ([]cipher.Address, map[cipher.Address]struct{}, error) {
 	// Get all addresses from the wallet for checking params against
 	walletAddresses, err := w.GetSkycoinAddresses()
 	if err != nil {
@@ -329,20 +451,42 @@ func (vs *Visor) walletCreateTransaction(methodName string, w *wallet.Wallet, p
 		}
 	}
 
-	var txn *coin.Transaction
-	var uxb []transaction.UxBalance
+	return addrs, walletAddressesMap, nil
+}
 
-	if err := vs.DB.View(methodName, func(tx *dbutil.Tx) error {
-		var err error
-		txn, uxb, err = vs.walletCreateTransactionTx(tx, methodName, w, p, wp, signed, addrs, walletAddressesMap)
-		return err
-	}); err != nil {
-		return nil, nil, err
+// getWalletCreateTransactionAuxs returns a map of the addresses to their unspent outputs for CreateTransactionParams,
+// checking that the unspent outputs are owned by the wallet.
+// The frozen outputs of the wallet are excluded, or rejected if requested by UxOuts.
+func (vs *Visor) getWalletCreateTransactionAuxs(tx *dbutil.Tx, w *wallet.Wallet, wp CreateTransactionParams,
+	addrs []cipher.Address, walletAddressesMap map[cipher.Address]struct{}) (coin.AddressUxOuts, error) {
+	if len(wp.UxOuts) == 0 {
+		auxs, err := vs.getCreateTransactionAuxsAddress(tx, addrs, wp.IgnoreUnconfirmed)
+		if err != nil {
+			return nil, err
+		}
+
+		return w.ExcludeFrozenUxOuts(auxs), nil
 	}
 
-	inputs := NewTransactionInputsFromUxBalance(uxb)
+	for _, h := range wp.UxOuts {
+		if w.IsFrozen(h) {
+			return nil, wallet.ErrUxOutFrozen
+		}
+	}
 
-	return txn, inputs, nil
+	auxs, err := vs.getCreateTransactionAuxsUxOut(tx, wp.UxOuts, wp.IgnoreUnconfirmed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that UxOut addresses are in the wallet,
+	for a := range auxs {
+		if _, ok := walletAddressesMap[a]; !ok {
+			return nil, wallet.ErrUnknownUxOut
+		}
+	}
+
+	return auxs, nil
 }
 
 func (vs *Visor) walletCreateTransactionTx(tx *dbutil.Tx, methodName string,
@@ -357,26 +501,14 @@ func (vs *Visor) walletCreateTransactionTx(tx *dbutil.Tx, methodName string,
 	}
 
 	// Get mapping of addresses to uxOuts based upon CreateTransactionParams
-	var auxs coin.AddressUxOuts
-	if len(wp.UxOuts) != 0 {
-		var err error
-		auxs, err = vs.getCreateTransactionAuxsUxOut(tx, wp.UxOuts, wp.IgnoreUnconfirmed)
-		if err != nil {
-			return nil, nil, err
-		}
+	auxs, err := vs.getWalletCreateTransactionAuxs(tx, w, wp, addrs, walletAddressesMap)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		// Check that UxOut addresses are in the wallet,
-		for a := range auxs {
-			if _, ok := walletAddressesMap[a]; !ok {
-				return nil, nil, wallet.ErrUnknownUxOut
-			}
-		}
-	} else {
-		var err error
-		auxs, err = vs.getCreateTransactionAuxsAddress(tx, addrs, wp.IgnoreUnconfirmed)
-		if err != nil {
-			return nil, nil, err
-		}
+	p.Reserved, err = vs.getReservedUxOuts(tx, wp)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Create and sign transaction
@@ -413,6 +545,446 @@ func (vs *Visor) walletCreateTransactionTx(tx *dbutil.Tx, methodName string,
 	return txn, uxb, nil
 }
 
+// Batch is a series of transactions that pay a list of receivers
+type Batch struct {
+	Transactions []coin.Transaction
+	Inputs       [][]TransactionInput
+	// Receivers are the indexes in Transactions of the transaction paying each receiver
+	Receivers []int
+}
+
+// WalletCreateBatchTransactionSigned creates signed transactions paying the receivers of transaction.Params,
+// each fitting within the transaction size limit. The unspent outputs are selected by CreateTransactionParams.
+// Refer to transaction.CreateBatch for information about the transactions.
+func (vs *Visor) WalletCreateBatchTransactionSigned(wltID string, password []byte, p transaction.Params, wp CreateTransactionParams) (*Batch, error) {
+	// Validate params before unlocking wallet
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := wp.Validate(); err != nil {
+		return nil, err
+	}
+
+	var b *Batch
+	if err := vs.openWalletCreateTransaction(wltID, password, &p, TxnSigned, func(w *wallet.Wallet) ([]coin.Transaction, error) {
+		var err error
+		b, err = vs.walletCreateBatchTransaction("WalletCreateBatchTransactionSigned", w, p, wp, TxnSigned)
+		if err != nil {
+			return nil, err
+		}
+		return b.Transactions, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// WalletCreateBatchTransaction creates unsigned transactions paying the receivers of transaction.Params,
+// each fitting within the transaction size limit. The unspent outputs are selected by CreateTransactionParams.
+// Refer to transaction.CreateBatch for information about the transactions.
+func (vs *Visor) WalletCreateBatchTransaction(wltID string, p transaction.Params, wp CreateTransactionParams) (*Batch, error) {
+	// Validate params before opening wallet
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := wp.Validate(); err != nil {
+		return nil, err
+	}
+
+	var b *Batch
+	if err := vs.openWalletCreateTransaction(wltID, nil, &p, TxnUnsigned, func(w *wallet.Wallet) ([]coin.Transaction, error) {
+		var err error
+		b, err = vs.walletCreateBatchTransaction("WalletCreateBatchTransaction", w, p, wp, TxnUnsigned)
+		if err != nil {
+			return nil, err
+		}
+		return b.Transactions, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (vs *Visor) walletCreateBatchTransaction(methodName string, w *wallet.Wallet, p transaction.Params, wp CreateTransactionParams, signed TxnSignedFlag) (*Batch, error) {
+	addrs, walletAddressesMap, err := walletSpendAddresses(w, wp)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTx := vs.DB.View
+	if wp.Reserve {
+		dbTx = vs.DB.Update
+	}
+
+	var b *transaction.Batch
+	if err := dbTx(methodName, func(tx *dbutil.Tx) error {
+		head, err := vs.Blockchain.Head(tx)
+		if err != nil {
+			logger.WithError(err).Error("Blockchain.Head failed")
+			return err
+		}
+
+		auxs, err := vs.getWalletCreateTransactionAuxs(tx, w, wp, addrs, walletAddressesMap)
+		if err != nil {
+			return err
+		}
+
+		p.Reserved, err = vs.getReservedUxOuts(tx, wp)
+		if err != nil {
+			return err
+		}
+
+		switch signed {
+		case TxnSigned:
+			b, err = w.CreateBatchTransactionSigned(p, auxs, head.Time())
+		case TxnUnsigned:
+			b, err = w.CreateBatchTransaction(p, auxs, head.Time())
+		default:
+			logger.Panic("Invalid TxnSignedFlag")
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, txn := range b.Transactions {
+			if err := VerifySingleTxnUserConstraints(txn); err != nil {
+				logger.WithError(err).Error("Created batch transaction violates transaction user constraints")
+				return err
+			}
+
+			if _, _, err := vs.Blockchain.VerifySingleTxnSoftHardConstraints(tx, txn, params.UserVerifyTxn, signed); err != nil {
+				logger.WithError(err).Error("Created batch transaction violates transaction soft/hard constraints")
+				return err
+			}
+		}
+
+		if wp.Reserve {
+			now := time.Now().UTC()
+			for i := range b.Transactions {
+				if err := vs.outputReservations.reserve(tx, w.Filename(), &b.Transactions[i], now, vs.Config.OutputReservationTTL); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	inputs := make([][]TransactionInput, len(b.Inputs))
+	for i, uxb := range b.Inputs {
+		inputs[i] = NewTransactionInputsFromUxBalance(uxb)
+	}
+
+	return &Batch{
+		Transactions: b.Transactions,
+		Inputs:       inputs,
+		Receivers:    b.Receivers,
+	}, nil
+}
+
+// Consolidation is a series of transactions that merge the unspent outputs of a wallet into one address
+type Consolidation struct {
+	Transactions []coin.Transaction
+	Inputs       [][]TransactionInput
+	// Coins is the total of coins consolidated
+	Coins uint64
+	// Fee is the total of coin hours burned by the transactions
+	Fee uint64
+	// Skipped are the unspent outputs without coin hours that were left unspent
+	Skipped []TransactionInput
+}
+
+// WalletCreateConsolidationSigned creates signed transactions that merge the unspent outputs of a wallet into one address.
+// The unspent outputs are selected by CreateTransactionParams. Refer to transaction.Consolidate for information about the transactions.
+func (vs *Visor) WalletCreateConsolidationSigned(wltID string, password []byte, p transaction.ConsolidateParams, wp CreateTransactionParams) (*Consolidation, error) {
+	// Validate params before unlocking wallet
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := wp.Validate(); err != nil {
+		return nil, err
+	}
+
+	var c *Consolidation
+	if err := vs.Wallets.ViewSecrets(wltID, password, func(w *wallet.Wallet) error {
+		var err error
+		c, err = vs.walletCreateConsolidation("WalletCreateConsolidationSigned", w, p, wp, TxnSigned)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// WalletCreateConsolidation creates unsigned transactions that merge the unspent outputs of a wallet into one address.
+// The unspent outputs are selected by CreateTransactionParams. Refer to transaction.Consolidate for information about the transactions.
+func (vs *Visor) WalletCreateConsolidation(wltID string, p transaction.ConsolidateParams, wp CreateTransactionParams) (*Consolidation, error) {
+	// Validate params before opening wallet
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := wp.Validate(); err != nil {
+		return nil, err
+	}
+
+	var c *Consolidation
+	if err := vs.Wallets.View(wltID, func(w *wallet.Wallet) error {
+		var err error
+		c, err = vs.walletCreateConsolidation("WalletCreateConsolidation", w, p, wp, TxnUnsigned)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (vs *Visor) walletCreateConsolidation(methodName string, w *wallet.Wallet, p transaction.ConsolidateParams, wp CreateTransactionParams, signed TxnSignedFlag) (*Consolidation, error) {
+	addrs, walletAddressesMap, err := walletSpendAddresses(w, wp)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTx := vs.DB.View
+	if wp.Reserve {
+		dbTx = vs.DB.Update
+	}
+
+	var c *transaction.Consolidation
+	if err := dbTx(methodName, func(tx *dbutil.Tx) error {
+		head, err := vs.Blockchain.Head(tx)
+		if err != nil {
+			logger.WithError(err).Error("Blockchain.Head failed")
+			return err
+		}
+
+		auxs, err := vs.getWalletCreateTransactionAuxs(tx, w, wp, addrs, walletAddressesMap)
+		if err != nil {
+			return err
+		}
+
+		p.Reserved, err = vs.getReservedUxOuts(tx, wp)
+		if err != nil {
+			return err
+		}
+
+		switch signed {
+		case TxnSigned:
+			c, err = w.CreateConsolidationSigned(p, auxs, head.Time())
+		case TxnUnsigned:
+			c, err = w.CreateConsolidation(p, auxs, head.Time())
+		default:
+			logger.Panic("Invalid TxnSignedFlag")
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, txn := range c.Transactions {
+			if err := VerifySingleTxnUserConstraints(txn); err != nil {
+				logger.WithError(err).Error("Created consolidation transaction violates transaction user constraints")
+				return err
+			}
+
+			if _, _, err := vs.Blockchain.VerifySingleTxnSoftHardConstraints(tx, txn, params.UserVerifyTxn, signed); err != nil {
+				logger.WithError(err).Error("Created consolidation transaction violates transaction soft/hard constraints")
+				return err
+			}
+		}
+
+		if wp.Reserve {
+			now := time.Now().UTC()
+			for i := range c.Transactions {
+				if err := vs.outputReservations.reserve(tx, w.Filename(), &c.Transactions[i], now, vs.Config.OutputReservationTTL); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	inputs := make([][]TransactionInput, len(c.Inputs))
+	for i, uxb := range c.Inputs {
+		inputs[i] = NewTransactionInputsFromUxBalance(uxb)
+	}
+
+	return &Consolidation{
+		Transactions: c.Transactions,
+		Inputs:       inputs,
+		Coins:        c.Coins,
+		Fee:          c.Fee,
+		Skipped:      NewTransactionInputsFromUxBalance(c.Skipped),
+	}, nil
+}
+
+// WalletBumpTransactionSigned creates a signed transaction that replaces the unconfirmed transaction txid
+// of a wallet, burning fee coin hours. Refer to walletBumpTransaction for information about the transaction.
+func (vs *Visor) WalletBumpTransactionSigned(wltID string, password []byte, txid cipher.SHA256, fee uint64) (*coin.Transaction, []TransactionInput, error) {
+	var txn *coin.Transaction
+	var inputs []TransactionInput
+
+	if err := vs.Wallets.ViewSecrets(wltID, password, func(w *wallet.Wallet) error {
+		var err error
+		txn, inputs, err = vs.walletBumpTransaction("WalletBumpTransactionSigned", w, txid, fee, TxnSigned)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, inputs, nil
+}
+
+// WalletBumpTransaction creates an unsigned transaction that replaces the unconfirmed transaction txid
+// of a wallet, burning fee coin hours. Refer to walletBumpTransaction for information about the transaction.
+func (vs *Visor) WalletBumpTransaction(wltID string, txid cipher.SHA256, fee uint64) (*coin.Transaction, []TransactionInput, error) {
+	var txn *coin.Transaction
+	var inputs []TransactionInput
+
+	if err := vs.Wallets.View(wltID, func(w *wallet.Wallet) error {
+		var err error
+		txn, inputs, err = vs.walletBumpTransaction("WalletBumpTransaction", w, txid, fee, TxnUnsigned)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, inputs, nil
+}
+
+// walletBumpTransaction creates a transaction that spends the same inputs as the unconfirmed transaction txid,
+// to the same outputs, but burns fee coin hours. fee must exceed the coin hours burned by txid by at least
+// MinReplacementFeeIncrement of the transaction size, for the transaction to replace txid.
+// The extra coin hours are taken from the change outputs, which are the outputs to addresses of the wallet,
+// starting with the last. All of the inputs must be owned by the wallet.
+// Once injected, the transaction replaces txid in the unconfirmed pool.
+func (vs *Visor) walletBumpTransaction(methodName string, w *wallet.Wallet, txid cipher.SHA256, fee uint64, signed TxnSignedFlag) (*coin.Transaction, []TransactionInput, error) {
+	_, walletAddressesMap, err := walletSpendAddresses(w, CreateTransactionParams{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var txn *coin.Transaction
+	var inputs []TransactionInput
+
+	if err := vs.DB.View(methodName, func(tx *dbutil.Tx) error {
+		utxn, err := vs.Unconfirmed.Get(tx, txid)
+		if err != nil {
+			return err
+		}
+		if utxn == nil {
+			return NewUserError(fmt.Errorf("transaction %s is not in the unconfirmed pool", txid.Hex()))
+		}
+
+		headTime, err := vs.Blockchain.Time(tx)
+		if err != nil {
+			logger.WithError(err).Error("Blockchain.Time failed")
+			return err
+		}
+
+		uxOuts, err := vs.Blockchain.Unspent().GetArray(tx, utxn.Transaction.In)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range uxOuts {
+			if _, ok := walletAddressesMap[o.Body.Address]; !ok {
+				return wallet.ErrUnknownUxOut
+			}
+		}
+
+		currentFee, err := vs.Blockchain.TransactionFee(tx, headTime)(&utxn.Transaction)
+		if err != nil {
+			return err
+		}
+
+		// The transaction spends the same inputs to the same outputs, so it has the same size
+		increment := MinReplacementFeeIncrement(utxn.Transaction.Length)
+		if fee < currentFee || fee-currentFee < increment {
+			return NewUserError(fmt.Errorf("fee must be at least %d coin hours more than the %d coin hours burned by the transaction", increment, currentFee))
+		}
+
+		txn, err = bumpTransactionFee(utxn.Transaction, fee-currentFee, walletAddressesMap)
+		if err != nil {
+			return err
+		}
+
+		if signed == TxnSigned {
+			txn, err = w.SignTransaction(txn, nil, uxOuts)
+			if err != nil {
+				logger.WithError(err).Error("wallet.SignTransaction failed")
+				return err
+			}
+		}
+
+		if err := VerifySingleTxnUserConstraints(*txn); err != nil {
+			logger.WithError(err).Error("Bumped transaction violates transaction user constraints")
+			return err
+		}
+
+		if _, _, err := vs.Blockchain.VerifySingleTxnSoftHardConstraints(tx, *txn, params.UserVerifyTxn, signed); err != nil {
+			logger.WithError(err).Error("Bumped transaction violates transaction soft/hard constraints")
+			return err
+		}
+
+		inputs = make([]TransactionInput, len(uxOuts))
+		for i, o := range uxOuts {
+			inputs[i], err = NewTransactionInput(o, headTime)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, inputs, nil
+}
+
+// bumpTransactionFee returns an unsigned copy of txn that burns extraFee more coin hours,
+// taken from the outputs to changeAddrs, starting with the last
+func bumpTransactionFee(txn coin.Transaction, extraFee uint64, changeAddrs map[cipher.Address]struct{}) (*coin.Transaction, error) {
+	bumped := coin.Transaction{
+		In:  append([]cipher.SHA256{}, txn.In...),
+		Out: append([]coin.TransactionOutput{}, txn.Out...),
+	}
+
+	for i := len(bumped.Out) - 1; i >= 0 && extraFee > 0; i-- {
+		o := &bumped.Out[i]
+		if _, ok := changeAddrs[o.Address]; !ok {
+			continue
+		}
+
+		hours := o.Hours
+		if hours > extraFee {
+			hours = extraFee
+		}
+
+		o.Hours -= hours
+		extraFee -= hours
+	}
+
+	if extraFee > 0 {
+		return nil, NewUserError(errors.New("the change outputs of the transaction do not have enough coin hours to burn the fee"))
+	}
+
+	bumped.Sigs = make([]cipher.Sig, len(bumped.In))
+	if err := bumped.UpdateHeader(); err != nil {
+		return nil, err
+	}
+
+	return &bumped, nil
+}
+
 // CreateTransaction creates an unsigned transaction from requested coin.UxOut hashes
 func (vs *Visor) CreateTransaction(p transaction.Params, wp CreateTransactionParams) (*coin.Transaction, []TransactionInput, error) {
 	// Validate parameters before starting database transaction
@@ -461,6 +1033,11 @@ func (vs *Visor) createTransactionTx(tx *dbutil.Tx, p transaction.Params, wp Cre
 		return nil, nil, err
 	}
 
+	p.Reserved, err = vs.getReservedUxOuts(tx, wp)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	txn, uxb, err := transaction.Create(p, auxs, head.Time())
 	if err != nil {
 		return nil, nil, err
@@ -747,6 +747,570 @@ func TestWalletCreateTransaction(t *testing.T) {
 	}
 }
 
+func TestWalletCreateConsolidation(t *testing.T) {
+	addrs := make([]cipher.Address, 2)
+	entries := make([]wallet.Entry, 2)
+	for i := range entries {
+		p, s := cipher.GenerateKeyPair()
+		addrs[i] = cipher.AddressFromPubKey(p)
+		entries[i] = wallet.Entry{
+			Address: addrs[i],
+			Public:  p,
+			Secret:  s,
+		}
+	}
+
+	now := uint64(time.Now().Unix())
+	var uxa coin.UxArray
+	for i := 0; i < 5; i++ {
+		uxa = append(uxa, coin.UxOut{
+			Head: coin.UxHead{
+				Time:  now - 3700,
+				BkSeq: 100,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        addrs[i%2],
+				Coins:          1e6,
+				Hours:          100,
+			},
+		})
+	}
+
+	uxOuts := make([]cipher.SHA256, len(uxa))
+	for i, ux := range uxa {
+		uxOuts[i] = ux.Hash()
+	}
+
+	unknownUxa := append(coin.UxArray{}, uxa...)
+	unknownUxa[0].Body.Address = testutil.MakeAddress()
+
+	headBlock := &coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				Time: now,
+			},
+		},
+	}
+
+	p := transaction.ConsolidateParams{
+		To:        testutil.MakeAddress(),
+		MaxInputs: 2,
+	}
+
+	cases := []struct {
+		name      string
+		p         transaction.ConsolidateParams
+		signed    TxnSignedFlag
+		password  []byte
+		getArray  coin.UxArray
+		verifyErr error
+		err       error
+	}{
+		{
+			name:     "unsigned",
+			p:        p,
+			signed:   TxnUnsigned,
+			getArray: uxa,
+		},
+		{
+			name:     "signed",
+			p:        p,
+			signed:   TxnSigned,
+			password: []byte("foo"),
+			getArray: uxa,
+		},
+		{
+			name:     "invalid params",
+			signed:   TxnUnsigned,
+			getArray: uxa,
+			err:      transaction.ErrNullConsolidationAddress,
+		},
+		{
+			name:     "unknown wallet uxouts",
+			p:        p,
+			signed:   TxnUnsigned,
+			getArray: unknownUxa,
+			err:      wallet.ErrUnknownUxOut,
+		},
+		{
+			name:      "blockchain verify error",
+			p:         p,
+			signed:    TxnSigned,
+			password:  []byte("foo"),
+			getArray:  uxa,
+			verifyErr: NewErrTxnViolatesSoftConstraint(errors.New("Violates soft constraints")),
+			err:       NewErrTxnViolatesSoftConstraint(errors.New("Violates soft constraints")),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := wallet.NewService(wallet.Config{
+				EnableWalletAPI: true,
+				CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+				WalletDir:       prepareWltDir(),
+			})
+			require.NoError(t, err)
+
+			_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+				Coin:       wallet.CoinTypeSkycoin,
+				Seed:       "foo",
+				Encrypt:    len(tc.password) != 0,
+				Password:   tc.password,
+				CryptoType: wallet.CryptoTypeScryptChacha20poly1305Insecure,
+			}, nil)
+			require.NoError(t, err)
+
+			err = ws.UpdateSecrets("foo.wlt", tc.password, func(w *wallet.Wallet) error {
+				for _, e := range entries {
+					err := w.AddEntry(e)
+					require.NoError(t, err)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			b := &MockBlockchainer{}
+			ut := &MockUnconfirmedTransactionPooler{}
+			up := &MockUnspentPooler{}
+
+			b.On("Head", matchDBTx).Return(headBlock, nil)
+			ut.On("ForEach", matchDBTx, mock.MatchedBy(func(f func(cipher.SHA256, UnconfirmedTransaction) error) bool {
+				return true
+			})).Return(nil)
+			up.On("GetArray", matchDBTx, mock.MatchedBy(matchUxOutsAnyOrder(uxOuts))).Return(tc.getArray, nil)
+			b.On("Unspent").Return(up)
+			b.On("VerifySingleTxnSoftHardConstraints", matchDBTx, mock.Anything, params.UserVerifyTxn, tc.signed).Return(nil, nil, tc.verifyErr)
+
+			db, shutdown := prepareDB(t)
+			defer shutdown()
+
+			v := &Visor{
+				DB:          db,
+				Blockchain:  b,
+				Unconfirmed: ut,
+				Wallets:     ws,
+			}
+
+			wp := CreateTransactionParams{
+				UxOuts: uxOuts,
+			}
+
+			var c *Consolidation
+			switch tc.signed {
+			case TxnSigned:
+				c, err = v.WalletCreateConsolidationSigned("foo.wlt", tc.password, tc.p, wp)
+			case TxnUnsigned:
+				c, err = v.WalletCreateConsolidation("foo.wlt", tc.p, wp)
+			}
+			require.Equal(t, tc.err, err, "%v != %v", tc.err, err)
+			if tc.err != nil {
+				return
+			}
+
+			require.Len(t, c.Transactions, 3)
+			require.Len(t, c.Inputs, 3)
+			require.Equal(t, uint64(5e6), c.Coins)
+			require.NotZero(t, c.Fee)
+			require.Empty(t, c.Skipped)
+
+			for i, txn := range c.Transactions {
+				require.Equal(t, tc.signed == TxnSigned, txn.IsFullySigned())
+				require.Len(t, c.Inputs[i], len(txn.In))
+				require.Equal(t, tc.p.To, txn.Out[0].Address)
+			}
+		})
+	}
+}
+
+func TestWalletCreateBatchTransaction(t *testing.T) {
+	addrs := make([]cipher.Address, 2)
+	entries := make([]wallet.Entry, 2)
+	for i := range entries {
+		p, s := cipher.GenerateKeyPair()
+		addrs[i] = cipher.AddressFromPubKey(p)
+		entries[i] = wallet.Entry{
+			Address: addrs[i],
+			Public:  p,
+			Secret:  s,
+		}
+	}
+
+	now := uint64(time.Now().Unix())
+	var uxa coin.UxArray
+	for i := 0; i < 3; i++ {
+		uxa = append(uxa, coin.UxOut{
+			Head: coin.UxHead{
+				Time:  now - 3700,
+				BkSeq: 100,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        addrs[i%2],
+				Coins:          1e6,
+				Hours:          100,
+			},
+		})
+	}
+
+	uxOuts := make([]cipher.SHA256, len(uxa))
+	for i, ux := range uxa {
+		uxOuts[i] = ux.Hash()
+	}
+
+	unknownUxa := append(coin.UxArray{}, uxa...)
+	unknownUxa[0].Body.Address = testutil.MakeAddress()
+
+	headBlock := &coin.SignedBlock{
+		Block: coin.Block{
+			Head: coin.BlockHeader{
+				Time: now,
+			},
+		},
+	}
+
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   1e6,
+				Hours:   10,
+			},
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   5e5,
+				Hours:   10,
+			},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		p         transaction.Params
+		signed    TxnSignedFlag
+		password  []byte
+		getArray  coin.UxArray
+		verifyErr error
+		err       error
+	}{
+		{
+			name:     "unsigned",
+			p:        p,
+			signed:   TxnUnsigned,
+			getArray: uxa,
+		},
+		{
+			name:     "signed",
+			p:        p,
+			signed:   TxnSigned,
+			password: []byte("foo"),
+			getArray: uxa,
+		},
+		{
+			name: "invalid params",
+			p: transaction.Params{
+				HoursSelection: p.HoursSelection,
+			},
+			signed:   TxnUnsigned,
+			getArray: uxa,
+			err:      transaction.ErrMissingReceivers,
+		},
+		{
+			name:     "unknown wallet uxouts",
+			p:        p,
+			signed:   TxnUnsigned,
+			getArray: unknownUxa,
+			err:      wallet.ErrUnknownUxOut,
+		},
+		{
+			name:      "blockchain verify error",
+			p:         p,
+			signed:    TxnSigned,
+			password:  []byte("foo"),
+			getArray:  uxa,
+			verifyErr: NewErrTxnViolatesSoftConstraint(errors.New("Violates soft constraints")),
+			err:       NewErrTxnViolatesSoftConstraint(errors.New("Violates soft constraints")),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := wallet.NewService(wallet.Config{
+				EnableWalletAPI: true,
+				CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+				WalletDir:       prepareWltDir(),
+			})
+			require.NoError(t, err)
+
+			_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+				Coin:       wallet.CoinTypeSkycoin,
+				Seed:       "foo",
+				Encrypt:    len(tc.password) != 0,
+				Password:   tc.password,
+				CryptoType: wallet.CryptoTypeScryptChacha20poly1305Insecure,
+			}, nil)
+			require.NoError(t, err)
+
+			err = ws.UpdateSecrets("foo.wlt", tc.password, func(w *wallet.Wallet) error {
+				for _, e := range entries {
+					err := w.AddEntry(e)
+					require.NoError(t, err)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			b := &MockBlockchainer{}
+			ut := &MockUnconfirmedTransactionPooler{}
+			up := &MockUnspentPooler{}
+
+			b.On("Head", matchDBTx).Return(headBlock, nil)
+			ut.On("ForEach", matchDBTx, mock.MatchedBy(func(f func(cipher.SHA256, UnconfirmedTransaction) error) bool {
+				return true
+			})).Return(nil)
+			up.On("GetArray", matchDBTx, mock.MatchedBy(matchUxOutsAnyOrder(uxOuts))).Return(tc.getArray, nil)
+			b.On("Unspent").Return(up)
+			b.On("VerifySingleTxnSoftHardConstraints", matchDBTx, mock.Anything, params.UserVerifyTxn, tc.signed).Return(nil, nil, tc.verifyErr)
+
+			db, shutdown := prepareDB(t)
+			defer shutdown()
+
+			v := &Visor{
+				DB:          db,
+				Blockchain:  b,
+				Unconfirmed: ut,
+				Wallets:     ws,
+			}
+
+			wp := CreateTransactionParams{
+				UxOuts: uxOuts,
+			}
+
+			var batch *Batch
+			switch tc.signed {
+			case TxnSigned:
+				batch, err = v.WalletCreateBatchTransactionSigned("foo.wlt", tc.password, tc.p, wp)
+			case TxnUnsigned:
+				batch, err = v.WalletCreateBatchTransaction("foo.wlt", tc.p, wp)
+			}
+			require.Equal(t, tc.err, err, "%v != %v", tc.err, err)
+			if tc.err != nil {
+				return
+			}
+
+			require.Len(t, batch.Transactions, 1)
+			require.Len(t, batch.Inputs, 1)
+			require.Equal(t, []int{0, 0}, batch.Receivers)
+
+			txn := batch.Transactions[0]
+			require.Equal(t, tc.signed == TxnSigned, txn.IsFullySigned())
+			require.Len(t, batch.Inputs[0], len(txn.In))
+			require.Equal(t, tc.p.To, txn.Out[:2])
+		})
+	}
+}
+
+func TestWalletBumpTransaction(t *testing.T) {
+	pub, sec := cipher.GenerateKeyPair()
+	addr := cipher.AddressFromPubKey(pub)
+	to := testutil.MakeAddress()
+
+	now := uint64(time.Now().Unix())
+	ux := coin.UxOut{
+		Head: coin.UxHead{
+			Time:  now,
+			BkSeq: 100,
+		},
+		Body: coin.UxBody{
+			SrcTransaction: testutil.RandSHA256(t),
+			Address:        addr,
+			Coins:          10e6,
+			Hours:          1000,
+		},
+	}
+
+	unknownUx := ux
+	unknownUx.Body.Address = testutil.MakeAddress()
+
+	pending := coin.Transaction{
+		In: []cipher.SHA256{ux.Hash()},
+		Out: []coin.TransactionOutput{
+			{
+				Address: to,
+				Coins:   5e6,
+				Hours:   200,
+			},
+			{
+				Address: addr,
+				Coins:   5e6,
+				Hours:   300,
+			},
+		},
+	}
+	pending.SignInputs([]cipher.SecKey{sec})
+	err := pending.UpdateHeader()
+	require.NoError(t, err)
+
+	increment := MinReplacementFeeIncrement(pending.Length)
+
+	cases := []struct {
+		name     string
+		fee      uint64
+		signed   TxnSignedFlag
+		password []byte
+		pending  *UnconfirmedTransaction
+		getArray coin.UxArray
+		hours    []uint64
+		err      error
+	}{
+		{
+			name:     "unsigned",
+			fee:      700,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			hours:    []uint64{200, 100},
+		},
+		{
+			name:     "signed",
+			fee:      800,
+			signed:   TxnSigned,
+			password: []byte("foo"),
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			hours:    []uint64{200, 0},
+		},
+		{
+			name:     "not pending",
+			fee:      700,
+			signed:   TxnUnsigned,
+			getArray: coin.UxArray{ux},
+			err:      NewUserError(fmt.Errorf("transaction %s is not in the unconfirmed pool", pending.Hash().Hex())),
+		},
+		{
+			name:     "unknown wallet uxouts",
+			fee:      700,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{unknownUx},
+			err:      wallet.ErrUnknownUxOut,
+		},
+		{
+			name:     "fee not increased",
+			fee:      500,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			err:      NewUserError(fmt.Errorf("fee must be at least %d coin hours more than the 500 coin hours burned by the transaction", increment)),
+		},
+		{
+			name:     "fee increase below the replacement increment",
+			fee:      500 + increment - 1,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			err:      NewUserError(fmt.Errorf("fee must be at least %d coin hours more than the 500 coin hours burned by the transaction", increment)),
+		},
+		{
+			name:     "fee increase of the replacement increment",
+			fee:      500 + increment,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			hours:    []uint64{200, 300 - increment},
+		},
+		{
+			name:     "insufficient change hours",
+			fee:      801,
+			signed:   TxnUnsigned,
+			pending:  &UnconfirmedTransaction{Transaction: pending},
+			getArray: coin.UxArray{ux},
+			err:      NewUserError(errors.New("the change outputs of the transaction do not have enough coin hours to burn the fee")),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws, err := wallet.NewService(wallet.Config{
+				EnableWalletAPI: true,
+				CryptoType:      wallet.CryptoTypeScryptChacha20poly1305Insecure,
+				WalletDir:       prepareWltDir(),
+			})
+			require.NoError(t, err)
+
+			_, err = ws.CreateWallet("foo.wlt", wallet.Options{
+				Coin:       wallet.CoinTypeSkycoin,
+				Seed:       "foo",
+				Encrypt:    len(tc.password) != 0,
+				Password:   tc.password,
+				CryptoType: wallet.CryptoTypeScryptChacha20poly1305Insecure,
+			}, nil)
+			require.NoError(t, err)
+
+			err = ws.UpdateSecrets("foo.wlt", tc.password, func(w *wallet.Wallet) error {
+				return w.AddEntry(wallet.Entry{
+					Address: addr,
+					Public:  pub,
+					Secret:  sec,
+				})
+			})
+			require.NoError(t, err)
+
+			b := &MockBlockchainer{}
+			ut := &MockUnconfirmedTransactionPooler{}
+			up := &MockUnspentPooler{}
+
+			ut.On("Get", matchDBTx, pending.Hash()).Return(tc.pending, nil)
+			b.On("Time", matchDBTx).Return(now, nil)
+			b.On("TransactionFee", matchDBTx, now).Return(coin.FeeCalculator(func(txn *coin.Transaction) (uint64, error) {
+				return 500, nil
+			}))
+			up.On("GetArray", matchDBTx, pending.In).Return(tc.getArray, nil)
+			b.On("Unspent").Return(up)
+			b.On("VerifySingleTxnSoftHardConstraints", matchDBTx, mock.Anything, params.UserVerifyTxn, tc.signed).Return(nil, nil, nil)
+
+			db, shutdown := prepareDB(t)
+			defer shutdown()
+
+			v := &Visor{
+				DB:          db,
+				Blockchain:  b,
+				Unconfirmed: ut,
+				Wallets:     ws,
+			}
+
+			var txn *coin.Transaction
+			var inputs []TransactionInput
+			switch tc.signed {
+			case TxnSigned:
+				txn, inputs, err = v.WalletBumpTransactionSigned("foo.wlt", tc.password, pending.Hash(), tc.fee)
+			case TxnUnsigned:
+				txn, inputs, err = v.WalletBumpTransaction("foo.wlt", pending.Hash(), tc.fee)
+			}
+			require.Equal(t, tc.err, err, "%v != %v", tc.err, err)
+			if tc.err != nil {
+				return
+			}
+
+			require.Equal(t, tc.signed == TxnSigned, txn.IsFullySigned())
+			require.Equal(t, pending.In, txn.In)
+			require.Len(t, txn.Out, len(pending.Out))
+			for i, o := range txn.Out {
+				require.Equal(t, pending.Out[i].Address, o.Address)
+				require.Equal(t, pending.Out[i].Coins, o.Coins)
+				require.Equal(t, tc.hours[i], o.Hours)
+			}
+
+			require.Len(t, inputs, 1)
+			require.Equal(t, ux.Hash(), inputs[0].UxOut.Hash())
+		})
+	}
+}
+
 func TestCreateTransactionParamsValidate(t *testing.T) {
 	var nullAddress cipher.Address
 	addr := testutil.MakeAddress()
@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+var (
+	// ErrMissingAddressBookName is returned if an address book entry has no name
+	ErrMissingAddressBookName = NewError(errors.New("address book name is required"))
+	// ErrInvalidAddressBookName is returned if an address book name is an address, which would make it ambiguous
+	ErrInvalidAddressBookName = NewError(errors.New("address book name must not be an address"))
+	// ErrAddressBookEntryNotExist is returned if an address book entry does not exist
+	ErrAddressBookEntryNotExist = NewError(errors.New("address book entry does not exist"))
+)
+
+// AddressBookEntry is a named external address to send coins to
+type AddressBookEntry struct {
+	Name    string
+	Address cipher.Addresser
+	Note    string
+}
+
+// ReadableAddressBookEntry address book entry with json tags
+type ReadableAddressBookEntry struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Note    string `json:"note,omitempty"`
+}
+
+// NewReadableAddressBook creates []ReadableAddressBookEntry from []AddressBookEntry
+func NewReadableAddressBook(entries []AddressBookEntry) []ReadableAddressBookEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rentries := make([]ReadableAddressBookEntry, len(entries))
+	for i, e := range entries {
+		rentries[i] = ReadableAddressBookEntry{
+			Name:    e.Name,
+			Address: e.Address.String(),
+			Note:    e.Note,
+		}
+	}
+	return rentries
+}
+
+// toAddressBook converts []ReadableAddressBookEntry to []AddressBookEntry
+func toAddressBook(coinType CoinType, rentries []ReadableAddressBookEntry) ([]AddressBookEntry, error) {
+	if len(rentries) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]AddressBookEntry, len(rentries))
+	for i, re := range rentries {
+		a, err := decodeAddress(coinType, re.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address book entry %q: %v", re.Name, err)
+		}
+
+		entries[i] = AddressBookEntry{
+			Name:    re.Name,
+			Address: a,
+			Note:    re.Note,
+		}
+	}
+	return entries, nil
+}
+
+// decodeAddress decodes a base58 address of the coin type
+func decodeAddress(coinType CoinType, addr string) (cipher.Addresser, error) {
+	switch coinType {
+	case CoinTypeSkycoin:
+		return cipher.DecodeBase58Address(addr)
+	case CoinTypeBitcoin:
+		return cipher.DecodeBase58BitcoinAddress(addr)
+	default:
+		return nil, ErrInvalidCoinType
+	}
+}
+
+// DecodeAddress decodes a base58 address of the wallet's coin type
+func (w *Wallet) DecodeAddress(addr string) (cipher.Addresser, error) {
+	a, err := decodeAddress(w.coin(), addr)
+	if err != nil {
+		return nil, NewError(fmt.Errorf("invalid %s address %q: %v", w.coin(), addr, err))
+	}
+	return a, nil
+}
+
+// SetAddressLabel sets the label of an address of the wallet. An empty label removes the label.
+func (w *Wallet) SetAddressLabel(addr cipher.Addresser, label string) error {
+	for i, e := range w.Entries {
+		if e.Address.String() == addr.String() {
+			w.Entries[i].Label = strings.TrimSpace(label)
+			return nil
+		}
+	}
+
+	return ErrUnknownAddress
+}
+
+// AddressLabels returns the labels of the addresses of the wallet, indexed by address
+func (w *Wallet) AddressLabels() map[string]string {
+	labels := make(map[string]string)
+	for _, e := range w.Entries {
+		if e.Label != "" {
+			labels[e.Address.String()] = e.Label
+		}
+	}
+	return labels
+}
+
+// copyAddressLabels copies the labels of the addresses of src that are in the wallet
+func (w *Wallet) copyAddressLabels(src *Wallet) {
+	labels := src.AddressLabels()
+	for i, e := range w.Entries {
+		if label, ok := labels[e.Address.String()]; ok {
+			w.Entries[i].Label = label
+		}
+	}
+}
+
+// GetAddressBook returns the address book of the wallet, ordered by name
+func (w *Wallet) GetAddressBook() []AddressBookEntry {
+	entries := make([]AddressBookEntry, len(w.AddressBook))
+	copy(entries, w.AddressBook)
+	return entries
+}
+
+// LookupAddressBook returns the address book entry with the name
+func (w *Wallet) LookupAddressBook(name string) (AddressBookEntry, bool) {
+	for _, e := range w.AddressBook {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return AddressBookEntry{}, false
+}
+
+// SetAddressBookEntry adds an entry to the address book, or replaces the entry with the same name.
+// The address must match the wallet's coin type. The name must not be an address,
+// so that names and addresses can't be confused when sending coins.
+func (w *Wallet) SetAddressBookEntry(e AddressBookEntry) error {
+	e.Name = strings.TrimSpace(e.Name)
+	e.Note = strings.TrimSpace(e.Note)
+
+	if e.Name == "" {
+		return ErrMissingAddressBookName
+	}
+
+	if _, err := decodeAddress(w.coin(), e.Name); err == nil {
+		return ErrInvalidAddressBookName
+	}
+
+	if err := w.validateCoinAddress(e.Address); err != nil {
+		return err
+	}
+
+	for i, be := range w.AddressBook {
+		if be.Name == e.Name {
+			w.AddressBook[i] = e
+			return nil
+		}
+	}
+
+	w.AddressBook = append(w.AddressBook, e)
+	sort.Slice(w.AddressBook, func(i, j int) bool {
+		return w.AddressBook[i].Name < w.AddressBook[j].Name
+	})
+
+	return nil
+}
+
+// RemoveAddressBookEntry removes the address book entry with the name
+func (w *Wallet) RemoveAddressBookEntry(name string) error {
+	for i, e := range w.AddressBook {
+		if e.Name == name {
+			w.AddressBook = append(w.AddressBook[:i], w.AddressBook[i+1:]...)
+			if len(w.AddressBook) == 0 {
+				w.AddressBook = nil
+			}
+			return nil
+		}
+	}
+
+	return ErrAddressBookEntryNotExist
+}
@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestWalletAddressLabels(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Seed:      "seed",
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	require.Empty(t, w.AddressLabels())
+
+	addrs := w.GetAddresses()
+
+	err = w.SetAddressLabel(testutil.MakeAddress(), "savings")
+	require.Equal(t, ErrUnknownAddress, err)
+
+	require.NoError(t, w.SetAddressLabel(addrs[1], " savings "))
+	require.Equal(t, map[string]string{addrs[1].String(): "savings"}, w.AddressLabels())
+
+	// The labels are saved in the wallet file and kept when the wallet is encrypted
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+	require.NoError(t, w.Save(dir))
+	w2, err := Load(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, w.AddressLabels(), w2.AddressLabels())
+	require.NoError(t, w2.Lock([]byte("pwd"), CryptoTypeScryptChacha20poly1305Insecure))
+	require.Equal(t, w.AddressLabels(), w2.AddressLabels())
+
+	// The labels are kept when scanning addresses
+	_, err = w.ScanAddresses(2, mockBalanceGetter{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{addrs[1].String(): "savings"}, w.AddressLabels())
+
+	// An empty label removes the label
+	require.NoError(t, w.SetAddressLabel(addrs[1], ""))
+	require.Empty(t, w.AddressLabels())
+}
+
+func TestWalletAddressBook(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Seed:      "seed",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, w.GetAddressBook())
+
+	alice := testutil.MakeAddress()
+	bob := testutil.MakeAddress()
+
+	err = w.SetAddressBookEntry(AddressBookEntry{Name: " ", Address: alice})
+	require.Equal(t, ErrMissingAddressBookName, err)
+
+	err = w.SetAddressBookEntry(AddressBookEntry{Name: bob.String(), Address: alice})
+	require.Equal(t, ErrInvalidAddressBookName, err)
+
+	err = w.SetAddressBookEntry(AddressBookEntry{Name: "alice", Address: cipher.Address{}})
+	require.Error(t, err)
+
+	err = w.SetAddressBookEntry(AddressBookEntry{Name: "alice", Address: cipher.BitcoinAddress{Version: 0, Key: alice.Key}})
+	require.Error(t, err)
+
+	// Entries are ordered by name, and replaced by name
+	require.NoError(t, w.SetAddressBookEntry(AddressBookEntry{Name: "bob", Address: bob}))
+	require.NoError(t, w.SetAddressBookEntry(AddressBookEntry{Name: "alice", Address: bob, Note: "rent"}))
+	require.NoError(t, w.SetAddressBookEntry(AddressBookEntry{Name: "alice", Address: alice, Note: "rent"}))
+	require.Equal(t, []AddressBookEntry{
+		{Name: "alice", Address: alice, Note: "rent"},
+		{Name: "bob", Address: bob},
+	}, w.GetAddressBook())
+
+	e, ok := w.LookupAddressBook("bob")
+	require.True(t, ok)
+	require.Equal(t, bob, e.Address)
+	_, ok = w.LookupAddressBook("carol")
+	require.False(t, ok)
+
+	// The address book is copied with the wallet
+	require.Equal(t, w.AddressBook, w.clone().AddressBook)
+
+	// The address book is saved in the wallet file
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+	require.NoError(t, w.Save(dir))
+	w2, err := Load(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, w.AddressBook, w2.AddressBook)
+
+	err = w.RemoveAddressBookEntry("carol")
+	require.Equal(t, ErrAddressBookEntryNotExist, err)
+	require.NoError(t, w.RemoveAddressBookEntry("alice"))
+	require.NoError(t, w.RemoveAddressBookEntry("bob"))
+	require.Empty(t, w.GetAddressBook())
+
+	// A wallet without an address book is saved without it
+	require.NoError(t, w.Save(dir))
+	rw, err := LoadReadableWallet(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Nil(t, rw.AddressBook)
+}
+
+func TestServiceAddressBook(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Seed:      "seed",
+		GenerateN: 2,
+		Encrypt:   true,
+		Password:  []byte("pwd"),
+	}, nil)
+	require.NoError(t, err)
+	addrs := w.GetAddresses()
+	alice := testutil.MakeAddress()
+
+	_, err = s.UpdateWalletAddressLabel("t.wlt", "foo", "savings")
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	_, err = s.UpdateWalletAddressLabel("missing.wlt", addrs[1].String(), "savings")
+	require.Equal(t, ErrWalletNotExist, err)
+
+	// Labels and the address book can be updated without the password of an encrypted wallet
+	w, err = s.UpdateWalletAddressLabel("t.wlt", addrs[1].String(), "savings")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{addrs[1].String(): "savings"}, w.AddressLabels())
+
+	w, err = s.AddWalletAddressBookEntry("t.wlt", "alice", alice.String(), "rent")
+	require.NoError(t, err)
+	require.Equal(t, []AddressBookEntry{{Name: "alice", Address: alice, Note: "rent"}}, w.GetAddressBook())
+
+	// The labels and the address book are kept when the wallet is recovered
+	w, err = s.RecoverWallet("t.wlt", "seed", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{addrs[1].String(): "savings"}, w.AddressLabels())
+	require.Equal(t, []AddressBookEntry{{Name: "alice", Address: alice, Note: "rent"}}, w.GetAddressBook())
+
+	_, err = s.RemoveWalletAddressBookEntry("t.wlt", "bob")
+	require.Equal(t, ErrAddressBookEntryNotExist, err)
+
+	w, err = s.RemoveWalletAddressBookEntry("t.wlt", "alice")
+	require.NoError(t, err)
+	require.Empty(t, w.GetAddressBook())
+}
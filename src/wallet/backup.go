@@ -0,0 +1,234 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupVersion is the version of the wallet backup format
+const BackupVersion = "0.1"
+
+var (
+	// ErrInvalidBackup is returned if a wallet backup is malformed
+	ErrInvalidBackup = NewError(errors.New("invalid wallet backup"))
+	// ErrMissingBackupWallets is returned if there are no wallets to back up
+	ErrMissingBackupWallets = NewError(errors.New("no wallets to back up"))
+)
+
+// backupCryptoType is the crypto type that wallet backups are encrypted with
+var backupCryptoType = CryptoTypeScryptChacha20poly1305
+
+// backupFile is the format of a wallet backup.
+// The wallets are encrypted together with an AEAD, so they can't be read or modified without the password.
+type backupFile struct {
+	Version    string     `json:"version"`
+	CryptoType CryptoType `json:"crypto_type"`
+	Data       string     `json:"data"`
+}
+
+// backupContent is the encrypted content of a wallet backup
+type backupContent struct {
+	Timestamp int64             `json:"timestamp"`
+	Wallets   []*ReadableWallet `json:"wallets"`
+}
+
+// EncryptBackup packages the wallets into a single backup encrypted with password.
+// The wallets are packaged as they are saved in their wallet files, with their labels,
+// address book and generated addresses. Encrypted wallets stay encrypted with their own password.
+func EncryptBackup(wlts []*Wallet, password []byte) ([]byte, error) {
+	if len(wlts) == 0 {
+		return nil, ErrMissingBackupWallets
+	}
+
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	c := backupContent{
+		Timestamp: time.Now().Unix(),
+		Wallets:   make([]*ReadableWallet, len(wlts)),
+	}
+	for i, w := range wlts {
+		c.Wallets[i] = NewReadableWallet(w)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	defer eraseBytes(b)
+
+	crypto, err := getCrypto(backupCryptoType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := crypto.Encrypt(b, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(backupFile{
+		Version:    BackupVersion,
+		CryptoType: backupCryptoType,
+		Data:       string(data),
+	}, "", "    ")
+}
+
+// DecryptBackup decrypts a backup created by EncryptBackup and returns its wallets.
+// The backup is authenticated, ErrInvalidPassword is returned if the password is wrong
+// or the backup was modified. Each wallet is validated, and the wallets of the backup
+// must not have duplicate filenames or be duplicates of each other.
+func DecryptBackup(backup, password []byte) ([]*Wallet, error) {
+	if len(password) == 0 {
+		return nil, ErrMissingPassword
+	}
+
+	var f backupFile
+	if err := json.Unmarshal(backup, &f); err != nil {
+		return nil, ErrInvalidBackup
+	}
+
+	if f.Version != BackupVersion {
+		return nil, NewError(fmt.Errorf("unsupported wallet backup version %q", f.Version))
+	}
+
+	switch f.CryptoType {
+	case CryptoTypeScryptChacha20poly1305, CryptoTypeScryptChacha20poly1305Insecure:
+	default:
+		return nil, NewError(fmt.Errorf("unsupported wallet backup crypto type %q", f.CryptoType))
+	}
+
+	crypto, err := getCrypto(f.CryptoType)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := crypto.Decrypt([]byte(f.Data), password)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	defer eraseBytes(b)
+
+	var c backupContent
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidBackup
+	}
+
+	if len(c.Wallets) == 0 {
+		return nil, ErrInvalidBackup
+	}
+
+	wlts := make(Wallets, len(c.Wallets))
+	ws := make([]*Wallet, 0, len(c.Wallets))
+	for _, rw := range c.Wallets {
+		if rw == nil || rw.Meta == nil {
+			return nil, ErrInvalidBackup
+		}
+
+		w, err := readableToSkycoinWallet(rw, rw.filename())
+		if err != nil {
+			return nil, NewError(err)
+		}
+
+		// The filename is used to save the wallet, it must not be a path
+		fn := w.Filename()
+		if filepath.Base(fn) != fn || !strings.HasSuffix(fn, WalletExt) {
+			return nil, NewError(fmt.Errorf("invalid wallet filename %q in wallet backup", fn))
+		}
+
+		if len(w.Entries) == 0 {
+			return nil, NewError(fmt.Errorf("empty wallet %q in wallet backup", fn))
+		}
+
+		if err := wlts.add(w); err != nil {
+			return nil, NewError(fmt.Errorf("duplicate wallet filename %q in wallet backup", fn))
+		}
+
+		ws = append(ws, w)
+	}
+
+	if wltID, addr, hasDup := wlts.containsDuplicate(); hasDup {
+		return nil, NewError(fmt.Errorf("duplicate wallet found with initial address %s in file %q", addr, wltID))
+	}
+
+	return ws, nil
+}
+
+// ImportBackupToDir decrypts a backup created by EncryptBackup and saves its wallets to the wallet directory dir.
+// The wallets whose filenames are used by the wallets of dir are renamed.
+// Nothing is saved if a wallet of the backup is a duplicate of a wallet of dir.
+func ImportBackupToDir(dir string, backup, password []byte) ([]*Wallet, error) {
+	ws, err := DecryptBackup(backup, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, os.FileMode(0700)); err != nil {
+		return nil, err
+	}
+
+	wlts, err := LoadWallets(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wlts.prepareImport(ws); err != nil {
+		return nil, err
+	}
+
+	if err := saveImportedWallets(dir, ws); err != nil {
+		return nil, err
+	}
+
+	return ws, nil
+}
+
+// prepareImport renames the imported wallets whose filenames are used by wlts,
+// and checks that none of the imported wallets is a duplicate of the wallets of wlts
+func (wlts Wallets) prepareImport(ws []*Wallet) error {
+	all := make(Wallets, len(wlts)+len(ws))
+	for id, w := range wlts {
+		all[id] = w
+	}
+
+	for _, w := range ws {
+		for all.get(w.Filename()) != nil {
+			w.setFilename(NewWalletFilename())
+		}
+		all[w.Filename()] = w
+	}
+
+	if wltID, addr, hasDup := all.containsDuplicate(); hasDup {
+		return NewError(fmt.Errorf("duplicate wallet found with initial address %s in file %q", addr, wltID))
+	}
+
+	return nil
+}
+
+// saveImportedWallets saves the imported wallets to dir.
+// If a wallet fails to save, the wallets that were saved before it are removed.
+func saveImportedWallets(dir string, ws []*Wallet) error {
+	for i, w := range ws {
+		if err := w.Save(dir); err != nil {
+			for _, w := range ws[:i] {
+				os.Remove(filepath.Join(dir, w.Filename())) // nolint: errcheck
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eraseBytes overwrites b with zeros
+func eraseBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,321 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+// useInsecureBackupCrypto encrypts the wallet backups with a weak work factor to speed up the tests.
+// Returns a function that restores the crypto type.
+func useInsecureBackupCrypto() func() {
+	ct := backupCryptoType
+	backupCryptoType = CryptoTypeScryptChacha20poly1305Insecure
+	return func() {
+		backupCryptoType = ct
+	}
+}
+
+func makeBackupWallets(t *testing.T) []*Wallet {
+	w1, err := NewWallet("t1.wlt", Options{
+		Seed:      "seed1",
+		Label:     "savings",
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w1.SetAddressLabel(w1.Entries[2].Address, "rent"))
+	require.NoError(t, w1.SetAddressBookEntry(AddressBookEntry{
+		Name:    "alice",
+		Address: testutil.MakeAddress(),
+	}))
+
+	w2, err := NewWallet("t2.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 2,
+		Encrypt:   true,
+		Password:  []byte("pwd"),
+
+		CryptoType: CryptoTypeScryptChacha20poly1305Insecure,
+	})
+	require.NoError(t, err)
+
+	return []*Wallet{w1, w2}
+}
+
+func TestEncryptDecryptBackup(t *testing.T) {
+	defer useInsecureBackupCrypto()()
+	wlts := makeBackupWallets(t)
+
+	_, err := EncryptBackup(nil, []byte("backup pwd"))
+	require.Equal(t, ErrMissingBackupWallets, err)
+
+	_, err = EncryptBackup(wlts, nil)
+	require.Equal(t, ErrMissingPassword, err)
+
+	backup, err := EncryptBackup(wlts, []byte("backup pwd"))
+	require.NoError(t, err)
+
+	// The seeds are not readable without the password
+	require.False(t, strings.Contains(string(backup), "seed1"))
+
+	var f backupFile
+	require.NoError(t, json.Unmarshal(backup, &f))
+	require.Equal(t, BackupVersion, f.Version)
+	require.Equal(t, CryptoTypeScryptChacha20poly1305Insecure, f.CryptoType)
+
+	// The wallets are restored with their labels, address book and generated addresses
+	ws, err := DecryptBackup(backup, []byte("backup pwd"))
+	require.NoError(t, err)
+	require.Len(t, ws, 2)
+	for i, w := range ws {
+		require.Equal(t, NewReadableWallet(wlts[i]), NewReadableWallet(w))
+	}
+	require.Equal(t, "savings", ws[0].Label())
+	require.Equal(t, wlts[0].AddressLabels(), ws[0].AddressLabels())
+	require.Equal(t, wlts[0].AddressBook, ws[0].AddressBook)
+	require.Len(t, ws[0].Entries, 3)
+	require.True(t, ws[1].IsEncrypted())
+
+	_, err = DecryptBackup(backup, nil)
+	require.Equal(t, ErrMissingPassword, err)
+
+	_, err = DecryptBackup(backup, []byte("wrong pwd"))
+	require.Equal(t, ErrInvalidPassword, err)
+
+	_, err = DecryptBackup([]byte("foo"), []byte("backup pwd"))
+	require.Equal(t, ErrInvalidBackup, err)
+
+	// A modified backup fails to authenticate
+	tampered := f
+	data := []byte(tampered.Data)
+	i := len(data) - 8
+	if data[i] == 'A' {
+		data[i] = 'B'
+	} else {
+		data[i] = 'A'
+	}
+	tampered.Data = string(data)
+	b, err := json.Marshal(tampered)
+	require.NoError(t, err)
+	_, err = DecryptBackup(b, []byte("backup pwd"))
+	require.Equal(t, ErrInvalidPassword, err)
+
+	unsupported := f
+	unsupported.Version = "0.2"
+	b, err = json.Marshal(unsupported)
+	require.NoError(t, err)
+	_, err = DecryptBackup(b, []byte("backup pwd"))
+	require.Equal(t, NewError(errors.New(`unsupported wallet backup version "0.2"`)), err)
+
+	unsupported = f
+	unsupported.CryptoType = CryptoTypeSha256Xor
+	b, err = json.Marshal(unsupported)
+	require.NoError(t, err)
+	_, err = DecryptBackup(b, []byte("backup pwd"))
+	require.Equal(t, NewError(errors.New(`unsupported wallet backup crypto type "sha256-xor"`)), err)
+}
+
+func TestDecryptBackupInvalidWallets(t *testing.T) {
+	defer useInsecureBackupCrypto()()
+
+	w1, err := NewWallet("t1.wlt", Options{
+		Seed:      "seed1",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+
+	dup := w1.clone()
+	dup.setFilename("t2.wlt")
+
+	traversal := w1.clone()
+	traversal.setFilename("../t1.wlt")
+
+	tt := []struct {
+		name string
+		wlts []*Wallet
+		err  error
+	}{
+		{
+			name: "duplicate wallet",
+			wlts: []*Wallet{w1, dup},
+			err:  NewError(fmt.Errorf("duplicate wallet found with initial address %s in file", w1.Entries[0].Address)),
+		},
+		{
+			name: "duplicate filename",
+			wlts: []*Wallet{w1, w1},
+			err:  NewError(errors.New(`duplicate wallet filename "t1.wlt" in wallet backup`)),
+		},
+		{
+			name: "filename is a path",
+			wlts: []*Wallet{traversal},
+			err:  NewError(errors.New(`invalid wallet filename "../t1.wlt" in wallet backup`)),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			backup, err := EncryptBackup(tc.wlts, []byte("pwd"))
+			require.NoError(t, err)
+
+			_, err = DecryptBackup(backup, []byte("pwd"))
+			require.Error(t, err)
+			require.IsType(t, Error{}, err)
+			require.True(t, strings.HasPrefix(err.Error(), tc.err.Error()), err.Error())
+		})
+	}
+}
+
+func TestServiceExportImportWallets(t *testing.T) {
+	defer useInsecureBackupCrypto()()
+
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeScryptChacha20poly1305Insecure,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w1, err := s.CreateWallet("t1.wlt", Options{
+		Seed:      "seed1",
+		Label:     "savings",
+		GenerateN: 3,
+	}, nil)
+	require.NoError(t, err)
+	w1, err = s.UpdateWalletAddressLabel("t1.wlt", w1.Entries[1].Address.String(), "rent")
+	require.NoError(t, err)
+
+	w2, err := s.CreateWallet("t2.wlt", Options{
+		Seed:      "seed2",
+		GenerateN: 2,
+		Encrypt:   true,
+		Password:  []byte("pwd"),
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = s.ExportWallets([]string{"t3.wlt"}, []byte("backup pwd"))
+	require.Equal(t, ErrWalletNotExist, err)
+
+	// Unencrypted wallets can't be exported if the seed API is disabled
+	_, err = s.ExportWallets(nil, []byte("backup pwd"))
+	require.Equal(t, ErrSeedAPIDisabled, err)
+
+	backup, err := s.ExportWallets([]string{"t2.wlt"}, []byte("backup pwd"))
+	require.NoError(t, err)
+	ws, err := DecryptBackup(backup, []byte("backup pwd"))
+	require.NoError(t, err)
+	require.Len(t, ws, 1)
+
+	s.enableSeedAPI = true
+	backup, err = s.ExportWallets(nil, []byte("backup pwd"))
+	require.NoError(t, err)
+
+	// The wallets can't be imported where they exist
+	_, err = s.ImportWallets(backup, []byte("backup pwd"))
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+	require.Contains(t, err.Error(), "duplicate wallet found with initial address")
+
+	// The wallets are imported into another wallet directory, the wallets whose filenames are used are renamed
+	dir := prepareWltDir()
+	s2, err := NewService(Config{
+		WalletDir:       dir,
+		CryptoType:      CryptoTypeScryptChacha20poly1305Insecure,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	w3, err := s2.CreateWallet("t1.wlt", Options{
+		Seed:      "seed3",
+		GenerateN: 1,
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = s2.ImportWallets(backup, []byte("wrong pwd"))
+	require.Equal(t, ErrInvalidPassword, err)
+
+	imported, err := s2.ImportWallets(backup, []byte("backup pwd"))
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	require.NotEqual(t, "t1.wlt", imported[0].Filename())
+	require.Equal(t, "t2.wlt", imported[1].Filename())
+
+	for i, w := range []*Wallet{w1, w2} {
+		require.Equal(t, w.Entries, imported[i].Entries)
+		require.Equal(t, w.Label(), imported[i].Label())
+		require.Equal(t, w.AddressLabels(), imported[i].AddressLabels())
+
+		// The wallets are loaded and saved
+		w, err := s2.GetWallet(imported[i].Filename())
+		require.NoError(t, err)
+		require.Equal(t, imported[i], w)
+		testutil.RequireFileExists(t, filepath.Join(dir, imported[i].Filename()))
+	}
+
+	// The existing wallet is kept
+	w, err := s2.GetWallet("t1.wlt")
+	require.NoError(t, err)
+	require.Equal(t, w3, w)
+
+	// The imported encrypted wallet can be unlocked with its password
+	_, err = s2.GetWalletSeed("t2.wlt", []byte("pwd"))
+	require.Equal(t, ErrSeedAPIDisabled, err)
+	require.NoError(t, s2.ViewSecrets("t2.wlt", []byte("pwd"), func(w *Wallet) error {
+		require.Equal(t, "seed2", w.seed())
+		return nil
+	}))
+
+	// The imported wallets are loaded with the wallet directory
+	s3, err := NewService(Config{
+		WalletDir:       dir,
+		CryptoType:      CryptoTypeScryptChacha20poly1305Insecure,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	wlts, err := s3.GetWallets()
+	require.NoError(t, err)
+	require.Len(t, wlts, 3)
+
+	s.enableWalletAPI = false
+	_, err = s.ExportWallets(nil, []byte("backup pwd"))
+	require.Equal(t, ErrWalletAPIDisabled, err)
+	_, err = s.ImportWallets(backup, []byte("backup pwd"))
+	require.Equal(t, ErrWalletAPIDisabled, err)
+}
+
+func TestImportBackupToDir(t *testing.T) {
+	defer useInsecureBackupCrypto()()
+	wlts := makeBackupWallets(t)
+
+	backup, err := EncryptBackup(wlts, []byte("backup pwd"))
+	require.NoError(t, err)
+
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+
+	ws, err := ImportBackupToDir(dir, backup, []byte("backup pwd"))
+	require.NoError(t, err)
+	require.Len(t, ws, 2)
+
+	for i, w := range wlts {
+		w2, err := Load(filepath.Join(dir, w.Filename()))
+		require.NoError(t, err)
+		require.Equal(t, NewReadableWallet(w), NewReadableWallet(w2))
+		require.Equal(t, ws[i].Filename(), w2.Filename())
+	}
+
+	// Nothing is saved if a wallet is a duplicate
+	_, err = ImportBackupToDir(dir, backup, []byte("backup pwd"))
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+	wltFiles, err := filterDir(dir, WalletExt)
+	require.NoError(t, err)
+	require.Len(t, wltFiles, 2)
+}
@@ -0,0 +1,146 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+)
+
+// bip44AccountIndex is the bip44 account that bip44 wallets derive their addresses from
+const bip44AccountIndex = 0
+
+// errBip44ChainExhausted is returned when all the non-hardened child numbers of a bip44 chain are used
+var errBip44ChainExhausted = errors.New("no more addresses can be derived on the bip44 chain")
+
+// bip44CoinType returns the bip44 coin_type for a wallet coin type
+func bip44CoinType(coin CoinType) bip44.CoinType {
+	switch coin {
+	case CoinTypeSkycoin:
+		return bip44.CoinTypeSkycoin
+	case CoinTypeBitcoin:
+		return bip44.CoinTypeBitcoin
+	default:
+		logger.Panicf("Invalid wallet coin type %q", coin)
+		return 0
+	}
+}
+
+// bip44Coin returns the bip44 coin_type recorded in the wallet meta
+func (w *Wallet) bip44Coin() (bip44.CoinType, error) {
+	c, err := strconv.ParseUint(w.Meta[metaBip44Coin], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bip44Coin: %v", err)
+	}
+	return bip44.CoinType(c), nil
+}
+
+// bip44Account derives the bip44 account node of the wallet from the wallet seed and its bip39 passphrase
+func (w *Wallet) bip44Account() (*bip44.Account, error) {
+	if w.seed() == "" {
+		return nil, errors.New("wallet seed is empty")
+	}
+
+	coinType, err := w.bip44Coin()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := bip44.NewCoin(bip39.NewSeed(w.seed(), w.seedPassphrase()), coinType)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Account(bip44AccountIndex)
+}
+
+// bip44EntriesCount returns the number of entries on the external and change chains
+func (w *Wallet) bip44EntriesCount() (uint64, uint64) {
+	var nExternal, nChange uint64
+	for _, e := range w.Entries {
+		switch e.Change {
+		case bip44.ExternalChainIndex:
+			nExternal++
+		case bip44.ChangeChainIndex:
+			nChange++
+		}
+	}
+	return nExternal, nChange
+}
+
+// bip44NextChildNumber returns the child number following the last entry on the given bip44 chain.
+// Child numbers whose keys are invalid are skipped, as required by BIP32,
+// so the child numbers of a chain can be greater than its number of entries
+func (w *Wallet) bip44NextChildNumber(chain uint32) uint32 {
+	var next uint32
+	for _, e := range w.Entries {
+		if e.Change == chain && e.ChildNumber >= next {
+			next = e.ChildNumber + 1
+		}
+	}
+	return next
+}
+
+// generateBip44Addresses generates num addresses on the given bip44 chain,
+// continuing after the last address generated on that chain
+func (w *Wallet) generateBip44Addresses(chain uint32, num uint64) ([]cipher.Addresser, error) {
+	if num == 0 {
+		return nil, nil
+	}
+
+	if w.IsEncrypted() {
+		return nil, ErrWalletEncrypted
+	}
+
+	acct, err := w.bip44Account()
+	if err != nil {
+		return nil, err
+	}
+
+	chainKey, err := acct.Chain(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]cipher.Addresser, 0, num)
+	makeAddress := w.addressConstructor()
+	for i := w.bip44NextChildNumber(chain); uint64(len(addrs)) < num; i++ {
+		if i >= bip32.FirstHardenedChild {
+			return nil, errBip44ChainExhausted
+		}
+
+		k, err := chainKey.NewPrivateChildKey(i)
+		if err == bip32.ErrInvalidPrivateKey {
+			// The key of this child number is invalid, proceed with the next one
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		s, err := k.SecKey()
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := cipher.PubKeyFromSecKey(s)
+		if err != nil {
+			return nil, err
+		}
+
+		a := makeAddress(p)
+		addrs = append(addrs, a)
+		w.Entries = append(w.Entries, Entry{
+			Address:     a,
+			Public:      p,
+			Secret:      s,
+			ChildNumber: k.ChildNumber,
+			Change:      chain,
+		})
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,319 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+)
+
+const testBip44Seed = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestBip44WalletGenerateAddresses(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 3)
+	require.Equal(t, "", w.lastSeed())
+	require.NoError(t, w.Validate())
+
+	// Addresses are derived along m/44'/8000'/0'/0/i
+	mk, err := bip32.NewMasterKey(bip39.NewSeed(testBip44Seed, ""))
+	require.NoError(t, err)
+	for i, e := range w.Entries {
+		path, err := bip32.ParsePath("m/44'/8000'/0'/0")
+		require.NoError(t, err)
+		k, err := mk.DeriveSubpath(append(path, uint32(i)))
+		require.NoError(t, err)
+		sk, err := k.SecKey()
+		require.NoError(t, err)
+
+		require.Equal(t, uint32(i), e.ChildNumber)
+		require.Equal(t, bip44.ExternalChainIndex, e.Change)
+		require.Equal(t, sk, e.Secret)
+		require.Equal(t, cipher.MustPubKeyFromSecKey(sk), e.Public)
+		require.Equal(t, cipher.AddressFromPubKey(e.Public), e.Address)
+	}
+
+	// Change addresses are counted separately from external addresses
+	addrs, err := w.generateBip44Addresses(bip44.ChangeChainIndex, 2)
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	require.Equal(t, uint32(1), w.Entries[4].ChildNumber)
+	require.Equal(t, bip44.ChangeChainIndex, w.Entries[4].Change)
+
+	addrs, err = w.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+	require.Equal(t, uint32(3), w.Entries[5].ChildNumber)
+	require.Equal(t, bip44.ExternalChainIndex, w.Entries[5].Change)
+
+	nExternal, nChange := w.bip44EntriesCount()
+	require.Equal(t, uint64(4), nExternal)
+	require.Equal(t, uint64(2), nChange)
+
+	// Bitcoin wallets use the bitcoin coin_type
+	bw, err := NewWallet("t.wlt", Options{
+		Type: WalletTypeBip44,
+		Coin: CoinTypeBitcoin,
+		Seed: testBip44Seed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", bw.Entries[0].BitcoinAddress().String())
+}
+
+func TestBip44WalletGenerateAddressesSkippedChild(t *testing.T) {
+	bw, err := NewWallet("b.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 4,
+	})
+	require.NoError(t, err)
+
+	xw, err := NewWallet("x.wlt", Options{
+		Type:      WalletTypeXPub,
+		XPub:      testAccountXPub(t, bip44.CoinTypeSkycoin),
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+
+	// Remove child number 1 from the wallets, as if its key was invalid and skipped.
+	// The next address is derived after the child number of the last entry, not after the number of entries
+	for _, w := range []*Wallet{w, xw} {
+		w.Entries = append(w.Entries[:1], w.Entries[2:]...)
+		require.Equal(t, uint32(3), w.bip44NextChildNumber(bip44.ExternalChainIndex))
+		require.Equal(t, uint32(0), w.bip44NextChildNumber(bip44.ChangeChainIndex))
+
+		addrs, err := w.GenerateAddresses(1)
+		require.NoError(t, err)
+		require.Len(t, w.Entries, 3)
+		require.Equal(t, bw.Entries[3].Address, addrs[0])
+		require.Equal(t, bw.Entries[3].Public, w.Entries[2].Public)
+		require.Equal(t, uint32(3), w.Entries[2].ChildNumber)
+	}
+}
+
+func TestBip44WalletLockUnlock(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	_, err = w.generateBip44Addresses(bip44.ChangeChainIndex, 1)
+	require.NoError(t, err)
+
+	cw := w.clone()
+	require.NoError(t, cw.Lock([]byte("pwd"), CryptoTypeSha256Xor))
+	require.Equal(t, "", cw.seed())
+	for _, e := range cw.Entries {
+		require.True(t, e.Secret.Null())
+	}
+
+	_, err = cw.GenerateAddresses(1)
+	require.Equal(t, ErrWalletEncrypted, err)
+
+	uw, err := cw.Unlock([]byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, uw.Entries)
+	require.Equal(t, w.seed(), uw.seed())
+}
+
+func TestBip44WalletReadable(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	_, err = w.generateBip44Addresses(bip44.ChangeChainIndex, 1)
+	require.NoError(t, err)
+
+	rw := NewReadableWallet(w)
+	for i, re := range rw.Entries {
+		require.NotNil(t, re.ChildNumber)
+		require.NotNil(t, re.Change)
+		require.Equal(t, w.Entries[i].ChildNumber, *re.ChildNumber)
+		require.Equal(t, w.Entries[i].Change, *re.Change)
+	}
+
+	w2, err := rw.ToWallet()
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, w2.Entries)
+	require.Equal(t, w.Meta, w2.Meta)
+
+	// Deterministic wallets don't include the bip44 entry fields
+	dw, err := NewWallet("t.wlt", Options{
+		Seed: "seed",
+	})
+	require.NoError(t, err)
+	rdw := NewReadableWallet(dw)
+	require.Nil(t, rdw.Entries[0].ChildNumber)
+	require.Nil(t, rdw.Entries[0].Change)
+}
+
+func TestBip44WalletScanAddresses(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Type: WalletTypeBip44,
+		Seed: testBip44Seed,
+	})
+	require.NoError(t, err)
+	_, err = w.generateBip44Addresses(bip44.ChangeChainIndex, 1)
+	require.NoError(t, err)
+
+	// Find the third external address which has coins
+	w2 := w.clone()
+	addrs, err := w2.GenerateAddresses(3)
+	require.NoError(t, err)
+
+	bg := mockBalanceGetter{
+		addrs[1].(cipher.Address): BalancePair{
+			Confirmed: Balance{Coins: 10, Hours: 100},
+		},
+	}
+
+	n, err := w.ScanAddresses(5, bg)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), n)
+	require.Len(t, w.Entries, 4)
+	require.Equal(t, w2.Entries[:4], w.Entries)
+
+	nExternal, nChange := w.bip44EntriesCount()
+	require.Equal(t, uint64(3), nExternal)
+	require.Equal(t, uint64(1), nChange)
+}
+
+func TestServiceRecoverBip44Wallet(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Type:       WalletTypeBip44,
+		Seed:       testBip44Seed,
+		GenerateN:  3,
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: CryptoTypeSha256Xor,
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = s.RecoverWallet("t.wlt", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong", "", nil)
+	require.Equal(t, ErrWalletRecoverSeedWrong, err)
+
+	_, err = s.RecoverWallet("t.wlt", "seed", "", nil)
+	require.Equal(t, ErrWalletRecoverSeedWrong, err)
+
+	w2, err := s.RecoverWallet("t.wlt", testBip44Seed, "", []byte("pwd2"))
+	require.NoError(t, err)
+	require.True(t, w2.IsEncrypted())
+	require.Equal(t, WalletTypeBip44, w2.Type())
+	require.Equal(t, w.Entries, w2.Entries)
+
+	uw, err := w2.Unlock([]byte("pwd2"))
+	require.NoError(t, err)
+	require.Equal(t, testBip44Seed, uw.seed())
+}
+
+func TestBip44WalletSeedPassphrase(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Type:           WalletTypeBip44,
+		Seed:           testBip44Seed,
+		SeedPassphrase: "TREZOR",
+		GenerateN:      2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Validate())
+
+	// Addresses are derived from the bip39 seed of the mnemonic and passphrase
+	mk, err := bip32.NewMasterKey(bip39.NewSeed(testBip44Seed, "TREZOR"))
+	require.NoError(t, err)
+	path, err := bip32.ParsePath("m/44'/8000'/0'/0/0")
+	require.NoError(t, err)
+	k, err := mk.DeriveSubpath(path)
+	require.NoError(t, err)
+	sk, err := k.SecKey()
+	require.NoError(t, err)
+	require.Equal(t, sk, w.Entries[0].Secret)
+
+	// Wallets without a passphrase derive the same addresses as before
+	w2, err := NewWallet("t.wlt", Options{
+		Type: WalletTypeBip44,
+		Seed: testBip44Seed,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, w2.Entries[0].Address, w.Entries[0].Address)
+
+	// The passphrase is encrypted with the seed
+	require.NoError(t, w.Lock([]byte("pwd"), CryptoTypeSha256Xor))
+	require.Equal(t, "", w.seedPassphrase())
+	_, ok := w.Meta[metaSeedPassphrase]
+	require.False(t, ok)
+
+	uw, err := w.Unlock([]byte("pwd"))
+	require.NoError(t, err)
+	require.Equal(t, "TREZOR", uw.seedPassphrase())
+	_, err = uw.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), uw.Entries[2].ChildNumber)
+
+	// Encrypted wallets without a passphrase have none in their secrets
+	require.NoError(t, w2.Lock([]byte("pwd"), CryptoTypeSha256Xor))
+	uw2, err := w2.Unlock([]byte("pwd"))
+	require.NoError(t, err)
+	_, ok = uw2.Meta[metaSeedPassphrase]
+	require.False(t, ok)
+}
+
+func TestServiceRecoverBip44WalletSeedPassphrase(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Type:           WalletTypeBip44,
+		Seed:           testBip44Seed,
+		SeedPassphrase: "TREZOR",
+		GenerateN:      2,
+		Encrypt:        true,
+		Password:       []byte("pwd"),
+		CryptoType:     CryptoTypeSha256Xor,
+	}, nil)
+	require.NoError(t, err)
+
+	// The passphrase is required to recover the wallet
+	_, err = s.RecoverWallet("t.wlt", testBip44Seed, "", nil)
+	require.Equal(t, ErrWalletRecoverSeedWrong, err)
+
+	_, err = s.RecoverWallet("t.wlt", testBip44Seed, "wrong", nil)
+	require.Equal(t, ErrWalletRecoverSeedWrong, err)
+
+	w2, err := s.RecoverWallet("t.wlt", testBip44Seed, "TREZOR", []byte("pwd2"))
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, w2.Entries)
+
+	require.NoError(t, s.ViewSecrets("t.wlt", []byte("pwd2"), func(w *Wallet) error {
+		require.Equal(t, "TREZOR", w.seedPassphrase())
+		return nil
+	}))
+}
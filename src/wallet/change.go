@@ -0,0 +1,100 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+)
+
+const (
+	// ChangeAddressPolicyReuse sends the change of a transaction back to one of its input addresses,
+	// unless a change address is specified
+	ChangeAddressPolicyReuse = "reuse"
+	// ChangeAddressPolicyFresh sends the change of a transaction to a new address generated from the wallet,
+	// unless a change address is specified
+	ChangeAddressPolicyFresh = "fresh"
+)
+
+// validateChangeAddressPolicy checks that a change address policy is known and usable by the wallet type
+func validateChangeAddressPolicy(walletType, policy string) error {
+	switch policy {
+	case ChangeAddressPolicyReuse:
+		return nil
+	case ChangeAddressPolicyFresh:
+		// Address list wallets can't generate the change addresses
+		if walletType == WalletTypeAddresses {
+			return ErrCannotGenerateAddresses
+		}
+		return nil
+	default:
+		return ErrInvalidChangeAddressPolicy
+	}
+}
+
+// ChangeAddressPolicy returns the change address policy of the wallet, reuse if none was set
+func (w *Wallet) ChangeAddressPolicy() string {
+	if policy := w.Meta[metaChangeAddressPolicy]; policy != "" {
+		return policy
+	}
+	return ChangeAddressPolicyReuse
+}
+
+// SetChangeAddressPolicy sets the change address policy of the wallet
+func (w *Wallet) SetChangeAddressPolicy(policy string) error {
+	if err := validateChangeAddressPolicy(w.Type(), policy); err != nil {
+		return err
+	}
+
+	w.Meta[metaChangeAddressPolicy] = policy
+	return nil
+}
+
+// GenerateChangeAddress generates a new address to receive the change of a transaction.
+// bip44 and xpub wallets derive it from the bip44 change chain. Deterministic wallets
+// generate the next address of the wallet and mark its entry as a change address.
+func (w *Wallet) GenerateChangeAddress() (cipher.Addresser, error) {
+	if w.IsEncrypted() {
+		return nil, ErrWalletEncrypted
+	}
+
+	var addrs []cipher.Addresser
+	var err error
+	switch w.Type() {
+	case WalletTypeBip44:
+		addrs, err = w.generateBip44Addresses(bip44.ChangeChainIndex, 1)
+	case WalletTypeXPub:
+		addrs, err = w.generateXPubAddresses(bip44.ChangeChainIndex, 1)
+	case WalletTypeAddresses:
+		return nil, ErrCannotGenerateAddresses
+	default:
+		addrs, err = w.GenerateAddresses(1)
+		if err == nil {
+			w.Entries[len(w.Entries)-1].Change = bip44.ChangeChainIndex
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return addrs[0], nil
+}
+
+// GenerateSkycoinChangeAddress generates a new Skycoin change address. If the wallet's coin type is not Skycoin, returns an error
+func (w *Wallet) GenerateSkycoinChangeAddress() (cipher.Address, error) {
+	if w.coin() != CoinTypeSkycoin {
+		return cipher.Address{}, errors.New("GenerateSkycoinChangeAddress called for non-skycoin wallet")
+	}
+
+	addr, err := w.GenerateChangeAddress()
+	if err != nil {
+		return cipher.Address{}, err
+	}
+
+	return addr.(cipher.Address), nil
+}
+
+// IsChangeAddress returns true if the entry was generated as a change address
+func (we *Entry) IsChangeAddress() bool {
+	return we.Change == bip44.ChangeChainIndex
+}
@@ -0,0 +1,207 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+)
+
+func TestWalletChangeAddressPolicy(t *testing.T) {
+	_, err := NewWallet("t.wlt", Options{
+		Seed:                "seed",
+		ChangeAddressPolicy: "new",
+	})
+	require.Equal(t, ErrInvalidChangeAddressPolicy, err)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type:                WalletTypeAddresses,
+		Addresses:           []cipher.Addresser{cipher.MustDecodeBase58Address("2GgFvqoyk9RjwVzj8tqfcXVXB4orBwoc9qv")},
+		ChangeAddressPolicy: ChangeAddressPolicyFresh,
+	})
+	require.Equal(t, ErrCannotGenerateAddresses, err)
+
+	// The policy defaults to reuse, and is not recorded unless set
+	w, err := NewWallet("t.wlt", Options{
+		Seed: "seed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ChangeAddressPolicyReuse, w.ChangeAddressPolicy())
+	_, ok := w.Meta[metaChangeAddressPolicy]
+	require.False(t, ok)
+
+	require.Equal(t, ErrInvalidChangeAddressPolicy, w.SetChangeAddressPolicy("new"))
+	require.NoError(t, w.SetChangeAddressPolicy(ChangeAddressPolicyFresh))
+	require.Equal(t, ChangeAddressPolicyFresh, w.ChangeAddressPolicy())
+	require.NoError(t, w.Validate())
+
+	w.Meta[metaChangeAddressPolicy] = "new"
+	require.Error(t, w.Validate())
+}
+
+func TestWalletGenerateChangeAddress(t *testing.T) {
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+
+	// Deterministic wallets generate the next address, marked as a change address
+	w, err := NewWallet("t.wlt", Options{
+		Seed:                "seed",
+		GenerateN:           2,
+		ChangeAddressPolicy: ChangeAddressPolicyFresh,
+	})
+	require.NoError(t, err)
+
+	w2 := w.clone()
+	addrs, err := w2.GenerateAddresses(2)
+	require.NoError(t, err)
+
+	addr, err := w.GenerateSkycoinChangeAddress()
+	require.NoError(t, err)
+	require.Equal(t, addrs[0], addr)
+	require.Len(t, w.Entries, 3)
+	require.True(t, w.Entries[2].IsChangeAddress())
+	require.False(t, w.Entries[0].IsChangeAddress())
+	require.False(t, w.Entries[1].IsChangeAddress())
+
+	_, err = w.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Equal(t, addrs[1], w.Entries[3].Address)
+	require.False(t, w.Entries[3].IsChangeAddress())
+
+	// The change addresses and the policy are saved in the wallet file
+	require.NoError(t, w.Save(dir))
+	rw, err := LoadReadableWallet(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Nil(t, rw.Entries[0].Change)
+	require.NotNil(t, rw.Entries[2].Change)
+	require.Equal(t, bip44.ChangeChainIndex, *rw.Entries[2].Change)
+
+	lw, err := Load(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, lw.Entries)
+	require.Equal(t, ChangeAddressPolicyFresh, lw.ChangeAddressPolicy())
+
+	// Scanning regenerates the addresses, keeping the change addresses
+	_, err = lw.ScanAddresses(2, mockBalanceGetter{
+		w2.Entries[len(w2.Entries)-1].SkycoinAddress(): BalancePair{
+			Confirmed: Balance{Coins: 10, Hours: 100},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, lw.Entries[:4])
+	require.True(t, lw.Entries[2].IsChangeAddress())
+
+	// The change addresses are kept when the wallet is encrypted
+	require.NoError(t, w.Lock([]byte("pwd"), CryptoTypeScryptChacha20poly1305Insecure))
+	require.True(t, w.Entries[2].IsChangeAddress())
+	_, err = w.GenerateChangeAddress()
+	require.Equal(t, ErrWalletEncrypted, err)
+
+	err = w.GuardUpdate([]byte("pwd"), func(w *Wallet) error {
+		require.True(t, w.Entries[2].IsChangeAddress())
+		_, err := w.GenerateChangeAddress()
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 5)
+	require.True(t, w.Entries[4].IsChangeAddress())
+
+	// bip44 wallets derive the change addresses from the change chain
+	bw, err := NewWallet("b.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := bw.GenerateChangeAddress()
+		require.NoError(t, err)
+	}
+	require.Len(t, bw.Entries, 4)
+	for i, e := range bw.Entries[2:] {
+		require.True(t, e.IsChangeAddress())
+		require.Equal(t, uint32(i), e.ChildNumber)
+	}
+
+	// xpub wallets derive the same change addresses as the bip44 wallet of the account
+	xw, err := NewWallet("x.wlt", Options{
+		Type:      WalletTypeXPub,
+		XPub:      testAccountXPub(t, bip44.CoinTypeSkycoin),
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := xw.GenerateChangeAddress()
+		require.NoError(t, err)
+	}
+	_, err = xw.GenerateAddresses(1)
+	require.NoError(t, err)
+
+	_, err = bw.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Len(t, xw.Entries, 5)
+	for i, e := range xw.Entries {
+		require.Equal(t, bw.Entries[i].Address, e.Address)
+		require.Equal(t, bw.Entries[i].ChildNumber, e.ChildNumber)
+		require.Equal(t, bw.Entries[i].Change, e.Change)
+	}
+
+	// Address list wallets can't generate change addresses
+	aw, err := NewWallet("a.wlt", Options{
+		Type:      WalletTypeAddresses,
+		Addresses: []cipher.Addresser{w.Entries[0].Address},
+	})
+	require.NoError(t, err)
+	_, err = aw.GenerateChangeAddress()
+	require.Equal(t, ErrCannotGenerateAddresses, err)
+	require.Equal(t, ErrCannotGenerateAddresses, aw.SetChangeAddressPolicy(ChangeAddressPolicyFresh))
+}
+
+func TestServiceRecoverWalletChangeAddresses(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreateWallet("t.wlt", Options{
+		Seed:                "seed",
+		GenerateN:           2,
+		Encrypt:             true,
+		Password:            []byte("pwd"),
+		ChangeAddressPolicy: ChangeAddressPolicyFresh,
+	}, nil)
+	require.NoError(t, err)
+
+	err = s.UpdateSecrets("t.wlt", []byte("pwd"), func(w *Wallet) error {
+		_, err := w.GenerateChangeAddress()
+		return err
+	})
+	require.NoError(t, err)
+
+	w, err := s.RecoverWallet("t.wlt", "seed", "", nil)
+	require.NoError(t, err)
+	require.False(t, w.IsEncrypted())
+	require.Equal(t, ChangeAddressPolicyFresh, w.ChangeAddressPolicy())
+	require.Len(t, w.Entries, 3)
+	require.False(t, w.Entries[1].IsChangeAddress())
+	require.True(t, w.Entries[2].IsChangeAddress())
+
+	// The policy can be changed afterwards
+	w, err = s.UpdateWalletChangeAddressPolicy("t.wlt", ChangeAddressPolicyReuse)
+	require.NoError(t, err)
+	require.Equal(t, ChangeAddressPolicyReuse, w.ChangeAddressPolicy())
+
+	_, err = s.UpdateWalletChangeAddressPolicy("t.wlt", "new")
+	require.Equal(t, ErrInvalidChangeAddressPolicy, err)
+
+	_, err = s.UpdateWalletChangeAddressPolicy("missing.wlt", ChangeAddressPolicyFresh)
+	require.Equal(t, ErrWalletNotExist, err)
+}
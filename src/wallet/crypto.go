@@ -24,6 +24,10 @@ func CryptoTypeFromString(s string) (CryptoType, error) {
 		return CryptoTypeScryptChacha20poly1305, nil
 	case CryptoTypeScryptChacha20poly1305Insecure:
 		return CryptoTypeScryptChacha20poly1305Insecure, nil
+	case CryptoTypeArgon2idXChacha20poly1305:
+		return CryptoTypeArgon2idXChacha20poly1305, nil
+	case CryptoTypeArgon2idXChacha20poly1305Insecure:
+		return CryptoTypeArgon2idXChacha20poly1305Insecure, nil
 	default:
 		return "", errors.New("unknown crypto type")
 	}
@@ -37,6 +41,10 @@ const (
 	CryptoTypeScryptChacha20poly1305 = CryptoType("scrypt-chacha20poly1305")
 	// CryptoTypeScryptChacha20poly1305Insecure uses chacha20poly1305 + scrypt key derivation with a weak work factor (unsafe)
 	CryptoTypeScryptChacha20poly1305Insecure = CryptoType("scrypt-chacha20poly1305-insecure")
+	// CryptoTypeArgon2idXChacha20poly1305 uses xchacha20poly1305 + argon2id key derivation
+	CryptoTypeArgon2idXChacha20poly1305 = CryptoType("argon2id-xchacha20poly1305")
+	// CryptoTypeArgon2idXChacha20poly1305Insecure uses xchacha20poly1305 + argon2id key derivation with a weak work factor (unsafe)
+	CryptoTypeArgon2idXChacha20poly1305Insecure = CryptoType("argon2id-xchacha20poly1305-insecure")
 )
 
 // cryptoTable records all supported wallet crypto methods
@@ -50,6 +58,13 @@ var cryptoTable = map[CryptoType]cryptor{
 		P:      encrypt.ScryptP,
 		KeyLen: encrypt.ScryptKeyLen,
 	},
+	CryptoTypeArgon2idXChacha20poly1305: encrypt.DefaultArgon2idXChacha20poly1305,
+	CryptoTypeArgon2idXChacha20poly1305Insecure: encrypt.Argon2idXChacha20poly1305{
+		Time:    1,
+		Memory:  1024,
+		Threads: 1,
+		KeyLen:  encrypt.Argon2idKeyLen,
+	},
 }
 
 // getCrypto gets crypto of given type
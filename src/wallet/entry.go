@@ -11,6 +11,16 @@ type Entry struct {
 	Address cipher.Addresser
 	Public  cipher.PubKey
 	Secret  cipher.SecKey
+
+	// ChildNumber and Change are only used by bip44 wallets.
+	// ChildNumber is the address_index and Change is the chain of the entry's bip44 path.
+	// The child numbers whose keys are invalid are skipped, so they may not be contiguous.
+	// Deterministic wallets set Change to 1 for the entries generated as change addresses.
+	ChildNumber uint32
+	Change      uint32
+
+	// Label is a name given to the address by the user, empty if none
+	Label string
 }
 
 // SkycoinAddress returns the Skycoin address of an entry. Panics if Address is not a Skycoin address
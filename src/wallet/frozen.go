@@ -0,0 +1,181 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+)
+
+var (
+	// ErrUxOutFrozen is returned if a frozen unspent output is requested to be spent
+	ErrUxOutFrozen = NewError(errors.New("uxout is frozen"))
+	// ErrNoUxOutsSpecified is returned if no unspent outputs are specified to freeze or unfreeze
+	ErrNoUxOutsSpecified = NewError(errors.New("no unspent outputs specified"))
+)
+
+// FrozenOutput is an unspent output of a wallet that is never chosen to spend,
+// until it is unfrozen
+type FrozenOutput struct {
+	UxOut  cipher.SHA256
+	Reason string
+	Time   time.Time
+}
+
+// ReadableFrozenOutput frozen output with json tags
+type ReadableFrozenOutput struct {
+	UxID   string    `json:"uxid"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// NewReadableFrozenOutputs creates []ReadableFrozenOutput from []FrozenOutput
+func NewReadableFrozenOutputs(fos []FrozenOutput) []ReadableFrozenOutput {
+	if len(fos) == 0 {
+		return nil
+	}
+
+	rfos := make([]ReadableFrozenOutput, len(fos))
+	for i, fo := range fos {
+		rfos[i] = ReadableFrozenOutput{
+			UxID:   fo.UxOut.Hex(),
+			Reason: fo.Reason,
+			Time:   fo.Time,
+		}
+	}
+	return rfos
+}
+
+// toFrozenOutputs converts []ReadableFrozenOutput to []FrozenOutput
+func toFrozenOutputs(rfos []ReadableFrozenOutput) ([]FrozenOutput, error) {
+	if len(rfos) == 0 {
+		return nil, nil
+	}
+
+	fos := make([]FrozenOutput, len(rfos))
+	for i, rfo := range rfos {
+		h, err := cipher.SHA256FromHex(rfo.UxID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frozen output uxid: %v", err)
+		}
+
+		fos[i] = FrozenOutput{
+			UxOut:  h,
+			Reason: rfo.Reason,
+			Time:   rfo.Time,
+		}
+	}
+	return fos, nil
+}
+
+// GetFrozenOutputs returns the frozen outputs of the wallet, ordered by the time they were frozen
+func (w *Wallet) GetFrozenOutputs() []FrozenOutput {
+	fos := make([]FrozenOutput, len(w.FrozenOutputs))
+	copy(fos, w.FrozenOutputs)
+	return fos
+}
+
+// FrozenUxOuts returns the hashes of the frozen outputs of the wallet
+func (w *Wallet) FrozenUxOuts() map[cipher.SHA256]struct{} {
+	frozen := make(map[cipher.SHA256]struct{}, len(w.FrozenOutputs))
+	for _, fo := range w.FrozenOutputs {
+		frozen[fo.UxOut] = struct{}{}
+	}
+	return frozen
+}
+
+// IsFrozen returns true if the unspent output is frozen
+func (w *Wallet) IsFrozen(uxOut cipher.SHA256) bool {
+	for _, fo := range w.FrozenOutputs {
+		if fo.UxOut == uxOut {
+			return true
+		}
+	}
+	return false
+}
+
+// FreezeOutputs freezes the unspent outputs at time t, for a reason which may be empty.
+// Outputs that are already frozen keep their reason and time.
+// Returns the outputs that were frozen.
+func (w *Wallet) FreezeOutputs(uxOuts []cipher.SHA256, reason string, t time.Time) ([]FrozenOutput, error) {
+	if len(uxOuts) == 0 {
+		return nil, ErrNoUxOutsSpecified
+	}
+
+	frozen := w.FrozenUxOuts()
+	var fos []FrozenOutput
+	for _, h := range uxOuts {
+		if _, ok := frozen[h]; ok {
+			continue
+		}
+		frozen[h] = struct{}{}
+
+		fos = append(fos, FrozenOutput{
+			UxOut:  h,
+			Reason: reason,
+			Time:   t,
+		})
+	}
+
+	w.FrozenOutputs = append(w.FrozenOutputs, fos...)
+	sortFrozenOutputs(w.FrozenOutputs)
+
+	return fos, nil
+}
+
+// UnfreezeOutputs unfreezes the unspent outputs. Outputs that are not frozen are ignored.
+// Returns the outputs that were unfrozen.
+func (w *Wallet) UnfreezeOutputs(uxOuts []cipher.SHA256) ([]FrozenOutput, error) {
+	if len(uxOuts) == 0 {
+		return nil, ErrNoUxOutsSpecified
+	}
+
+	unfreeze := make(map[cipher.SHA256]struct{}, len(uxOuts))
+	for _, h := range uxOuts {
+		unfreeze[h] = struct{}{}
+	}
+
+	var kept, unfrozen []FrozenOutput
+	for _, fo := range w.FrozenOutputs {
+		if _, ok := unfreeze[fo.UxOut]; ok {
+			unfrozen = append(unfrozen, fo)
+		} else {
+			kept = append(kept, fo)
+		}
+	}
+
+	w.FrozenOutputs = kept
+
+	return unfrozen, nil
+}
+
+// ExcludeFrozenUxOuts returns the unspent outputs of auxs that are not frozen
+func (w *Wallet) ExcludeFrozenUxOuts(auxs coin.AddressUxOuts) coin.AddressUxOuts {
+	if len(w.FrozenOutputs) == 0 {
+		return auxs
+	}
+
+	frozen := w.FrozenUxOuts()
+	unfrozen := make(coin.AddressUxOuts, len(auxs))
+	for a, uxa := range auxs {
+		for _, ux := range uxa {
+			if _, ok := frozen[ux.Hash()]; !ok {
+				unfrozen[a] = append(unfrozen[a], ux)
+			}
+		}
+	}
+
+	return unfrozen
+}
+
+func sortFrozenOutputs(fos []FrozenOutput) {
+	sort.SliceStable(fos, func(i, j int) bool {
+		if fos[i].Time.Equal(fos[j].Time) {
+			return fos[i].UxOut.Hex() < fos[j].UxOut.Hex()
+		}
+		return fos[i].Time.Before(fos[j].Time)
+	})
+}
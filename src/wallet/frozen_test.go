@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+func TestWalletFreezeOutputs(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Seed:      "seed",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, w.GetFrozenOutputs())
+
+	_, err = w.FreezeOutputs(nil, "", time.Now())
+	require.Equal(t, ErrNoUxOutsSpecified, err)
+	_, err = w.UnfreezeOutputs(nil)
+	require.Equal(t, ErrNoUxOutsSpecified, err)
+
+	t1 := time.Unix(1540000000, 0).UTC()
+	t2 := t1.Add(time.Hour)
+	h1 := testutil.RandSHA256(t)
+	h2 := testutil.RandSHA256(t)
+	h3 := testutil.RandSHA256(t)
+
+	fos, err := w.FreezeOutputs([]cipher.SHA256{h2}, "compliance review", t2)
+	require.NoError(t, err)
+	require.Equal(t, []FrozenOutput{{UxOut: h2, Reason: "compliance review", Time: t2}}, fos)
+
+	// Frozen outputs are ordered by time, and keep their reason if frozen again
+	fos, err = w.FreezeOutputs([]cipher.SHA256{h1, h2, h1}, "tainted", t1)
+	require.NoError(t, err)
+	require.Equal(t, []FrozenOutput{{UxOut: h1, Reason: "tainted", Time: t1}}, fos)
+	require.Equal(t, []FrozenOutput{
+		{UxOut: h1, Reason: "tainted", Time: t1},
+		{UxOut: h2, Reason: "compliance review", Time: t2},
+	}, w.GetFrozenOutputs())
+
+	require.True(t, w.IsFrozen(h1))
+	require.True(t, w.IsFrozen(h2))
+	require.False(t, w.IsFrozen(h3))
+
+	// The frozen outputs are copied with the wallet
+	require.Equal(t, w.FrozenOutputs, w.clone().FrozenOutputs)
+
+	// The frozen outputs are saved in the wallet file
+	dir := prepareWltDir()
+	defer os.RemoveAll(dir)
+	require.NoError(t, w.Save(dir))
+	w2, err := Load(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Equal(t, w.FrozenOutputs, w2.FrozenOutputs)
+
+	// The frozen outputs are kept when the wallet is encrypted
+	require.NoError(t, w2.Lock([]byte("pwd"), CryptoTypeScryptChacha20poly1305Insecure))
+	require.Equal(t, w.FrozenOutputs, w2.FrozenOutputs)
+
+	// Outputs that are not frozen are ignored when unfreezing
+	fos, err = w.UnfreezeOutputs([]cipher.SHA256{h1, h3})
+	require.NoError(t, err)
+	require.Equal(t, []FrozenOutput{{UxOut: h1, Reason: "tainted", Time: t1}}, fos)
+	require.Equal(t, []FrozenOutput{{UxOut: h2, Reason: "compliance review", Time: t2}}, w.GetFrozenOutputs())
+
+	_, err = w.UnfreezeOutputs([]cipher.SHA256{h2})
+	require.NoError(t, err)
+	require.Empty(t, w.GetFrozenOutputs())
+
+	// A wallet without frozen outputs is saved without them
+	require.NoError(t, w.Save(dir))
+	rw, err := LoadReadableWallet(filepath.Join(dir, "t.wlt"))
+	require.NoError(t, err)
+	require.Nil(t, rw.FrozenOutputs)
+}
+
+func TestWalletExcludeFrozenUxOuts(t *testing.T) {
+	w, err := NewWallet("t.wlt", Options{
+		Seed:      "seed",
+		GenerateN: 1,
+	})
+	require.NoError(t, err)
+
+	_, s := cipher.GenerateKeyPair()
+	ux1 := makeUxOut(t, s, 1e6, 10)
+	ux2 := makeUxOut(t, s, 2e6, 10)
+	auxs := coin.NewAddressUxOuts(coin.UxArray{ux1, ux2})
+
+	require.Equal(t, auxs, w.ExcludeFrozenUxOuts(auxs))
+
+	_, err = w.FreezeOutputs([]cipher.SHA256{ux1.Hash()}, "", time.Now())
+	require.NoError(t, err)
+
+	unfrozen := w.ExcludeFrozenUxOuts(auxs)
+	require.Equal(t, coin.UxArray{ux2}, unfrozen.Flatten())
+
+	_, err = w.FreezeOutputs([]cipher.SHA256{ux2.Hash()}, "", time.Now())
+	require.NoError(t, err)
+	require.Empty(t, w.ExcludeFrozenUxOuts(auxs).Flatten())
+}
@@ -0,0 +1,915 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/util/droplet"
+)
+
+// PartiallySignedTransactionVersion is the current version of the partially signed transaction format
+const PartiallySignedTransactionVersion uint16 = 1
+
+var (
+	// ErrUnsupportedPartiallySignedTransactionVersion is returned if a partially signed transaction has an unknown version
+	ErrUnsupportedPartiallySignedTransactionVersion = NewError(errors.New("unsupported partially signed transaction version"))
+	// ErrPartiallySignedTransactionMismatch is returned when combining partially signed transactions of different transactions
+	ErrPartiallySignedTransactionMismatch = NewError(errors.New("partially signed transactions do not spend the same transaction"))
+	// ErrPartiallySignedTransactionNotFullySigned is returned when finalizing a partially signed transaction that is missing signatures
+	ErrPartiallySignedTransactionNotFullySigned = NewError(errors.New("partially signed transaction is not fully signed"))
+	// ErrPartiallySignedTransactionFullySigned is returned when signing a partially signed transaction that has no missing signatures
+	ErrPartiallySignedTransactionFullySigned = NewError(errors.New("partially signed transaction is already fully signed"))
+	// ErrNoInputsToSign is returned if a wallet does not own any of the unsigned inputs of a partially signed transaction
+	ErrNoInputsToSign = NewError(errors.New("wallet does not own any unsigned inputs"))
+	// ErrMissingMultisigRedeemScript is returned when signing a multisig input whose public keys are not known
+	ErrMissingMultisigRedeemScript = NewError(errors.New("multisig input has no public keys"))
+	// ErrMissingTimeLockScript is returned when signing a time-locked input whose lock and owner are not known
+	ErrMissingTimeLockScript = NewError(errors.New("time-locked input has no time-lock script"))
+)
+
+const (
+	// TimeLockKindHeight is the readable kind of a lock until a block height
+	TimeLockKindHeight = "height"
+	// TimeLockKindTime is the readable kind of a lock until a unix time
+	TimeLockKindTime = "time"
+)
+
+// PartiallySignedInput is an input of a partially signed transaction
+type PartiallySignedInput struct {
+	// UxOut is the unspent output spent by the input
+	UxOut coin.UxOut
+	// Wallet is the filename of the wallet that signed the input, if known.
+	// It is not set for multisig and time-locked inputs
+	Wallet string
+	// Multisig holds the public keys and the collected signatures of an input owned by a multisig address,
+	// or of a time-locked input owned by a multisig address
+	Multisig *MultisigInput
+	// TimeLock holds the lock and owner of an input owned by a time-locked address
+	TimeLock *TimeLockInput
+}
+
+// ownerAddress returns the address that must authorize the spend of the input,
+// which is the owner of the lock for time-locked inputs
+func (in PartiallySignedInput) ownerAddress() cipher.Address {
+	if in.TimeLock != nil {
+		return in.TimeLock.Owner
+	}
+	return in.UxOut.Body.Address
+}
+
+// TimeLockInput is the time-lock script of a time-locked input.
+// If the owner is a standard address, Sig is its signature, null until signed.
+// If the owner is a multisig address, the signatures are collected in PartiallySignedInput.Multisig.
+type TimeLockInput struct {
+	Kind  byte
+	Value uint64
+	Owner cipher.Address
+	Sig   cipher.Sig
+}
+
+func (tl *TimeLockInput) copy() *TimeLockInput {
+	if tl == nil {
+		return nil
+	}
+	c := *tl
+	return &c
+}
+
+// validate checks that the time-lock script hashes to addr and that the signature signed hash
+func (tl *TimeLockInput) validate(addr cipher.Address, hash cipher.SHA256) error {
+	a, err := cipher.TimeLockedAddress(tl.Owner, tl.Kind, tl.Value)
+	if err != nil {
+		return err
+	}
+	if a != addr {
+		return errors.New("time-lock script does not match the address")
+	}
+
+	if tl.Sig.Null() {
+		return nil
+	}
+	if tl.Owner.IsMultisig() {
+		return errors.New("time-lock owner is a multisig address, signatures must be set on the multisig input")
+	}
+
+	return cipher.VerifyAddressSignedHash(tl.Owner, tl.Sig, hash)
+}
+
+// MultisigInput is the redeem script of a multisig input along with its collected signatures
+type MultisigInput struct {
+	Required int
+	PubKeys  []cipher.PubKey
+	// Sigs has one signature per public key, null if that key has not signed yet
+	Sigs []cipher.Sig
+}
+
+// NewMultisigInput creates a MultisigInput without signatures
+func NewMultisigInput(required int, pubKeys []cipher.PubKey) *MultisigInput {
+	return &MultisigInput{
+		Required: required,
+		PubKeys:  append([]cipher.PubKey{}, pubKeys...),
+		Sigs:     make([]cipher.Sig, len(pubKeys)),
+	}
+}
+
+// signatures returns the number of collected signatures
+func (m *MultisigInput) signatures() int {
+	n := 0
+	for _, s := range m.Sigs {
+		if !s.Null() {
+			n++
+		}
+	}
+	return n
+}
+
+// witness returns the witness made of the first Required signatures
+func (m *MultisigInput) witness() coin.MultisigWitness {
+	w := coin.MultisigWitness{
+		Required: m.Required,
+		PubKeys:  m.PubKeys,
+	}
+	for _, s := range m.Sigs {
+		if !s.Null() && len(w.Sigs) < m.Required {
+			w.Sigs = append(w.Sigs, s)
+		}
+	}
+	return w
+}
+
+func (m *MultisigInput) copy() *MultisigInput {
+	if m == nil {
+		return nil
+	}
+	return &MultisigInput{
+		Required: m.Required,
+		PubKeys:  append([]cipher.PubKey{}, m.PubKeys...),
+		Sigs:     append([]cipher.Sig{}, m.Sigs...),
+	}
+}
+
+// validate checks that the redeem script hashes to addr and that the signatures signed hash
+func (m *MultisigInput) validate(addr cipher.Address, hash cipher.SHA256) error {
+	a, err := cipher.MultisigAddressFromPubKeys(m.Required, m.PubKeys)
+	if err != nil {
+		return err
+	}
+	if a != addr {
+		return errors.New("multisig public keys do not match the address")
+	}
+
+	if len(m.Sigs) != len(m.PubKeys) {
+		return errors.New("multisig signatures and public keys length mismatch")
+	}
+
+	for j, s := range m.Sigs {
+		if s.Null() {
+			continue
+		}
+		if err := cipher.VerifyPubKeySignedHash(m.PubKeys[j], s, hash); err != nil {
+			return fmt.Errorf("multisig signature %d: %v", j, err)
+		}
+	}
+
+	return nil
+}
+
+// PartiallySignedTransaction carries a transaction that is being signed by one or more parties,
+// along with the outputs it spends, so that it can be signed without access to a node.
+type PartiallySignedTransaction struct {
+	Version     uint16
+	Transaction coin.Transaction
+	Inputs      []PartiallySignedInput
+}
+
+// NewPartiallySignedTransaction creates a PartiallySignedTransaction from a transaction and the outputs spent by it.
+// uxOuts must be in the same order as the transaction's inputs.
+// Inputs owned by multisig addresses can't be signed until their public keys are set with SetMultisigInput,
+// and inputs owned by time-locked addresses until their time-lock script is set with SetTimeLockInput.
+func NewPartiallySignedTransaction(txn *coin.Transaction, uxOuts []coin.UxOut) (*PartiallySignedTransaction, error) {
+	inputs := make([]PartiallySignedInput, len(uxOuts))
+	for i, o := range uxOuts {
+		inputs[i] = PartiallySignedInput{
+			UxOut: o,
+		}
+	}
+
+	pst := &PartiallySignedTransaction{
+		Version:     PartiallySignedTransactionVersion,
+		Transaction: *copyTransaction(txn),
+		Inputs:      inputs,
+	}
+
+	if err := pst.Validate(); err != nil {
+		return nil, err
+	}
+
+	return pst, nil
+}
+
+// Validate checks that the transaction matches its inputs and that any existing signatures are valid
+func (pst *PartiallySignedTransaction) Validate() error {
+	if pst.Version != PartiallySignedTransactionVersion {
+		return ErrUnsupportedPartiallySignedTransactionVersion
+	}
+
+	txn := &pst.Transaction
+	if len(txn.In) == 0 {
+		return NewError(errors.New("transaction has no inputs"))
+	}
+	if len(txn.Sigs) != len(txn.In) {
+		return NewError(errors.New("transaction signatures and inputs length mismatch"))
+	}
+	if len(pst.Inputs) != len(txn.In) {
+		return NewError(errors.New("partially signed transaction inputs and transaction inputs length mismatch"))
+	}
+	if txn.InnerHash != txn.HashInner() {
+		return NewError(errors.New("transaction inner hash does not match computed inner hash"))
+	}
+
+	for i, in := range pst.Inputs {
+		if in.UxOut.Hash() != txn.In[i] {
+			return NewError(fmt.Errorf("uxout of input %d does not match transaction input %s", i, txn.In[i].Hex()))
+		}
+
+		hash := cipher.AddSHA256(txn.InnerHash, txn.In[i])
+
+		if in.TimeLock != nil {
+			if !in.UxOut.Body.Address.IsTimeLocked() {
+				return NewError(fmt.Errorf("input %d is not owned by a time-locked address", i))
+			}
+
+			if err := in.TimeLock.validate(in.UxOut.Body.Address, hash); err != nil {
+				return NewError(fmt.Errorf("invalid time-locked input %d: %v", i, err))
+			}
+		}
+
+		if in.Multisig == nil {
+			continue
+		}
+
+		if !in.ownerAddress().IsMultisig() {
+			return NewError(fmt.Errorf("input %d is not owned by a multisig address", i))
+		}
+
+		if err := in.Multisig.validate(in.ownerAddress(), hash); err != nil {
+			return NewError(fmt.Errorf("invalid multisig input %d: %v", i, err))
+		}
+	}
+
+	if err := txn.VerifyPartialInputSignatures(pst.uxOuts()); err != nil {
+		return NewError(err)
+	}
+
+	return nil
+}
+
+func (pst *PartiallySignedTransaction) uxOuts() []coin.UxOut {
+	uxOuts := make([]coin.UxOut, len(pst.Inputs))
+	for i, in := range pst.Inputs {
+		uxOuts[i] = in.UxOut
+	}
+	return uxOuts
+}
+
+// Signed returns true if input i has a signature, or enough signatures if it is a multisig input
+func (pst *PartiallySignedTransaction) Signed(i int) bool {
+	if m := pst.Inputs[i].Multisig; m != nil {
+		return m.signatures() >= m.Required
+	}
+	if tl := pst.Inputs[i].TimeLock; tl != nil {
+		return !tl.Sig.Null()
+	}
+	return !pst.Transaction.Sigs[i].Null()
+}
+
+// IsFullySigned returns true if every input is signed
+func (pst *PartiallySignedTransaction) IsFullySigned() bool {
+	for i := range pst.Inputs {
+		if !pst.Signed(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasWitnessInputs returns true if any input is owned by a multisig or time-locked address
+func (pst *PartiallySignedTransaction) hasWitnessInputs() bool {
+	for _, in := range pst.Inputs {
+		if isWitnessAddress(in.UxOut.Body.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWitnessAddress returns true if spending addr needs a witness instead of a single signature
+func isWitnessAddress(addr cipher.Address) bool {
+	return addr.IsMultisig() || addr.IsTimeLocked()
+}
+
+func (pst *PartiallySignedTransaction) copyInputs() []PartiallySignedInput {
+	inputs := make([]PartiallySignedInput, len(pst.Inputs))
+	for i, in := range pst.Inputs {
+		inputs[i] = in
+		inputs[i].Multisig = in.Multisig.copy()
+		inputs[i].TimeLock = in.TimeLock.copy()
+	}
+	return inputs
+}
+
+// SetMultisigInput sets the public keys of the m-of-n multisig address that owns input i,
+// which are needed to sign it. The public keys must hash to the address of the input,
+// or to the owner of its time-lock, so setting them again leaves the collected signatures unchanged.
+func (pst *PartiallySignedTransaction) SetMultisigInput(i, required int, pubKeys []cipher.PubKey) error {
+	if i < 0 || i >= len(pst.Inputs) {
+		return NewError(fmt.Errorf("input index %d out of range", i))
+	}
+
+	addr := pst.Inputs[i].ownerAddress()
+	if !addr.IsMultisig() {
+		return NewError(fmt.Errorf("input %d is not owned by a multisig address", i))
+	}
+
+	m := NewMultisigInput(required, pubKeys)
+	if err := m.validate(addr, cipher.SHA256{}); err != nil {
+		return NewError(fmt.Errorf("invalid multisig input %d: %v", i, err))
+	}
+
+	if pst.Inputs[i].Multisig == nil {
+		pst.Inputs[i].Multisig = m
+	}
+
+	return nil
+}
+
+// SetTimeLockInput sets the time-lock script of the time-locked address that owns input i,
+// which is needed to sign it. The script must hash to the address of the input,
+// so setting it again leaves the collected signature unchanged.
+// If owner is a multisig address, its public keys must be set with SetMultisigInput afterwards.
+func (pst *PartiallySignedTransaction) SetTimeLockInput(i int, kind byte, value uint64, owner cipher.Address) error {
+	if i < 0 || i >= len(pst.Inputs) {
+		return NewError(fmt.Errorf("input index %d out of range", i))
+	}
+
+	addr := pst.Inputs[i].UxOut.Body.Address
+	if !addr.IsTimeLocked() {
+		return NewError(fmt.Errorf("input %d is not owned by a time-locked address", i))
+	}
+
+	tl := &TimeLockInput{
+		Kind:  kind,
+		Value: value,
+		Owner: owner,
+	}
+	if err := tl.validate(addr, cipher.SHA256{}); err != nil {
+		return NewError(fmt.Errorf("invalid time-locked input %d: %v", i, err))
+	}
+
+	if pst.Inputs[i].TimeLock == nil {
+		pst.Inputs[i].TimeLock = tl
+	}
+
+	return nil
+}
+
+// SignPartiallySignedTransaction signs the inputs of a partially signed transaction that the wallet owns.
+// Specific inputs may be signed by specifying signIndexes.
+// If signIndexes is empty, all unsigned inputs owned by the wallet will be signed.
+// The signed inputs are marked with the wallet's filename.
+func (w *Wallet) SignPartiallySignedTransaction(pst *PartiallySignedTransaction, signIndexes []int) (*PartiallySignedTransaction, error) {
+	if err := pst.Validate(); err != nil {
+		return nil, err
+	}
+
+	if pst.IsFullySigned() {
+		return nil, ErrPartiallySignedTransactionFullySigned
+	}
+
+	if len(signIndexes) == 0 {
+		for i, in := range pst.Inputs {
+			if pst.Signed(i) {
+				continue
+			}
+
+			if in.Multisig != nil {
+				if len(w.multisigSignIndexes(in.Multisig)) > 0 {
+					signIndexes = append(signIndexes, i)
+				}
+			} else if w.HasEntry(in.ownerAddress()) {
+				signIndexes = append(signIndexes, i)
+			}
+		}
+
+		if len(signIndexes) == 0 {
+			return nil, ErrNoInputsToSign
+		}
+	}
+
+	if err := validateSignIndexes(signIndexes, pst.uxOuts()); err != nil {
+		return nil, NewError(err)
+	}
+
+	// Multisig and time-locked inputs are signed separately, their signatures are kept outside
+	// of the transaction until the partially signed transaction is finalized
+	var stdIndexes, msIndexes []int
+	for _, i := range signIndexes {
+		if isWitnessAddress(pst.Inputs[i].UxOut.Body.Address) {
+			msIndexes = append(msIndexes, i)
+		} else {
+			stdIndexes = append(stdIndexes, i)
+		}
+	}
+
+	signedTxn := copyTransaction(&pst.Transaction)
+	if len(stdIndexes) > 0 {
+		var err error
+		signedTxn, err = w.SignTransaction(&pst.Transaction, stdIndexes, pst.uxOuts())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inputs := pst.copyInputs()
+	for _, i := range stdIndexes {
+		inputs[i].Wallet = w.Filename()
+	}
+
+	if len(msIndexes) > 0 {
+		if w.IsEncrypted() {
+			return nil, ErrWalletEncrypted
+		}
+		if w.IsWatchOnly() {
+			return nil, ErrWatchOnlyWallet
+		}
+	}
+
+	for _, i := range msIndexes {
+		in := inputs[i]
+		if in.UxOut.Body.Address.IsTimeLocked() && in.TimeLock == nil {
+			return nil, ErrMissingTimeLockScript
+		}
+
+		hash := cipher.AddSHA256(signedTxn.InnerHash, signedTxn.In[i])
+
+		if tl := in.TimeLock; tl != nil && !tl.Owner.IsMultisig() {
+			e, ok := w.GetEntry(tl.Owner)
+			if !ok {
+				return nil, NewError(fmt.Errorf("wallet cannot sign time-locked input %d", i))
+			}
+			sig, err := cipher.SignHash(hash, e.Secret)
+			if err != nil {
+				return nil, err
+			}
+			tl.Sig = sig
+			continue
+		}
+
+		m := in.Multisig
+		if m == nil {
+			return nil, ErrMissingMultisigRedeemScript
+		}
+
+		keyIndexes := w.multisigSignIndexes(m)
+		if len(keyIndexes) == 0 {
+			return nil, NewError(fmt.Errorf("wallet cannot sign multisig input %d", i))
+		}
+
+		for _, j := range keyIndexes {
+			e, _ := w.GetEntry(cipher.AddressFromPubKey(m.PubKeys[j]))
+			sig, err := cipher.SignHash(hash, e.Secret)
+			if err != nil {
+				return nil, err
+			}
+			m.Sigs[j] = sig
+		}
+	}
+
+	return &PartiallySignedTransaction{
+		Version:     pst.Version,
+		Transaction: *signedTxn,
+		Inputs:      inputs,
+	}, nil
+}
+
+// multisigSignIndexes returns the indexes of the public keys of a multisig input
+// that have not signed yet and are owned by the wallet
+func (w *Wallet) multisigSignIndexes(m *MultisigInput) []int {
+	var idxs []int
+	for j, pk := range m.PubKeys {
+		if m.Sigs[j].Null() && w.HasEntry(cipher.AddressFromPubKey(pk)) {
+			idxs = append(idxs, j)
+		}
+	}
+	return idxs
+}
+
+// CombinePartiallySignedTransactions merges the signatures of partially signed transactions of the same transaction.
+// If more than one of them signs an input, the signature of the first one is kept.
+func CombinePartiallySignedTransactions(psts ...*PartiallySignedTransaction) (*PartiallySignedTransaction, error) {
+	if len(psts) == 0 {
+		return nil, NewError(errors.New("no partially signed transactions to combine"))
+	}
+
+	for _, pst := range psts {
+		if err := pst.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	base := psts[0]
+	txn := copyTransaction(&base.Transaction)
+	inputs := base.copyInputs()
+
+	for _, pst := range psts[1:] {
+		if pst.Transaction.InnerHash != txn.InnerHash {
+			return nil, ErrPartiallySignedTransactionMismatch
+		}
+
+		for i, in := range pst.Inputs {
+			if in.UxOut != inputs[i].UxOut {
+				return nil, ErrPartiallySignedTransactionMismatch
+			}
+
+			if txn.Sigs[i].Null() && !pst.Transaction.Sigs[i].Null() {
+				txn.Sigs[i] = pst.Transaction.Sigs[i]
+				inputs[i].Wallet = in.Wallet
+			}
+
+			if in.TimeLock != nil {
+				tl := inputs[i].TimeLock
+				if tl == nil {
+					inputs[i].TimeLock = in.TimeLock.copy()
+				} else {
+					if tl.Kind != in.TimeLock.Kind || tl.Value != in.TimeLock.Value || tl.Owner != in.TimeLock.Owner {
+						return nil, ErrPartiallySignedTransactionMismatch
+					}
+					if tl.Sig.Null() {
+						tl.Sig = in.TimeLock.Sig
+					}
+				}
+			}
+
+			if in.Multisig == nil {
+				continue
+			}
+
+			m := inputs[i].Multisig
+			if m == nil {
+				inputs[i].Multisig = in.Multisig.copy()
+				continue
+			}
+
+			if m.Required != in.Multisig.Required || len(m.PubKeys) != len(in.Multisig.PubKeys) {
+				return nil, ErrPartiallySignedTransactionMismatch
+			}
+			for j, pk := range in.Multisig.PubKeys {
+				if m.PubKeys[j] != pk {
+					return nil, ErrPartiallySignedTransactionMismatch
+				}
+				if m.Sigs[j].Null() {
+					m.Sigs[j] = in.Multisig.Sigs[j]
+				}
+			}
+		}
+	}
+
+	if err := txn.UpdateHeader(); err != nil {
+		return nil, err
+	}
+
+	return &PartiallySignedTransaction{
+		Version:     base.Version,
+		Transaction: *txn,
+		Inputs:      inputs,
+	}, nil
+}
+
+// Finalize returns the fully signed transaction, ready to be injected
+func (pst *PartiallySignedTransaction) Finalize() (*coin.Transaction, error) {
+	if err := pst.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !pst.IsFullySigned() {
+		return nil, ErrPartiallySignedTransactionNotFullySigned
+	}
+
+	txn := copyTransaction(&pst.Transaction)
+
+	if pst.hasWitnessInputs() {
+		witnesses := make(map[int]coin.Witness)
+		for i, in := range pst.Inputs {
+			switch {
+			case in.TimeLock != nil:
+				w := coin.TimeLockWitness{
+					Kind:  in.TimeLock.Kind,
+					Value: in.TimeLock.Value,
+					Owner: in.TimeLock.Owner,
+					Sig:   in.TimeLock.Sig,
+				}
+				if in.Multisig != nil {
+					ms := in.Multisig.witness()
+					w.Multisig = &ms
+				}
+				witnesses[i] = w
+			case in.Multisig != nil:
+				witnesses[i] = in.Multisig.witness()
+			}
+		}
+
+		if err := txn.SetWitnesses(witnesses); err != nil {
+			return nil, NewError(err)
+		}
+	}
+
+	if err := txn.VerifyInputSignatures(pst.uxOuts()); err != nil {
+		return nil, NewError(err)
+	}
+
+	if err := txn.Verify(); err != nil {
+		return nil, NewError(err)
+	}
+
+	return txn, nil
+}
+
+// ReadablePartiallySignedInput is the JSON representation of a PartiallySignedInput
+type ReadablePartiallySignedInput struct {
+	UxID    string `json:"uxid"`
+	Address string `json:"address"`
+	Coins   string `json:"coins"`
+	Hours   uint64 `json:"hours"`
+	SrcTxn  string `json:"src_tx"`
+	Time    uint64 `json:"time"`
+	BkSeq   uint64 `json:"block_seq"`
+	Wallet  string `json:"wallet,omitempty"`
+	Signed  bool   `json:"signed"`
+
+	Multisig *ReadableMultisigInput `json:"multisig,omitempty"`
+	TimeLock *ReadableTimeLockInput `json:"time_lock,omitempty"`
+}
+
+// ReadableTimeLockInput is the JSON representation of a TimeLockInput.
+// Signature is empty if the owner has not signed yet, or if the owner is a multisig address.
+type ReadableTimeLockInput struct {
+	Kind      string `json:"kind"`
+	Value     uint64 `json:"value"`
+	Owner     string `json:"owner"`
+	Signature string `json:"signature"`
+}
+
+// ParseTimeLockKind converts a readable time-lock kind to a cipher time-lock kind
+func ParseTimeLockKind(kind string) (byte, error) {
+	switch kind {
+	case TimeLockKindHeight:
+		return cipher.TimeLockHeight, nil
+	case TimeLockKindTime:
+		return cipher.TimeLockTime, nil
+	default:
+		return 0, fmt.Errorf("invalid time-lock kind %q, must be %q or %q", kind, TimeLockKindHeight, TimeLockKindTime)
+	}
+}
+
+// NewReadableTimeLockInput creates a ReadableTimeLockInput
+func NewReadableTimeLockInput(tl *TimeLockInput) *ReadableTimeLockInput {
+	r := &ReadableTimeLockInput{
+		Value: tl.Value,
+		Owner: tl.Owner.String(),
+	}
+	switch tl.Kind {
+	case cipher.TimeLockHeight:
+		r.Kind = TimeLockKindHeight
+	case cipher.TimeLockTime:
+		r.Kind = TimeLockKindTime
+	}
+	if !tl.Sig.Null() {
+		r.Signature = tl.Sig.Hex()
+	}
+	return r
+}
+
+// ToTimeLockInput converts a ReadableTimeLockInput to a TimeLockInput
+func (r ReadableTimeLockInput) ToTimeLockInput() (*TimeLockInput, error) {
+	kind, err := ParseTimeLockKind(r.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := cipher.DecodeBase58Address(r.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner: %v", err)
+	}
+
+	tl := &TimeLockInput{
+		Kind:  kind,
+		Value: r.Value,
+		Owner: owner,
+	}
+
+	if r.Signature != "" {
+		sig, err := cipher.SigFromHex(r.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %v", err)
+		}
+		tl.Sig = sig
+	}
+
+	return tl, nil
+}
+
+// ReadableMultisigInput is the JSON representation of a MultisigInput.
+// Signatures has one entry per public key, empty if that key has not signed yet.
+type ReadableMultisigInput struct {
+	Required   int      `json:"required"`
+	PubKeys    []string `json:"public_keys"`
+	Signatures []string `json:"signatures"`
+}
+
+// NewReadableMultisigInput creates a ReadableMultisigInput
+func NewReadableMultisigInput(m *MultisigInput) *ReadableMultisigInput {
+	r := &ReadableMultisigInput{
+		Required:   m.Required,
+		PubKeys:    make([]string, len(m.PubKeys)),
+		Signatures: make([]string, len(m.Sigs)),
+	}
+	for i, pk := range m.PubKeys {
+		r.PubKeys[i] = pk.Hex()
+	}
+	for i, s := range m.Sigs {
+		if !s.Null() {
+			r.Signatures[i] = s.Hex()
+		}
+	}
+	return r
+}
+
+// ToMultisigInput converts a ReadableMultisigInput to a MultisigInput
+func (r ReadableMultisigInput) ToMultisigInput() (*MultisigInput, error) {
+	if len(r.Signatures) != len(r.PubKeys) {
+		return nil, errors.New("multisig signatures and public keys length mismatch")
+	}
+
+	m := &MultisigInput{
+		Required: r.Required,
+		PubKeys:  make([]cipher.PubKey, len(r.PubKeys)),
+		Sigs:     make([]cipher.Sig, len(r.Signatures)),
+	}
+
+	for i, pk := range r.PubKeys {
+		p, err := cipher.PubKeyFromHex(pk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %d: %v", i, err)
+		}
+		m.PubKeys[i] = p
+	}
+
+	for i, s := range r.Signatures {
+		if s == "" {
+			continue
+		}
+		sig, err := cipher.SigFromHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %d: %v", i, err)
+		}
+		m.Sigs[i] = sig
+	}
+
+	return m, nil
+}
+
+// ReadablePartiallySignedTransaction is the JSON representation of a PartiallySignedTransaction
+type ReadablePartiallySignedTransaction struct {
+	Version     uint16                         `json:"version"`
+	Transaction string                         `json:"transaction"`
+	Inputs      []ReadablePartiallySignedInput `json:"inputs"`
+}
+
+// NewReadablePartiallySignedTransaction creates a ReadablePartiallySignedTransaction
+func NewReadablePartiallySignedTransaction(pst *PartiallySignedTransaction) (*ReadablePartiallySignedTransaction, error) {
+	txnHex, err := pst.Transaction.SerializeHex()
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]ReadablePartiallySignedInput, len(pst.Inputs))
+	for i, in := range pst.Inputs {
+		coins, err := droplet.ToString(in.UxOut.Body.Coins)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs[i] = ReadablePartiallySignedInput{
+			UxID:    in.UxOut.Hash().Hex(),
+			Address: in.UxOut.Body.Address.String(),
+			Coins:   coins,
+			Hours:   in.UxOut.Body.Hours,
+			SrcTxn:  in.UxOut.Body.SrcTransaction.Hex(),
+			Time:    in.UxOut.Head.Time,
+			BkSeq:   in.UxOut.Head.BkSeq,
+			Wallet:  in.Wallet,
+			Signed:  pst.Signed(i),
+		}
+
+		if in.Multisig != nil {
+			inputs[i].Multisig = NewReadableMultisigInput(in.Multisig)
+		}
+		if in.TimeLock != nil {
+			inputs[i].TimeLock = NewReadableTimeLockInput(in.TimeLock)
+		}
+	}
+
+	return &ReadablePartiallySignedTransaction{
+		Version:     pst.Version,
+		Transaction: txnHex,
+		Inputs:      inputs,
+	}, nil
+}
+
+// ToPartiallySignedTransaction converts a ReadablePartiallySignedTransaction to a PartiallySignedTransaction and validates it
+func (r ReadablePartiallySignedTransaction) ToPartiallySignedTransaction() (*PartiallySignedTransaction, error) {
+	if r.Version != PartiallySignedTransactionVersion {
+		return nil, ErrUnsupportedPartiallySignedTransactionVersion
+	}
+
+	txn, err := coin.DeserializeTransactionHex(r.Transaction)
+	if err != nil {
+		return nil, NewError(fmt.Errorf("invalid transaction: %v", err))
+	}
+
+	inputs := make([]PartiallySignedInput, len(r.Inputs))
+	for i, in := range r.Inputs {
+		addr, err := cipher.DecodeBase58Address(in.Address)
+		if err != nil {
+			return nil, NewError(fmt.Errorf("invalid address of input %d: %v", i, err))
+		}
+
+		coins, err := droplet.FromString(in.Coins)
+		if err != nil {
+			return nil, NewError(fmt.Errorf("invalid coins of input %d: %v", i, err))
+		}
+
+		srcTxn, err := cipher.SHA256FromHex(in.SrcTxn)
+		if err != nil {
+			return nil, NewError(fmt.Errorf("invalid src_tx of input %d: %v", i, err))
+		}
+
+		uxOut := coin.UxOut{
+			Head: coin.UxHead{
+				Time:  in.Time,
+				BkSeq: in.BkSeq,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: srcTxn,
+				Address:        addr,
+				Coins:          coins,
+				Hours:          in.Hours,
+			},
+		}
+
+		if uxOut.Hash().Hex() != in.UxID {
+			return nil, NewError(fmt.Errorf("uxid of input %d does not match its uxout", i))
+		}
+
+		inputs[i] = PartiallySignedInput{
+			UxOut:  uxOut,
+			Wallet: in.Wallet,
+		}
+
+		if in.Multisig != nil {
+			m, err := in.Multisig.ToMultisigInput()
+			if err != nil {
+				return nil, NewError(fmt.Errorf("invalid multisig of input %d: %v", i, err))
+			}
+			inputs[i].Multisig = m
+		}
+
+		if in.TimeLock != nil {
+			tl, err := in.TimeLock.ToTimeLockInput()
+			if err != nil {
+				return nil, NewError(fmt.Errorf("invalid time_lock of input %d: %v", i, err))
+			}
+			inputs[i].TimeLock = tl
+		}
+	}
+
+	pst := &PartiallySignedTransaction{
+		Version:     r.Version,
+		Transaction: txn,
+		Inputs:      inputs,
+	}
+
+	if err := pst.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i, in := range r.Inputs {
+		if in.Signed != pst.Signed(i) {
+			return nil, NewError(fmt.Errorf("signed status of input %d does not match the transaction signature", i))
+		}
+	}
+
+	return pst, nil
+}
@@ -0,0 +1,402 @@
+package wallet
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+)
+
+// makePartialTxn creates an unsigned transaction spending one output of each of the wallets
+func makePartialTxn(t *testing.T, wlts ...*Wallet) (*coin.Transaction, []coin.UxOut) {
+	headTime := uint64(time.Now().UTC().Unix())
+
+	var txn coin.Transaction
+	var uxOuts []coin.UxOut
+	var coins uint64
+	for _, w := range wlts {
+		ux := coin.UxOut{
+			Head: coin.UxHead{
+				Time:  headTime,
+				BkSeq: 1,
+			},
+			Body: coin.UxBody{
+				SrcTransaction: testutil.RandSHA256(t),
+				Address:        w.Entries[0].SkycoinAddress(),
+				Coins:          10e6,
+				Hours:          100,
+			},
+		}
+		uxOuts = append(uxOuts, ux)
+		coins += ux.Body.Coins
+		require.NoError(t, txn.PushInput(ux.Hash()))
+	}
+
+	require.NoError(t, txn.PushOutput(testutil.MakeAddress(), coins, 50))
+	txn.Sigs = make([]cipher.Sig, len(txn.In))
+	require.NoError(t, txn.UpdateHeader())
+
+	return &txn, uxOuts
+}
+
+func TestPartiallySignedTransaction(t *testing.T) {
+	w1, err := NewWallet("w1.wlt", Options{
+		Seed: "seed1",
+	})
+	require.NoError(t, err)
+	w2, err := NewWallet("w2.wlt", Options{
+		Seed: "seed2",
+	})
+	require.NoError(t, err)
+
+	txn, uxOuts := makePartialTxn(t, w1, w2)
+
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+	require.Equal(t, PartiallySignedTransactionVersion, pst.Version)
+	require.False(t, pst.IsFullySigned())
+	require.False(t, pst.Signed(0))
+	require.False(t, pst.Signed(1))
+
+	_, err = pst.Finalize()
+	require.Equal(t, ErrPartiallySignedTransactionNotFullySigned, err)
+
+	// Each wallet signs only the inputs it owns
+	pst1, err := w1.SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.True(t, pst1.Signed(0))
+	require.False(t, pst1.Signed(1))
+	require.Equal(t, "w1.wlt", pst1.Inputs[0].Wallet)
+	require.Equal(t, "", pst1.Inputs[1].Wallet)
+	require.False(t, pst.Signed(0))
+
+	_, err = w1.SignPartiallySignedTransaction(pst1, nil)
+	require.Equal(t, ErrNoInputsToSign, err)
+
+	_, err = w1.SignPartiallySignedTransaction(pst, []int{1})
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	pst2, err := w2.SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.False(t, pst2.Signed(0))
+	require.True(t, pst2.Signed(1))
+
+	// Combine the signatures of both parties
+	combined, err := CombinePartiallySignedTransactions(pst1, pst2)
+	require.NoError(t, err)
+	require.True(t, combined.IsFullySigned())
+	require.Equal(t, "w1.wlt", combined.Inputs[0].Wallet)
+	require.Equal(t, "w2.wlt", combined.Inputs[1].Wallet)
+
+	_, err = w2.SignPartiallySignedTransaction(combined, nil)
+	require.Equal(t, ErrPartiallySignedTransactionFullySigned, err)
+
+	signedTxn, err := combined.Finalize()
+	require.NoError(t, err)
+	require.NoError(t, signedTxn.VerifyInputSignatures(uxOuts))
+	require.Equal(t, txn.InnerHash, signedTxn.InnerHash)
+
+	// Signing sequentially gives an equivalent result
+	pst12, err := w2.SignPartiallySignedTransaction(pst1, nil)
+	require.NoError(t, err)
+	require.True(t, pst12.IsFullySigned())
+	require.Equal(t, combined.Inputs, pst12.Inputs)
+	require.Equal(t, combined.Transaction.Sigs[0], pst12.Transaction.Sigs[0])
+
+	// Partially signed transactions of different transactions can't be combined
+	otherTxn, otherUxOuts := makePartialTxn(t, w1, w2)
+	other, err := NewPartiallySignedTransaction(otherTxn, otherUxOuts)
+	require.NoError(t, err)
+	_, err = CombinePartiallySignedTransactions(pst1, other)
+	require.Equal(t, ErrPartiallySignedTransactionMismatch, err)
+}
+
+func TestNewPartiallySignedTransactionInvalid(t *testing.T) {
+	w, err := NewWallet("w.wlt", Options{
+		Seed: "seed",
+	})
+	require.NoError(t, err)
+
+	txn, uxOuts := makePartialTxn(t, w, w)
+
+	_, err = NewPartiallySignedTransaction(txn, uxOuts[:1])
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	_, err = NewPartiallySignedTransaction(txn, []coin.UxOut{uxOuts[1], uxOuts[0]})
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	// An invalid signature is rejected
+	badTxn := *txn
+	badTxn.Sigs = []cipher.Sig{testutil.RandSig(t), {}}
+	_, err = NewPartiallySignedTransaction(&badTxn, uxOuts)
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+	pst.Version = 2
+	require.Equal(t, ErrUnsupportedPartiallySignedTransactionVersion, pst.Validate())
+}
+
+func TestReadablePartiallySignedTransaction(t *testing.T) {
+	w1, err := NewWallet("w1.wlt", Options{
+		Seed: "seed1",
+	})
+	require.NoError(t, err)
+	w2, err := NewWallet("w2.wlt", Options{
+		Seed: "seed2",
+	})
+	require.NoError(t, err)
+
+	txn, uxOuts := makePartialTxn(t, w1, w2)
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+	pst, err = w1.SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+
+	rpst, err := NewReadablePartiallySignedTransaction(pst)
+	require.NoError(t, err)
+	require.Equal(t, PartiallySignedTransactionVersion, rpst.Version)
+	require.Len(t, rpst.Inputs, 2)
+	require.Equal(t, uxOuts[0].Hash().Hex(), rpst.Inputs[0].UxID)
+	require.Equal(t, "10.000000", rpst.Inputs[0].Coins)
+	require.Equal(t, "w1.wlt", rpst.Inputs[0].Wallet)
+	require.True(t, rpst.Inputs[0].Signed)
+	require.False(t, rpst.Inputs[1].Signed)
+
+	pst2, err := rpst.ToPartiallySignedTransaction()
+	require.NoError(t, err)
+	require.Equal(t, pst, pst2)
+
+	// Tampered fields are rejected
+	r := *rpst
+	r.Inputs = append([]ReadablePartiallySignedInput{}, rpst.Inputs...)
+	r.Inputs[1].Signed = true
+	_, err = r.ToPartiallySignedTransaction()
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	r.Inputs[1] = rpst.Inputs[1]
+	r.Inputs[1].Coins = "11"
+	_, err = r.ToPartiallySignedTransaction()
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	r = *rpst
+	r.Version = 0
+	_, err = r.ToPartiallySignedTransaction()
+	require.Equal(t, ErrUnsupportedPartiallySignedTransactionVersion, err)
+}
+
+func TestServiceSignPartiallySignedTransaction(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Seed:       "seed",
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: CryptoTypeSha256Xor,
+	}, nil)
+	require.NoError(t, err)
+
+	uw, err := w.Unlock([]byte("pwd"))
+	require.NoError(t, err)
+	txn, uxOuts := makePartialTxn(t, uw)
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+
+	_, err = s.SignPartiallySignedTransaction("t.wlt", nil, pst, nil)
+	require.Equal(t, ErrMissingPassword, err)
+
+	_, err = s.SignPartiallySignedTransaction("t.wlt", []byte("wrong"), pst, nil)
+	require.Equal(t, ErrInvalidPassword, err)
+
+	signedPst, err := s.SignPartiallySignedTransaction("t.wlt", []byte("pwd"), pst, nil)
+	require.NoError(t, err)
+	require.True(t, signedPst.IsFullySigned())
+	require.Equal(t, "t.wlt", signedPst.Inputs[0].Wallet)
+}
+
+func TestPartiallySignedTransactionMultisig(t *testing.T) {
+	wlts := make([]*Wallet, 3)
+	pubKeys := make([]cipher.PubKey, 3)
+	for i := range wlts {
+		w, err := NewWallet(fmt.Sprintf("w%d.wlt", i), Options{
+			Seed: fmt.Sprintf("seed%d", i),
+		})
+		require.NoError(t, err)
+		wlts[i] = w
+		pubKeys[i] = w.Entries[0].Public
+	}
+
+	// Spend an output of a 2-of-3 multisig address and an output of the first wallet
+	txn, uxOuts := makePartialTxn(t, wlts[0], wlts[0])
+	addr, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+	uxOuts[0].Body.Address = addr
+	txn.In[0] = uxOuts[0].Hash()
+	require.NoError(t, txn.UpdateHeader())
+
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+
+	// The multisig input can't be signed until its public keys are known
+	pst, err = wlts[0].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.False(t, pst.Signed(0))
+	require.True(t, pst.Signed(1))
+	_, err = wlts[0].SignPartiallySignedTransaction(pst, []int{0})
+	require.Equal(t, ErrMissingMultisigRedeemScript, err)
+
+	require.Error(t, pst.SetMultisigInput(0, 3, pubKeys))
+	require.Error(t, pst.SetMultisigInput(1, 2, pubKeys))
+	require.NoError(t, pst.SetMultisigInput(0, 2, pubKeys))
+
+	pst0, err := wlts[0].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.False(t, pst0.Signed(0))
+	require.False(t, pst0.Inputs[0].Multisig.Sigs[0].Null())
+	require.True(t, pst.Inputs[0].Multisig.Sigs[0].Null())
+
+	_, err = wlts[0].SignPartiallySignedTransaction(pst0, nil)
+	require.Equal(t, ErrNoInputsToSign, err)
+
+	_, err = pst0.Finalize()
+	require.Equal(t, ErrPartiallySignedTransactionNotFullySigned, err)
+
+	pst2, err := wlts[2].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.False(t, pst2.Signed(0))
+
+	// Two of the three signatures are enough
+	combined, err := CombinePartiallySignedTransactions(pst0, pst2)
+	require.NoError(t, err)
+	require.True(t, combined.IsFullySigned())
+	require.Equal(t, "", combined.Inputs[0].Wallet)
+
+	// The multisig signatures survive the JSON representation
+	rpst, err := NewReadablePartiallySignedTransaction(combined)
+	require.NoError(t, err)
+	require.NotNil(t, rpst.Inputs[0].Multisig)
+	require.Equal(t, "", rpst.Inputs[0].Multisig.Signatures[1])
+	require.Nil(t, rpst.Inputs[1].Multisig)
+	combined2, err := rpst.ToPartiallySignedTransaction()
+	require.NoError(t, err)
+	require.Equal(t, combined, combined2)
+
+	signedTxn, err := combined.Finalize()
+	require.NoError(t, err)
+	require.Equal(t, coin.TransactionTypeMultisig, signedTxn.Type)
+	require.Equal(t, txn.InnerHash, signedTxn.InnerHash)
+	require.NoError(t, signedTxn.Verify())
+	require.NoError(t, signedTxn.VerifyInputSignatures(uxOuts))
+
+	// An invalid multisig signature is rejected
+	bad := *combined
+	bad.Inputs = combined.copyInputs()
+	bad.Inputs[0].Multisig.Sigs[1] = bad.Inputs[0].Multisig.Sigs[0]
+	require.Error(t, bad.Validate())
+}
+
+func TestPartiallySignedTransactionTimeLock(t *testing.T) {
+	wlts := make([]*Wallet, 2)
+	for i := range wlts {
+		w, err := NewWallet(fmt.Sprintf("w%d.wlt", i), Options{
+			Seed: fmt.Sprintf("seed%d", i),
+		})
+		require.NoError(t, err)
+		wlts[i] = w
+	}
+
+	// Spend an output of a time-locked address owned by the first wallet,
+	// and an output of a time-locked address owned by a 2-of-2 multisig address of both wallets
+	owner := wlts[0].Entries[0].SkycoinAddress()
+	pubKeys := []cipher.PubKey{wlts[0].Entries[0].Public, wlts[1].Entries[0].Public}
+	msOwner, err := cipher.MultisigAddressFromPubKeys(2, pubKeys)
+	require.NoError(t, err)
+
+	txn, uxOuts := makePartialTxn(t, wlts[0], wlts[1])
+	uxOuts[0].Body.Address, err = cipher.TimeLockedAddress(owner, cipher.TimeLockHeight, 100)
+	require.NoError(t, err)
+	uxOuts[1].Body.Address, err = cipher.TimeLockedAddress(msOwner, cipher.TimeLockTime, 1e9)
+	require.NoError(t, err)
+	for i := range uxOuts {
+		txn.In[i] = uxOuts[i].Hash()
+	}
+	require.NoError(t, txn.UpdateHeader())
+
+	pst, err := NewPartiallySignedTransaction(txn, uxOuts)
+	require.NoError(t, err)
+
+	// The time-locked inputs can't be signed until their time-lock scripts are known
+	_, err = wlts[0].SignPartiallySignedTransaction(pst, nil)
+	require.Equal(t, ErrNoInputsToSign, err)
+	_, err = wlts[0].SignPartiallySignedTransaction(pst, []int{0})
+	require.Equal(t, ErrMissingTimeLockScript, err)
+
+	require.Error(t, pst.SetTimeLockInput(0, cipher.TimeLockHeight, 101, owner))
+	require.Error(t, pst.SetTimeLockInput(0, cipher.TimeLockTime, 100, owner))
+	require.NoError(t, pst.SetTimeLockInput(0, cipher.TimeLockHeight, 100, owner))
+	require.NoError(t, pst.SetTimeLockInput(1, cipher.TimeLockTime, 1e9, msOwner))
+
+	// The multisig owner's public keys are needed to sign the second input
+	require.Error(t, pst.SetMultisigInput(1, 1, pubKeys))
+	require.NoError(t, pst.SetMultisigInput(1, 2, pubKeys))
+
+	pst0, err := wlts[0].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.True(t, pst0.Signed(0))
+	require.False(t, pst0.Signed(1))
+	require.True(t, pst0.Transaction.Sigs[0].Null())
+	require.True(t, pst.Inputs[0].TimeLock.Sig.Null())
+
+	pst1, err := wlts[1].SignPartiallySignedTransaction(pst, nil)
+	require.NoError(t, err)
+	require.False(t, pst1.Signed(0))
+
+	combined, err := CombinePartiallySignedTransactions(pst0, pst1)
+	require.NoError(t, err)
+	require.True(t, combined.IsFullySigned())
+
+	// The time-lock scripts survive the JSON representation
+	rpst, err := NewReadablePartiallySignedTransaction(combined)
+	require.NoError(t, err)
+	require.Equal(t, &ReadableTimeLockInput{
+		Kind:      TimeLockKindHeight,
+		Value:     100,
+		Owner:     owner.String(),
+		Signature: combined.Inputs[0].TimeLock.Sig.Hex(),
+	}, rpst.Inputs[0].TimeLock)
+	require.Equal(t, TimeLockKindTime, rpst.Inputs[1].TimeLock.Kind)
+	require.Equal(t, "", rpst.Inputs[1].TimeLock.Signature)
+	combined2, err := rpst.ToPartiallySignedTransaction()
+	require.NoError(t, err)
+	require.Equal(t, combined, combined2)
+
+	signedTxn, err := combined.Finalize()
+	require.NoError(t, err)
+	require.Equal(t, coin.TransactionTypeMultisig, signedTxn.Type)
+	require.NoError(t, signedTxn.Verify())
+	require.NoError(t, signedTxn.VerifyInputSignatures(uxOuts))
+	require.Equal(t, coin.ErrTimeLocked, signedTxn.VerifyTimeLocks(uxOuts, 100, 1e9-1))
+	require.NoError(t, signedTxn.VerifyTimeLocks(uxOuts, 100, 1e9))
+
+	// A time-lock script that does not match the address is rejected
+	bad := *combined
+	bad.Inputs = combined.copyInputs()
+	bad.Inputs[0].TimeLock.Value = 101
+	require.Error(t, bad.Validate())
+}
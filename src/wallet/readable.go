@@ -10,14 +10,32 @@ import (
 
 // ReadableEntry wallet entry with json tags
 type ReadableEntry struct {
-	Address string `json:"address"`
-	Public  string `json:"public_key"`
-	Secret  string `json:"secret_key"`
+	Address     string  `json:"address"`
+	Public      string  `json:"public_key"`
+	Secret      string  `json:"secret_key"`
+	ChildNumber *uint32 `json:"child_number,omitempty"` // bip44 and xpub wallets only
+	Change      *uint32 `json:"change,omitempty"`       // bip44 and xpub wallets, and change addresses of deterministic wallets
+	Label       string  `json:"label,omitempty"`
 }
 
 // NewReadableEntry creates readable wallet entry
-func NewReadableEntry(coinType CoinType, w Entry) ReadableEntry {
-	re := ReadableEntry{}
+func NewReadableEntry(coinType CoinType, walletType string, w Entry) ReadableEntry {
+	re := ReadableEntry{
+		Label: w.Label,
+	}
+	switch walletType {
+	case WalletTypeBip44, WalletTypeXPub:
+		childNumber := w.ChildNumber
+		change := w.Change
+		re.ChildNumber = &childNumber
+		re.Change = &change
+	default:
+		if w.Change != 0 {
+			change := w.Change
+			re.Change = &change
+		}
+	}
+
 	if !w.Address.Null() {
 		re.Address = w.Address.String()
 	}
@@ -83,9 +101,13 @@ func newEntryFromReadable(coinType CoinType, w *ReadableEntry) (*Entry, error) {
 		return nil, err
 	}
 
-	p, err := cipher.PubKeyFromHex(w.Public)
-	if err != nil {
-		return nil, err
+	// Entries of address list wallets have no public key
+	var p cipher.PubKey
+	if w.Public != "" {
+		p, err = cipher.PubKeyFromHex(w.Public)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Decodes the secret hex string if any
@@ -104,24 +126,38 @@ func newEntryFromReadable(coinType CoinType, w *ReadableEntry) (*Entry, error) {
 		}
 	}
 
-	return &Entry{
+	e := &Entry{
 		Address: a,
 		Public:  p,
 		Secret:  secret,
-	}, nil
+		Label:   w.Label,
+	}
+
+	if w.ChildNumber != nil {
+		e.ChildNumber = *w.ChildNumber
+	}
+	if w.Change != nil {
+		e.Change = *w.Change
+	}
+
+	return e, nil
 }
 
 // ReadableWallet used for [de]serialization of a Wallet
 type ReadableWallet struct {
 	Meta    map[string]string `json:"meta"`
 	Entries ReadableEntries   `json:"entries"`
+	// FrozenOutputs are omitted if empty, so that wallets without frozen outputs are saved as before
+	FrozenOutputs []ReadableFrozenOutput `json:"frozen_outputs,omitempty"`
+	// AddressBook is omitted if empty, so that wallets without an address book are saved as before
+	AddressBook []ReadableAddressBookEntry `json:"address_book,omitempty"`
 }
 
 // NewReadableWallet creates readable wallet
 func NewReadableWallet(w *Wallet) *ReadableWallet {
 	readable := make(ReadableEntries, len(w.Entries))
 	for i, e := range w.Entries {
-		readable[i] = NewReadableEntry(w.coin(), e)
+		readable[i] = NewReadableEntry(w.coin(), w.Type(), e)
 	}
 
 	meta := make(map[string]string, len(w.Meta))
@@ -130,8 +166,10 @@ func NewReadableWallet(w *Wallet) *ReadableWallet {
 	}
 
 	return &ReadableWallet{
-		Meta:    meta,
-		Entries: readable,
+		Meta:          meta,
+		Entries:       readable,
+		FrozenOutputs: NewReadableFrozenOutputs(w.FrozenOutputs),
+		AddressBook:   NewReadableAddressBook(w.AddressBook),
 	}
 }
 
@@ -161,6 +199,16 @@ func (rw *ReadableWallet) ToWallet() (*Wallet, error) {
 
 	w.Entries = ets
 
+	w.FrozenOutputs, err = toFrozenOutputs(rw.FrozenOutputs)
+	if err != nil {
+		return nil, err
+	}
+
+	w.AddressBook, err = toAddressBook(w.coin(), rw.AddressBook)
+	if err != nil {
+		return nil, err
+	}
+
 	return w, nil
 }
 
@@ -169,6 +217,11 @@ func (rw *ReadableWallet) Save(filename string) error {
 	return file.SaveJSON(filename, rw, 0600)
 }
 
+// SaveAtomic replaces filename atomically, keeping the previous file as a backup with the ".bak" extension
+func (rw *ReadableWallet) SaveAtomic(filename string) error {
+	return file.SaveJSONAtomic(filename, rw, 0600, filename+".bak")
+}
+
 // Load loads from filename
 func (rw *ReadableWallet) Load(filename string) error {
 	return file.LoadJSON(filename, rw)
@@ -189,6 +242,7 @@ func (rw *ReadableWallet) filename() string {
 func (rw *ReadableWallet) Erase() {
 	delete(rw.Meta, metaSeed)
 	delete(rw.Meta, metaLastSeed)
+	delete(rw.Meta, metaSeedPassphrase)
 	delete(rw.Meta, metaSecrets)
 	for i := range rw.Entries {
 		rw.Entries[i].Secret = ""
@@ -0,0 +1,211 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+	"github.com/skycoin/skycoin/src/cipher/shamir"
+)
+
+// A seed share is encoded as a list of bip39 words, 11 bits per word, of the bytes:
+//
+//	version (1) | id (2) | threshold (1) | x (1) | length (2) | y (length) | checksum (4)
+//
+// The id is random and shared by the shares of the same split, to detect mixed shares.
+// The checksum is the first 4 bytes of the sha256 of the preceding bytes.
+// The last word is padded with zero bits.
+const (
+	seedShareVersion      = 0
+	seedShareHeaderLen    = 7
+	seedShareChecksumLen  = 4
+	seedShareBitsPerWord  = 11
+	seedShareMaxSeedLen   = 1<<16 - 1
+	seedShareWordBitsMask = 1<<seedShareBitsPerWord - 1
+)
+
+var (
+	// ErrInvalidSeedShare is returned if a seed share is not a valid share
+	ErrInvalidSeedShare = NewError(errors.New("invalid seed share"))
+	// ErrSeedSharesMismatch is returned when trying to combine shares of different seed splits
+	ErrSeedSharesMismatch = NewError(errors.New("seed shares are not shares of the same seed"))
+	// ErrNotEnoughSeedShares is returned when trying to combine fewer seed shares than their threshold
+	ErrNotEnoughSeedShares = NewError(errors.New("not enough seed shares"))
+	// ErrDuplicateSeedShare is returned if a seed share is given more than once
+	ErrDuplicateSeedShare = NewError(errors.New("duplicate seed share"))
+)
+
+// seedShare is a decoded seed share
+type seedShare struct {
+	id        uint16
+	threshold byte
+	share     shamir.Share
+}
+
+// SplitSeed splits a wallet seed with Shamir's secret sharing into n shares, any threshold of which recover the seed.
+// The shares are encoded as lists of bip39 words with a checksum.
+func SplitSeed(seed string, n, threshold int) ([]string, error) {
+	if seed == "" {
+		return nil, ErrMissingSeed
+	}
+
+	if len(seed) > seedShareMaxSeedLen {
+		return nil, NewError(fmt.Errorf("seed is longer than %d bytes", seedShareMaxSeedLen))
+	}
+
+	shares, err := shamir.Split([]byte(seed), n, threshold)
+	if err != nil {
+		return nil, NewError(err)
+	}
+
+	id := binary.BigEndian.Uint16(cipher.RandByte(2))
+
+	words := make([]string, len(shares))
+	for i, s := range shares {
+		words[i] = encodeSeedShare(seedShare{
+			id:        id,
+			threshold: byte(threshold),
+			share:     s,
+		})
+	}
+
+	return words, nil
+}
+
+// CombineSeedShares recovers a wallet seed from the shares created by SplitSeed.
+// Returns an error if a share is invalid, the shares are of different seeds or fewer than their threshold.
+func CombineSeedShares(shares []string) (string, error) {
+	if len(shares) == 0 {
+		return "", ErrNotEnoughSeedShares
+	}
+
+	ss := make([]shamir.Share, len(shares))
+	var first seedShare
+	for i, s := range shares {
+		share, err := decodeSeedShare(s)
+		if err != nil {
+			return "", NewError(fmt.Errorf("seed share %d: %v", i+1, err))
+		}
+
+		if i == 0 {
+			first = share
+		} else if share.id != first.id || share.threshold != first.threshold || len(share.share.Y) != len(first.share.Y) {
+			return "", ErrSeedSharesMismatch
+		}
+
+		for _, t := range ss[:i] {
+			if t.X == share.share.X {
+				return "", ErrDuplicateSeedShare
+			}
+		}
+
+		ss[i] = share.share
+	}
+
+	if len(ss) < int(first.threshold) {
+		return "", NewError(fmt.Errorf("%v, %d of %d required", ErrNotEnoughSeedShares, len(ss), first.threshold))
+	}
+
+	seed, err := shamir.Combine(ss)
+	if err != nil {
+		return "", NewError(err)
+	}
+
+	return string(seed), nil
+}
+
+func encodeSeedShare(s seedShare) string {
+	b := make([]byte, seedShareHeaderLen, seedShareHeaderLen+len(s.share.Y)+seedShareChecksumLen)
+	b[0] = seedShareVersion
+	binary.BigEndian.PutUint16(b[1:3], s.id)
+	b[3] = s.threshold
+	b[4] = s.share.X
+	binary.BigEndian.PutUint16(b[5:7], uint16(len(s.share.Y)))
+	b = append(b, s.share.Y...)
+	h := cipher.SumSHA256(b)
+	b = append(b, h[:seedShareChecksumLen]...)
+
+	words := make([]string, 0, (len(b)*8+seedShareBitsPerWord-1)/seedShareBitsPerWord)
+	var acc uint32
+	var nBits uint
+	for _, c := range b {
+		acc = acc<<8 | uint32(c)
+		nBits += 8
+		for nBits >= seedShareBitsPerWord {
+			nBits -= seedShareBitsPerWord
+			words = append(words, bip39.WordList[(acc>>nBits)&seedShareWordBitsMask])
+		}
+		acc &= 1<<nBits - 1
+	}
+
+	if nBits > 0 {
+		words = append(words, bip39.WordList[(acc<<(seedShareBitsPerWord-nBits))&seedShareWordBitsMask])
+	}
+
+	return strings.Join(words, " ")
+}
+
+func decodeSeedShare(s string) (seedShare, error) {
+	words := strings.Fields(s)
+
+	b := make([]byte, 0, len(words)*seedShareBitsPerWord/8)
+	var acc uint32
+	var nBits uint
+	for _, w := range words {
+		i, ok := bip39.ReverseWordMap[strings.ToLower(w)]
+		if !ok {
+			return seedShare{}, fmt.Errorf("invalid word %q", w)
+		}
+
+		acc = acc<<seedShareBitsPerWord | uint32(i)
+		nBits += seedShareBitsPerWord
+		for nBits >= 8 {
+			nBits -= 8
+			b = append(b, byte(acc>>nBits))
+		}
+		acc &= 1<<nBits - 1
+	}
+
+	if acc != 0 {
+		return seedShare{}, ErrInvalidSeedShare
+	}
+
+	if len(b) < seedShareHeaderLen+seedShareChecksumLen {
+		return seedShare{}, ErrInvalidSeedShare
+	}
+
+	if b[0] != seedShareVersion {
+		return seedShare{}, fmt.Errorf("unsupported seed share version %d", b[0])
+	}
+
+	// The padding of the last word can add a zero byte
+	n := seedShareHeaderLen + int(binary.BigEndian.Uint16(b[5:7]))
+	switch len(b) {
+	case n + seedShareChecksumLen:
+	case n + seedShareChecksumLen + 1:
+		if b[len(b)-1] != 0 {
+			return seedShare{}, ErrInvalidSeedShare
+		}
+		b = b[:len(b)-1]
+	default:
+		return seedShare{}, ErrInvalidSeedShare
+	}
+
+	h := cipher.SumSHA256(b[:n])
+	if !bytes.Equal(h[:seedShareChecksumLen], b[n:]) {
+		return seedShare{}, errors.New("checksum incorrect")
+	}
+
+	return seedShare{
+		id:        binary.BigEndian.Uint16(b[1:3]),
+		threshold: b[3],
+		share: shamir.Share{
+			X: b[4],
+			Y: b[seedShareHeaderLen:n],
+		},
+	}, nil
+}
@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+)
+
+func TestSplitCombineSeed(t *testing.T) {
+	seeds := []string{
+		testBip44Seed,
+		"8f3d4a3b2e5f1c6d7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f",
+	}
+	// Seeds of every length up to 32 bytes cover every padding of the last word
+	for i := 1; i <= 32; i++ {
+		seeds = append(seeds, strings.Repeat("s", i))
+	}
+
+	for _, seed := range seeds {
+		shares, err := SplitSeed(seed, 3, 2)
+		require.NoError(t, err)
+		require.Len(t, shares, 3)
+
+		for _, s := range shares {
+			for _, w := range strings.Split(s, " ") {
+				_, ok := bip39.ReverseWordMap[w]
+				require.True(t, ok, w)
+			}
+		}
+
+		for _, ss := range [][]string{
+			{shares[0], shares[1]},
+			{shares[2], shares[0]},
+			{shares[1], shares[2]},
+			shares,
+		} {
+			s, err := CombineSeedShares(ss)
+			require.NoError(t, err)
+			require.Equal(t, seed, s)
+		}
+	}
+
+	// Extra whitespace and uppercase words are accepted
+	shares, err := SplitSeed(testBip44Seed, 5, 3)
+	require.NoError(t, err)
+	seed, err := CombineSeedShares([]string{
+		"  " + strings.ToUpper(shares[4]) + "\n",
+		strings.Replace(shares[0], " ", "  ", -1),
+		shares[2],
+	})
+	require.NoError(t, err)
+	require.Equal(t, testBip44Seed, seed)
+}
+
+func TestSplitSeedInvalid(t *testing.T) {
+	_, err := SplitSeed("", 3, 2)
+	require.Equal(t, ErrMissingSeed, err)
+
+	_, err = SplitSeed("seed", 3, 1)
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	_, err = SplitSeed("seed", 2, 3)
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	_, err = SplitSeed("seed", 256, 2)
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+}
+
+func TestCombineSeedSharesInvalid(t *testing.T) {
+	shares, err := SplitSeed(testBip44Seed, 5, 3)
+	require.NoError(t, err)
+
+	otherShares, err := SplitSeed(testBip44Seed, 5, 3)
+	require.NoError(t, err)
+
+	replaceWord := func(share string, i int) string {
+		words := strings.Split(share, " ")
+		if words[i] == "zoo" {
+			words[i] = "abandon"
+		} else {
+			words[i] = "zoo"
+		}
+		return strings.Join(words, " ")
+	}
+
+	tt := []struct {
+		name   string
+		shares []string
+		err    error
+	}{
+		{
+			name: "no shares",
+			err:  ErrNotEnoughSeedShares,
+		},
+		{
+			name:   "fewer shares than the threshold",
+			shares: shares[:2],
+			err:    NewError(errors.New("not enough seed shares, 2 of 3 required")),
+		},
+		{
+			name:   "duplicate share",
+			shares: []string{shares[0], shares[1], shares[0]},
+			err:    ErrDuplicateSeedShare,
+		},
+		{
+			name:   "shares of different splits",
+			shares: []string{shares[0], shares[1], otherShares[2]},
+			err:    ErrSeedSharesMismatch,
+		},
+		{
+			name:   "invalid word",
+			shares: []string{shares[0], shares[1], shares[2] + " foo"},
+			err:    NewError(errors.New(`seed share 3: invalid word "foo"`)),
+		},
+		{
+			name:   "modified word",
+			shares: []string{shares[0], replaceWord(shares[1], 10), shares[2]},
+			err:    NewError(errors.New("seed share 2: checksum incorrect")),
+		},
+		{
+			name:   "missing words",
+			shares: []string{shares[0], shares[1], strings.Join(strings.Split(shares[2], " ")[:20], " ")},
+			err:    NewError(errors.New("seed share 3: invalid seed share")),
+		},
+		{
+			name:   "empty share",
+			shares: []string{shares[0], ""},
+			err:    NewError(errors.New("seed share 2: invalid seed share")),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CombineSeedShares(tc.shares)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestServiceSeedShares(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Seed:       "seed",
+		GenerateN:  3,
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: CryptoTypeSha256Xor,
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = s.GetWalletSeedShares("t.wlt", []byte("pwd"), 3, 2)
+	require.Equal(t, ErrSeedAPIDisabled, err)
+
+	s.enableSeedAPI = true
+
+	_, err = s.GetWalletSeedShares("t.wlt", []byte("wrong"), 3, 2)
+	require.Equal(t, ErrInvalidPassword, err)
+
+	shares, err := s.GetWalletSeedShares("t.wlt", []byte("pwd"), 3, 2)
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+
+	_, err = s.RecoverWalletFromSeedShares("t.wlt", shares[:1], "", nil)
+	require.Equal(t, NewError(errors.New("not enough seed shares, 1 of 2 required")), err)
+
+	otherShares, err := SplitSeed("other seed", 3, 2)
+	require.NoError(t, err)
+	_, err = s.RecoverWalletFromSeedShares("t.wlt", otherShares[1:], "", nil)
+	require.Equal(t, ErrWalletRecoverSeedWrong, err)
+
+	w2, err := s.RecoverWalletFromSeedShares("t.wlt", []string{shares[2], shares[0]}, "", []byte("pwd2"))
+	require.NoError(t, err)
+	require.True(t, w2.IsEncrypted())
+	require.Equal(t, w.Entries, w2.Entries)
+
+	seed, err := s.GetWalletSeed("t.wlt", []byte("pwd2"))
+	require.NoError(t, err)
+	require.Equal(t, "seed", seed)
+}
@@ -3,9 +3,11 @@ package wallet
 import (
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
 )
 
 // BalanceGetter interface for getting the balance of given addresses
@@ -201,6 +203,35 @@ func (serv *Service) DecryptWallet(wltID string, password []byte) (*Wallet, erro
 	return unlockWlt, nil
 }
 
+// ChangePassword re-encrypts the secrets of an encrypted wallet with a new password and,
+// if cryptoType is not empty, a new crypto type. The wallet is never decrypted on disk:
+// the wallet file is replaced atomically, and the previous file is kept as a backup.
+func (serv *Service) ChangePassword(wltID string, password, newPassword []byte, cryptoType CryptoType) (*Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.enableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	w, err := serv.getWallet(wltID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.ChangePassword(password, newPassword, cryptoType); err != nil {
+		return nil, err
+	}
+
+	// Save to disk first
+	if err := w.SaveAtomic(serv.walletDirectory); err != nil {
+		return nil, err
+	}
+
+	// Sets the re-encrypted wallet
+	serv.wallets.set(w)
+	return w, nil
+}
+
 // NewAddresses generate address entries in given wallet,
 // return nil if wallet does not exist.
 // Set password as nil if the wallet is not encrypted, otherwise the password must be provided.
@@ -322,6 +353,85 @@ func (serv *Service) UpdateWalletLabel(wltID, label string) error {
 	return nil
 }
 
+// UpdateWalletChangeAddressPolicy updates the change address policy of the wallet
+func (serv *Service) UpdateWalletChangeAddressPolicy(wltID, policy string) (*Wallet, error) {
+	var wlt *Wallet
+	if err := serv.Update(wltID, func(w *Wallet) error {
+		if err := w.SetChangeAddressPolicy(policy); err != nil {
+			return err
+		}
+		wlt = w.clone()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return wlt, nil
+}
+
+// UpdateWalletAddressLabel sets the label of an address of the wallet. An empty label removes the label.
+func (serv *Service) UpdateWalletAddressLabel(wltID, addr, label string) (*Wallet, error) {
+	var wlt *Wallet
+	if err := serv.Update(wltID, func(w *Wallet) error {
+		a, err := w.DecodeAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		if err := w.SetAddressLabel(a, label); err != nil {
+			return err
+		}
+		wlt = w.clone()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return wlt, nil
+}
+
+// AddWalletAddressBookEntry adds an entry to the address book of the wallet,
+// or replaces the entry with the same name
+func (serv *Service) AddWalletAddressBookEntry(wltID, name, addr, note string) (*Wallet, error) {
+	var wlt *Wallet
+	if err := serv.Update(wltID, func(w *Wallet) error {
+		a, err := w.DecodeAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		if err := w.SetAddressBookEntry(AddressBookEntry{
+			Name:    name,
+			Address: a,
+			Note:    note,
+		}); err != nil {
+			return err
+		}
+		wlt = w.clone()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return wlt, nil
+}
+
+// RemoveWalletAddressBookEntry removes an entry from the address book of the wallet
+func (serv *Service) RemoveWalletAddressBookEntry(wltID, name string) (*Wallet, error) {
+	var wlt *Wallet
+	if err := serv.Update(wltID, func(w *Wallet) error {
+		if err := w.RemoveAddressBookEntry(name); err != nil {
+			return err
+		}
+		wlt = w.clone()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return wlt, nil
+}
+
 // Remove removes wallet of given wallet id from the service
 func (serv *Service) Remove(wltID string) error {
 	serv.Lock()
@@ -367,6 +477,10 @@ func (serv *Service) GetWalletSeed(wltID string, password []byte) (string, error
 		return "", err
 	}
 
+	if w.IsWatchOnly() {
+		return "", ErrWatchOnlyWallet
+	}
+
 	if !w.IsEncrypted() {
 		return "", ErrWalletNotEncrypted
 	}
@@ -382,6 +496,18 @@ func (serv *Service) GetWalletSeed(wltID string, password []byte) (string, error
 	return seed, nil
 }
 
+// GetWalletSeedShares splits the seed of an encrypted wallet into n shares with Shamir's secret sharing,
+// any threshold of which recover the wallet with RecoverWalletFromSeedShares.
+// The bip39 passphrase of a bip44 wallet seed is not part of the shares.
+func (serv *Service) GetWalletSeedShares(wltID string, password []byte, n, threshold int) ([]string, error) {
+	seed, err := serv.GetWalletSeed(wltID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return SplitSeed(seed, n, threshold)
+}
+
 // UpdateSecrets opens a wallet for modification of secret data and saves it safely
 func (serv *Service) UpdateSecrets(wltID string, password []byte, f func(*Wallet) error) error {
 	serv.Lock()
@@ -482,9 +608,35 @@ func (serv *Service) View(wltID string, f func(*Wallet) error) error {
 	return f(w)
 }
 
-// RecoverWallet recovers an encrypted wallet from seed.
+// SignPartiallySignedTransaction signs the inputs of a partially signed transaction owned by a wallet.
+// If signIndexes is empty, all unsigned inputs owned by the wallet will be signed.
+func (serv *Service) SignPartiallySignedTransaction(wltID string, password []byte, pst *PartiallySignedTransaction, signIndexes []int) (*PartiallySignedTransaction, error) {
+	var signedPst *PartiallySignedTransaction
+	if err := serv.ViewSecrets(wltID, password, func(w *Wallet) error {
+		var err error
+		signedPst, err = w.SignPartiallySignedTransaction(pst, signIndexes)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return signedPst, nil
+}
+
+// RecoverWalletFromSeedShares recovers an encrypted wallet from the shares of its seed created by GetWalletSeedShares.
+// The recovered wallet will be encrypted with the new password, if provided.
+func (serv *Service) RecoverWalletFromSeedShares(wltName string, shares []string, seedPassphrase string, password []byte) (*Wallet, error) {
+	seed, err := CombineSeedShares(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	return serv.RecoverWallet(wltName, seed, seedPassphrase, password)
+}
+
+// RecoverWallet recovers an encrypted wallet from seed and, for bip44 wallets, its optional bip39 passphrase.
 // The recovered wallet will be encrypted with the new password, if provided.
-func (serv *Service) RecoverWallet(wltName, seed string, password []byte) (*Wallet, error) {
+func (serv *Service) RecoverWallet(wltName, seed, seedPassphrase string, password []byte) (*Wallet, error) {
 	serv.Lock()
 	defer serv.Unlock()
 	if !serv.enableWalletAPI {
@@ -500,35 +652,64 @@ func (serv *Service) RecoverWallet(wltName, seed string, password []byte) (*Wall
 		return nil, ErrWalletNotEncrypted
 	}
 
-	if w.Type() != WalletTypeDeterministic {
+	switch w.Type() {
+	case WalletTypeDeterministic, WalletTypeBip44:
+	default:
 		return nil, ErrWalletNotDeterministic
 	}
 
 	// Generate the first address from the seed
-	var pk cipher.PubKey
-	pk, _, err = cipher.GenerateDeterministicKeyPair([]byte(seed))
+	w2, err := NewWallet(wltName, Options{
+		Coin:                w.coin(),
+		Type:                w.Type(),
+		Label:               w.Label(),
+		Seed:                seed,
+		SeedPassphrase:      seedPassphrase,
+		ChangeAddressPolicy: w.Meta[metaChangeAddressPolicy],
+	})
 	if err != nil {
+		if w.Type() == WalletTypeBip44 && err == ErrInvalidBip44Seed {
+			return nil, ErrWalletRecoverSeedWrong
+		}
 		return nil, err
 	}
-	addr := w.addressConstructor()(pk)
 
 	// Compare to the wallet's first address
-	if addr != w.Entries[0].Address {
+	if w2.Entries[0].Address != w.Entries[0].Address {
 		return nil, ErrWalletRecoverSeedWrong
 	}
 
-	// Create a new wallet with the same number of addresses, encrypting if needed
-	w2, err := NewWallet(wltName, Options{
-		Coin:       w.coin(),
-		Label:      w.Label(),
-		Seed:       seed,
-		Encrypt:    len(password) != 0,
-		Password:   password,
-		CryptoType: w.cryptoType(),
-		GenerateN:  uint64(len(w.Entries)),
-	})
-	if err != nil {
-		return nil, err
+	// Regenerate the same number of addresses as the original wallet
+	switch w.Type() {
+	case WalletTypeBip44:
+		nExternal, nChange := w.bip44EntriesCount()
+		if _, err := w2.generateBip44Addresses(bip44.ExternalChainIndex, nExternal-1); err != nil {
+			return nil, err
+		}
+		if _, err := w2.generateBip44Addresses(bip44.ChangeChainIndex, nChange); err != nil {
+			return nil, err
+		}
+	default:
+		if _, err := w2.GenerateAddresses(uint64(len(w.Entries) - 1)); err != nil {
+			return nil, err
+		}
+
+		// Restore the change address flags of the regenerated entries
+		for i, e := range w.Entries {
+			w2.Entries[i].Change = e.Change
+		}
+	}
+
+	// Preserve the frozen outputs, address labels and address book of the old wallet
+	w2.FrozenOutputs = w.FrozenOutputs
+	w2.copyAddressLabels(w)
+	w2.AddressBook = w.AddressBook
+
+	// Encrypt the wallet if a password was provided
+	if len(password) != 0 {
+		if err := w2.Lock(password, w.cryptoType()); err != nil {
+			return nil, err
+		}
 	}
 
 	// Preserve the timestamp of the old wallet
@@ -543,3 +724,70 @@ func (serv *Service) RecoverWallet(wltName, seed string, password []byte) (*Wall
 
 	return w2.clone(), nil
 }
+
+// ExportWallets packages the wallets of the given wallet ids, or all wallets if no ids are given,
+// into a single backup encrypted with password. The seed API must be enabled to export
+// unencrypted wallets, because their seeds can be read from the backup with its password.
+func (serv *Service) ExportWallets(wltIDs []string, password []byte) ([]byte, error) {
+	serv.RLock()
+	defer serv.RUnlock()
+	if !serv.enableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	if len(wltIDs) == 0 {
+		for wltID := range serv.wallets {
+			wltIDs = append(wltIDs, wltID)
+		}
+		sort.Strings(wltIDs)
+	}
+
+	wlts := make([]*Wallet, len(wltIDs))
+	for i, wltID := range wltIDs {
+		w, err := serv.getWallet(wltID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !w.IsEncrypted() && !w.IsWatchOnly() && !serv.enableSeedAPI {
+			return nil, ErrSeedAPIDisabled
+		}
+
+		wlts[i] = w
+	}
+
+	return EncryptBackup(wlts, password)
+}
+
+// ImportWallets decrypts a backup created by ExportWallets and adds its wallets.
+// The wallets whose filenames are used by loaded wallets are renamed.
+// No wallet is added if a wallet of the backup is a duplicate of a loaded wallet.
+func (serv *Service) ImportWallets(backup, password []byte) ([]*Wallet, error) {
+	serv.Lock()
+	defer serv.Unlock()
+	if !serv.enableWalletAPI {
+		return nil, ErrWalletAPIDisabled
+	}
+
+	ws, err := DecryptBackup(backup, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := serv.wallets.prepareImport(ws); err != nil {
+		return nil, err
+	}
+
+	if err := saveImportedWallets(serv.walletDirectory, ws); err != nil {
+		return nil, err
+	}
+
+	imported := make([]*Wallet, len(ws))
+	for i, w := range ws {
+		serv.wallets.set(w)
+		serv.firstAddrIDMap[w.Entries[0].Address.String()] = w.Filename()
+		imported[i] = w.clone()
+	}
+
+	return imported, nil
+}
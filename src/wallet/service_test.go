@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -936,6 +937,190 @@ func TestServiceDecryptWallet(t *testing.T) {
 	}
 }
 
+func TestServiceChangePassword(t *testing.T) {
+	tt := []struct {
+		name             string
+		opts             Options
+		wltName          string
+		password         []byte
+		newPassword      []byte
+		cryptoType       CryptoType
+		disableWalletAPI bool
+		err              error
+	}{
+		{
+			name: "ok",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+		},
+		{
+			name: "ok new crypto type",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			cryptoType:  CryptoTypeScryptChacha20poly1305Insecure,
+		},
+		{
+			name: "ok migrate to argon2id",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			cryptoType:  CryptoTypeArgon2idXChacha20poly1305Insecure,
+		},
+		{
+			name: "wallet not exist",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "t.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			err:         ErrWalletNotExist,
+		},
+		{
+			name: "wallet not encrypted",
+			opts: Options{
+				Seed: "seed",
+			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			err:         ErrWalletNotEncrypted,
+		},
+		{
+			name: "invalid password",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "test.wlt",
+			password:    []byte("wrong password"),
+			newPassword: []byte("new pwd"),
+			err:         ErrInvalidPassword,
+		},
+		{
+			name: "missing new password",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:  "test.wlt",
+			password: []byte("pwd"),
+			err:      ErrMissingPassword,
+		},
+		{
+			name: "invalid crypto type",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:     "test.wlt",
+			password:    []byte("pwd"),
+			newPassword: []byte("new pwd"),
+			cryptoType:  CryptoType("foo"),
+			err:         errors.New("can not find crypto foo in crypto table"),
+		},
+		{
+			name: "wallet api disabled",
+			opts: Options{
+				Seed:     "seed",
+				Encrypt:  true,
+				Password: []byte("pwd"),
+			},
+			wltName:          "test.wlt",
+			password:         []byte("pwd"),
+			newPassword:      []byte("new pwd"),
+			disableWalletAPI: true,
+			err:              ErrWalletAPIDisabled,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := prepareWltDir()
+			s, err := NewService(Config{
+				WalletDir:       dir,
+				CryptoType:      CryptoTypeSha256Xor,
+				EnableWalletAPI: !tc.disableWalletAPI,
+			})
+			require.NoError(t, err)
+
+			if tc.disableWalletAPI {
+				_, err = s.ChangePassword(tc.wltName, tc.password, tc.newPassword, tc.cryptoType)
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			w, err := s.CreateWallet("test.wlt", tc.opts, nil)
+			require.NoError(t, err)
+
+			fn := filepath.Join(dir, "test.wlt")
+			data, err := ioutil.ReadFile(fn)
+			require.NoError(t, err)
+
+			w1, err := s.ChangePassword(tc.wltName, tc.password, tc.newPassword, tc.cryptoType)
+			require.Equal(t, tc.err, err)
+			if err != nil {
+				// The wallet is unchanged
+				w2, err := s.getWallet("test.wlt")
+				require.NoError(t, err)
+				require.Equal(t, w, w2)
+				testutil.RequireFileNotExists(t, fn+".bak")
+				return
+			}
+
+			cryptoType := tc.cryptoType
+			if cryptoType == "" {
+				cryptoType = CryptoTypeSha256Xor
+			}
+			require.True(t, w1.IsEncrypted())
+			require.Equal(t, cryptoType, w1.cryptoType())
+			require.Empty(t, w1.seed())
+			require.Empty(t, w1.lastSeed())
+
+			// The secrets are only decrypted by the new password, in the service and in the wallet file
+			w2, err := Load(fn)
+			require.NoError(t, err)
+			for _, w := range []*Wallet{w1, w2} {
+				_, err = w.Unlock(tc.password)
+				require.Equal(t, ErrInvalidPassword, err)
+
+				wlt, err := w.Unlock(tc.newPassword)
+				require.NoError(t, err)
+				require.Equal(t, tc.opts.Seed, wlt.seed())
+				require.Equal(t, w.Entries[0].Address, wlt.Entries[0].Address)
+			}
+
+			// The previous wallet file is kept as a backup, and no temporary file is left
+			bak, err := ioutil.ReadFile(fn + ".bak")
+			require.NoError(t, err)
+			require.Equal(t, data, bak)
+			testutil.RequireFileNotExists(t, fn+".tmp")
+		})
+	}
+}
+
 func TestServiceCreateWalletWithScan(t *testing.T) {
 	seed := "seed1"
 	addrs := make([]cipher.Address, 20)
@@ -73,6 +73,10 @@ func (w *Wallet) SignTransaction(txn *coin.Transaction, signIndexes []int, uxOut
 		return nil, ErrWalletEncrypted
 	}
 
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnlyWallet
+	}
+
 	if txnInnerHash != signedTxn.InnerHash {
 		return nil, NewError(errors.New("Transaction inner hash does not match computed inner hash"))
 	}
@@ -203,12 +207,30 @@ func (w *Wallet) CreateTransaction(p transaction.Params, auxs coin.AddressUxOuts
 // Set the password as nil if the wallet is not encrypted, otherwise the password must be provided.
 // Refer to CreateTransaction for information about transaction creation.
 func (w *Wallet) CreateTransactionSigned(p transaction.Params, auxs coin.AddressUxOuts, headTime uint64) (*coin.Transaction, []transaction.UxBalance, error) {
+	if w.IsWatchOnly() {
+		return nil, nil, ErrWatchOnlyWallet
+	}
+
 	txn, uxb, err := w.CreateTransaction(p, auxs, headTime)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Sign the transaction
+	if err := w.signCreatedTransaction(txn, uxb); err != nil {
+		return nil, nil, err
+	}
+
+	// Sanity check the signed transaction
+	if err := verifyCreatedSignedInvariants(p, txn, uxb); err != nil {
+		return nil, nil, err
+	}
+
+	return txn, uxb, nil
+}
+
+// signCreatedTransaction signs the inputs of a transaction created by the wallet
+func (w *Wallet) signCreatedTransaction(txn *coin.Transaction, uxb []transaction.UxBalance) error {
 	entriesMap := make(map[cipher.Address]Entry)
 	for i, s := range uxb {
 		entry, ok := entriesMap[s.Address]
@@ -218,23 +240,102 @@ func (w *Wallet) CreateTransactionSigned(p transaction.Params, auxs coin.Address
 				// This should not occur because CreateTransaction should have checked it already
 				err := fmt.Errorf("Chosen spend address %s not found in wallet", s.Address)
 				logger.Critical().WithError(err).Error()
-				return nil, nil, err
+				return err
 			}
 			entriesMap[s.Address] = entry
 		}
 
 		if err := txn.SignInput(entry.Secret, i); err != nil {
 			logger.Critical().WithError(err).Error("CreateTransaction SignInput failed")
-			return nil, nil, err
+			return err
 		}
 	}
 
-	// Sanity check the signed transaction
-	if err := verifyCreatedSignedInvariants(p, txn, uxb); err != nil {
-		return nil, nil, err
+	return nil
+}
+
+// CreateBatchTransaction creates unsigned transactions paying the receivers of transaction.Params,
+// each fitting within the transaction size limit. Refer to transaction.CreateBatch for information about the transactions.
+func (w *Wallet) CreateBatchTransaction(p transaction.Params, auxs coin.AddressUxOuts, headTime uint64) (*transaction.Batch, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
 	}
 
-	return txn, uxb, nil
+	// Check that auxs does not contain addresses that are not known to this wallet
+	for a := range auxs {
+		if !w.HasEntry(a) {
+			return nil, fmt.Errorf("Address %s from auxs not found in wallet", a)
+		}
+	}
+
+	return transaction.CreateBatch(p, auxs, headTime)
+}
+
+// CreateBatchTransactionSigned creates and signs transactions paying the receivers of transaction.Params.
+// Refer to CreateBatchTransaction for information about the transactions.
+func (w *Wallet) CreateBatchTransactionSigned(p transaction.Params, auxs coin.AddressUxOuts, headTime uint64) (*transaction.Batch, error) {
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnlyWallet
+	}
+
+	b, err := w.CreateBatchTransaction(p, auxs, headTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range b.Transactions {
+		if err := w.signCreatedTransaction(&b.Transactions[i], b.Inputs[i]); err != nil {
+			return nil, err
+		}
+
+		if !b.Transactions[i].IsFullySigned() {
+			return nil, errors.New("Transaction is not fully signed")
+		}
+	}
+
+	return b, nil
+}
+
+// CreateConsolidation creates unsigned transactions that merge the unspent outputs in auxs
+// into one address. Refer to transaction.Consolidate for information about the transactions.
+func (w *Wallet) CreateConsolidation(p transaction.ConsolidateParams, auxs coin.AddressUxOuts, headTime uint64) (*transaction.Consolidation, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Check that auxs does not contain addresses that are not known to this wallet
+	for a := range auxs {
+		if !w.HasEntry(a) {
+			return nil, fmt.Errorf("Address %s from auxs not found in wallet", a)
+		}
+	}
+
+	return transaction.Consolidate(p, auxs, headTime)
+}
+
+// CreateConsolidationSigned creates and signs transactions that merge the unspent outputs in auxs
+// into one address. Refer to CreateConsolidation for information about the transactions.
+func (w *Wallet) CreateConsolidationSigned(p transaction.ConsolidateParams, auxs coin.AddressUxOuts, headTime uint64) (*transaction.Consolidation, error) {
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnlyWallet
+	}
+
+	c, err := w.CreateConsolidation(p, auxs, headTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range c.Transactions {
+		if err := w.signCreatedTransaction(&c.Transactions[i], c.Inputs[i]); err != nil {
+			return nil, err
+		}
+
+		if !c.Transactions[i].IsFullySigned() {
+			return nil, errors.New("Transaction is not fully signed")
+		}
+	}
+
+	return c, nil
 }
 
 func verifyCreatedSignedInvariants(p transaction.Params, txn *coin.Transaction, inputs []transaction.UxBalance) error {
@@ -766,6 +766,146 @@ func makeTransaction(t *testing.T, nInputs int) (coin.Transaction, []coin.UxOut,
 	return txn, uxs, toSign
 }
 
+func TestWalletCreateConsolidationSigned(t *testing.T) {
+	headTime := uint64(time.Now().UTC().Unix())
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 3)
+
+	w := &Wallet{}
+	var uxouts []coin.UxOut
+	for _, s := range secKeys {
+		p := cipher.MustPubKeyFromSecKey(s)
+		err := w.AddEntry(Entry{
+			Address: cipher.AddressFromPubKey(p),
+			Public:  p,
+			Secret:  s,
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			uxout := makeUxOut(t, s, 1e6, 10)
+			uxout.Head.Time = headTime
+			uxouts = append(uxouts, uxout)
+		}
+	}
+
+	to := testutil.MakeAddress()
+	p := transaction.ConsolidateParams{
+		To:        to,
+		MaxInputs: 4,
+	}
+
+	c, err := w.CreateConsolidationSigned(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.NoError(t, err)
+	require.Len(t, c.Transactions, 3)
+
+	for i, txn := range c.Transactions {
+		require.True(t, txn.IsFullySigned())
+		require.NoError(t, txn.Verify())
+
+		uxIn := make(coin.UxArray, len(c.Inputs[i]))
+		for j, in := range c.Inputs[i] {
+			for _, ux := range uxouts {
+				if ux.Hash() == in.Hash {
+					uxIn[j] = ux
+				}
+			}
+		}
+		require.NoError(t, txn.VerifyInputSignatures(uxIn))
+	}
+
+	// Unsigned consolidation
+	c, err = w.CreateConsolidation(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.NoError(t, err)
+	for _, txn := range c.Transactions {
+		require.True(t, txn.IsFullyUnsigned())
+	}
+
+	// Outputs of addresses not in the wallet are rejected
+	_, unknownSecKey := cipher.GenerateKeyPair()
+	unknown := makeUxOut(t, unknownSecKey, 1e6, 10)
+	_, err = w.CreateConsolidationSigned(p, coin.NewAddressUxOuts(append(uxouts, unknown)), headTime)
+	require.Error(t, err)
+
+	// Watch-only wallets can't sign
+	w.Meta = map[string]string{metaType: WalletTypeAddresses}
+	_, err = w.CreateConsolidationSigned(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.Equal(t, ErrWatchOnlyWallet, err)
+}
+
+func TestWalletCreateBatchTransactionSigned(t *testing.T) {
+	headTime := uint64(time.Now().UTC().Unix())
+	_, secKeys := cipher.MustGenerateDeterministicKeyPairsSeed([]byte("seed"), 2)
+
+	w := &Wallet{}
+	var uxouts []coin.UxOut
+	for _, s := range secKeys {
+		p := cipher.MustPubKeyFromSecKey(s)
+		err := w.AddEntry(Entry{
+			Address: cipher.AddressFromPubKey(p),
+			Public:  p,
+			Secret:  s,
+		})
+		require.NoError(t, err)
+
+		uxout := makeUxOut(t, s, 10e6, 100)
+		uxout.Head.Time = headTime
+		uxouts = append(uxouts, uxout)
+	}
+
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   2e6,
+				Hours:   1,
+			},
+			{
+				Address: testutil.MakeAddress(),
+				Coins:   3e6,
+				Hours:   1,
+			},
+		},
+	}
+
+	b, err := w.CreateBatchTransactionSigned(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.NoError(t, err)
+	require.Len(t, b.Transactions, 1)
+	require.Equal(t, []int{0, 0}, b.Receivers)
+
+	txn := b.Transactions[0]
+	require.True(t, txn.IsFullySigned())
+	require.NoError(t, txn.Verify())
+
+	uxIn := make(coin.UxArray, len(b.Inputs[0]))
+	for j, in := range b.Inputs[0] {
+		for _, ux := range uxouts {
+			if ux.Hash() == in.Hash {
+				uxIn[j] = ux
+			}
+		}
+	}
+	require.NoError(t, txn.VerifyInputSignatures(uxIn))
+
+	// Unsigned batch
+	b, err = w.CreateBatchTransaction(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.NoError(t, err)
+	require.True(t, b.Transactions[0].IsFullyUnsigned())
+
+	// Outputs of addresses not in the wallet are rejected
+	_, unknownSecKey := cipher.GenerateKeyPair()
+	unknown := makeUxOut(t, unknownSecKey, 1e6, 10)
+	_, err = w.CreateBatchTransactionSigned(p, coin.NewAddressUxOuts(append(uxouts, unknown)), headTime)
+	require.Error(t, err)
+
+	// Watch-only wallets can't sign
+	w.Meta = map[string]string{metaType: WalletTypeAddresses}
+	_, err = w.CreateBatchTransactionSigned(p, coin.NewAddressUxOuts(uxouts), headTime)
+	require.Equal(t, ErrWatchOnlyWallet, err)
+}
+
 func makeUxOut(t *testing.T, s cipher.SecKey, coins, hours uint64) coin.UxOut { // nolint: unparam
 	body := makeUxBody(t, s, coins, hours)
 	tm := rand.Int31n(1000)
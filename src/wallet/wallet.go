@@ -16,6 +16,9 @@ import (
 	"encoding/hex"
 
 	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
 
 	"github.com/skycoin/skycoin/src/util/logging"
 )
@@ -76,6 +79,24 @@ var (
 	ErrWalletNotDeterministic = NewError(errors.New("wallet type is not deterministic"))
 	// ErrInvalidCoinType is returned for invalid coin types
 	ErrInvalidCoinType = NewError(errors.New("invalid coin type"))
+	// ErrInvalidWalletType is returned for invalid wallet types
+	ErrInvalidWalletType = NewError(errors.New("invalid wallet type"))
+	// ErrInvalidBip44Seed is returned if a bip44 wallet's seed is not a valid bip39 mnemonic
+	ErrInvalidBip44Seed = NewError(errors.New("bip44 wallet seed must be a valid bip39 mnemonic"))
+	// ErrWatchOnlyWallet is returned when trying to use secrets of a watch-only wallet, which has none
+	ErrWatchOnlyWallet = NewError(errors.New("wallet is watch-only"))
+	// ErrMissingXPub is returned when trying to create a xpub wallet without an extended public key
+	ErrMissingXPub = NewError(errors.New("missing xpub"))
+	// ErrMissingAddresses is returned when trying to create an address list wallet without addresses
+	ErrMissingAddresses = NewError(errors.New("missing addresses"))
+	// ErrWatchOnlyWalletSeed is returned when trying to create a watch-only wallet with a seed
+	ErrWatchOnlyWalletSeed = NewError(errors.New("watch-only wallets must not have a seed"))
+	// ErrCannotGenerateAddresses is returned when trying to generate addresses in an address list wallet
+	ErrCannotGenerateAddresses = NewError(errors.New("addresses cannot be generated for this wallet type"))
+	// ErrInvalidChangeAddressPolicy is returned for invalid change address policies
+	ErrInvalidChangeAddressPolicy = NewError(errors.New("invalid change address policy"))
+	// ErrSeedPassphraseNotSupported is returned when trying to create a wallet with a seed passphrase, but the wallet type is not bip44
+	ErrSeedPassphraseNotSupported = NewError(errors.New("seed passphrase is only supported by bip44 wallets"))
 )
 
 const (
@@ -92,6 +113,12 @@ const (
 
 	// WalletTypeDeterministic deterministic wallet type
 	WalletTypeDeterministic = "deterministic"
+	// WalletTypeBip44 hierarchical deterministic wallet type, derived along bip44 paths from a bip39 mnemonic
+	WalletTypeBip44 = "bip44"
+	// WalletTypeXPub watch-only wallet type, derived from an account level bip32 extended public key
+	WalletTypeXPub = "xpub"
+	// WalletTypeAddresses watch-only wallet type, consisting of a fixed list of addresses
+	WalletTypeAddresses = "addresses"
 )
 
 // ResolveCoinType normalizes a coin type string to a CoinType constant
@@ -119,6 +146,12 @@ const (
 	metaSeed       = "seed"       // wallet seed
 	metaLastSeed   = "lastSeed"   // seed for generating next address
 	metaSecrets    = "secrets"    // secrets which records the encrypted seeds and secrets of address entries
+	metaBip44Coin  = "bip44Coin"  // bip44 coin_type of a bip44 wallet
+	metaXPub       = "xpub"       // extended public key of a xpub wallet
+
+	metaSeedPassphrase = "seedPassphrase" // bip39 passphrase of a bip44 wallet seed
+
+	metaChangeAddressPolicy = "changeAddressPolicy" // how the change address of created transactions is chosen
 )
 
 // CoinType represents the wallet coin type
@@ -134,14 +167,20 @@ func NewWalletFilename() string {
 
 // Options options that could be used when creating a wallet
 type Options struct {
-	Coin       CoinType   // coin type, skycoin, bitcoin, etc.
-	Label      string     // wallet label.
-	Seed       string     // wallet seed.
-	Encrypt    bool       // whether the wallet need to be encrypted.
-	Password   []byte     // password that would be used for encryption, and would only be used when 'Encrypt' is true.
-	CryptoType CryptoType // wallet encryption type, scrypt-chacha20poly1305 or sha256-xor.
-	ScanN      uint64     // number of addresses that're going to be scanned for a balance. The highest address with a balance will be used.
-	GenerateN  uint64     // number of addresses to generate, regardless of balance
+	Coin       CoinType           // coin type, skycoin, bitcoin, etc.
+	Type       string             // wallet type, deterministic, bip44, xpub or addresses. Defaults to deterministic.
+	Label      string             // wallet label.
+	Seed       string             // wallet seed.
+	XPub       string             // extended public key of a xpub wallet.
+	Addresses  []cipher.Addresser // addresses of an address list wallet.
+	Encrypt    bool               // whether the wallet need to be encrypted.
+	Password   []byte             // password that would be used for encryption, and would only be used when 'Encrypt' is true.
+	CryptoType CryptoType         // wallet encryption type, scrypt-chacha20poly1305, argon2id-xchacha20poly1305 or sha256-xor.
+	ScanN      uint64             // number of addresses that're going to be scanned for a balance. The highest address with a balance will be used.
+	GenerateN  uint64             // number of addresses to generate, regardless of balance
+
+	ChangeAddressPolicy string // change address policy, reuse or fresh. Defaults to reuse.
+	SeedPassphrase      string // bip39 passphrase of the seed, only for bip44 wallets. Optional, the addresses depend on it.
 }
 
 // Wallet is consisted of meta and entries.
@@ -149,18 +188,18 @@ type Options struct {
 // filename, lable, wallet type, secrets, etc.
 // Entries field stores the address entries that are deterministically generated
 // from seed.
+// FrozenOutputs field records the unspent outputs that are never chosen to spend.
+// AddressBook field records named external addresses to send coins to.
 // For wallet encryption
 type Wallet struct {
-	Meta    map[string]string
-	Entries []Entry
+	Meta          map[string]string
+	Entries       []Entry
+	FrozenOutputs []FrozenOutput
+	AddressBook   []AddressBookEntry
 }
 
 // newWallet creates a wallet instance with given name and options.
 func newWallet(wltName string, opts Options, bg BalanceGetter) (*Wallet, error) {
-	if opts.Seed == "" {
-		return nil, ErrMissingSeed
-	}
-
 	if opts.ScanN > 0 && bg == nil {
 		return nil, ErrNilBalanceGetter
 	}
@@ -176,6 +215,52 @@ func newWallet(wltName string, opts Options, bg BalanceGetter) (*Wallet, error)
 		return nil, fmt.Errorf("Invalid coin type %q", coin)
 	}
 
+	walletType := opts.Type
+	if walletType == "" {
+		walletType = WalletTypeDeterministic
+	}
+
+	switch walletType {
+	case WalletTypeDeterministic, WalletTypeBip44:
+		if opts.Seed == "" {
+			return nil, ErrMissingSeed
+		}
+		if walletType == WalletTypeBip44 {
+			if _, err := bip39.MnemonicToByteArray(opts.Seed); err != nil {
+				return nil, ErrInvalidBip44Seed
+			}
+		}
+	case WalletTypeXPub:
+		if opts.Seed != "" {
+			return nil, ErrWatchOnlyWalletSeed
+		}
+		if opts.XPub == "" {
+			return nil, ErrMissingXPub
+		}
+		if _, err := bip32.DeserializeEncodedPublicKey(opts.XPub); err != nil {
+			return nil, NewError(fmt.Errorf("invalid xpub: %v", err))
+		}
+	case WalletTypeAddresses:
+		if opts.Seed != "" {
+			return nil, ErrWatchOnlyWalletSeed
+		}
+		if len(opts.Addresses) == 0 {
+			return nil, ErrMissingAddresses
+		}
+	default:
+		return nil, ErrInvalidWalletType
+	}
+
+	if opts.SeedPassphrase != "" && walletType != WalletTypeBip44 {
+		return nil, ErrSeedPassphraseNotSupported
+	}
+
+	if opts.ChangeAddressPolicy != "" {
+		if err := validateChangeAddressPolicy(walletType, opts.ChangeAddressPolicy); err != nil {
+			return nil, err
+		}
+	}
+
 	w := &Wallet{
 		Meta: map[string]string{
 			metaFilename:   wltName,
@@ -184,7 +269,7 @@ func newWallet(wltName string, opts Options, bg BalanceGetter) (*Wallet, error)
 			metaSeed:       opts.Seed,
 			metaLastSeed:   opts.Seed,
 			metaTimestamp:  strconv.FormatInt(time.Now().Unix(), 10),
-			metaType:       WalletTypeDeterministic,
+			metaType:       walletType,
 			metaCoin:       string(coin),
 			metaEncrypted:  "false",
 			metaCryptoType: "",
@@ -192,13 +277,35 @@ func newWallet(wltName string, opts Options, bg BalanceGetter) (*Wallet, error)
 		},
 	}
 
+	switch walletType {
+	case WalletTypeBip44:
+		// bip44 wallets derive every address from the seed, there is no lastSeed
+		w.setLastSeed("")
+		w.Meta[metaBip44Coin] = strconv.FormatUint(uint64(bip44CoinType(coin)), 10)
+		if opts.SeedPassphrase != "" {
+			w.setSeedPassphrase(opts.SeedPassphrase)
+		}
+	case WalletTypeXPub:
+		w.Meta[metaXPub] = opts.XPub
+	case WalletTypeAddresses:
+		if err := w.addWatchAddresses(opts.Addresses); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.ChangeAddressPolicy != "" {
+		w.Meta[metaChangeAddressPolicy] = opts.ChangeAddressPolicy
+	}
+
 	// Create a default wallet
 	generateN := opts.GenerateN
 	if generateN == 0 {
 		generateN = 1
 	}
-	if _, err := w.GenerateAddresses(generateN); err != nil {
-		return nil, err
+	if walletType != WalletTypeAddresses {
+		if _, err := w.GenerateAddresses(generateN); err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.ScanN != 0 && coin != CoinTypeSkycoin {
@@ -220,6 +327,11 @@ func newWallet(wltName string, opts Options, bg BalanceGetter) (*Wallet, error)
 		return w, nil
 	}
 
+	// Watch-only wallets have no secrets to encrypt
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnlyWallet
+	}
+
 	// Checks if the password is provided
 	if len(opts.Password) == 0 {
 		return nil, ErrMissingPassword
@@ -263,6 +375,10 @@ func (w *Wallet) Lock(password []byte, cryptoType CryptoType) error {
 		return ErrWalletEncrypted
 	}
 
+	if w.IsWatchOnly() {
+		return ErrWatchOnlyWallet
+	}
+
 	wlt := w.clone()
 
 	// Records seeds in secrets
@@ -275,6 +391,9 @@ func (w *Wallet) Lock(password []byte, cryptoType CryptoType) error {
 
 	ss.set(secretSeed, wlt.seed())
 	ss.set(secretLastSeed, wlt.lastSeed())
+	if p := wlt.seedPassphrase(); p != "" {
+		ss.set(secretSeedPassphrase, p)
+	}
 
 	// Saves address's secret keys in secrets
 	for _, e := range wlt.Entries {
@@ -376,6 +495,11 @@ func (w *Wallet) Unlock(password []byte) (*Wallet, error) {
 	}
 	wlt.setLastSeed(lastSeed)
 
+	// Wallets without a seed passphrase have none in their secrets
+	if p, ok := ss.get(secretSeedPassphrase); ok {
+		wlt.setSeedPassphrase(p)
+	}
+
 	// Gets addresses related secrets
 	for i, e := range wlt.Entries {
 		sstr, ok := ss.get(e.Address.String())
@@ -409,13 +533,26 @@ func (w *Wallet) copyFrom(src *Wallet) {
 
 	// Copies the address entries
 	w.Entries = append(w.Entries, src.Entries...)
+
+	// Copies the frozen outputs
+	w.FrozenOutputs = nil
+	if len(src.FrozenOutputs) != 0 {
+		w.FrozenOutputs = append(w.FrozenOutputs, src.FrozenOutputs...)
+	}
+
+	// Copies the address book
+	w.AddressBook = nil
+	if len(src.AddressBook) != 0 {
+		w.AddressBook = append(w.AddressBook, src.AddressBook...)
+	}
 }
 
 // Erase wipes secret fields in wallet
 func (w *Wallet) Erase() {
-	// Wipes the seed and last seed
+	// Wipes the seed, last seed and seed passphrase
 	w.setSeed("")
 	w.setLastSeed("")
+	delete(w.Meta, metaSeedPassphrase)
 
 	// Wipes private keys in entries
 	for i := range w.Entries {
@@ -427,6 +564,43 @@ func (w *Wallet) Erase() {
 	}
 }
 
+// ChangePassword re-encrypts the secrets of the wallet with a new password and crypto type.
+// The crypto type of the wallet is kept if cryptoType is empty.
+// The wallet is decrypted in memory only, and is left unchanged if it fails.
+func (w *Wallet) ChangePassword(password, newPassword []byte, cryptoType CryptoType) error {
+	if !w.IsEncrypted() {
+		return ErrWalletNotEncrypted
+	}
+
+	if len(password) == 0 || len(newPassword) == 0 {
+		return ErrMissingPassword
+	}
+
+	if cryptoType == "" {
+		cryptoType = w.cryptoType()
+	}
+
+	if _, err := getCrypto(cryptoType); err != nil {
+		return err
+	}
+
+	wlt, err := w.Unlock(password)
+	if err != nil {
+		return err
+	}
+
+	defer wlt.Erase()
+
+	if err := wlt.Lock(newPassword, cryptoType); err != nil {
+		return err
+	}
+
+	*w = *wlt
+	// Wipes all sensitive data
+	w.Erase()
+	return nil
+}
+
 // GuardUpdate executes a function within the context of a read-write managed decrypted wallet.
 // Returns ErrWalletNotEncrypted if wallet is not encrypted.
 func (w *Wallet) GuardUpdate(password []byte, fn func(w *Wallet) error) error {
@@ -503,6 +677,13 @@ func (w *Wallet) Save(dir string) error {
 	return r.Save(filepath.Join(dir, w.Filename()))
 }
 
+// SaveAtomic replaces the wallet file in the given dir atomically,
+// keeping the previous wallet file as a backup with the ".bak" extension
+func (w *Wallet) SaveAtomic(dir string) error {
+	r := NewReadableWallet(w)
+	return r.SaveAtomic(filepath.Join(dir, w.Filename()))
+}
+
 // removeBackupFiles removes any *.wlt.bak files whom have version 0.1 and *.wlt matched in the given directory
 func removeBackupFiles(dir string) error {
 	fs, err := filterDir(dir, ".wlt")
@@ -580,7 +761,18 @@ func (w *Wallet) Validate() error {
 	if !ok {
 		return errors.New("type field not set")
 	}
-	if walletType != WalletTypeDeterministic {
+	switch walletType {
+	case WalletTypeDeterministic:
+	case WalletTypeBip44:
+		if _, err := strconv.ParseUint(w.Meta[metaBip44Coin], 10, 32); err != nil {
+			return errors.New("bip44Coin field not set or invalid")
+		}
+	case WalletTypeXPub:
+		if _, err := bip32.DeserializeEncodedPublicKey(w.Meta[metaXPub]); err != nil {
+			return errors.New("xpub field not set or invalid")
+		}
+	case WalletTypeAddresses:
+	default:
 		return errors.New("wallet type invalid")
 	}
 
@@ -588,6 +780,16 @@ func (w *Wallet) Validate() error {
 		return errors.New("coin field not set")
 	}
 
+	if p := w.Meta[metaSeedPassphrase]; p != "" && walletType != WalletTypeBip44 {
+		return errors.New("seedPassphrase field is only valid for bip44 wallets")
+	}
+
+	if policy := w.Meta[metaChangeAddressPolicy]; policy != "" {
+		if err := validateChangeAddressPolicy(walletType, policy); err != nil {
+			return errors.New("changeAddressPolicy field invalid")
+		}
+	}
+
 	var isEncrypted bool
 	if encStr, ok := w.Meta[metaEncrypted]; ok {
 		// validate the encrypted value
@@ -599,7 +801,9 @@ func (w *Wallet) Validate() error {
 	}
 
 	// checks if the secrets field is empty
-	if isEncrypted {
+	if isEncrypted && w.IsWatchOnly() {
+		return errors.New("watch-only wallet can't be encrypted")
+	} else if isEncrypted {
 		cryptoType, ok := w.Meta[metaCryptoType]
 		if !ok {
 			return errors.New("crypto type field not set")
@@ -612,12 +816,12 @@ func (w *Wallet) Validate() error {
 		if s := w.Meta[metaSecrets]; s == "" {
 			return errors.New("wallet is encrypted, but secrets field not set")
 		}
-	} else {
+	} else if !w.IsWatchOnly() {
 		if s := w.Meta[metaSeed]; s == "" {
 			return errors.New("seed missing in unencrypted wallet")
 		}
 
-		if s := w.Meta[metaLastSeed]; s == "" {
+		if s := w.Meta[metaLastSeed]; s == "" && walletType == WalletTypeDeterministic {
 			return errors.New("lastSeed missing in unencrypted wallet")
 		}
 	}
@@ -630,6 +834,16 @@ func (w *Wallet) Type() string {
 	return w.Meta[metaType]
 }
 
+// IsWatchOnly returns true if the wallet has no secrets and only tracks addresses
+func (w *Wallet) IsWatchOnly() bool {
+	switch w.Type() {
+	case WalletTypeXPub, WalletTypeAddresses:
+		return true
+	default:
+		return false
+	}
+}
+
 // Version gets the wallet version
 func (w *Wallet) Version() string {
 	return w.Meta[metaVersion]
@@ -676,6 +890,15 @@ func (w *Wallet) setSeed(seed string) {
 	w.Meta[metaSeed] = seed
 }
 
+// seedPassphrase returns the bip39 passphrase of the seed, empty if the wallet has none
+func (w *Wallet) seedPassphrase() string {
+	return w.Meta[metaSeedPassphrase]
+}
+
+func (w *Wallet) setSeedPassphrase(passphrase string) {
+	w.Meta[metaSeedPassphrase] = passphrase
+}
+
 func (w *Wallet) coin() CoinType {
 	return CoinType(w.Meta[metaCoin])
 }
@@ -756,6 +979,15 @@ func (w *Wallet) GenerateAddresses(num uint64) ([]cipher.Addresser, error) {
 		return nil, ErrWalletEncrypted
 	}
 
+	switch w.Type() {
+	case WalletTypeBip44:
+		return w.generateBip44Addresses(bip44.ExternalChainIndex, num)
+	case WalletTypeXPub:
+		return w.generateXPubAddresses(bip44.ExternalChainIndex, num)
+	case WalletTypeAddresses:
+		return nil, ErrCannotGenerateAddresses
+	}
+
 	var seckeys []cipher.SecKey
 	var seed []byte
 	if len(w.Entries) == 0 {
@@ -859,11 +1091,24 @@ func (w *Wallet) ScanAddresses(scanN uint64, bg BalanceGetter) (uint64, error) {
 		n = scanN - extraScan
 	}
 
-	// Regenerate addresses up to nExistingAddrs + nAddAddrss.
-	// This is necessary to keep the lastSeed updated.
-	w2.reset()
-	if _, err := w2.GenerateSkycoinAddresses(nExistingAddrs + nAddAddrs); err != nil {
-		return 0, err
+	switch w2.Type() {
+	case WalletTypeBip44, WalletTypeXPub:
+		// bip44 and xpub addresses are derived by index, so the scanned addresses can be truncated
+		// instead of regenerated
+		w2.Entries = w2.Entries[:nExistingAddrs+nAddAddrs]
+	default:
+		// Regenerate addresses up to nExistingAddrs + nAddAddrss.
+		// This is necessary to keep the lastSeed updated.
+		// The change addresses and labels are not known from the seed, so they are restored afterwards.
+		w2.reset()
+		if _, err := w2.GenerateSkycoinAddresses(nExistingAddrs + nAddAddrs); err != nil {
+			return 0, err
+		}
+
+		for i, e := range w.Entries {
+			w2.Entries[i].Change = e.Change
+			w2.Entries[i].Label = e.Label
+		}
 	}
 
 	*w = *w2
@@ -939,5 +1184,13 @@ func (w *Wallet) clone() *Wallet {
 
 	wlt.Entries = append(wlt.Entries, w.Entries...)
 
+	if len(w.FrozenOutputs) != 0 {
+		wlt.FrozenOutputs = append(wlt.FrozenOutputs, w.FrozenOutputs...)
+	}
+
+	if len(w.AddressBook) != 0 {
+		wlt.AddressBook = append(wlt.AddressBook, w.AddressBook...)
+	}
+
 	return &wlt
 }
@@ -226,6 +226,92 @@ func TestNewWallet(t *testing.T) {
 				err: ErrMissingSeed,
 			},
 		},
+		{
+			"ok bip44",
+			"test.wlt",
+			Options{
+				Type: WalletTypeBip44,
+				Seed: testBip44Seed,
+			},
+			expect{
+				meta: map[string]string{
+					"label":     "",
+					"filename":  "test.wlt",
+					"coin":      string(CoinTypeSkycoin),
+					"type":      WalletTypeBip44,
+					"seed":      testBip44Seed,
+					"bip44Coin": "8000",
+					"version":   Version,
+				},
+				err: nil,
+			},
+		},
+		{
+			"bip44 seed is not a mnemonic",
+			"test.wlt",
+			Options{
+				Type: WalletTypeBip44,
+				Seed: "testseed123",
+			},
+			expect{
+				err: ErrInvalidBip44Seed,
+			},
+		},
+		{
+			"bip44 seed checksum is incorrect",
+			"test.wlt",
+			Options{
+				Type: WalletTypeBip44,
+				Seed: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			},
+			expect{
+				err: ErrInvalidBip44Seed,
+			},
+		},
+		{
+			"ok bip44 seed passphrase",
+			"test.wlt",
+			Options{
+				Type:           WalletTypeBip44,
+				Seed:           testBip44Seed,
+				SeedPassphrase: "TREZOR",
+			},
+			expect{
+				meta: map[string]string{
+					"label":          "",
+					"filename":       "test.wlt",
+					"coin":           string(CoinTypeSkycoin),
+					"type":           WalletTypeBip44,
+					"seed":           testBip44Seed,
+					"seedPassphrase": "TREZOR",
+					"bip44Coin":      "8000",
+					"version":        Version,
+				},
+				err: nil,
+			},
+		},
+		{
+			"seed passphrase of deterministic wallet",
+			"test.wlt",
+			Options{
+				Seed:           "testseed123",
+				SeedPassphrase: "TREZOR",
+			},
+			expect{
+				err: ErrSeedPassphraseNotSupported,
+			},
+		},
+		{
+			"invalid wallet type",
+			"test.wlt",
+			Options{
+				Type: "foo",
+				Seed: "testseed123",
+			},
+			expect{
+				err: ErrInvalidWalletType,
+			},
+		},
 		{
 			"password=pwd encrypt=false",
 			"test.wlt",
@@ -54,6 +54,19 @@ func loadWallet(fn string) (*Wallet, error) {
 		return nil, err
 	}
 
+	w, err := readableToSkycoinWallet(rw, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Loaded wallet from %s", fn)
+	w.setFilename(filepath.Base(fn))
+
+	return w, nil
+}
+
+// readableToSkycoinWallet converts a ReadableWallet loaded from fn to a skycoin Wallet
+func readableToSkycoinWallet(rw *ReadableWallet, fn string) (*Wallet, error) {
 	// Normalize coin types (older wallets used different names for the coin type)
 	switch strings.ToLower(rw.Meta[metaCoin]) {
 	case "sky", "skycoin":
@@ -72,9 +85,6 @@ func loadWallet(fn string) (*Wallet, error) {
 		return nil, fmt.Errorf("LoadWallets only support skycoin wallets, %s is a %s wallet", fn, coinType)
 	}
 
-	logger.Infof("Loaded wallet from %s", fn)
-	w.setFilename(filepath.Base(fn))
-
 	return w, nil
 }
 
@@ -0,0 +1,105 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip32"
+)
+
+// xpub returns the extended public key recorded in the wallet meta
+func (w *Wallet) xpub() (*bip32.PublicKey, error) {
+	return bip32.DeserializeEncodedPublicKey(w.Meta[metaXPub])
+}
+
+// generateXPubAddresses generates num addresses on the given chain of the wallet's
+// account level extended public key, continuing after the last address generated on that chain.
+// The addresses match those of a bip44 wallet whose account xpub was exported.
+func (w *Wallet) generateXPubAddresses(chain uint32, num uint64) ([]cipher.Addresser, error) {
+	if num == 0 {
+		return nil, nil
+	}
+
+	xpub, err := w.xpub()
+	if err != nil {
+		return nil, err
+	}
+
+	chainKey, err := xpub.NewPublicChildKey(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]cipher.Addresser, 0, num)
+	makeAddress := w.addressConstructor()
+	for i := w.bip44NextChildNumber(chain); uint64(len(addrs)) < num; i++ {
+		if i >= bip32.FirstHardenedChild {
+			return nil, errBip44ChainExhausted
+		}
+
+		k, err := chainKey.NewPublicChildKey(i)
+		if err == bip32.ErrInvalidPrivateKey || err == bip32.ErrInvalidPublicKey {
+			// The key of this child number is invalid, proceed with the next one, as bip44 wallets do
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		p, err := k.PubKey()
+		if err != nil {
+			return nil, err
+		}
+
+		a := makeAddress(p)
+		addrs = append(addrs, a)
+		w.Entries = append(w.Entries, Entry{
+			Address:     a,
+			Public:      p,
+			ChildNumber: k.ChildNumber,
+			Change:      chain,
+		})
+	}
+
+	return addrs, nil
+}
+
+// validateCoinAddress checks that an address is a non-null address of the wallet's coin type
+func (w *Wallet) validateCoinAddress(a cipher.Addresser) error {
+	var ok bool
+	switch w.coin() {
+	case CoinTypeSkycoin:
+		_, ok = a.(cipher.Address)
+	case CoinTypeBitcoin:
+		_, ok = a.(cipher.BitcoinAddress)
+	}
+	if !ok || a.Null() {
+		return NewError(fmt.Errorf("invalid %s address %q", w.coin(), a))
+	}
+	return nil
+}
+
+// addWatchAddresses adds address-only entries to an address list wallet.
+// The addresses must match the wallet's coin type and must not be duplicated.
+func (w *Wallet) addWatchAddresses(addrs []cipher.Addresser) error {
+	seen := make(map[string]struct{}, len(w.Entries)+len(addrs))
+	for _, e := range w.Entries {
+		seen[e.Address.String()] = struct{}{}
+	}
+
+	for _, a := range addrs {
+		if err := w.validateCoinAddress(a); err != nil {
+			return err
+		}
+
+		if _, ok := seen[a.String()]; ok {
+			return NewError(fmt.Errorf("duplicate address %s", a))
+		}
+		seen[a.String()] = struct{}{}
+
+		w.Entries = append(w.Entries, Entry{
+			Address: a,
+		})
+	}
+
+	return nil
+}
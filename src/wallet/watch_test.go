@@ -0,0 +1,237 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/bip44"
+	bip39 "github.com/skycoin/skycoin/src/cipher/go-bip39"
+	"github.com/skycoin/skycoin/src/coin"
+	"github.com/skycoin/skycoin/src/testutil"
+	"github.com/skycoin/skycoin/src/transaction"
+)
+
+func testAccountXPub(t *testing.T, coinType bip44.CoinType) string {
+	c, err := bip44.NewCoin(bip39.NewSeed(testBip44Seed, ""), coinType)
+	require.NoError(t, err)
+	acct, err := c.Account(0)
+	require.NoError(t, err)
+	return acct.PublicKey().String()
+}
+
+func TestNewXPubWallet(t *testing.T) {
+	xpub := testAccountXPub(t, bip44.CoinTypeSkycoin)
+
+	// The xpub wallet has the same external addresses as the bip44 wallet of the same account
+	bw, err := NewWallet("b.wlt", Options{
+		Type:      WalletTypeBip44,
+		Seed:      testBip44Seed,
+		GenerateN: 3,
+	})
+	require.NoError(t, err)
+
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeXPub,
+		XPub:      xpub,
+		GenerateN: 2,
+	})
+	require.NoError(t, err)
+	require.True(t, w.IsWatchOnly())
+	require.NoError(t, w.Validate())
+	require.Equal(t, "", w.seed())
+	require.Equal(t, xpub, w.Meta[metaXPub])
+	require.Len(t, w.Entries, 2)
+
+	_, err = w.GenerateAddresses(1)
+	require.NoError(t, err)
+	require.Len(t, w.Entries, 3)
+
+	for i, e := range w.Entries {
+		require.True(t, e.Secret.Null())
+		require.Equal(t, bw.Entries[i].Address, e.Address)
+		require.Equal(t, bw.Entries[i].Public, e.Public)
+		require.Equal(t, uint32(i), e.ChildNumber)
+		require.Equal(t, bip44.ExternalChainIndex, e.Change)
+	}
+
+	// Readable round trip
+	rw := NewReadableWallet(w)
+	require.NotNil(t, rw.Entries[2].ChildNumber)
+	require.Equal(t, uint32(2), *rw.Entries[2].ChildNumber)
+	w2, err := rw.ToWallet()
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, w2.Entries)
+
+	// Watch-only wallets can't be encrypted
+	require.Equal(t, ErrWatchOnlyWallet, w.Lock([]byte("pwd"), CryptoTypeSha256Xor))
+}
+
+func TestNewXPubWalletInvalid(t *testing.T) {
+	xpub := testAccountXPub(t, bip44.CoinTypeSkycoin)
+
+	_, err := NewWallet("t.wlt", Options{
+		Type: WalletTypeXPub,
+	})
+	require.Equal(t, ErrMissingXPub, err)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type: WalletTypeXPub,
+		XPub: xpub,
+		Seed: "seed",
+	})
+	require.Equal(t, ErrWatchOnlyWalletSeed, err)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type:       WalletTypeXPub,
+		XPub:       xpub,
+		Encrypt:    true,
+		Password:   []byte("pwd"),
+		CryptoType: CryptoTypeSha256Xor,
+	})
+	require.Equal(t, ErrWatchOnlyWallet, err)
+
+	// A private extended key is not accepted
+	c, err := bip44.NewCoin(bip39.NewSeed(testBip44Seed, ""), bip44.CoinTypeSkycoin)
+	require.NoError(t, err)
+	_, err = NewWallet("t.wlt", Options{
+		Type: WalletTypeXPub,
+		XPub: c.String(),
+	})
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+}
+
+func TestNewAddressesWallet(t *testing.T) {
+	addrs := []cipher.Addresser{
+		testutil.MakeAddress(),
+		testutil.MakeAddress(),
+	}
+
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeAddresses,
+		Addresses: addrs,
+	})
+	require.NoError(t, err)
+	require.True(t, w.IsWatchOnly())
+	require.NoError(t, w.Validate())
+	require.Equal(t, addrs, w.GetAddresses())
+	for _, e := range w.Entries {
+		require.True(t, e.Public.Null())
+		require.True(t, e.Secret.Null())
+	}
+
+	_, err = w.GenerateAddresses(1)
+	require.Equal(t, ErrCannotGenerateAddresses, err)
+
+	// Readable round trip, entries have no public key
+	rw := NewReadableWallet(w)
+	require.Equal(t, "", rw.Entries[0].Public)
+	require.Nil(t, rw.Entries[0].ChildNumber)
+	w2, err := rw.ToWallet()
+	require.NoError(t, err)
+	require.Equal(t, w.Entries, w2.Entries)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type: WalletTypeAddresses,
+	})
+	require.Equal(t, ErrMissingAddresses, err)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type:      WalletTypeAddresses,
+		Addresses: []cipher.Addresser{addrs[0], addrs[0]},
+	})
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+
+	_, err = NewWallet("t.wlt", Options{
+		Type:      WalletTypeAddresses,
+		Coin:      CoinTypeBitcoin,
+		Addresses: addrs,
+	})
+	require.Error(t, err)
+	require.IsType(t, Error{}, err)
+}
+
+func TestWatchOnlyWalletTransactions(t *testing.T) {
+	headTime := uint64(time.Now().UTC().Unix())
+	uxout, s := makeUxOutWithSecret(t)
+	uxout.Head.Time = headTime
+	addr := cipher.MustAddressFromSecKey(s)
+
+	w, err := NewWallet("t.wlt", Options{
+		Type:      WalletTypeAddresses,
+		Addresses: []cipher.Addresser{addr},
+	})
+	require.NoError(t, err)
+
+	changeAddress := testutil.MakeAddress()
+	p := transaction.Params{
+		HoursSelection: transaction.HoursSelection{
+			Type: transaction.HoursSelectionTypeManual,
+		},
+		ChangeAddress: &changeAddress,
+		To: []coin.TransactionOutput{
+			{
+				Address: testutil.MakeAddress(),
+				Hours:   1,
+				Coins:   uxout.Body.Coins / 2,
+			},
+		},
+	}
+	auxs := coin.AddressUxOuts{
+		addr: []coin.UxOut{uxout},
+	}
+
+	// Unsigned transactions can be created
+	txn, _, err := w.CreateTransaction(p, auxs, headTime)
+	require.NoError(t, err)
+	require.False(t, txn.IsFullySigned())
+
+	// Signing is refused
+	_, _, err = w.CreateTransactionSigned(p, auxs, headTime)
+	require.Equal(t, ErrWatchOnlyWallet, err)
+
+	_, err = w.SignTransaction(txn, nil, []coin.UxOut{uxout})
+	require.Equal(t, ErrWatchOnlyWallet, err)
+}
+
+func TestServiceWatchOnlyWallet(t *testing.T) {
+	s, err := NewService(Config{
+		WalletDir:       prepareWltDir(),
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+		EnableSeedAPI:   true,
+	})
+	require.NoError(t, err)
+
+	w, err := s.CreateWallet("t.wlt", Options{
+		Type: WalletTypeXPub,
+		XPub: testAccountXPub(t, bip44.CoinTypeSkycoin),
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = s.GetWalletSeed(w.Filename(), []byte("pwd"))
+	require.Equal(t, ErrWatchOnlyWallet, err)
+
+	_, err = s.EncryptWallet(w.Filename(), []byte("pwd"))
+	require.Equal(t, ErrWatchOnlyWallet, err)
+
+	addrs, err := s.NewAddresses(w.Filename(), nil, 2)
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+
+	// The wallet is saved and reloaded with its entries
+	s2, err := NewService(Config{
+		WalletDir:       s.walletDirectory,
+		CryptoType:      CryptoTypeSha256Xor,
+		EnableWalletAPI: true,
+	})
+	require.NoError(t, err)
+	w2, err := s2.GetWallet(w.Filename())
+	require.NoError(t, err)
+	require.Len(t, w2.Entries, 3)
+	require.True(t, w2.IsWatchOnly())
+}